@@ -0,0 +1,615 @@
+package tview
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gdamore/tcell"
+)
+
+// accordionSection is one section of an Accordion.
+type accordionSection struct {
+	Title    string    // The text shown in the section's header.
+	Item     Primitive // The section's content.
+	Height   int       // The content's height if > 0, otherwise it shares the remaining space.
+	Expanded bool      // Whether or not the content is shown.
+
+	// The time at which the section was last expanded or collapsed and how
+	// far it was expanded at that time (0 to 1). Used for animations.
+	toggled  time.Time
+	fraction float64
+
+	// The row of the section's header the last time it was drawn, -1 if it
+	// was not visible.
+	headerY int
+}
+
+// Accordion is a container of vertically stacked sections. Each section has a
+// one-line header with a title and a primitive which is only shown when the
+// section is expanded. Sections with a fixed height (see AddSection()) take up
+// that height when expanded, the others share the remaining space.
+//
+// By default, only one section can be expanded at a time, expanding a section
+// collapses the others. Call SetMultiOpen() to allow any number of expanded
+// sections. Expanding and collapsing can be animated (see SetAnimation()).
+//
+// The section headers receive focus when the current section is collapsed, when
+// the user clicks on a header (if mouse events are enabled), or when
+// FocusHeaders() is called. When the accordion receives focus otherwise, it is
+// passed on to the current section's primitive. The headers are navigated
+// with the following keys when they have focus:
+//
+//   - Up arrow, k: Select the previous section.
+//   - Down arrow, j: Select the next section.
+//   - Home, g: Select the first section.
+//   - End, G: Select the last section.
+//   - Enter, Space: Expand or collapse the current section.
+//   - Right arrow, l: Expand the current section or, if it is already
+//     expanded, move the focus to its primitive.
+//   - Left arrow, h: Collapse the current section.
+type Accordion struct {
+	*Box
+
+	// The sections.
+	sections []*accordionSection
+
+	// The index of the current section, -1 if there are no sections.
+	currentSection int
+
+	// Whether or not multiple sections may be expanded at the same time.
+	multiOpen bool
+
+	// Whether or not the headers should receive focus when this primitive
+	// receives focus.
+	focusHeaders bool
+
+	// We keep a reference to the function which allows us to set the focus to
+	// a section's primitive.
+	setFocus func(p Primitive)
+
+	// The duration of the expand/collapse animation, 0 for no animation.
+	animationDuration time.Duration
+
+	// The function which is called repeatedly during an animation to redraw
+	// the screen.
+	redraw func()
+
+	// The header's text and background colors.
+	headerTextColor, headerBackgroundColor tcell.Color
+
+	// The text and background colors of the current section's header when
+	// the headers have focus.
+	currentHeaderTextColor, currentHeaderBackgroundColor tcell.Color
+
+	// An optional function which is called when a section is expanded or
+	// collapsed.
+	changed func(index int, expanded bool)
+
+	// An optional function which is called when the user leaves the headers.
+	done func(tcell.Key)
+}
+
+// accordionState is the state of an Accordion saved with SaveState().
+type accordionState struct {
+	Expanded []bool `json:"expanded"`
+}
+
+// NewAccordion returns a new accordion without any sections.
+func NewAccordion() *Accordion {
+	styles := Styles.forWidget("Accordion")
+	a := &Accordion{
+		Box:                          NewBox(),
+		currentSection:               -1,
+		headerTextColor:              styles.PrimaryTextColor,
+		headerBackgroundColor:        styles.ContrastBackgroundColor,
+		currentHeaderTextColor:       styles.InverseTextColor,
+		currentHeaderBackgroundColor: styles.PrimaryTextColor,
+	}
+	a.focus = a
+	return a
+}
+
+// AddSection adds a new section with the given title and primitive at the end
+// of the accordion. If "height" is greater than 0, the primitive has that
+// height when the section is expanded. Otherwise, it shares the space not
+// claimed by other sections with all other expanded sections whose height is
+// 0. If "expanded" is true, the section is expanded (and, unless multiple
+// expanded sections are allowed, all other sections are collapsed) and becomes
+// the current section.
+func (a *Accordion) AddSection(title string, item Primitive, height int, expanded bool) *Accordion {
+	a.sections = append(a.sections, &accordionSection{
+		Title:   title,
+		Item:    item,
+		Height:  height,
+		headerY: -1,
+	})
+	if a.currentSection < 0 {
+		a.currentSection = 0
+	}
+	if expanded {
+		a.currentSection = len(a.sections) - 1
+		a.setExpanded(a.currentSection, true)
+	}
+	return a
+}
+
+// RemoveSection removes the section with the given index.
+func (a *Accordion) RemoveSection(index int) *Accordion {
+	if index < 0 || index >= len(a.sections) {
+		return a
+	}
+	a.sections = append(a.sections[:index], a.sections[index+1:]...)
+	if a.currentSection >= len(a.sections) || a.currentSection > index {
+		a.currentSection--
+	}
+	if a.currentSection < 0 && len(a.sections) > 0 {
+		a.currentSection = 0
+	}
+	return a
+}
+
+// Clear removes all sections.
+func (a *Accordion) Clear() *Accordion {
+	a.sections = nil
+	a.currentSection = -1
+	return a
+}
+
+// GetSectionCount returns the number of sections.
+func (a *Accordion) GetSectionCount() int {
+	return len(a.sections)
+}
+
+// SetSectionTitle sets the title of the section with the given index.
+func (a *Accordion) SetSectionTitle(index int, title string) *Accordion {
+	if index >= 0 && index < len(a.sections) {
+		a.sections[index].Title = title
+	}
+	return a
+}
+
+// SetMultiOpen sets whether or not multiple sections may be expanded at the
+// same time. If false (the default), expanding a section collapses all other
+// sections.
+func (a *Accordion) SetMultiOpen(multiOpen bool) *Accordion {
+	a.multiOpen = multiOpen
+	return a
+}
+
+// SetAnimation animates the expansion and collapse of sections over the given
+// duration. As the accordion cannot redraw itself, the provided function is
+// called repeatedly while an animation is running. It will usually call
+// Application.Draw(). It is called from a different goroutine. A duration of 0
+// (the default) switches off animations.
+func (a *Accordion) SetAnimation(duration time.Duration, redraw func()) *Accordion {
+	a.animationDuration = duration
+	a.redraw = redraw
+	return a
+}
+
+// Expand expands the section with the given index.
+func (a *Accordion) Expand(index int) *Accordion {
+	a.setExpanded(index, true)
+	return a
+}
+
+// Collapse collapses the section with the given index.
+func (a *Accordion) Collapse(index int) *Accordion {
+	a.setExpanded(index, false)
+	return a
+}
+
+// Toggle expands the section with the given index if it is collapsed or
+// collapses it if it is expanded.
+func (a *Accordion) Toggle(index int) *Accordion {
+	if index >= 0 && index < len(a.sections) {
+		a.setExpanded(index, !a.sections[index].Expanded)
+	}
+	return a
+}
+
+// IsExpanded returns whether or not the section with the given index is
+// expanded.
+func (a *Accordion) IsExpanded(index int) bool {
+	return index >= 0 && index < len(a.sections) && a.sections[index].Expanded
+}
+
+// GetCurrentSection returns the index of the current section, -1 if there are
+// no sections.
+func (a *Accordion) GetCurrentSection() int {
+	return a.currentSection
+}
+
+// SetHeaderTextColor sets the text color of the section headers.
+func (a *Accordion) SetHeaderTextColor(color tcell.Color) *Accordion {
+	a.headerTextColor = color
+	return a
+}
+
+// SetHeaderBackgroundColor sets the background color of the section headers.
+func (a *Accordion) SetHeaderBackgroundColor(color tcell.Color) *Accordion {
+	a.headerBackgroundColor = color
+	return a
+}
+
+// SetCurrentHeaderTextColor sets the text color of the current section's
+// header when the headers have focus.
+func (a *Accordion) SetCurrentHeaderTextColor(color tcell.Color) *Accordion {
+	a.currentHeaderTextColor = color
+	return a
+}
+
+// SetCurrentHeaderBackgroundColor sets the background color of the current
+// section's header when the headers have focus.
+func (a *Accordion) SetCurrentHeaderBackgroundColor(color tcell.Color) *Accordion {
+	a.currentHeaderBackgroundColor = color
+	return a
+}
+
+// SetChangedFunc sets a handler which is called whenever a section is expanded
+// or collapsed, by the user or programmatically. It receives the section's
+// index and its new state. When expanding a section collapses other sections,
+// the handler is called for those sections first.
+func (a *Accordion) SetChangedFunc(handler func(index int, expanded bool)) *Accordion {
+	a.changed = handler
+	return a
+}
+
+// SetDoneFunc sets a handler which is called when the user leaves the section
+// headers. The callback function is provided with the key that was pressed,
+// which is one of the following:
+//
+//   - KeyEscape: Leaving the headers with no specific direction.
+//   - KeyTab: Move to the next field.
+//   - KeyBacktab: Move to the previous field.
+func (a *Accordion) SetDoneFunc(handler func(key tcell.Key)) *Accordion {
+	a.done = handler
+	return a
+}
+
+// FocusHeaders causes the section headers to receive focus the next time this
+// primitive receives focus. If it already has focus, the headers receive focus
+// immediately.
+func (a *Accordion) FocusHeaders() *Accordion {
+	a.focusHeaders = true
+	if a.setFocus != nil && a.HasFocus() {
+		a.setFocus(a)
+	}
+	return a
+}
+
+// setExpanded expands or collapses the section with the given index, collapsing
+// other sections if necessary. If the primitive of a collapsed section had
+// focus, the focus is moved to the headers.
+func (a *Accordion) setExpanded(index int, expanded bool) {
+	if index < 0 || index >= len(a.sections) {
+		return
+	}
+	hadFocus := a.HasFocus() && !a.hasFocus
+	if expanded && !a.multiOpen {
+		for other := range a.sections {
+			if other != index {
+				a.setSectionExpanded(other, false)
+			}
+		}
+	}
+	a.setSectionExpanded(index, expanded)
+	if a.setFocus != nil && hadFocus && !a.HasFocus() {
+		// The focused primitive was hidden.
+		a.focusHeaders = true
+		a.setFocus(a)
+	}
+}
+
+// setSectionExpanded changes the state of the section with the given index,
+// starting an animation and notifying the "changed" handler.
+func (a *Accordion) setSectionExpanded(index int, expanded bool) {
+	section := a.sections[index]
+	if section.Expanded == expanded {
+		return
+	}
+	now := time.Now()
+	section.fraction = a.sectionFraction(section, now)
+	section.toggled = now
+	section.Expanded = expanded
+	if !expanded && section.Item != nil && section.Item.GetFocusable().HasFocus() {
+		section.Item.Blur()
+	}
+
+	// Keep redrawing until the animation is over.
+	if a.animationDuration > 0 && a.redraw != nil {
+		end, redraw := now.Add(a.animationDuration), a.redraw
+		var tick func()
+		tick = func() {
+			redraw()
+			if time.Now().Before(end) {
+				time.AfterFunc(25*time.Millisecond, tick)
+			}
+		}
+		time.AfterFunc(25*time.Millisecond, tick)
+	}
+
+	if a.changed != nil {
+		a.changed(index, expanded)
+	}
+}
+
+// sectionFraction returns how far the given section is expanded at the given
+// time, from 0 (collapsed) to 1 (expanded), taking animations into account.
+func (a *Accordion) sectionFraction(section *accordionSection, now time.Time) float64 {
+	target := 0.0
+	if section.Expanded {
+		target = 1
+	}
+	if a.animationDuration <= 0 || section.toggled.IsZero() {
+		return target
+	}
+	progress := float64(now.Sub(section.toggled)) / float64(a.animationDuration)
+	if progress >= 1 {
+		return target
+	}
+	return section.fraction + (target-section.fraction)*progress
+}
+
+// GetChildren returns the primitives of all sections.
+func (a *Accordion) GetChildren() []Primitive {
+	children := make([]Primitive, 0, len(a.sections))
+	for _, section := range a.sections {
+		if section.Item != nil {
+			children = append(children, section.Item)
+		}
+	}
+	return children
+}
+
+// SaveState returns which sections are expanded as JSON. See SaveLayout() for
+// details.
+func (a *Accordion) SaveState() ([]byte, error) {
+	var state accordionState
+	for _, section := range a.sections {
+		state.Expanded = append(state.Expanded, section.Expanded)
+	}
+	return json.Marshal(state)
+}
+
+// RestoreState restores a state returned by SaveState().
+func (a *Accordion) RestoreState(data []byte) error {
+	var state accordionState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	for index, expanded := range state.Expanded {
+		if index < len(a.sections) && !expanded {
+			a.Collapse(index)
+		}
+	}
+	for index, expanded := range state.Expanded {
+		if index < len(a.sections) && expanded {
+			a.Expand(index)
+		}
+	}
+	return nil
+}
+
+// applyTheme is called when the application's theme changes.
+func (a *Accordion) applyTheme(from, to *Theme) {
+	a.Box.applyTheme(from, to)
+	themeColor(&a.headerTextColor, from.PrimaryTextColor, to.PrimaryTextColor)
+	themeColor(&a.headerBackgroundColor, from.ContrastBackgroundColor, to.ContrastBackgroundColor)
+	themeColor(&a.currentHeaderTextColor, from.InverseTextColor, to.InverseTextColor)
+	themeColor(&a.currentHeaderBackgroundColor, from.PrimaryTextColor, to.PrimaryTextColor)
+}
+
+// Draw draws this primitive onto the screen.
+func (a *Accordion) Draw(screen tcell.Screen) {
+	defer a.applyStyle(&a.headerTextColor)()
+	a.Box.Draw(screen)
+
+	x, y, width, height := a.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	// Distribute the space left after the headers.
+	now := time.Now()
+	fractions := make([]float64, len(a.sections))
+	available := height - len(a.sections)
+	var proportional float64
+	for index, section := range a.sections {
+		fractions[index] = a.sectionFraction(section, now)
+		if section.Height > 0 {
+			available -= int(float64(section.Height)*fractions[index] + 0.5)
+		} else {
+			proportional += fractions[index]
+		}
+	}
+	if available < 0 {
+		available = 0
+	}
+
+	// Draw the sections.
+	row := y
+	bottom := y + height
+	for index, section := range a.sections {
+		section.headerY = -1
+		if row >= bottom {
+			if section.Item != nil {
+				section.Item.SetRect(x, bottom, width, 0)
+			}
+			continue
+		}
+
+		// Draw the header.
+		textColor, backgroundColor := a.headerTextColor, a.headerBackgroundColor
+		if index == a.currentSection && a.hasFocus {
+			textColor, backgroundColor = a.currentHeaderTextColor, a.currentHeaderBackgroundColor
+		}
+		style := tcell.StyleDefault.Background(backgroundColor).Foreground(textColor)
+		for column := x; column < x+width; column++ {
+			screen.SetContent(column, row, ' ', nil, style)
+		}
+		marker := '▶'
+		if section.Expanded {
+			marker = '▼'
+		}
+		screen.SetContent(x, row, marker, nil, style)
+		Print(screen, section.Title, x+2, row, width-2, AlignLeft, textColor)
+		section.headerY = row
+		row++
+
+		// Determine the content's height.
+		var contentHeight int
+		if section.Height > 0 {
+			contentHeight = int(float64(section.Height)*fractions[index] + 0.5)
+		} else if proportional > 0 {
+			contentHeight = int(float64(available)*fractions[index]/proportional + 0.5)
+			available -= contentHeight
+			proportional -= fractions[index]
+		}
+		if contentHeight > bottom-row {
+			contentHeight = bottom - row
+		}
+		if section.Item == nil {
+			row += contentHeight
+			continue
+		}
+		section.Item.SetRect(x, row, width, contentHeight)
+		if contentHeight > 0 {
+			if section.Item.GetFocusable().HasFocus() {
+				defer section.Item.Draw(screen)
+			} else {
+				section.Item.Draw(screen)
+			}
+		}
+		row += contentHeight
+	}
+}
+
+// Focus is called when this primitive receives focus.
+func (a *Accordion) Focus(delegate func(p Primitive)) {
+	a.setFocus = delegate
+	if a.focusHeaders || a.currentSection < 0 || !a.sections[a.currentSection].Expanded || a.sections[a.currentSection].Item == nil {
+		a.focusHeaders = false
+		a.Box.Focus(delegate)
+		return
+	}
+	delegate(a.sections[a.currentSection].Item)
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (a *Accordion) HasFocus() bool {
+	if a.hasFocus {
+		return true
+	}
+	for _, section := range a.sections {
+		if section.Item != nil && section.Item.GetFocusable().HasFocus() {
+			return true
+		}
+	}
+	return false
+}
+
+// InputHandler returns the handler for this primitive.
+func (a *Accordion) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return a.wrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		if a.currentSection < 0 {
+			if key := event.Key(); (key == tcell.KeyEscape || key == tcell.KeyTab || key == tcell.KeyBacktab) && a.done != nil {
+				a.done(key)
+			}
+			return
+		}
+		previous := func() {
+			if a.currentSection > 0 {
+				a.currentSection--
+			}
+		}
+		next := func() {
+			if a.currentSection < len(a.sections)-1 {
+				a.currentSection++
+			}
+		}
+		enter := func() {
+			section := a.sections[a.currentSection]
+			if !section.Expanded {
+				a.setExpanded(a.currentSection, true)
+			} else if section.Item != nil {
+				setFocus(section.Item)
+			}
+		}
+
+		switch key := event.Key(); key {
+		case tcell.KeyUp:
+			previous()
+		case tcell.KeyDown:
+			next()
+		case tcell.KeyHome:
+			a.currentSection = 0
+		case tcell.KeyEnd:
+			a.currentSection = len(a.sections) - 1
+		case tcell.KeyEnter:
+			a.Toggle(a.currentSection)
+		case tcell.KeyRight:
+			enter()
+		case tcell.KeyLeft:
+			a.Collapse(a.currentSection)
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'k':
+				previous()
+			case 'j':
+				next()
+			case 'g':
+				a.currentSection = 0
+			case 'G':
+				a.currentSection = len(a.sections) - 1
+			case ' ':
+				a.Toggle(a.currentSection)
+			case 'l':
+				enter()
+			case 'h':
+				a.Collapse(a.currentSection)
+			}
+		case tcell.KeyEscape, tcell.KeyTab, tcell.KeyBacktab:
+			if a.done != nil {
+				a.done(key)
+			}
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (a *Accordion) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return a.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+		if !a.InRect(x, y) {
+			return false, nil
+		}
+
+		// Clicks on a header.
+		for index, section := range a.sections {
+			if section.headerY != y {
+				continue
+			}
+			if action == MouseLeftClick {
+				a.currentSection = index
+				a.setExpanded(index, !section.Expanded)
+				a.focusHeaders = true
+				setFocus(a)
+			}
+			return true, nil
+		}
+
+		// Other events go to the expanded sections' primitives.
+		for _, section := range a.sections {
+			if section.Item == nil || !section.Expanded {
+				continue
+			}
+			if handler := mouseHandler(section.Item); handler != nil {
+				if consumed, capture = handler(action, event, setFocus); consumed {
+					return
+				}
+			}
+		}
+		return true, nil
+	})
+}
@@ -0,0 +1,274 @@
+package tview
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// The states of the ANSI escape sequence parser.
+const (
+	ansiText = iota
+	ansiEscape
+	ansiEscapeIntermediate
+	ansiControlSequence
+	ansiOperatingSystemCommand
+)
+
+// ansiColors are the names of the 16 basic ANSI colors.
+var ansiColors = []string{
+	"black", "maroon", "green", "olive", "navy", "purple", "teal", "silver",
+	"gray", "red", "lime", "yellow", "blue", "fuchsia", "aqua", "white",
+}
+
+// ansi is a writer which translates ANSI escape sequences into color tags.
+type ansi struct {
+	io.Writer
+
+	// The state of the parser.
+	state int
+
+	// The parameters of the current control sequence.
+	parameters bytes.Buffer
+
+	// Text which has not been written yet.
+	text bytes.Buffer
+
+	// The current text color, background color, and attributes as tag fields.
+	foreground, background string
+	attributes             map[rune]bool
+
+	// The last tag written.
+	lastTag string
+}
+
+// ANSIWriter returns an io.Writer which translates ANSI escape sequences
+// written to it into color tags (see TranslateANSI()) before writing them to
+// the given writer. This allows the output of commands which use ANSI colors
+// to be written into a TextView with dynamic colors:
+//
+//	cmd.Stdout = tview.ANSIWriter(textView)
+//
+// Sequences may be split across calls to Write().
+func ANSIWriter(writer io.Writer) io.Writer {
+	return &ansi{
+		Writer:     writer,
+		foreground: "-",
+		background: "-",
+		attributes: make(map[rune]bool),
+		lastTag:    "[-:-:-]",
+	}
+}
+
+// TranslateANSI replaces ANSI escape sequences in the given text with color
+// tags which can be used by any primitive that accepts color tags. The SGR
+// ("Select Graphic Rendition") sequences for the 16 basic colors, the 256-color
+// palette, 24-bit colors, and the bold, dim, underline, blink, and reverse
+// attributes are supported. Other escape sequences (e.g. cursor movements) are
+// removed. Square brackets in the text are escaped so they don't form tags.
+func TranslateANSI(text string) string {
+	var buffer bytes.Buffer
+	writer := ANSIWriter(&buffer).(*ansi)
+	writer.Write([]byte(text))
+	writer.flush(true)
+	return buffer.String()
+}
+
+// Write translates the given bytes and writes them to the underlying writer.
+func (a *ansi) Write(p []byte) (int, error) {
+	for _, b := range p {
+		switch a.state {
+		case ansiEscape:
+			switch b {
+			case '[':
+				a.state = ansiControlSequence
+				a.parameters.Reset()
+			case ']':
+				a.state = ansiOperatingSystemCommand
+			default:
+				if b >= 0x20 && b <= 0x2f {
+					a.state = ansiEscapeIntermediate // E.g. ESC ( B.
+				} else {
+					a.state = ansiText // Other escape sequences are dropped.
+				}
+			}
+		case ansiEscapeIntermediate:
+			// Skip intermediate bytes up to and including the final byte.
+			if b < 0x20 || b > 0x2f {
+				a.state = ansiText
+			}
+		case ansiControlSequence:
+			if b >= 0x40 && b <= 0x7e {
+				// This is the final byte.
+				if b == 'm' {
+					a.selectGraphicRendition(a.parameters.String())
+				}
+				a.state = ansiText
+			} else {
+				a.parameters.WriteByte(b)
+			}
+		case ansiOperatingSystemCommand:
+			// Terminated by BEL or ST (ESC \).
+			if b == '\a' {
+				a.state = ansiText
+			} else if b == 0x1b {
+				a.state = ansiEscape
+			}
+		default:
+			if b == 0x1b {
+				if err := a.flush(true); err != nil {
+					return 0, err
+				}
+				a.state = ansiEscape
+			} else {
+				a.text.WriteByte(b)
+			}
+		}
+	}
+
+	if err := a.flush(false); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// flush writes the pending text, with square brackets escaped, to the
+// underlying writer. If "all" is false, text starting with an opening square
+// bracket which may still become a tag is kept for the next call.
+func (a *ansi) flush(all bool) error {
+	text := a.text.String()
+	var keep string
+	if !all {
+		if open := strings.LastIndex(text, "["); open >= 0 && !strings.ContainsAny(text[open:], "]\n") {
+			text, keep = text[:open], text[open:]
+		}
+	}
+	a.text.Reset()
+	a.text.WriteString(keep)
+	if text == "" {
+		return nil
+	}
+	_, err := io.WriteString(a.Writer, Escape(text))
+	return err
+}
+
+// selectGraphicRendition applies the given SGR parameters to the current style
+// and writes the resulting color tag.
+func (a *ansi) selectGraphicRendition(parameters string) {
+	fields := strings.FieldsFunc(parameters, func(r rune) bool {
+		return r == ';' || r == ':'
+	})
+	if len(fields) == 0 {
+		fields = []string{"0"}
+	}
+
+	for index := 0; index < len(fields); index++ {
+		code, err := strconv.Atoi(fields[index])
+		if err != nil {
+			continue
+		}
+		switch {
+		case code == 0:
+			a.foreground, a.background = "-", "-"
+			a.attributes = make(map[rune]bool)
+		case code == 1:
+			a.attributes['b'] = true
+		case code == 2:
+			a.attributes['d'] = true
+		case code == 4:
+			a.attributes['u'] = true
+		case code == 5 || code == 6:
+			a.attributes['l'] = true
+		case code == 7:
+			a.attributes['r'] = true
+		case code == 22:
+			delete(a.attributes, 'b')
+			delete(a.attributes, 'd')
+		case code == 24:
+			delete(a.attributes, 'u')
+		case code == 25:
+			delete(a.attributes, 'l')
+		case code == 27:
+			delete(a.attributes, 'r')
+		case code >= 30 && code <= 37:
+			a.foreground = ansiColors[code-30]
+		case code >= 90 && code <= 97:
+			a.foreground = ansiColors[code-90+8]
+		case code == 39:
+			a.foreground = "-"
+		case code >= 40 && code <= 47:
+			a.background = ansiColors[code-40]
+		case code >= 100 && code <= 107:
+			a.background = ansiColors[code-100+8]
+		case code == 49:
+			a.background = "-"
+		case code == 38 || code == 48:
+			color, consumed := ansiExtendedColor(fields[index+1:])
+			index += consumed
+			if color == "" {
+				break
+			}
+			if code == 38 {
+				a.foreground = color
+			} else {
+				a.background = color
+			}
+		}
+	}
+
+	// Write the tag if the style has changed.
+	attributes := "-"
+	if len(a.attributes) > 0 {
+		attributes = ""
+		for _, flag := range "bdulr" {
+			if a.attributes[flag] {
+				attributes += string(flag)
+			}
+		}
+	}
+	tag := fmt.Sprintf("[%s:%s:%s]", a.foreground, a.background, attributes)
+	if tag != a.lastTag {
+		io.WriteString(a.Writer, tag)
+		a.lastTag = tag
+	}
+}
+
+// ansiExtendedColor parses the parameters following an SGR 38 or 48 code,
+// either "5;n" for a color of the 256-color palette or "2;r;g;b" for a 24-bit
+// color. It returns the color as a tag field (an empty string if the parameters
+// are invalid) and the number of parameters consumed.
+func ansiExtendedColor(fields []string) (string, int) {
+	values := make([]int, len(fields))
+	for index, field := range fields {
+		values[index], _ = strconv.Atoi(field)
+	}
+	switch {
+	case len(values) >= 2 && values[0] == 5:
+		n := values[1]
+		switch {
+		case n < 0 || n > 255:
+			return "", 2
+		case n < 16:
+			return ansiColors[n], 2
+		case n < 232:
+			// The 6x6x6 color cube.
+			n -= 16
+			levels := []int{0, 95, 135, 175, 215, 255}
+			return fmt.Sprintf("#%02x%02x%02x", levels[n/36], levels[n/6%6], levels[n%6]), 2
+		default:
+			// The grayscale ramp.
+			gray := 8 + 10*(n-232)
+			return fmt.Sprintf("#%02x%02x%02x", gray, gray, gray), 2
+		}
+	case len(values) >= 4 && values[0] == 2:
+		for _, value := range values[1:4] {
+			if value < 0 || value > 255 {
+				return "", 4
+			}
+		}
+		return fmt.Sprintf("#%02x%02x%02x", values[1], values[2], values[3]), 4
+	}
+	return "", len(values)
+}
@@ -0,0 +1,21 @@
+package tview
+
+import (
+	"testing"
+)
+
+func TestTranslateANSI(t *testing.T) {
+	for _, test := range []struct {
+		ansi, text string
+	}{
+		{"\x1b[31mred\x1b[0mplain", "redplain"},
+		{"\x1b[31mred\x1b(B\x1b[mplain", "redplain"},
+		{"\x1b)0\x1b7text\x1b8", "text"},
+		{"\x1b]0;title\atext", "text"},
+		{"arr[1] = [red]", "arr[1] = [red]"},
+	} {
+		if text := StripTags(TranslateANSI(test.ansi)); text != test.text {
+			t.Errorf("TranslateANSI(%q) shows %q, want %q", test.ansi, text, test.text)
+		}
+	}
+}
@@ -1,11 +1,77 @@
 package tview
 
 import (
+	"os"
+	"reflect"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gdamore/tcell"
 )
 
+// DoubleClickInterval specifies the maximum time between clicks to register a
+// double click rather than a single click.
+var DoubleClickInterval = 500 * time.Millisecond
+
+// pasteMarkerTimeout is the time after which the beginning of a possible paste
+// marker is processed as regular key events if the rest did not arrive.
+const pasteMarkerTimeout = 50 * time.Millisecond
+
+// MouseAction indicates one of the actions the mouse is logically doing.
+type MouseAction int16
+
+// Available mouse actions.
+const (
+	MouseMove MouseAction = iota
+	MouseLeftDown
+	MouseLeftUp
+	MouseLeftClick
+	MouseLeftDoubleClick
+	MouseMiddleDown
+	MouseMiddleUp
+	MouseMiddleClick
+	MouseMiddleDoubleClick
+	MouseRightDown
+	MouseRightUp
+	MouseRightClick
+	MouseRightDoubleClick
+	MouseScrollUp
+	MouseScrollDown
+	MouseScrollLeft
+	MouseScrollRight
+)
+
+// FocusIndicator determines how the application marks the focused primitive
+// (see Application.SetFocusIndicator()).
+type FocusIndicator int
+
+// Focus indicators.
+const (
+	FocusIndicatorNone    FocusIndicator = iota // No marker, widgets show focus themselves.
+	FocusIndicatorBorder                        // Invert the outermost cells of the focused primitive.
+	FocusIndicatorCorners                       // Draw markers into the corners of the focused primitive.
+)
+
+// DrawStats contains statistics about a frame drawn by an Application (see
+// Application.SetDrawStatsFunc()).
+type DrawStats struct {
+	// The time it took to draw the primitives onto the screen buffer.
+	Duration time.Duration
+
+	// The time it took to send the changes to the terminal.
+	ShowDuration time.Duration
+
+	// The number of primitives based on Box which were drawn.
+	Primitives int
+
+	// Whether only changed primitives were redrawn (see
+	// Application.EnableDirtyTracking() and Application.EnableFocusedRedraw()).
+	Partial bool
+}
+
 // Application represents the top node of an application.
 //
 // It is not strictly required to use this class as none of the other classes
@@ -38,11 +104,98 @@ type Application struct {
 	// An optional callback function which is invoked after the root primitive
 	// was drawn.
 	afterDraw func(screen tcell.Screen)
+
+	// An optional callback function which receives statistics about each
+	// frame.
+	drawStats func(stats DrawStats)
+
+	// The state shared with the boxes drawn by the application.
+	state *appState
+
+	// Closed when the function called by Suspend() returns, nil if the
+	// application is not suspended.
+	resumed chan struct{}
+
+	// Whether or not mouse events are processed.
+	enableMouse bool
+
+	// The keys which stop the application.
+	quitKeys []tcell.Key
+
+	// The key which suspends the application, tcell.KeyNUL for none.
+	suspendKey tcell.Key
+
+	// Whether or not only changed primitives are redrawn (see
+	// EnableDirtyTracking()), whether the next draw must redraw everything
+	// regardless, and the size of the screen when it was last drawn.
+	dirtyTracking           bool
+	fullDraw                bool
+	drawnWidth, drawnHeight int
+
+	// Whether or not only the focused primitive is redrawn after a key event
+	// which changed nothing else (see EnableFocusedRedraw()).
+	focusedRedraw bool
+
+	// The buffer which holds the screen's cells if only changed cells are
+	// passed on to the screen (see EnableCellDiffing()), nil otherwise.
+	shadow *shadowScreen
+
+	// How the focused primitive is marked and the marker's color.
+	focusIndicator      FocusIndicator
+	focusIndicatorColor tcell.Color
+
+	// Whether or not Tab and Backtab move the focus between all focusable
+	// primitives.
+	tabNavigation bool
+
+	// Whether or not Alt+arrow keys move the focus in the pressed direction.
+	directionalFocus bool
+
+	// The key which stands for "Leader" in key sequences, if any.
+	leaderKey *KeyStroke
+
+	// The time the user has to press the next key of a key sequence.
+	keySequenceTimeout time.Duration
+
+	// The keys of a key sequence pressed so far, the shortcut whose sequence
+	// they complete while they may also start a longer one (if any), and a
+	// number which identifies the pending sequence.
+	pendingKeys       []KeyStroke
+	pendingShortcut   *shortcut
+	pendingGeneration int
+
+	// Whether or not the terminal is asked to mark pasted text.
+	bracketedPaste bool
+
+	// The key events of a possible paste start or end marker, a number which
+	// identifies the pending marker, whether or not text is being pasted, and
+	// the text pasted so far.
+	pasteMarker     []*tcell.EventKey
+	pasteGeneration int
+	pasting         bool
+	pasteText       []rune
+
+	// The primitive which currently captures all mouse events (nil if none).
+	mouseCapturingPrimitive Primitive
+
+	// The last mouse position and button state, used to derive mouse actions.
+	lastMouseX, lastMouseY int
+	lastMouseButtons       tcell.ButtonMask
+
+	// The position of the last mouse button press.
+	mouseDownX, mouseDownY int
+
+	// The time of the last mouse click, used to detect double clicks.
+	lastMouseClick time.Time
 }
 
 // NewApplication creates and returns a new application.
 func NewApplication() *Application {
-	return &Application{}
+	return &Application{
+		quitKeys:           []tcell.Key{tcell.KeyCtrlC},
+		keySequenceTimeout: time.Second,
+		state:              newAppState(),
+	}
 }
 
 // SetInputCapture sets a function which captures all key events before they are
@@ -59,23 +212,464 @@ func (a *Application) SetInputCapture(capture func(event *tcell.EventKey) *tcell
 	return a
 }
 
+// EnableMouse enables or disables the processing of mouse events. When
+// enabled, mouse events are translated into mouse actions (see the MouseAction
+// constants) and passed on to the root primitive's mouse handler. Mouse events
+// are disabled by default.
+func (a *Application) EnableMouse(enable bool) *Application {
+	a.Lock()
+	defer a.Unlock()
+	a.enableMouse = enable
+	if a.screen != nil {
+		if enable {
+			a.screen.EnableMouse()
+		} else {
+			a.screen.DisableMouse()
+		}
+	}
+	return a
+}
+
+// SetQuitKeys sets the keys which stop the application (see Stop()). The
+// default is Ctrl-C. Call it without keys to let the application only be
+// stopped programmatically. The keys are still passed on to the focused
+// primitive. Note that the input capture function (see SetInputCapture()) sees
+// these keys first and may intercept them.
+func (a *Application) SetQuitKeys(keys ...tcell.Key) *Application {
+	a.Lock()
+	defer a.Unlock()
+	a.quitKeys = keys
+	return a
+}
+
+// SetSuspendKey sets the key which suspends the application, e.g.
+// tcell.KeyCtrlZ, following the usual job control of Unix shells: the
+// terminal is restored and the process is stopped until it is continued (e.g.
+// with "fg"), at which point the application takes over the terminal again
+// and redraws it. The same happens when the process receives a SIGTSTP signal
+// while the application is running. tcell.KeyNUL (the default) means there is
+// no suspend key. Suspending has no effect on platforms without job control.
+func (a *Application) SetSuspendKey(key tcell.Key) *Application {
+	a.Lock()
+	defer a.Unlock()
+	a.suspendKey = key
+	return a
+}
+
+// Suspend temporarily restores the terminal, calls the given function, and
+// takes over the terminal again when the function returns, e.g. to run an
+// external program like an editor in the terminal. It returns false if the
+// application was not running. The screen is not drawn while the application
+// is suspended. When the function returns, the event loop makes a new screen
+// and redraws it. If that fails, Run() returns the error.
+func (a *Application) Suspend(f func()) bool {
+	a.Lock()
+	screen := a.screen
+	if screen == nil {
+		a.Unlock()
+		return false
+	}
+	resumed := make(chan struct{})
+	a.screen, a.resumed = nil, resumed
+	bracketedPaste := a.bracketedPaste
+	a.Unlock()
+
+	// Restore the terminal and wait for the function to return.
+	if bracketedPaste {
+		setBracketedPaste(false)
+	}
+	screen.Fini()
+	f()
+
+	// The event loop takes over again.
+	close(resumed)
+	return true
+}
+
+// initScreen makes and initializes a new screen. The application must be
+// locked.
+func (a *Application) initScreen() error {
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		return err
+	}
+	if err = screen.Init(); err != nil {
+		return err
+	}
+	if a.enableMouse {
+		screen.EnableMouse()
+	}
+	if a.bracketedPaste {
+		setBracketedPaste(true)
+	}
+	a.screen = screen
+	return nil
+}
+
+// EnableDirtyTracking enables or disables dirty tracking. When enabled, Draw()
+// repaints only the boxes which were marked as changed since they were last
+// drawn (see Box.MarkDirty()), including the primitives they contain and the
+// boxes drawn on top of them, instead of the entire primitive tree. This
+// reduces the work done after each key event considerably for large layouts.
+//
+// Boxes mark themselves when they process key events or consume mouse events.
+// Other changes must be marked explicitly, e.g. when a button's handler
+// updates a TextView. Marking the root primitive redraws everything. The
+// entire tree is also redrawn when the root primitive, the focus, the theme,
+// or the size of the screen changes, when changed boxes are covered by
+// something the application cannot redraw separately, e.g. a shadow, and when
+// they draw outside their rectangle, e.g. an open DropDown list.
+func (a *Application) EnableDirtyTracking(enable bool) *Application {
+	a.Lock()
+	defer a.Unlock()
+	a.dirtyTracking, a.fullDraw = enable, true
+	return a
+}
+
+// EnableFocusedRedraw enables or disables redrawing only the focused primitive
+// after it processed a key event, e.g. a keystroke in an InputField, instead
+// of the entire primitive tree. Drawing is clipped to the primitive's
+// rectangle. This only happens if the primitive processing the event was the
+// only one which changed: if the focus moved or other primitives were marked
+// as changed (see Box.MarkDirty()), e.g. because a handler added items to a
+// Flex or switched Pages, everything is redrawn as usual.
+//
+// Unlike dirty tracking (see EnableDirtyTracking()), this does not require
+// marking changes made outside of input handling as Draw() still redraws the
+// entire tree. But handlers which change other primitives in response to a
+// key event without these being marked (e.g. by setting a TextView's text)
+// must call Draw() themselves.
+func (a *Application) EnableFocusedRedraw(enable bool) *Application {
+	a.Lock()
+	defer a.Unlock()
+	a.focusedRedraw = enable
+	return a
+}
+
+// EnableCellDiffing enables or disables passing only changed cells on to the
+// screen. Primitives then draw into a buffer held by the application and when
+// the screen is shown, only cells whose rune or style differ from what was
+// passed on before are set on the screen. Cells which are drawn again with
+// the same contents (e.g. an unchanged background or border) or which change
+// only temporarily while drawing are skipped, reducing the work done by the
+// screen and, for screens which don't compare cells themselves, the output
+// sent to the terminal, e.g. over slow links such as SSH.
+//
+// The buffer requires some memory per screen cell. The screen passed to the
+// before-draw and after-draw handlers (see SetBeforeDrawFunc()) draws into
+// the buffer, too.
+func (a *Application) EnableCellDiffing(enable bool) *Application {
+	a.Lock()
+	defer a.Unlock()
+	if enable && a.shadow == nil {
+		a.shadow = &shadowScreen{}
+	} else if !enable {
+		a.shadow = nil
+	}
+	a.fullDraw = true
+	return a
+}
+
+// drawnPrimitive is a primitive drawn the last time the screen was drawn,
+// with its embedded box.
+type drawnPrimitive struct {
+	Primitive
+	box *Box
+}
+
+// overlaps returns whether the primitive's rectangle overlaps the given
+// rectangle.
+func (d drawnPrimitive) overlaps(x, y, width, height int) bool {
+	dx, dy, dWidth, dHeight := d.GetRect()
+	return dx < x+width && x < dx+dWidth && dy < y+height && y < dy+dHeight
+}
+
+// drawDirty redraws the boxes in the tree below "root" which were marked as
+// changed since they were last drawn (see Box.MarkDirty()), followed by the
+// boxes which were drawn on top of them, each clipped to its rectangle. It
+// returns false without drawing anything if the entire tree needs to be
+// redrawn instead.
+func (s *appState) drawDirty(root Primitive) bool {
+	// Collect the boxes drawn the last time and the changed ones among them.
+	var drawn, redraw []drawnPrimitive
+	var others []Primitive
+	overlays := false
+	boxes := 0
+	WalkTree(root, func(p, parent Primitive) bool {
+		b, ok := p.(interface {
+			getBox() *Box
+		})
+		if !ok {
+			others = append(others, p)
+			return true
+		}
+		box := b.getBox()
+		if !box.drawnIn(s) || box.drawnScreen == nil {
+			return true
+		}
+		boxes++
+		if len(box.drawFuncs[DrawOverlay]) > 0 {
+			overlays = true
+		}
+		d := drawnPrimitive{Primitive: p, box: box}
+		drawn = append(drawn, d)
+		if box.isDirty() {
+			redraw = append(redraw, d)
+		}
+		return true
+	})
+	if overlays || boxes < s.drawnBoxes {
+		return false // Overlays and boxes outside the tree may cover anything.
+	}
+
+	// Add the boxes drawn on top of the ones to be redrawn.
+	contained := func(p Primitive) bool {
+		for _, r := range redraw {
+			if containsPrimitive(r.Primitive, p) {
+				return true
+			}
+		}
+		return false
+	}
+	for index := 0; index < len(redraw); index++ {
+		r := redraw[index]
+		if r.box.shadow || r.box.joinBorders {
+			return false // These depend on what was drawn before.
+		}
+		if r.box.drewOutside || drawsOutside(r.Primitive) {
+			return false // They may cover or uncover other boxes.
+		}
+		last := r.box.drawOrder
+		for _, d := range drawn {
+			if d.box.drawOrder > last && containsPrimitive(r.Primitive, d.Primitive) {
+				last = d.box.drawOrder
+			}
+		}
+		for _, d := range drawn {
+			if d.box.drawOrder > r.box.drawOrder && d.overlaps(r.GetRect()) && !contained(d.Primitive) {
+				redraw = append(redraw, d)
+			}
+		}
+		for _, p := range others {
+			if r.overlaps(p.GetRect()) && !containsPrimitive(r.Primitive, p) && !containsPrimitive(p, r.Primitive) {
+				return false // We don't know when it was drawn.
+			}
+		}
+		for _, dimmed := range s.dimmedRects {
+			if dimmed.order > last && r.overlaps(dimmed.x, dimmed.y, dimmed.width, dimmed.height) {
+				return false // Dimmed after the box was drawn.
+			}
+		}
+	}
+
+	// Redraw them in their original order, skipping those contained in others.
+	sort.Slice(redraw, func(i, j int) bool {
+		return redraw[i].box.drawOrder < redraw[j].box.drawOrder
+	})
+	for index, r := range redraw {
+		inside := false
+		for _, other := range redraw[:index] {
+			if containsPrimitive(other.Primitive, r.Primitive) {
+				inside = true
+				break
+			}
+		}
+		if inside {
+			continue
+		}
+		for _, d := range drawn {
+			if d.box.drawnIn(s) && containsPrimitive(r.Primitive, d.Primitive) {
+				d.box.drawn = 0 // Contained boxes may not be drawn again.
+				s.drawnBoxes--
+			}
+		}
+		x, y, width, height := r.GetRect()
+		drawPrimitive(&clippedScreen{Screen: r.box.drawnScreen, x: x, y: y, width: width, height: height}, r.Primitive)
+	}
+	return true
+}
+
+// onlyDirty returns whether no box in the tree below "root" other than the
+// given primitive was marked as changed (see Box.MarkDirty()) since it was
+// last drawn. Boxes which were not drawn the last time are ignored.
+func (s *appState) onlyDirty(root, p Primitive) bool {
+	only := true
+	WalkTree(root, func(q, parent Primitive) bool {
+		if b, ok := q.(interface {
+			getBox() *Box
+		}); ok && q != p && b.getBox().drawnIn(s) && b.getBox().isDirty() {
+			only = false
+		}
+		return only
+	})
+	return only
+}
+
+// SetFocusIndicator sets a marker which the application draws around the
+// focused primitive on top of everything else, independent of the focused
+// widget's own styling, which helps users keep track of the focus:
+//
+//   - FocusIndicatorNone: No marker (the default).
+//   - FocusIndicatorBorder: The outermost cells of the focused primitive (e.g.
+//     its border) are drawn in reverse video, in the given color.
+//   - FocusIndicatorCorners: Markers are drawn into the four corners of the
+//     focused primitive, in the given color.
+//
+// If the color is tcell.ColorDefault, the colors already on screen are used
+// for FocusIndicatorBorder and Styles.PrimaryTextColor for
+// FocusIndicatorCorners.
+func (a *Application) SetFocusIndicator(indicator FocusIndicator, color tcell.Color) *Application {
+	a.Lock()
+	defer a.Unlock()
+	a.focusIndicator, a.focusIndicatorColor = indicator, color
+	return a
+}
+
+// drawFocusIndicator draws the given focus indicator around the given
+// primitive.
+func drawFocusIndicator(screen tcell.Screen, state *appState, p Primitive, indicator FocusIndicator, color tcell.Color) {
+	if p == nil || indicator == FocusIndicatorNone || !wasDrawn(p, state) {
+		return
+	}
+	x, y, width, height := p.GetRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+	right, bottom := x+width-1, y+height-1
+
+	switch indicator {
+	case FocusIndicatorBorder:
+		invert := func(x, y int) {
+			mainc, combc, style, _ := screen.GetContent(x, y)
+			if color != tcell.ColorDefault {
+				style = style.Foreground(color)
+			}
+			screen.SetContent(x, y, mainc, combc, style.Reverse(true))
+		}
+		for column := x; column <= right; column++ {
+			invert(column, y)
+			if bottom > y {
+				invert(column, bottom)
+			}
+		}
+		for row := y + 1; row < bottom; row++ {
+			invert(x, row)
+			if right > x {
+				invert(right, row)
+			}
+		}
+	case FocusIndicatorCorners:
+		if color == tcell.ColorDefault {
+			color = Styles.PrimaryTextColor
+		}
+		corner := func(x, y int, ch rune) {
+			_, _, style, _ := screen.GetContent(x, y)
+			screen.SetContent(x, y, ch, nil, style.Foreground(color).Bold(true))
+		}
+		corner(x, y, '▛')
+		corner(right, y, '▜')
+		corner(x, bottom, '▙')
+		corner(right, bottom, '▟')
+	}
+}
+
+// EnableTabNavigation enables or disables moving the focus with Tab and
+// Backtab. When enabled, these keys move the focus to the next or previous
+// focusable primitive on the screen, in the order in which they appear in the
+// primitive tree (see WalkTree()), instead of being passed on to the focused
+// primitive. Focus groups (see FocusGroup) count as a single stop. Hidden and
+// disabled primitives are skipped. Tab navigation is disabled by default, but
+// focus groups move the focus within themselves regardless.
+func (a *Application) EnableTabNavigation(enable bool) *Application {
+	a.Lock()
+	defer a.Unlock()
+	a.tabNavigation = enable
+	return a
+}
+
+// EnableDirectionalFocus enables or disables moving the focus with Alt and
+// the arrow keys. When enabled, these keys move the focus to the focusable
+// primitive which is geometrically nearest to the focused primitive in the
+// pressed direction, based on their positions on screen (see GetRect()), like
+// switching between panes in tmux or an IDE. The keys are then not passed on
+// to the focused primitive. Focus groups (see FocusGroup) count as a single
+// stop. Directional focus is disabled by default.
+func (a *Application) EnableDirectionalFocus(enable bool) *Application {
+	a.Lock()
+	defer a.Unlock()
+	a.directionalFocus = enable
+	return a
+}
+
+// SetLeaderKey sets the key which is matched by "Leader" in key sequences (see
+// Box.AddKeySequence()), e.g. tcell.KeyRune and ' ' for the space bar. If
+// "key" is tcell.KeyRune, the leader key is the rune "ch". There is no leader
+// key by default, i.e. key sequences with "Leader" are never triggered.
+func (a *Application) SetLeaderKey(key tcell.Key, ch rune) *Application {
+	if key != tcell.KeyRune {
+		ch = 0
+	}
+	a.Lock()
+	defer a.Unlock()
+	a.leaderKey = &KeyStroke{Key: key, Rune: ch}
+	return a
+}
+
+// SetKeySequenceTimeout sets the time the user has to press the next key of a
+// key sequence (see Box.AddKeySequence()) before the sequence is abandoned.
+// The default is one second.
+func (a *Application) SetKeySequenceTimeout(timeout time.Duration) *Application {
+	a.Lock()
+	defer a.Unlock()
+	a.keySequenceTimeout = timeout
+	return a
+}
+
+// EnableBracketedPaste enables or disables bracketed paste mode. When enabled,
+// the terminal marks the beginning and the end of pasted text and the pasted
+// text is delivered to the focused primitive as a whole if it implements
+// Paster (e.g. InputField and TextArea), instead of as individual key events.
+// Other primitives receive the text's characters as key events as before.
+// Bracketed paste mode is disabled by default.
+//
+// The terminal is switched into bracketed paste mode by writing an escape
+// sequence to the standard output, which must therefore be connected to the
+// terminal.
+func (a *Application) EnableBracketedPaste(enable bool) *Application {
+	a.Lock()
+	defer a.Unlock()
+	if a.screen != nil && a.bracketedPaste != enable {
+		setBracketedPaste(enable)
+	}
+	a.bracketedPaste = enable
+	return a
+}
+
+// setBracketedPaste switches the terminal's bracketed paste mode on or off.
+func setBracketedPaste(enable bool) {
+	if enable {
+		os.Stdout.WriteString("\x1b[?2004h")
+	} else {
+		os.Stdout.WriteString("\x1b[?2004l")
+	}
+}
+
 // Run starts the application and thus the event loop. This function returns
 // when Stop() was called.
 func (a *Application) Run() error {
-	var err error
 	a.Lock()
 
 	// Make a screen.
-	a.screen, err = tcell.NewScreen()
-	if err != nil {
-		a.Unlock()
-		return err
-	}
-	if err = a.screen.Init(); err != nil {
+	if err := a.initScreen(); err != nil {
 		a.Unlock()
 		return err
 	}
 
+	// Job control signals suspend the application.
+	defer watchStopSignal(func() {
+		a.Suspend(stopProcess)
+	})()
+
 	// We catch panics to clean up because they mess up the terminal.
 	defer func() {
 		if p := recover(); p != nil {
@@ -93,65 +687,623 @@ func (a *Application) Run() error {
 	// Start event loop.
 	for {
 		a.RLock()
-		screen := a.screen
+		screen, resumed := a.screen, a.resumed
 		a.RUnlock()
 		if screen == nil {
-			break
+			if resumed == nil {
+				break // The application was stopped.
+			}
+
+			// The application was suspended. Wait until it resumes, then
+			// make a new screen, unless it was stopped in the meantime.
+			<-resumed
+			a.Lock()
+			if a.resumed != resumed {
+				a.Unlock()
+				break
+			}
+			a.resumed = nil
+			a.fullDraw = true
+			err := a.initScreen()
+			a.Unlock()
+			if err != nil {
+				return err
+			}
+			a.Draw()
+			continue
 		}
 
 		// Wait for next event.
-		event := a.screen.PollEvent()
+		event := screen.PollEvent()
 		if event == nil {
-			break // The screen was finalized.
+			continue // The screen was finalized.
 		}
 
 		switch event := event.(type) {
 		case *tcell.EventKey:
-			a.RLock()
-			p := a.focus
-			a.RUnlock()
-
-			// Intercept keys.
-			if a.inputCapture != nil {
-				event = a.inputCapture(event)
-				if event == nil {
-					break // Don't forward event.
-				}
+			for _, event := range a.scanPaste(event) {
+				a.handleKey(event)
 			}
-
-			// Ctrl-C closes the application.
-			if event.Key() == tcell.KeyCtrlC {
-				a.Stop()
-			}
-
-			// Pass other key events to the currently focused primitive.
-			if p != nil {
-				if handler := p.InputHandler(); handler != nil {
-					handler(event, func(p Primitive) {
-						a.SetFocus(p)
-					})
-					a.Draw()
+		case *keySequenceTimeoutEvent:
+			if event.paste {
+				if event.generation == a.pasteGeneration {
+					for _, event := range a.flushPasteMarker() {
+						a.handleKey(event)
+					}
+				}
+			} else if event.generation == a.pendingGeneration && a.pendingKeys != nil {
+				s := a.pendingShortcut
+				a.pendingKeys, a.pendingShortcut = nil, nil
+				if s != nil && s.handler != nil {
+					s.handler()
+					a.redrawAll()
 				}
 			}
 		case *tcell.EventResize:
 			a.Lock()
 			screen := a.screen
+			a.fullDraw = true
+			if a.shadow != nil {
+				a.shadow.reset() // The screen is cleared below.
+			}
 			a.Unlock()
-			screen.Clear()
+			if screen != nil {
+				screen.Clear()
+			}
 			a.Draw()
+		case *tcell.EventMouse:
+			a.handleMouse(event)
 		}
 	}
 
 	return nil
 }
 
-// Stop stops the application, causing Run() to return.
-func (a *Application) Stop() {
+// scanPaste looks for the markers of pasted text in bracketed paste mode. The
+// terminal sends them as escape sequences which arrive as Alt+[ followed by
+// "200~" (start) or "201~" (end). The text in between is collected and, once
+// the end marker arrives, delivered to the focused primitive. It returns the
+// key events which are to be processed as usual.
+func (a *Application) scanPaste(event *tcell.EventKey) []*tcell.EventKey {
 	a.RLock()
-	defer a.RUnlock()
+	enabled, screen := a.bracketedPaste, a.screen
+	a.RUnlock()
+	if !enabled && !a.pasting && len(a.pasteMarker) == 0 {
+		return []*tcell.EventKey{event}
+	}
+
+	// Collect the key events of a marker.
+	if len(a.pasteMarker) > 0 || event.Key() == tcell.KeyRune && event.Rune() == '[' && event.Modifiers()&tcell.ModAlt != 0 {
+		a.pasteMarker = append(a.pasteMarker, event)
+		var marker string
+		for _, e := range a.pasteMarker {
+			if e.Key() != tcell.KeyRune {
+				marker += "\x00"
+				continue
+			}
+			marker += string(e.Rune())
+		}
+		expected := "[200~"
+		if a.pasting {
+			expected = "[201~"
+		}
+		if marker == expected {
+			a.pasteMarker = nil
+			a.pasting = !a.pasting
+			if !a.pasting {
+				text := string(a.pasteText)
+				a.pasteText = nil
+				a.paste(text)
+			}
+			return nil
+		}
+		if strings.HasPrefix(expected, marker) {
+			// Wait for the rest of the marker. The terminal sends it all at
+			// once so if it doesn't arrive shortly, this was a key press
+			// (e.g. Alt+[) which must not be held back.
+			a.pasteGeneration++
+			if screen != nil {
+				generation := a.pasteGeneration
+				time.AfterFunc(pasteMarkerTimeout, func() {
+					screen.PostEvent(&keySequenceTimeoutEvent{generation: generation, paste: true})
+				})
+			}
+			return nil
+		}
+
+		// This was not a marker.
+		return a.flushPasteMarker()
+	}
+
+	// Collect pasted text.
+	if a.pasting {
+		a.pasteText = append(a.pasteText, pastedRunes(event)...)
+		return nil
+	}
+	return []*tcell.EventKey{event}
+}
+
+// flushPasteMarker gives up on the key events collected for a paste marker.
+// While text is being pasted, they are added to it. Otherwise they are
+// returned to be processed as usual.
+func (a *Application) flushPasteMarker() []*tcell.EventKey {
+	events := a.pasteMarker
+	a.pasteMarker = nil
+	if !a.pasting {
+		return events
+	}
+	for _, e := range events {
+		a.pasteText = append(a.pasteText, pastedRunes(e)...)
+	}
+	return nil
+}
+
+// pastedRunes returns the text which the given key event contributes to
+// pasted text.
+func pastedRunes(event *tcell.EventKey) []rune {
+	switch event.Key() {
+	case tcell.KeyRune:
+		if event.Modifiers()&tcell.ModAlt != 0 {
+			return []rune{'\x1b', event.Rune()}
+		}
+		return []rune{event.Rune()}
+	case tcell.KeyEnter:
+		return []rune{'\n'}
+	case tcell.KeyTab:
+		return []rune{'\t'}
+	}
+	return nil
+}
+
+// paste delivers pasted text to the focused primitive, as a whole if it
+// implements Paster and as individual key events otherwise.
+func (a *Application) paste(text string) {
+	a.RLock()
+	p := a.focus
+	a.RUnlock()
+	if p == nil {
+		return
+	}
+	setFocus := func(p Primitive) {
+		a.SetFocus(p)
+	}
+	if paster, ok := p.(Paster); ok {
+		if handler := paster.PasteHandler(); handler != nil {
+			handler(text, setFocus)
+			markDirty(p)
+			a.Draw()
+			return
+		}
+	}
+	for _, ch := range text {
+		event := tcell.NewEventKey(tcell.KeyRune, ch, tcell.ModNone)
+		switch ch {
+		case '\n':
+			event = tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)
+		case '\t':
+			event = tcell.NewEventKey(tcell.KeyTab, 0, tcell.ModNone)
+		}
+		a.handleKey(event)
+	}
+}
+
+// handleKey processes a key event: it is passed through the input capture
+// function, shortcuts, and focus navigation and then to the focused
+// primitive.
+func (a *Application) handleKey(event *tcell.EventKey) {
+	a.RLock()
+	p := a.focus
+	quitKeys, suspendKey := a.quitKeys, a.suspendKey
+	a.RUnlock()
+
+	// Intercept keys.
+	if a.inputCapture != nil {
+		event = a.inputCapture(event)
+		if event == nil {
+			return // Don't forward event.
+		}
+	}
+
+	// The quit keys close the application.
+	for _, key := range quitKeys {
+		if event.Key() == key {
+			a.Stop()
+			break
+		}
+	}
+
+	// The suspend key stops the process.
+	if suspendKey != tcell.KeyNUL && event.Key() == suspendKey {
+		a.Suspend(stopProcess)
+		return
+	}
+
+	// Shortcuts of the focused primitives.
+	if a.triggerShortcut(p, event) {
+		a.redrawAll()
+		return
+	}
+
+	// Tab and Backtab may move the focus.
+	if key := event.Key(); (key == tcell.KeyTab || key == tcell.KeyBacktab) && a.tabFocus(p, key == tcell.KeyTab) {
+		a.Draw()
+		return
+	}
+
+	// Alt+arrow keys may move the focus.
+	if event.Modifiers()&tcell.ModAlt != 0 && a.moveFocus(p, event.Key()) {
+		a.Draw()
+		return
+	}
+
+	// Ctrl+arrow keys may move the boundaries of a resizable flex.
+	if event.Modifiers()&tcell.ModCtrl != 0 && a.resizeFlex(p, event) {
+		a.Draw()
+		return
+	}
+
+	// Pass other key events to the currently focused primitive.
+	if p != nil {
+		if handler := p.InputHandler(); handler != nil {
+			handler(event, func(p Primitive) {
+				a.SetFocus(p)
+			})
+
+			// Maybe only the focused primitive needs to be redrawn.
+			a.RLock()
+			partial := a.dirtyTracking || a.focusedRedraw && a.focus == p && a.state.onlyDirty(a.root, p)
+			a.RUnlock()
+			a.draw(partial)
+		}
+	}
+}
+
+// GetShortcuts returns the shortcuts (see Box.AddShortcut()) which are
+// currently available: those of the focused primitive, followed by those of
+// the primitives containing it, innermost first. The result can be shown to
+// the user, e.g. in a StatusBar or a HelpOverlay:
+//
+//	help.ClearBindings().AddBindings(app.GetShortcuts()...)
+func (a *Application) GetShortcuts() (bindings []KeyBinding) {
+	a.RLock()
+	root, focus := a.root, a.focus
+	a.RUnlock()
+	for _, box := range focusedShortcutBoxes(root, focus) {
+		bindings = append(bindings, box.GetShortcuts()...)
+	}
+	return
+}
+
+// triggerShortcut processes the given key event as part of the shortcuts of
+// the focused primitive and the primitives containing it (innermost first).
+// It calls the handler of a shortcut whose key sequence was completed and
+// remembers the keys of an incomplete sequence. It returns whether the event
+// was consumed.
+func (a *Application) triggerShortcut(focus Primitive, event *tcell.EventKey) bool {
+	a.RLock()
+	root, leader, timeout, screen := a.root, a.leaderKey, a.keySequenceTimeout, a.screen
+	a.RUnlock()
+
+	// Find the first shortcut completed by the keys and whether they may be
+	// continued.
+	keys := append(a.pendingKeys, keyStrokeOf(event))
+	var (
+		completed *shortcut
+		continued bool
+	)
+	for _, box := range focusedShortcutBoxes(root, focus) {
+		for _, s := range box.shortcuts {
+			full, prefix := s.match(keys, leader)
+			if full && completed == nil {
+				completed = s
+			}
+			continued = continued || prefix
+		}
+	}
+
+	// Wait for more keys.
+	if continued {
+		a.pendingKeys, a.pendingShortcut = keys, completed
+		a.pendingGeneration++
+		if screen != nil && timeout > 0 {
+			generation := a.pendingGeneration
+			time.AfterFunc(timeout, func() {
+				screen.PostEvent(&keySequenceTimeoutEvent{generation: generation})
+			})
+		}
+		return true
+	}
+
+	// The sequence is complete or broken off.
+	pending := a.pendingShortcut
+	a.pendingKeys, a.pendingShortcut = nil, nil
+	if completed != nil {
+		if completed.handler != nil {
+			completed.handler()
+		}
+		return true
+	}
+	if len(keys) > 1 {
+		// The pending keys don't continue with this key. Finish what they
+		// completed and start over with this key.
+		if pending != nil && pending.handler != nil {
+			pending.handler()
+		}
+		return a.triggerShortcut(focus, event)
+	}
+	return false
+}
+
+// focusedShortcutBoxes returns the boxes with shortcuts among the focused
+// primitive and the primitives which contain it, innermost first.
+func focusedShortcutBoxes(root, focus Primitive) (boxes []*Box) {
+	if focus == nil {
+		return nil
+	}
+	path := []Primitive{focus}
+	if root != nil {
+		if p := primitivePath(root, focus); p != nil {
+			path = p
+		}
+	}
+	for index := len(path) - 1; index >= 0; index-- {
+		b, ok := path[index].(interface {
+			getBox() *Box
+		})
+		if ok && len(b.getBox().shortcuts) > 0 && !isDisabled(path[index]) {
+			boxes = append(boxes, b.getBox())
+		}
+	}
+	return
+}
+
+// resizeFlex passes the given key event to the flexes containing the focused
+// primitive, innermost first, until one of them moves a boundary between its
+// items (see Flex.SetResizable()). It returns whether a boundary was moved.
+func (a *Application) resizeFlex(focus Primitive, event *tcell.EventKey) bool {
+	a.RLock()
+	root := a.root
+	a.RUnlock()
+	if root == nil || focus == nil {
+		return false
+	}
+	path := primitivePath(root, focus)
+	for index := len(path) - 1; index >= 0; index-- {
+		if flex, ok := path[index].(*Flex); ok && flex.resizeWithKey(event) {
+			return true
+		}
+	}
+	return false
+}
+
+// tabFocus moves the focus away from the given primitive for Tab (if
+// "forward" is true) or Backtab, within the innermost focus group which
+// contains it or, if the groups let it leave, between the stops on the screen
+// if Tab navigation is enabled. It returns whether the focus was moved.
+func (a *Application) tabFocus(focus Primitive, forward bool) bool {
+	a.RLock()
+	root, enabled := a.root, a.tabNavigation
+	a.RUnlock()
+	if root == nil || focus == nil {
+		return false
+	}
+
+	// Move within the focus groups, innermost first.
+	groups := focusGroupsOf(root, focus)
+	for index := len(groups) - 1; index >= 0; index-- {
+		group := groups[index]
+		group.last = focus
+		next, wrapped := nextFocusStop(group, focus, forward)
+		if next != nil && (!wrapped || group.trap) {
+			a.SetFocus(next)
+			return true
+		}
+	}
+
+	// Move between all stops.
+	if enabled {
+		if next, _ := nextFocusStop(root, focus, forward); next != nil {
+			a.SetFocus(next)
+			return true
+		}
+	}
+	return false
+}
+
+// moveFocus moves the focus from the given primitive to the nearest stop (see
+// focusStops()) in the direction of the given arrow key, if directional focus
+// is enabled. It returns whether the focus was moved.
+func (a *Application) moveFocus(focus Primitive, key tcell.Key) bool {
+	a.RLock()
+	root, enabled := a.root, a.directionalFocus
+	a.RUnlock()
+	if !enabled || root == nil || focus == nil {
+		return false
+	}
+	switch key {
+	case tcell.KeyLeft, tcell.KeyRight, tcell.KeyUp, tcell.KeyDown:
+	default:
+		return false
+	}
+
+	// Find the nearest stop. The distance in the pressed direction counts
+	// less than the offset across it.
+	x, y, width, height := focus.GetRect()
+	var (
+		nearest                     Primitive
+		nearestScore, nearestOffset int
+	)
+	for _, stop := range focusStops(root) {
+		if stop == focus || containsPrimitive(stop, focus) {
+			continue
+		}
+		sx, sy, sw, sh := stop.GetRect()
+		var distance, from, to, stopFrom, stopTo int
+		switch key {
+		case tcell.KeyLeft:
+			distance = x - (sx + sw)
+		case tcell.KeyRight:
+			distance = sx - (x + width)
+		case tcell.KeyUp:
+			distance = y - (sy + sh)
+		case tcell.KeyDown:
+			distance = sy - (y + height)
+		}
+		if distance < 0 {
+			continue // Not in this direction.
+		}
+		if key == tcell.KeyLeft || key == tcell.KeyRight {
+			from, to, stopFrom, stopTo = y, y+height, sy, sy+sh
+		} else {
+			from, to, stopFrom, stopTo = x, x+width, sx, sx+sw
+		}
+
+		// The gap across the direction (0 if the two overlap) and the
+		// distance between the centers (doubled).
+		var across int
+		if stopFrom >= to {
+			across = stopFrom - to
+		} else if from >= stopTo {
+			across = from - stopTo
+		}
+		offset := from + to - stopFrom - stopTo
+		if offset < 0 {
+			offset = -offset
+		}
+		score := distance + 2*across
+		if nearest == nil || score < nearestScore || score == nearestScore && offset < nearestOffset {
+			nearest, nearestScore, nearestOffset = stop, score, offset
+		}
+	}
+	if nearest == nil {
+		return false
+	}
+	a.SetFocus(nearest)
+	return true
+}
+
+// handleMouse translates a mouse event into mouse actions, passes them on to
+// the primitives, and redraws the screen if they were consumed.
+func (a *Application) handleMouse(event *tcell.EventMouse) {
+	consumed, isMouseDownAction := a.fireMouseActions(event)
+	if consumed {
+		a.Draw()
+	}
+	a.lastMouseButtons = event.Buttons()
+	if isMouseDownAction {
+		a.mouseDownX, a.mouseDownY = event.Position()
+	}
+}
+
+// fireMouseActions analyzes the provided mouse event, derives mouse actions
+// from it and then forwards them to the corresponding primitives.
+func (a *Application) fireMouseActions(event *tcell.EventMouse) (consumed, isMouseDownAction bool) {
+	// We want to relay follow-up events to the same target primitive.
+	var targetPrimitive Primitive
+
+	// Helper function to fire a mouse action.
+	fire := func(action MouseAction) {
+		switch action {
+		case MouseLeftDown, MouseMiddleDown, MouseRightDown:
+			isMouseDownAction = true
+		}
+
+		// Determine the target primitive.
+		a.RLock()
+		root := a.root
+		a.RUnlock()
+		var primitive, capturingPrimitive Primitive
+		if a.mouseCapturingPrimitive != nil {
+			primitive = a.mouseCapturingPrimitive
+			targetPrimitive = a.mouseCapturingPrimitive
+		} else if targetPrimitive != nil {
+			primitive = targetPrimitive
+		} else {
+			primitive = root
+		}
+		if primitive != nil {
+			if handler := mouseHandler(primitive); handler != nil {
+				var wasConsumed bool
+				wasConsumed, capturingPrimitive = handler(action, event, func(p Primitive) {
+					a.SetFocus(p)
+				})
+				if wasConsumed {
+					consumed = true
+				}
+			}
+		}
+		a.mouseCapturingPrimitive = capturingPrimitive
+	}
+
+	x, y := event.Position()
+	buttons := event.Buttons()
+	clickMoved := x != a.mouseDownX || y != a.mouseDownY
+	buttonChanges := buttons ^ a.lastMouseButtons
+
+	if x != a.lastMouseX || y != a.lastMouseY {
+		if a.state.leaveHoveredBoxes(x, y) {
+			consumed = true // Redraw the boxes which are no longer hovered.
+		}
+		fire(MouseMove)
+		a.lastMouseX = x
+		a.lastMouseY = y
+	}
+
+	for _, buttonEvent := range []struct {
+		button                  tcell.ButtonMask
+		down, up, click, dclick MouseAction
+	}{
+		{tcell.Button1, MouseLeftDown, MouseLeftUp, MouseLeftClick, MouseLeftDoubleClick},
+		{tcell.Button2, MouseMiddleDown, MouseMiddleUp, MouseMiddleClick, MouseMiddleDoubleClick},
+		{tcell.Button3, MouseRightDown, MouseRightUp, MouseRightClick, MouseRightDoubleClick},
+	} {
+		if buttonChanges&buttonEvent.button != 0 {
+			if buttons&buttonEvent.button != 0 {
+				fire(buttonEvent.down)
+			} else {
+				fire(buttonEvent.up)
+				if !clickMoved {
+					if a.lastMouseClick.Add(DoubleClickInterval).Before(time.Now()) {
+						fire(buttonEvent.click)
+						a.lastMouseClick = time.Now()
+					} else {
+						fire(buttonEvent.dclick)
+						a.lastMouseClick = time.Time{} // reset
+					}
+				}
+			}
+		}
+	}
+
+	for _, wheelEvent := range []struct {
+		button tcell.ButtonMask
+		action MouseAction
+	}{
+		{tcell.WheelUp, MouseScrollUp},
+		{tcell.WheelDown, MouseScrollDown},
+		{tcell.WheelLeft, MouseScrollLeft},
+		{tcell.WheelRight, MouseScrollRight},
+	} {
+		if buttons&wheelEvent.button != 0 {
+			fire(wheelEvent.action)
+		}
+	}
+
+	return consumed, isMouseDownAction
+}
+
+// Stop stops the application, causing Run() to return. If the application is
+// suspended, Run() returns when the function called by Suspend() returns.
+func (a *Application) Stop() {
+	a.Lock()
+	defer a.Unlock()
+	a.resumed = nil // Don't resume a suspended application.
 	if a.screen == nil {
 		return
 	}
+	if a.bracketedPaste {
+		setBracketedPaste(false)
+	}
 	a.screen.Fini()
 	a.screen = nil
 }
@@ -159,12 +1311,25 @@ func (a *Application) Stop() {
 // Draw refreshes the screen. It calls the Draw() function of the application's
 // root primitive and then syncs the screen buffer.
 func (a *Application) Draw() *Application {
+	a.RLock()
+	partial := a.dirtyTracking
+	a.RUnlock()
+	return a.draw(partial)
+}
+
+// draw refreshes the screen. If "partial" is true, only the primitives marked
+// as changed are redrawn if possible (see drawDirty()).
+func (a *Application) draw(partial bool) *Application {
 	a.RLock()
 	screen := a.screen
 	root := a.root
 	fullscreen := a.rootFullscreen
 	before := a.beforeDraw
 	after := a.afterDraw
+	stats := a.drawStats
+	shadow := a.shadow
+	focus := a.focus
+	indicator, indicatorColor := a.focusIndicator, a.focusIndicatorColor
 	a.RUnlock()
 
 	// Maybe we're not ready yet or not anymore.
@@ -172,9 +1337,26 @@ func (a *Application) Draw() *Application {
 		return a
 	}
 
+	// Only one goroutine draws at a time.
+	state := a.state
+	state.drawing.Lock()
+	defer state.drawing.Unlock()
+
+	// Find out if everything needs to be drawn.
+	width, height := screen.Size()
+	a.Lock()
+	full := !partial || a.fullDraw || width != a.drawnWidth || height != a.drawnHeight
+	a.fullDraw, a.drawnWidth, a.drawnHeight = false, width, height
+	a.Unlock()
+
+	// Draw into the buffer if only changed cells are passed on.
+	if shadow != nil {
+		screen = shadow.wrap(screen)
+	}
+	start, draws := time.Now(), state.boxDraws
+
 	// Resize if requested.
 	if fullscreen && root != nil {
-		width, height := screen.Size()
 		root.SetRect(0, 0, width, height)
 	}
 
@@ -186,8 +1368,15 @@ func (a *Application) Draw() *Application {
 		}
 	}
 
-	// Draw all primitives.
-	root.Draw(screen)
+	// Draw all primitives (or only the changed ones), then their overlays.
+	partial = !full && state.drawDirty(root)
+	if !partial {
+		atomic.AddUint64(&state.drawCount, 1)
+		state.dimmedRects, state.drawnBoxes = state.dimmedRects[:0], 0
+		root.Draw(&appScreen{Screen: screen, state: state})
+	}
+	state.drawOverlays()
+	drawFocusIndicator(screen, state, focus, indicator, indicatorColor)
 
 	// Call after handler if there is one.
 	if after != nil {
@@ -195,11 +1384,47 @@ func (a *Application) Draw() *Application {
 	}
 
 	// Sync screen.
+	drawn := time.Now()
 	screen.Show()
+	state.writeSixels(os.Stdout)
+
+	// Report statistics.
+	if stats != nil {
+		stats(DrawStats{
+			Duration:     drawn.Sub(start),
+			ShowDuration: time.Since(drawn),
+			Primitives:   int(state.boxDraws - draws),
+			Partial:      partial,
+		})
+	}
+
+	return a
+}
 
+// SetDrawStatsFunc installs a callback function which is invoked after each
+// frame with statistics about it, e.g. to measure the effect of changes on
+// drawing performance or to show the frame rate or latency to the user. The
+// function is called from the goroutine which drew the frame and must not
+// call Draw() itself.
+//
+// Provide nil to uninstall the callback function.
+func (a *Application) SetDrawStatsFunc(handler func(stats DrawStats)) *Application {
+	a.Lock()
+	defer a.Unlock()
+	a.drawStats = handler
 	return a
 }
 
+// redrawAll redraws the entire screen, e.g. after handlers which may have
+// changed any primitive, even with dirty tracking enabled (see
+// EnableDirtyTracking()).
+func (a *Application) redrawAll() {
+	a.Lock()
+	a.fullDraw = true
+	a.Unlock()
+	a.Draw()
+}
+
 // SetBeforeDrawFunc installs a callback function which is invoked just before
 // the root primitive is drawn during screen updates. If the function returns
 // true, drawing will not continue, i.e. the root primitive will not be drawn
@@ -234,6 +1459,7 @@ func (a *Application) SetRoot(root Primitive, fullscreen bool) *Application {
 	a.Lock()
 	a.root = root
 	a.rootFullscreen = fullscreen
+	a.fullDraw = true
 	if a.screen != nil {
 		a.screen.Clear()
 	}
@@ -244,6 +1470,37 @@ func (a *Application) SetRoot(root Primitive, fullscreen bool) *Application {
 	return a
 }
 
+// SetTheme replaces the global Styles with the given theme and applies it to
+// all primitives reachable from the root primitive (see WalkTree()), including
+// those which are currently not visible. Then the screen is redrawn.
+//
+// Only colors which were taken from the previous theme are changed. Colors
+// which were set explicitly, e.g. with SetBackgroundColor(), remain as they are
+// unless they happen to be identical to a color of the previous theme.
+// Primitives which are not part of the tree (e.g. pages that were removed)
+// keep their colors. Table cells are themed only in their text color. The
+// colors in Theme.Widgets are looked up using the type name of each primitive.
+func (a *Application) SetTheme(theme Theme) *Application {
+	a.Lock()
+	previous := Styles
+	Styles = theme
+	root := a.root
+	a.fullDraw = true
+	a.Unlock()
+
+	if root != nil {
+		WalkTree(root, func(p, parent Primitive) bool {
+			if t, ok := p.(themeable); ok {
+				name := reflect.Indirect(reflect.ValueOf(p)).Type().Name()
+				t.applyTheme(previous.forWidget(name), theme.forWidget(name))
+			}
+			return true
+		})
+	}
+
+	return a.Draw()
+}
+
 // ResizeToFullScreen resizes the given primitive such that it fills the entire
 // screen.
 func (a *Application) ResizeToFullScreen(p Primitive) *Application {
@@ -259,13 +1516,16 @@ func (a *Application) ResizeToFullScreen(p Primitive) *Application {
 // events.
 //
 // Blur() will be called on the previously focused primitive. Focus() will be
-// called on the new primitive.
+// called on the new primitive. Afterwards, the handlers set with
+// Box.SetBlurFunc() and Box.SetFocusFunc() are called for the primitives
+// whose focus state changed.
 func (a *Application) SetFocus(p Primitive) *Application {
 	a.Lock()
 	if a.focus != nil {
 		a.focus.Blur()
 	}
 	a.focus = p
+	a.fullDraw = true
 	if a.screen != nil {
 		a.screen.HideCursor()
 	}
@@ -274,6 +1534,11 @@ func (a *Application) SetFocus(p Primitive) *Application {
 		a.SetFocus(p)
 	})
 
+	a.RLock()
+	root, focus := a.root, a.focus
+	a.RUnlock()
+	a.state.notifyFocus(root, focus)
+
 	return a
 }
 
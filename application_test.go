@@ -0,0 +1,103 @@
+package tview
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell"
+)
+
+// newTestApplication returns an application which draws the given root
+// primitive onto a simulation screen of the given size.
+func newTestApplication(t *testing.T, root Primitive, width, height int) (*Application, tcell.SimulationScreen) {
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	screen.SetSize(width, height)
+	app := NewApplication().SetRoot(root, true)
+	app.screen = screen
+	return app, screen
+}
+
+// screenLine returns the text in the given row of the screen.
+func screenLine(screen tcell.Screen, y int) string {
+	width, _ := screen.Size()
+	var line strings.Builder
+	for x := 0; x < width; x++ {
+		ch, _, _, _ := screen.GetContent(x, y)
+		line.WriteRune(ch)
+	}
+	return line.String()
+}
+
+// mouseActions returns the mouse actions which a box receives when the given
+// mouse events are processed by an application.
+func mouseActions(events ...*tcell.EventMouse) []MouseAction {
+	var actions []MouseAction
+	box := NewBox()
+	box.SetRect(0, 0, 10, 10)
+	box.SetMouseCapture(func(action MouseAction, event *tcell.EventMouse) (MouseAction, *tcell.EventMouse) {
+		if action != MouseMove {
+			actions = append(actions, action)
+		}
+		return action, event
+	})
+	app := NewApplication().SetRoot(box, false)
+	for _, event := range events {
+		app.handleMouse(event)
+	}
+	return actions
+}
+
+func TestMouseButtons(t *testing.T) {
+	for _, test := range []struct {
+		button tcell.ButtonMask
+		click  MouseAction
+	}{
+		{tcell.Button1, MouseLeftClick},
+		{tcell.Button2, MouseMiddleClick},
+		{tcell.Button3, MouseRightClick},
+	} {
+		actions := mouseActions(
+			tcell.NewEventMouse(1, 1, test.button, tcell.ModNone),
+			tcell.NewEventMouse(1, 1, tcell.ButtonNone, tcell.ModNone),
+		)
+		if len(actions) != 3 || actions[2] != test.click {
+			t.Errorf("button %d: got actions %v, want a click action %d", test.button, actions, test.click)
+		}
+	}
+}
+
+func TestPasteMarkerTimeout(t *testing.T) {
+	app := NewApplication()
+	app.bracketedPaste = true
+
+	// A lone Alt+[ is held back as the possible start of a paste marker.
+	altBracket := tcell.NewEventKey(tcell.KeyRune, '[', tcell.ModAlt)
+	if events := app.scanPaste(altBracket); len(events) != 0 {
+		t.Fatalf("Alt+[ was not held back: %v", events)
+	}
+
+	// When the marker times out, the key is processed after all.
+	if events := app.flushPasteMarker(); len(events) != 1 || events[0] != altBracket {
+		t.Errorf("flushing the paste marker returned %v, want the Alt+[ key", events)
+	}
+	if len(app.pasteMarker) != 0 || app.pasting {
+		t.Error("the paste marker was not reset")
+	}
+
+	// A complete marker still starts pasting.
+	for _, r := range "[200~" {
+		modifiers := tcell.ModNone
+		if r == '[' {
+			modifiers = tcell.ModAlt
+		}
+		if events := app.scanPaste(tcell.NewEventKey(tcell.KeyRune, r, modifiers)); len(events) != 0 {
+			t.Fatalf("marker key %q was not consumed", r)
+		}
+	}
+	if !app.pasting {
+		t.Error("the paste start marker was not recognized")
+	}
+}
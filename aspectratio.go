@@ -0,0 +1,156 @@
+package tview
+
+import (
+	"math"
+
+	"github.com/gdamore/tcell"
+)
+
+// AspectRatio is a container which gives a primitive the largest size that
+// fits into the available space while keeping a fixed ratio between its width
+// and its height, e.g. for image previews, square canvases, or game boards.
+//
+// Terminal cells are usually about twice as high as they are wide. The ratio
+// is therefore applied to the physical size of the primitive, taking the
+// height of a cell relative to its width into account (see SetCellAspect()).
+// A ratio of 1:1 with the default cell aspect of 2 results in a primitive
+// which is twice as many cells wide as it is high.
+type AspectRatio struct {
+	*Box
+
+	// The contained primitive.
+	item Primitive
+
+	// The ratio between the primitive's width and height.
+	ratioWidth, ratioHeight int
+
+	// The height of a cell divided by its width.
+	cellAspect float64
+
+	// The horizontal and vertical alignment of the primitive.
+	horizontal, vertical int
+}
+
+// NewAspectRatio returns a new container which keeps the given primitive at a
+// ratio of "width" to "height", e.g. 16 and 9.
+func NewAspectRatio(item Primitive, width, height int) *AspectRatio {
+	a := &AspectRatio{
+		Box:         NewBox(),
+		item:        item,
+		ratioWidth:  width,
+		ratioHeight: height,
+		cellAspect:  2,
+		horizontal:  AlignCenter,
+		vertical:    AlignCenter,
+	}
+	a.focus = a
+	return a
+}
+
+// SetItem sets the contained primitive.
+func (a *AspectRatio) SetItem(item Primitive) *AspectRatio {
+	a.item = item
+	return a
+}
+
+// GetItem returns the contained primitive.
+func (a *AspectRatio) GetItem() Primitive {
+	return a.item
+}
+
+// SetRatio sets the ratio of the primitive's width to its height.
+func (a *AspectRatio) SetRatio(width, height int) *AspectRatio {
+	a.ratioWidth, a.ratioHeight = width, height
+	return a
+}
+
+// SetCellAspect sets the height of a terminal cell divided by its width. The
+// default is 2. Use 1 to apply the ratio to the number of cells directly.
+func (a *AspectRatio) SetCellAspect(aspect float64) *AspectRatio {
+	a.cellAspect = aspect
+	return a
+}
+
+// SetAlign sets the position of the primitive within the available space if
+// there is space left over. "horizontal" is one of AlignLeft, AlignCenter, or
+// AlignRight and "vertical" is one of AlignTop, AlignCenter, or AlignBottom.
+// The default is to center the primitive in both directions.
+func (a *AspectRatio) SetAlign(horizontal, vertical int) *AspectRatio {
+	a.horizontal, a.vertical = horizontal, vertical
+	return a
+}
+
+// GetChildren returns the contained primitive.
+func (a *AspectRatio) GetChildren() []Primitive {
+	if a.item == nil {
+		return nil
+	}
+	return []Primitive{a.item}
+}
+
+// Draw draws this primitive onto the screen.
+func (a *AspectRatio) Draw(screen tcell.Screen) {
+	a.Box.Draw(screen)
+	if a.item == nil {
+		return
+	}
+	x, y, width, height := a.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	// Find the largest size with the requested ratio.
+	itemWidth, itemHeight := width, height
+	if a.ratioWidth > 0 && a.ratioHeight > 0 && a.cellAspect > 0 {
+		cellsPerRow := float64(a.ratioWidth) / float64(a.ratioHeight) * a.cellAspect
+		itemWidth = int(math.Floor(float64(height)*cellsPerRow + 0.5))
+		if itemWidth > width {
+			itemWidth = width
+		}
+		itemHeight = int(math.Floor(float64(itemWidth)/cellsPerRow + 0.5))
+		if itemHeight > height {
+			itemHeight = height
+		}
+		if itemWidth < 1 {
+			itemWidth = 1
+		}
+		if itemHeight < 1 {
+			itemHeight = 1
+		}
+	}
+
+	x, itemWidth = place(itemWidth, a.horizontal, x, width)
+	y, itemHeight = place(itemHeight, a.vertical, y, height)
+	a.item.SetRect(x, y, itemWidth, itemHeight)
+	a.item.Draw(screen)
+}
+
+// Focus is called when this primitive receives focus.
+func (a *AspectRatio) Focus(delegate func(p Primitive)) {
+	if a.item != nil {
+		delegate(a.item)
+		return
+	}
+	a.hasFocus = true
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (a *AspectRatio) HasFocus() bool {
+	if a.item != nil {
+		return a.item.GetFocusable().HasFocus()
+	}
+	return a.hasFocus
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (a *AspectRatio) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return a.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if !a.InRect(event.Position()) || a.item == nil {
+			return false, nil
+		}
+		if handler := mouseHandler(a.item); handler != nil {
+			return handler(action, event, setFocus)
+		}
+		return
+	})
+}
@@ -0,0 +1,135 @@
+package tview
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell"
+)
+
+// newBenchmarkScreen returns an initialized simulation screen of the given
+// size.
+func newBenchmarkScreen(b *testing.B, width, height int) tcell.SimulationScreen {
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		b.Fatal(err)
+	}
+	screen.SetSize(width, height)
+	return screen
+}
+
+// benchmarkLines returns lines of text with color tags.
+func benchmarkLines(count int) []string {
+	lines := make([]string, count)
+	for index := range lines {
+		lines[index] = fmt.Sprintf("[yellow]%5d[-] The quick [red]brown[-] fox jumps over the [::b]lazy[::-] dog. %s\n", index, strings.Repeat("x", index%40))
+	}
+	return lines
+}
+
+func BenchmarkTextViewWrite(b *testing.B) {
+	lines := benchmarkLines(1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		textView := NewTextView().SetDynamicColors(true)
+		for _, line := range lines {
+			fmt.Fprint(textView, line)
+		}
+	}
+}
+
+func BenchmarkTextViewDraw(b *testing.B) {
+	screen := newBenchmarkScreen(b, 80, 24)
+	textView := NewTextView().SetDynamicColors(true).SetWrap(true)
+	for _, line := range benchmarkLines(1000) {
+		fmt.Fprint(textView, line)
+	}
+	textView.SetRect(0, 0, 80, 24)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		textView.Draw(screen)
+	}
+}
+
+func BenchmarkTableDraw(b *testing.B) {
+	screen := newBenchmarkScreen(b, 120, 40)
+	table := NewTable().SetBorders(true).SetFixed(1, 1).SetSelectable(true, false)
+	for row := 0; row < 1000; row++ {
+		for column := 0; column < 10; column++ {
+			table.SetCell(row, column, NewTableCell(fmt.Sprintf("[green]%d[-]/%d", row, column)))
+		}
+	}
+	table.SetRect(0, 0, 120, 40)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		table.Select(n%1000, 0)
+		table.Draw(screen)
+	}
+}
+
+func BenchmarkFlexDraw(b *testing.B) {
+	screen := newBenchmarkScreen(b, 200, 60)
+	root := NewFlex().SetDirection(FlexRow)
+	for row := 0; row < 10; row++ {
+		columns := NewFlex()
+		for column := 0; column < 10; column++ {
+			box := NewBox().SetBorder(true).SetTitle(fmt.Sprintf("%d/%d", row, column))
+			columns.AddItem(box, 0, 1, false)
+		}
+		root.AddItem(columns, 0, 1, false)
+	}
+	root.SetRect(0, 0, 200, 60)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		root.Draw(screen)
+	}
+}
+
+// benchmarkApplicationDraw draws an application with a large layout after
+// key events processed by an input field.
+func benchmarkApplicationDraw(b *testing.B, setup func(app *Application)) {
+	app := NewApplication()
+	app.screen = newBenchmarkScreen(b, 200, 60)
+	input := NewInputField()
+	root := NewFlex().SetDirection(FlexRow).AddItem(input, 1, 0, true)
+	for row := 0; row < 10; row++ {
+		columns := NewFlex()
+		for column := 0; column < 10; column++ {
+			columns.AddItem(NewTextView().SetText(strings.Repeat("Some text. ", 50)).SetWrap(true), 0, 1, false)
+		}
+		root.AddItem(columns, 0, 1, false)
+	}
+	app.SetRoot(root, true)
+	setup(app)
+	app.Draw()
+	event := tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModNone)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if n%50 == 0 {
+			input.SetText("")
+		}
+		app.handleKey(event)
+	}
+}
+
+func BenchmarkApplicationDraw(b *testing.B) {
+	benchmarkApplicationDraw(b, func(app *Application) {})
+}
+
+func BenchmarkApplicationDrawDirty(b *testing.B) {
+	benchmarkApplicationDraw(b, func(app *Application) {
+		app.EnableDirtyTracking(true)
+	})
+}
+
+func BenchmarkApplicationDrawCellDiffing(b *testing.B) {
+	benchmarkApplicationDraw(b, func(app *Application) {
+		app.EnableCellDiffing(true)
+	})
+}
@@ -0,0 +1,264 @@
+package tview
+
+import (
+	"bytes"
+	"unicode"
+)
+
+// Bidirectional character classes, reduced to what the simplified algorithm
+// in bidiReorder() distinguishes.
+const (
+	bidiL       = iota // Left-to-right letters.
+	bidiR              // Right-to-left letters (Hebrew, Arabic, etc.).
+	bidiEN             // European numbers.
+	bidiAN             // Arabic numbers.
+	bidiNSM            // Non-spacing marks.
+	bidiNeutral        // Everything else, e.g. whitespace and punctuation.
+)
+
+// bidiMirrors maps characters to their mirrored counterparts which are shown
+// in right-to-left text.
+var bidiMirrors = map[rune]rune{
+	'(': ')', ')': '(',
+	'[': ']', ']': '[',
+	'{': '}', '}': '{',
+	'<': '>', '>': '<',
+	'«': '»', '»': '«',
+}
+
+// bidiClass returns the bidirectional class of the given character.
+func bidiClass(ch rune) int {
+	switch {
+	case ch >= '0' && ch <= '9':
+		return bidiEN
+	case ch >= 0x660 && ch <= 0x669 || ch >= 0x6f0 && ch <= 0x6f9:
+		return bidiAN
+	case unicode.In(ch, unicode.Mn, unicode.Me):
+		return bidiNSM
+	case unicode.In(ch, unicode.Hebrew, unicode.Arabic, unicode.Syriac, unicode.Thaana, unicode.Nko):
+		return bidiR
+	case unicode.IsLetter(ch) || unicode.IsDigit(ch) || unicode.Is(unicode.Mc, ch):
+		return bidiL
+	}
+	return bidiNeutral
+}
+
+// bidiReorder determines the visual order of the given runes which make up a
+// single line of text. It implements a simplified version of the Unicode
+// Bidirectional Algorithm (UAX #9) without explicit embeddings: The paragraph
+// direction is taken from the first strong character, neutral characters take
+// the direction of their surroundings, numbers are always shown left to right,
+// and grapheme clusters are kept intact.
+//
+// The returned slice contains the indices of the runes in visual order. The
+// second slice indicates for each rune whether it is shown right to left, in
+// which case brackets should be mirrored (see bidiMirror()). If the text does
+// not contain any right-to-left characters, nil slices are returned.
+func bidiReorder(runes []rune) (order []int, rtl []bool) {
+	classes := make([]int, len(runes))
+	var hasRTL bool
+	for index, ch := range runes {
+		classes[index] = bidiClass(ch)
+		if classes[index] == bidiR || classes[index] == bidiAN {
+			hasRTL = true
+		}
+	}
+	if !hasRTL {
+		return nil, nil
+	}
+
+	// The paragraph direction is that of the first strong character.
+	base, baseClass := 0, bidiL
+	for _, class := range classes {
+		if class == bidiL {
+			break
+		}
+		if class == bidiR {
+			base, baseClass = 1, bidiR
+			break
+		}
+	}
+
+	// Non-spacing marks take the class of the preceding character. European
+	// numbers following left-to-right text are treated as left-to-right text.
+	lastStrong := baseClass
+	for index, class := range classes {
+		if class == bidiNSM {
+			class = bidiNeutral
+			if index > 0 {
+				class = classes[index-1]
+			}
+			classes[index] = class
+		}
+		switch class {
+		case bidiL, bidiR:
+			lastStrong = class
+		case bidiEN:
+			if lastStrong == bidiL {
+				classes[index] = bidiL
+			}
+		}
+	}
+
+	// Neutral characters take the direction of the surrounding text if it is
+	// the same on both sides, otherwise the paragraph direction. Numbers count
+	// as right-to-left text here.
+	direction := func(class int) int {
+		if class == bidiL {
+			return bidiL
+		}
+		return bidiR
+	}
+	for index := 0; index < len(classes); {
+		if classes[index] != bidiNeutral {
+			index++
+			continue
+		}
+		end := index
+		for end < len(classes) && classes[end] == bidiNeutral {
+			end++
+		}
+		before, after := baseClass, baseClass
+		if index > 0 {
+			before = direction(classes[index-1])
+		}
+		if end < len(classes) {
+			after = direction(classes[end])
+		}
+		resolved := baseClass
+		if before == after {
+			resolved = before
+		}
+		for ; index < end; index++ {
+			classes[index] = resolved
+		}
+	}
+
+	// Resolve the embedding levels. Trailing whitespace is reset to the
+	// paragraph level.
+	levels := make([]int, len(runes))
+	for index, class := range classes {
+		level := base
+		switch {
+		case base == 0 && class == bidiR:
+			level = 1
+		case base == 0 && (class == bidiEN || class == bidiAN):
+			level = 2
+		case base == 1 && class != bidiR:
+			level = 2
+		}
+		levels[index] = level
+	}
+	for index := len(runes) - 1; index >= 0 && unicode.IsSpace(runes[index]); index-- {
+		levels[index] = base
+	}
+
+	// Group the runes into grapheme clusters.
+	type cluster struct {
+		from, to, level int
+	}
+	var (
+		clusters []cluster
+		maxLevel int
+	)
+	for index, start := range graphemeStarts(runes) {
+		if start {
+			clusters = append(clusters, cluster{from: index, level: levels[index]})
+			if levels[index] > maxLevel {
+				maxLevel = levels[index]
+			}
+		}
+		clusters[len(clusters)-1].to = index + 1
+	}
+
+	// Reverse the runs of clusters, from the highest level down to level 1.
+	for level := maxLevel; level >= 1; level-- {
+		for index := 0; index < len(clusters); {
+			if clusters[index].level < level {
+				index++
+				continue
+			}
+			end := index
+			for end < len(clusters) && clusters[end].level >= level {
+				end++
+			}
+			for left, right := index, end-1; left < right; left, right = left+1, right-1 {
+				clusters[left], clusters[right] = clusters[right], clusters[left]
+			}
+			index = end
+		}
+	}
+
+	// Produce the final order.
+	order = make([]int, 0, len(runes))
+	rtl = make([]bool, len(runes))
+	for _, c := range clusters {
+		for index := c.from; index < c.to; index++ {
+			order = append(order, index)
+			rtl[index] = c.level%2 == 1
+		}
+	}
+	return
+}
+
+// bidiMirror returns the mirrored counterpart of the given character, e.g. ')'
+// for '('. Characters which have no counterpart are returned unchanged.
+func bidiMirror(ch rune) rune {
+	if mirrored, ok := bidiMirrors[ch]; ok {
+		return mirrored
+	}
+	return ch
+}
+
+// reorderBidi returns the given line of text with its characters in visual
+// order (see bidiReorder()) such that printing it from left to right displays
+// right-to-left text correctly. Color tags are moved along with the characters
+// they apply to. Text without right-to-left characters is returned unchanged.
+func reorderBidi(text string) string {
+	runes := visibleRunes(text)
+	chs := make([]rune, len(runes))
+	for index, r := range runes {
+		chs[index] = r.ch
+	}
+	order, rtl := bidiReorder(chs)
+	if order == nil {
+		return text
+	}
+
+	// For each character, determine the number of color tags preceding it.
+	colorIndices := colorPattern.FindAllStringIndex(text, -1)
+	tagCounts := make([]int, len(runes))
+	var tag int
+	for index, r := range runes {
+		for tag < len(colorIndices) && colorIndices[tag][1] <= r.from {
+			tag++
+		}
+		tagCounts[index] = tag
+	}
+
+	// Write the characters in visual order. Whenever the tags preceding a
+	// character change, we reset the style and repeat its tags.
+	var (
+		buffer, run bytes.Buffer
+		current     int
+	)
+	for _, index := range order {
+		if tagCounts[index] != current {
+			buffer.WriteString(Escape(run.String()))
+			run.Reset()
+			buffer.WriteString("[-:-:-]")
+			for _, tag := range colorIndices[:tagCounts[index]] {
+				buffer.WriteString(text[tag[0]:tag[1]])
+			}
+			current = tagCounts[index]
+		}
+		ch := runes[index].ch
+		if rtl[index] {
+			ch = bidiMirror(ch)
+		}
+		run.WriteRune(ch)
+	}
+	buffer.WriteString(Escape(run.String()))
+
+	return buffer.String()
+}
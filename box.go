@@ -1,9 +1,54 @@
 package tview
 
 import (
+	"sync"
+	"sync/atomic"
+	"time"
+
 	"github.com/gdamore/tcell"
 )
 
+// shadowDimming is the factor by which the colors of cells in a box's shadow
+// are darkened (see Box.SetShadow()).
+const shadowDimming = 0.4
+
+// DrawLayer determines when a draw function added with Box.AddDrawFunc() is
+// called.
+type DrawLayer int
+
+// Overflow determines how a Box is drawn by a layout container when it is
+// smaller than its minimum size (see Box.SetMinSize()).
+type Overflow int
+
+// Overflow policies.
+const (
+	OverflowClip        Overflow = iota // Draw the box at its minimum size, clipped to the available space.
+	OverflowHide                        // Don't draw the box at all.
+	OverflowPlaceholder                 // Draw a "too small" placeholder instead of the box.
+)
+
+// TitleOverflow determines how a Box title which is wider than the box's
+// border is shown (see Box.SetTitleOverflow()).
+type TitleOverflow int
+
+// Title overflow modes.
+const (
+	TitleTruncateEnd   TitleOverflow = iota // Cut off the end of the title and show an ellipsis.
+	TitleTruncateStart                      // Cut off the beginning of the title and show an ellipsis, e.g. for file paths.
+	TitleMarquee                            // Scroll the title through the border.
+)
+
+// titleMarqueeGap is the space between the end and the beginning of a
+// scrolling title.
+const titleMarqueeGap = "   "
+
+// Draw layers, in the order in which they are drawn.
+const (
+	DrawBackground DrawLayer = iota // After the background was filled, before the border.
+	DrawContent                     // After the border and titles, before the primitive's content.
+	DrawOverlay                     // After the entire screen was drawn, on top of everything.
+)
+
 // Box implements Primitive with a background and optional elements such as a
 // border and a title. Most subclasses keep their content contained in the box
 // but don't necessarily have to.
@@ -23,9 +68,15 @@ type Box struct {
 	// Border padding.
 	paddingTop, paddingBottom, paddingLeft, paddingRight int
 
+	// Margins outside the border.
+	marginTop, marginBottom, marginLeft, marginRight int
+
 	// The box's background color.
 	backgroundColor tcell.Color
 
+	// The rune used to fill the background.
+	backgroundRune rune
+
 	// Whether or not a border is drawn, reducing the box's space for content by
 	// two in width and height.
 	border bool
@@ -42,6 +93,59 @@ type Box struct {
 	// The alignment of the title.
 	titleAlign int
 
+	// An optional second title shown in the bottom border, its color, and its
+	// alignment.
+	bottomTitle      string
+	bottomTitleColor tcell.Color
+	bottomTitleAlign int
+
+	// The colors of the border and the title while the box has focus. If they
+	// are tcell.ColorDefault, the colors above are used.
+	borderColorFocused, titleColorFocused tcell.Color
+
+	// Text attributes applied to the border and the title while the box has
+	// focus.
+	borderAttributesFocused, titleAttributesFocused tcell.AttrMask
+
+	// Text attributes applied to the titles.
+	titleAttributes tcell.AttrMask
+
+	// How titles which are too wide are shown.
+	titleOverflow TitleOverflow
+
+	// The time between two steps of a scrolling title, the function which
+	// causes a redraw, when the title started scrolling, and when the next
+	// redraw is due.
+	marqueeInterval time.Duration
+	marqueeRedraw   func()
+	marqueeStarted  time.Time
+	marqueeNext     time.Time
+
+	// Whether or not a shadow is drawn to the right of and below the box.
+	shadow bool
+
+	// The runes used to draw the border. If nil, Styles.Borders is used.
+	borderRunes *BorderRunes
+
+	// Whether or not the border is joined with box drawing runes already on
+	// the screen.
+	joinBorders bool
+
+	// Whether or not right-to-left text is reordered for display.
+	bidi bool
+
+	// Optional styles which replace the colors above (and the main text color
+	// of subclasses) when the box is drawn. They apply to the normal, focused,
+	// and disabled state, respectively.
+	style, focusStyle, disabledStyle *Style
+
+	// Whether or not the box is disabled. Disabled boxes ignore key and mouse
+	// events and cannot receive focus from containers such as Form.
+	disabled bool
+
+	// The main text color of disabled boxes without a disabled style.
+	disabledTextColor tcell.Color
+
 	// Provides a way to find out if this box has focus. We always go through
 	// this interface because it may be overridden by implementing classes.
 	focus Focusable
@@ -54,19 +158,367 @@ type Box struct {
 	// nothing should be forwarded).
 	inputCapture func(event *tcell.EventKey) *tcell.EventKey
 
+	// An optional capture function which receives a mouse action and event
+	// and returns the action and event to be forwarded to the primitive's
+	// default mouse handler (a nil event if nothing should be forwarded).
+	mouseCapture func(action MouseAction, event *tcell.EventMouse) (MouseAction, *tcell.EventMouse)
+
+	// The minimum size of the box and what happens when a layout container
+	// gives it less space.
+	minWidth, minHeight int
+	overflow            Overflow
+
 	// An optional function which is called before the box is drawn.
 	draw func(screen tcell.Screen, x, y, width, height int) (int, int, int, int)
+
+	// The state of the application which last drew the box, its draw count
+	// and draw sequence at that time (see appState), and the screen the box
+	// was drawn on.
+	drawnState       *appState
+	drawn, drawOrder uint64
+	drawnScreen      tcell.Screen
+
+	// Whether or not the primitive drew outside its rectangle the last time
+	// it was drawn (see drawsOutside()).
+	drewOutside bool
+
+	// 1 if the box changed since it was last drawn (see MarkDirty()). It is
+	// accessed atomically.
+	dirty int32
+
+	// Additional draw functions, by layer.
+	drawFuncs [3][]func(screen tcell.Screen, x, y, width, height int)
+
+	// Optional functions which are called when the box is clicked or
+	// double-clicked with the left mouse button.
+	clicked, doubleClicked func() bool
+
+	// An optional function which is called when the mouse enters or leaves the
+	// box.
+	hover func(hovering bool)
+
+	// Optional functions which are called when the box gains or loses focus.
+	focused, blurred func()
+
+	// Keys which trigger handlers while the box has focus.
+	shortcuts []*shortcut
+}
+
+// appState is the state of an Application which the boxes it draws share
+// with it. Boxes reach it through the screen they are drawn on (see
+// appStateOf()) and remember it for the events they receive afterwards.
+type appState struct {
+	// Held while the application draws the screen. It guards the fields
+	// below which are changed while drawing.
+	drawing sync.Mutex
+
+	// Incremented each time the application draws the entire screen. It is
+	// accessed atomically.
+	drawCount uint64
+
+	// Incremented each time a box is drawn or a rectangle is dimmed. It tells
+	// which boxes were drawn on top of others.
+	drawSequence uint64
+
+	// Incremented each time a box is drawn.
+	boxDraws uint64
+
+	// The number of boxes drawn since drawCount was last incremented. It is
+	// larger than the number of boxes found in the primitive tree if
+	// primitives draw boxes which are not part of it, e.g. the list of an
+	// open DropDown.
+	drawnBoxes int
+
+	// The rectangles dimmed since the last full draw.
+	dimmedRects []dimmedRect
+
+	// The boxes drawn since the last call to drawOverlays() which have
+	// overlay draw functions, in the order in which they were drawn.
+	pendingOverlays []overlay
+
+	// The sixel images drawn since the screen was last shown (see
+	// Image.SetSixel()).
+	pendingSixels []sixelImage
+
+	// The number of nested mouse handlers (see wrapMouseHandler()) currently
+	// processing a mouse event and whether one of them consumed it. Only the
+	// application's event loop accesses these fields.
+	mouseDepth    int
+	mouseConsumed bool
+
+	// The boxes with a hover handler (see SetHoverFunc()) which the mouse is
+	// currently over. Only the application's event loop accesses it.
+	hoveredBoxes map[*Box]bool
+
+	// The boxes with a focus or blur handler (see SetFocusFunc()) which had
+	// focus when the handlers were last called, guarded by a mutex which is
+	// not held while the handlers are called.
+	focusedBoxes map[*Box]bool
+	focusMutex   sync.Mutex
+}
+
+// newAppState returns a new application state.
+func newAppState() *appState {
+	return &appState{
+		hoveredBoxes: make(map[*Box]bool),
+		focusedBoxes: make(map[*Box]bool),
+	}
+}
+
+// appScreen is the screen an Application draws its primitives on. It gives
+// the boxes access to the application's state.
+type appScreen struct {
+	tcell.Screen
+	state *appState
+}
+
+// appStateOf returns the state of the application which draws onto the given
+// screen or nil if the screen does not belong to an application, e.g. when a
+// primitive is drawn directly onto a screen.
+func appStateOf(screen tcell.Screen) *appState {
+	for {
+		switch s := screen.(type) {
+		case *appScreen:
+			return s.state
+		case *clippedScreen:
+			screen = s.Screen
+		case *shadowScreen:
+			screen = s.Screen
+		default:
+			return nil
+		}
+	}
+}
+
+// leaveHoveredBoxes calls the hover handlers of the boxes which the mouse has
+// left, given its new position. It returns whether any handler was called.
+func (s *appState) leaveHoveredBoxes(x, y int) (left bool) {
+	for box := range s.hoveredBoxes {
+		if !box.InRect(x, y) {
+			delete(s.hoveredBoxes, box)
+			if box.hover != nil {
+				box.hover(false)
+				box.MarkDirty()
+				left = true
+			}
+		}
+	}
+	return
+}
+
+// wasDrawn returns whether the given primitive was drawn the last time the
+// application with the given state drew the screen. Primitives which are not
+// boxes are assumed to be drawn, as are all primitives if the state is nil.
+func wasDrawn(p Primitive, state *appState) bool {
+	b, ok := p.(interface {
+		getBox() *Box
+	})
+	return !ok || state == nil || b.getBox().drawnIn(state)
+}
+
+// drawnIn returns whether the box was drawn the last time the application
+// with the given state drew the screen.
+func (b *Box) drawnIn(state *appState) bool {
+	return b.drawnState == state && b.drawn == atomic.LoadUint64(&state.drawCount)
+}
+
+// drawnStateOf returns the state of the application which last drew the given
+// primitive or nil if it is not a box or was not drawn by an application.
+func drawnStateOf(p Primitive) *appState {
+	if b, ok := p.(interface {
+		getBox() *Box
+	}); ok {
+		return b.getBox().drawnState
+	}
+	return nil
+}
+
+// dimmedRect is a rectangle which was dimmed (see dimRect()) after the box
+// with the given draw order was drawn.
+type dimmedRect struct {
+	order               uint64
+	x, y, width, height int
+}
+
+// MarkDirty marks the box as changed so it is redrawn the next time the
+// application draws the screen with dirty tracking enabled (see
+// Application.EnableDirtyTracking()). If the box contains other primitives,
+// they are redrawn along with it. It may be called from any goroutine.
+//
+// Boxes mark themselves when they process key events or consume mouse events,
+// and layout containers such as Flex, Grid, and Pages mark themselves when
+// their items change. When other code changes a primitive, e.g. a handler
+// which updates a TextView after a button was pressed, it must mark the
+// primitive (or one of its containers) itself.
+func (b *Box) MarkDirty() {
+	atomic.StoreInt32(&b.dirty, 1)
+}
+
+// isDirty returns whether the box was marked as changed since it was last
+// drawn.
+func (b *Box) isDirty() bool {
+	return atomic.LoadInt32(&b.dirty) != 0
+}
+
+// markDrawn records that the box was drawn onto the given screen. Primitives
+// based on Box which don't call Box.Draw() should call it when they are drawn.
+func (b *Box) markDrawn(screen tcell.Screen) {
+	atomic.StoreInt32(&b.dirty, 0)
+	state := appStateOf(screen)
+	if state == nil {
+		return
+	}
+	if b.drawnState != state || b.drawn != state.drawCount {
+		state.drawnBoxes++
+	}
+	state.drawSequence++
+	state.boxDraws++
+	b.drawnState, b.drawn, b.drawOrder, b.drawnScreen = state, state.drawCount, state.drawSequence, screen
+	b.drewOutside = drawsOutside(b.focus)
+}
+
+// drawsOutside returns whether the given primitive currently draws outside
+// its rectangle, e.g. the list of an open DropDown. Such primitives cannot be
+// redrawn clipped to their rectangle (see appState.drawDirty()).
+func drawsOutside(p interface{}) bool {
+	if o, ok := p.(interface {
+		drawsOutside() bool
+	}); ok {
+		return o.drawsOutside()
+	}
+	return false
+}
+
+// markDirty marks the given primitive as changed if it is a box (see
+// Box.MarkDirty()).
+func markDirty(p Primitive) {
+	if b, ok := p.(interface {
+		getBox() *Box
+	}); ok {
+		b.getBox().MarkDirty()
+	}
+}
+
+// shortcut is a key or a sequence of keys which triggers a handler while a
+// primitive has focus (see Box.AddShortcut() and Box.AddKeySequence()).
+type shortcut struct {
+	keys        []KeyStroke
+	description string
+	handler     func()
+}
+
+// match returns whether the given key strokes are the shortcut's key sequence
+// ("full") or the beginning of it ("prefix"). The leader stroke in the
+// sequence matches the given leader key.
+func (s *shortcut) match(strokes []KeyStroke, leader *KeyStroke) (full, prefix bool) {
+	if len(strokes) > len(s.keys) {
+		return false, false
+	}
+	for index, stroke := range strokes {
+		key := s.keys[index]
+		if key.Key == keyLeader {
+			if leader == nil {
+				return false, false
+			}
+			key = *leader
+		}
+		if stroke != key {
+			return false, false
+		}
+	}
+	return len(strokes) == len(s.keys), len(strokes) < len(s.keys)
+}
+
+// getBox returns the box itself. It gives access to the Box embedded in a
+// primitive.
+func (b *Box) getBox() *Box {
+	return b
+}
+
+// notifyFocus calls the focus and blur handlers of the boxes (see
+// SetFocusFunc()) whose focus state changed, looking for them in the trees
+// below the given primitives.
+func (s *appState) notifyFocus(roots ...Primitive) {
+	// The handlers are called after the mutex was released.
+	var handlers []func()
+	defer func() {
+		for _, handler := range handlers {
+			handler()
+		}
+	}()
+	s.focusMutex.Lock()
+	defer s.focusMutex.Unlock()
+	for box := range s.focusedBoxes {
+		if !box.focus.HasFocus() {
+			delete(s.focusedBoxes, box)
+			if box.blurred != nil {
+				handlers = append(handlers, box.blurred)
+			}
+		}
+	}
+	for _, root := range roots {
+		if root == nil {
+			continue
+		}
+		WalkTree(root, func(p, parent Primitive) bool {
+			b, ok := p.(interface {
+				getBox() *Box
+			})
+			if !ok {
+				return true
+			}
+			box := b.getBox()
+			if (box.focused != nil || box.blurred != nil) && !s.focusedBoxes[box] && box.focus.HasFocus() {
+				s.focusedBoxes[box] = true
+				if box.focused != nil {
+					handlers = append(handlers, box.focused)
+				}
+			}
+			return true
+		})
+	}
+}
+
+// overlay is a box whose overlay draw functions are called after the screen
+// was drawn, with the screen the box was drawn on.
+type overlay struct {
+	box    *Box
+	screen tcell.Screen
+}
+
+// drawOverlays calls the overlay draw functions of all boxes drawn since the
+// last call.
+func (s *appState) drawOverlays() {
+	for _, o := range s.pendingOverlays {
+		o.draw()
+	}
+	s.pendingOverlays = s.pendingOverlays[:0]
+}
+
+// draw calls the overlay draw functions of the box.
+func (o overlay) draw() {
+	x, y, width, height := o.box.getBorderRect()
+	for _, handler := range o.box.drawFuncs[DrawOverlay] {
+		handler(o.screen, x, y, width, height)
+	}
 }
 
 // NewBox returns a Box without a border.
 func NewBox() *Box {
+	styles := Styles.forWidget("Box")
 	b := &Box{
-		width:           15,
-		height:          10,
-		backgroundColor: Styles.PrimitiveBackgroundColor,
-		borderColor:     Styles.BorderColor,
-		titleColor:      Styles.TitleColor,
-		titleAlign:      AlignCenter,
+		width:              15,
+		height:             10,
+		backgroundColor:    styles.PrimitiveBackgroundColor,
+		backgroundRune:     ' ',
+		borderColor:        styles.BorderColor,
+		titleColor:         styles.TitleColor,
+		titleAlign:         AlignCenter,
+		bottomTitleColor:   styles.TitleColor,
+		bottomTitleAlign:   AlignCenter,
+		borderColorFocused: tcell.ColorDefault,
+		titleColorFocused:  tcell.ColorDefault,
+		disabledTextColor:  styles.DisabledTextColor,
 	}
 	b.focus = b
 	return b
@@ -78,6 +530,22 @@ func (b *Box) SetBorderPadding(top, bottom, left, right int) *Box {
 	return b
 }
 
+// SetPadding sets the number of empty cells between the box's border (or its
+// edge if it has no border) and its content. This is the same as
+// SetBorderPadding().
+func (b *Box) SetPadding(top, bottom, left, right int) *Box {
+	return b.SetBorderPadding(top, bottom, left, right)
+}
+
+// SetMargin sets the number of cells between the box's rectangle (see
+// SetRect()) and its border. Nothing is drawn into the margins, so they show
+// whatever is below the box, usually the background of the containing
+// primitive. Mouse events in the margins are not handled by the box.
+func (b *Box) SetMargin(top, bottom, left, right int) *Box {
+	b.marginTop, b.marginBottom, b.marginLeft, b.marginRight = top, bottom, left, right
+	return b
+}
+
 // GetRect returns the current position of the rectangle, x, y, width, and
 // height.
 func (b *Box) GetRect() (int, int, int, int) {
@@ -85,12 +553,12 @@ func (b *Box) GetRect() (int, int, int, int) {
 }
 
 // GetInnerRect returns the position of the inner rectangle (x, y, width,
-// height), without the border and without any padding.
+// height), without the margins, the border, and any padding.
 func (b *Box) GetInnerRect() (int, int, int, int) {
 	if b.draw != nil {
 		return b.innerX, b.innerY, b.innerWidth, b.innerHeight
 	}
-	x, y, width, height := b.GetRect()
+	x, y, width, height := b.getBorderRect()
 	if b.border {
 		x++
 		y++
@@ -103,6 +571,16 @@ func (b *Box) GetInnerRect() (int, int, int, int) {
 		height - b.paddingTop - b.paddingBottom
 }
 
+// getBorderRect returns the position of the rectangle without the margins,
+// i.e. the area which is filled with the background color and surrounded by
+// the border.
+func (b *Box) getBorderRect() (int, int, int, int) {
+	return b.x + b.marginLeft,
+		b.y + b.marginTop,
+		b.width - b.marginLeft - b.marginRight,
+		b.height - b.marginTop - b.marginBottom
+}
+
 // SetRect sets a new position of the primitive.
 func (b *Box) SetRect(x, y, width, height int) {
 	b.x = x
@@ -115,25 +593,59 @@ func (b *Box) SetRect(x, y, width, height int) {
 // has been drawn. This allows you to add a more individual style to the box
 // (and all primitives which extend it).
 //
-// The function is provided with the box's dimensions (set via SetRect()). It
-// must return the box's inner dimensions (x, y, width, height) which will be
-// returned by GetInnerRect(), used by descendent primitives to draw their own
-// content.
+// The function is provided with the box's dimensions (set via SetRect(),
+// without any margins). It must return the box's inner dimensions (x, y,
+// width, height) which will be returned by GetInnerRect(), used by descendent
+// primitives to draw their own content.
 func (b *Box) SetDrawFunc(handler func(screen tcell.Screen, x, y, width, height int) (int, int, int, int)) *Box {
 	b.draw = handler
 	return b
 }
 
+// AddDrawFunc adds a function which is invoked when the box is drawn, in
+// addition to the function set with SetDrawFunc() and any functions added
+// before. This allows decorations, e.g. badges or watermarks, to be combined.
+// The layer determines when the function is called:
+//
+//   - DrawBackground: After the background was filled but before the border.
+//   - DrawContent: After the border and the function set with SetDrawFunc(),
+//     but before the primitive's content.
+//   - DrawOverlay: After the application has drawn all primitives, so the
+//     function draws on top of the primitive's content and its children.
+//     These functions are only called for boxes drawn by an Application.
+//
+// Functions of the same layer are called in the order in which they were
+// added. They are provided with the box's dimensions (set via SetRect(),
+// without any margins). Unlike the function set with SetDrawFunc(), they do
+// not change the box's inner rectangle.
+func (b *Box) AddDrawFunc(layer DrawLayer, handler func(screen tcell.Screen, x, y, width, height int)) *Box {
+	b.drawFuncs[layer] = append(b.drawFuncs[layer], handler)
+	return b
+}
+
+// ClearDrawFuncs removes all functions added with AddDrawFunc(). The function
+// set with SetDrawFunc() is not affected.
+func (b *Box) ClearDrawFuncs() *Box {
+	for layer := range b.drawFuncs {
+		b.drawFuncs[layer] = nil
+	}
+	return b
+}
+
 // wrapInputHandler wraps an input handler (see InputHandler()) with the
 // functionality to capture input (see SetInputCapture()) before passing it
 // on to the provided (default) input handler.
 func (b *Box) wrapInputHandler(inputHandler func(*tcell.EventKey, func(p Primitive))) func(*tcell.EventKey, func(p Primitive)) {
 	return func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		if b.disabled {
+			return
+		}
 		if b.inputCapture != nil {
 			event = b.inputCapture(event)
 		}
 		if event != nil && inputHandler != nil {
 			inputHandler(event, setFocus)
+			b.MarkDirty()
 		}
 	}
 }
@@ -143,6 +655,186 @@ func (b *Box) InputHandler() func(event *tcell.EventKey, setFocus func(p Primiti
 	return b.wrapInputHandler(nil)
 }
 
+// wrapMouseHandler wraps a mouse handler (see MouseHandler()) such that it is
+// only called with valid events. It is the mouse counterpart of
+// wrapInputHandler() and should be used by all subclasses which process mouse
+// events.
+func (b *Box) wrapMouseHandler(mouseHandler func(MouseAction, *tcell.EventMouse, func(p Primitive)) (bool, Primitive)) func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if event == nil || b.disabled {
+			return
+		}
+
+		// The innermost box which consumes the event marks itself dirty.
+		if state := b.drawnState; state != nil {
+			if state.mouseDepth == 0 {
+				state.mouseConsumed = false
+			}
+			state.mouseDepth++
+			defer func() {
+				state.mouseDepth--
+				if consumed && !state.mouseConsumed {
+					state.mouseConsumed = true
+					b.MarkDirty()
+				}
+			}()
+		} else {
+			defer func() {
+				if consumed {
+					b.MarkDirty()
+				}
+			}()
+		}
+
+		if b.mouseCapture != nil {
+			if action, event = b.mouseCapture(action, event); event == nil {
+				return true, nil // Swallowed.
+			}
+		}
+		var entered bool
+		if (b.clicked != nil || b.doubleClicked != nil || b.hover != nil) && b.InRect(event.Position()) {
+			switch action {
+			case MouseMove:
+				if state := b.drawnState; b.hover != nil && state != nil && !state.hoveredBoxes[b] {
+					state.hoveredBoxes[b] = true
+					b.hover(true)
+					entered = true // Causes a redraw.
+				}
+			case MouseLeftClick:
+				if b.clicked != nil && b.clicked() {
+					return true, nil
+				}
+			case MouseLeftDoubleClick:
+				if b.doubleClicked != nil && b.doubleClicked() {
+					return true, nil
+				}
+			}
+		}
+		if mouseHandler != nil {
+			consumed, capture = mouseHandler(action, event, setFocus)
+		}
+		consumed = consumed || entered
+		return
+	}
+}
+
+// SetClickedFunc sets a handler which is called when the box is clicked with
+// the left mouse button. If the handler returns true, the click is consumed and
+// not processed any further, e.g. by the mouse handler of a subclass such as
+// List. Otherwise, it is processed as usual. This works for all primitives
+// based on Box, without the need for a custom mouse handler.
+func (b *Box) SetClickedFunc(handler func() bool) *Box {
+	b.clicked = handler
+	return b
+}
+
+// SetDoubleClickedFunc sets a handler which is called when the box is
+// double-clicked with the left mouse button. See SetClickedFunc() for the
+// meaning of the return value.
+func (b *Box) SetDoubleClickedFunc(handler func() bool) *Box {
+	b.doubleClicked = handler
+	return b
+}
+
+// SetHoverFunc sets a handler which is called with "true" when the mouse moves
+// onto the box and with "false" when it leaves the box again, e.g. to highlight
+// the box. Mouse events must be enabled (see Application.EnableMouse()).
+func (b *Box) SetHoverFunc(handler func(hovering bool)) *Box {
+	b.hover = handler
+	return b
+}
+
+// SetFocusFunc sets a handler which is called when the box gains focus. For
+// primitives which contain other primitives (e.g. a Form), this is when one of
+// them receives focus while none had it before. This allows any primitive to
+// react to focus changes, e.g. to refresh its contents, without subclassing
+// it. The handler is called by the Application after the focus has changed
+// (see Application.SetFocus()).
+func (b *Box) SetFocusFunc(handler func()) *Box {
+	b.focused = handler
+	return b
+}
+
+// SetBlurFunc sets a handler which is called when the box loses focus. See
+// SetFocusFunc() for details.
+func (b *Box) SetBlurFunc(handler func()) *Box {
+	b.blurred = handler
+	return b
+}
+
+// AddShortcut adds a key which calls the given handler while the box (or, for
+// primitives which contain others, one of its contained primitives) has
+// focus. If "key" is tcell.KeyRune, the shortcut is the rune "ch". The
+// description says what the shortcut does, e.g. "Save".
+//
+// The Application routes matching key events to the handler before they reach
+// the focused primitive, preferring the shortcuts of the innermost primitive.
+// It also collects the shortcuts of the focused primitive and the primitives
+// containing it for display, e.g. in a HelpOverlay or a StatusBar (see
+// Application.GetShortcuts()).
+func (b *Box) AddShortcut(key tcell.Key, ch rune, description string, handler func()) *Box {
+	if key != tcell.KeyRune {
+		ch = 0
+	}
+	return b.AddKeySequence([]KeyStroke{{Key: key, Rune: ch}}, description, handler)
+}
+
+// AddKeySequence adds a shortcut (see AddShortcut()) which consists of
+// several keys pressed one after another, e.g. "g g", "d d", or a key
+// following the leader key (see Application.SetLeaderKey()). Use
+// ParseKeySequence() to obtain the key strokes from a string:
+//
+//   keys, err := tview.ParseKeySequence("g g")
+//   if err != nil {
+//     panic(err)
+//   }
+//   textView.AddKeySequence(keys, "Go to top", scrollToTop)
+//
+// While the keys of a sequence are being pressed, they are not passed on to
+// the focused primitive. If a key does not continue any sequence, the keys
+// pressed before it are discarded. If the next key is not pressed within the
+// application's timeout (see Application.SetKeySequenceTimeout()), the
+// sequence is abandoned. If a sequence is also the beginning of a longer
+// sequence, its handler is called when the timeout expires or another key
+// is pressed.
+func (b *Box) AddKeySequence(keys []KeyStroke, description string, handler func()) *Box {
+	b.shortcuts = append(b.shortcuts, &shortcut{
+		keys:        keys,
+		description: description,
+		handler:     handler,
+	})
+	return b
+}
+
+// ClearShortcuts removes all shortcuts added with AddShortcut() and
+// AddKeySequence().
+func (b *Box) ClearShortcuts() *Box {
+	b.shortcuts = nil
+	return b
+}
+
+// GetShortcuts returns the box's shortcuts (see AddShortcut()) as key
+// bindings for display, e.g. in a HelpOverlay.
+func (b *Box) GetShortcuts() []KeyBinding {
+	bindings := make([]KeyBinding, 0, len(b.shortcuts))
+	for _, s := range b.shortcuts {
+		bindings = append(bindings, KeyBinding{Keys: keySequenceString(s.keys), Description: s.description})
+	}
+	return bindings
+}
+
+// MouseHandler returns a handler which does not process any mouse events.
+func (b *Box) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return b.wrapMouseHandler(nil)
+}
+
+// InRect returns true if the given coordinate is within the bounds of the box's
+// rectangle, excluding its margins.
+func (b *Box) InRect(x, y int) bool {
+	rectX, rectY, width, height := b.getBorderRect()
+	return x >= rectX && x < rectX+width && y >= rectY && y < rectY+height
+}
+
 // SetInputCapture installs a function which captures key events before they are
 // forwarded to the primitive's default key event handler. This function can
 // then choose to forward that key event (or a different one) to the default
@@ -155,12 +847,37 @@ func (b *Box) SetInputCapture(capture func(event *tcell.EventKey) *tcell.EventKe
 	return b
 }
 
+// SetMouseCapture installs a function which captures mouse events before they
+// are forwarded to the primitive's default mouse handler and, for containers,
+// to the primitives contained in it. This function can then choose to forward
+// that mouse action and event (or different ones, e.g. with translated
+// coordinates) to the default handler by returning them. If the returned event
+// is nil, the default handler will not be called and the event is treated as
+// consumed. This allows a container to implement its own gestures, e.g.
+// dragging items between its children.
+//
+// Mouse events must be enabled (see Application.EnableMouse()). Providing a nil
+// handler will remove a previously existing handler.
+func (b *Box) SetMouseCapture(capture func(action MouseAction, event *tcell.EventMouse) (MouseAction, *tcell.EventMouse)) *Box {
+	b.mouseCapture = capture
+	return b
+}
+
 // SetBackgroundColor sets the box's background color.
 func (b *Box) SetBackgroundColor(color tcell.Color) *Box {
 	b.backgroundColor = color
 	return b
 }
 
+// SetBackgroundRune sets the rune used to fill the box's background, e.g. '░'
+// or '·', to make placeholder panes, drop zones, or desktop-style backgrounds
+// distinct. The rune is drawn in the default foreground color. The default is
+// a space character.
+func (b *Box) SetBackgroundRune(r rune) *Box {
+	b.backgroundRune = r
+	return b
+}
+
 // SetBorder sets the flag indicating whether or not the box should have a
 // border.
 func (b *Box) SetBorder(show bool) *Box {
@@ -174,6 +891,72 @@ func (b *Box) SetBorderColor(color tcell.Color) *Box {
 	return b
 }
 
+// SetBorderColorFocused sets the box's border color while it has focus. If set
+// to tcell.ColorDefault (the default), the color set with SetBorderColor() is
+// used regardless of focus.
+func (b *Box) SetBorderColorFocused(color tcell.Color) *Box {
+	b.borderColorFocused = color
+	return b
+}
+
+// SetBorderAttributesFocused sets text attributes (e.g. tcell.AttrBold) which
+// are applied to the box's border while it has focus.
+func (b *Box) SetBorderAttributesFocused(attributes tcell.AttrMask) *Box {
+	b.borderAttributesFocused = attributes
+	return b
+}
+
+// SetBorderStyle sets the runes used to draw the box's border, e.g. one of the
+// BorderStyleRounded, BorderStyleDouble, BorderStyleThick, BorderStyleASCII,
+// or BorderStyleNone presets. By default, the borders of the current theme
+// (Styles.Borders) are used. This has no effect if the box has no border (see
+// SetBorder()).
+func (b *Box) SetBorderStyle(style BorderRunes) *Box {
+	b.borderRunes = &style
+	return b
+}
+
+// SetJoinBorders sets whether or not the box's border is joined with the box
+// drawing runes already on the screen where it is drawn (see JoinGraphics()).
+// If two boxes overlap by one cell such that they share an edge, the box drawn
+// second then produces T and cross junctions instead of overwriting the other
+// box's border. The cells along the edges of such a box are not cleared before
+// drawing, even if it has no border. Flex.SetJoinBorders() makes use of this.
+func (b *Box) SetJoinBorders(join bool) *Box {
+	b.joinBorders = join
+	return b
+}
+
+// SetBidi sets whether or not right-to-left text (e.g. Arabic or Hebrew) is
+// displayed in its visual order. Text is reordered line by line according to a
+// simplified version of the Unicode Bidirectional Algorithm (UAX #9). This
+// applies to the box title and to the text of the primitives which support it:
+// TextView, Table cells, and the labels of buttons and form items. It is off by
+// default.
+func (b *Box) SetBidi(enabled bool) *Box {
+	b.bidi = enabled
+	return b
+}
+
+// bidiText returns the given line of text in visual order if reordering of
+// right-to-left text is enabled (see SetBidi()).
+func (b *Box) bidiText(text string) string {
+	if b.bidi {
+		return reorderBidi(text)
+	}
+	return text
+}
+
+// SetShadow sets whether or not the box casts a shadow, i.e. whether the cells
+// to the right of and below the box (offset by one cell) are dimmed. This gives
+// modals and floating windows some depth. The shadow is drawn outside the box's
+// rectangle, over whatever was drawn there before the box. If the box has
+// margins (see SetMargin()), the shadow is drawn into the margins.
+func (b *Box) SetShadow(shadow bool) *Box {
+	b.shadow = shadow
+	return b
+}
+
 // SetTitle sets the box's title.
 func (b *Box) SetTitle(title string) *Box {
 	b.title = title
@@ -186,6 +969,95 @@ func (b *Box) SetTitleColor(color tcell.Color) *Box {
 	return b
 }
 
+// SetTitleColorFocused sets the box's title color while it has focus. If set to
+// tcell.ColorDefault (the default), the color set with SetTitleColor() is used
+// regardless of focus.
+func (b *Box) SetTitleColorFocused(color tcell.Color) *Box {
+	b.titleColorFocused = color
+	return b
+}
+
+// SetTitleAttributesFocused sets text attributes (e.g. tcell.AttrBold) which
+// are applied to the box's title while it has focus.
+func (b *Box) SetTitleAttributesFocused(attributes tcell.AttrMask) *Box {
+	b.titleAttributesFocused = attributes
+	return b
+}
+
+// SetTitleAttributes sets text attributes (e.g. tcell.AttrBold or
+// tcell.AttrReverse) which are applied to the box's titles. While the box has
+// focus, the attributes set with SetTitleAttributesFocused() are used instead,
+// if any.
+func (b *Box) SetTitleAttributes(attributes tcell.AttrMask) *Box {
+	b.titleAttributes = attributes
+	return b
+}
+
+// SetTitleOverflow sets how titles which are wider than the box's border are
+// shown. By default (TitleTruncateEnd), the end of the title is cut off and
+// replaced with an ellipsis. TitleTruncateStart cuts off the beginning
+// instead, which keeps the file name of a long path visible. For
+// TitleMarquee, use SetTitleMarquee().
+func (b *Box) SetTitleOverflow(overflow TitleOverflow) *Box {
+	b.titleOverflow = overflow
+	return b
+}
+
+// SetTitleMarquee lets titles which are wider than the box's border scroll
+// through it, moving by one cell per interval. The "redraw" function is
+// called when the title needs to be redrawn, e.g. Application.Draw(). Titles
+// which fit are not affected.
+func (b *Box) SetTitleMarquee(interval time.Duration, redraw func()) *Box {
+	b.titleOverflow = TitleMarquee
+	b.marqueeInterval = interval
+	b.marqueeRedraw = redraw
+	b.marqueeStarted = time.Now()
+	b.marqueeNext = time.Time{}
+	return b
+}
+
+// titleText returns the given title as it is printed into a border of the
+// given width, according to the title overflow mode.
+func (b *Box) titleText(text string, width int) string {
+	if StringWidth(text) <= width {
+		return text
+	}
+	switch b.titleOverflow {
+	case TitleTruncateStart:
+		runes := []rune(StripTags(text))
+		textWidth := stringWidth(string(runes))
+		for len(runes) > 0 && textWidth+1 > width {
+			textWidth -= RuneWidth(runes[0])
+			runes = runes[1:]
+		}
+		return string(GraphicsEllipsis) + Escape(string(runes))
+	case TitleMarquee:
+		if b.marqueeInterval <= 0 {
+			break
+		}
+		runes := []rune(StripTags(text) + titleMarqueeGap)
+		offset := int(time.Since(b.marqueeStarted)/b.marqueeInterval) % stringWidth(string(runes))
+		var skipped, start int
+		for start < len(runes) && skipped < offset {
+			skipped += RuneWidth(runes[start])
+			start++
+		}
+		scrolled := string(runes[start:]) + string(runes[:start])
+
+		// Schedule the next step.
+		if now := time.Now(); b.marqueeRedraw != nil && !now.Before(b.marqueeNext) {
+			b.marqueeNext = now.Add(b.marqueeInterval)
+			redraw := b.marqueeRedraw
+			time.AfterFunc(b.marqueeInterval, func() {
+				b.MarkDirty()
+				redraw()
+			})
+		}
+		return Escape(truncateWidth(scrolled, width))
+	}
+	return TruncateString(text, width, string(GraphicsEllipsis))
+}
+
 // SetTitleAlign sets the alignment of the title, one of AlignLeft, AlignCenter,
 // or AlignRight.
 func (b *Box) SetTitleAlign(align int) *Box {
@@ -193,69 +1065,335 @@ func (b *Box) SetTitleAlign(align int) *Box {
 	return b
 }
 
+// SetBottomTitle sets a second title which is shown in the bottom border of the
+// box, e.g. for key hints or item counts. Like the title, it is only visible if
+// the box has a border.
+func (b *Box) SetBottomTitle(title string) *Box {
+	b.bottomTitle = title
+	return b
+}
+
+// SetBottomTitleColor sets the color of the bottom title.
+func (b *Box) SetBottomTitleColor(color tcell.Color) *Box {
+	b.bottomTitleColor = color
+	return b
+}
+
+// SetBottomTitleAlign sets the alignment of the bottom title, one of AlignLeft,
+// AlignCenter, or AlignRight.
+func (b *Box) SetBottomTitleAlign(align int) *Box {
+	b.bottomTitleAlign = align
+	return b
+}
+
+// SetStyle sets the style of the box. The style's background color replaces
+// the box's background color, its attributes are applied to the text drawn
+// onto the background, and its foreground color replaces the color of the main
+// text of the primitive (e.g. a text view's text or an input field's input
+// text). Other colors, such as those of labels or selections, are not affected.
+//
+// The style takes precedence over the colors set with other functions such as
+// SetBackgroundColor(). All fields of the style are used, so a style should
+// always be given a foreground and a background color.
+func (b *Box) SetStyle(style Style) *Box {
+	b.style = &style
+	return b
+}
+
+// SetFocusStyle sets the style used instead of the one set with SetStyle()
+// while the box has focus. See SetStyle() for details.
+func (b *Box) SetFocusStyle(style Style) *Box {
+	b.focusStyle = &style
+	return b
+}
+
+// SetDisabledStyle sets the style used instead of the ones set with SetStyle()
+// and SetFocusStyle() while the box is disabled (see SetDisabled()). See
+// SetStyle() for details.
+func (b *Box) SetDisabledStyle(style Style) *Box {
+	b.disabledStyle = &style
+	return b
+}
+
+// SetDisabled sets whether or not the box is disabled. Disabled boxes ignore
+// all key and mouse events and are skipped when containers such as Form move
+// the focus. They are drawn with the disabled style (see SetDisabledStyle()) or,
+// if none was set, with their main text in Styles.DisabledTextColor.
+func (b *Box) SetDisabled(disabled bool) *Box {
+	b.disabled = disabled
+	return b
+}
+
+// IsDisabled returns whether or not the box is disabled.
+func (b *Box) IsDisabled() bool {
+	return b.disabled
+}
+
+// isDisabled returns whether the given primitive is disabled. Primitives which
+// cannot be disabled are never disabled.
+func isDisabled(p Primitive) bool {
+	d, ok := p.(interface {
+		IsDisabled() bool
+	})
+	return ok && d.IsDisabled()
+}
+
+// SetMinSize sets the minimum width and height of the box. When a layout
+// container (e.g. Flex, Grid, Pages, SplitPane, or Frame) gives the box less
+// space than this, the overflow policy decides what is drawn instead (see
+// SetOverflow()). A value of 0 means there is no minimum. This does not
+// change the layout itself, see Flex.AddItemWithLimits() for that.
+func (b *Box) SetMinSize(width, height int) *Box {
+	b.minWidth, b.minHeight = width, height
+	return b
+}
+
+// GetMinSize returns the minimum width and height of the box.
+func (b *Box) GetMinSize() (width, height int) {
+	return b.minWidth, b.minHeight
+}
+
+// SetOverflow sets what layout containers draw when the box is smaller than
+// its minimum size (see SetMinSize()):
+//
+//   - OverflowClip: The box is drawn at its minimum size and cut off at the
+//     edges of the available space (the default).
+//   - OverflowHide: Nothing is drawn.
+//   - OverflowPlaceholder: The available space is filled with a "too small"
+//     message.
+func (b *Box) SetOverflow(overflow Overflow) *Box {
+	b.overflow = overflow
+	return b
+}
+
+// overflowPolicy returns the box's minimum size and overflow policy.
+func (b *Box) overflowPolicy() (minWidth, minHeight int, overflow Overflow) {
+	return b.minWidth, b.minHeight, b.overflow
+}
+
+// drawPrimitive draws a primitive which was placed by a layout container,
+// taking into account its minimum size and overflow policy (see
+// Box.SetMinSize()).
+func drawPrimitive(screen tcell.Screen, p Primitive) {
+	o, ok := p.(interface {
+		overflowPolicy() (int, int, Overflow)
+	})
+	if !ok {
+		p.Draw(screen)
+		return
+	}
+	minWidth, minHeight, overflow := o.overflowPolicy()
+	x, y, width, height := p.GetRect()
+	if width >= minWidth && height >= minHeight {
+		p.Draw(screen)
+		return
+	}
+
+	switch overflow {
+	case OverflowHide:
+	case OverflowPlaceholder:
+		background := tcell.StyleDefault.Background(Styles.PrimitiveBackgroundColor)
+		for row := y; row < y+height; row++ {
+			for column := x; column < x+width; column++ {
+				screen.SetContent(column, row, ' ', nil, background)
+			}
+		}
+		if width > 0 && height > 0 {
+			Print(screen, "too small", x, y+height/2, width, AlignCenter, Styles.TertiaryTextColor)
+		}
+	default:
+		clipped := &clippedScreen{Screen: screen, x: x, y: y, width: width, height: height}
+		if width < minWidth {
+			width = minWidth
+		}
+		if height < minHeight {
+			height = minHeight
+		}
+		p.SetRect(x, y, width, height)
+		p.Draw(clipped)
+		p.SetRect(clipped.x, clipped.y, clipped.width, clipped.height)
+	}
+}
+
+// currentStyle returns the style for the box's current state, or nil if no
+// style applies.
+func (b *Box) currentStyle() *Style {
+	if b.disabled {
+		if b.disabledStyle != nil {
+			return b.disabledStyle
+		}
+		return &Style{
+			Foreground: b.disabledTextColor,
+			Background: b.backgroundColor,
+		}
+	}
+	if b.focusStyle != nil && b.focus.HasFocus() {
+		return b.focusStyle
+	}
+	return b.style
+}
+
+// applyStyle replaces the box's background color and the given text colors
+// with the colors of the current style (see SetStyle()). It returns a function
+// which restores the original colors. Subclasses call it at the beginning of
+// their Draw() function:
+//
+//   defer t.applyStyle(&t.textColor)()
+func (b *Box) applyStyle(textColors ...*tcell.Color) func() {
+	style := b.currentStyle()
+	if style == nil {
+		return func() {}
+	}
+	backgroundColor := b.backgroundColor
+	colors := make([]tcell.Color, len(textColors))
+	b.backgroundColor = style.Background
+	for index, color := range textColors {
+		colors[index] = *color
+		*color = style.Foreground
+	}
+	return func() {
+		b.backgroundColor = backgroundColor
+		for index, color := range textColors {
+			*color = colors[index]
+		}
+	}
+}
+
+// applyTheme is called when the application's theme changes. Colors which
+// still have the value they were given by the previous theme are replaced with
+// the corresponding colors of the new theme. Colors set by the application are
+// left alone.
+func (b *Box) applyTheme(from, to *Theme) {
+	themeColor(&b.backgroundColor, from.PrimitiveBackgroundColor, to.PrimitiveBackgroundColor)
+	themeColor(&b.borderColor, from.BorderColor, to.BorderColor)
+	themeColor(&b.titleColor, from.TitleColor, to.TitleColor)
+	themeColor(&b.bottomTitleColor, from.TitleColor, to.TitleColor)
+	themeColor(&b.disabledTextColor, from.DisabledTextColor, to.DisabledTextColor)
+}
+
 // Draw draws this primitive onto the screen.
 func (b *Box) Draw(screen tcell.Screen) {
 	// Don't draw anything if there is no space.
-	if b.width <= 0 || b.height <= 0 {
+	boxX, boxY, boxWidth, boxHeight := b.getBorderRect()
+	if boxWidth <= 0 || boxHeight <= 0 {
 		return
 	}
+	b.markDrawn(screen)
 
 	def := tcell.StyleDefault
+	defer b.applyStyle()()
+
+	// Draw the shadow.
+	if b.shadow {
+		dimRect(screen, boxX+boxWidth, boxY+1, 1, boxHeight, shadowDimming)
+		dimRect(screen, boxX+1, boxY+boxHeight, boxWidth-1, 1, shadowDimming)
+	}
 
 	// Fill background.
 	background := def.Background(b.backgroundColor)
-	for y := b.y; y < b.y+b.height; y++ {
-		for x := b.x; x < b.x+b.width; x++ {
-			screen.SetContent(x, y, ' ', nil, background)
+	if style := b.currentStyle(); style != nil {
+		background = style.withAttributes(background)
+	}
+	fillX, fillY, fillWidth, fillHeight := boxX, boxY, boxWidth, boxHeight
+	if b.joinBorders {
+		fillX, fillY, fillWidth, fillHeight = boxX+1, boxY+1, boxWidth-2, boxHeight-2
+	}
+	for y := fillY; y < fillY+fillHeight; y++ {
+		for x := fillX; x < fillX+fillWidth; x++ {
+			screen.SetContent(x, y, b.backgroundRune, nil, background)
 		}
 	}
+	for _, handler := range b.drawFuncs[DrawBackground] {
+		handler(screen, boxX, boxY, boxWidth, boxHeight)
+	}
 
 	// Draw border.
-	if b.border && b.width >= 2 && b.height >= 2 {
-		border := background.Foreground(b.borderColor)
+	if b.border && boxWidth >= 2 && boxHeight >= 2 {
+		// Determine the colors and attributes for the focus state.
+		focused := b.focus.HasFocus()
+		borderColor, titleColor := b.borderColor, b.titleColor
+		var borderAttributes tcell.AttrMask
+		titleAttributes := b.titleAttributes
+		if focused {
+			if b.borderColorFocused != tcell.ColorDefault {
+				borderColor = b.borderColorFocused
+			}
+			if b.titleColorFocused != tcell.ColorDefault {
+				titleColor = b.titleColorFocused
+			}
+			borderAttributes = b.borderAttributesFocused
+			if b.titleAttributesFocused != 0 {
+				titleAttributes = b.titleAttributesFocused
+			}
+		}
+		border := Style{Attributes: borderAttributes}.withAttributes(background.Foreground(borderColor))
+
+		runes := &Styles.Borders
+		if b.borderRunes != nil {
+			runes = b.borderRunes
+		}
 		var vertical, horizontal, topLeft, topRight, bottomLeft, bottomRight rune
-		if b.focus.HasFocus() {
-			vertical = GraphicsDbVertBar
-			horizontal = GraphicsDbHorBar
-			topLeft = GraphicsDbTopLeftCorner
-			topRight = GraphicsDbTopRightCorner
-			bottomLeft = GraphicsDbBottomLeftCorner
-			bottomRight = GraphicsDbBottomRightCorner
+		if focused {
+			vertical = runes.HorizontalFocus
+			horizontal = runes.VerticalFocus
+			topLeft = runes.TopLeftFocus
+			topRight = runes.TopRightFocus
+			bottomLeft = runes.BottomLeftFocus
+			bottomRight = runes.BottomRightFocus
 		} else {
-			vertical = GraphicsHoriBar
-			horizontal = GraphicsVertBar
-			topLeft = GraphicsTopLeftCorner
-			topRight = GraphicsTopRightCorner
-			bottomLeft = GraphicsBottomLeftCorner
-			bottomRight = GraphicsBottomRightCorner
-		}
-		for x := b.x + 1; x < b.x+b.width-1; x++ {
-			screen.SetContent(x, b.y, vertical, nil, border)
-			screen.SetContent(x, b.y+b.height-1, vertical, nil, border)
-		}
-		for y := b.y + 1; y < b.y+b.height-1; y++ {
-			screen.SetContent(b.x, y, horizontal, nil, border)
-			screen.SetContent(b.x+b.width-1, y, horizontal, nil, border)
-		}
-		screen.SetContent(b.x, b.y, topLeft, nil, border)
-		screen.SetContent(b.x+b.width-1, b.y, topRight, nil, border)
-		screen.SetContent(b.x, b.y+b.height-1, bottomLeft, nil, border)
-		screen.SetContent(b.x+b.width-1, b.y+b.height-1, bottomRight, nil, border)
-
-		// Draw title.
-		if b.title != "" && b.width >= 4 {
-			_, printed := Print(screen, b.title, b.x+1, b.y, b.width-2, b.titleAlign, b.titleColor)
-			if StringWidth(b.title)-printed > 0 && printed > 0 {
-				_, _, style, _ := screen.GetContent(b.x+b.width-2, b.y)
-				fg, _, _ := style.Decompose()
-				Print(screen, string(GraphicsEllipsis), b.x+b.width-2, b.y, 1, AlignLeft, fg)
+			vertical = runes.Horizontal
+			horizontal = runes.Vertical
+			topLeft = runes.TopLeft
+			topRight = runes.TopRight
+			bottomLeft = runes.BottomLeft
+			bottomRight = runes.BottomRight
+		}
+		setBorder := func(x, y int, ch rune) {
+			if b.joinBorders {
+				previous, _, _, _ := screen.GetContent(x, y)
+				ch = JoinGraphics(previous, ch)
 			}
+			screen.SetContent(x, y, ch, nil, border)
 		}
+		for x := boxX + 1; x < boxX+boxWidth-1; x++ {
+			setBorder(x, boxY, vertical)
+			setBorder(x, boxY+boxHeight-1, vertical)
+		}
+		for y := boxY + 1; y < boxY+boxHeight-1; y++ {
+			setBorder(boxX, y, horizontal)
+			setBorder(boxX+boxWidth-1, y, horizontal)
+		}
+		setBorder(boxX, boxY, topLeft)
+		setBorder(boxX+boxWidth-1, boxY, topRight)
+		setBorder(boxX, boxY+boxHeight-1, bottomLeft)
+		setBorder(boxX+boxWidth-1, boxY+boxHeight-1, bottomRight)
+
+		// Draw the titles.
+		drawTitle := func(text string, y, align int, color tcell.Color) {
+			if text == "" || boxWidth < 4 {
+				return
+			}
+			printWithStyle(screen, b.bidiText(b.titleText(text, boxWidth-2)), boxX+1, y, boxWidth-2, align, newTextStyle(color, titleAttributes))
+		}
+		drawTitle(b.title, boxY, b.titleAlign, titleColor)
+		drawTitle(b.bottomTitle, boxY+boxHeight-1, b.bottomTitleAlign, b.bottomTitleColor)
 	}
 
 	// Call custom draw function.
 	if b.draw != nil {
-		b.innerX, b.innerY, b.innerWidth, b.innerHeight = b.draw(screen, b.x, b.y, b.width, b.height)
+		b.innerX, b.innerY, b.innerWidth, b.innerHeight = b.draw(screen, boxX, boxY, boxWidth, boxHeight)
+	}
+	for _, handler := range b.drawFuncs[DrawContent] {
+		handler(screen, boxX, boxY, boxWidth, boxHeight)
+	}
+
+	// Overlays are drawn after the application drew everything else.
+	if len(b.drawFuncs[DrawOverlay]) > 0 {
+		if state := appStateOf(screen); state != nil {
+			state.pendingOverlays = append(state.pendingOverlays, overlay{box: b, screen: screen})
+		} else {
+			overlay{box: b, screen: screen}.draw()
+		}
 	}
 }
 
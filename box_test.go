@@ -0,0 +1,20 @@
+package tview
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBoxTitleMarqueeDirtyTracking(t *testing.T) {
+	box := NewBox().SetBorder(true).SetTitle("a title which is too long")
+	app, screen := newTestApplication(t, box, 10, 3)
+	app.EnableDirtyTracking(true)
+	box.SetTitleMarquee(20*time.Millisecond, func() { app.Draw() })
+	app.Draw()
+
+	title := screenLine(screen, 0)
+	time.Sleep(100 * time.Millisecond)
+	if line := screenLine(screen, 0); line == title {
+		t.Errorf("the title did not scroll: %q", line)
+	}
+}
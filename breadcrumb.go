@@ -0,0 +1,374 @@
+package tview
+
+import (
+	"github.com/gdamore/tcell"
+)
+
+// Breadcrumb is a single line showing a path of segments, e.g. the directories
+// leading to the current one:
+//
+//   Home ▸ Projects ▸ tview
+//
+// If the segments don't fit into the available width, segments in the middle
+// are replaced by an ellipsis, starting with the ones closest to the first
+// segment. The first and the last segment are always shown (the last one
+// truncated if necessary).
+//
+// When the breadcrumb has focus, the user can select a segment with the
+// following keys:
+//
+//   - Left arrow, h: Select the previous segment.
+//   - Right arrow, l: Select the next segment.
+//   - Home, g: Select the first segment.
+//   - End, G: Select the last segment.
+//   - Enter: Navigate to the selected segment.
+//
+// Clicking on a segment navigates to it (if mouse events are enabled). In both
+// cases, the handler set with SetNavigateFunc() is called. It is up to this
+// handler to update the segments, e.g. by calling Truncate().
+type Breadcrumb struct {
+	*Box
+
+	// The segments' labels. They may contain color tags.
+	segments []string
+
+	// The index of the selected segment.
+	currentSegment int
+
+	// The separator drawn between two segments.
+	separator string
+
+	// The positions and widths of the segments the last time the breadcrumb
+	// was drawn. A width of 0 indicates that the segment was not visible.
+	segmentX, segmentWidth []int
+
+	// The color of the segments except for the last one.
+	segmentColor tcell.Color
+
+	// The color of the last segment.
+	lastSegmentColor tcell.Color
+
+	// The color of the separators and the ellipsis.
+	separatorColor tcell.Color
+
+	// The text and background colors of the selected segment when the
+	// breadcrumb has focus.
+	selectedTextColor, selectedBackgroundColor tcell.Color
+
+	// An optional function which is called when the user navigates to a
+	// segment.
+	navigate func(index int, label string)
+
+	// An optional function which is called when the user leaves the
+	// breadcrumb.
+	done func(tcell.Key)
+}
+
+// NewBreadcrumb returns a new breadcrumb without any segments.
+func NewBreadcrumb() *Breadcrumb {
+	styles := Styles.forWidget("Breadcrumb")
+	return &Breadcrumb{
+		Box:                     NewBox(),
+		separator:               " ▸ ",
+		segmentColor:            styles.SecondaryTextColor,
+		lastSegmentColor:        styles.PrimaryTextColor,
+		separatorColor:          styles.TertiaryTextColor,
+		selectedTextColor:       styles.InverseTextColor,
+		selectedBackgroundColor: styles.PrimaryTextColor,
+	}
+}
+
+// SetSegments replaces all segments with the given labels. The last segment
+// is selected.
+func (b *Breadcrumb) SetSegments(labels ...string) *Breadcrumb {
+	b.segments = append([]string(nil), labels...)
+	b.currentSegment = len(b.segments) - 1
+	return b
+}
+
+// Push appends a segment with the given label and selects it.
+func (b *Breadcrumb) Push(label string) *Breadcrumb {
+	b.segments = append(b.segments, label)
+	b.currentSegment = len(b.segments) - 1
+	return b
+}
+
+// Pop removes the last segment and returns its label. An empty string is
+// returned if there are no segments.
+func (b *Breadcrumb) Pop() string {
+	if len(b.segments) == 0 {
+		return ""
+	}
+	label := b.segments[len(b.segments)-1]
+	b.Truncate(len(b.segments) - 2)
+	return label
+}
+
+// Truncate removes all segments after the one with the given index and
+// selects the new last segment.
+func (b *Breadcrumb) Truncate(index int) *Breadcrumb {
+	if index < -1 {
+		index = -1
+	}
+	if index < len(b.segments)-1 {
+		b.segments = b.segments[:index+1]
+	}
+	b.currentSegment = len(b.segments) - 1
+	return b
+}
+
+// GetSegmentCount returns the number of segments.
+func (b *Breadcrumb) GetSegmentCount() int {
+	return len(b.segments)
+}
+
+// GetSegment returns the label of the segment with the given index. An empty
+// string is returned if the index is out of range.
+func (b *Breadcrumb) GetSegment(index int) string {
+	if index < 0 || index >= len(b.segments) {
+		return ""
+	}
+	return b.segments[index]
+}
+
+// GetCurrentSegment returns the index of the selected segment, -1 if there are
+// no segments.
+func (b *Breadcrumb) GetCurrentSegment() int {
+	return b.currentSegment
+}
+
+// SetSeparator sets the text drawn between two segments. The default is
+// " ▸ ".
+func (b *Breadcrumb) SetSeparator(separator string) *Breadcrumb {
+	b.separator = separator
+	return b
+}
+
+// SetSegmentColor sets the color of all segments except for the last one.
+func (b *Breadcrumb) SetSegmentColor(color tcell.Color) *Breadcrumb {
+	b.segmentColor = color
+	return b
+}
+
+// SetLastSegmentColor sets the color of the last segment.
+func (b *Breadcrumb) SetLastSegmentColor(color tcell.Color) *Breadcrumb {
+	b.lastSegmentColor = color
+	return b
+}
+
+// SetSeparatorColor sets the color of the separators and of the ellipsis
+// replacing hidden segments.
+func (b *Breadcrumb) SetSeparatorColor(color tcell.Color) *Breadcrumb {
+	b.separatorColor = color
+	return b
+}
+
+// SetSelectedTextColor sets the text color of the selected segment when the
+// breadcrumb has focus.
+func (b *Breadcrumb) SetSelectedTextColor(color tcell.Color) *Breadcrumb {
+	b.selectedTextColor = color
+	return b
+}
+
+// SetSelectedBackgroundColor sets the background color of the selected
+// segment when the breadcrumb has focus.
+func (b *Breadcrumb) SetSelectedBackgroundColor(color tcell.Color) *Breadcrumb {
+	b.selectedBackgroundColor = color
+	return b
+}
+
+// SetNavigateFunc sets a handler which is called when the user navigates to a
+// segment by pressing Enter or by clicking on it. It receives the segment's
+// index and label.
+func (b *Breadcrumb) SetNavigateFunc(handler func(index int, label string)) *Breadcrumb {
+	b.navigate = handler
+	return b
+}
+
+// SetDoneFunc sets a handler which is called when the user leaves the
+// breadcrumb. The callback function is provided with the key that was pressed,
+// which is one of the following:
+//
+//   - KeyEscape: Leaving the breadcrumb with no specific direction.
+//   - KeyTab: Move to the next field.
+//   - KeyBacktab: Move to the previous field.
+func (b *Breadcrumb) SetDoneFunc(handler func(key tcell.Key)) *Breadcrumb {
+	b.done = handler
+	return b
+}
+
+// selectSegment selects the segment with the given index, if it exists.
+func (b *Breadcrumb) selectSegment(index int) {
+	if index >= 0 && index < len(b.segments) {
+		b.currentSegment = index
+	}
+}
+
+// navigateTo selects the segment with the given index and notifies the
+// "navigate" handler.
+func (b *Breadcrumb) navigateTo(index int) {
+	b.selectSegment(index)
+	if b.navigate != nil && index >= 0 && index < len(b.segments) {
+		b.navigate(index, b.segments[index])
+	}
+}
+
+// visibleSegments determines which segments fit into the given width. It
+// returns the segments' widths, -1 for hidden segments.
+func (b *Breadcrumb) visibleSegments(width int) []int {
+	widths := make([]int, len(b.segments))
+	for index, segment := range b.segments {
+		widths[index] = StringWidth(segment)
+	}
+
+	// Hide segments after the first one until everything fits, keeping the
+	// selected one as long as possible.
+	last := len(b.segments) - 1
+	for index := 1; index < last && b.lineWidth(widths) > width; index++ {
+		if index != b.currentSegment {
+			widths[index] = -1
+		}
+	}
+	if b.currentSegment > 0 && b.currentSegment < last && b.lineWidth(widths) > width {
+		widths[b.currentSegment] = -1
+	}
+	return widths
+}
+
+// lineWidth returns the width of the breadcrumb with the given segment widths
+// (as returned by visibleSegments()), including separators and ellipses.
+func (b *Breadcrumb) lineWidth(widths []int) int {
+	separatorWidth := StringWidth(b.separator)
+	total := -separatorWidth
+	for index, width := range widths {
+		if width >= 0 {
+			total += width + separatorWidth
+		} else if widths[index-1] >= 0 {
+			total += 1 + separatorWidth // Ellipsis.
+		}
+	}
+	return total
+}
+
+// applyTheme is called when the application's theme changes.
+func (b *Breadcrumb) applyTheme(from, to *Theme) {
+	b.Box.applyTheme(from, to)
+	themeColor(&b.segmentColor, from.SecondaryTextColor, to.SecondaryTextColor)
+	themeColor(&b.lastSegmentColor, from.PrimaryTextColor, to.PrimaryTextColor)
+	themeColor(&b.separatorColor, from.TertiaryTextColor, to.TertiaryTextColor)
+	themeColor(&b.selectedTextColor, from.InverseTextColor, to.InverseTextColor)
+	themeColor(&b.selectedBackgroundColor, from.PrimaryTextColor, to.PrimaryTextColor)
+}
+
+// Draw draws this primitive onto the screen.
+func (b *Breadcrumb) Draw(screen tcell.Screen) {
+	defer b.applyStyle(&b.lastSegmentColor)()
+	b.Box.Draw(screen)
+
+	x, y, width, height := b.GetInnerRect()
+	b.segmentX = make([]int, len(b.segments))
+	b.segmentWidth = make([]int, len(b.segments))
+	if width <= 0 || height <= 0 || len(b.segments) == 0 {
+		return
+	}
+
+	widths := b.visibleSegments(width)
+	right := x + width
+	for index, segment := range b.segments {
+		if widths[index] < 0 && widths[index-1] >= 0 {
+			// Replace hidden segments with an ellipsis.
+			_, printed := Print(screen, b.separator+string(GraphicsEllipsis), x, y, right-x, AlignLeft, b.separatorColor)
+			x += printed
+		}
+		if widths[index] < 0 {
+			continue
+		}
+		if index > 0 {
+			_, printed := Print(screen, b.separator, x, y, right-x, AlignLeft, b.separatorColor)
+			x += printed
+		}
+		if x >= right {
+			break
+		}
+
+		// Draw the segment.
+		color := b.segmentColor
+		if index == len(b.segments)-1 {
+			color = b.lastSegmentColor
+		}
+		segmentWidth := widths[index]
+		if segmentWidth > right-x {
+			segmentWidth = right - x
+		}
+		backgroundColor := b.backgroundColor
+		if index == b.currentSegment && b.hasFocus {
+			color, backgroundColor = b.selectedTextColor, b.selectedBackgroundColor
+			style := tcell.StyleDefault.Background(backgroundColor)
+			for offset := 0; offset < segmentWidth; offset++ {
+				screen.SetContent(x+offset, y, ' ', nil, style)
+			}
+		}
+		if segmentWidth < widths[index] && segmentWidth > 0 {
+			// Truncate the segment, ending with an ellipsis.
+			Print(screen, segment, x, y, segmentWidth-1, AlignLeft, color)
+			screen.SetContent(x+segmentWidth-1, y, GraphicsEllipsis, nil, tcell.StyleDefault.Foreground(color).Background(backgroundColor))
+		} else {
+			Print(screen, segment, x, y, segmentWidth, AlignLeft, color)
+		}
+		b.segmentX[index], b.segmentWidth[index] = x, segmentWidth
+		x += segmentWidth
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (b *Breadcrumb) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return b.wrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		switch key := event.Key(); key {
+		case tcell.KeyLeft:
+			b.selectSegment(b.currentSegment - 1)
+		case tcell.KeyRight:
+			b.selectSegment(b.currentSegment + 1)
+		case tcell.KeyHome:
+			b.selectSegment(0)
+		case tcell.KeyEnd:
+			b.selectSegment(len(b.segments) - 1)
+		case tcell.KeyEnter:
+			b.navigateTo(b.currentSegment)
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'h':
+				b.selectSegment(b.currentSegment - 1)
+			case 'l':
+				b.selectSegment(b.currentSegment + 1)
+			case 'g':
+				b.selectSegment(0)
+			case 'G':
+				b.selectSegment(len(b.segments) - 1)
+			}
+		case tcell.KeyEscape, tcell.KeyTab, tcell.KeyBacktab:
+			if b.done != nil {
+				b.done(key)
+			}
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (b *Breadcrumb) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return b.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+		if !b.InRect(x, y) {
+			return false, nil
+		}
+		if action == MouseLeftClick {
+			setFocus(b)
+			for index := range b.segmentX {
+				if b.segmentWidth[index] > 0 && x >= b.segmentX[index] && x < b.segmentX[index]+b.segmentWidth[index] {
+					b.navigateTo(index)
+					break
+				}
+			}
+		}
+		return true, nil
+	})
+}
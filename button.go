@@ -1,11 +1,18 @@
 package tview
 
 import (
+	"strings"
+	"time"
+
 	"github.com/gdamore/tcell"
 )
 
 // Button is labeled box that triggers an action when selected.
 //
+// The label may be preceded by an icon (see SetIcon()) and may span two lines,
+// separated by a newline character, e.g. "Run\nCtrl+R". The second line is
+// only shown if the button is at least two lines high.
+//
 // See https://github.com/rivo/tview/wiki/Button for an example.
 type Button struct {
 	*Box
@@ -13,6 +20,9 @@ type Button struct {
 	// The text to be displayed before the input area.
 	label string
 
+	// An optional icon shown before the label.
+	icon string
+
 	// The label color.
 	labelColor tcell.Color
 
@@ -22,6 +32,15 @@ type Button struct {
 	// The background color when the button is in focus.
 	backgroundColorActivated tcell.Color
 
+	// The style of the button while it is pressed.
+	selectedStyle Style
+
+	// How long the button is shown as pressed after it was selected, the
+	// function which redraws it afterwards, and the time it was last pressed.
+	pressDuration time.Duration
+	redraw        func()
+	pressed       time.Time
+
 	// An optional function which is called when the button was selected.
 	selected func()
 
@@ -32,15 +51,22 @@ type Button struct {
 
 // NewButton returns a new input field.
 func NewButton(label string) *Button {
-	box := NewBox().SetBackgroundColor(Styles.ContrastBackgroundColor)
-	box.SetRect(0, 0, StringWidth(label)+4, 1)
-	return &Button{
+	styles := Styles.forWidget("Button")
+	box := NewBox().SetBackgroundColor(styles.ContrastBackgroundColor)
+	b := &Button{
 		Box:                      box,
 		label:                    label,
-		labelColor:               Styles.PrimaryTextColor,
-		labelColorActivated:      Styles.InverseTextColor,
-		backgroundColorActivated: Styles.PrimaryTextColor,
+		labelColor:               styles.PrimaryTextColor,
+		labelColorActivated:      styles.InverseTextColor,
+		backgroundColorActivated: styles.PrimaryTextColor,
+		selectedStyle: Style{
+			Foreground: styles.InverseTextColor,
+			Background: styles.SecondaryTextColor,
+		},
 	}
+	width, height := b.GetPreferredSize()
+	box.SetRect(0, 0, width, height)
+	return b
 }
 
 // SetLabel sets the button text.
@@ -54,6 +80,41 @@ func (b *Button) GetLabel() string {
 	return b.label
 }
 
+// SetIcon sets an icon which is shown before the label, separated by a space,
+// e.g. "▶" or "✖". It may be any string, including color tags. Set to an empty
+// string to remove the icon.
+func (b *Button) SetIcon(icon string) *Button {
+	b.icon = icon
+	return b
+}
+
+// GetIcon returns the icon shown before the label.
+func (b *Button) GetIcon() string {
+	return b.icon
+}
+
+// GetPreferredSize returns the size the button needs to show its icon and its
+// label, including a margin of two cells on either side. The height is 2 if the
+// label has two lines, and 1 otherwise. Containers such as Form use the width
+// to lay out buttons.
+func (b *Button) GetPreferredSize() (width, height int) {
+	lines := b.labelLines()
+	for _, line := range lines {
+		if w := StringWidth(line); w > width {
+			width = w
+		}
+	}
+	if b.icon != "" {
+		width += StringWidth(b.icon) + 1
+	}
+	return width + 4, len(lines)
+}
+
+// labelLines returns the lines of the label, at most two.
+func (b *Button) labelLines() []string {
+	return strings.SplitN(b.label, "\n", 2)
+}
+
 // SetLabelColor sets the color of the button text.
 func (b *Button) SetLabelColor(color tcell.Color) *Button {
 	b.labelColor = color
@@ -74,6 +135,25 @@ func (b *Button) SetBackgroundColorActivated(color tcell.Color) *Button {
 	return b
 }
 
+// SetSelectedStyle sets the style of the button while it is shown as pressed
+// after it was selected (see SetPressFeedback()). It is distinct from the focus
+// style (see Box.SetFocusStyle()).
+func (b *Button) SetSelectedStyle(style Style) *Button {
+	b.selectedStyle = style
+	return b
+}
+
+// SetPressFeedback sets for how long the button is shown as pressed, using the
+// selected style (see SetSelectedStyle()), after the user selected it. As the
+// button cannot redraw itself, the provided function is called when this time
+// has passed. It will usually call Application.Draw(). It is called from a
+// different goroutine. A duration of 0 (the default) turns the feedback off.
+func (b *Button) SetPressFeedback(duration time.Duration, redraw func()) *Button {
+	b.pressDuration = duration
+	b.redraw = redraw
+	return b
+}
+
 // SetSelectedFunc sets a handler which is called when the button was selected.
 func (b *Button) SetSelectedFunc(handler func()) *Button {
 	b.selected = handler
@@ -92,12 +172,49 @@ func (b *Button) SetBlurFunc(handler func(key tcell.Key)) *Button {
 	return b
 }
 
+// SetDisabled sets whether or not the button is disabled. Disabled buttons
+// cannot be selected and are skipped by Form navigation. See Box.SetDisabled()
+// for details.
+func (b *Button) SetDisabled(disabled bool) *Button {
+	b.Box.SetDisabled(disabled)
+	return b
+}
+
+// applyTheme is called when the application's theme changes.
+func (b *Button) applyTheme(from, to *Theme) {
+	b.Box.applyTheme(from, to)
+	themeColor(&b.backgroundColor, from.ContrastBackgroundColor, to.ContrastBackgroundColor)
+	themeColor(&b.labelColor, from.PrimaryTextColor, to.PrimaryTextColor)
+	themeColor(&b.labelColorActivated, from.InverseTextColor, to.InverseTextColor)
+	themeColor(&b.backgroundColorActivated, from.PrimaryTextColor, to.PrimaryTextColor)
+	themeColor(&b.selectedStyle.Foreground, from.InverseTextColor, to.InverseTextColor)
+	themeColor(&b.selectedStyle.Background, from.SecondaryTextColor, to.SecondaryTextColor)
+}
+
 // Draw draws this primitive onto the screen.
 func (b *Button) Draw(screen tcell.Screen) {
+	// Apply the current style. A focus or disabled style also replaces the
+	// colors of the activated button.
+	if style := b.currentStyle(); style != nil && b.focus.HasFocus() && style != b.style {
+		labelColorActivated, backgroundColorActivated := b.labelColorActivated, b.backgroundColorActivated
+		b.labelColorActivated, b.backgroundColorActivated = style.Foreground, style.Background
+		defer func() {
+			b.labelColorActivated, b.backgroundColorActivated = labelColorActivated, backgroundColorActivated
+		}()
+	}
+	defer b.applyStyle(&b.labelColor)()
+
 	// Draw the box.
+	pressed := b.isPressed()
 	borderColor := b.borderColor
 	backgroundColor := b.backgroundColor
-	if b.focus.HasFocus() {
+	if pressed {
+		b.backgroundColor = b.selectedStyle.Background
+		b.borderColor = b.selectedStyle.Foreground
+		defer func() {
+			b.borderColor = borderColor
+		}()
+	} else if b.focus.HasFocus() {
 		b.backgroundColor = b.backgroundColorActivated
 		b.borderColor = b.labelColorActivated
 		defer func() {
@@ -107,15 +224,55 @@ func (b *Button) Draw(screen tcell.Screen) {
 	b.Box.Draw(screen)
 	b.backgroundColor = backgroundColor
 
-	// Draw label.
+	// Draw icon and label.
 	x, y, width, height := b.GetInnerRect()
-	if width > 0 && height > 0 {
-		y = y + height/2
-		labelColor := b.labelColor
-		if b.focus.HasFocus() {
-			labelColor = b.labelColorActivated
-		}
-		Print(screen, b.label, x, y, width, AlignCenter, labelColor)
+	if width <= 0 || height <= 0 {
+		return
+	}
+	labelStyle := Style{Foreground: b.labelColor, Background: tcell.ColorDefault}
+	if pressed {
+		labelStyle = b.selectedStyle
+	} else if b.focus.HasFocus() {
+		labelStyle.Foreground = b.labelColorActivated
+	}
+	lines := b.labelLines()
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	y += (height - len(lines)) / 2
+
+	// The icon and the lines of the label form a centered block.
+	blockWidth, _ := b.GetPreferredSize()
+	blockWidth -= 4
+	if blockWidth > width {
+		blockWidth = width
+	}
+	x += (width - blockWidth) / 2
+	if b.icon != "" {
+		iconWidth := PrintStyled(screen, b.icon, x, y, blockWidth, AlignLeft, labelStyle)
+		x += iconWidth + 1
+		blockWidth -= iconWidth + 1
+	}
+	for index, line := range lines {
+		PrintStyled(screen, b.bidiText(line), x, y+index, blockWidth, AlignCenter, labelStyle)
+	}
+}
+
+// isPressed returns whether the button is currently shown as pressed (see
+// SetPressFeedback()).
+func (b *Button) isPressed() bool {
+	return !b.pressed.IsZero() && time.Since(b.pressed) < b.pressDuration
+}
+
+// press shows the button as pressed for the duration set with
+// SetPressFeedback().
+func (b *Button) press() {
+	if b.pressDuration <= 0 {
+		return
+	}
+	b.pressed = time.Now()
+	if b.redraw != nil {
+		time.AfterFunc(b.pressDuration, b.redraw)
 	}
 }
 
@@ -125,6 +282,7 @@ func (b *Button) InputHandler() func(event *tcell.EventKey, setFocus func(p Prim
 		// Process key event.
 		switch key := event.Key(); key {
 		case tcell.KeyEnter: // Selected.
+			b.press()
 			if b.selected != nil {
 				b.selected()
 			}
@@ -0,0 +1,404 @@
+package tview
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell"
+)
+
+// Calendar displays the days of one month in a grid, one week per row. The
+// user can navigate the days with the keyboard or the mouse and select one of
+// them. Individual dates (e.g. dates with events) can be highlighted and
+// dates can be excluded from selection.
+//
+// The following keys are available:
+//
+//   - Left arrow, right arrow, h, l: Move to the previous/next day.
+//   - Up arrow, down arrow, k, j: Move to the previous/next week.
+//   - Page up, page down: Move to the previous/next month.
+//   - [, ]: Move to the previous/next year.
+//   - Home, End: Move to the first/last day of the month.
+//   - t: Move to today.
+//   - Enter: Select the current date.
+//   - Escape, Tab, Backtab: Finish (see SetDoneFunc()).
+//
+// A calendar needs 20 columns and 8 rows of screen space, not including the
+// box's border. It is used by DateField as a popup.
+type Calendar struct {
+	*Box
+
+	// The date the cursor is on. The month of this date is displayed.
+	date time.Time
+
+	// The dates to be highlighted, keyed by calendarKey().
+	highlights map[string]bool
+
+	// An optional function which determines if a date may be selected.
+	selectable func(date time.Time) bool
+
+	// The first day of the week.
+	firstWeekday time.Weekday
+
+	// The color of the month and year at the top.
+	headerColor tcell.Color
+
+	// The color of the weekday names.
+	weekdayColor tcell.Color
+
+	// The color of the days.
+	dayColor tcell.Color
+
+	// The color of highlighted days.
+	highlightColor tcell.Color
+
+	// The color of days which cannot be selected.
+	disabledColor tcell.Color
+
+	// The text and background colors of the day the cursor is on.
+	selectedTextColor, selectedBackgroundColor tcell.Color
+
+	// An optional function which is called when the user moves the cursor to
+	// another date.
+	changed func(date time.Time)
+
+	// An optional function which is called when the user selects a date.
+	selected func(date time.Time)
+
+	// An optional function which is called when the user presses Escape, Tab,
+	// or Backtab.
+	done func(key tcell.Key)
+}
+
+// NewCalendar returns a new calendar showing the current month with the
+// cursor on today's date.
+func NewCalendar() *Calendar {
+	styles := Styles.forWidget("Calendar")
+	return &Calendar{
+		Box:                     NewBox(),
+		date:                    calendarDay(time.Now()),
+		highlights:              make(map[string]bool),
+		firstWeekday:            time.Sunday,
+		headerColor:             styles.TitleColor,
+		weekdayColor:            styles.TertiaryTextColor,
+		dayColor:                styles.PrimaryTextColor,
+		highlightColor:          styles.SecondaryTextColor,
+		disabledColor:           styles.ContrastBackgroundColor,
+		selectedTextColor:       styles.PrimitiveBackgroundColor,
+		selectedBackgroundColor: styles.PrimaryTextColor,
+	}
+}
+
+// calendarDay strips the time of day from the given time, keeping its
+// location.
+func calendarDay(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+}
+
+// calendarKey returns a key which identifies the day of the given date.
+func calendarKey(date time.Time) string {
+	return date.Format("2006-01-02")
+}
+
+// daysIn returns the number of days in the given month.
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// SetDate moves the cursor to the given date and displays its month. The time
+// of day is ignored.
+func (c *Calendar) SetDate(date time.Time) *Calendar {
+	c.date = calendarDay(date)
+	return c
+}
+
+// GetDate returns the date the cursor is on.
+func (c *Calendar) GetDate() time.Time {
+	return c.date
+}
+
+// SetFirstWeekday sets the day each week starts with. The default is
+// time.Sunday.
+func (c *Calendar) SetFirstWeekday(weekday time.Weekday) *Calendar {
+	c.firstWeekday = weekday
+	return c
+}
+
+// HighlightDate sets whether or not the given date is highlighted, e.g.
+// because it has events associated with it.
+func (c *Calendar) HighlightDate(date time.Time, highlight bool) *Calendar {
+	if highlight {
+		c.highlights[calendarKey(date)] = true
+	} else {
+		delete(c.highlights, calendarKey(date))
+	}
+	return c
+}
+
+// ClearHighlights removes all highlights.
+func (c *Calendar) ClearHighlights() *Calendar {
+	c.highlights = make(map[string]bool)
+	return c
+}
+
+// SetSelectableFunc sets a function which determines whether or not a date
+// may be selected by the user. Dates which cannot be selected are shown in a
+// different color. If no such function is set, all dates may be selected.
+func (c *Calendar) SetSelectableFunc(handler func(date time.Time) bool) *Calendar {
+	c.selectable = handler
+	return c
+}
+
+// SetHeaderColor sets the color of the month and year shown at the top.
+func (c *Calendar) SetHeaderColor(color tcell.Color) *Calendar {
+	c.headerColor = color
+	return c
+}
+
+// SetWeekdayColor sets the color of the weekday names.
+func (c *Calendar) SetWeekdayColor(color tcell.Color) *Calendar {
+	c.weekdayColor = color
+	return c
+}
+
+// SetDayColor sets the color of the days.
+func (c *Calendar) SetDayColor(color tcell.Color) *Calendar {
+	c.dayColor = color
+	return c
+}
+
+// SetHighlightColor sets the color of highlighted days.
+func (c *Calendar) SetHighlightColor(color tcell.Color) *Calendar {
+	c.highlightColor = color
+	return c
+}
+
+// SetDisabledColor sets the color of days which cannot be selected.
+func (c *Calendar) SetDisabledColor(color tcell.Color) *Calendar {
+	c.disabledColor = color
+	return c
+}
+
+// SetSelectedTextColor sets the text color of the day the cursor is on.
+func (c *Calendar) SetSelectedTextColor(color tcell.Color) *Calendar {
+	c.selectedTextColor = color
+	return c
+}
+
+// SetSelectedBackgroundColor sets the background color of the day the cursor
+// is on.
+func (c *Calendar) SetSelectedBackgroundColor(color tcell.Color) *Calendar {
+	c.selectedBackgroundColor = color
+	return c
+}
+
+// SetChangedFunc sets a handler which is called when the user moves the
+// cursor to another date.
+func (c *Calendar) SetChangedFunc(handler func(date time.Time)) *Calendar {
+	c.changed = handler
+	return c
+}
+
+// SetSelectedFunc sets a handler which is called when the user selects a date
+// by pressing Enter or clicking on it.
+func (c *Calendar) SetSelectedFunc(handler func(date time.Time)) *Calendar {
+	c.selected = handler
+	return c
+}
+
+// SetDoneFunc sets a handler which is called when the user presses the
+// Escape, Tab, or Backtab key.
+func (c *Calendar) SetDoneFunc(handler func(key tcell.Key)) *Calendar {
+	c.done = handler
+	return c
+}
+
+// isSelectable returns whether or not the given date may be selected.
+func (c *Calendar) isSelectable(date time.Time) bool {
+	return c.selectable == nil || c.selectable(date)
+}
+
+// moveTo moves the cursor to the given date and calls the "changed" handler.
+func (c *Calendar) moveTo(date time.Time) {
+	date = calendarDay(date)
+	if date.Equal(c.date) {
+		return
+	}
+	c.date = date
+	if c.changed != nil {
+		c.changed(c.date)
+	}
+}
+
+// moveMonths moves the cursor by the given number of months, keeping the day
+// within the target month.
+func (c *Calendar) moveMonths(months int) {
+	first := time.Date(c.date.Year(), c.date.Month()+time.Month(months), 1, 0, 0, 0, 0, c.date.Location())
+	day := c.date.Day()
+	if last := daysIn(first.Year(), first.Month()); day > last {
+		day = last
+	}
+	c.moveTo(first.AddDate(0, 0, day-1))
+}
+
+// selectDate selects the date the cursor is on, if possible.
+func (c *Calendar) selectDate() {
+	if c.selected != nil && c.isSelectable(c.date) {
+		c.selected(c.date)
+	}
+}
+
+// firstCell returns the date shown in the top-left cell of the day grid.
+func (c *Calendar) firstCell() time.Time {
+	first := time.Date(c.date.Year(), c.date.Month(), 1, 0, 0, 0, 0, c.date.Location())
+	offset := (int(first.Weekday()) - int(c.firstWeekday) + 7) % 7
+	return first.AddDate(0, 0, -offset)
+}
+
+// applyTheme is called when the application's theme changes.
+func (c *Calendar) applyTheme(from, to *Theme) {
+	c.Box.applyTheme(from, to)
+	themeColor(&c.headerColor, from.TitleColor, to.TitleColor)
+	themeColor(&c.weekdayColor, from.TertiaryTextColor, to.TertiaryTextColor)
+	themeColor(&c.dayColor, from.PrimaryTextColor, to.PrimaryTextColor)
+	themeColor(&c.highlightColor, from.SecondaryTextColor, to.SecondaryTextColor)
+	themeColor(&c.disabledColor, from.ContrastBackgroundColor, to.ContrastBackgroundColor)
+	themeColor(&c.selectedTextColor, from.PrimitiveBackgroundColor, to.PrimitiveBackgroundColor)
+	themeColor(&c.selectedBackgroundColor, from.PrimaryTextColor, to.PrimaryTextColor)
+}
+
+// Draw draws this primitive onto the screen.
+func (c *Calendar) Draw(screen tcell.Screen) {
+	defer c.applyStyle(&c.dayColor)()
+	c.Box.Draw(screen)
+	x, y, width, height := c.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	// Month and year, with arrows for paging.
+	Print(screen, "◀", x, y, width, AlignLeft, c.headerColor)
+	Print(screen, fmt.Sprintf("%s %d", c.date.Month(), c.date.Year()), x, y, width, AlignCenter, c.headerColor)
+	if width >= 20 {
+		Print(screen, "▶", x+19, y, 1, AlignLeft, c.headerColor)
+	}
+	if height < 2 {
+		return
+	}
+
+	// Weekday names.
+	for column := 0; column < 7; column++ {
+		weekday := time.Weekday((int(c.firstWeekday) + column) % 7)
+		Print(screen, weekday.String()[:2], x+column*3, y+1, width-column*3, AlignLeft, c.weekdayColor)
+	}
+
+	// The days.
+	date := c.firstCell()
+	for row := 0; row < 6 && row+2 < height; row++ {
+		for column := 0; column < 7; column++ {
+			if date.Month() == c.date.Month() {
+				color := c.dayColor
+				if !c.isSelectable(date) {
+					color = c.disabledColor
+				} else if c.highlights[calendarKey(date)] {
+					color = c.highlightColor
+				}
+				cellX := x + column*3
+				if date.Equal(c.date) {
+					color = c.selectedTextColor
+					style := tcell.StyleDefault.Background(c.selectedBackgroundColor)
+					for offset := 0; offset < 2 && cellX+offset < x+width; offset++ {
+						screen.SetContent(cellX+offset, y+2+row, ' ', nil, style)
+					}
+				}
+				Print(screen, fmt.Sprintf("%2d", date.Day()), cellX, y+2+row, x+width-cellX, AlignLeft, color)
+			}
+			date = date.AddDate(0, 0, 1)
+		}
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (c *Calendar) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return c.wrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		switch key := event.Key(); key {
+		case tcell.KeyLeft:
+			c.moveTo(c.date.AddDate(0, 0, -1))
+		case tcell.KeyRight:
+			c.moveTo(c.date.AddDate(0, 0, 1))
+		case tcell.KeyUp:
+			c.moveTo(c.date.AddDate(0, 0, -7))
+		case tcell.KeyDown:
+			c.moveTo(c.date.AddDate(0, 0, 7))
+		case tcell.KeyPgUp:
+			c.moveMonths(-1)
+		case tcell.KeyPgDn:
+			c.moveMonths(1)
+		case tcell.KeyHome:
+			c.moveTo(c.date.AddDate(0, 0, 1-c.date.Day()))
+		case tcell.KeyEnd:
+			c.moveTo(c.date.AddDate(0, 0, daysIn(c.date.Year(), c.date.Month())-c.date.Day()))
+		case tcell.KeyEnter:
+			c.selectDate()
+		case tcell.KeyEscape, tcell.KeyTab, tcell.KeyBacktab:
+			if c.done != nil {
+				c.done(key)
+			}
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'h':
+				c.moveTo(c.date.AddDate(0, 0, -1))
+			case 'l':
+				c.moveTo(c.date.AddDate(0, 0, 1))
+			case 'k':
+				c.moveTo(c.date.AddDate(0, 0, -7))
+			case 'j':
+				c.moveTo(c.date.AddDate(0, 0, 7))
+			case '[':
+				c.moveMonths(-12)
+			case ']':
+				c.moveMonths(12)
+			case 't':
+				c.moveTo(time.Now().In(c.date.Location()))
+			case ' ':
+				c.selectDate()
+			}
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (c *Calendar) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return c.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+		if !c.InRect(x, y) {
+			return false, nil
+		}
+		rectX, rectY, _, _ := c.GetInnerRect()
+		switch action {
+		case MouseLeftClick:
+			setFocus(c)
+			column, row := x-rectX, y-rectY
+			if row == 0 {
+				if column == 0 {
+					c.moveMonths(-1)
+				} else if column == 19 {
+					c.moveMonths(1)
+				}
+			} else if row >= 2 && row < 8 && column >= 0 && column < 20 && column%3 != 2 {
+				date := c.firstCell().AddDate(0, 0, (row-2)*7+column/3)
+				if date.Month() == c.date.Month() {
+					c.moveTo(date)
+					c.selectDate()
+				}
+			}
+		case MouseScrollUp:
+			c.moveMonths(-1)
+		case MouseScrollDown:
+			c.moveMonths(1)
+		default:
+			return false, nil
+		}
+		return true, nil
+	})
+}
@@ -0,0 +1,275 @@
+package tview
+
+import (
+	"github.com/gdamore/tcell"
+)
+
+// brailleBits maps a pixel's position within a cell (two columns, four rows)
+// to its dot in the Unicode braille pattern block.
+var brailleBits = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// canvasCell is one cell of a canvas' buffer.
+type canvasCell struct {
+	// The character in this cell. If this is a braille pattern, the cell
+	// holds pixels (see Canvas.SetPixel()).
+	ch rune
+
+	// The color of the character.
+	color tcell.Color
+}
+
+// canvasPoint is the position of a cell in a canvas' buffer.
+type canvasPoint struct {
+	x, y int
+}
+
+// Canvas is a primitive onto which arbitrary characters, lines, rectangles,
+// and text can be drawn. It is meant for custom visualizations such as
+// charts or maps which would otherwise require implementing a new primitive.
+//
+// Drawing happens on an offscreen buffer of unlimited size which is copied to
+// the screen when the canvas is drawn. Cell (0, 0) of the buffer appears in
+// the top-left corner of the canvas' inner rectangle unless an offset is set
+// with SetOffset(). Cells outside the inner rectangle are not shown.
+//
+// In addition to full cells, the canvas supports sub-cell "pixels" using
+// Unicode braille patterns. Each cell holds a grid of two by four pixels,
+// giving a resolution of twice the cell width and four times the cell height.
+// See SetPixel() for details.
+type Canvas struct {
+	*Box
+
+	// The offscreen buffer.
+	cells map[canvasPoint]canvasCell
+
+	// The buffer cell shown in the top-left corner of the inner rectangle.
+	offsetX, offsetY int
+}
+
+// NewCanvas returns a new, empty canvas.
+func NewCanvas() *Canvas {
+	return &Canvas{
+		Box:   NewBox(),
+		cells: make(map[canvasPoint]canvasCell),
+	}
+}
+
+// SetOffset sets the buffer cell which is shown in the top-left corner of the
+// canvas' inner rectangle. This can be used to scroll the canvas' content.
+func (c *Canvas) SetOffset(x, y int) *Canvas {
+	c.offsetX, c.offsetY = x, y
+	return c
+}
+
+// GetOffset returns the offset set with SetOffset().
+func (c *Canvas) GetOffset() (x, y int) {
+	return c.offsetX, c.offsetY
+}
+
+// Clear removes all content from the canvas.
+func (c *Canvas) Clear() *Canvas {
+	c.cells = make(map[canvasPoint]canvasCell)
+	return c
+}
+
+// SetCell sets the character and color of the cell at the given position.
+// A character of 0 (or a space) clears the cell.
+func (c *Canvas) SetCell(x, y int, ch rune, color tcell.Color) *Canvas {
+	if ch == 0 || ch == ' ' {
+		delete(c.cells, canvasPoint{x, y})
+		return c
+	}
+	c.cells[canvasPoint{x, y}] = canvasCell{ch: ch, color: color}
+	return c
+}
+
+// GetCell returns the character and color of the cell at the given position.
+// The character is 0 if the cell is empty.
+func (c *Canvas) GetCell(x, y int) (ch rune, color tcell.Color) {
+	cell := c.cells[canvasPoint{x, y}]
+	return cell.ch, cell.color
+}
+
+// DrawLine draws a straight line from (x1, y1) to (x2, y2), both inclusive,
+// using the given character and color.
+func (c *Canvas) DrawLine(x1, y1, x2, y2 int, ch rune, color tcell.Color) *Canvas {
+	bresenham(x1, y1, x2, y2, func(x, y int) {
+		c.SetCell(x, y, ch, color)
+	})
+	return c
+}
+
+// DrawRect draws the outline of a rectangle with the given top-left corner
+// and size, using box drawing characters in the given color.
+func (c *Canvas) DrawRect(x, y, width, height int, color tcell.Color) *Canvas {
+	if width <= 0 || height <= 0 {
+		return c
+	}
+	right, bottom := x+width-1, y+height-1
+	c.DrawLine(x, y, right, y, GraphicsHoriBar, color)
+	c.DrawLine(x, bottom, right, bottom, GraphicsHoriBar, color)
+	c.DrawLine(x, y, x, bottom, GraphicsVertBar, color)
+	c.DrawLine(right, y, right, bottom, GraphicsVertBar, color)
+	if width > 1 && height > 1 {
+		c.SetCell(x, y, GraphicsTopLeftCorner, color)
+		c.SetCell(right, y, GraphicsTopRightCorner, color)
+		c.SetCell(x, bottom, GraphicsBottomLeftCorner, color)
+		c.SetCell(right, bottom, GraphicsBottomRightCorner, color)
+	}
+	return c
+}
+
+// FillRect fills a rectangle with the given top-left corner and size with the
+// given character and color. A character of 0 (or a space) clears the area.
+func (c *Canvas) FillRect(x, y, width, height int, ch rune, color tcell.Color) *Canvas {
+	for row := y; row < y+height; row++ {
+		for column := x; column < x+width; column++ {
+			c.SetCell(column, row, ch, color)
+		}
+	}
+	return c
+}
+
+// DrawText writes the given text into the canvas starting at the given
+// position, in the given color. Color tags are not interpreted. Text is not
+// wrapped, newlines are ignored. Wide characters occupy two cells.
+func (c *Canvas) DrawText(x, y int, text string, color tcell.Color) *Canvas {
+	for _, ch := range text {
+		if ch == '\n' || ch == '\r' {
+			continue
+		}
+		width := RuneWidth(ch)
+		if width == 0 {
+			continue
+		}
+		c.SetCell(x, y, ch, color)
+		for extra := 1; extra < width; extra++ {
+			// Covered by the wide character.
+			delete(c.cells, canvasPoint{x + extra, y})
+		}
+		x += width
+	}
+	return c
+}
+
+// SetPixel switches on the braille pixel at the given pixel coordinates.
+// Pixel (x, y) is located in cell (x/2, y/4). All pixels of a cell share the
+// same color, the color of the last pixel set in it. Setting a pixel in a
+// cell which contains a regular character replaces that character.
+func (c *Canvas) SetPixel(x, y int, color tcell.Color) *Canvas {
+	point, bit := pixelPosition(x, y)
+	cell := c.cells[point]
+	if cell.ch < 0x2800 || cell.ch > 0x28ff {
+		cell.ch = 0x2800
+	}
+	cell.ch |= bit
+	cell.color = color
+	c.cells[point] = cell
+	return c
+}
+
+// ClearPixel switches off the braille pixel at the given pixel coordinates.
+func (c *Canvas) ClearPixel(x, y int) *Canvas {
+	point, bit := pixelPosition(x, y)
+	cell, ok := c.cells[point]
+	if !ok || cell.ch < 0x2800 || cell.ch > 0x28ff {
+		return c
+	}
+	cell.ch &^= bit
+	if cell.ch == 0x2800 {
+		delete(c.cells, point)
+	} else {
+		c.cells[point] = cell
+	}
+	return c
+}
+
+// GetPixel returns whether the braille pixel at the given pixel coordinates
+// is switched on.
+func (c *Canvas) GetPixel(x, y int) bool {
+	point, bit := pixelPosition(x, y)
+	cell := c.cells[point]
+	return cell.ch >= 0x2800 && cell.ch <= 0x28ff && cell.ch&bit != 0
+}
+
+// DrawPixelLine draws a straight line of braille pixels from (x1, y1) to
+// (x2, y2), both inclusive, given in pixel coordinates.
+func (c *Canvas) DrawPixelLine(x1, y1, x2, y2 int, color tcell.Color) *Canvas {
+	bresenham(x1, y1, x2, y2, func(x, y int) {
+		c.SetPixel(x, y, color)
+	})
+	return c
+}
+
+// GetPixelRect returns the size of the canvas' inner rectangle in braille
+// pixels. This is useful for scaling data to the available space.
+func (c *Canvas) GetPixelRect() (width, height int) {
+	_, _, width, height = c.GetInnerRect()
+	return width * 2, height * 4
+}
+
+// Draw draws this primitive onto the screen.
+func (c *Canvas) Draw(screen tcell.Screen) {
+	defer c.applyStyle()()
+	c.Box.Draw(screen)
+	x, y, width, height := c.GetInnerRect()
+	for row := 0; row < height; row++ {
+		for column := 0; column < width; column++ {
+			cell, ok := c.cells[canvasPoint{c.offsetX + column, c.offsetY + row}]
+			if !ok || column+RuneWidth(cell.ch) > width {
+				continue
+			}
+			style := tcell.StyleDefault.Foreground(cell.color).Background(c.backgroundColor)
+			screen.SetContent(x+column, y+row, cell.ch, nil, style)
+		}
+	}
+}
+
+// pixelPosition returns the cell containing the braille pixel at the given
+// pixel coordinates and the pixel's bit within the braille pattern.
+func pixelPosition(x, y int) (canvasPoint, rune) {
+	cellX, cellY := floorDiv(x, 2), floorDiv(y, 4)
+	return canvasPoint{cellX, cellY}, brailleBits[y-cellY*4][x-cellX*2]
+}
+
+// floorDiv divides a by b, rounding towards negative infinity.
+func floorDiv(a, b int) int {
+	if a < 0 {
+		return -((-a + b - 1) / b)
+	}
+	return a / b
+}
+
+// bresenham calls the given function for each point on the straight line from
+// (x1, y1) to (x2, y2).
+func bresenham(x1, y1, x2, y2 int, point func(x, y int)) {
+	dx, dy := x2-x1, y2-y1
+	stepX, stepY := 1, 1
+	if dx < 0 {
+		dx, stepX = -dx, -1
+	}
+	if dy < 0 {
+		dy, stepY = -dy, -1
+	}
+	err := dx - dy
+	for {
+		point(x1, y1)
+		if x1 == x2 && y1 == y2 {
+			return
+		}
+		e2 := 2 * err
+		if e2 > -dy {
+			err -= dy
+			x1 += stepX
+		}
+		if e2 < dx {
+			err += dx
+			y1 += stepY
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package tview
+
+import (
+	"github.com/gdamore/tcell"
+)
+
+// Center is a container which places a primitive of a given size at a chosen
+// position within the available space, e.g. in the center or in the top-right
+// corner. This replaces the common trick of nesting Flex primitives with empty
+// items to center a primitive.
+//
+// Sizes are specified as in SetSize(). If the primitive does not fit into the
+// available space, it is made smaller.
+//
+// Unless it has a border, the container does not fill its background, so it
+// can be used to show a primitive on top of others, e.g. as a page of Pages.
+type Center struct {
+	*Box
+
+	// The contained primitive.
+	item Primitive
+
+	// The primitive's width and height. See SetSize() for details.
+	width, height int
+
+	// The horizontal and vertical alignment of the primitive.
+	horizontal, vertical int
+}
+
+// NewCenter returns a new container which shows the given primitive with the
+// given size in its center. See SetSize() for details on sizes.
+func NewCenter(item Primitive, width, height int) *Center {
+	c := &Center{
+		Box:        NewBox(),
+		item:       item,
+		width:      width,
+		height:     height,
+		horizontal: AlignCenter,
+		vertical:   AlignCenter,
+	}
+	c.focus = c
+	return c
+}
+
+// SetItem sets the contained primitive.
+func (c *Center) SetItem(item Primitive) *Center {
+	c.item = item
+	return c
+}
+
+// GetItem returns the contained primitive.
+func (c *Center) GetItem() Primitive {
+	return c.item
+}
+
+// SetSize sets the width and height of the contained primitive. Positive
+// values are absolute sizes. A value of 0 means that the primitive takes all
+// available space in that direction. Negative values are percentages of the
+// available space, e.g. -50 for half of it.
+func (c *Center) SetSize(width, height int) *Center {
+	c.width, c.height = width, height
+	return c
+}
+
+// SetAlign sets the position of the contained primitive. "horizontal" is one
+// of AlignLeft, AlignCenter, or AlignRight and "vertical" is one of AlignTop,
+// AlignCenter, or AlignBottom. The default is to center the primitive in both
+// directions.
+func (c *Center) SetAlign(horizontal, vertical int) *Center {
+	c.horizontal, c.vertical = horizontal, vertical
+	return c
+}
+
+// place returns the position and size of a primitive with the given size and
+// alignment within the given available space.
+func place(size, align, position, available int) (int, int) {
+	if size == 0 || size > available {
+		size = available
+	} else if size < 0 {
+		size = available * -size / 100
+		if size > available {
+			size = available
+		}
+	}
+	switch align {
+	case AlignCenter:
+		position += (available - size) / 2
+	case AlignRight:
+		position += available - size
+	}
+	return position, size
+}
+
+// GetChildren returns the contained primitive.
+func (c *Center) GetChildren() []Primitive {
+	if c.item == nil {
+		return nil
+	}
+	return []Primitive{c.item}
+}
+
+// Draw draws this primitive onto the screen.
+func (c *Center) Draw(screen tcell.Screen) {
+	if c.border {
+		c.Box.Draw(screen)
+	}
+	if c.item == nil {
+		return
+	}
+	x, y, width, height := c.GetInnerRect()
+	x, width = place(c.width, c.horizontal, x, width)
+	y, height = place(c.height, c.vertical, y, height)
+	c.item.SetRect(x, y, width, height)
+	c.item.Draw(screen)
+}
+
+// Focus is called when this primitive receives focus.
+func (c *Center) Focus(delegate func(p Primitive)) {
+	if c.item != nil {
+		delegate(c.item)
+		return
+	}
+	c.hasFocus = true
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (c *Center) HasFocus() bool {
+	if c.item != nil {
+		return c.item.GetFocusable().HasFocus()
+	}
+	return c.hasFocus
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (c *Center) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return c.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if !c.InRect(event.Position()) || c.item == nil {
+			return false, nil
+		}
+		if handler := mouseHandler(c.item); handler != nil {
+			return handler(action, event, setFocus)
+		}
+		return
+	})
+}
@@ -1,22 +1,45 @@
 package tview
 
 import (
+	"strings"
+
 	"github.com/gdamore/tcell"
 )
 
+// CheckboxState is the state of a Checkbox.
+type CheckboxState int
+
+// Checkbox states.
+const (
+	CheckboxUnchecked     CheckboxState = iota // The box is not checked.
+	CheckboxChecked                            // The box is checked.
+	CheckboxIndeterminate                      // The box is partially checked, e.g. for "select all" over a partial selection.
+)
+
 // Checkbox implements a simple box for boolean values which can be checked and
-// unchecked.
+// unchecked. It can also be set to an indeterminate state (see SetState()).
 //
 // See https://github.com/rivo/tview/wiki/Checkbox for an example.
 type Checkbox struct {
 	*Box
 
-	// Whether or not this box is checked.
-	checked bool
+	// The state of the box.
+	state CheckboxState
+
+	// If true, the user cycles through all three states. Otherwise, the
+	// indeterminate state can only be set programmatically.
+	cycleIndeterminate bool
 
 	// The text to be displayed before the input area.
 	label string
 
+	// If true, the label is displayed after the box instead of before it.
+	labelAfter bool
+
+	// The strings shown in the box for the checked, unchecked, and
+	// indeterminate states.
+	checkedString, uncheckedString, indeterminateString string
+
 	// The label color.
 	labelColor tcell.Color
 
@@ -26,10 +49,17 @@ type Checkbox struct {
 	// The text color of the input area.
 	fieldTextColor tcell.Color
 
+	// The text attributes of the input area.
+	fieldAttributes tcell.AttrMask
+
 	// An optional function which is called when the user changes the checked
 	// state of this checkbox.
 	changed func(checked bool)
 
+	// An optional function which is called when the user changes the state of
+	// this checkbox.
+	stateChanged func(state CheckboxState)
+
 	// An optional function which is called when the user indicated that they
 	// are done entering text. The key which was pressed is provided (tab,
 	// shift-tab, or escape).
@@ -38,23 +68,50 @@ type Checkbox struct {
 
 // NewCheckbox returns a new input field.
 func NewCheckbox() *Checkbox {
+	styles := Styles.forWidget("Checkbox")
 	return &Checkbox{
 		Box:                  NewBox(),
-		labelColor:           Styles.SecondaryTextColor,
-		fieldBackgroundColor: Styles.ContrastBackgroundColor,
-		fieldTextColor:       Styles.PrimaryTextColor,
+		checkedString:        "X",
+		uncheckedString:      " ",
+		indeterminateString:  "-",
+		labelColor:           styles.SecondaryTextColor,
+		fieldBackgroundColor: styles.ContrastBackgroundColor,
+		fieldTextColor:       styles.PrimaryTextColor,
 	}
 }
 
 // SetChecked sets the state of the checkbox.
 func (c *Checkbox) SetChecked(checked bool) *Checkbox {
-	c.checked = checked
+	c.state = CheckboxUnchecked
+	if checked {
+		c.state = CheckboxChecked
+	}
 	return c
 }
 
-// IsChecked returns whether or not the box is checked.
+// IsChecked returns whether or not the box is checked. Indeterminate boxes are
+// not checked.
 func (c *Checkbox) IsChecked() bool {
-	return c.checked
+	return c.state == CheckboxChecked
+}
+
+// SetState sets the state of the checkbox, including the indeterminate state.
+func (c *Checkbox) SetState(state CheckboxState) *Checkbox {
+	c.state = state
+	return c
+}
+
+// GetState returns the state of the checkbox.
+func (c *Checkbox) GetState() CheckboxState {
+	return c.state
+}
+
+// SetCycleIndeterminate sets whether toggling the checkbox cycles through all
+// three states (unchecked, checked, indeterminate). By default, the user can
+// only check and uncheck the box and toggling an indeterminate box checks it.
+func (c *Checkbox) SetCycleIndeterminate(cycle bool) *Checkbox {
+	c.cycleIndeterminate = cycle
+	return c
 }
 
 // SetLabel sets the text to be displayed before the input area.
@@ -74,6 +131,32 @@ func (c *Checkbox) SetLabelColor(color tcell.Color) *Checkbox {
 	return c
 }
 
+// SetLabelAfter sets whether the label is displayed after the box, separated
+// by a space, instead of before it.
+func (c *Checkbox) SetLabelAfter(after bool) *Checkbox {
+	c.labelAfter = after
+	return c
+}
+
+// SetCheckedString sets the strings shown in the box for the checked,
+// unchecked, and indeterminate states. The defaults are "X", " ", and "-". The
+// strings may contain color tags, so square brackets must be escaped (see
+// Escape()), e.g. Escape("[✔]").
+func (c *Checkbox) SetCheckedString(checked, unchecked, indeterminate string) *Checkbox {
+	c.checkedString = checked
+	c.uncheckedString = unchecked
+	c.indeterminateString = indeterminate
+	return c
+}
+
+// SetFieldAttributes sets the text attributes of the box, e.g. tcell.AttrBold.
+// Together with SetFieldTextColor() and SetFieldBackgroundColor(), this styles
+// the box independently of the label.
+func (c *Checkbox) SetFieldAttributes(attributes tcell.AttrMask) *Checkbox {
+	c.fieldAttributes = attributes
+	return c
+}
+
 // SetFieldBackgroundColor sets the background color of the input area.
 func (c *Checkbox) SetFieldBackgroundColor(color tcell.Color) *Checkbox {
 	c.fieldBackgroundColor = color
@@ -98,7 +181,13 @@ func (c *Checkbox) SetFormAttributes(label string, labelColor, bgColor, fieldTex
 
 // GetFieldWidth returns this primitive's field width.
 func (c *Checkbox) GetFieldWidth() int {
-	return 1
+	width := 1
+	for _, text := range []string{c.checkedString, c.uncheckedString, c.indeterminateString} {
+		if w := TaggedStringWidth(text); w > width {
+			width = w
+		}
+	}
+	return width
 }
 
 // SetChangedFunc sets a handler which is called when the checked state of this
@@ -109,6 +198,15 @@ func (c *Checkbox) SetChangedFunc(handler func(checked bool)) *Checkbox {
 	return c
 }
 
+// SetStateChangedFunc sets a handler which is called when the state of this
+// checkbox was changed by the user. Unlike the handler set with
+// SetChangedFunc(), it receives the indeterminate state, too. If both handlers
+// are set, both are called.
+func (c *Checkbox) SetStateChangedFunc(handler func(state CheckboxState)) *Checkbox {
+	c.stateChanged = handler
+	return c
+}
+
 // SetDoneFunc sets a handler which is called when the user is done entering
 // text. The callback function is provided with the key that was pressed, which
 // is one of the following:
@@ -126,8 +224,25 @@ func (c *Checkbox) SetFinishedFunc(handler func(key tcell.Key)) FormItem {
 	return c.SetDoneFunc(handler)
 }
 
+// SetDisabled sets whether or not the checkbox is disabled. Disabled
+// checkboxes cannot be toggled and are skipped by Form navigation. See
+// Box.SetDisabled() for details.
+func (c *Checkbox) SetDisabled(disabled bool) *Checkbox {
+	c.Box.SetDisabled(disabled)
+	return c
+}
+
+// applyTheme is called when the application's theme changes.
+func (c *Checkbox) applyTheme(from, to *Theme) {
+	c.Box.applyTheme(from, to)
+	themeColor(&c.labelColor, from.SecondaryTextColor, to.SecondaryTextColor)
+	themeColor(&c.fieldBackgroundColor, from.ContrastBackgroundColor, to.ContrastBackgroundColor)
+	themeColor(&c.fieldTextColor, from.PrimaryTextColor, to.PrimaryTextColor)
+}
+
 // Draw draws this primitive onto the screen.
 func (c *Checkbox) Draw(screen tcell.Screen) {
+	defer c.applyStyle(&c.fieldTextColor)()
 	c.Box.Draw(screen)
 
 	// Prepare
@@ -138,19 +253,62 @@ func (c *Checkbox) Draw(screen tcell.Screen) {
 	}
 
 	// Draw label.
-	_, drawnWidth := Print(screen, c.label, x, y, rightLimit-x, AlignLeft, c.labelColor)
-	x += drawnWidth
+	if !c.labelAfter {
+		_, drawnWidth := Print(screen, c.bidiText(c.label), x, y, rightLimit-x, AlignLeft, c.labelColor)
+		x += drawnWidth
+	}
 
 	// Draw checkbox.
-	fieldStyle := tcell.StyleDefault.Background(c.fieldBackgroundColor).Foreground(c.fieldTextColor)
+	background, foreground := c.fieldBackgroundColor, c.fieldTextColor
 	if c.focus.HasFocus() {
-		fieldStyle = fieldStyle.Background(c.fieldTextColor).Foreground(c.fieldBackgroundColor)
+		background, foreground = foreground, background
+	}
+	text := c.uncheckedString
+	switch c.state {
+	case CheckboxChecked:
+		text = c.checkedString
+	case CheckboxIndeterminate:
+		text = c.indeterminateString
+	}
+	fieldWidth := c.GetFieldWidth()
+	if fieldWidth > rightLimit-x {
+		fieldWidth = rightLimit - x
+	}
+	fieldStyle := tcell.StyleDefault.Background(background)
+	for index := 0; index < fieldWidth; index++ {
+		screen.SetContent(x+index, y, ' ', nil, fieldStyle)
+	}
+	PrintStyled(screen, text, x, y, fieldWidth, AlignLeft, Style{
+		Foreground: foreground,
+		Background: background,
+		Attributes: c.fieldAttributes,
+	})
+	x += fieldWidth
+
+	// Draw label after the box.
+	if c.labelAfter && x+1 < rightLimit {
+		Print(screen, c.bidiText(strings.TrimSpace(c.label)), x+1, y, rightLimit-x-1, AlignLeft, c.labelColor)
+	}
+}
+
+// toggle advances the checkbox to its next state and calls the handlers.
+func (c *Checkbox) toggle() {
+	switch {
+	case c.state == CheckboxUnchecked:
+		c.state = CheckboxChecked
+	case c.state == CheckboxChecked && c.cycleIndeterminate:
+		c.state = CheckboxIndeterminate
+	case c.state == CheckboxIndeterminate && !c.cycleIndeterminate:
+		c.state = CheckboxChecked
+	default:
+		c.state = CheckboxUnchecked
+	}
+	if c.changed != nil {
+		c.changed(c.state == CheckboxChecked)
 	}
-	checkedRune := 'X'
-	if !c.checked {
-		checkedRune = ' '
+	if c.stateChanged != nil {
+		c.stateChanged(c.state)
 	}
-	screen.SetContent(x, y, checkedRune, nil, fieldStyle)
 }
 
 // InputHandler returns the handler for this primitive.
@@ -162,10 +320,7 @@ func (c *Checkbox) InputHandler() func(event *tcell.EventKey, setFocus func(p Pr
 			if key == tcell.KeyRune && event.Rune() != ' ' {
 				break
 			}
-			c.checked = !c.checked
-			if c.changed != nil {
-				c.changed(c.checked)
-			}
+			c.toggle()
 		case tcell.KeyTab, tcell.KeyBacktab, tcell.KeyEscape: // We're done.
 			if c.done != nil {
 				c.done(key)
@@ -0,0 +1,422 @@
+package tview
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell"
+)
+
+// The areas of a ColorPicker which can receive keyboard input.
+const (
+	colorPickerPalette = iota
+	colorPickerRed
+	colorPickerGreen
+	colorPickerBlue
+)
+
+// ColorPicker lets the user choose a color from the 256-color palette. If the
+// terminal supports more than 256 colors, three sliders for the red, green,
+// and blue components are shown below the palette, allowing the user to pick
+// any RGB color. A preview swatch next to the palette shows the current color.
+//
+// The following keys are available:
+//
+//   - Arrow keys, h, j, k, l: Move the cursor in the palette. Up and Down also
+//     move between the palette and the sliders.
+//   - Left arrow, right arrow (on a slider): Decrease/increase the value by 1.
+//   - Page up, page down (on a slider): Decrease/increase the value by 16.
+//   - Enter: Select the current color.
+//   - Escape, Tab, Backtab: Finish (see SetDoneFunc()).
+//
+// A color picker needs 44 columns and 16 rows of screen space (20 rows with
+// sliders), not including the box's border.
+type ColorPicker struct {
+	*Box
+
+	// The index of the palette color the cursor is on.
+	paletteIndex int
+
+	// The red, green, and blue components set with the sliders.
+	red, green, blue int32
+
+	// Whether the current color is an RGB color (set with the sliders) instead
+	// of a palette color.
+	rgb bool
+
+	// The area which receives keyboard input. One of the colorPicker
+	// constants.
+	area int
+
+	// Whether or not the sliders are shown. This is determined when drawing.
+	trueColor bool
+
+	// The color of labels and slider values.
+	labelColor tcell.Color
+
+	// The color of the sliders.
+	sliderColor tcell.Color
+
+	// An optional function which is called when the user changes the color.
+	changed func(color tcell.Color)
+
+	// An optional function which is called when the user selects a color.
+	selected func(color tcell.Color)
+
+	// An optional function which is called when the user presses Escape, Tab,
+	// or Backtab.
+	done func(key tcell.Key)
+}
+
+// NewColorPicker returns a new color picker with the cursor on the first
+// palette color.
+func NewColorPicker() *ColorPicker {
+	styles := Styles.forWidget("ColorPicker")
+	return &ColorPicker{
+		Box:         NewBox(),
+		labelColor:  styles.SecondaryTextColor,
+		sliderColor: styles.GraphicsColor,
+	}
+}
+
+// SetColor sets the current color. Palette colors move the palette cursor,
+// RGB colors are set on the sliders.
+func (c *ColorPicker) SetColor(color tcell.Color) *ColorPicker {
+	if color >= 0 && color < 256 {
+		c.paletteIndex = int(color)
+		c.rgb = false
+	} else if color&tcell.ColorIsRGB != 0 {
+		c.red, c.green, c.blue = color.RGB()
+		c.rgb = true
+	}
+	return c
+}
+
+// GetColor returns the current color.
+func (c *ColorPicker) GetColor() tcell.Color {
+	if c.rgb {
+		return tcell.NewRGBColor(c.red, c.green, c.blue)
+	}
+	return tcell.Color(c.paletteIndex)
+}
+
+// SetLabelColor sets the color of labels and slider values.
+func (c *ColorPicker) SetLabelColor(color tcell.Color) *ColorPicker {
+	c.labelColor = color
+	return c
+}
+
+// SetSliderColor sets the color of the sliders.
+func (c *ColorPicker) SetSliderColor(color tcell.Color) *ColorPicker {
+	c.sliderColor = color
+	return c
+}
+
+// SetChangedFunc sets a handler which is called when the user changes the
+// current color.
+func (c *ColorPicker) SetChangedFunc(handler func(color tcell.Color)) *ColorPicker {
+	c.changed = handler
+	return c
+}
+
+// SetSelectedFunc sets a handler which is called when the user selects a
+// color by pressing Enter or double-clicking on it.
+func (c *ColorPicker) SetSelectedFunc(handler func(color tcell.Color)) *ColorPicker {
+	c.selected = handler
+	return c
+}
+
+// SetDoneFunc sets a handler which is called when the user presses the
+// Escape, Tab, or Backtab key.
+func (c *ColorPicker) SetDoneFunc(handler func(key tcell.Key)) *ColorPicker {
+	c.done = handler
+	return c
+}
+
+// setPaletteIndex moves the palette cursor and makes the palette color the
+// current color.
+func (c *ColorPicker) setPaletteIndex(index int) {
+	if index < 0 || index > 255 || (index == c.paletteIndex && !c.rgb) {
+		return
+	}
+	c.paletteIndex = index
+	c.rgb = false
+	if c.changed != nil {
+		c.changed(c.GetColor())
+	}
+}
+
+// components returns the red, green, and blue components of the current
+// color.
+func (c *ColorPicker) components() [3]int32 {
+	if c.rgb {
+		return [3]int32{c.red, c.green, c.blue}
+	}
+	red, green, blue := tcell.Color(c.paletteIndex).RGB()
+	return [3]int32{red, green, blue}
+}
+
+// component returns a pointer to the color component of the given slider
+// area.
+func (c *ColorPicker) component(area int) *int32 {
+	switch area {
+	case colorPickerRed:
+		return &c.red
+	case colorPickerGreen:
+		return &c.green
+	case colorPickerBlue:
+		return &c.blue
+	}
+	return nil
+}
+
+// setComponent sets the value of the given slider and makes the RGB color the
+// current color.
+func (c *ColorPicker) setComponent(area int, value int32) {
+	component := c.component(area)
+	if component == nil {
+		return
+	}
+	if !c.rgb {
+		// Start with the palette color.
+		components := c.components()
+		c.red, c.green, c.blue = components[0], components[1], components[2]
+	}
+	if value < 0 {
+		value = 0
+	} else if value > 255 {
+		value = 255
+	}
+	*component = value
+	c.rgb = true
+	if c.changed != nil {
+		c.changed(c.GetColor())
+	}
+}
+
+// selectColor calls the "selected" handler with the current color.
+func (c *ColorPicker) selectColor() {
+	if c.selected != nil {
+		c.selected(c.GetColor())
+	}
+}
+
+// applyTheme is called when the application's theme changes.
+func (c *ColorPicker) applyTheme(from, to *Theme) {
+	c.Box.applyTheme(from, to)
+	themeColor(&c.labelColor, from.SecondaryTextColor, to.SecondaryTextColor)
+	themeColor(&c.sliderColor, from.GraphicsColor, to.GraphicsColor)
+}
+
+// Draw draws this primitive onto the screen.
+func (c *ColorPicker) Draw(screen tcell.Screen) {
+	defer c.applyStyle()()
+	c.Box.Draw(screen)
+	x, y, width, height := c.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+	c.trueColor = screen.Colors() > 256
+	if !c.trueColor && c.area != colorPickerPalette {
+		c.area = colorPickerPalette
+	}
+	hasFocus := c.HasFocus()
+
+	// The palette, 16 colors per row, two cells per color.
+	for index := 0; index < 256; index++ {
+		cellX, cellY := x+(index%16)*2, y+index/16
+		if cellY >= y+height || cellX+1 >= x+width {
+			continue
+		}
+		style := tcell.StyleDefault.Background(tcell.Color(index))
+		left, right := ' ', ' '
+		if index == c.paletteIndex && !c.rgb {
+			// Mark the cursor with a contrasting color.
+			r, g, b := tcell.Color(index).RGB()
+			if r*299+g*587+b*114 > 128000 {
+				style = style.Foreground(tcell.ColorBlack)
+			} else {
+				style = style.Foreground(tcell.ColorWhite)
+			}
+			left, right = '[', ']'
+			if !hasFocus || c.area != colorPickerPalette {
+				left, right = '<', '>'
+			}
+		}
+		screen.SetContent(cellX, cellY, left, nil, style)
+		screen.SetContent(cellX+1, cellY, right, nil, style)
+	}
+
+	// The preview swatch.
+	color := c.GetColor()
+	previewX := x + 34
+	for row := 0; row < 4 && row < height; row++ {
+		for column := 0; column < 10 && previewX+column < x+width; column++ {
+			screen.SetContent(previewX+column, y+row, ' ', nil, tcell.StyleDefault.Background(color))
+		}
+	}
+	r, g, b := color.RGB()
+	info := fmt.Sprintf("#%02x%02x%02x", r, g, b)
+	if !c.rgb {
+		info = fmt.Sprintf("%d", c.paletteIndex)
+	}
+	if height > 5 && previewX < x+width {
+		Print(screen, info, previewX, y+5, x+width-previewX, AlignLeft, c.labelColor)
+	}
+
+	// The sliders.
+	if !c.trueColor {
+		return
+	}
+	for index, value := range c.components() {
+		area := colorPickerRed + index
+		sliderY := y + 17 + index
+		if sliderY >= y+height {
+			break
+		}
+		label := " " + []string{"R", "G", "B"}[index]
+		if hasFocus && c.area == area {
+			label = "▶" + label[1:]
+		}
+		Print(screen, label, x, sliderY, width, AlignLeft, c.labelColor)
+		sliderWidth := 32
+		if x+3+sliderWidth > x+width {
+			sliderWidth = width - 3
+		}
+		position := int(value) * (sliderWidth - 1) / 255
+		for column := 0; column < sliderWidth; column++ {
+			ch := '─'
+			if column < position {
+				ch = '━'
+			} else if column == position {
+				ch = '●'
+			}
+			screen.SetContent(x+3+column, sliderY, ch, nil, tcell.StyleDefault.Foreground(c.sliderColor).Background(c.backgroundColor))
+		}
+		Print(screen, fmt.Sprintf("%3d", value), x+4+sliderWidth, sliderY, x+width-(x+4+sliderWidth), AlignLeft, c.labelColor)
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (c *ColorPicker) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return c.wrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		key := event.Key()
+		if key == tcell.KeyRune {
+			switch event.Rune() {
+			case 'h':
+				key = tcell.KeyLeft
+			case 'j':
+				key = tcell.KeyDown
+			case 'k':
+				key = tcell.KeyUp
+			case 'l':
+				key = tcell.KeyRight
+			case ' ':
+				key = tcell.KeyEnter
+			}
+		}
+
+		switch key {
+		case tcell.KeyEnter:
+			c.selectColor()
+			return
+		case tcell.KeyEscape, tcell.KeyTab, tcell.KeyBacktab:
+			if c.done != nil {
+				c.done(key)
+			}
+			return
+		}
+
+		// Sliders.
+		if c.area != colorPickerPalette {
+			value := c.components()[c.area-colorPickerRed]
+			switch key {
+			case tcell.KeyUp:
+				c.area--
+			case tcell.KeyDown:
+				if c.area < colorPickerBlue {
+					c.area++
+				}
+			case tcell.KeyLeft:
+				c.setComponent(c.area, value-1)
+			case tcell.KeyRight:
+				c.setComponent(c.area, value+1)
+			case tcell.KeyPgUp:
+				c.setComponent(c.area, value-16)
+			case tcell.KeyPgDn:
+				c.setComponent(c.area, value+16)
+			case tcell.KeyHome:
+				c.setComponent(c.area, 0)
+			case tcell.KeyEnd:
+				c.setComponent(c.area, 255)
+			}
+			return
+		}
+
+		// Palette.
+		switch key {
+		case tcell.KeyLeft:
+			if c.paletteIndex%16 > 0 {
+				c.setPaletteIndex(c.paletteIndex - 1)
+			}
+		case tcell.KeyRight:
+			if c.paletteIndex%16 < 15 {
+				c.setPaletteIndex(c.paletteIndex + 1)
+			}
+		case tcell.KeyUp:
+			c.setPaletteIndex(c.paletteIndex - 16)
+		case tcell.KeyDown:
+			if c.paletteIndex >= 240 && c.trueColor {
+				c.area = colorPickerRed
+			} else {
+				c.setPaletteIndex(c.paletteIndex + 16)
+			}
+		case tcell.KeyHome:
+			c.setPaletteIndex(0)
+		case tcell.KeyEnd:
+			c.setPaletteIndex(255)
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (c *ColorPicker) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return c.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+		if !c.InRect(x, y) {
+			return false, nil
+		}
+		rectX, rectY, _, _ := c.GetInnerRect()
+		column, row := x-rectX, y-rectY
+		switch action {
+		case MouseLeftDown, MouseLeftClick, MouseLeftDoubleClick:
+			setFocus(c)
+			if row >= 0 && row < 16 && column >= 0 && column < 32 {
+				c.area = colorPickerPalette
+				c.setPaletteIndex(row*16 + column/2)
+				if action == MouseLeftDoubleClick {
+					c.selectColor()
+				}
+			} else if c.trueColor && row >= 17 && row < 20 && column >= 3 && column < 35 {
+				c.area = colorPickerRed + row - 17
+				c.setComponent(c.area, int32((column-3)*255/31))
+			} else if action == MouseLeftDoubleClick && column >= 34 && column < 44 && row < 4 {
+				c.selectColor() // Double-click on the preview.
+			}
+		case MouseScrollUp, MouseScrollDown:
+			if !c.trueColor || row < 17 || row >= 20 {
+				return false, nil
+			}
+			area := colorPickerRed + row - 17
+			value := c.components()[area-colorPickerRed]
+			if action == MouseScrollUp {
+				value++
+			} else {
+				value--
+			}
+			c.setComponent(area, value)
+		default:
+			return false, nil
+		}
+		return true, nil
+	})
+}
@@ -0,0 +1,283 @@
+package tview
+
+import (
+	"github.com/gdamore/tcell"
+)
+
+// ContextMenu is a wrapper around another primitive which shows a popup Menu
+// on top of it. The menu opens at the mouse position when the user
+// right-clicks on the contained primitive. It can also be opened at arbitrary
+// screen coordinates by calling Open(), e.g. in response to a key press.
+//
+// While the menu is open, it receives all key and mouse events. It closes when
+// the user selects an item, presses Escape, or clicks outside of the menu. The
+// focus is then returned to the contained primitive. See Menu for the keys
+// available to navigate the menu.
+type ContextMenu struct {
+	*Box
+
+	// The contained primitive.
+	primitive Primitive
+
+	// The menu to be shown.
+	menu *Menu
+
+	// An optional function which returns the menu to be shown at the given
+	// screen position, overriding "menu".
+	menuFunc func(x, y int) *Menu
+
+	// The currently open menus.
+	popups menuPopups
+
+	// The screen position at which the menu was opened.
+	menuX, menuY int
+
+	// The colors of the menu.
+	menuColors menuColors
+
+	// An optional function which is called when the menu was closed.
+	done func(key tcell.Key)
+
+	// A function which sets the focus to a primitive.
+	setFocus func(p Primitive)
+}
+
+// NewContextMenu returns a new context menu around the given primitive. The
+// primitive's size will be changed to fit within the context menu's inner
+// rectangle.
+func NewContextMenu(primitive Primitive) *ContextMenu {
+	c := &ContextMenu{
+		Box:        NewBox(),
+		primitive:  primitive,
+		menuColors: defaultMenuColors(Styles.forWidget("ContextMenu")),
+	}
+	c.focus = c
+	return c
+}
+
+// SetMenu sets the menu which is shown when the context menu is opened.
+func (c *ContextMenu) SetMenu(menu *Menu) *ContextMenu {
+	c.menu = menu
+	return c
+}
+
+// SetMenuFunc sets a function which returns the menu to be shown when the
+// context menu is opened at the given screen position. This allows showing
+// different menus depending on what the user clicked on. If the function
+// returns nil, no menu is opened. If no such function is set, the menu
+// provided with SetMenu() is shown.
+func (c *ContextMenu) SetMenuFunc(handler func(x, y int) *Menu) *ContextMenu {
+	c.menuFunc = handler
+	return c
+}
+
+// SetMenuBackgroundColor sets the background color of the menu.
+func (c *ContextMenu) SetMenuBackgroundColor(color tcell.Color) *ContextMenu {
+	c.menuColors.backgroundColor = color
+	return c
+}
+
+// SetMenuBorderColor sets the color of the menu's border and separators.
+func (c *ContextMenu) SetMenuBorderColor(color tcell.Color) *ContextMenu {
+	c.menuColors.borderColor = color
+	return c
+}
+
+// SetMenuTextColor sets the text color of the menu items.
+func (c *ContextMenu) SetMenuTextColor(color tcell.Color) *ContextMenu {
+	c.menuColors.textColor = color
+	return c
+}
+
+// SetShortcutColor sets the color of the menu items' shortcut hints.
+func (c *ContextMenu) SetShortcutColor(color tcell.Color) *ContextMenu {
+	c.menuColors.shortcutColor = color
+	return c
+}
+
+// SetDisabledTextColor sets the text color of disabled menu items.
+func (c *ContextMenu) SetDisabledTextColor(color tcell.Color) *ContextMenu {
+	c.menuColors.disabledTextColor = color
+	return c
+}
+
+// SetSelectedTextColor sets the text color of the selected menu item.
+func (c *ContextMenu) SetSelectedTextColor(color tcell.Color) *ContextMenu {
+	c.menuColors.selectedTextColor = color
+	return c
+}
+
+// SetSelectedBackgroundColor sets the background color of the selected menu
+// item.
+func (c *ContextMenu) SetSelectedBackgroundColor(color tcell.Color) *ContextMenu {
+	c.menuColors.selectedBackgroundColor = color
+	return c
+}
+
+// SetDoneFunc sets a handler which is called when the menu was closed. The key
+// is tcell.KeyEnter if the user selected an item (the handler is called before
+// the item's own callback) and tcell.KeyEscape if the menu was dismissed.
+func (c *ContextMenu) SetDoneFunc(handler func(key tcell.Key)) *ContextMenu {
+	c.done = handler
+	return c
+}
+
+// Open opens the menu with its top-left corner at the given screen position.
+// If the context menu or its contained primitive has focus, the focus moves to
+// the menu. Nothing happens if there is no menu to be shown.
+func (c *ContextMenu) Open(x, y int) *ContextMenu {
+	menu := c.menu
+	if c.menuFunc != nil {
+		menu = c.menuFunc(x, y)
+	}
+	if menu == nil {
+		return c
+	}
+	hasFocus := c.HasFocus()
+	c.menuX, c.menuY = x, y
+	c.popups.open(menu)
+	if hasFocus && c.setFocus != nil {
+		c.setFocus(c)
+	}
+	return c
+}
+
+// Close closes the menu without calling the "done" handler.
+func (c *ContextMenu) Close() *ContextMenu {
+	if !c.popups.isOpen() {
+		return c
+	}
+	hasFocus := c.HasFocus()
+	c.popups.close()
+	if hasFocus && c.setFocus != nil {
+		c.setFocus(c)
+	}
+	return c
+}
+
+// IsOpen returns true if the menu is currently open.
+func (c *ContextMenu) IsOpen() bool {
+	return c.popups.isOpen()
+}
+
+// finish closes the menu and calls the "done" handler, followed by the given
+// callback (which may be nil).
+func (c *ContextMenu) finish(key tcell.Key, selected func()) {
+	c.Close()
+	if c.done != nil {
+		c.done(key)
+	}
+	if selected != nil {
+		selected()
+	}
+}
+
+// handleResult processes the result of an event handled by the open menus.
+func (c *ContextMenu) handleResult(result int, selected func()) {
+	switch result {
+	case menuClose:
+		c.finish(tcell.KeyEscape, nil)
+	case menuSelected:
+		c.finish(tcell.KeyEnter, selected)
+	}
+}
+
+// GetChildren returns the primitive which the context menu wraps.
+func (c *ContextMenu) GetChildren() []Primitive {
+	if c.primitive == nil {
+		return nil
+	}
+	return []Primitive{c.primitive}
+}
+
+// applyTheme is called when the application's theme changes.
+func (c *ContextMenu) applyTheme(from, to *Theme) {
+	c.Box.applyTheme(from, to)
+	c.menuColors.applyTheme(from, to)
+}
+
+// Draw draws this primitive onto the screen.
+func (c *ContextMenu) Draw(screen tcell.Screen) {
+	c.Box.Draw(screen)
+	if c.primitive != nil {
+		c.primitive.SetRect(c.GetInnerRect())
+		c.primitive.Draw(screen)
+	}
+	if c.popups.isOpen() {
+		c.popups.draw(screen, c.menuX, c.menuY, &c.menuColors)
+	}
+}
+
+// Focus is called when this primitive receives focus.
+func (c *ContextMenu) Focus(delegate func(p Primitive)) {
+	c.setFocus = delegate
+	if c.popups.isOpen() || c.primitive == nil {
+		c.Box.Focus(delegate)
+		return
+	}
+	delegate(c.primitive)
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (c *ContextMenu) HasFocus() bool {
+	if c.hasFocus {
+		return true
+	}
+	return c.primitive != nil && c.primitive.GetFocusable().HasFocus()
+}
+
+// drawsOutside returns whether a menu, which may extend beyond the context
+// menu's rectangle, is open.
+func (c *ContextMenu) drawsOutside() bool {
+	return c.popups.isOpen()
+}
+
+// InputHandler returns the handler for this primitive.
+func (c *ContextMenu) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return c.wrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		if c.popups.isOpen() {
+			c.handleResult(c.popups.input(event))
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (c *ContextMenu) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return c.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+
+		// While the menu is open, we capture all mouse events.
+		if c.popups.isOpen() {
+			result, selected, inside := c.popups.mouse(action, x, y)
+			if inside {
+				c.handleResult(result, selected)
+			} else {
+				switch action {
+				case MouseLeftClick, MouseMiddleClick, MouseRightClick:
+					c.finish(tcell.KeyEscape, nil)
+				}
+			}
+			if c.popups.isOpen() {
+				capture = c
+			}
+			return true, capture
+		}
+
+		if !c.InRect(x, y) {
+			return false, nil
+		}
+		if action == MouseRightClick {
+			setFocus(c)
+			c.Open(x, y)
+			if c.popups.isOpen() {
+				return true, c
+			}
+		}
+		if c.primitive != nil {
+			if handler := mouseHandler(c.primitive); handler != nil {
+				return handler(action, event, setFocus)
+			}
+		}
+		return false, nil
+	})
+}
@@ -0,0 +1,334 @@
+package tview
+
+import (
+	"time"
+
+	"github.com/gdamore/tcell"
+)
+
+// DateField is a one-line form item showing a date. When the user presses
+// Enter, the Space key, or the Down arrow, a Calendar pops up below the field
+// from which a new date can be selected.
+type DateField struct {
+	*Box
+
+	// The selected date. The zero value means no date was selected.
+	date time.Time
+
+	// The layout used to display the date, as accepted by time.Time.Format().
+	format string
+
+	// Set to true if the calendar is visible.
+	open bool
+
+	// The calendar popup.
+	calendar *Calendar
+
+	// The text to be displayed before the input area.
+	label string
+
+	// The label color.
+	labelColor tcell.Color
+
+	// The background color of the input area.
+	fieldBackgroundColor tcell.Color
+
+	// The text color of the input area.
+	fieldTextColor tcell.Color
+
+	// The screen width of the input area. A value of 0 means the width of the
+	// formatted date.
+	fieldWidth int
+
+	// An optional function which is called when the user selected a date.
+	changed func(date time.Time)
+
+	// An optional function which is called when the user indicated that they
+	// are done with this field. The key which was pressed is provided (tab,
+	// shift-tab, or escape).
+	done func(tcell.Key)
+}
+
+// NewDateField returns a new date field without a date.
+func NewDateField() *DateField {
+	styles := Styles.forWidget("DateField")
+	calendar := NewCalendar()
+	calendar.SetBorder(true).SetBackgroundColor(styles.MoreContrastBackgroundColor)
+
+	d := &DateField{
+		Box:                  NewBox(),
+		format:               "2006-01-02",
+		calendar:             calendar,
+		labelColor:           styles.SecondaryTextColor,
+		fieldBackgroundColor: styles.ContrastBackgroundColor,
+		fieldTextColor:       styles.PrimaryTextColor,
+	}
+
+	d.focus = d
+
+	return d
+}
+
+// SetDate sets the field's date. Use the zero value to clear the field.
+func (d *DateField) SetDate(date time.Time) *DateField {
+	d.date = date
+	if !date.IsZero() {
+		d.date = calendarDay(date)
+	}
+	return d
+}
+
+// GetDate returns the field's date. This is the zero value if no date was
+// selected yet.
+func (d *DateField) GetDate() time.Time {
+	return d.date
+}
+
+// SetFormat sets the layout used to display the date, as accepted by
+// time.Time.Format(). The default is "2006-01-02".
+func (d *DateField) SetFormat(format string) *DateField {
+	d.format = format
+	return d
+}
+
+// GetCalendar returns the calendar shown when the field is opened. It can be
+// used to change the calendar's colors, highlights, or selectable dates.
+func (d *DateField) GetCalendar() *Calendar {
+	return d.calendar
+}
+
+// SetLabel sets the text to be displayed before the input area.
+func (d *DateField) SetLabel(label string) *DateField {
+	d.label = label
+	return d
+}
+
+// GetLabel returns the text to be displayed before the input area.
+func (d *DateField) GetLabel() string {
+	return d.label
+}
+
+// SetLabelColor sets the color of the label.
+func (d *DateField) SetLabelColor(color tcell.Color) *DateField {
+	d.labelColor = color
+	return d
+}
+
+// SetFieldBackgroundColor sets the background color of the input area.
+func (d *DateField) SetFieldBackgroundColor(color tcell.Color) *DateField {
+	d.fieldBackgroundColor = color
+	return d
+}
+
+// SetFieldTextColor sets the text color of the input area.
+func (d *DateField) SetFieldTextColor(color tcell.Color) *DateField {
+	d.fieldTextColor = color
+	return d
+}
+
+// SetFormAttributes sets attributes shared by all form items.
+func (d *DateField) SetFormAttributes(label string, labelColor, bgColor, fieldTextColor, fieldBgColor tcell.Color) FormItem {
+	d.label = label
+	d.labelColor = labelColor
+	d.backgroundColor = bgColor
+	d.fieldTextColor = fieldTextColor
+	d.fieldBackgroundColor = fieldBgColor
+	return d
+}
+
+// SetFieldWidth sets the screen width of the input area. A value of 0 means
+// the width of a formatted date.
+func (d *DateField) SetFieldWidth(width int) *DateField {
+	d.fieldWidth = width
+	return d
+}
+
+// GetFieldWidth returns this primitive's field screen width.
+func (d *DateField) GetFieldWidth() int {
+	if d.fieldWidth > 0 {
+		return d.fieldWidth
+	}
+	return StringWidth(time.Date(2006, time.September, 30, 0, 0, 0, 0, time.UTC).Format(d.format))
+}
+
+// SetChangedFunc sets a handler which is called when the user selected a
+// date.
+func (d *DateField) SetChangedFunc(handler func(date time.Time)) *DateField {
+	d.changed = handler
+	return d
+}
+
+// SetDoneFunc sets a handler which is called when the user is done with this
+// field. The callback function is provided with the key that was pressed,
+// which is one of the following:
+//
+//   - KeyEscape: Abort selection.
+//   - KeyTab: Move to the next field.
+//   - KeyBacktab: Move to the previous field.
+func (d *DateField) SetDoneFunc(handler func(key tcell.Key)) *DateField {
+	d.done = handler
+	return d
+}
+
+// SetFinishedFunc calls SetDoneFunc().
+func (d *DateField) SetFinishedFunc(handler func(key tcell.Key)) FormItem {
+	return d.SetDoneFunc(handler)
+}
+
+// openCalendar shows the calendar popup and gives it focus.
+func (d *DateField) openCalendar(setFocus func(p Primitive)) {
+	d.open = true
+	if d.date.IsZero() {
+		d.calendar.SetDate(time.Now())
+	} else {
+		d.calendar.SetDate(d.date)
+	}
+	d.calendar.SetSelectedFunc(func(date time.Time) {
+		// A date was selected. Close the calendar again.
+		d.open = false
+		setFocus(d)
+		d.date = date
+
+		// Trigger "changed" event.
+		if d.changed != nil {
+			d.changed(date)
+		}
+	}).SetDoneFunc(func(key tcell.Key) {
+		d.open = false
+		setFocus(d)
+	})
+	setFocus(d.calendar)
+}
+
+// applyTheme is called when the application's theme changes.
+func (d *DateField) applyTheme(from, to *Theme) {
+	d.Box.applyTheme(from, to)
+	themeColor(&d.labelColor, from.SecondaryTextColor, to.SecondaryTextColor)
+	themeColor(&d.fieldBackgroundColor, from.ContrastBackgroundColor, to.ContrastBackgroundColor)
+	themeColor(&d.fieldTextColor, from.PrimaryTextColor, to.PrimaryTextColor)
+	d.calendar.applyTheme(from, to)
+	themeColor(&d.calendar.backgroundColor, from.MoreContrastBackgroundColor, to.MoreContrastBackgroundColor)
+}
+
+// Draw draws this primitive onto the screen.
+func (d *DateField) Draw(screen tcell.Screen) {
+	defer d.applyStyle(&d.fieldTextColor)()
+	d.Box.Draw(screen)
+
+	// Prepare.
+	x, y, width, height := d.GetInnerRect()
+	rightLimit := x + width
+	if height < 1 || rightLimit <= x {
+		return
+	}
+
+	// Draw label.
+	_, drawnWidth := Print(screen, d.bidiText(d.label), x, y, rightLimit-x, AlignLeft, d.labelColor)
+	x += drawnWidth
+
+	// Draw input area.
+	fieldWidth := d.GetFieldWidth()
+	if rightLimit-x < fieldWidth {
+		fieldWidth = rightLimit - x
+	}
+	fieldStyle := tcell.StyleDefault.Background(d.fieldBackgroundColor)
+	color := d.fieldTextColor
+	if d.GetFocusable().HasFocus() && !d.open {
+		fieldStyle = fieldStyle.Background(d.fieldTextColor)
+		color = d.fieldBackgroundColor
+	}
+	for index := 0; index < fieldWidth; index++ {
+		screen.SetContent(x+index, y, ' ', nil, fieldStyle)
+	}
+	if !d.date.IsZero() {
+		Print(screen, d.date.Format(d.format), x, y, fieldWidth, AlignLeft, color)
+	}
+
+	// Draw the calendar.
+	if d.HasFocus() && d.open {
+		// We prefer to drop down but if there is no space, maybe drop up?
+		cx, cy, cwidth, cheight := x, y+1, 22, 10
+		screenWidth, screenHeight := screen.Size()
+		if cy+cheight > screenHeight && y-cheight >= 0 {
+			cy = y - cheight
+		}
+		if cx+cwidth > screenWidth && screenWidth >= cwidth {
+			cx = screenWidth - cwidth
+		}
+		d.calendar.SetRect(cx, cy, cwidth, cheight)
+		d.calendar.Draw(screen)
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (d *DateField) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return d.wrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		// Process key event.
+		switch key := event.Key(); key {
+		case tcell.KeyEnter, tcell.KeyRune, tcell.KeyDown:
+			if key == tcell.KeyRune && event.Rune() != ' ' {
+				break
+			}
+			d.openCalendar(setFocus)
+		case tcell.KeyEscape, tcell.KeyTab, tcell.KeyBacktab:
+			if d.done != nil {
+				d.done(key)
+			}
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (d *DateField) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return d.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+
+		// While the calendar is open, we capture all mouse events.
+		if d.open {
+			if d.calendar.InRect(x, y) {
+				if handler := d.calendar.MouseHandler(); handler != nil {
+					handler(action, event, func(p Primitive) {})
+				}
+			} else if action == MouseLeftClick {
+				d.open = false
+				setFocus(d)
+			}
+			if d.open {
+				capture = d
+			}
+			return true, capture
+		}
+
+		if !d.InRect(x, y) {
+			return false, nil
+		}
+		if action == MouseLeftClick {
+			setFocus(d)
+			d.openCalendar(setFocus)
+			return true, d
+		}
+		return true, nil
+	})
+}
+
+// Focus is called by the application when the primitive receives focus.
+func (d *DateField) Focus(delegate func(p Primitive)) {
+	d.Box.Focus(delegate)
+	if d.open {
+		delegate(d.calendar)
+	}
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (d *DateField) HasFocus() bool {
+	if d.open {
+		return d.calendar.HasFocus()
+	}
+	return d.hasFocus
+}
+
+// drawsOutside returns whether the calendar, which extends below (or above)
+// the field, is shown.
+func (d *DateField) drawsOutside() bool {
+	return d.open
+}
@@ -0,0 +1,26 @@
+// Demo code for the Accordion primitive.
+package main
+
+import (
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+func main() {
+	app := tview.NewApplication().EnableMouse(true)
+	accordion := tview.NewAccordion().
+		AddSection("General", tview.NewTextView().SetText("Name: tview\nLicense: MIT"), 3, true).
+		AddSection("Files", tview.NewList().
+			AddItem("accordion.go", "", 0, nil).
+			AddItem("box.go", "", 0, nil).
+			AddItem("flex.go", "", 0, nil), 0, false).
+		AddSection("Notes", tview.NewTextView().SetText("Press Enter or Space on a header to expand or collapse it."), 0, false)
+	accordion.SetAnimation(200*time.Millisecond, func() { app.Draw() }).
+		SetBorder(true).
+		SetTitle("Accordion")
+	accordion.FocusHeaders()
+	if err := app.SetRoot(accordion, true).Run(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,13 @@
+// Demo code for the AspectRatio primitive.
+package main
+
+import "github.com/rivo/tview"
+
+func main() {
+	// A square board which stays square when the terminal is resized.
+	board := tview.NewBox().SetBorder(true).SetTitle("Square")
+	square := tview.NewAspectRatio(board, 1, 1)
+	if err := tview.NewApplication().SetRoot(square, true).Run(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,51 @@
+// Demo code for the Breadcrumb primitive.
+package main
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+func main() {
+	app := tview.NewApplication().EnableMouse(true)
+	breadcrumb := tview.NewBreadcrumb().
+		SetSegments("Home", "Projects", "tview", "demos", "breadcrumb")
+	list := tview.NewList().ShowSecondaryText(false)
+
+	// Fill the list with subdirectories of the current location.
+	var update func()
+	update = func() {
+		list.Clear()
+		depth := breadcrumb.GetSegmentCount()
+		for index := 1; index <= 3; index++ {
+			name := fmt.Sprintf("folder-%d-%d", depth, index)
+			list.AddItem(name, "", 0, func() {
+				breadcrumb.Push(name)
+				update()
+			})
+		}
+	}
+	update()
+
+	breadcrumb.SetNavigateFunc(func(index int, label string) {
+		breadcrumb.Truncate(index)
+		update()
+		app.SetFocus(list)
+	})
+	breadcrumb.SetDoneFunc(func(key tcell.Key) {
+		app.SetFocus(list)
+	})
+	list.SetDoneFunc(func() {
+		app.SetFocus(breadcrumb)
+	})
+
+	root := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(breadcrumb, 1, 0, false).
+		AddItem(list, 0, 1, true)
+	if err := app.SetRoot(root, true).Run(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,37 @@
+// Demo code for the Calendar primitive.
+package main
+
+import (
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+func main() {
+	app := tview.NewApplication().EnableMouse(true)
+	today := time.Now()
+	calendar := tview.NewCalendar().
+		SetFirstWeekday(time.Monday).
+		HighlightDate(today.AddDate(0, 0, 3), true).
+		HighlightDate(today.AddDate(0, 0, 10), true).
+		SetSelectableFunc(func(date time.Time) bool {
+			return date.Weekday() != time.Saturday && date.Weekday() != time.Sunday
+		}).
+		SetSelectedFunc(func(date time.Time) {
+			app.Stop()
+		})
+	calendar.SetBorder(true).SetTitle("Pick a weekday")
+
+	form := tview.NewForm().
+		AddDateField("Start date", today, nil).
+		AddDateField("End date", time.Time{}, nil).
+		AddButton("Quit", app.Stop)
+	form.SetBorder(true).SetTitle("Date fields")
+
+	flex := tview.NewFlex().
+		AddItem(calendar, 22, 0, true).
+		AddItem(form, 0, 1, false)
+	if err := app.SetRoot(flex, true).Run(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,44 @@
+// Demo code for the Canvas primitive.
+package main
+
+import (
+	"math"
+
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+func main() {
+	app := tview.NewApplication()
+	canvas := tview.NewCanvas()
+	canvas.SetBorder(true).SetTitle("Canvas")
+
+	// Plot a sine wave in braille pixels whenever the size changes.
+	var lastWidth, lastHeight int
+	canvas.SetDrawFunc(func(screen tcell.Screen, x, y, width, height int) (int, int, int, int) {
+		pixelWidth, pixelHeight := (width-2)*2, (height-2)*4
+		if pixelWidth == lastWidth && pixelHeight == lastHeight {
+			return x + 1, y + 1, width - 2, height - 2
+		}
+		lastWidth, lastHeight = pixelWidth, pixelHeight
+		canvas.Clear()
+		cellWidth, cellHeight := pixelWidth/2, pixelHeight/4
+		canvas.DrawRect(0, 0, cellWidth, cellHeight, tcell.ColorGray).
+			DrawLine(1, cellHeight/2, cellWidth-2, cellHeight/2, '·', tcell.ColorDarkGray).
+			DrawText(2, 0, " sin(x) ", tcell.ColorYellow)
+		previousX, previousY := -1, 0
+		for px := 2; px < pixelWidth-2; px++ {
+			angle := float64(px-2) / float64(pixelWidth-4) * 4 * math.Pi
+			py := pixelHeight/2 - int(math.Sin(angle)*float64(pixelHeight/2-6))
+			if previousX >= 0 {
+				canvas.DrawPixelLine(previousX, previousY, px, py, tcell.ColorGreen)
+			}
+			previousX, previousY = px, py
+		}
+		return x + 1, y + 1, width - 2, height - 2
+	})
+
+	if err := app.SetRoot(canvas, true).Run(); err != nil {
+		panic(err)
+	}
+}
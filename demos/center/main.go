@@ -0,0 +1,24 @@
+// Demo code for the Center primitive.
+package main
+
+import "github.com/rivo/tview"
+
+func main() {
+	app := tview.NewApplication()
+	form := tview.NewForm().
+		AddInputField("User", "", 20, nil, nil).
+		AddPasswordField("Password", "", 20, '*', nil).
+		AddButton("Login", func() {
+			app.Stop()
+		})
+	form.SetBorder(true).SetTitle("Login")
+
+	// A fixed-size form in the center, a note in the bottom-right corner.
+	note := tview.NewTextView().SetText("Press Ctrl-C to exit")
+	pages := tview.NewPages().
+		AddPage("note", tview.NewCenter(note, 20, 1).SetAlign(tview.AlignRight, tview.AlignBottom), true, true).
+		AddPage("form", tview.NewCenter(form, 40, 9), true, true)
+	if err := app.SetRoot(pages, true).Run(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,26 @@
+// Demo code for the ColorPicker primitive.
+package main
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+func main() {
+	app := tview.NewApplication().EnableMouse(true)
+	var selected tcell.Color
+	colorPicker := tview.NewColorPicker().
+		SetColor(tcell.ColorOrange).
+		SetSelectedFunc(func(color tcell.Color) {
+			selected = color
+			app.Stop()
+		})
+	colorPicker.SetBorder(true).SetTitle("Pick a color and press Enter")
+	if err := app.SetRoot(colorPicker, true).Run(); err != nil {
+		panic(err)
+	}
+	r, g, b := selected.RGB()
+	fmt.Printf("You selected #%02x%02x%02x\n", r, g, b)
+}
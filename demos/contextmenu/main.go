@@ -0,0 +1,45 @@
+// Demo code for the ContextMenu primitive.
+package main
+
+import (
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+func main() {
+	app := tview.NewApplication().EnableMouse(true)
+	textView := tview.NewTextView().
+		SetTextAlign(tview.AlignCenter).
+		SetText("\nRight-click anywhere or press Ctrl-O to open the context menu.")
+	report := func(text string) func() {
+		return func() {
+			textView.SetText("\nYou selected: " + text)
+		}
+	}
+	sortMenu := tview.NewMenu("Sort").
+		AddItem("By Name", "", report("Sort by name")).
+		AddItem("By Date", "", report("Sort by date")).
+		AddItem("By Size", "", report("Sort by size"))
+	menu := tview.NewMenu("Actions").
+		AddItem("Open", "Enter", report("Open")).
+		AddItem("Rename", "F2", report("Rename")).
+		AddItem("Delete", "Del", report("Delete")).
+		AddSeparator().
+		AddSubmenu("Sort", sortMenu).
+		AddSeparator().
+		AddItem("Properties", "", report("Properties")).
+		SetItemDisabled(6, true)
+	contextMenu := tview.NewContextMenu(textView).SetMenu(menu)
+	contextMenu.SetBorder(true).SetTitle("Context Menu Demo")
+	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyCtrlO {
+			x, y, _, _ := contextMenu.GetInnerRect()
+			contextMenu.Open(x+2, y+1)
+			return nil
+		}
+		return event
+	})
+	if err := app.SetRoot(contextMenu, true).Run(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,53 @@
+// Demo code for the Dialog primitive.
+package main
+
+import (
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+func main() {
+	app := tview.NewApplication()
+	pages := tview.NewPages()
+
+	background := tview.NewTextView().
+		SetText("Press Enter to open the dialog, Escape to quit.").
+		SetDoneFunc(func(key tcell.Key) {
+			if key == tcell.KeyEscape {
+				app.Stop()
+			} else {
+				pages.ShowPage("dialog")
+			}
+		})
+
+	form := tview.NewForm().
+		AddInputField("File name", "untitled.txt", 30, nil, nil).
+		AddCheckbox("Overwrite", false, nil)
+	dialog := tview.NewDialog(form).
+		AddButton("Save", 's', nil).
+		AddButton("Discard", 'd', nil).
+		AddButton("Cancel", 'c', nil).
+		SetDefaultButton(0).
+		SetSize(50, 11).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			pages.HidePage("dialog")
+		})
+	dialog.SetTitle("Save changes? (Alt+S/D/C)")
+	dialog.GetButton(1).SetBackgroundColorActivated(tcell.ColorRed)
+	form.SetCancelFunc(func() {
+		dialog.FocusButtons()
+	})
+
+	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if dialog.HasFocus() && dialog.HandleKey(event) {
+			return nil
+		}
+		return event
+	})
+
+	pages.AddPage("background", background, true, true).
+		AddPage("dialog", dialog, false, false)
+	if err := app.SetRoot(pages, true).Run(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,41 @@
+// Demo code for the DiffView primitive.
+package main
+
+import (
+	"github.com/rivo/tview"
+)
+
+const oldText = `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("Hello, world")
+	fmt.Println("Goodbye")
+}
+`
+
+const newText = `package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	fmt.Println("Hello, tview")
+	fmt.Println("Goodbye")
+	os.Exit(0)
+}
+`
+
+func main() {
+	app := tview.NewApplication()
+	diffView := tview.NewDiffView().
+		SetTexts(oldText, newText).
+		SetSplit(true)
+	diffView.SetBorder(true).SetTitle("main.go (s: split/unified, n/N: next/previous hunk)")
+	if err := app.SetRoot(diffView, true).Run(); err != nil {
+		panic(err)
+	}
+}
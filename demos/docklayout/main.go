@@ -0,0 +1,22 @@
+// Demo code for the DockLayout primitive.
+package main
+
+import "github.com/rivo/tview"
+
+func main() {
+	box := func(title string) *tview.Box {
+		return tview.NewBox().SetBorder(true).SetTitle(title)
+	}
+	status := tview.NewTextView().SetText(" Press Ctrl-C to exit")
+	status.SetBackgroundColor(tview.Styles.ContrastBackgroundColor)
+
+	dock := tview.NewDockLayout().
+		SetTop(box("Header"), 3).
+		SetBottom(status, 1).
+		SetLeft(box("Navigation"), 20).
+		SetRight(box("Details"), 30).
+		SetCenter(box("Main content"))
+	if err := tview.NewApplication().SetRoot(dock, true).Run(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,26 @@
+// Demo code for the FileBrowser primitive.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rivo/tview"
+)
+
+func main() {
+	app := tview.NewApplication().EnableMouse(true)
+	var selected string
+	fileBrowser := tview.NewFileBrowser().
+		SetSelectedFunc(func(path string, info os.FileInfo) {
+			selected = path
+			app.Stop()
+		})
+	fileBrowser.SetBorder(true).SetTitle("Select a file (. = hidden files, s = sort, / = filter)")
+	if err := app.SetRoot(fileBrowser, true).Run(); err != nil {
+		panic(err)
+	}
+	if selected != "" {
+		fmt.Println("You selected", selected)
+	}
+}
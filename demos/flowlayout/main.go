@@ -0,0 +1,21 @@
+// Demo code for the FlowLayout primitive.
+package main
+
+import "github.com/rivo/tview"
+
+func main() {
+	app := tview.NewApplication()
+	flow := tview.NewFlowLayout().SetGap(1, 1)
+	flow.SetBorder(true).SetTitle("Resize the terminal to see the tags reflow")
+	for _, tag := range []string{"go", "terminal", "ui", "widgets", "layout", "flexbox", "grid", "colors", "mouse", "keyboard", "unicode", "forms", "tables", "text"} {
+		button := tview.NewButton(tag)
+		flow.AddItem(button, len(tag)+4, 1, false)
+	}
+	quit := tview.NewButton("Quit").SetSelectedFunc(func() {
+		app.Stop()
+	})
+	flow.AddItem(quit, 8, 1, true)
+	if err := app.SetRoot(flow, true).EnableMouse(true).Run(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,33 @@
+// Demo code for the HelpOverlay primitive.
+package main
+
+import (
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+func main() {
+	app := tview.NewApplication()
+	textView := tview.NewTextView().SetText("Press F1 to show the key bindings.")
+	textView.SetBorder(true).SetTitle("Editor")
+
+	help := tview.NewHelpOverlay(textView).
+		AddBindings(
+			tview.KeyBinding{Group: "Navigation", Keys: "j, Down", Description: "Scroll down"},
+			tview.KeyBinding{Group: "Navigation", Keys: "k, Up", Description: "Scroll up"},
+			tview.KeyBinding{Group: "Navigation", Keys: "g, Home", Description: "Go to the top"},
+			tview.KeyBinding{Group: "Navigation", Keys: "G, End", Description: "Go to the bottom"},
+			tview.KeyBinding{Group: "Application", Keys: "F1", Description: "Show or hide this help"},
+			tview.KeyBinding{Group: "Application", Keys: "Ctrl-C", Description: "Quit"},
+		)
+
+	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if help.HandleKey(event) {
+			return nil
+		}
+		return event
+	})
+	if err := app.SetRoot(help, true).Run(); err != nil {
+		panic(err)
+	}
+}
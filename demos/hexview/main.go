@@ -0,0 +1,36 @@
+// Demo code for the HexView primitive.
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+func main() {
+	// Show the demo's own executable unless a file is given.
+	name := os.Args[0]
+	if len(os.Args) > 1 {
+		name = os.Args[1]
+	}
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	app := tview.NewApplication()
+	hexView := tview.NewHexView().
+		SetData(data).
+		SetEditable(true).
+		SetDoneFunc(func(key tcell.Key) {
+			app.Stop()
+		})
+	hexView.SetBorder(true).SetTitle(name)
+	if err := app.SetRoot(hexView, true).Run(); err != nil {
+		panic(err)
+	}
+}
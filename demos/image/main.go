@@ -0,0 +1,74 @@
+// Demo code for the Image primitive.
+package main
+
+import (
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
+	"os"
+
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+// gradient returns a colorful test image.
+func gradient() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 256, 128))
+	for y := 0; y < 128; y++ {
+		for x := 0; x < 256; x++ {
+			hue := float64(x) / 256 * 2 * math.Pi
+			light := 1 - float64(y)/128
+			img.Set(x, y, color.RGBA{
+				R: uint8(255 * light * (1 + math.Cos(hue)) / 2),
+				G: uint8(255 * light * (1 + math.Cos(hue-2*math.Pi/3)) / 2),
+				B: uint8(255 * light * (1 + math.Cos(hue+2*math.Pi/3)) / 2),
+				A: 255,
+			})
+		}
+	}
+	return img
+}
+
+func main() {
+	// Show the image given on the command line or a generated one.
+	img := gradient()
+	if len(os.Args) > 1 {
+		file, err := os.Open(os.Args[1])
+		if err != nil {
+			panic(err)
+		}
+		img, _, err = image.Decode(file)
+		file.Close()
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	app := tview.NewApplication()
+	view := tview.NewImage().SetImage(img)
+	view.SetBorder(true).SetTitle("Image (c: 256 colors, d: dithering, s: stretch)")
+	colors, dithering, stretch := 0, true, false
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'c':
+			if colors == 0 {
+				colors = 256
+			} else {
+				colors = 0
+			}
+			view.SetColors(colors)
+		case 'd':
+			dithering = !dithering
+			view.SetDithering(dithering)
+		case 's':
+			stretch = !stretch
+			view.SetStretch(stretch)
+		}
+		return event
+	})
+	if err := app.SetRoot(view, true).Run(); err != nil {
+		panic(err)
+	}
+}
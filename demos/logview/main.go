@@ -0,0 +1,41 @@
+// Demo code for the LogView primitive.
+package main
+
+import (
+	"log"
+	"math/rand"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+func main() {
+	app := tview.NewApplication()
+	logView := tview.NewLogView().
+		SetMaxEntries(500).
+		SetChangedFunc(func() {
+			app.Draw()
+		})
+	logView.SetBorder(true).SetTitle("Log (p: pause, +/-: level, /: search)")
+
+	// Standard library logging goes to the log view, too.
+	log.SetOutput(logView)
+	log.SetFlags(0)
+
+	// Produce some log entries.
+	go func() {
+		messages := []string{"Connection accepted", "Request handled", "Cache miss", "Retrying request", "Connection reset by peer"}
+		for {
+			time.Sleep(300 * time.Millisecond)
+			level := rand.Intn(4)
+			logView.Logf(level, "%s (id %d)", messages[rand.Intn(len(messages))], rand.Intn(1000))
+			if rand.Intn(10) == 0 {
+				log.Print("Heartbeat")
+			}
+		}
+	}()
+
+	if err := app.SetRoot(logView, true).Run(); err != nil {
+		panic(err)
+	}
+}
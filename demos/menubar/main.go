@@ -0,0 +1,67 @@
+// Demo code for the MenuBar primitive.
+package main
+
+import (
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+func main() {
+	app := tview.NewApplication().EnableMouse(true)
+	status := tview.NewTextView().
+		SetTextAlign(tview.AlignCenter).
+		SetText("\nPress Alt+F, Alt+E, or Alt+H, or click on a menu.")
+	status.SetBorder(true)
+	report := func(text string) func() {
+		return func() {
+			status.SetText("\nYou selected: " + text)
+		}
+	}
+
+	recent := tview.NewMenu("Recent").
+		AddItem("notes.txt", "", report("notes.txt")).
+		AddItem("todo.txt", "", report("todo.txt"))
+	file := tview.NewMenu("File").
+		AddItem("New", "Ctrl-N", report("New")).
+		AddItem("Open...", "Ctrl-O", report("Open")).
+		AddSubmenu("Open Recent", recent).
+		AddSeparator().
+		AddItem("Save", "Ctrl-S", report("Save")).
+		AddItem("Save As...", "", report("Save As")).
+		AddSeparator().
+		AddItem("Quit", "Ctrl-Q", app.Stop)
+	edit := tview.NewMenu("Edit").
+		AddItem("Undo", "Ctrl-Z", report("Undo")).
+		AddItem("Redo", "Ctrl-Y", report("Redo")).
+		AddSeparator().
+		AddItem("Cut", "Ctrl-X", report("Cut")).
+		AddItem("Copy", "Ctrl-C", report("Copy")).
+		AddItem("Paste", "Ctrl-V", report("Paste")).
+		SetItemDisabled(1, true)
+	help := tview.NewMenu("Help").
+		AddItem("About", "", report("About"))
+
+	menuBar := tview.NewMenuBar().
+		AddMenu(file).
+		AddMenu(edit).
+		AddMenu(help).
+		SetDoneFunc(func(key tcell.Key) {
+			app.SetFocus(status)
+		})
+
+	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if menuBar.HandleShortcut(event) {
+			app.SetFocus(menuBar)
+			return nil
+		}
+		return event
+	})
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(menuBar, 1, 0, false).
+		AddItem(status, 0, 1, true)
+	if err := app.SetRoot(flex, true).Run(); err != nil {
+		panic(err)
+	}
+}
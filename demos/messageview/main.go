@@ -0,0 +1,55 @@
+// Demo code for the MessageView primitive.
+package main
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+func main() {
+	app := tview.NewApplication()
+	messages := tview.NewMessageView().
+		SetChangedFunc(func() {
+			app.Draw()
+		})
+	messages.SetBorder(true).SetTitle("Chat")
+
+	// Some history from yesterday.
+	yesterday := time.Now().Add(-24 * time.Hour)
+	messages.AddMessage("Alice", "Did you see the new release?", yesterday, tview.AlignLeft).
+		AddMessage("Me", "Not yet. Anything interesting?", yesterday.Add(time.Minute), tview.AlignRight).
+		AddMessage("Alice", "Lots of new widgets. Have a look when you find the time, it's worth it.", yesterday.Add(2*time.Minute), tview.AlignLeft)
+
+	// Others keep talking.
+	go func() {
+		lines := []string{"Hey!", "How is it going?", "I'm trying out the message view. Scroll up and wait for new messages to arrive.", "Lunch?", "👍"}
+		for {
+			time.Sleep(2 * time.Second)
+			messages.AddMessage("Bob", lines[rand.Intn(len(lines))], time.Now(), tview.AlignLeft)
+		}
+	}()
+
+	input := tview.NewInputField().SetLabel("> ")
+	input.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter && input.GetText() != "" {
+			messages.AddMessage("Me", input.GetText(), time.Now(), tview.AlignRight).ScrollToEnd()
+			input.SetText("")
+		} else if key == tcell.KeyTab {
+			app.SetFocus(messages)
+		}
+	})
+	messages.SetDoneFunc(func(key tcell.Key) {
+		app.SetFocus(input)
+	})
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(messages, 0, 1, false).
+		AddItem(input, 1, 0, true)
+	if err := app.SetRoot(flex, true).Run(); err != nil {
+		panic(err)
+	}
+}
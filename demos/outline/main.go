@@ -0,0 +1,52 @@
+// Demo code for the Outline primitive.
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+func main() {
+	// Generate a document.
+	var lines []string
+	for chapter := 1; chapter <= 5; chapter++ {
+		lines = append(lines, fmt.Sprintf("# Chapter %d", chapter), "")
+		for section := 1; section <= 3; section++ {
+			lines = append(lines, fmt.Sprintf("## Section %d.%d", chapter, section), "")
+			for paragraph := 0; paragraph < 8; paragraph++ {
+				lines = append(lines, "Lorem ipsum dolor sit amet, consectetur adipiscing elit.")
+			}
+			lines = append(lines, "")
+		}
+	}
+	text := strings.Join(lines, "\n")
+
+	app := tview.NewApplication()
+	pager := tview.NewPager().SetText(text)
+	pager.SetBorder(true)
+	outline := tview.NewOutline().
+		AddMarkdownHeadings(text).
+		SetPager(pager)
+	outline.SetBorder(true).SetTitle("Contents")
+
+	outline.SetDoneFunc(func(key tcell.Key) {
+		app.SetFocus(pager)
+	})
+	pager.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyRune {
+			app.Stop()
+		} else {
+			app.SetFocus(outline)
+		}
+	})
+
+	flex := tview.NewFlex().
+		AddItem(outline, 24, 0, true).
+		AddItem(pager, 0, 1, false)
+	if err := app.SetRoot(flex, true).Run(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,41 @@
+// Demo code for the Pager primitive.
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+func main() {
+	// Show the file given on the command line or some generated text.
+	var text string
+	if len(os.Args) > 1 {
+		content, err := ioutil.ReadFile(os.Args[1])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		text = string(content)
+	} else {
+		var lines []string
+		for line := 1; line <= 500; line++ {
+			lines = append(lines, fmt.Sprintf("%4d\tThe quick brown fox jumps over the lazy dog. %s", line, strings.Repeat("Lorem ipsum dolor sit amet. ", line%10)))
+		}
+		text = strings.Join(lines, "\n")
+	}
+
+	app := tview.NewApplication()
+	pager := tview.NewPager().
+		SetText(text).
+		SetDoneFunc(func(key tcell.Key) {
+			app.Stop()
+		})
+	if err := app.SetRoot(pager, true).Run(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,28 @@
+// Demo code for the Responsive primitive.
+package main
+
+import "github.com/rivo/tview"
+
+func main() {
+	box := func(title string) tview.Primitive {
+		return tview.NewBox().SetBorder(true).SetTitle(title)
+	}
+	menu, main, sidebar := box("Menu"), box("Main content"), box("Sidebar")
+
+	// Stacked rows for narrow screens, three columns for wide screens.
+	rows := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(menu, 3, 1, false).
+		AddItem(main, 0, 1, false).
+		AddItem(sidebar, 5, 1, false)
+	columns := tview.NewFlex().
+		AddItem(menu, 20, 1, false).
+		AddItem(main, 0, 1, false).
+		AddItem(sidebar, 30, 1, false)
+
+	responsive := tview.NewResponsive().
+		AddLayout(rows, 0, 0).
+		AddLayout(columns, 100, 0)
+	if err := tview.NewApplication().SetRoot(responsive, true).Run(); err != nil {
+		panic(err)
+	}
+}
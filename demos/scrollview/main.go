@@ -0,0 +1,27 @@
+// Demo code for the ScrollView primitive.
+package main
+
+import (
+	"fmt"
+
+	"github.com/rivo/tview"
+)
+
+func main() {
+	app := tview.NewApplication()
+	form := tview.NewForm()
+	for index := 1; index <= 20; index++ {
+		form.AddInputField(fmt.Sprintf("Field %d", index), "", 30, nil, nil)
+	}
+	form.AddButton("Quit", func() {
+		app.Stop()
+	})
+
+	scrollView := tview.NewScrollView().
+		SetContent(form, true).
+		SetContentSize(60, 43)
+	scrollView.SetBorder(true).SetTitle("A form larger than the screen")
+	if err := app.SetRoot(scrollView, true).EnableMouse(true).Run(); err != nil {
+		panic(err)
+	}
+}
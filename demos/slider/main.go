@@ -0,0 +1,51 @@
+// Demo code for the Slider primitive.
+package main
+
+import (
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+func main() {
+	app := tview.NewApplication().EnableMouse(true)
+	flex := tview.NewFlex()
+	var sliders []tview.Primitive
+	for _, name := range []string{"Bass", "Mid", "Treble", "Volume"} {
+		slider := tview.NewSlider().
+			SetVertical(true).
+			SetLabel(name).
+			SetRange(0, 10, 0.5).
+			SetValue(5)
+		slider.SetBorder(true)
+		sliders = append(sliders, slider)
+		flex.AddItem(slider, 10, 0, len(sliders) == 1)
+	}
+	brightness := tview.NewSlider().
+		SetLabel("Brightness: ").
+		SetValue(80)
+	brightness.SetBorder(true)
+	sliders = append(sliders, brightness)
+
+	root := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(flex, 0, 1, true).
+		AddItem(brightness, 3, 0, false)
+
+	// Tab moves between the sliders.
+	current := 0
+	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyTab:
+			current = (current + 1) % len(sliders)
+		case tcell.KeyBacktab:
+			current = (current - 1 + len(sliders)) % len(sliders)
+		default:
+			return event
+		}
+		app.SetFocus(sliders[current])
+		return nil
+	})
+	if err := app.SetRoot(root, true).Run(); err != nil {
+		panic(err)
+	}
+}
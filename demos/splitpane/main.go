@@ -0,0 +1,42 @@
+// Demo code for the SplitPane primitive.
+package main
+
+import (
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+func main() {
+	app := tview.NewApplication().EnableMouse(true)
+
+	list := tview.NewList().
+		AddItem("Inbox", "", 'i', nil).
+		AddItem("Sent", "", 's', nil).
+		AddItem("Drafts", "", 'd', nil)
+	list.SetBorder(true).SetTitle("Folders")
+	preview := tview.NewTextView().SetText("Drag the dividers with the mouse or press Ctrl-D to focus the outer divider.")
+	preview.SetBorder(true).SetTitle("Preview")
+	messages := tview.NewTextView().SetText("No messages.")
+	messages.SetBorder(true).SetTitle("Messages")
+
+	right := tview.NewSplitPane(messages, preview).
+		SetDirection(tview.FlexRow).
+		SetMinSizes(3, 3)
+	split := tview.NewSplitPane(list, right).
+		SetRatio(0.3).
+		SetMinSizes(10, 20)
+	split.SetDoneFunc(func(key tcell.Key) {
+		app.SetFocus(split)
+	})
+
+	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyCtrlD {
+			split.FocusDivider()
+			return nil
+		}
+		return event
+	})
+	if err := app.SetRoot(split, true).Run(); err != nil {
+		panic(err)
+	}
+}
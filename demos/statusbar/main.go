@@ -0,0 +1,45 @@
+// Demo code for the StatusBar primitive.
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+func main() {
+	app := tview.NewApplication()
+	textView := tview.NewTextView().
+		SetTextAlign(tview.AlignCenter).
+		SetText("\nPress Ctrl-S to save or Ctrl-X to exit.")
+	statusBar := tview.NewStatusBar().
+		AddKeyHint("^S", "Save").
+		AddKeyHint("^X", "Exit").
+		SetText(tview.AlignCenter, "demo.txt").
+		SetText(tview.AlignRight, "Ln 1, Col 1").
+		SetChangedFunc(func() {
+			app.Draw()
+		})
+	saved := 0
+	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyCtrlS:
+			saved++
+			statusBar.ShowMessage(fmt.Sprintf("File saved (%d times)", saved), 2*time.Second)
+			return nil
+		case tcell.KeyCtrlX:
+			app.Stop()
+			return nil
+		}
+		return event
+	})
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(textView, 0, 1, true).
+		AddItem(statusBar, 1, 0, false)
+	if err := app.SetRoot(flex, true).Run(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,45 @@
+// Demo code for the StructuredDataView primitive.
+package main
+
+import (
+	"github.com/rivo/tview"
+)
+
+const document = `{
+  "apiVersion": "v1",
+  "kind": "Pod",
+  "metadata": {
+    "name": "web",
+    "labels": {"app": "web", "tier": "frontend"}
+  },
+  "spec": {
+    "containers": [
+      {"name": "nginx", "image": "nginx:1.15", "ports": [{"containerPort": 80}]},
+      {"name": "sidecar", "image": "busybox", "command": ["sleep", "3600"]}
+    ],
+    "restartPolicy": "Always",
+    "hostNetwork": false,
+    "nodeName": null
+  }
+}`
+
+func main() {
+	app := tview.NewApplication()
+	copied := tview.NewTextView()
+	view := tview.NewStructuredDataView().
+		SetCopyFunc(func(path, value string) {
+			copied.SetText("Copied " + path + ": " + value)
+		})
+	if err := view.SetJSON(document); err != nil {
+		panic(err)
+	}
+	view.SetBorder(true).SetTitle("pod.json (/: search, y: copy)")
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(view, 0, 1, true).
+		AddItem(copied, 1, 0, false)
+	if err := app.SetRoot(flex, true).Run(); err != nil {
+		panic(err)
+	}
+}
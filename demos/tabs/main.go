@@ -0,0 +1,39 @@
+// Demo code for the Tabs primitive.
+package main
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+func main() {
+	app := tview.NewApplication().EnableMouse(true)
+	tabs := tview.NewTabs()
+	tabs.SetBorder(true).SetTitle("Tabs Demo")
+	for index := 1; index <= 8; index++ {
+		name := fmt.Sprintf("tab%d", index)
+		text := tview.NewTextView().
+			SetTextAlign(tview.AlignCenter).
+			SetText(fmt.Sprintf("\nThis is tab number %d.\n\nPress Ctrl-N/Ctrl-P to switch tabs, Ctrl-T to focus the tab bar.", index))
+		tabs.AddTab(name, fmt.Sprintf("Tab %d", index), text, index > 1)
+	}
+	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyCtrlN:
+			tabs.NextTab()
+			return nil
+		case tcell.KeyCtrlP:
+			tabs.PreviousTab()
+			return nil
+		case tcell.KeyCtrlT:
+			tabs.FocusTabBar()
+			return nil
+		}
+		return event
+	})
+	if err := app.SetRoot(tabs, true).Run(); err != nil {
+		panic(err)
+	}
+}
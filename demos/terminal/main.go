@@ -0,0 +1,41 @@
+// Demo code for the Terminal primitive.
+package main
+
+import (
+	"os"
+	"os/exec"
+
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+func main() {
+	app := tview.NewApplication()
+	terminal := tview.NewTerminal()
+	terminal.SetBorder(true).SetTitle("Terminal (Ctrl-Q to quit)")
+	terminal.SetChangedFunc(func() {
+		app.Draw()
+	}).SetExitedFunc(func(err error) {
+		app.Stop()
+	})
+
+	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyCtrlQ {
+			app.Stop()
+			return nil
+		}
+		return event
+	})
+
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+	if err := terminal.Start(exec.Command(shell)); err != nil {
+		panic(err)
+	}
+	defer terminal.Close()
+	if err := app.SetRoot(terminal, true).Run(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,27 @@
+// Demo code for the TextArea primitive.
+package main
+
+import (
+	"fmt"
+
+	"github.com/rivo/tview"
+)
+
+func main() {
+	app := tview.NewApplication().EnableMouse(true)
+	textArea := tview.NewTextArea().
+		SetPlaceholder("Enter text here...")
+	textArea.SetBorder(true).SetTitle("Text Area (Ctrl-Z undo, Ctrl-X/Ctrl-Q/Ctrl-V cut/copy/paste)")
+	status := tview.NewTextView()
+	textArea.SetChangedFunc(func() {
+		text := textArea.GetText()
+		status.SetText(fmt.Sprintf("%d characters", len([]rune(text))))
+	})
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(textArea, 0, 1, true).
+		AddItem(status, 1, 0, false)
+	if err := app.SetRoot(flex, true).Run(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,47 @@
+// Demo code for the WindowManager primitive.
+package main
+
+import (
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+func main() {
+	app := tview.NewApplication().EnableMouse(true)
+	manager := tview.NewWindowManager()
+
+	notes := tview.NewTextView().
+		SetText("Drag the title bar to move this window and its bottom-right corner to resize it. Press Ctrl-W to move, resize, and switch windows with the keyboard.").
+		SetWordWrap(true)
+	notesWindow := tview.NewWindow(notes).SetPosition(2, 1).SetSize(40, 10)
+	notesWindow.SetTitle("Notes")
+
+	list := tview.NewList().
+		AddItem("First item", "", 'a', nil).
+		AddItem("Second item", "", 'b', nil).
+		AddItem("Quit", "", 'q', func() {
+			app.Stop()
+		})
+	listWindow := tview.NewWindow(list).SetPosition(30, 6).SetSize(30, 12)
+	listWindow.SetTitle("List")
+
+	form := tview.NewForm().
+		AddInputField("Name", "", 20, nil, nil).
+		AddCheckbox("Subscribe", false, nil)
+	formWindow := tview.NewWindow(form).SetPosition(10, 12).SetSize(40, 9)
+	formWindow.SetTitle("Form")
+
+	manager.AddWindow(notesWindow).
+		AddWindow(listWindow).
+		AddWindow(formWindow)
+
+	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if manager.HandleKey(event) {
+			return nil
+		}
+		return event
+	})
+	if err := app.SetRoot(manager, true).Run(); err != nil {
+		panic(err)
+	}
+}
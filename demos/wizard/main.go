@@ -0,0 +1,54 @@
+// Demo code for the Wizard primitive.
+package main
+
+import (
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+func main() {
+	app := tview.NewApplication()
+	wizard := tview.NewWizard()
+
+	welcome := tview.NewTextView().
+		SetText("This wizard installs nothing at all.\n\nPress Tab to reach the buttons.")
+	welcome.SetDoneFunc(func(key tcell.Key) {
+		wizard.FocusButtons()
+	})
+
+	var name string
+	form := tview.NewForm().
+		AddInputField("Name", "", 20, nil, func(text string) {
+			name = text
+		})
+	form.SetCancelFunc(func() {
+		wizard.FocusButtons()
+	})
+	form.AddButton("Continue", func() {
+		wizard.Next()
+	})
+
+	summary := tview.NewTextView()
+
+	wizard.SetBorder(true).SetTitle("Setup")
+	wizard.AddStep("Welcome", welcome, nil).
+		AddStep("Your name", form, func() bool {
+			return name != ""
+		}).
+		AddStep("Summary", summary, nil).
+		SetChangedFunc(func(index int, title string) {
+			if index == 2 {
+				summary.SetText("Hello, " + name + "!")
+			}
+		}).
+		SetFinishedFunc(func() {
+			app.Stop()
+		}).
+		SetCancelFunc(func() {
+			app.Stop()
+		})
+
+	if err := app.SetRoot(wizard, true).Run(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,405 @@
+package tview
+
+import (
+	"unicode"
+
+	"github.com/gdamore/tcell"
+)
+
+// dialogButton is one button of a Dialog.
+type dialogButton struct {
+	button   *Button // The button primitive.
+	shortcut rune    // The shortcut rune, 0 if there is none.
+	selected func()  // An optional function which is called when the button is pressed.
+}
+
+// Dialog is a centered window which shows an arbitrary primitive (e.g. a form
+// or a text view) above a row of buttons. Unlike Modal, which only shows a
+// message text, the content, the buttons, and the dialog's size can be freely
+// configured.
+//
+// Buttons are added with AddButton(). Each button can have a shortcut rune.
+// When a button has focus, typing a shortcut rune presses the corresponding
+// button. To make shortcuts available while the content has focus, pass key
+// events to HandleKey(), e.g. from Application.SetInputCapture(). It presses
+// a button when its shortcut is typed together with the Alt key.
+//
+// When the dialog receives focus, it is passed on to the default button (see
+// SetDefaultButton()) or, if there is none, to the content. Call
+// FocusButtons() (e.g. from the "done" handler of the content) to move the
+// focus to the buttons. Tab and Backtab move between the buttons and the
+// content. Escape on a button invokes the "done" handler with a negative
+// button index.
+//
+// Like Modal, the dialog positions itself in the center of the screen. It is
+// usually shown on top of other primitives with Pages.
+type Dialog struct {
+	*Box
+
+	// The dialog's content, may be nil.
+	content Primitive
+
+	// The buttons.
+	buttons []*dialogButton
+
+	// The index of the default button, -1 if there is none.
+	defaultButton int
+
+	// The index of the button which should receive focus when this primitive
+	// receives focus, -1 for the content, -2 for the default button.
+	focusButton int
+
+	// The alignment of the buttons.
+	buttonsAlign int
+
+	// The dialog's width and height. Values of 0 or less are relative to the
+	// screen's size (see SetSize()).
+	width, height int
+
+	// We keep a reference to the function which allows us to set the focus.
+	setFocus func(p Primitive)
+
+	// An optional function which is called when a button was pressed or when
+	// the user pressed Escape on a button.
+	done func(buttonIndex int, buttonLabel string)
+}
+
+// NewDialog returns a new dialog showing the given content primitive (which
+// may be nil). It has a border by default.
+func NewDialog(content Primitive) *Dialog {
+	styles := Styles.forWidget("Dialog")
+	d := &Dialog{
+		Box:           NewBox().SetBackgroundColor(styles.ContrastBackgroundColor),
+		content:       content,
+		defaultButton: -1,
+		focusButton:   -2,
+		buttonsAlign:  AlignCenter,
+	}
+	d.SetBorder(true)
+	d.focus = d
+	return d
+}
+
+// SetContent sets the primitive shown above the buttons. It may be nil.
+func (d *Dialog) SetContent(content Primitive) *Dialog {
+	d.content = content
+	return d
+}
+
+// GetContent returns the primitive shown above the buttons.
+func (d *Dialog) GetContent() Primitive {
+	return d.content
+}
+
+// AddButton adds a button with the given label to the dialog. If "shortcut"
+// is not 0, typing this rune (case-insensitive) presses the button (see the
+// Dialog description). The "selected" function, which may be nil, is called
+// when the button is pressed, followed by the "done" handler.
+func (d *Dialog) AddButton(label string, shortcut rune, selected func()) *Dialog {
+	index := len(d.buttons)
+	button := NewButton(label).
+		SetSelectedFunc(func() {
+			d.press(index)
+		}).
+		SetBlurFunc(func(key tcell.Key) {
+			d.blurButton(index, key)
+		})
+	button.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyRune {
+			if index := d.findShortcut(event.Rune()); index >= 0 {
+				d.press(index)
+				return nil
+			}
+		}
+		return event
+	})
+	d.buttons = append(d.buttons, &dialogButton{button: button, shortcut: shortcut, selected: selected})
+	return d
+}
+
+// GetButton returns the button with the given index, e.g. to change its
+// colors. Buttons are numbered in the order in which they were added.
+func (d *Dialog) GetButton(index int) *Button {
+	return d.buttons[index].button
+}
+
+// GetButtonCount returns the number of buttons.
+func (d *Dialog) GetButtonCount() int {
+	return len(d.buttons)
+}
+
+// ClearButtons removes all buttons.
+func (d *Dialog) ClearButtons() *Dialog {
+	d.buttons = nil
+	d.defaultButton = -1
+	return d
+}
+
+// SetDefaultButton sets the index of the button which receives focus when the
+// dialog receives focus. A negative value means that the content receives
+// focus.
+func (d *Dialog) SetDefaultButton(index int) *Dialog {
+	d.defaultButton = index
+	return d
+}
+
+// SetButtonsAlign sets how the buttons align horizontally, one of AlignLeft,
+// AlignCenter (the default), or AlignRight.
+func (d *Dialog) SetButtonsAlign(align int) *Dialog {
+	d.buttonsAlign = align
+	return d
+}
+
+// SetSize sets the dialog's size, including its border. Positive values are
+// absolute sizes. Values of 0 or less are relative to the screen size: 0 is
+// one half of the screen's width or height, -1 one third, -2 one fourth, and
+// so on. The dialog is always at least wide enough for its buttons and never
+// larger than the screen. The default is one half of the screen in both
+// directions.
+func (d *Dialog) SetSize(width, height int) *Dialog {
+	d.width, d.height = width, height
+	return d
+}
+
+// SetDoneFunc sets a handler which is called when one of the buttons was
+// pressed. It receives the index of the button as well as its label text. The
+// handler is also called when the user presses the Escape key on a button. The
+// index will then be negative and the label text an empty string.
+func (d *Dialog) SetDoneFunc(handler func(buttonIndex int, buttonLabel string)) *Dialog {
+	d.done = handler
+	return d
+}
+
+// FocusButtons causes the default button (or the first button if there is no
+// default button) to receive focus the next time this primitive receives
+// focus. If it already has focus, the button receives focus immediately.
+func (d *Dialog) FocusButtons() *Dialog {
+	if len(d.buttons) == 0 {
+		return d
+	}
+	d.focusButton = d.defaultButton
+	if d.focusButton < 0 || d.focusButton >= len(d.buttons) {
+		d.focusButton = 0
+	}
+	if d.setFocus != nil && d.HasFocus() {
+		d.setFocus(d)
+	}
+	return d
+}
+
+// HandleKey presses the button whose shortcut was typed together with the Alt
+// key and returns true. If the event does not match any shortcut, false is
+// returned. Call this function from Application.SetInputCapture() while the
+// dialog is visible:
+//
+//   app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+//     if dialog.HandleKey(event) {
+//       return nil
+//     }
+//     return event
+//   })
+func (d *Dialog) HandleKey(event *tcell.EventKey) bool {
+	if event.Key() != tcell.KeyRune || event.Modifiers()&tcell.ModAlt == 0 {
+		return false
+	}
+	index := d.findShortcut(event.Rune())
+	if index < 0 {
+		return false
+	}
+	d.press(index)
+	return true
+}
+
+// findShortcut returns the index of the button with the given shortcut rune
+// or -1 if there is no such button.
+func (d *Dialog) findShortcut(r rune) int {
+	r = unicode.ToLower(r)
+	for index, button := range d.buttons {
+		if button.shortcut != 0 && unicode.ToLower(button.shortcut) == r {
+			return index
+		}
+	}
+	return -1
+}
+
+// press presses the button with the given index.
+func (d *Dialog) press(index int) {
+	button := d.buttons[index]
+	if button.selected != nil {
+		button.selected()
+	}
+	if d.done != nil {
+		d.done(index, button.button.GetLabel())
+	}
+}
+
+// blurButton is called when the user leaves the button with the given index
+// with the given key.
+func (d *Dialog) blurButton(index int, key tcell.Key) {
+	switch key {
+	case tcell.KeyTab:
+		index++
+	case tcell.KeyBacktab:
+		index--
+	case tcell.KeyEscape:
+		if d.done != nil {
+			d.done(-1, "")
+		}
+		return
+	}
+	if index >= len(d.buttons) || index < 0 && d.content != nil {
+		index = -1 // The content.
+	} else if index < 0 {
+		index = len(d.buttons) - 1
+	}
+	d.focusButton = index
+	if d.setFocus != nil {
+		d.setFocus(d)
+	}
+}
+
+// GetChildren returns the dialog's content followed by its buttons.
+func (d *Dialog) GetChildren() []Primitive {
+	var children []Primitive
+	if d.content != nil {
+		children = append(children, d.content)
+	}
+	for _, button := range d.buttons {
+		children = append(children, button.button)
+	}
+	return children
+}
+
+// applyTheme is called when the application's theme changes.
+func (d *Dialog) applyTheme(from, to *Theme) {
+	d.Box.applyTheme(from, to)
+	themeColor(&d.backgroundColor, from.ContrastBackgroundColor, to.ContrastBackgroundColor)
+}
+
+// Draw draws this primitive onto the screen.
+func (d *Dialog) Draw(screen tcell.Screen) {
+	// Calculate the width of the buttons.
+	buttonsWidth := 0
+	for _, button := range d.buttons {
+		buttonWidth, _ := button.button.GetPreferredSize()
+		buttonsWidth += buttonWidth + 2
+	}
+	buttonsWidth -= 2
+
+	// Determine the dialog's size and position.
+	screenWidth, screenHeight := screen.Size()
+	size := func(size, screenSize int) int {
+		if size <= 0 {
+			size = screenSize / (2 - size)
+		}
+		return size
+	}
+	width, height := size(d.width, screenWidth), size(d.height, screenHeight)
+	if width < buttonsWidth+4 {
+		width = buttonsWidth + 4
+	}
+	if width > screenWidth {
+		width = screenWidth
+	}
+	if height > screenHeight {
+		height = screenHeight
+	}
+	d.SetRect((screenWidth-width)/2, (screenHeight-height)/2, width, height)
+	d.Box.Draw(screen)
+
+	// Draw the buttons.
+	x, y, width, height := d.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+	contentHeight := height
+	if len(d.buttons) > 0 {
+		contentHeight -= 2
+		buttonX := x
+		if d.buttonsAlign == AlignRight {
+			buttonX = x + width - buttonsWidth
+		} else if d.buttonsAlign == AlignCenter {
+			buttonX = x + (width-buttonsWidth)/2
+		}
+		for _, button := range d.buttons {
+			buttonWidth, _ := button.button.GetPreferredSize()
+			button.button.SetRect(buttonX, y+height-1, buttonWidth, 1)
+			button.button.Draw(screen)
+			buttonX += buttonWidth + 2
+		}
+	}
+
+	// Draw the content.
+	if d.content != nil && contentHeight > 0 {
+		d.content.SetRect(x, y, width, contentHeight)
+		d.content.Draw(screen)
+	}
+}
+
+// Focus is called when this primitive receives focus.
+func (d *Dialog) Focus(delegate func(p Primitive)) {
+	d.setFocus = delegate
+	index := d.focusButton
+	d.focusButton = -2
+	if index == -2 || index >= len(d.buttons) {
+		index = d.defaultButton
+	}
+	if index < 0 && d.content == nil {
+		index = 0
+	}
+	if index >= 0 && index < len(d.buttons) {
+		delegate(d.buttons[index].button)
+		return
+	}
+	if d.content != nil {
+		delegate(d.content)
+		return
+	}
+	d.Box.Focus(delegate)
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (d *Dialog) HasFocus() bool {
+	if d.hasFocus {
+		return true
+	}
+	if d.content != nil && d.content.GetFocusable().HasFocus() {
+		return true
+	}
+	for _, button := range d.buttons {
+		if button.button.GetFocusable().HasFocus() {
+			return true
+		}
+	}
+	return false
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (d *Dialog) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return d.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if !d.InRect(event.Position()) {
+			return false, nil
+		}
+
+		// Clicks on the buttons press them.
+		for index, button := range d.buttons {
+			if button.button.InRect(event.Position()) {
+				if action == MouseLeftClick {
+					setFocus(button.button)
+					d.press(index)
+				}
+				return true, nil
+			}
+		}
+
+		// Other events go to the content.
+		if d.content != nil {
+			if handler := mouseHandler(d.content); handler != nil {
+				if consumed, capture = handler(action, event, setFocus); consumed {
+					return
+				}
+			}
+		}
+		return true, nil // Don't let clicks on the dialog fall through.
+	})
+}
@@ -0,0 +1,600 @@
+package tview
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell"
+)
+
+// Kinds of lines in a DiffView.
+const (
+	diffEqual = iota
+	diffRemoved
+	diffAdded
+	diffHunk
+)
+
+// hunkPattern matches the header of a hunk in a unified diff.
+var hunkPattern = regexp.MustCompile(`^@@ -(\d+)(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// diffLine is one line of a diff.
+type diffLine struct {
+	kind             int    // One of the diff line kinds.
+	oldLine, newLine int    // The line numbers in the old and new text, starting at 1, 0 if the line does not exist there.
+	text             string // The line's text, with tabs expanded.
+}
+
+// diffRow is one row of a DiffView. In unified mode, only "left" is set. In
+// split mode, "left" is the old and "right" the new line, either may be nil.
+type diffRow struct {
+	left, right *diffLine
+}
+
+// DiffView is a primitive which shows the differences between two texts. The
+// differences are either computed from the two texts (see SetTexts()) or taken
+// from a unified diff (see SetUnifiedDiff()).
+//
+// The diff can be shown in unified mode, where removed and added lines are
+// shown one after the other, or in split mode, where the old text is shown on
+// the left and the new text on the right, scrolling together. In split mode,
+// removed lines which were replaced by added lines are shown side by side as
+// changed lines.
+//
+// The following keys are available when the diff view has focus:
+//
+//   - Up arrow, k: Scroll up by one line.
+//   - Down arrow, j: Scroll down by one line.
+//   - Left arrow, h: Scroll left.
+//   - Right arrow, l: Scroll right.
+//   - Page Up, Ctrl-B: Scroll up by one page.
+//   - Page Down, Ctrl-F: Scroll down by one page.
+//   - Home, g: Scroll to the top.
+//   - End, G: Scroll to the bottom.
+//   - n, ]: Scroll to the next hunk (a block of changes).
+//   - N, p, [: Scroll to the previous hunk.
+//   - s: Switch between unified and split mode.
+//   - Escape, Tab, Backtab: Finish (see SetDoneFunc()).
+type DiffView struct {
+	*Box
+
+	// The lines of the diff.
+	lines []*diffLine
+
+	// The rows for the current mode, nil if they need to be recalculated.
+	rows []diffRow
+
+	// Whether or not the diff is shown in split mode.
+	split bool
+
+	// Whether or not line numbers are shown.
+	lineNumbers bool
+
+	// The index of the first visible row and the number of columns scrolled to
+	// the right.
+	rowOffset, columnOffset int
+
+	// The number of rows visible the last time the diff view was drawn.
+	pageSize int
+
+	// The colors of unchanged, removed, added, and changed lines.
+	textColor, removedColor, addedColor, changedColor tcell.Color
+
+	// The colors of hunk headers and line numbers.
+	hunkColor, lineNumberColor tcell.Color
+
+	// An optional function which is called when the user leaves the diff view.
+	done func(tcell.Key)
+}
+
+// NewDiffView returns a new, empty diff view in unified mode.
+func NewDiffView() *DiffView {
+	styles := Styles.forWidget("DiffView")
+	return &DiffView{
+		Box:             NewBox(),
+		lineNumbers:     true,
+		textColor:       styles.PrimaryTextColor,
+		removedColor:    tcell.ColorRed,
+		addedColor:      tcell.ColorGreen,
+		changedColor:    tcell.ColorYellow,
+		hunkColor:       styles.TertiaryTextColor,
+		lineNumberColor: styles.SecondaryTextColor,
+	}
+}
+
+// SetTexts computes the line-based differences between the old and the new
+// text and shows them. All lines of both texts are shown.
+func (d *DiffView) SetTexts(oldText, newText string) *DiffView {
+	oldLines, newLines := splitDiffText(oldText), splitDiffText(newText)
+	d.lines = nil
+	var oldLine, newLine int
+	for _, kind := range diffOperations(oldLines, newLines) {
+		line := &diffLine{kind: kind}
+		switch kind {
+		case diffEqual:
+			line.text = oldLines[oldLine]
+			oldLine++
+			newLine++
+			line.oldLine, line.newLine = oldLine, newLine
+		case diffRemoved:
+			line.text = oldLines[oldLine]
+			oldLine++
+			line.oldLine = oldLine
+		case diffAdded:
+			line.text = newLines[newLine]
+			newLine++
+			line.newLine = newLine
+		}
+		d.lines = append(d.lines, line)
+	}
+	d.rows = nil
+	d.rowOffset, d.columnOffset = 0, 0
+	return d
+}
+
+// SetUnifiedDiff shows the given diff in the unified format, as produced by
+// "diff -u" or "git diff". Lines outside of hunks (e.g. file headers) are
+// ignored.
+func (d *DiffView) SetUnifiedDiff(diff string) *DiffView {
+	d.lines = nil
+	var inHunk bool
+	var oldLine, newLine int
+	for _, text := range splitDiffText(diff) {
+		if match := hunkPattern.FindStringSubmatch(text); match != nil {
+			oldLine, _ = strconv.Atoi(match[1])
+			newLine, _ = strconv.Atoi(match[2])
+			d.lines = append(d.lines, &diffLine{kind: diffHunk, text: text})
+			inHunk = true
+			continue
+		}
+		if !inHunk || text == "" {
+			continue
+		}
+		line := &diffLine{text: text[1:]}
+		switch text[0] {
+		case ' ':
+			line.kind, line.oldLine, line.newLine = diffEqual, oldLine, newLine
+			oldLine++
+			newLine++
+		case '-':
+			line.kind, line.oldLine = diffRemoved, oldLine
+			oldLine++
+		case '+':
+			line.kind, line.newLine = diffAdded, newLine
+			newLine++
+		case '\\':
+			continue // "No newline at end of file".
+		default:
+			inHunk = false
+			continue
+		}
+		d.lines = append(d.lines, line)
+	}
+	d.rows = nil
+	d.rowOffset, d.columnOffset = 0, 0
+	return d
+}
+
+// SetSplit sets whether the diff is shown in split mode (true) or in unified
+// mode (false).
+func (d *DiffView) SetSplit(split bool) *DiffView {
+	if split != d.split {
+		d.split = split
+		d.rows = nil
+	}
+	return d
+}
+
+// IsSplit returns whether or not the diff is shown in split mode.
+func (d *DiffView) IsSplit() bool {
+	return d.split
+}
+
+// SetLineNumbers sets whether or not line numbers are shown.
+func (d *DiffView) SetLineNumbers(show bool) *DiffView {
+	d.lineNumbers = show
+	return d
+}
+
+// SetTextColor sets the color of unchanged lines.
+func (d *DiffView) SetTextColor(color tcell.Color) *DiffView {
+	d.textColor = color
+	return d
+}
+
+// SetRemovedColor sets the color of removed lines.
+func (d *DiffView) SetRemovedColor(color tcell.Color) *DiffView {
+	d.removedColor = color
+	return d
+}
+
+// SetAddedColor sets the color of added lines.
+func (d *DiffView) SetAddedColor(color tcell.Color) *DiffView {
+	d.addedColor = color
+	return d
+}
+
+// SetChangedColor sets the color of changed lines in split mode.
+func (d *DiffView) SetChangedColor(color tcell.Color) *DiffView {
+	d.changedColor = color
+	return d
+}
+
+// SetHunkColor sets the color of hunk headers.
+func (d *DiffView) SetHunkColor(color tcell.Color) *DiffView {
+	d.hunkColor = color
+	return d
+}
+
+// SetLineNumberColor sets the color of line numbers.
+func (d *DiffView) SetLineNumberColor(color tcell.Color) *DiffView {
+	d.lineNumberColor = color
+	return d
+}
+
+// SetDoneFunc sets a handler which is called when the user leaves the diff
+// view. The callback function is provided with the key that was pressed, which
+// is one of the following:
+//
+//   - KeyEscape: Leaving the diff view with no specific direction.
+//   - KeyTab: Move to the next field.
+//   - KeyBacktab: Move to the previous field.
+func (d *DiffView) SetDoneFunc(handler func(key tcell.Key)) *DiffView {
+	d.done = handler
+	return d
+}
+
+// NextHunk scrolls to the next block of changes.
+func (d *DiffView) NextHunk() *DiffView {
+	for _, row := range d.hunks() {
+		if row > d.rowOffset {
+			d.rowOffset = row
+			break
+		}
+	}
+	return d
+}
+
+// PreviousHunk scrolls to the previous block of changes.
+func (d *DiffView) PreviousHunk() *DiffView {
+	hunks := d.hunks()
+	for index := len(hunks) - 1; index >= 0; index-- {
+		if hunks[index] < d.rowOffset {
+			d.rowOffset = hunks[index]
+			break
+		}
+	}
+	return d
+}
+
+// splitDiffText splits a text into lines and expands tabs.
+func splitDiffText(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	tab := strings.Repeat(" ", TabSize)
+	for index, line := range lines {
+		lines[index] = strings.Replace(strings.TrimSuffix(line, "\r"), "\t", tab, -1)
+	}
+	return lines
+}
+
+// diffOperations returns the shortest sequence of operations (diffEqual,
+// diffRemoved, diffAdded) which turns the lines "a" into the lines "b", using
+// Myers' algorithm.
+func diffOperations(a, b []string) []int {
+	n, m := len(a), len(b)
+	max := n + m
+	v := make([]int, 2*max+2)
+	var trace [][]int
+search:
+	for distance := 0; distance <= max; distance++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -distance; k <= distance; k += 2 {
+			var x int
+			if k == -distance || k != distance && v[max+k-1] < v[max+k+1] {
+				x = v[max+k+1] // Move down.
+			} else {
+				x = v[max+k-1] + 1 // Move right.
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[max+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	// Backtrack to find the operations, last one first.
+	var operations []int
+	x, y := n, m
+	for distance := len(trace) - 1; distance >= 0; distance-- {
+		v := trace[distance]
+		k := x - y
+		previousK := k - 1
+		if k == -distance || k != distance && v[max+k-1] < v[max+k+1] {
+			previousK = k + 1
+		}
+		previousX := v[max+previousK]
+		previousY := previousX - previousK
+		for x > previousX && y > previousY {
+			operations = append(operations, diffEqual)
+			x--
+			y--
+		}
+		if distance > 0 {
+			if x == previousX {
+				operations = append(operations, diffAdded)
+			} else {
+				operations = append(operations, diffRemoved)
+			}
+		}
+		x, y = previousX, previousY
+	}
+	for left, right := 0, len(operations)-1; left < right; left, right = left+1, right-1 {
+		operations[left], operations[right] = operations[right], operations[left]
+	}
+	return operations
+}
+
+// getRows returns the rows for the current mode.
+func (d *DiffView) getRows() []diffRow {
+	if d.rows != nil {
+		return d.rows
+	}
+	d.rows = make([]diffRow, 0, len(d.lines))
+	if !d.split {
+		for _, line := range d.lines {
+			d.rows = append(d.rows, diffRow{left: line})
+		}
+		return d.rows
+	}
+
+	// In split mode, pair removed lines with the added lines following them.
+	for index := 0; index < len(d.lines); {
+		line := d.lines[index]
+		switch line.kind {
+		case diffEqual:
+			d.rows = append(d.rows, diffRow{left: line, right: line})
+			index++
+		case diffHunk:
+			d.rows = append(d.rows, diffRow{left: line})
+			index++
+		default:
+			var removed, added []*diffLine
+			for ; index < len(d.lines) && d.lines[index].kind == diffRemoved; index++ {
+				removed = append(removed, d.lines[index])
+			}
+			for ; index < len(d.lines) && d.lines[index].kind == diffAdded; index++ {
+				added = append(added, d.lines[index])
+			}
+			for row := 0; row < len(removed) || row < len(added); row++ {
+				var r diffRow
+				if row < len(removed) {
+					r.left = removed[row]
+				}
+				if row < len(added) {
+					r.right = added[row]
+				}
+				d.rows = append(d.rows, r)
+			}
+		}
+	}
+	return d.rows
+}
+
+// hunks returns the indices of the rows where a block of changes or a hunk
+// header starts.
+func (d *DiffView) hunks() []int {
+	var (
+		hunks   []int
+		changed bool
+	)
+	for index, row := range d.getRows() {
+		isChange := row.left == nil || row.left.kind == diffRemoved || row.left.kind == diffAdded
+		if row.left != nil && row.left.kind == diffHunk || isChange && !changed {
+			hunks = append(hunks, index)
+		}
+		changed = isChange
+	}
+	return hunks
+}
+
+// applyTheme is called when the application's theme changes.
+func (d *DiffView) applyTheme(from, to *Theme) {
+	d.Box.applyTheme(from, to)
+	themeColor(&d.textColor, from.PrimaryTextColor, to.PrimaryTextColor)
+	themeColor(&d.hunkColor, from.TertiaryTextColor, to.TertiaryTextColor)
+	themeColor(&d.lineNumberColor, from.SecondaryTextColor, to.SecondaryTextColor)
+}
+
+// Draw draws this primitive onto the screen.
+func (d *DiffView) Draw(screen tcell.Screen) {
+	defer d.applyStyle(&d.textColor)()
+	d.Box.Draw(screen)
+	x, y, width, height := d.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+	d.pageSize = height
+
+	// Adjust the scroll position.
+	rows := d.getRows()
+	if d.rowOffset > len(rows)-height {
+		d.rowOffset = len(rows) - height
+	}
+	if d.rowOffset < 0 {
+		d.rowOffset = 0
+	}
+	if d.columnOffset < 0 {
+		d.columnOffset = 0
+	}
+
+	// Determine the width of line numbers.
+	var numberWidth int
+	if d.lineNumbers {
+		var maxLine int
+		for _, line := range d.lines {
+			if line.oldLine > maxLine {
+				maxLine = line.oldLine
+			}
+			if line.newLine > maxLine {
+				maxLine = line.newLine
+			}
+		}
+		numberWidth = len(strconv.Itoa(maxLine))
+	}
+	number := func(line int) string {
+		if !d.lineNumbers {
+			return ""
+		}
+		if line == 0 {
+			return strings.Repeat(" ", numberWidth) + " "
+		}
+		return fmt.Sprintf("%*d ", numberWidth, line)
+	}
+
+	// drawLine draws one line's text at the given position, scrolled
+	// horizontally.
+	drawLine := func(text string, x, y, width int, color tcell.Color) {
+		style := tcell.StyleDefault.Foreground(color).Background(d.backgroundColor)
+		var column int
+		for _, r := range text {
+			runeWidth := RuneWidth(r)
+			if runeWidth == 0 {
+				continue
+			}
+			if column < d.columnOffset {
+				column += runeWidth
+				continue
+			}
+			if column-d.columnOffset+runeWidth > width {
+				break
+			}
+			screen.SetContent(x+column-d.columnOffset, y, r, nil, style)
+			column += runeWidth
+		}
+	}
+
+	// Draw the rows.
+	kindColors := []tcell.Color{d.textColor, d.removedColor, d.addedColor, d.hunkColor}
+	for row := 0; row < height && d.rowOffset+row < len(rows); row++ {
+		r := rows[d.rowOffset+row]
+		if !d.split || r.left != nil && r.left.kind == diffHunk {
+			// Unified mode or hunk header.
+			line := r.left
+			if line.kind == diffHunk {
+				Print(screen, Escape(line.text), x, y+row, width, AlignLeft, d.hunkColor)
+				continue
+			}
+			prefix := number(line.oldLine) + number(line.newLine) + string(" -+"[line.kind]) + " "
+			Print(screen, prefix, x, y+row, width, AlignLeft, d.lineNumberColor)
+			prefixWidth := len(prefix)
+			if prefixWidth < width {
+				drawLine(line.text, x+prefixWidth, y+row, width-prefixWidth, kindColors[line.kind])
+			}
+			continue
+		}
+
+		// Split mode.
+		leftWidth := (width - 1) / 2
+		rightX, rightWidth := x+leftWidth+1, width-leftWidth-1
+		screen.SetContent(x+leftWidth, y+row, GraphicsVertBar, nil, tcell.StyleDefault.Foreground(d.borderColor).Background(d.backgroundColor))
+		changed := r.left != nil && r.right != nil && r.left != r.right
+		drawSide := func(line *diffLine, lineNumber, sideX, sideWidth int) {
+			prefix := number(lineNumber)
+			Print(screen, prefix, sideX, y+row, sideWidth, AlignLeft, d.lineNumberColor)
+			color := kindColors[line.kind]
+			if changed {
+				color = d.changedColor
+			}
+			if len(prefix) < sideWidth {
+				drawLine(line.text, sideX+len(prefix), y+row, sideWidth-len(prefix), color)
+			}
+		}
+		if r.left != nil {
+			drawSide(r.left, r.left.oldLine, x, leftWidth)
+		}
+		if r.right != nil {
+			drawSide(r.right, r.right.newLine, rightX, rightWidth)
+		}
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (d *DiffView) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return d.wrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		switch key := event.Key(); key {
+		case tcell.KeyUp:
+			d.rowOffset--
+		case tcell.KeyDown:
+			d.rowOffset++
+		case tcell.KeyLeft:
+			d.columnOffset--
+		case tcell.KeyRight:
+			d.columnOffset++
+		case tcell.KeyPgUp, tcell.KeyCtrlB:
+			d.rowOffset -= d.pageSize
+		case tcell.KeyPgDn, tcell.KeyCtrlF:
+			d.rowOffset += d.pageSize
+		case tcell.KeyHome:
+			d.rowOffset = 0
+		case tcell.KeyEnd:
+			d.rowOffset = len(d.getRows())
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'k':
+				d.rowOffset--
+			case 'j':
+				d.rowOffset++
+			case 'h':
+				d.columnOffset--
+			case 'l':
+				d.columnOffset++
+			case 'g':
+				d.rowOffset = 0
+			case 'G':
+				d.rowOffset = len(d.getRows())
+			case 'n', ']':
+				d.NextHunk()
+			case 'N', 'p', '[':
+				d.PreviousHunk()
+			case 's':
+				d.SetSplit(!d.split)
+			}
+		case tcell.KeyEscape, tcell.KeyTab, tcell.KeyBacktab:
+			if d.done != nil {
+				d.done(key)
+			}
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (d *DiffView) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return d.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if !d.InRect(event.Position()) {
+			return false, nil
+		}
+		switch action {
+		case MouseLeftClick:
+			setFocus(d)
+		case MouseScrollUp:
+			d.rowOffset -= 3
+		case MouseScrollDown:
+			d.rowOffset += 3
+		case MouseScrollLeft:
+			d.columnOffset -= 3
+		case MouseScrollRight:
+			d.columnOffset += 3
+		}
+		return true, nil
+	})
+}
@@ -13,14 +13,49 @@ The package implements the following widgets:
     also be highlighted.
   - List: A navigable text list with optional keyboard shortcuts.
   - InputField: One-line input fields to enter text.
+  - TextArea: A multi-line text editor.
   - DropDown: Drop-down selection fields.
   - Checkbox: Selectable checkbox for boolean values.
+  - DateField: A date entry field with a calendar popup.
   - Button: Buttons which get activated when the user selects them.
-  - Form: Forms composed of input fields, drop down selections, checkboxes, and
-    buttons.
+  - Form: Forms composed of input fields, drop down selections, checkboxes,
+    date fields, and buttons.
   - Modal: A centered window with a text message and one or more buttons.
   - Flex: A Flexbox based layout manager.
   - Pages: A page based layout manager.
+  - Tabs: A tabbed container showing one of several primitives at a time.
+  - MenuBar: A row of pull-down menus with nested submenus.
+  - ContextMenu: A popup menu shown on top of another primitive.
+  - StatusBar: A status line with text segments, key hints, and transient
+    messages.
+  - Calendar: A month view from which dates can be selected.
+  - FileBrowser: A file system navigator for selecting files and directories.
+  - ColorPicker: A palette and RGB sliders for choosing colors.
+  - Slider: A horizontal or vertical slider for numeric values.
+  - Image: Displays an image using Unicode half blocks.
+  - Canvas: A drawing surface for custom visualizations.
+  - SplitPane: Two primitives separated by a movable divider.
+  - Breadcrumb: A navigable path of segments.
+  - Accordion: Stacked sections which can be expanded and collapsed.
+  - HelpOverlay: A popup cheat sheet of key bindings.
+  - Terminal: Runs a program in a pseudo terminal and shows its output.
+  - LogView: A bounded view of log entries with levels and search.
+  - Pager: A text viewer with the key bindings of "less".
+  - Wizard: A container which guides the user through a sequence of steps.
+  - Dialog: A centered window with arbitrary content and a row of buttons.
+  - Outline: A table of contents which scrolls a linked TextView or Pager.
+  - DiffView: Shows the differences between two texts, unified or side by side.
+  - StructuredDataView: An expandable tree view of JSON or YAML data.
+  - MessageView: A chat-style list of messages shown as bubbles.
+  - HexView: A hex dump of binary data with search and optional editing.
+  - Responsive: A container which switches between layouts depending on its size.
+  - WindowManager: A container for floating windows which can be moved and resized.
+  - ScrollView: A container which scrolls a primitive larger than the available space.
+  - Center: A container which places a primitive at a chosen position, e.g. centered.
+  - DockLayout: A container with a header, a status bar, side panels, and a center.
+  - AspectRatio: A container which keeps a primitive at a fixed width-to-height ratio.
+  - FlowLayout: A container which arranges items in rows, wrapping them like text.
+  - FocusGroup: A container which keeps Tab navigation within its primitives.
 
 The package also provides Application which is used to poll the event queue and
 draw widgets on screen.
@@ -77,6 +112,37 @@ applies to almost everything from box titles, list text, form item labels, to
 table cells. In a TextView, this functionality has to be switched on explicitly.
 See the TextView documentation for more information.
 
+A color tag may also specify a background color, separated from the text color
+by a colon. The background color remains in effect until another tag specifies
+a different one. Example:
+
+  [#ffffff:#005f87]Selected[#ffffff] text
+
+A third field sets text attributes, replacing the current ones: "b" (bold),
+"l" (blink), "r" (reverse), "u" (underline), and "d" (dim). Italics and
+strikethrough are not supported by tcell. Fields may be left empty to keep
+their current value. Examples:
+
+  This is [::b]important[::u] and underlined
+  [yellow:red:b]Alarm[white:black:l]!
+
+A field containing a hyphen resets the text color, the background color, or the
+attributes to the default of the primitive which displays the text. This works
+the same way in text views, table cells, list items, box titles, and anywhere
+else tags are accepted. Examples:
+
+  [red:blue]Alert[-:-]    (back to the primitive's colors)
+  [::b]Bold[::-]          (back to the primitive's attributes)
+  [yellow::u]Link[-:-:-]  (everything reset)
+
+The functions Gradient() and Rainbow() generate color tags which make the color
+of a string change gradually from character to character. Text containing ANSI
+escape sequences (e.g. the output of command line tools) can be converted to
+color tags with TranslateANSI() or by writing it through an ANSIWriter().
+
+Hexadecimal colors are sent to the terminal as 24-bit colors if it supports
+them. Otherwise, tcell substitutes the closest color the terminal can display.
+
 In the rare event that you want to display a string such as "[red]" or
 "[#00ff1a]" without applying its effect, you need to put an opening square
 bracket before the closing square bracket. Examples:
@@ -85,15 +151,33 @@ bracket before the closing square bracket. Examples:
   ["123"[]    will be output as ["123"]
   [#6aff00[[] will be output as [#6aff00[]
 
+The Escape() function does this for any text, which is useful for strings
+supplied by users. TaggedStringWidth() returns the width of a tagged string on
+screen. Custom primitives can support color tags with ParseTags(), which splits
+a string into styled spans, or remove them with StripTags().
+
 Styles
 
 When primitives are instantiated, they are initialized with colors taken from
 the global Styles variable. You may change this variable to adapt the look and
 feel of the primitives to your preferred style.
 
+Two themes are predefined: DarkTheme, the default, and LightTheme. To use the
+one which matches the terminal's background (see HasLightBackground()), set
+Styles before creating any primitives:
+
+  tview.Styles = tview.ThemeForBackground()
+
+Themes can also be loaded from JSON or YAML files with LoadTheme(). To switch
+the theme of primitives which already exist, use Application.SetTheme().
+
 Unicode Support
 
-This package supports unicode characters including wide characters.
+This package supports unicode characters including wide characters. Because
+terminals disagree on the width of some characters (East Asian characters of
+ambiguous width and symbols which may be shown as emoji), their width can be
+adjusted to the terminal with SetWidthPolicy(). Custom primitives should use
+RuneWidth() to calculate the width of characters.
 
 Type Hierarchy
 
@@ -106,6 +190,11 @@ interface which is used to override functions in subclassing types.
 The tview package is based on https://github.com/gdamore/tcell. It uses types
 and constants from that package (e.g. colors and keyboard values).
 
-This package does not process mouse input (yet).
+Mouse Input
+
+Mouse input is disabled by default. Call Application.EnableMouse() to have
+mouse events translated into mouse actions (clicks, double clicks, scroll
+events etc., see MouseAction) which are passed on to the primitives' mouse
+handlers.
 */
 package tview
@@ -0,0 +1,189 @@
+package tview
+
+import (
+	"github.com/gdamore/tcell"
+)
+
+// Slots of a DockLayout.
+const (
+	DockTop = iota
+	DockBottom
+	DockLeft
+	DockRight
+	DockCenter
+)
+
+// dockItem is a primitive in one of the slots of a DockLayout.
+type dockItem struct {
+	Item Primitive // The primitive. May be nil for an empty slot.
+	Size int       // The height of the top or bottom item or the width of the left or right item.
+}
+
+// DockLayout is a container with five slots, arranged in the typical skeleton
+// of an application: a header at the top, a status bar at the bottom, side
+// panels on the left and on the right, and the main content in the center.
+// The top and bottom items span the entire width, the left and right items
+// are placed between them. The edge items take the size they were given and
+// the center item receives the remaining space. Empty slots take no space.
+//
+// When the dock layout receives focus, it is passed on to the center item.
+type DockLayout struct {
+	*Box
+
+	// The items, indexed by slot.
+	items [5]dockItem
+}
+
+// NewDockLayout returns a new dock layout with all slots empty.
+func NewDockLayout() *DockLayout {
+	d := &DockLayout{
+		Box: NewBox(),
+	}
+	d.focus = d
+	return d
+}
+
+// SetTop sets the item at the top and its height.
+func (d *DockLayout) SetTop(item Primitive, height int) *DockLayout {
+	d.items[DockTop] = dockItem{Item: item, Size: height}
+	return d
+}
+
+// SetBottom sets the item at the bottom and its height.
+func (d *DockLayout) SetBottom(item Primitive, height int) *DockLayout {
+	d.items[DockBottom] = dockItem{Item: item, Size: height}
+	return d
+}
+
+// SetLeft sets the item on the left and its width.
+func (d *DockLayout) SetLeft(item Primitive, width int) *DockLayout {
+	d.items[DockLeft] = dockItem{Item: item, Size: width}
+	return d
+}
+
+// SetRight sets the item on the right and its width.
+func (d *DockLayout) SetRight(item Primitive, width int) *DockLayout {
+	d.items[DockRight] = dockItem{Item: item, Size: width}
+	return d
+}
+
+// SetCenter sets the item in the center.
+func (d *DockLayout) SetCenter(item Primitive) *DockLayout {
+	d.items[DockCenter] = dockItem{Item: item}
+	return d
+}
+
+// GetItem returns the item in the given slot (DockTop, DockBottom, DockLeft,
+// DockRight, or DockCenter), or nil if the slot is empty.
+func (d *DockLayout) GetItem(slot int) Primitive {
+	if slot < DockTop || slot > DockCenter {
+		return nil
+	}
+	return d.items[slot].Item
+}
+
+// GetChildren returns the items of all non-empty slots.
+func (d *DockLayout) GetChildren() []Primitive {
+	var children []Primitive
+	for _, item := range d.items {
+		if item.Item != nil {
+			children = append(children, item.Item)
+		}
+	}
+	return children
+}
+
+// Draw draws this primitive onto the screen.
+func (d *DockLayout) Draw(screen tcell.Screen) {
+	d.Box.Draw(screen)
+	x, y, width, height := d.GetInnerRect()
+
+	// size returns the size of an edge item, limited to what is available.
+	size := func(slot, available int) int {
+		item := d.items[slot]
+		if item.Item == nil || item.Size <= 0 || available <= 0 {
+			return 0
+		}
+		if item.Size > available {
+			return available
+		}
+		return item.Size
+	}
+
+	// Calculate positions.
+	top := size(DockTop, height)
+	bottom := size(DockBottom, height-top)
+	middleY, middleHeight := y+top, height-top-bottom
+	left := size(DockLeft, width)
+	right := size(DockRight, width-left)
+	rects := [5][4]int{
+		DockTop:    {x, y, width, top},
+		DockBottom: {x, y + height - bottom, width, bottom},
+		DockLeft:   {x, middleY, left, middleHeight},
+		DockRight:  {x + width - right, middleY, right, middleHeight},
+		DockCenter: {x + left, middleY, width - left - right, middleHeight},
+	}
+
+	// Draw the items, the focused one last.
+	var focused Primitive
+	for slot, item := range d.items {
+		if item.Item == nil {
+			continue
+		}
+		rect := rects[slot]
+		item.Item.SetRect(rect[0], rect[1], rect[2], rect[3])
+		if rect[2] <= 0 || rect[3] <= 0 {
+			continue
+		}
+		if item.Item.GetFocusable().HasFocus() {
+			focused = item.Item
+			continue
+		}
+		item.Item.Draw(screen)
+	}
+	if focused != nil {
+		focused.Draw(screen)
+	}
+}
+
+// Focus is called when this primitive receives focus.
+func (d *DockLayout) Focus(delegate func(p Primitive)) {
+	if center := d.items[DockCenter].Item; center != nil {
+		delegate(center)
+		return
+	}
+	d.hasFocus = true
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (d *DockLayout) HasFocus() bool {
+	for _, item := range d.items {
+		if item.Item != nil && item.Item.GetFocusable().HasFocus() {
+			return true
+		}
+	}
+	return d.hasFocus
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (d *DockLayout) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return d.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if !d.InRect(event.Position()) {
+			return false, nil
+		}
+
+		// Pass mouse events along to the first item that takes it.
+		for _, item := range d.items {
+			if item.Item == nil {
+				continue
+			}
+			if handler := mouseHandler(item.Item); handler != nil {
+				if consumed, capture = handler(action, event, setFocus); consumed {
+					return
+				}
+			}
+		}
+
+		return
+	})
+}
@@ -1,6 +1,11 @@
 package tview
 
 import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/gdamore/tcell"
 )
 
@@ -8,11 +13,31 @@ import (
 type dropDownOption struct {
 	Text     string // The text to be displayed in the drop-down.
 	Selected func() // The (optional) callback for when this option was selected.
+	Checked  bool   // Whether this option is selected in multi-select mode.
+
+	Disabled   bool           // If true, the option cannot be selected.
+	Separator  bool           // If true, the option is a separator line.
+	Color      tcell.Color    // The option's text color, tcell.ColorDefault for the drop-down's color.
+	Attributes tcell.AttrMask // The option's text attributes.
 }
 
+// dropDownSpinner contains the frames of the animation shown while options
+// are being loaded (see DropDown.SetOptionsFunc()).
+var dropDownSpinner = []rune("⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏")
+
 // DropDown is a one-line box (three lines if there is a title) where the
 // user can enter text.
 //
+// While the options are shown, typing narrows them down to those containing
+// the typed text.
+//
+// In multi-select mode (see SetMultiSelect()), any number of options can be
+// selected by toggling them with the space bar.
+//
+// Options may be styled individually, disabled, or separated into groups with
+// separator lines. For full control over their appearance, they can be drawn
+// by a custom function (see SetOptionDrawFunc()).
+//
 // See https://github.com/rivo/tview/wiki/DropDown for an example.
 type DropDown struct {
 	*Box
@@ -24,12 +49,37 @@ type DropDown struct {
 	// currently selected.
 	currentOption int
 
+	// Whether multiple options can be selected.
+	multiple bool
+
+	// An optional function which is called when an option is toggled in
+	// multi-select mode.
+	checked func(index int, checked bool)
+
+	// An optional function which draws the options in the list.
+	drawOption func(screen tcell.Screen, x, y, width, index int, text string, selected bool)
+
+	// Options are loaded asynchronously by SetOptionsFunc(). "loadID"
+	// identifies the latest load, and "loaded" holds its result until it is
+	// applied in the primitive's own goroutine.
+	loadMutex    sync.Mutex
+	loadID       int
+	loading      bool
+	loaded       []string
+	loadPending  bool
+	loadSelected func(text string, index int)
+
 	// Set to true if the options are visible and selectable.
 	open bool
 
 	// The list element for the options.
 	list *List
 
+	// The text typed by the user while the options are shown, used to filter
+	// them, and the indices of the options which are shown in the list.
+	filter   string
+	filtered []int
+
 	// The text to be displayed before the input area.
 	label string
 
@@ -54,22 +104,24 @@ type DropDown struct {
 
 // NewDropDown returns a new drop-down.
 func NewDropDown() *DropDown {
+	styles := Styles.forWidget("DropDown")
 	list := NewList().ShowSecondaryText(false)
-	list.SetMainTextColor(Styles.PrimitiveBackgroundColor).
-		SetSelectedTextColor(Styles.PrimitiveBackgroundColor).
-		SetSelectedBackgroundColor(Styles.PrimaryTextColor).
-		SetBackgroundColor(Styles.MoreContrastBackgroundColor)
+	list.SetMainTextColor(styles.PrimitiveBackgroundColor).
+		SetSelectedTextColor(styles.PrimitiveBackgroundColor).
+		SetSelectedBackgroundColor(styles.PrimaryTextColor).
+		SetBackgroundColor(styles.MoreContrastBackgroundColor)
 
 	d := &DropDown{
 		Box:                  NewBox(),
 		currentOption:        -1,
 		list:                 list,
-		labelColor:           Styles.SecondaryTextColor,
-		fieldBackgroundColor: Styles.ContrastBackgroundColor,
-		fieldTextColor:       Styles.PrimaryTextColor,
+		labelColor:           styles.SecondaryTextColor,
+		fieldBackgroundColor: styles.ContrastBackgroundColor,
+		fieldTextColor:       styles.PrimaryTextColor,
 	}
 
 	d.focus = d
+	list.SetInputCapture(d.filterInput)
 
 	return d
 }
@@ -92,6 +144,54 @@ func (d *DropDown) GetCurrentOption() (int, string) {
 	return d.currentOption, text
 }
 
+// SetMultiSelect sets whether or not multiple options can be selected. In
+// multi-select mode, the space bar toggles the option under the cursor, which
+// is then marked with a check mark, and Enter closes the list of options. The
+// field shows the selected options separated by commas or, if they don't fit,
+// their number. Spaces cannot be used for filtering in this mode.
+func (d *DropDown) SetMultiSelect(multiple bool) *DropDown {
+	d.multiple = multiple
+	return d
+}
+
+// SetCurrentOptions selects the options with the given indices in multi-select
+// mode and deselects all others. Indices outside the range of options are
+// ignored.
+func (d *DropDown) SetCurrentOptions(indices ...int) *DropDown {
+	for _, option := range d.options {
+		option.Checked = false
+	}
+	for _, index := range indices {
+		if index >= 0 && index < len(d.options) {
+			d.options[index].Checked = true
+		}
+	}
+	if d.open {
+		d.updateList()
+	}
+	return d
+}
+
+// GetCurrentOptions returns the indices and texts of the options selected in
+// multi-select mode, in the order of the options.
+func (d *DropDown) GetCurrentOptions() (indices []int, texts []string) {
+	for index, option := range d.options {
+		if option.Checked {
+			indices = append(indices, index)
+			texts = append(texts, option.Text)
+		}
+	}
+	return
+}
+
+// SetCheckedFunc sets a handler which is called when the user toggles an
+// option in multi-select mode. It receives the option's index and whether it
+// is now selected.
+func (d *DropDown) SetCheckedFunc(handler func(index int, checked bool)) *DropDown {
+	d.checked = handler
+	return d
+}
+
 // SetLabel sets the text to be displayed before the input area.
 func (d *DropDown) SetLabel(label string) *DropDown {
 	d.label = label
@@ -143,21 +243,80 @@ func (d *DropDown) GetFieldWidth() int {
 	if d.fieldWidth > 0 {
 		return d.fieldWidth
 	}
-	fieldWidth := 0
+	return d.optionsWidth()
+}
+
+// optionsWidth returns the screen width of the widest option in the list.
+func (d *DropDown) optionsWidth() int {
+	var maxWidth int
 	for _, option := range d.options {
-		width := StringWidth(option.Text)
-		if width > fieldWidth {
-			fieldWidth = width
+		if option.Separator {
+			continue
+		}
+		if width := StringWidth(d.listText(option)); width > maxWidth {
+			maxWidth = width
 		}
 	}
-	return fieldWidth
+	return maxWidth
 }
 
 // AddOption adds a new selectable option to this drop-down. The "selected"
 // callback is called when this option was selected. It may be nil.
 func (d *DropDown) AddOption(text string, selected func()) *DropDown {
-	d.options = append(d.options, &dropDownOption{Text: text, Selected: selected})
-	d.list.AddItem(text, "", 0, selected)
+	d.options = append(d.options, &dropDownOption{Text: text, Selected: selected, Color: tcell.ColorDefault})
+	if d.open {
+		d.updateList()
+	}
+	return d
+}
+
+// AddSeparator adds a separator line to the options. Like options, separators
+// have an index but they cannot be selected. They are hidden while the options
+// are filtered.
+func (d *DropDown) AddSeparator() *DropDown {
+	d.options = append(d.options, &dropDownOption{Separator: true, Color: tcell.ColorDefault})
+	if d.open {
+		d.updateList()
+	}
+	return d
+}
+
+// SetOptionDisabled sets whether or not the option with the given index is
+// disabled. Disabled options are shown in a different color and cannot be
+// selected. Indices outside the range of options are ignored.
+func (d *DropDown) SetOptionDisabled(index int, disabled bool) *DropDown {
+	if index >= 0 && index < len(d.options) {
+		d.options[index].Disabled = disabled
+		if d.open {
+			d.updateList()
+		}
+	}
+	return d
+}
+
+// SetOptionStyle sets the text color and attributes of the option with the
+// given index in the list of options. A color of tcell.ColorDefault keeps the
+// drop-down's text color. Indices outside the range of options are ignored.
+func (d *DropDown) SetOptionStyle(index int, color tcell.Color, attributes tcell.AttrMask) *DropDown {
+	if index >= 0 && index < len(d.options) {
+		d.options[index].Color = color
+		d.options[index].Attributes = attributes
+		if d.open {
+			d.updateList()
+		}
+	}
+	return d
+}
+
+// SetOptionDrawFunc sets a function which draws the options in the list of
+// options instead of the drop-down, e.g. to show an icon and a shortcut next to
+// the option's text. It is called for each visible option (except separators)
+// with the area of its row, which has already been cleared, the option's index
+// and text, and whether the option is the one under the cursor. The function
+// should not draw outside the given area. Provide nil to use the default
+// drawing.
+func (d *DropDown) SetOptionDrawFunc(handler func(screen tcell.Screen, x, y, width, index int, text string, selected bool)) *DropDown {
+	d.drawOption = handler
 	return d
 }
 
@@ -166,7 +325,6 @@ func (d *DropDown) AddOption(text string, selected func()) *DropDown {
 // It will be called with the option's text and its index into the options
 // slice. The "selected" parameter may be nil.
 func (d *DropDown) SetOptions(texts []string, selected func(text string, index int)) *DropDown {
-	d.list.Clear()
 	d.options = nil
 	for index, text := range texts {
 		func(t string, i int) {
@@ -180,6 +338,80 @@ func (d *DropDown) SetOptions(texts []string, selected func(text string, index i
 	return d
 }
 
+// SetOptionsFunc replaces all current options with the ones returned by the
+// provided function, which is called in a separate goroutine, e.g. to fetch the
+// options from a remote service. Until it returns, the drop-down has no options
+// and shows a loading animation when opened. The "selected" callback is the
+// same as for SetOptions().
+//
+// As the drop-down cannot redraw itself, the "redraw" function is called
+// repeatedly while the options are loading and once when they are available.
+// It will usually call Application.Draw(). The options are applied when the
+// drop-down is drawn next.
+func (d *DropDown) SetOptionsFunc(load func() []string, selected func(text string, index int), redraw func()) *DropDown {
+	d.loadMutex.Lock()
+	d.loadID++
+	id := d.loadID
+	d.loading = true
+	d.loaded, d.loadPending = nil, false
+	d.loadSelected = selected
+	d.loadMutex.Unlock()
+	d.SetOptions(nil, nil)
+
+	go func() {
+		options := load()
+		d.loadMutex.Lock()
+		if id != d.loadID {
+			d.loadMutex.Unlock()
+			return // A newer load was started in the meantime.
+		}
+		d.loading = false
+		d.loaded, d.loadPending = options, true
+		d.loadMutex.Unlock()
+		d.MarkDirty()
+		if redraw != nil {
+			redraw()
+		}
+	}()
+
+	// Animate the loading indicator.
+	if redraw != nil {
+		var tick func()
+		tick = func() {
+			if !d.isLoading(id) {
+				return
+			}
+			d.MarkDirty()
+			redraw()
+			time.AfterFunc(100*time.Millisecond, tick)
+		}
+		time.AfterFunc(100*time.Millisecond, tick)
+	}
+
+	return d
+}
+
+// isLoading returns whether options are still being loaded by the call of
+// SetOptionsFunc() with the given load ID. Pass a negative ID for the latest
+// call.
+func (d *DropDown) isLoading(id int) bool {
+	d.loadMutex.Lock()
+	defer d.loadMutex.Unlock()
+	return d.loading && (id < 0 || id == d.loadID)
+}
+
+// applyLoaded replaces the options with those loaded by SetOptionsFunc(), if
+// they have become available.
+func (d *DropDown) applyLoaded() {
+	d.loadMutex.Lock()
+	options, pending, selected := d.loaded, d.loadPending, d.loadSelected
+	d.loaded, d.loadPending = nil, false
+	d.loadMutex.Unlock()
+	if pending {
+		d.SetOptions(options, selected)
+	}
+}
+
 // SetDoneFunc sets a handler which is called when the user is done selecting
 // options. The callback function is provided with the key that was pressed,
 // which is one of the following:
@@ -197,9 +429,144 @@ func (d *DropDown) SetFinishedFunc(handler func(key tcell.Key)) FormItem {
 	return d.SetDoneFunc(handler)
 }
 
+// SetDisabled sets whether or not the drop-down is disabled. Disabled
+// drop-downs cannot be opened and are skipped by Form navigation. See
+// Box.SetDisabled() for details.
+func (d *DropDown) SetDisabled(disabled bool) *DropDown {
+	d.Box.SetDisabled(disabled)
+	return d
+}
+
+// updateList fills the list with the options which contain the filter text,
+// ignoring case. If there is a filter text, the first option starting with it
+// is selected. Otherwise, the current option is selected.
+func (d *DropDown) updateList() {
+	d.list.Clear()
+	d.filtered = d.filtered[:0]
+	filter := strings.ToLower(d.filter)
+	current := -1
+	for index, option := range d.options {
+		if option.Separator {
+			if filter == "" {
+				d.filtered = append(d.filtered, index)
+				d.list.AddItem(strings.Repeat(string(GraphicsHoriBar), d.optionsWidth()), "", 0, nil)
+				d.list.SetItemDisabled(len(d.filtered)-1, true)
+			}
+			continue
+		}
+		text := strings.ToLower(StripTags(option.Text))
+		if !strings.Contains(text, filter) {
+			continue
+		}
+		if !option.Disabled && (filter == "" && index == d.currentOption || filter != "" && current < 0 && strings.HasPrefix(text, filter)) {
+			current = len(d.filtered)
+		}
+		d.filtered = append(d.filtered, index)
+		d.list.AddItem(d.listText(option), "", 0, nil)
+		d.list.SetItemDisabled(len(d.filtered)-1, option.Disabled)
+	}
+	if current < 0 && len(d.filtered) > 0 {
+		current = d.list.enabledItem(0, 1)
+	}
+	if current >= 0 {
+		d.list.SetCurrentItem(current)
+	}
+}
+
+// listText returns the text shown in the list for the given option. In
+// multi-select mode, it is prefixed with a check mark if the option is
+// selected.
+func (d *DropDown) listText(option *dropDownOption) string {
+	text := option.Text
+	if option.Color != tcell.ColorDefault || option.Attributes != 0 {
+		text = styleTag(option.Color, option.Attributes) + text
+	}
+	if !d.multiple {
+		return text
+	}
+	if option.Checked {
+		return string(GraphicsCheckMark) + " " + text
+	}
+	return "  " + text
+}
+
+// toggleOption toggles the option under the cursor in multi-select mode.
+func (d *DropDown) toggleOption() {
+	current := d.list.currentItem
+	if current < 0 || current >= len(d.filtered) {
+		return
+	}
+	index := d.filtered[current]
+	option := d.options[index]
+	if option.Disabled || option.Separator {
+		return
+	}
+	option.Checked = !option.Checked
+	d.list.items[current].MainText = d.listText(option)
+	if d.checked != nil {
+		d.checked(index, option.Checked)
+	}
+}
+
+// filterInput captures the key events of the open list. Typed characters are
+// added to the filter text which narrows down the options. Backspace removes
+// the last character and Escape clears the filter text.
+func (d *DropDown) filterInput(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Key() {
+	case tcell.KeyEnter:
+		if len(d.filtered) == 0 {
+			return nil // Nothing to select.
+		}
+		return event
+	case tcell.KeyRune:
+		if event.Rune() == ' ' && d.multiple {
+			d.toggleOption()
+			return nil
+		}
+		if event.Rune() == ' ' && d.filter == "" {
+			if len(d.filtered) == 0 {
+				return nil
+			}
+			return event // Select the current option.
+		}
+		d.filter += string(event.Rune())
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if d.filter == "" {
+			return nil
+		}
+		runes := []rune(d.filter)
+		d.filter = string(runes[:len(runes)-1])
+	case tcell.KeyEscape:
+		if d.filter == "" {
+			return event // Close the list.
+		}
+		d.filter = ""
+	default:
+		return event
+	}
+	d.updateList()
+	return nil
+}
+
+// applyTheme is called when the application's theme changes.
+func (d *DropDown) applyTheme(from, to *Theme) {
+	d.Box.applyTheme(from, to)
+	themeColor(&d.labelColor, from.SecondaryTextColor, to.SecondaryTextColor)
+	themeColor(&d.fieldBackgroundColor, from.ContrastBackgroundColor, to.ContrastBackgroundColor)
+	themeColor(&d.fieldTextColor, from.PrimaryTextColor, to.PrimaryTextColor)
+
+	// The drop-down list uses its own colors.
+	themeColor(&d.list.mainTextColor, from.PrimitiveBackgroundColor, to.PrimitiveBackgroundColor)
+	themeColor(&d.list.selectedTextColor, from.PrimitiveBackgroundColor, to.PrimitiveBackgroundColor)
+	themeColor(&d.list.selectedBackgroundColor, from.PrimaryTextColor, to.PrimaryTextColor)
+	themeColor(&d.list.backgroundColor, from.MoreContrastBackgroundColor, to.MoreContrastBackgroundColor)
+}
+
 // Draw draws this primitive onto the screen.
 func (d *DropDown) Draw(screen tcell.Screen) {
+	defer d.applyStyle(&d.fieldTextColor)()
 	d.Box.Draw(screen)
+	d.applyLoaded()
 
 	// Prepare.
 	x, y, width, height := d.GetInnerRect()
@@ -209,17 +576,11 @@ func (d *DropDown) Draw(screen tcell.Screen) {
 	}
 
 	// Draw label.
-	_, drawnWidth := Print(screen, d.label, x, y, rightLimit-x, AlignLeft, d.labelColor)
+	_, drawnWidth := Print(screen, d.bidiText(d.label), x, y, rightLimit-x, AlignLeft, d.labelColor)
 	x += drawnWidth
 
 	// What's the longest option text?
-	maxWidth := 0
-	for _, option := range d.options {
-		strWidth := StringWidth(option.Text)
-		if strWidth > maxWidth {
-			maxWidth = strWidth
-		}
-	}
+	maxWidth := d.optionsWidth()
 
 	// Draw selection area.
 	fieldWidth := d.fieldWidth
@@ -237,13 +598,18 @@ func (d *DropDown) Draw(screen tcell.Screen) {
 		screen.SetContent(x+index, y, ' ', nil, fieldStyle)
 	}
 
-	// Draw selected text.
-	if d.currentOption >= 0 && d.currentOption < len(d.options) {
+	// Draw selected text or, while filtering, the filter text.
+	if d.open && d.filter != "" {
+		_, filterWidth := Print(screen, Escape(d.filter), x, y, fieldWidth, AlignLeft, d.fieldTextColor)
+		if d.HasFocus() && filterWidth < fieldWidth {
+			screen.ShowCursor(x+filterWidth, y)
+		}
+	} else if text := d.fieldText(fieldWidth); text != "" {
 		color := d.fieldTextColor
 		if d.GetFocusable().HasFocus() && !d.open {
 			color = d.fieldBackgroundColor
 		}
-		Print(screen, d.options[d.currentOption].Text, x, y, fieldWidth, AlignLeft, color)
+		Print(screen, text, x, y, fieldWidth, AlignLeft, color)
 	}
 
 	// Draw options list.
@@ -252,19 +618,81 @@ func (d *DropDown) Draw(screen tcell.Screen) {
 		lx := x
 		ly := y + 1
 		lwidth := maxWidth
-		lheight := len(d.options)
+		lheight := len(d.filtered)
 		_, sheight := screen.Size()
 		if ly+lheight >= sheight && ly-lheight-1 >= 0 {
 			ly = y - lheight
+		} else if ly+lheight > sheight {
+			lheight = sheight - ly // Long lists are scrolled.
 		}
 		d.list.SetRect(lx, ly, lwidth, lheight)
 		d.list.Draw(screen)
+		if d.drawOption != nil {
+			d.drawOptions(screen)
+		}
+
+		// Show an animation while the options are loading.
+		if d.isLoading(-1) && ly < sheight {
+			frame := dropDownSpinner[time.Now().UnixNano()/int64(100*time.Millisecond)%int64(len(dropDownSpinner))]
+			text := string(frame) + " Loading" + string(GraphicsEllipsis)
+			if lwidth < StringWidth(text) {
+				lwidth = StringWidth(text)
+			}
+			style := tcell.StyleDefault.Background(d.list.backgroundColor)
+			for index := 0; index < lwidth; index++ {
+				screen.SetContent(lx+index, ly, ' ', nil, style)
+			}
+			Print(screen, text, lx, ly, lwidth, AlignLeft, d.list.mainTextColor)
+		}
 	}
 }
 
+// drawOptions draws the visible options of the open list with the custom draw
+// function (see SetOptionDrawFunc()).
+func (d *DropDown) drawOptions(screen tcell.Screen) {
+	x, y, width, height := d.list.GetInnerRect()
+	if d.list.scrollBar.shown(height, len(d.filtered)) {
+		width--
+	}
+	style := tcell.StyleDefault.Background(d.list.backgroundColor)
+	for row := 0; row < height; row++ {
+		item := d.list.itemOffset + row
+		if item >= len(d.filtered) {
+			break
+		}
+		option := d.options[d.filtered[item]]
+		if option.Separator {
+			continue
+		}
+		for index := 0; index < width; index++ {
+			screen.SetContent(x+index, y+row, ' ', nil, style)
+		}
+		d.drawOption(screen, x, y+row, width, d.filtered[item], option.Text, item == d.list.currentItem)
+	}
+}
+
+// fieldText returns the text shown in the field when the options are hidden,
+// given the width of the field.
+func (d *DropDown) fieldText(width int) string {
+	if !d.multiple {
+		if d.currentOption >= 0 && d.currentOption < len(d.options) {
+			return d.options[d.currentOption].Text
+		}
+		return ""
+	}
+	_, texts := d.GetCurrentOptions()
+	text := strings.Join(texts, ", ")
+	if len(texts) > 1 && StringWidth(text) > width {
+		text = fmt.Sprintf("%d selected", len(texts))
+	}
+	return text
+}
+
 // InputHandler returns the handler for this primitive.
 func (d *DropDown) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
 	return d.wrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		d.applyLoaded()
+
 		// Process key event.
 		switch key := event.Key(); key {
 		case tcell.KeyEnter, tcell.KeyRune, tcell.KeyDown:
@@ -272,17 +700,26 @@ func (d *DropDown) InputHandler() func(event *tcell.EventKey, setFocus func(p Pr
 				break
 			}
 			d.open = true
+			d.filter = ""
+			d.updateList()
 			d.list.SetSelectedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
 				// An option was selected. Close the list again.
 				d.open = false
 				setFocus(d)
-				d.currentOption = index
+				if d.multiple {
+					return // Options are selected with the space bar.
+				}
+				d.currentOption = d.filtered[index]
 
 				// Trigger "selected" event.
 				if d.options[d.currentOption].Selected != nil {
 					d.options[d.currentOption].Selected()
 				}
 			})
+			d.list.SetDoneFunc(func() {
+				d.open = false
+				setFocus(d)
+			})
 			setFocus(d.list)
 		case tcell.KeyEscape, tcell.KeyTab, tcell.KeyBacktab:
 			if d.done != nil {
@@ -307,3 +744,9 @@ func (d *DropDown) HasFocus() bool {
 	}
 	return d.hasFocus
 }
+
+// drawsOutside returns whether the list of options, which extends below (or
+// above) the drop-down, is shown.
+func (d *DropDown) drawsOutside() bool {
+	return d.open
+}
@@ -0,0 +1,45 @@
+package tview
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell"
+)
+
+func TestDropDownOptionsFuncDirtyTracking(t *testing.T) {
+	dropDown := NewDropDown()
+	flex := NewFlex().SetDirection(FlexRow).
+		AddItem(dropDown, 1, 0, true).
+		AddItem(NewBox(), 0, 1, false)
+	app, screen := newTestApplication(t, flex, 20, 5)
+	app.EnableDirtyTracking(true)
+	app.SetFocus(dropDown)
+	loaded := make(chan struct{})
+	dropDown.SetOptionsFunc(func() []string {
+		<-loaded
+		return []string{"first", "second"}
+	}, nil, func() { app.Draw() })
+	app.handleKey(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)) // Open the list.
+
+	// The loading animation is running.
+	spinner := screenLine(screen, 1)
+	time.Sleep(250 * time.Millisecond)
+	if line := screenLine(screen, 1); line == spinner {
+		t.Errorf("the loading animation did not advance: %q", line)
+	}
+
+	// The loaded options are shown.
+	close(loaded)
+	time.Sleep(50 * time.Millisecond)
+	if line := screenLine(screen, 1); line[:5] != "first" {
+		t.Errorf("the loaded options are not shown: %q", line)
+	}
+
+	// Selecting an option closes the list.
+	app.handleKey(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+	if line := screenLine(screen, 1); line != strings.Repeat(" ", 20) {
+		t.Errorf("the closed list is still shown: %q", line)
+	}
+}
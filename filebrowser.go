@@ -0,0 +1,633 @@
+package tview
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell"
+)
+
+// Sort orders for the FileBrowser's directory listing.
+const (
+	FileSortName = iota
+	FileSortSize
+	FileSortModTime
+)
+
+// FileBrowser lets the user navigate the file system and select files or
+// directories. It shows the current directory in a path bar at the top,
+// followed by a listing of the directory's entries. Subdirectories are always
+// listed before files.
+//
+// The following keys are available:
+//
+//   - Up arrow, down arrow, k, j: Move the cursor.
+//   - Page up, page down, Home, End: Move the cursor by a page or to the
+//     first/last entry.
+//   - Enter, Right arrow, l: Open the directory under the cursor or select
+//     the file under the cursor.
+//   - Space: Select the entry under the cursor (file or directory).
+//   - Backspace, Left arrow, h: Go to the parent directory.
+//   - ~: Go to the user's home directory.
+//   - .: Show/hide hidden files.
+//   - s: Switch the sort order (name, size, modification time).
+//   - r: Reverse the sort order.
+//   - /: Start filtering. Typed characters are added to the filter, Enter
+//     finishes filtering, Escape removes the filter.
+//   - Escape, Tab, Backtab: Finish (see SetDoneFunc()).
+type FileBrowser struct {
+	*Box
+
+	// The absolute path of the current directory.
+	path string
+
+	// The error encountered when reading the current directory, if any.
+	err error
+
+	// All entries of the current directory.
+	entries []os.FileInfo
+
+	// The entries which are currently listed, filtered and sorted.
+	visible []os.FileInfo
+
+	// The index of the entry the cursor is on. The ".." entry, if shown, is
+	// not part of "visible" and has the index -1.
+	currentEntry int
+
+	// The index of the first listed entry (-1 for "..").
+	offset int
+
+	// Whether or not hidden files (starting with a dot) are listed.
+	showHidden bool
+
+	// One of the FileSort constants.
+	sortBy int
+
+	// Whether or not the sort order is reversed.
+	sortReverse bool
+
+	// Only entries containing this string (case-insensitive) are listed.
+	filter string
+
+	// Set to true while the user is typing a filter.
+	filtering bool
+
+	// The color of the path bar.
+	pathColor tcell.Color
+
+	// The color of directory names.
+	directoryColor tcell.Color
+
+	// The color of file names.
+	fileColor tcell.Color
+
+	// The color of file sizes and other details.
+	detailColor tcell.Color
+
+	// The text and background colors of the entry under the cursor.
+	selectedTextColor, selectedBackgroundColor tcell.Color
+
+	// An optional function which is called when the current directory changes.
+	changed func(path string)
+
+	// An optional function which is called when the user selects a file or a
+	// directory.
+	selected func(path string, info os.FileInfo)
+
+	// An optional function which is called when the user presses Escape, Tab,
+	// or Backtab.
+	done func(key tcell.Key)
+}
+
+// NewFileBrowser returns a new file browser showing the current working
+// directory.
+func NewFileBrowser() *FileBrowser {
+	styles := Styles.forWidget("FileBrowser")
+	f := &FileBrowser{
+		Box:                     NewBox(),
+		pathColor:               styles.SecondaryTextColor,
+		directoryColor:          styles.TertiaryTextColor,
+		fileColor:               styles.PrimaryTextColor,
+		detailColor:             styles.GraphicsColor,
+		selectedTextColor:       styles.PrimitiveBackgroundColor,
+		selectedBackgroundColor: styles.PrimaryTextColor,
+	}
+	path, err := os.Getwd()
+	if err != nil {
+		path = string(filepath.Separator)
+	}
+	f.SetPath(path)
+	return f
+}
+
+// SetPath changes the current directory to the given path. If the path refers
+// to a file, its directory is shown and the cursor is placed on the file.
+func (f *FileBrowser) SetPath(path string) *FileBrowser {
+	if absolute, err := filepath.Abs(path); err == nil {
+		path = absolute
+	}
+	var name string
+	if info, err := os.Stat(path); err == nil && !info.IsDir() {
+		path, name = filepath.Split(path)
+		path = filepath.Clean(path)
+	}
+	f.changeDir(path)
+	if name != "" {
+		f.selectName(name)
+	}
+	return f
+}
+
+// GetPath returns the absolute path of the current directory.
+func (f *FileBrowser) GetPath() string {
+	return f.path
+}
+
+// GetCurrentEntry returns the absolute path of the entry under the cursor. An
+// empty string is returned if the directory is empty.
+func (f *FileBrowser) GetCurrentEntry() string {
+	if f.currentEntry < 0 {
+		return filepath.Dir(f.path)
+	}
+	if f.currentEntry >= len(f.visible) {
+		return ""
+	}
+	return filepath.Join(f.path, f.visible[f.currentEntry].Name())
+}
+
+// Refresh reads the current directory again.
+func (f *FileBrowser) Refresh() *FileBrowser {
+	var name string
+	if f.currentEntry >= 0 && f.currentEntry < len(f.visible) {
+		name = f.visible[f.currentEntry].Name()
+	}
+	f.entries, f.err = ioutil.ReadDir(f.path)
+	f.update()
+	f.selectName(name)
+	return f
+}
+
+// ShowHidden sets whether or not hidden files (whose name starts with a dot)
+// are listed.
+func (f *FileBrowser) ShowHidden(show bool) *FileBrowser {
+	f.showHidden = show
+	f.update()
+	return f
+}
+
+// SetSortOrder sets the order of the directory listing. "sortBy" must be one
+// of FileSortName, FileSortSize, or FileSortModTime. Set "reverse" to true to
+// reverse the order.
+func (f *FileBrowser) SetSortOrder(sortBy int, reverse bool) *FileBrowser {
+	f.sortBy, f.sortReverse = sortBy, reverse
+	f.update()
+	return f
+}
+
+// SetFilter sets a string which entry names must contain (ignoring case) to
+// be listed. Provide an empty string to list all entries.
+func (f *FileBrowser) SetFilter(filter string) *FileBrowser {
+	f.filter = filter
+	f.update()
+	return f
+}
+
+// GetFilter returns the current filter string.
+func (f *FileBrowser) GetFilter() string {
+	return f.filter
+}
+
+// SetPathColor sets the color of the path bar.
+func (f *FileBrowser) SetPathColor(color tcell.Color) *FileBrowser {
+	f.pathColor = color
+	return f
+}
+
+// SetDirectoryColor sets the color of directory names.
+func (f *FileBrowser) SetDirectoryColor(color tcell.Color) *FileBrowser {
+	f.directoryColor = color
+	return f
+}
+
+// SetFileColor sets the color of file names.
+func (f *FileBrowser) SetFileColor(color tcell.Color) *FileBrowser {
+	f.fileColor = color
+	return f
+}
+
+// SetDetailColor sets the color of file sizes.
+func (f *FileBrowser) SetDetailColor(color tcell.Color) *FileBrowser {
+	f.detailColor = color
+	return f
+}
+
+// SetSelectedTextColor sets the text color of the entry under the cursor.
+func (f *FileBrowser) SetSelectedTextColor(color tcell.Color) *FileBrowser {
+	f.selectedTextColor = color
+	return f
+}
+
+// SetSelectedBackgroundColor sets the background color of the entry under the
+// cursor.
+func (f *FileBrowser) SetSelectedBackgroundColor(color tcell.Color) *FileBrowser {
+	f.selectedBackgroundColor = color
+	return f
+}
+
+// SetChangedFunc sets a handler which is called with the new directory's
+// absolute path when the current directory changes.
+func (f *FileBrowser) SetChangedFunc(handler func(path string)) *FileBrowser {
+	f.changed = handler
+	return f
+}
+
+// SetSelectedFunc sets a handler which is called when the user selects an
+// entry. This happens when Enter is pressed on a file or Space is pressed on a
+// file or directory. The handler receives the entry's absolute path and its
+// file info.
+func (f *FileBrowser) SetSelectedFunc(handler func(path string, info os.FileInfo)) *FileBrowser {
+	f.selected = handler
+	return f
+}
+
+// SetDoneFunc sets a handler which is called when the user presses the
+// Escape, Tab, or Backtab key.
+func (f *FileBrowser) SetDoneFunc(handler func(key tcell.Key)) *FileBrowser {
+	f.done = handler
+	return f
+}
+
+// hasParent returns whether or not the current directory has a parent, i.e.
+// whether or not the ".." entry is listed.
+func (f *FileBrowser) hasParent() bool {
+	return filepath.Dir(f.path) != f.path
+}
+
+// changeDir reads the given directory and makes it the current directory.
+func (f *FileBrowser) changeDir(path string) {
+	f.path = path
+	f.filter = ""
+	f.filtering = false
+	f.entries, f.err = ioutil.ReadDir(path)
+	f.update()
+	f.currentEntry, f.offset = 0, 0
+	if len(f.visible) == 0 && f.hasParent() {
+		f.currentEntry = -1
+	}
+	if f.changed != nil {
+		f.changed(path)
+	}
+}
+
+// selectName places the cursor on the entry with the given name, if it is
+// listed.
+func (f *FileBrowser) selectName(name string) {
+	for index, info := range f.visible {
+		if info.Name() == name {
+			f.currentEntry = index
+			return
+		}
+	}
+}
+
+// update filters and sorts the directory's entries.
+func (f *FileBrowser) update() {
+	filter := strings.ToLower(f.filter)
+	f.visible = f.visible[:0]
+	for _, info := range f.entries {
+		name := info.Name()
+		if !f.showHidden && strings.HasPrefix(name, ".") {
+			continue
+		}
+		if filter != "" && !strings.Contains(strings.ToLower(name), filter) {
+			continue
+		}
+		f.visible = append(f.visible, info)
+	}
+	sort.SliceStable(f.visible, func(i, j int) bool {
+		a, b := f.visible[i], f.visible[j]
+		if a.IsDir() != b.IsDir() {
+			return a.IsDir()
+		}
+		var less bool
+		switch f.sortBy {
+		case FileSortSize:
+			if a.Size() == b.Size() {
+				return a.Name() < b.Name()
+			}
+			less = a.Size() < b.Size()
+		case FileSortModTime:
+			if a.ModTime().Equal(b.ModTime()) {
+				return a.Name() < b.Name()
+			}
+			less = a.ModTime().Before(b.ModTime())
+		default:
+			less = strings.ToLower(a.Name()) < strings.ToLower(b.Name())
+		}
+		if f.sortReverse {
+			return !less
+		}
+		return less
+	})
+	if f.currentEntry >= len(f.visible) {
+		f.currentEntry = len(f.visible) - 1
+	}
+	if f.currentEntry < 0 && !f.hasParent() {
+		f.currentEntry = 0
+	}
+}
+
+// first returns the index of the first entry in the listing.
+func (f *FileBrowser) first() int {
+	if f.hasParent() {
+		return -1
+	}
+	return 0
+}
+
+// activate opens the directory under the cursor or selects the file under the
+// cursor.
+func (f *FileBrowser) activate() {
+	if f.currentEntry < 0 {
+		f.parent()
+		return
+	}
+	if f.currentEntry >= len(f.visible) {
+		return
+	}
+	info := f.visible[f.currentEntry]
+	path := filepath.Join(f.path, info.Name())
+	if info.IsDir() {
+		f.changeDir(path)
+		return
+	}
+	if f.selected != nil {
+		f.selected(path, info)
+	}
+}
+
+// parent moves to the parent directory, placing the cursor on the directory
+// we came from.
+func (f *FileBrowser) parent() {
+	if !f.hasParent() {
+		return
+	}
+	name := filepath.Base(f.path)
+	f.changeDir(filepath.Dir(f.path))
+	f.selectName(name)
+}
+
+// formatFileSize returns a short, human-readable version of the given file
+// size.
+func formatFileSize(size int64) string {
+	if size < 1024 {
+		return fmt.Sprintf("%d", size)
+	}
+	value := float64(size)
+	for _, unit := range []string{"K", "M", "G", "T"} {
+		value /= 1024
+		if value < 1024 {
+			if value < 10 {
+				return fmt.Sprintf("%.1f%s", value, unit)
+			}
+			return fmt.Sprintf("%.0f%s", value, unit)
+		}
+	}
+	return fmt.Sprintf("%.0fP", value/1024)
+}
+
+// applyTheme is called when the application's theme changes.
+func (f *FileBrowser) applyTheme(from, to *Theme) {
+	f.Box.applyTheme(from, to)
+	themeColor(&f.pathColor, from.SecondaryTextColor, to.SecondaryTextColor)
+	themeColor(&f.directoryColor, from.TertiaryTextColor, to.TertiaryTextColor)
+	themeColor(&f.fileColor, from.PrimaryTextColor, to.PrimaryTextColor)
+	themeColor(&f.detailColor, from.GraphicsColor, to.GraphicsColor)
+	themeColor(&f.selectedTextColor, from.PrimitiveBackgroundColor, to.PrimitiveBackgroundColor)
+	themeColor(&f.selectedBackgroundColor, from.PrimaryTextColor, to.PrimaryTextColor)
+}
+
+// Draw draws this primitive onto the screen.
+func (f *FileBrowser) Draw(screen tcell.Screen) {
+	defer f.applyStyle(&f.fileColor)()
+	f.Box.Draw(screen)
+	x, y, width, height := f.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	// The path bar, with the filter on the right.
+	var filter string
+	if f.filtering || f.filter != "" {
+		filter = "/" + Escape(f.filter)
+		if f.filtering {
+			filter += "_"
+		}
+	}
+	filterWidth := StringWidth(filter)
+	pathWidth := width
+	if filterWidth > 0 {
+		pathWidth -= filterWidth + 1
+		Print(screen, filter, x, y, width, AlignRight, f.pathColor)
+	}
+	path := f.path
+	if w := StringWidth(path); w > pathWidth && pathWidth > 0 {
+		path = "…" + string([]rune(path)[len([]rune(path))-pathWidth+1:])
+	}
+	Print(screen, Escape(path), x, y, pathWidth, AlignLeft, f.pathColor)
+	y++
+	height--
+	if height <= 0 {
+		return
+	}
+
+	// Show errors instead of the listing.
+	if f.err != nil {
+		Print(screen, Escape(f.err.Error()), x, y, width, AlignLeft, f.pathColor)
+		return
+	}
+
+	// Scroll to the cursor.
+	if f.offset < f.first() {
+		f.offset = f.first()
+	}
+	if f.currentEntry < f.offset {
+		f.offset = f.currentEntry
+	}
+	if f.currentEntry >= f.offset+height {
+		f.offset = f.currentEntry - height + 1
+	}
+
+	// Draw the entries.
+	for row := 0; row < height; row++ {
+		index := f.offset + row
+		if index >= len(f.visible) {
+			break
+		}
+		name, detail, color := "..", "", f.directoryColor
+		if index >= 0 {
+			info := f.visible[index]
+			name = info.Name()
+			if info.IsDir() {
+				name += string(filepath.Separator)
+			} else {
+				color = f.fileColor
+				detail = formatFileSize(info.Size())
+			}
+		}
+		detailColor := f.detailColor
+		if index == f.currentEntry {
+			color, detailColor = f.selectedTextColor, f.selectedTextColor
+			style := tcell.StyleDefault.Background(f.selectedBackgroundColor)
+			for column := 0; column < width; column++ {
+				screen.SetContent(x+column, y+row, ' ', nil, style)
+			}
+		}
+		nameWidth := width
+		if detail != "" {
+			Print(screen, detail, x, y+row, width, AlignRight, detailColor)
+			nameWidth -= len(detail) + 1
+		}
+		Print(screen, Escape(name), x, y+row, nameWidth, AlignLeft, color)
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (f *FileBrowser) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return f.wrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		_, _, _, height := f.GetInnerRect()
+		pageSize := height - 1
+		if pageSize < 1 {
+			pageSize = 1
+		}
+		last := len(f.visible) - 1
+		if last < f.first() {
+			last = f.first()
+		}
+
+		// Typing a filter.
+		if f.filtering {
+			switch event.Key() {
+			case tcell.KeyRune:
+				f.SetFilter(f.filter + string(event.Rune()))
+				return
+			case tcell.KeyBackspace, tcell.KeyBackspace2:
+				if f.filter != "" {
+					runes := []rune(f.filter)
+					f.SetFilter(string(runes[:len(runes)-1]))
+				}
+				return
+			case tcell.KeyEnter:
+				f.filtering = false
+				return
+			case tcell.KeyEscape:
+				f.filtering = false
+				f.SetFilter("")
+				return
+			}
+		}
+
+		switch key := event.Key(); key {
+		case tcell.KeyUp:
+			f.currentEntry--
+		case tcell.KeyDown:
+			f.currentEntry++
+		case tcell.KeyPgUp:
+			f.currentEntry -= pageSize
+		case tcell.KeyPgDn:
+			f.currentEntry += pageSize
+		case tcell.KeyHome:
+			f.currentEntry = f.first()
+		case tcell.KeyEnd:
+			f.currentEntry = last
+		case tcell.KeyEnter, tcell.KeyRight:
+			f.activate()
+		case tcell.KeyBackspace, tcell.KeyBackspace2, tcell.KeyLeft:
+			f.parent()
+		case tcell.KeyEscape:
+			if f.filter != "" {
+				f.SetFilter("")
+			} else if f.done != nil {
+				f.done(key)
+			}
+		case tcell.KeyTab, tcell.KeyBacktab:
+			if f.done != nil {
+				f.done(key)
+			}
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'k':
+				f.currentEntry--
+			case 'j':
+				f.currentEntry++
+			case 'l':
+				f.activate()
+			case 'h':
+				f.parent()
+			case ' ':
+				if f.currentEntry >= 0 && f.currentEntry < len(f.visible) && f.selected != nil {
+					info := f.visible[f.currentEntry]
+					f.selected(filepath.Join(f.path, info.Name()), info)
+				}
+			case '~':
+				if home, err := os.UserHomeDir(); err == nil {
+					f.changeDir(home)
+				}
+			case '.':
+				f.ShowHidden(!f.showHidden)
+			case 's':
+				f.SetSortOrder((f.sortBy+1)%3, f.sortReverse)
+			case 'r':
+				f.SetSortOrder(f.sortBy, !f.sortReverse)
+			case '/':
+				f.filtering = true
+			}
+		}
+
+		if f.currentEntry < f.first() {
+			f.currentEntry = f.first()
+		} else if f.currentEntry > last {
+			f.currentEntry = last
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (f *FileBrowser) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return f.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+		if !f.InRect(x, y) {
+			return false, nil
+		}
+		_, rectY, _, _ := f.GetInnerRect()
+		switch action {
+		case MouseLeftClick, MouseLeftDoubleClick:
+			setFocus(f)
+			if y <= rectY {
+				break
+			}
+			index := f.offset + y - rectY - 1
+			if index >= f.first() && index < len(f.visible) {
+				f.currentEntry = index
+				if action == MouseLeftDoubleClick {
+					f.activate()
+				}
+			}
+		case MouseScrollUp:
+			if f.currentEntry > f.first() {
+				f.currentEntry--
+			}
+		case MouseScrollDown:
+			if f.currentEntry < len(f.visible)-1 {
+				f.currentEntry++
+			}
+		default:
+			return false, nil
+		}
+		return true, nil
+	})
+}
@@ -1,6 +1,8 @@
 package tview
 
 import (
+	"encoding/json"
+
 	"github.com/gdamore/tcell"
 )
 
@@ -15,6 +17,8 @@ type flexItem struct {
 	Item       Primitive // The item to be positioned. May be nil for an empty item.
 	FixedSize  int       // The item's fixed size which may not be changed, 0 if it has no fixed size.
 	Proportion int       // The item's proportion.
+	MinSize    int       // The minimum size of a proportional item, 0 if there is none.
+	MaxSize    int       // The maximum size of a proportional item, 0 if there is none.
 	Focus      bool      // Whether or not this item attracts the layout's focus.
 }
 
@@ -23,6 +27,10 @@ type flexItem struct {
 // distributed along that dimension depends on their layout settings, which is
 // either a fixed length or a proportional length. See AddItem() for details.
 //
+// If the flex is resizable (see SetResizable()), the user can move the
+// boundaries between items by dragging them with the mouse or with Ctrl-arrow
+// keys.
+//
 // See https://github.com/rivo/tview/wiki/Flex for an example.
 type Flex struct {
 	*Box
@@ -33,9 +41,39 @@ type Flex struct {
 	// FlexRow or FlexColumn.
 	direction int
 
+	// The number of cells between neighboring items.
+	gap int
+
+	// Whether or not neighboring items overlap by one cell so their borders
+	// are joined.
+	joinBorders bool
+
+	// Whether or not the items were last drawn with joined borders, i.e. if
+	// their join setting needs to be reset when joining is switched off.
+	bordersJoined bool
+
 	// If set to true, will use the entire screen as its available space instead
 	// its box dimensions.
 	fullScreen bool
+
+	// Whether or not the user can move the boundaries between items.
+	resizable bool
+
+	// The positions and sizes of the items the last time the flex was drawn,
+	// along its direction.
+	positions, sizes []int
+
+	// The index of the item whose trailing boundary is being dragged with the
+	// mouse, or -1 if none is, and the mouse position of the last drag event.
+	dragIndex, dragPosition int
+
+	// An optional handler which is called when the user moved a boundary.
+	resized func(proportions []int)
+}
+
+// flexState is the state of a Flex saved with SaveState().
+type flexState struct {
+	Proportions []int `json:"proportions"`
 }
 
 // NewFlex returns a new flexbox layout container with no primitives and its
@@ -45,6 +83,7 @@ func NewFlex() *Flex {
 	f := &Flex{
 		Box:       NewBox(),
 		direction: FlexColumn,
+		dragIndex: -1,
 	}
 	f.focus = f
 	return f
@@ -54,9 +93,42 @@ func NewFlex() *Flex {
 // distributed. This can be either FlexColumn (default) or FlexRow.
 func (f *Flex) SetDirection(direction int) *Flex {
 	f.direction = direction
+	f.MarkDirty()
+	return f
+}
+
+// SetGap sets the number of empty cells between neighboring items. No space is
+// added before the first or after the last item. Panics if a negative value is
+// provided.
+func (f *Flex) SetGap(cells int) *Flex {
+	if cells < 0 {
+		panic("Invalid gap size")
+	}
+	f.gap = cells
+	f.MarkDirty()
+	return f
+}
+
+// SetJoinBorders sets whether or not the borders of neighboring items are
+// joined. If set to true, neighboring items overlap by one cell, i.e. they
+// share an edge, and items based on Box are drawn with joined borders (see
+// Box.SetJoinBorders()) so the layout looks like a single frame with T and
+// cross junctions. The gap set with SetGap() is ignored in this case.
+func (f *Flex) SetJoinBorders(join bool) *Flex {
+	f.joinBorders = join
+	f.MarkDirty()
 	return f
 }
 
+// itemGap returns the number of cells between neighboring items. It is -1 if
+// neighboring items share an edge.
+func (f *Flex) itemGap() int {
+	if f.joinBorders {
+		return -1
+	}
+	return f.gap
+}
+
 // SetFullScreen sets the flag which, when true, causes the flex layout to use
 // the entire screen space instead of whatever size it is currently assigned to.
 func (f *Flex) SetFullScreen(fullScreen bool) *Flex {
@@ -64,6 +136,161 @@ func (f *Flex) SetFullScreen(fullScreen bool) *Flex {
 	return f
 }
 
+// SetResizable sets whether or not the user can move the boundaries between
+// items. With the mouse, a boundary is moved by dragging the gap between two
+// items (see SetGap()) or, if there is no gap, the last column or row of the
+// item before the boundary. With the keyboard, Ctrl-arrow keys in the flex's
+// direction (Ctrl-Left and Ctrl-Right for FlexColumn, Ctrl-Up and Ctrl-Down
+// for FlexRow) move the boundary after the item which contains the focus, or
+// the boundary before it if it is the last item. The application handles these
+// keys for the innermost resizable flex around the focused primitive, so they
+// are not passed on to that primitive.
+//
+// Moving a boundary changes the sizes of the two adjacent items. For fixed-size
+// items, the fixed size is changed. When a flexible item is resized, the
+// proportions of all flexible items are set to their current sizes. The
+// result can be retrieved with GetProportions() and restored later with
+// SetProportions().
+func (f *Flex) SetResizable(resizable bool) *Flex {
+	f.resizable = resizable
+	return f
+}
+
+// SetResizedFunc sets a handler which is called when the user moved a
+// boundary between two items. It receives the new proportions, see
+// GetProportions().
+func (f *Flex) SetResizedFunc(handler func(proportions []int)) *Flex {
+	f.resized = handler
+	return f
+}
+
+// GetProportions returns, for each item, its fixed size if it has one or its
+// proportion otherwise. These values can be stored and later be restored with
+// SetProportions().
+func (f *Flex) GetProportions() []int {
+	proportions := make([]int, len(f.items))
+	for index, item := range f.items {
+		if item.FixedSize > 0 {
+			proportions[index] = item.FixedSize
+		} else {
+			proportions[index] = item.Proportion
+		}
+	}
+	return proportions
+}
+
+// SetProportions sets, for each item, its fixed size if it has one or its
+// proportion otherwise. This is usually used to restore the values returned by
+// GetProportions(). Extra values are ignored.
+func (f *Flex) SetProportions(proportions ...int) *Flex {
+	for index, proportion := range proportions {
+		if index >= len(f.items) {
+			break
+		}
+		if f.items[index].FixedSize > 0 {
+			f.items[index].FixedSize = proportion
+		} else {
+			f.items[index].Proportion = proportion
+		}
+	}
+	f.MarkDirty()
+	return f
+}
+
+// resizeWithKey moves a boundary of the item which has focus if the flex is
+// resizable and the given key event is a Ctrl-arrow key in the flex's
+// direction (see SetResizable()). It returns true if the event was handled.
+func (f *Flex) resizeWithKey(event *tcell.EventKey) bool {
+	if !f.resizable || event.Modifiers()&tcell.ModCtrl == 0 {
+		return false
+	}
+	for index, item := range f.items {
+		if item.Item == nil || !item.Item.GetFocusable().HasFocus() {
+			continue
+		}
+		var delta int
+		switch event.Key() {
+		case tcell.KeyLeft, tcell.KeyUp:
+			delta = -1
+		case tcell.KeyRight, tcell.KeyDown:
+			delta = 1
+		}
+		if vertical := event.Key() == tcell.KeyUp || event.Key() == tcell.KeyDown; delta == 0 || vertical != (f.direction == FlexRow) {
+			return false
+		}
+		if index == len(f.items)-1 {
+			index--
+		}
+		if index < 0 {
+			return false
+		}
+		f.moveBoundary(index, delta)
+		f.MarkDirty()
+		return true
+	}
+	return false
+}
+
+// moveBoundary moves the boundary after the item with the given index by the
+// given number of cells, as far as the sizes of the two adjacent items allow.
+// Returns the number of cells the boundary was actually moved.
+func (f *Flex) moveBoundary(index, delta int) int {
+	if index < 0 || index >= len(f.items)-1 || index+1 >= len(f.sizes) {
+		return 0
+	}
+	first, second := &f.items[index], &f.items[index+1]
+
+	// Respect the minimum and maximum sizes.
+	minimum := func(item *flexItem) int {
+		if item.FixedSize <= 0 && item.MinSize > 1 {
+			return item.MinSize
+		}
+		return 1
+	}
+	if f.sizes[index]+delta < minimum(first) {
+		delta = minimum(first) - f.sizes[index]
+	}
+	if f.sizes[index+1]-delta < minimum(second) {
+		delta = f.sizes[index+1] - minimum(second)
+	}
+	if first.FixedSize <= 0 && first.MaxSize > 0 && f.sizes[index]+delta > first.MaxSize {
+		delta = first.MaxSize - f.sizes[index]
+	}
+	if second.FixedSize <= 0 && second.MaxSize > 0 && f.sizes[index+1]-delta > second.MaxSize {
+		delta = f.sizes[index+1] - second.MaxSize
+	}
+	if delta == 0 {
+		return 0
+	}
+
+	// Flexible items take their current sizes as their proportions.
+	if first.FixedSize <= 0 || second.FixedSize <= 0 {
+		for itemIndex := range f.items {
+			if f.items[itemIndex].FixedSize <= 0 {
+				f.items[itemIndex].Proportion = f.sizes[itemIndex]
+			}
+		}
+	}
+	f.sizes[index] += delta
+	f.sizes[index+1] -= delta
+	f.positions[index+1] += delta
+	if first.FixedSize > 0 {
+		first.FixedSize = f.sizes[index]
+	} else {
+		first.Proportion = f.sizes[index]
+	}
+	if second.FixedSize > 0 {
+		second.FixedSize = f.sizes[index+1]
+	} else {
+		second.Proportion = f.sizes[index+1]
+	}
+
+	if f.resized != nil {
+		f.resized(f.GetProportions())
+	}
+	return delta
+}
+
 // AddItem adds a new item to the container. The "fixedSize" argument is a width
 // or height that may not be changed by the layout algorithm. A value of 0 means
 // that its size is flexible and may be changed. The "proportion" argument
@@ -80,6 +307,27 @@ func (f *Flex) SetFullScreen(fullScreen bool) *Flex {
 // space but nothing will be drawn.
 func (f *Flex) AddItem(item Primitive, fixedSize, proportion int, focus bool) *Flex {
 	f.items = append(f.items, flexItem{Item: item, FixedSize: fixedSize, Proportion: proportion, Focus: focus})
+	f.MarkDirty()
+	return f
+}
+
+// AddItemWithLimits adds a new item to the container, like AddItem(), but with
+// a minimum and a maximum size that apply if the item's size is flexible. The
+// item will never be smaller than "minSize" or larger than "maxSize", even if
+// this means that the items extend beyond the container or leave space unused.
+// A value of 0 means that there is no such limit. Space which an item cannot
+// take because of its maximum size is distributed among the other flexible
+// items.
+func (f *Flex) AddItemWithLimits(item Primitive, fixedSize, proportion, minSize, maxSize int, focus bool) *Flex {
+	f.items = append(f.items, flexItem{
+		Item:       item,
+		FixedSize:  fixedSize,
+		Proportion: proportion,
+		MinSize:    minSize,
+		MaxSize:    maxSize,
+		Focus:      focus,
+	})
+	f.MarkDirty()
 	return f
 }
 
@@ -88,12 +336,43 @@ func (f *Flex) AddItem(item Primitive, fixedSize, proportion int, focus bool) *F
 func (f *Flex) RemoveItem(p Primitive) *Flex {
 	for index := len(f.items) - 1; index >= 0; index-- {
 		if f.items[index].Item == p {
+			if f.bordersJoined {
+				joinItemBorders(p, false)
+			}
 			f.items = append(f.items[:index], f.items[index+1:]...)
 		}
 	}
+	f.MarkDirty()
 	return f
 }
 
+// GetChildren returns the items of the flex in the order in which they were added.
+func (f *Flex) GetChildren() []Primitive {
+	var children []Primitive
+	for _, item := range f.items {
+		if item.Item != nil {
+			children = append(children, item.Item)
+		}
+	}
+	return children
+}
+
+// SaveState returns the proportions of the items (see GetProportions()) as
+// JSON. See SaveLayout() for details.
+func (f *Flex) SaveState() ([]byte, error) {
+	return json.Marshal(flexState{Proportions: f.GetProportions()})
+}
+
+// RestoreState restores a state returned by SaveState().
+func (f *Flex) RestoreState(data []byte) error {
+	var state flexState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	f.SetProportions(state.Proportions...)
+	return nil
+}
+
 // Draw draws this primitive onto the screen.
 func (f *Flex) Draw(screen tcell.Screen) {
 	f.Box.Draw(screen)
@@ -108,30 +387,27 @@ func (f *Flex) Draw(screen tcell.Screen) {
 
 	// How much space can we distribute?
 	x, y, width, height := f.GetInnerRect()
-	var proportionSum int
 	distSize := width
 	if f.direction == FlexRow {
 		distSize = height
 	}
-	for _, item := range f.items {
-		if item.FixedSize > 0 {
-			distSize -= item.FixedSize
-		} else {
-			proportionSum += item.Proportion
-		}
+	if len(f.items) > 1 {
+		distSize -= (len(f.items) - 1) * f.itemGap()
 	}
+	sizes := f.distribute(distSize)
+	f.sizes = sizes
+	f.positions = make([]int, len(sizes))
 
 	// Calculate positions and draw items.
 	pos := x
 	if f.direction == FlexRow {
 		pos = y
 	}
-	for _, item := range f.items {
-		size := item.FixedSize
-		if size <= 0 {
-			size = distSize * item.Proportion / proportionSum
-			distSize -= size
-			proportionSum -= item.Proportion
+	for index, item := range f.items {
+		size := sizes[index]
+		f.positions[index] = pos
+		if f.joinBorders || f.bordersJoined {
+			joinItemBorders(item.Item, f.joinBorders)
 		}
 		if item.Item != nil {
 			if f.direction == FlexColumn {
@@ -140,16 +416,89 @@ func (f *Flex) Draw(screen tcell.Screen) {
 				item.Item.SetRect(x, pos, width, size)
 			}
 		}
-		pos += size
+		pos += size + f.itemGap()
 
 		if item.Item != nil {
 			if item.Item.GetFocusable().HasFocus() {
-				defer item.Item.Draw(screen)
+				defer drawPrimitive(screen, item.Item)
 			} else {
-				item.Item.Draw(screen)
+				drawPrimitive(screen, item.Item)
 			}
 		}
 	}
+	f.bordersJoined = f.joinBorders
+}
+
+// joinItemBorders sets whether or not the border of the given item is joined
+// with those of its neighbors (see Box.SetJoinBorders()).
+func joinItemBorders(item Primitive, join bool) {
+	switch joiner := item.(type) {
+	case *Flex:
+		joiner.Box.SetJoinBorders(join) // Don't clear the shared edges.
+	case interface {
+		SetJoinBorders(bool) *Box
+	}:
+		joiner.SetJoinBorders(join)
+	}
+}
+
+// distribute returns the sizes of all items, given the space available to
+// them. Flexible items which would fall below their minimum size or exceed
+// their maximum size are given that size, and the remaining space is
+// distributed among the other flexible items.
+func (f *Flex) distribute(distSize int) []int {
+	sizes := make([]int, len(f.items))
+	limited := make([]bool, len(f.items))
+	for index, item := range f.items {
+		if item.FixedSize > 0 {
+			sizes[index] = item.FixedSize
+			limited[index] = true
+			distSize -= item.FixedSize
+		}
+	}
+	for {
+		// Distribute the remaining space proportionally.
+		var proportionSum int
+		for index, item := range f.items {
+			if !limited[index] {
+				proportionSum += item.Proportion
+			}
+		}
+		remaining, sum := distSize, proportionSum
+		for index, item := range f.items {
+			if limited[index] {
+				continue
+			}
+			size := 0
+			if sum > 0 {
+				size = remaining * item.Proportion / sum
+			}
+			remaining -= size
+			sum -= item.Proportion
+			sizes[index] = size
+		}
+
+		// Apply limits. If there were any, start over with the rest.
+		var changed bool
+		for index, item := range f.items {
+			if limited[index] {
+				continue
+			}
+			if item.MinSize > 0 && sizes[index] < item.MinSize {
+				sizes[index] = item.MinSize
+			} else if item.MaxSize > 0 && sizes[index] > item.MaxSize {
+				sizes[index] = item.MaxSize
+			} else {
+				continue
+			}
+			limited[index] = true
+			distSize -= sizes[index]
+			changed = true
+		}
+		if !changed {
+			return sizes
+		}
+	}
 }
 
 // Focus is called when this primitive receives focus.
@@ -171,3 +520,60 @@ func (f *Flex) HasFocus() bool {
 	}
 	return false
 }
+
+// MouseHandler returns the mouse handler for this primitive.
+func (f *Flex) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return f.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		// Moving a boundary.
+		mouseX, mouseY := event.Position()
+		position := mouseX
+		if f.direction == FlexRow {
+			position = mouseY
+		}
+		if f.dragIndex >= 0 {
+			switch action {
+			case MouseMove:
+				f.dragPosition += f.moveBoundary(f.dragIndex, position-f.dragPosition)
+				return true, f
+			case MouseLeftUp:
+				f.dragIndex = -1
+				return true, nil
+			}
+		}
+
+		if !f.InRect(mouseX, mouseY) {
+			return false, nil
+		}
+
+		// Start dragging a boundary.
+		if f.resizable && action == MouseLeftDown {
+			for index := 0; index < len(f.items)-1 && index+1 < len(f.positions); index++ {
+				start := f.positions[index] + f.sizes[index]
+				end := start + f.itemGap()
+				if end <= start {
+					start-- // Without a gap, the last column or row is dragged.
+					end = start + 1
+				}
+				if position >= start && position < end {
+					f.dragIndex, f.dragPosition = index, position
+					return true, f
+				}
+			}
+		}
+
+		// Pass mouse events along to the first child item that takes it.
+		for _, item := range f.items {
+			if item.Item == nil {
+				continue
+			}
+			if handler := mouseHandler(item.Item); handler != nil {
+				consumed, capture = handler(action, event, setFocus)
+				if consumed {
+					return
+				}
+			}
+		}
+
+		return
+	})
+}
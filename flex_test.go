@@ -0,0 +1,128 @@
+package tview
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell"
+)
+
+func TestFlexSetGapDirtyTracking(t *testing.T) {
+	flex := NewFlex().
+		AddItem(NewBox().SetBorder(true), 0, 1, false).
+		AddItem(NewBox().SetBorder(true), 0, 1, false)
+	app, screen := newTestApplication(t, flex, 10, 3)
+	app.EnableDirtyTracking(true)
+	app.Draw()
+	if line := screenLine(screen, 1); line != "│   ││   │" {
+		t.Fatalf("unexpected layout %q", line)
+	}
+
+	flex.SetGap(2)
+	app.Draw()
+	if line := screenLine(screen, 1); line != "│  │  │  │" {
+		t.Errorf("the gap was not applied: %q", line)
+	}
+}
+
+func TestFlexSetJoinBorders(t *testing.T) {
+	left, right := NewBox().SetBorder(true), NewBox().SetBorder(true)
+	flex := NewFlex().
+		AddItem(left, 0, 1, false).
+		AddItem(right, 0, 1, false)
+	app, screen := newTestApplication(t, flex, 10, 3)
+	app.EnableDirtyTracking(true)
+	app.Draw()
+	unjoined := screenLine(screen, 0)
+
+	flex.SetJoinBorders(true)
+	app.Draw()
+	if !left.joinBorders || !right.joinBorders {
+		t.Error("the items' borders are not joined")
+	}
+	if line := screenLine(screen, 0); line == unjoined || !strings.ContainsRune(line, '┬') {
+		t.Errorf("the borders were not joined: %q", line)
+	}
+
+	flex.SetJoinBorders(false)
+	app.Draw()
+	if left.joinBorders || right.joinBorders {
+		t.Error("the items' borders are still joined")
+	}
+	if line := screenLine(screen, 0); line != unjoined {
+		t.Errorf("the borders are still joined: %q, want %q", line, unjoined)
+	}
+
+	// Removed items are reset, too.
+	flex.SetJoinBorders(true)
+	app.Draw()
+	flex.RemoveItem(right)
+	if !left.joinBorders || right.joinBorders {
+		t.Error("the removed item's border is still joined")
+	}
+}
+
+func TestFlexResizeWithKeys(t *testing.T) {
+	left, right, bottom := NewBox(), NewInputField(), NewBox()
+	columns := NewFlex().
+		AddItem(left, 0, 1, true).
+		AddItem(right, 0, 1, false)
+	rows := NewFlex().SetDirection(FlexRow).SetResizable(true).
+		AddItem(columns, 0, 1, true).
+		AddItem(bottom, 0, 1, false)
+	var proportions []int
+	rows.SetResizedFunc(func(p []int) {
+		proportions = p
+	})
+	app, _ := newTestApplication(t, rows, 20, 10)
+	app.SetFocus(left)
+	app.Draw()
+	width := func(p Primitive) int {
+		_, _, w, _ := p.GetRect()
+		return w
+	}
+	height := func(p Primitive) int {
+		_, _, _, h := p.GetRect()
+		return h
+	}
+	ctrl := func(key tcell.Key) {
+		app.handleKey(tcell.NewEventKey(key, 0, tcell.ModCtrl))
+	}
+
+	// The columns are not resizable, the rows are.
+	ctrl(tcell.KeyRight)
+	if width(left) != 10 {
+		t.Errorf("Ctrl-Right resized a flex which is not resizable: width %d", width(left))
+	}
+	ctrl(tcell.KeyDown)
+	if height(columns) != 6 || height(bottom) != 4 {
+		t.Errorf("Ctrl-Down resized the rows to %d and %d, want 6 and 4", height(columns), height(bottom))
+	}
+	if len(proportions) != 2 || proportions[0] != 6 || proportions[1] != 4 {
+		t.Errorf("resized handler received %v, want [6 4]", proportions)
+	}
+
+	// The innermost resizable flex handles the keys.
+	columns.SetResizable(true)
+	ctrl(tcell.KeyRight)
+	if width(left) != 11 || height(columns) != 6 {
+		t.Errorf("Ctrl-Right resized the columns to %d (rows %d), want 11 (6)", width(left), height(columns))
+	}
+
+	// The last item moves its leading boundary.
+	app.SetFocus(bottom)
+	ctrl(tcell.KeyUp)
+	if height(columns) != 5 {
+		t.Errorf("Ctrl-Up on the last row resized the first row to %d, want 5", height(columns))
+	}
+
+	// Other keys reach the focused primitive.
+	columns.SetResizable(false)
+	rows.SetResizable(false)
+	app.SetFocus(right)
+	app.handleKey(tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModNone))
+	ctrl(tcell.KeyDown)
+	if right.GetText() != "x" || height(columns) != 5 {
+		t.Errorf("unexpected text %q and height %d", right.GetText(), height(columns))
+	}
+}
@@ -0,0 +1,226 @@
+package tview
+
+import (
+	"github.com/gdamore/tcell"
+)
+
+// flowItem holds layout options for one item of a FlowLayout.
+type flowItem struct {
+	Item          Primitive // The item to be positioned. May be nil for an empty item.
+	Width, Height int       // The item's size.
+	Focus         bool      // Whether or not this item attracts the layout's focus.
+
+	visible bool // Whether or not this item was visible the last time the layout was drawn.
+}
+
+// FlowLayout is a container which arranges its items from left to right and
+// continues on the next row when there is no more space in the current row,
+// like words in a paragraph. This is useful for toolbars or lists of tags.
+// The layout is recalculated whenever the container is drawn, so the items
+// reflow automatically when the container is resized.
+//
+// Each item has a fixed size. The height of a row is the height of its highest
+// item. Items which do not fit vertically are not drawn.
+type FlowLayout struct {
+	*Box
+
+	// The items to be positioned.
+	items []*flowItem
+
+	// The number of empty cells between items in a row and between rows.
+	horizontalGap, verticalGap int
+
+	// The alignment of the items within a row.
+	align int
+}
+
+// NewFlowLayout returns a new flow layout container with no items.
+func NewFlowLayout() *FlowLayout {
+	f := &FlowLayout{
+		Box:           NewBox(),
+		horizontalGap: 1,
+		align:         AlignLeft,
+	}
+	f.focus = f
+	return f
+}
+
+// SetGap sets the number of empty cells between neighboring items in a row
+// ("horizontal", 1 by default) and between rows ("vertical", 0 by default).
+func (f *FlowLayout) SetGap(horizontal, vertical int) *FlowLayout {
+	f.horizontalGap, f.verticalGap = horizontal, vertical
+	return f
+}
+
+// SetAlign sets the alignment of the items within each row. Must be one of
+// AlignLeft (the default), AlignCenter, or AlignRight.
+func (f *FlowLayout) SetAlign(align int) *FlowLayout {
+	f.align = align
+	return f
+}
+
+// AddItem adds a new item with the given size to the end of the layout. Items
+// which are wider than the container are made smaller.
+//
+// If "focus" is set to true, the item will receive focus when the flow layout
+// receives focus. If multiple items have the "focus" flag set to true, the
+// first one will receive focus.
+func (f *FlowLayout) AddItem(item Primitive, width, height int, focus bool) *FlowLayout {
+	f.items = append(f.items, &flowItem{Item: item, Width: width, Height: height, Focus: focus})
+	return f
+}
+
+// RemoveItem removes all items for the given primitive from the container,
+// keeping the order of the remaining items intact.
+func (f *FlowLayout) RemoveItem(p Primitive) *FlowLayout {
+	for index := len(f.items) - 1; index >= 0; index-- {
+		if f.items[index].Item == p {
+			f.items = append(f.items[:index], f.items[index+1:]...)
+		}
+	}
+	return f
+}
+
+// Clear removes all items from the container.
+func (f *FlowLayout) Clear() *FlowLayout {
+	f.items = nil
+	return f
+}
+
+// GetItemCount returns the number of items in this container.
+func (f *FlowLayout) GetItemCount() int {
+	return len(f.items)
+}
+
+// GetChildren returns the items of the flow layout in the order in which they
+// were added.
+func (f *FlowLayout) GetChildren() []Primitive {
+	var children []Primitive
+	for _, item := range f.items {
+		if item.Item != nil {
+			children = append(children, item.Item)
+		}
+	}
+	return children
+}
+
+// Draw draws this primitive onto the screen.
+func (f *FlowLayout) Draw(screen tcell.Screen) {
+	f.Box.Draw(screen)
+	x, y, width, height := f.GetInnerRect()
+
+	// Break the items into rows.
+	type row struct {
+		items         []*flowItem
+		width, height int
+	}
+	var (
+		rows    []row
+		current row
+	)
+	for _, item := range f.items {
+		item.visible = false
+		if item.Width <= 0 || item.Height <= 0 {
+			continue
+		}
+		itemWidth := item.Width
+		if itemWidth > width {
+			itemWidth = width
+		}
+		if len(current.items) > 0 && current.width+f.horizontalGap+itemWidth > width {
+			rows = append(rows, current)
+			current = row{}
+		}
+		if len(current.items) > 0 {
+			current.width += f.horizontalGap
+		}
+		current.items = append(current.items, item)
+		current.width += itemWidth
+		if item.Height > current.height {
+			current.height = item.Height
+		}
+	}
+	if len(current.items) > 0 {
+		rows = append(rows, current)
+	}
+
+	// Position and draw the items.
+	var focused Primitive
+	rowY := y
+	for _, r := range rows {
+		if rowY+r.height > y+height {
+			break
+		}
+		itemX := x
+		switch f.align {
+		case AlignCenter:
+			itemX += (width - r.width) / 2
+		case AlignRight:
+			itemX += width - r.width
+		}
+		for _, item := range r.items {
+			itemWidth := item.Width
+			if itemWidth > width {
+				itemWidth = width
+			}
+			item.visible = true
+			if item.Item != nil {
+				item.Item.SetRect(itemX, rowY, itemWidth, item.Height)
+				if item.Item.GetFocusable().HasFocus() {
+					focused = item.Item
+				} else {
+					item.Item.Draw(screen)
+				}
+			}
+			itemX += itemWidth + f.horizontalGap
+		}
+		rowY += r.height + f.verticalGap
+	}
+	if focused != nil {
+		focused.Draw(screen)
+	}
+}
+
+// Focus is called when this primitive receives focus.
+func (f *FlowLayout) Focus(delegate func(p Primitive)) {
+	for _, item := range f.items {
+		if item.Item != nil && item.Focus {
+			delegate(item.Item)
+			return
+		}
+	}
+	f.hasFocus = true
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (f *FlowLayout) HasFocus() bool {
+	for _, item := range f.items {
+		if item.Item != nil && item.Item.GetFocusable().HasFocus() {
+			return true
+		}
+	}
+	return f.hasFocus
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (f *FlowLayout) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return f.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if !f.InRect(event.Position()) {
+			return false, nil
+		}
+
+		// Pass mouse events along to the first visible child item that takes it.
+		for _, item := range f.items {
+			if !item.visible || item.Item == nil {
+				continue
+			}
+			if handler := mouseHandler(item.Item); handler != nil {
+				if consumed, capture = handler(action, event, setFocus); consumed {
+					return
+				}
+			}
+		}
+
+		return
+	})
+}
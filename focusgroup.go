@@ -0,0 +1,207 @@
+package tview
+
+import (
+	"github.com/gdamore/tcell"
+)
+
+// FocusGroup is a container which groups the focusable primitives contained in
+// another primitive (e.g. the fields of a dialog laid out with Flex or Grid)
+// for Tab navigation. While one of them has focus, Tab and Backtab move the
+// focus between them, in the order in which they appear in the primitive tree
+// (see WalkTree()). By default, the focus is trapped in the group: Tab on the
+// last primitive moves it back to the first one, until the group is removed
+// or hidden, e.g. when a dialog is dismissed. See SetTrap() to let the focus
+// leave the group.
+//
+// From the outside, the group is a single stop: navigating to it (see
+// Application.EnableTabNavigation()) focuses the primitive which last had
+// focus in the group, or the first one. Groups may be nested.
+//
+// Tab navigation is performed by the Application, before the key is passed on
+// to the focused primitive.
+type FocusGroup struct {
+	*Box
+
+	// The contained primitive.
+	item Primitive
+
+	// Whether or not the focus is kept in the group.
+	trap bool
+
+	// The primitive in the group which last had focus.
+	last Primitive
+}
+
+// NewFocusGroup returns a new focus group for the focusable primitives
+// contained in the given primitive.
+func NewFocusGroup(item Primitive) *FocusGroup {
+	g := &FocusGroup{
+		Box:  NewBox(),
+		item: item,
+		trap: true,
+	}
+	g.focus = g
+	return g
+}
+
+// SetItem sets the contained primitive.
+func (g *FocusGroup) SetItem(item Primitive) *FocusGroup {
+	g.item = item
+	g.last = nil
+	return g
+}
+
+// GetItem returns the contained primitive.
+func (g *FocusGroup) GetItem() Primitive {
+	return g.item
+}
+
+// SetTrap sets whether the focus is kept in the group (the default). If set
+// to false, Tab on the last primitive and Backtab on the first primitive of
+// the group move the focus to the next or previous stop outside of it, as
+// determined by an enclosing group or the application (see
+// Application.EnableTabNavigation()).
+func (g *FocusGroup) SetTrap(trap bool) *FocusGroup {
+	g.trap = trap
+	return g
+}
+
+// GetChildren returns the contained primitive.
+func (g *FocusGroup) GetChildren() []Primitive {
+	if g.item == nil {
+		return nil
+	}
+	return []Primitive{g.item}
+}
+
+// Draw draws this primitive onto the screen.
+func (g *FocusGroup) Draw(screen tcell.Screen) {
+	if g.border {
+		g.Box.Draw(screen)
+	} else {
+		g.markDrawn(screen)
+	}
+	if g.item == nil {
+		return
+	}
+	g.item.SetRect(g.GetInnerRect())
+	g.item.Draw(screen)
+}
+
+// Focus is called when this primitive receives focus.
+func (g *FocusGroup) Focus(delegate func(p Primitive)) {
+	if g.last != nil && g.item != nil && containsPrimitive(g.item, g.last) && !isDisabled(g.last) {
+		delegate(g.last)
+		return
+	}
+	if stops := focusStops(g); len(stops) > 0 {
+		g.last = stops[0]
+		delegate(stops[0])
+		return
+	}
+	if g.item != nil {
+		delegate(g.item)
+		return
+	}
+	g.hasFocus = true
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (g *FocusGroup) HasFocus() bool {
+	if g.item != nil {
+		return g.item.GetFocusable().HasFocus()
+	}
+	return g.hasFocus
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (g *FocusGroup) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return g.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if !g.InRect(event.Position()) || g.item == nil {
+			return false, nil
+		}
+		if handler := mouseHandler(g.item); handler != nil {
+			return handler(action, event, setFocus)
+		}
+		return
+	})
+}
+
+// focusStops returns the primitives contained in the given primitive which
+// are visited by Tab navigation, in tree order: focus groups (which are not
+// entered) and focusable primitives without children which were drawn the
+// last time the screen was drawn and are not disabled. Plain boxes, e.g. used
+// as spacers, are skipped.
+func focusStops(root Primitive) (stops []Primitive) {
+	state := drawnStateOf(root)
+	WalkTree(root, func(p, parent Primitive) bool {
+		if p == root {
+			return true
+		}
+		if isDisabled(p) {
+			return false
+		}
+		if group, ok := p.(*FocusGroup); ok {
+			if len(focusStops(group)) > 0 {
+				stops = append(stops, group)
+			}
+			return false
+		}
+		if container, ok := p.(Container); ok && len(container.GetChildren()) > 0 {
+			return true
+		}
+		if _, ok := p.(*Box); ok || p.InputHandler() == nil || !wasDrawn(p, state) {
+			return false
+		}
+		stops = append(stops, p)
+		return false
+	})
+	return
+}
+
+// nextFocusStop returns the stop in "root" (see focusStops()) after (or, if
+// "forward" is false, before) the one which is or contains the given
+// primitive. If it wrapped around to the first (or last) stop, "wrapped" is
+// true. If the primitive is not found, the first (or last) stop is returned.
+// If there are no stops, nil is returned.
+func nextFocusStop(root, focus Primitive, forward bool) (next Primitive, wrapped bool) {
+	stops := focusStops(root)
+	if len(stops) == 0 {
+		return nil, false
+	}
+	current := -1
+	for index, stop := range stops {
+		if stop == focus || containsPrimitive(stop, focus) {
+			current = index
+			break
+		}
+	}
+	if current < 0 {
+		if forward {
+			return stops[0], false
+		}
+		return stops[len(stops)-1], false
+	}
+	if forward {
+		current++
+	} else {
+		current--
+	}
+	if current >= len(stops) {
+		current, wrapped = 0, true
+	} else if current < 0 {
+		current, wrapped = len(stops)-1, true
+	}
+	return stops[current], wrapped
+}
+
+// focusGroupsOf returns the focus groups which contain the given primitive
+// in the tree below "root", outermost first.
+func focusGroupsOf(root, focus Primitive) (groups []*FocusGroup) {
+	for _, p := range primitivePath(root, focus) {
+		if group, ok := p.(*FocusGroup); ok && p != focus {
+			groups = append(groups, group)
+		}
+	}
+	return
+}
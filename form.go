@@ -2,6 +2,7 @@ package tview
 
 import (
 	"strings"
+	"time"
 
 	"github.com/gdamore/tcell"
 )
@@ -85,16 +86,17 @@ type Form struct {
 
 // NewForm returns a new form.
 func NewForm() *Form {
+	styles := Styles.forWidget("Form")
 	box := NewBox().SetBorderPadding(1, 1, 1, 1)
 
 	f := &Form{
 		Box:                   box,
 		itemPadding:           1,
-		labelColor:            Styles.SecondaryTextColor,
-		fieldBackgroundColor:  Styles.ContrastBackgroundColor,
-		fieldTextColor:        Styles.PrimaryTextColor,
-		buttonBackgroundColor: Styles.ContrastBackgroundColor,
-		buttonTextColor:       Styles.PrimaryTextColor,
+		labelColor:            styles.SecondaryTextColor,
+		fieldBackgroundColor:  styles.ContrastBackgroundColor,
+		fieldTextColor:        styles.PrimaryTextColor,
+		buttonBackgroundColor: styles.ContrastBackgroundColor,
+		buttonTextColor:       styles.PrimaryTextColor,
 	}
 
 	f.focus = f
@@ -213,6 +215,17 @@ func (f *Form) AddCheckbox(label string, checked bool, changed func(checked bool
 	return f
 }
 
+// AddDateField adds a date field to the form. It has a label, an initial date
+// (which may be the zero value for no date), and an (optional) callback
+// function which is invoked when the user selected a date.
+func (f *Form) AddDateField(label string, date time.Time, changed func(date time.Time)) *Form {
+	f.items = append(f.items, NewDateField().
+		SetLabel(label).
+		SetDate(date).
+		SetChangedFunc(changed))
+	return f
+}
+
 // AddButton adds a new button to the form. The "selected" function is called
 // when the user selects this button. It may be nil.
 func (f *Form) AddButton(label string, selected func()) *Form {
@@ -253,6 +266,28 @@ func (f *Form) SetCancelFunc(callback func()) *Form {
 	return f
 }
 
+// GetChildren returns the form items followed by the buttons.
+func (f *Form) GetChildren() []Primitive {
+	children := make([]Primitive, 0, len(f.items)+len(f.buttons))
+	for _, item := range f.items {
+		children = append(children, item)
+	}
+	for _, button := range f.buttons {
+		children = append(children, button)
+	}
+	return children
+}
+
+// applyTheme is called when the application's theme changes.
+func (f *Form) applyTheme(from, to *Theme) {
+	f.Box.applyTheme(from, to)
+	themeColor(&f.labelColor, from.SecondaryTextColor, to.SecondaryTextColor)
+	themeColor(&f.fieldBackgroundColor, from.ContrastBackgroundColor, to.ContrastBackgroundColor)
+	themeColor(&f.fieldTextColor, from.PrimaryTextColor, to.PrimaryTextColor)
+	themeColor(&f.buttonBackgroundColor, from.ContrastBackgroundColor, to.ContrastBackgroundColor)
+	themeColor(&f.buttonTextColor, from.PrimaryTextColor, to.PrimaryTextColor)
+}
+
 // Draw draws this primitive onto the screen.
 func (f *Form) Draw(screen tcell.Screen) {
 	f.Box.Draw(screen)
@@ -336,7 +371,7 @@ func (f *Form) Draw(screen tcell.Screen) {
 	buttonWidths := make([]int, len(f.buttons))
 	buttonsWidth := 0
 	for index, button := range f.buttons {
-		w := StringWidth(button.GetLabel()) + 4
+		w, _ := button.GetPreferredSize()
 		buttonWidths[index] = w
 		buttonsWidth += w + 1
 	}
@@ -395,20 +430,22 @@ func (f *Form) Focus(delegate func(p Primitive)) {
 		return
 	}
 
-	// Hand on the focus to one of our child elements.
+	// Hand on the focus to one of our child elements, skipping disabled ones.
 	if f.focusedElement < 0 || f.focusedElement >= len(f.items)+len(f.buttons) {
 		f.focusedElement = 0
 	}
+	f.focusedElement = f.enabledElement(f.focusedElement, 1)
+	if f.focusedElement < 0 {
+		f.focusedElement = 0
+		return // All elements are disabled.
+	}
 	handler := func(key tcell.Key) {
 		switch key {
 		case tcell.KeyTab, tcell.KeyEnter:
 			f.focusedElement++
 			f.Focus(delegate)
 		case tcell.KeyBacktab:
-			f.focusedElement--
-			if f.focusedElement < 0 {
-				f.focusedElement = len(f.items) + len(f.buttons) - 1
-			}
+			f.focusedElement = f.enabledElement(f.focusedElement-1, -1)
 			f.Focus(delegate)
 		case tcell.KeyEscape:
 			if f.cancel != nil {
@@ -433,6 +470,28 @@ func (f *Form) Focus(delegate func(p Primitive)) {
 	}
 }
 
+// enabledElement returns the index of the first form element (items followed
+// by buttons) which is not disabled, starting at the given index and moving in
+// the direction of "step" (1 or -1), wrapping around at either end. If all
+// elements are disabled, -1 is returned.
+func (f *Form) enabledElement(index, step int) int {
+	count := len(f.items) + len(f.buttons)
+	for tries := 0; tries < count; tries++ {
+		index = (index%count + count) % count
+		var element Primitive
+		if index < len(f.items) {
+			element = f.items[index]
+		} else {
+			element = f.buttons[index-len(f.items)]
+		}
+		if !isDisabled(element) {
+			return index
+		}
+		index += step
+	}
+	return -1
+}
+
 // HasFocus returns whether or not this primitive has focus.
 func (f *Form) HasFocus() bool {
 	for _, item := range f.items {
@@ -447,3 +506,32 @@ func (f *Form) HasFocus() bool {
 	}
 	return false
 }
+
+// MouseHandler returns the mouse handler for this primitive.
+func (f *Form) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return f.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if !f.InRect(event.Position()) {
+			return false, nil
+		}
+
+		// Pass mouse events along to the first item or button that takes it.
+		for _, item := range f.items {
+			if handler := mouseHandler(item); handler != nil {
+				consumed, capture = handler(action, event, setFocus)
+				if consumed {
+					return
+				}
+			}
+		}
+		for _, button := range f.buttons {
+			if handler := mouseHandler(button); handler != nil {
+				consumed, capture = handler(action, event, setFocus)
+				if consumed {
+					return
+				}
+			}
+		}
+
+		return
+	})
+}
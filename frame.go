@@ -10,6 +10,13 @@ type frameText struct {
 	Header bool        // true = place in header, false = place in footer.
 	Align  int         // One of the Align constants.
 	Color  tcell.Color // The text color.
+
+	// Lines with a lower priority are hidden first when there is not enough
+	// space.
+	Priority int
+
+	// Whether the line was shown the last time the frame was drawn.
+	visible bool
 }
 
 // Frame is a wrapper which adds a border around another primitive. The top area
@@ -27,6 +34,10 @@ type Frame struct {
 
 	// Border spacing.
 	top, bottom, header, footer, left, right int
+
+	// The number of rows which the contained primitive keeps at least. Text
+	// lines are hidden to make room for it.
+	minHeight int
 }
 
 // NewFrame returns a new frame around the given primitive. The primitive's
@@ -43,6 +54,7 @@ func NewFrame(primitive Primitive) *Frame {
 		footer:    1,
 		left:      1,
 		right:     1,
+		minHeight: 1,
 	}
 
 	f.focus = f
@@ -56,6 +68,10 @@ func NewFrame(primitive Primitive) *Frame {
 // the Align constants. Rows in the header are printed top to bottom, rows in
 // the footer are printed bottom to top. Note that long text can overlap as
 // different alignments will be placed on the same row.
+//
+// The text may contain color tags. Region tags are removed so the same text
+// can be used in a TextView. Tags do not count towards the width of the text
+// when it is aligned.
 func (f *Frame) AddText(text string, header bool, align int, color tcell.Color) *Frame {
 	f.text = append(f.text, &frameText{
 		Text:   text,
@@ -66,12 +82,107 @@ func (f *Frame) AddText(text string, header bool, align int, color tcell.Color)
 	return f
 }
 
+// SetHeaderText replaces the header line with the given index (starting at 0,
+// in the order in which header lines were added). If there is no such line, a
+// new one is added. This allows the header to be updated, e.g. with a clock,
+// without clearing the frame. See AddText() for the other parameters.
+func (f *Frame) SetHeaderText(index int, text string, align int, color tcell.Color) *Frame {
+	return f.setText(true, index, text, align, color)
+}
+
+// SetFooterText replaces the footer line with the given index (starting at 0,
+// in the order in which footer lines were added). If there is no such line, a
+// new one is added. See SetHeaderText() and AddText() for details.
+func (f *Frame) SetFooterText(index int, text string, align int, color tcell.Color) *Frame {
+	return f.setText(false, index, text, align, color)
+}
+
+// setText replaces the header or footer line with the given index or adds a
+// new one.
+func (f *Frame) setText(header bool, index int, text string, align int, color tcell.Color) *Frame {
+	if t := f.getText(header, index); t != nil {
+		t.Text, t.Align, t.Color = text, align, color
+		return f
+	}
+	return f.AddText(text, header, align, color)
+}
+
+// SetTextPriority sets the priority of the header line (if "header" is true)
+// or footer line with the given index (see SetHeaderText()). When the frame is
+// too small to show all lines and the contained primitive at its minimum
+// height (see SetMinPrimitiveHeight()), lines are hidden, starting with the
+// lowest priority. Among lines of the same priority, footer lines are hidden
+// before header lines and lines further away from the border first. The
+// default priority is 0.
+func (f *Frame) SetTextPriority(header bool, index, priority int) *Frame {
+	if t := f.getText(header, index); t != nil {
+		t.Priority = priority
+	}
+	return f
+}
+
+// SetMinPrimitiveHeight sets the number of rows which the contained primitive
+// keeps at least when there is not enough space. Header and footer lines are
+// hidden to make room for it (see SetTextPriority()). The default is 1. If set
+// to 0, the primitive is hidden before any text is.
+func (f *Frame) SetMinPrimitiveHeight(height int) *Frame {
+	f.minHeight = height
+	return f
+}
+
+// IsTextVisible returns whether the header line (if "header" is true) or
+// footer line with the given index (see SetHeaderText()) was shown the last
+// time the frame was drawn. Lines may be hidden when there is not enough
+// space.
+func (f *Frame) IsTextVisible(header bool, index int) bool {
+	t := f.getText(header, index)
+	return t != nil && t.visible
+}
+
+// getText returns the header or footer line with the given index or nil if
+// there is no such line.
+func (f *Frame) getText(header bool, index int) *frameText {
+	var count int
+	for _, t := range f.text {
+		if t.Header != header {
+			continue
+		}
+		if count == index {
+			return t
+		}
+		count++
+	}
+	return nil
+}
+
 // Clear removes all text from the frame.
 func (f *Frame) Clear() *Frame {
 	f.text = nil
 	return f
 }
 
+// ClearHeaders removes all header text from the frame.
+func (f *Frame) ClearHeaders() *Frame {
+	return f.removeText(true)
+}
+
+// ClearFooters removes all footer text from the frame.
+func (f *Frame) ClearFooters() *Frame {
+	return f.removeText(false)
+}
+
+// removeText removes all header or all footer text.
+func (f *Frame) removeText(header bool) *Frame {
+	text := f.text[:0]
+	for _, t := range f.text {
+		if t.Header != header {
+			text = append(text, t)
+		}
+	}
+	f.text = text
+	return f
+}
+
 // SetBorders sets the width of the frame borders as well as "header" and
 // "footer", the vertical space between the header and footer text and the
 // contained primitive (does not apply if there is no text).
@@ -80,6 +191,14 @@ func (f *Frame) SetBorders(top, bottom, header, footer, left, right int) *Frame
 	return f
 }
 
+// GetChildren returns the primitive contained in the frame.
+func (f *Frame) GetChildren() []Primitive {
+	if f.primitive == nil {
+		return nil
+	}
+	return []Primitive{f.primitive}
+}
+
 // Draw draws this primitive onto the screen.
 func (f *Frame) Draw(screen tcell.Screen) {
 	f.Box.Draw(screen)
@@ -91,21 +210,50 @@ func (f *Frame) Draw(screen tcell.Screen) {
 	top += f.top
 	bottom -= f.bottom
 	width -= f.left + f.right
+	for _, text := range f.text {
+		text.visible = false
+	}
 	if width <= 0 || top >= bottom {
 		return // No space left.
 	}
 
+	// Hide text until the primitive fits.
+	for _, text := range f.text {
+		text.visible = true
+	}
+	for f.requiredHeight() > bottom-top+1 {
+		var hide *frameText
+		for index := len(f.text) - 1; index >= 0; index-- {
+			text := f.text[index]
+			if !text.visible {
+				continue
+			}
+			if hide == nil || text.Priority < hide.Priority || text.Priority == hide.Priority && !text.Header && hide.Header {
+				hide = text
+			}
+		}
+		if hide == nil {
+			break
+		}
+		hide.visible = false
+	}
+
 	// Draw text.
 	var rows [6]int // top-left, top-center, top-right, bottom-left, bottom-center, bottom-right.
 	topMax := top
 	bottomMin := bottom
 	for _, text := range f.text {
+		if !text.visible {
+			continue
+		}
+
 		// Where do we place this text?
 		var y int
 		if text.Header {
 			y = top + rows[text.Align]
 			rows[text.Align]++
 			if y >= bottomMin {
+				text.visible = false
 				continue
 			}
 			if y+1 > topMax {
@@ -115,6 +263,7 @@ func (f *Frame) Draw(screen tcell.Screen) {
 			y = bottom - rows[3+text.Align]
 			rows[3+text.Align]++
 			if y <= topMax {
+				text.visible = false
 				continue
 			}
 			if y-1 < bottomMin {
@@ -123,7 +272,7 @@ func (f *Frame) Draw(screen tcell.Screen) {
 		}
 
 		// Draw text.
-		Print(screen, text.Text, x, y, width, text.Align, text.Color)
+		Print(screen, regionPattern.ReplaceAllString(text.Text, ""), x, y, width, text.Align, text.Color)
 	}
 
 	// Set the size of the contained primitive.
@@ -139,7 +288,40 @@ func (f *Frame) Draw(screen tcell.Screen) {
 	f.primitive.SetRect(x, top, width, bottom+1-top)
 
 	// Finally, draw the contained primitive.
-	f.primitive.Draw(screen)
+	drawPrimitive(screen, f.primitive)
+}
+
+// requiredHeight returns the number of rows needed between the top and bottom
+// borders to show the visible text lines, the spacing around them, and the
+// contained primitive at its minimum height.
+func (f *Frame) requiredHeight() int {
+	var rows [6]int
+	for _, text := range f.text {
+		if !text.visible {
+			continue
+		}
+		if text.Header {
+			rows[text.Align]++
+		} else {
+			rows[3+text.Align]++
+		}
+	}
+	header, footer := rows[0], rows[3]
+	for align := 1; align < 3; align++ {
+		if rows[align] > header {
+			header = rows[align]
+		}
+		if rows[3+align] > footer {
+			footer = rows[3+align]
+		}
+	}
+	if header > 0 {
+		header += f.header
+	}
+	if footer > 0 {
+		footer += f.footer
+	}
+	return header + footer + f.minHeight
 }
 
 // Focus is called when this primitive receives focus.
@@ -155,3 +337,19 @@ func (f *Frame) HasFocus() bool {
 	}
 	return false
 }
+
+// MouseHandler returns the mouse handler for this primitive.
+func (f *Frame) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return f.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if !f.InRect(event.Position()) {
+			return false, nil
+		}
+
+		// Pass mouse events on to the contained primitive.
+		if handler := mouseHandler(f.primitive); handler != nil {
+			return handler(action, event, setFocus)
+		}
+
+		return
+	})
+}
@@ -0,0 +1,85 @@
+package tview
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/gdamore/tcell"
+	colorful "github.com/lucasb-eyer/go-colorful"
+)
+
+// Gradient returns the given text with color tags inserted such that the text
+// color changes gradually from the "from" color at the first character to the
+// "to" color at the last character. The result can be used wherever color tags
+// are accepted, e.g. in box titles, text views, or table cells. For example, a
+// progress bar may be drawn like this:
+//
+//   bar := tview.Gradient(strings.Repeat("█", width), tcell.ColorGreen, tcell.ColorRed)
+//
+// The colors are interpolated in the CIE L*a*b* color space which results in
+// even transitions. Both colors must have RGB values (i.e. not be
+// tcell.ColorDefault), otherwise the text is returned unchanged. Tags contained
+// in the text are not interpreted but displayed as they are.
+func Gradient(text string, from, to tcell.Color) string {
+	r1, g1, b1 := from.RGB()
+	r2, g2, b2 := to.RGB()
+	if r1 < 0 || r2 < 0 {
+		return text
+	}
+	start := colorful.Color{R: float64(r1) / 255, G: float64(g1) / 255, B: float64(b1) / 255}
+	end := colorful.Color{R: float64(r2) / 255, G: float64(g2) / 255, B: float64(b2) / 255}
+	return colorRunes(text, func(t float64) colorful.Color {
+		return start.BlendLab(end, t)
+	})
+}
+
+// Rainbow returns the given text with color tags inserted such that the text
+// runs through all hues, from red to violet. See Gradient() for details.
+func Rainbow(text string) string {
+	return colorRunes(text, func(t float64) colorful.Color {
+		return colorful.Hsv(t*300, 1, 1)
+	})
+}
+
+// colorRunes inserts a color tag in front of every character of the given text
+// whose color differs from the previous character's color. The colors are
+// determined by the provided function which receives the relative position of
+// the character in the text, from 0 (first character) to 1 (last character).
+// Zero-width characters (e.g. combining characters) keep the color of the
+// preceding character. Square brackets in the text are never turned into tags.
+func colorRunes(text string, color func(t float64) colorful.Color) string {
+	// Count the characters which receive a color.
+	var count int
+	for _, ch := range text {
+		if RuneWidth(ch) > 0 {
+			count++
+		}
+	}
+
+	var (
+		buffer   bytes.Buffer
+		index    int
+		previous string
+		lastRune rune
+	)
+	for _, ch := range text {
+		if RuneWidth(ch) > 0 {
+			var t float64
+			if count > 1 {
+				t = float64(index) / float64(count-1)
+			}
+			r, g, b := color(t).Clamped().RGB255()
+			tag := fmt.Sprintf("[#%02x%02x%02x]", r, g, b)
+			if tag != previous || lastRune == '[' {
+				// A tag after '[' also prevents the text from forming tags.
+				buffer.WriteString(tag)
+				previous = tag
+			}
+			index++
+		}
+		buffer.WriteRune(ch)
+		lastRune = ch
+	}
+
+	return buffer.String()
+}
@@ -41,6 +41,10 @@ type Grid struct {
 	// The minimum sizes for rows and columns.
 	minWidth, minHeight int
 
+	// Minimum sizes for individual rows and columns. See
+	// SetRowMinSizes()/SetColumnMinSizes() for details.
+	rowMinSizes, columnMinSizes []int
+
 	// The size of the gaps between neighboring primitives. This is automatically
 	// set to 1 if borders is true.
 	gapRows, gapColumns int
@@ -60,9 +64,10 @@ type Grid struct {
 
 // NewGrid returns a new grid-based layout container with no initial primitives.
 func NewGrid() *Grid {
+	styles := Styles.forWidget("Grid")
 	g := &Grid{
 		Box:          NewBox(),
-		bordersColor: Styles.GraphicsColor,
+		bordersColor: styles.GraphicsColor,
 	}
 	g.focus = g
 	return g
@@ -99,6 +104,7 @@ func NewGrid() *Grid {
 // of 125 cells, 25 cells wider than the available grid width.
 func (g *Grid) SetRows(rows ...int) *Grid {
 	g.rows = rows
+	g.MarkDirty()
 	return g
 }
 
@@ -110,6 +116,7 @@ func (g *Grid) SetRows(rows ...int) *Grid {
 // the height of the topmost column.
 func (g *Grid) SetColumns(columns ...int) *Grid {
 	g.columns = columns
+	g.MarkDirty()
 	return g
 }
 
@@ -128,7 +135,9 @@ func (g *Grid) SetSize(numRows, numColumns, rowSize, columnSize int) *Grid {
 }
 
 // SetMinSize sets an absolute minimum width for rows and an absolute minimum
-// height for columns. Panics if negative values are provided.
+// height for columns. Panics if negative values are provided. Minimum sizes for
+// individual rows and columns can be set with SetRowMinSizes() and
+// SetColumnMinSizes().
 func (g *Grid) SetMinSize(row, column int) *Grid {
 	if row < 0 || column < 0 {
 		panic("Invalid minimum row/column size")
@@ -137,6 +146,40 @@ func (g *Grid) SetMinSize(row, column int) *Grid {
 	return g
 }
 
+// SetRowMinSizes sets minimum heights for individual rows, starting with the
+// topmost row. A value of 0 means that only the minimum set with SetMinSize()
+// applies. Where both are set, the larger one is observed. Example:
+//
+//   grid.SetRows(3, -1, -1).SetRowMinSizes(0, 10, 5)
+func (g *Grid) SetRowMinSizes(sizes ...int) *Grid {
+	g.rowMinSizes = sizes
+	return g
+}
+
+// SetColumnMinSizes sets minimum widths for individual columns, starting with
+// the leftmost column. These values behave the same as the row values provided
+// with SetRowMinSizes(), see there for details.
+func (g *Grid) SetColumnMinSizes(sizes ...int) *Grid {
+	g.columnMinSizes = sizes
+	return g
+}
+
+// minRowHeight returns the minimum height of the row with the given index.
+func (g *Grid) minRowHeight(index int) int {
+	if index < len(g.rowMinSizes) && g.rowMinSizes[index] > g.minHeight {
+		return g.rowMinSizes[index]
+	}
+	return g.minHeight
+}
+
+// minColumnWidth returns the minimum width of the column with the given index.
+func (g *Grid) minColumnWidth(index int) int {
+	if index < len(g.columnMinSizes) && g.columnMinSizes[index] > g.minWidth {
+		return g.columnMinSizes[index]
+	}
+	return g.minWidth
+}
+
 // SetGap sets the size of the gaps between neighboring primitives on the grid.
 // If borders are drawn (see SetBorders()), these values are ignored and a gap
 // of 1 is assumed. Panics if negative values are provided.
@@ -199,6 +242,7 @@ func (g *Grid) AddItem(p Primitive, row, column, height, width, minGridHeight, m
 		MinGridWidth:  minGridWidth,
 		Focus:         focus,
 	})
+	g.MarkDirty()
 	return g
 }
 
@@ -210,12 +254,14 @@ func (g *Grid) RemoveItem(p Primitive) *Grid {
 			g.items = append(g.items[:index], g.items[index+1:]...)
 		}
 	}
+	g.MarkDirty()
 	return g
 }
 
 // Clear removes all items from the grid.
 func (g *Grid) Clear() *Grid {
 	g.items = nil
+	g.MarkDirty()
 	return g
 }
 
@@ -296,6 +342,25 @@ func (g *Grid) InputHandler() func(event *tcell.EventKey, setFocus func(p Primit
 	})
 }
 
+// GetChildren returns the primitives of the grid, each primitive only once.
+func (g *Grid) GetChildren() []Primitive {
+	var children []Primitive
+	seen := make(map[Primitive]bool)
+	for _, item := range g.items {
+		if item.Item != nil && !seen[item.Item] {
+			seen[item.Item] = true
+			children = append(children, item.Item)
+		}
+	}
+	return children
+}
+
+// applyTheme is called when the application's theme changes.
+func (g *Grid) applyTheme(from, to *Theme) {
+	g.Box.applyTheme(from, to)
+	themeColor(&g.bordersColor, from.GraphicsColor, to.GraphicsColor)
+}
+
 // Draw draws this primitive onto the screen.
 func (g *Grid) Draw(screen tcell.Screen) {
 	g.Box.Draw(screen)
@@ -345,8 +410,8 @@ func (g *Grid) Draw(screen tcell.Screen) {
 	proportionalHeight := 0
 	for index, row := range g.rows {
 		if row > 0 {
-			if row < g.minHeight {
-				row = g.minHeight
+			if minimum := g.minRowHeight(index); row < minimum {
+				row = minimum
 			}
 			remainingHeight -= row
 			rowHeight[index] = row
@@ -358,8 +423,8 @@ func (g *Grid) Draw(screen tcell.Screen) {
 	}
 	for index, column := range g.columns {
 		if column > 0 {
-			if column < g.minWidth {
-				column = g.minWidth
+			if minimum := g.minColumnWidth(index); column < minimum {
+				column = minimum
 			}
 			remainingWidth -= column
 			columnWidth[index] = column
@@ -392,8 +457,8 @@ func (g *Grid) Draw(screen tcell.Screen) {
 			row = g.rows[index]
 		}
 		if row > 0 {
-			if row < g.minHeight {
-				row = g.minHeight
+			if minimum := g.minRowHeight(index); row < minimum {
+				row = minimum
 			}
 			gridHeight += row
 			continue // Not proportional. We already know the width.
@@ -405,8 +470,8 @@ func (g *Grid) Draw(screen tcell.Screen) {
 		rowAbs := row * remainingHeight / proportionalHeight
 		remainingHeight -= rowAbs
 		proportionalHeight -= row
-		if rowAbs < g.minHeight {
-			rowAbs = g.minHeight
+		if minimum := g.minRowHeight(index); rowAbs < minimum {
+			rowAbs = minimum
 		}
 		rowHeight[index] = rowAbs
 		gridHeight += rowAbs
@@ -417,8 +482,8 @@ func (g *Grid) Draw(screen tcell.Screen) {
 			column = g.columns[index]
 		}
 		if column > 0 {
-			if column < g.minWidth {
-				column = g.minWidth
+			if minimum := g.minColumnWidth(index); column < minimum {
+				column = minimum
 			}
 			gridWidth += column
 			continue // Not proportional. We already know the height.
@@ -430,8 +495,8 @@ func (g *Grid) Draw(screen tcell.Screen) {
 		columnAbs := column * remainingWidth / proportionalWidth
 		remainingWidth -= columnAbs
 		proportionalWidth -= column
-		if columnAbs < g.minWidth {
-			columnAbs = g.minWidth
+		if minimum := g.minColumnWidth(index); columnAbs < minimum {
+			columnAbs = minimum
 		}
 		columnWidth[index] = columnAbs
 		gridWidth += columnAbs
@@ -570,9 +635,9 @@ func (g *Grid) Draw(screen tcell.Screen) {
 
 		// Draw primitive.
 		if item == focus {
-			defer primitive.Draw(screen)
+			defer drawPrimitive(screen, primitive)
 		} else {
-			primitive.Draw(screen)
+			drawPrimitive(screen, primitive)
 		}
 
 		// Draw border around primitive.
@@ -622,3 +687,27 @@ func (g *Grid) Draw(screen tcell.Screen) {
 		}
 	}
 }
+
+// MouseHandler returns the mouse handler for this primitive.
+func (g *Grid) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return g.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if !g.InRect(event.Position()) {
+			return false, nil
+		}
+
+		// Pass mouse events along to the first visible child item that takes it.
+		for _, item := range g.items {
+			if !item.visible || item.Item == nil {
+				continue
+			}
+			if handler := mouseHandler(item.Item); handler != nil {
+				consumed, capture = handler(action, event, setFocus)
+				if consumed {
+					return
+				}
+			}
+		}
+
+		return
+	})
+}
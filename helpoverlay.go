@@ -0,0 +1,445 @@
+package tview
+
+import (
+	"github.com/gdamore/tcell"
+)
+
+// KeyBinding describes a key or key combination and what it does, for display
+// in a HelpOverlay.
+type KeyBinding struct {
+	// The name of the group this binding belongs to, e.g. "Navigation".
+	// Consecutive bindings with the same group are shown under a common
+	// heading. May be empty.
+	Group string
+
+	// The key or keys, as shown to the user, e.g. "Ctrl-S" or "j, Down".
+	Keys string
+
+	// A description of what the keys do.
+	Description string
+}
+
+// HelpOverlay is a wrapper around another primitive which can show a cheat
+// sheet of key bindings in a centered popup on top of it. The key bindings
+// are provided as a declarative list (see AddBindings()).
+//
+// The popup is opened and closed with Open() and Close() or by passing key
+// events to HandleKey(), e.g. from Application.SetInputCapture(), which
+// toggles the popup when the help key is pressed (F1 by default, see
+// SetToggleKey()). While the popup is open, it receives all key events and the
+// following keys are available:
+//
+//   - Up arrow, k: Scroll up by one line.
+//   - Down arrow, j: Scroll down by one line.
+//   - Page Up, Page Down: Scroll up or down by one page.
+//   - Home, g: Scroll to the top.
+//   - End, G: Scroll to the bottom.
+//   - Escape, Enter, q: Close the popup.
+type HelpOverlay struct {
+	*Box
+
+	// The contained primitive.
+	primitive Primitive
+
+	// The key bindings to be shown.
+	bindings []KeyBinding
+
+	// Whether or not the popup is open.
+	open bool
+
+	// The popup's title.
+	title string
+
+	// The key and rune which toggle the popup in HandleKey().
+	toggleKey  tcell.Key
+	toggleRune rune
+
+	// The index of the first line shown in the popup.
+	lineOffset int
+
+	// The number of lines visible in the popup the last time it was drawn.
+	pageSize int
+
+	// The total number of lines of the cheat sheet.
+	lineCount int
+
+	// The popup's colors.
+	popupBackgroundColor, borderColor, titleColor tcell.Color
+
+	// The colors of group headings, keys, and descriptions.
+	groupColor, keysColor, descriptionColor tcell.Color
+
+	// A function which sets the focus to a primitive.
+	setFocus func(p Primitive)
+}
+
+// NewHelpOverlay returns a new help overlay around the given primitive. The
+// primitive's size will be changed to fit within the help overlay's inner
+// rectangle.
+func NewHelpOverlay(primitive Primitive) *HelpOverlay {
+	styles := Styles.forWidget("HelpOverlay")
+	h := &HelpOverlay{
+		Box:                  NewBox(),
+		primitive:            primitive,
+		title:                "Help",
+		toggleKey:            tcell.KeyF1,
+		popupBackgroundColor: styles.ContrastBackgroundColor,
+		borderColor:          styles.BorderColor,
+		titleColor:           styles.TitleColor,
+		groupColor:           styles.TertiaryTextColor,
+		keysColor:            styles.SecondaryTextColor,
+		descriptionColor:     styles.PrimaryTextColor,
+	}
+	h.focus = h
+	return h
+}
+
+// AddBindings adds key bindings to the cheat sheet.
+func (h *HelpOverlay) AddBindings(bindings ...KeyBinding) *HelpOverlay {
+	h.bindings = append(h.bindings, bindings...)
+	return h
+}
+
+// AddBinding adds a single key binding to the cheat sheet.
+func (h *HelpOverlay) AddBinding(group, keys, description string) *HelpOverlay {
+	return h.AddBindings(KeyBinding{Group: group, Keys: keys, Description: description})
+}
+
+// GetBindings returns the key bindings of the cheat sheet.
+func (h *HelpOverlay) GetBindings() []KeyBinding {
+	return h.bindings
+}
+
+// ClearBindings removes all key bindings from the cheat sheet.
+func (h *HelpOverlay) ClearBindings() *HelpOverlay {
+	h.bindings = nil
+	h.lineOffset = 0
+	return h
+}
+
+// SetPopupTitle sets the title of the popup. The default is "Help".
+func (h *HelpOverlay) SetPopupTitle(title string) *HelpOverlay {
+	h.title = title
+	return h
+}
+
+// SetToggleKey sets the key which opens and closes the popup in HandleKey().
+// If "key" is tcell.KeyRune, the popup is toggled with the rune "ch", e.g. '?'.
+// The default is tcell.KeyF1.
+func (h *HelpOverlay) SetToggleKey(key tcell.Key, ch rune) *HelpOverlay {
+	h.toggleKey, h.toggleRune = key, ch
+	return h
+}
+
+// SetPopupBackgroundColor sets the background color of the popup.
+func (h *HelpOverlay) SetPopupBackgroundColor(color tcell.Color) *HelpOverlay {
+	h.popupBackgroundColor = color
+	return h
+}
+
+// SetPopupBorderColor sets the color of the popup's border.
+func (h *HelpOverlay) SetPopupBorderColor(color tcell.Color) *HelpOverlay {
+	h.borderColor = color
+	return h
+}
+
+// SetPopupTitleColor sets the color of the popup's title.
+func (h *HelpOverlay) SetPopupTitleColor(color tcell.Color) *HelpOverlay {
+	h.titleColor = color
+	return h
+}
+
+// SetGroupColor sets the color of the group headings.
+func (h *HelpOverlay) SetGroupColor(color tcell.Color) *HelpOverlay {
+	h.groupColor = color
+	return h
+}
+
+// SetKeysColor sets the color of the keys.
+func (h *HelpOverlay) SetKeysColor(color tcell.Color) *HelpOverlay {
+	h.keysColor = color
+	return h
+}
+
+// SetDescriptionColor sets the color of the key descriptions.
+func (h *HelpOverlay) SetDescriptionColor(color tcell.Color) *HelpOverlay {
+	h.descriptionColor = color
+	return h
+}
+
+// Open opens the popup. If the help overlay or its contained primitive has
+// focus, the focus moves to the popup.
+func (h *HelpOverlay) Open() *HelpOverlay {
+	if h.open {
+		return h
+	}
+	hasFocus := h.HasFocus()
+	h.open = true
+	h.lineOffset = 0
+	if hasFocus && h.setFocus != nil {
+		h.setFocus(h)
+	}
+	return h
+}
+
+// Close closes the popup. If it had focus, the focus is returned to the
+// contained primitive.
+func (h *HelpOverlay) Close() *HelpOverlay {
+	if !h.open {
+		return h
+	}
+	hasFocus := h.HasFocus()
+	h.open = false
+	if hasFocus && h.setFocus != nil {
+		h.setFocus(h)
+	}
+	return h
+}
+
+// IsOpen returns true if the popup is currently open.
+func (h *HelpOverlay) IsOpen() bool {
+	return h.open
+}
+
+// HandleKey opens the popup if it is closed or closes it if it is open when
+// the given key event corresponds to the toggle key (see SetToggleKey()). It
+// returns true if the event was handled. This is meant to be called from
+// Application.SetInputCapture().
+func (h *HelpOverlay) HandleKey(event *tcell.EventKey) bool {
+	if event.Key() != h.toggleKey || h.toggleKey == tcell.KeyRune && event.Rune() != h.toggleRune {
+		return false
+	}
+	if h.open {
+		h.Close()
+	} else {
+		h.Open()
+	}
+	return true
+}
+
+// GetChildren returns the primitive which the help overlay wraps.
+func (h *HelpOverlay) GetChildren() []Primitive {
+	if h.primitive == nil {
+		return nil
+	}
+	return []Primitive{h.primitive}
+}
+
+// applyTheme is called when the application's theme changes.
+func (h *HelpOverlay) applyTheme(from, to *Theme) {
+	h.Box.applyTheme(from, to)
+	themeColor(&h.popupBackgroundColor, from.ContrastBackgroundColor, to.ContrastBackgroundColor)
+	themeColor(&h.borderColor, from.BorderColor, to.BorderColor)
+	themeColor(&h.titleColor, from.TitleColor, to.TitleColor)
+	themeColor(&h.groupColor, from.TertiaryTextColor, to.TertiaryTextColor)
+	themeColor(&h.keysColor, from.SecondaryTextColor, to.SecondaryTextColor)
+	themeColor(&h.descriptionColor, from.PrimaryTextColor, to.PrimaryTextColor)
+}
+
+// Draw draws this primitive onto the screen.
+func (h *HelpOverlay) Draw(screen tcell.Screen) {
+	defer h.applyStyle(&h.descriptionColor)()
+	h.Box.Draw(screen)
+	x, y, width, height := h.GetInnerRect()
+	if h.primitive != nil {
+		h.primitive.SetRect(x, y, width, height)
+		h.primitive.Draw(screen)
+	}
+	if !h.open {
+		return
+	}
+
+	// Determine the lines and their widths.
+	type line struct {
+		group       string
+		binding     *KeyBinding
+		widthNeeded int
+	}
+	var (
+		lines     []line
+		group     string
+		keysWidth int
+	)
+	for index := range h.bindings {
+		binding := &h.bindings[index]
+		if index == 0 || binding.Group != group {
+			if index > 0 {
+				lines = append(lines, line{}) // Empty line between groups.
+			}
+			group = binding.Group
+			if group != "" {
+				lines = append(lines, line{group: group, widthNeeded: StringWidth(group)})
+			}
+		}
+		if width := StringWidth(binding.Keys); width > keysWidth {
+			keysWidth = width
+		}
+		lines = append(lines, line{binding: binding})
+	}
+	popupWidth := StringWidth(h.title) + 2
+	for index, l := range lines {
+		if l.binding != nil {
+			lines[index].widthNeeded = keysWidth + 2 + StringWidth(l.binding.Description)
+		}
+		if lines[index].widthNeeded > popupWidth {
+			popupWidth = lines[index].widthNeeded
+		}
+	}
+	h.lineCount = len(lines)
+
+	// Determine the popup's position and size.
+	popupWidth += 4 // Border and padding.
+	popupHeight := len(lines) + 2
+	if popupWidth > width {
+		popupWidth = width
+	}
+	if popupHeight > height {
+		popupHeight = height
+	}
+	if popupWidth < 4 || popupHeight < 3 {
+		return
+	}
+	popupX, popupY := x+(width-popupWidth)/2, y+(height-popupHeight)/2
+
+	// Draw the frame.
+	background := tcell.StyleDefault.Background(h.popupBackgroundColor)
+	border := background.Foreground(h.borderColor)
+	for row := popupY; row < popupY+popupHeight; row++ {
+		for column := popupX; column < popupX+popupWidth; column++ {
+			ch := ' '
+			switch {
+			case row == popupY && column == popupX:
+				ch = Styles.Borders.TopLeft
+			case row == popupY && column == popupX+popupWidth-1:
+				ch = Styles.Borders.TopRight
+			case row == popupY+popupHeight-1 && column == popupX:
+				ch = Styles.Borders.BottomLeft
+			case row == popupY+popupHeight-1 && column == popupX+popupWidth-1:
+				ch = Styles.Borders.BottomRight
+			case row == popupY || row == popupY+popupHeight-1:
+				ch = Styles.Borders.Horizontal
+			case column == popupX || column == popupX+popupWidth-1:
+				ch = Styles.Borders.Vertical
+			}
+			screen.SetContent(column, row, ch, nil, border)
+		}
+	}
+	Print(screen, h.title, popupX+1, popupY, popupWidth-2, AlignCenter, h.titleColor)
+
+	// Draw the lines.
+	h.pageSize = popupHeight - 2
+	if h.lineOffset > len(lines)-h.pageSize {
+		h.lineOffset = len(lines) - h.pageSize
+	}
+	if h.lineOffset < 0 {
+		h.lineOffset = 0
+	}
+	innerX, innerWidth := popupX+2, popupWidth-4
+	for row := 0; row < h.pageSize && h.lineOffset+row < len(lines); row++ {
+		l := lines[h.lineOffset+row]
+		lineY := popupY + 1 + row
+		if l.binding == nil {
+			Print(screen, l.group, innerX, lineY, innerWidth, AlignLeft, h.groupColor)
+			continue
+		}
+		Print(screen, l.binding.Keys, innerX, lineY, innerWidth, AlignLeft, h.keysColor)
+		if keysWidth+2 < innerWidth {
+			Print(screen, l.binding.Description, innerX+keysWidth+2, lineY, innerWidth-keysWidth-2, AlignLeft, h.descriptionColor)
+		}
+	}
+
+	// Indicate that there is more content.
+	if h.lineOffset > 0 {
+		screen.SetContent(popupX+popupWidth-1, popupY+1, '▲', nil, border)
+	}
+	if h.lineOffset+h.pageSize < len(lines) {
+		screen.SetContent(popupX+popupWidth-1, popupY+popupHeight-2, '▼', nil, border)
+	}
+}
+
+// Focus is called when this primitive receives focus.
+func (h *HelpOverlay) Focus(delegate func(p Primitive)) {
+	h.setFocus = delegate
+	if h.open || h.primitive == nil {
+		h.Box.Focus(delegate)
+		return
+	}
+	delegate(h.primitive)
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (h *HelpOverlay) HasFocus() bool {
+	if h.hasFocus {
+		return true
+	}
+	return h.primitive != nil && h.primitive.GetFocusable().HasFocus()
+}
+
+// InputHandler returns the handler for this primitive.
+func (h *HelpOverlay) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return h.wrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		if !h.open {
+			return
+		}
+		switch event.Key() {
+		case tcell.KeyUp:
+			h.lineOffset--
+		case tcell.KeyDown:
+			h.lineOffset++
+		case tcell.KeyPgUp:
+			h.lineOffset -= h.pageSize
+		case tcell.KeyPgDn:
+			h.lineOffset += h.pageSize
+		case tcell.KeyHome:
+			h.lineOffset = 0
+		case tcell.KeyEnd:
+			h.lineOffset = h.lineCount
+		case tcell.KeyEscape, tcell.KeyEnter:
+			h.Close()
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'k':
+				h.lineOffset--
+			case 'j':
+				h.lineOffset++
+			case 'g':
+				h.lineOffset = 0
+			case 'G':
+				h.lineOffset = h.lineCount
+			case 'q':
+				h.Close()
+			}
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (h *HelpOverlay) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return h.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		// While the popup is open, we capture all mouse events.
+		if h.open {
+			switch action {
+			case MouseScrollUp:
+				h.lineOffset--
+			case MouseScrollDown:
+				h.lineOffset++
+			case MouseLeftClick, MouseMiddleClick, MouseRightClick:
+				h.Close()
+			}
+			if h.open {
+				capture = h
+			}
+			return true, capture
+		}
+
+		if !h.InRect(event.Position()) {
+			return false, nil
+		}
+		if h.primitive != nil {
+			if handler := mouseHandler(h.primitive); handler != nil {
+				return handler(action, event, setFocus)
+			}
+		}
+		return true, nil
+	})
+}
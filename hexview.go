@@ -0,0 +1,544 @@
+package tview
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell"
+)
+
+// HexView is a primitive which shows binary data in the classic hex dump
+// layout: the offset of each row, the bytes as hexadecimal numbers, and the
+// bytes as ASCII characters (non-printable characters are shown as dots). A
+// cursor marks the current byte. A status line at the bottom shows the
+// cursor's offset and is used to type commands.
+//
+// If the hex view is editable (see SetEditable()), typing hexadecimal digits
+// overwrites the byte under the cursor, one nibble at a time. After the Insert
+// key was pressed, the ASCII column is edited instead, where typed characters
+// overwrite the byte under the cursor. The data slice is modified in place.
+//
+// The following keys are available when the hex view has focus:
+//
+//   - Arrow keys, h, j, k, l: Move the cursor.
+//   - Page Up, Page Down: Move the cursor by one page.
+//   - Home, End: Move the cursor to the start or end of the row.
+//   - g, G: Move the cursor to the first or last byte.
+//   - :offset: Move the cursor to the given offset. Prefix hexadecimal offsets
+//     with "0x".
+//   - /text: Search for the given text.
+//   - #hex: Search for the given bytes, e.g. "#de ad be ef".
+//   - n, N: Repeat the last search forward or backward.
+//   - Insert: Switch between editing hex digits and ASCII characters (if
+//     editable).
+//   - Escape, Tab, Backtab: Finish (see SetDoneFunc()).
+//
+// While a command is typed, Enter executes it and Escape cancels it.
+type HexView struct {
+	*Box
+
+	// The data.
+	data []byte
+
+	// The offset of the byte under the cursor.
+	cursor int
+
+	// Whether the low nibble of the byte under the cursor is edited next.
+	lowNibble bool
+
+	// Whether the ASCII column is edited instead of the hex digits.
+	editASCII bool
+
+	// The index of the first visible row.
+	rowOffset int
+
+	// The number of bytes per row. If 0, it is chosen to fit the width.
+	bytesPerRow int
+
+	// The number of bytes per row and rows visible the last time the view was
+	// drawn.
+	rowSize, pageSize int
+
+	// Whether or not the data can be edited.
+	editable bool
+
+	// The last search pattern.
+	search []byte
+
+	// The command currently typed (':', '/', '#') or 0 if there is none, and
+	// the text typed after it.
+	prompt rune
+	input  string
+
+	// A message shown in the status line until the next key is pressed.
+	message string
+
+	// The colors of offsets, hex digits, ASCII characters, and the status line.
+	offsetColor, hexColor, asciiColor, statusColor tcell.Color
+
+	// The colors of the cursor.
+	cursorTextColor, cursorBackgroundColor tcell.Color
+
+	// An optional function which is called when the user changed a byte.
+	changed func(offset int, value byte)
+
+	// An optional function which is called when the user leaves the hex view.
+	done func(tcell.Key)
+}
+
+// NewHexView returns a new, empty, read-only hex view.
+func NewHexView() *HexView {
+	styles := Styles.forWidget("HexView")
+	return &HexView{
+		Box:                   NewBox(),
+		offsetColor:           styles.TertiaryTextColor,
+		hexColor:              styles.PrimaryTextColor,
+		asciiColor:            styles.SecondaryTextColor,
+		statusColor:           styles.PrimaryTextColor,
+		cursorTextColor:       styles.PrimitiveBackgroundColor,
+		cursorBackgroundColor: styles.PrimaryTextColor,
+	}
+}
+
+// SetData sets the data shown by the hex view. If the hex view is editable,
+// the slice is modified in place. The cursor is moved to the first byte.
+func (h *HexView) SetData(data []byte) *HexView {
+	h.data = data
+	h.cursor, h.rowOffset, h.lowNibble = 0, 0, false
+	return h
+}
+
+// GetData returns the data shown by the hex view.
+func (h *HexView) GetData() []byte {
+	return h.data
+}
+
+// SetEditable sets whether or not the user can change the data.
+func (h *HexView) SetEditable(editable bool) *HexView {
+	h.editable = editable
+	return h
+}
+
+// SetBytesPerRow sets the number of bytes shown in each row. If 0 (the
+// default), a multiple of 8 is chosen which fits the available width.
+func (h *HexView) SetBytesPerRow(bytes int) *HexView {
+	h.bytesPerRow = bytes
+	return h
+}
+
+// SetCursor moves the cursor to the byte at the given offset.
+func (h *HexView) SetCursor(offset int) *HexView {
+	h.moveCursor(offset)
+	return h
+}
+
+// GetCursor returns the offset of the byte under the cursor.
+func (h *HexView) GetCursor() int {
+	return h.cursor
+}
+
+// SetOffsetColor sets the color of the offsets.
+func (h *HexView) SetOffsetColor(color tcell.Color) *HexView {
+	h.offsetColor = color
+	return h
+}
+
+// SetHexColor sets the color of the hex digits.
+func (h *HexView) SetHexColor(color tcell.Color) *HexView {
+	h.hexColor = color
+	return h
+}
+
+// SetASCIIColor sets the color of the ASCII characters.
+func (h *HexView) SetASCIIColor(color tcell.Color) *HexView {
+	h.asciiColor = color
+	return h
+}
+
+// SetStatusColor sets the color of the status line.
+func (h *HexView) SetStatusColor(color tcell.Color) *HexView {
+	h.statusColor = color
+	return h
+}
+
+// SetCursorColors sets the text and background color of the cursor.
+func (h *HexView) SetCursorColors(textColor, backgroundColor tcell.Color) *HexView {
+	h.cursorTextColor, h.cursorBackgroundColor = textColor, backgroundColor
+	return h
+}
+
+// SetChangedFunc sets a handler which is called when the user changed a byte.
+// It receives the byte's offset and its new value.
+func (h *HexView) SetChangedFunc(handler func(offset int, value byte)) *HexView {
+	h.changed = handler
+	return h
+}
+
+// SetDoneFunc sets a handler which is called when the user leaves the hex
+// view. The callback function is provided with the key that was pressed, which
+// is one of the following:
+//
+//   - KeyEscape: Leaving the hex view with no specific direction.
+//   - KeyTab: Move to the next field.
+//   - KeyBacktab: Move to the previous field.
+func (h *HexView) SetDoneFunc(handler func(key tcell.Key)) *HexView {
+	h.done = handler
+	return h
+}
+
+// Find searches for the given bytes, starting after the cursor (or before it
+// if "forward" is false) and wrapping around. If found, the cursor is moved to
+// the first byte of the match. Returns whether the bytes were found.
+func (h *HexView) Find(pattern []byte, forward bool) bool {
+	h.search = pattern
+	if len(pattern) == 0 || len(h.data) == 0 {
+		return false
+	}
+	var index int
+	if forward {
+		if index = bytes.Index(h.data[h.cursor+1:], pattern); index >= 0 {
+			index += h.cursor + 1
+		} else {
+			index = bytes.Index(h.data, pattern)
+		}
+	} else {
+		if index = bytes.LastIndex(h.data[:h.cursor], pattern); index < 0 {
+			index = bytes.LastIndex(h.data, pattern)
+		}
+	}
+	if index < 0 {
+		return false
+	}
+	h.moveCursor(index)
+	return true
+}
+
+// moveCursor moves the cursor to the given offset, clamped to the data.
+func (h *HexView) moveCursor(offset int) {
+	if offset >= len(h.data) {
+		offset = len(h.data) - 1
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if offset != h.cursor {
+		h.lowNibble = false
+	}
+	h.cursor = offset
+}
+
+// execute executes the command typed in the status line.
+func (h *HexView) execute() {
+	input := strings.TrimSpace(h.input)
+	switch h.prompt {
+	case ':':
+		offset, err := strconv.ParseInt(input, 0, 64)
+		if err != nil || offset < 0 {
+			h.message = "Invalid offset"
+			return
+		}
+		h.moveCursor(int(offset))
+	case '/', '#':
+		pattern := []byte(h.input)
+		if h.prompt == '#' {
+			var err error
+			pattern, err = hex.DecodeString(strings.Replace(input, " ", "", -1))
+			if err != nil {
+				h.message = "Invalid hex string"
+				return
+			}
+		}
+		if !h.Find(pattern, true) {
+			h.message = "Pattern not found"
+		}
+	}
+}
+
+// edit changes the byte under the cursor according to the typed rune. Returns
+// false if the rune cannot be used for editing.
+func (h *HexView) edit(r rune) bool {
+	if !h.editable || len(h.data) == 0 {
+		return false
+	}
+	value := h.data[h.cursor]
+	if h.editASCII {
+		if r < ' ' || r > '~' {
+			return false
+		}
+		value = byte(r)
+	} else {
+		digit, err := strconv.ParseUint(string(r), 16, 8)
+		if err != nil {
+			return false
+		}
+		if h.lowNibble {
+			value = value&0xf0 | byte(digit)
+		} else {
+			value = value&0x0f | byte(digit)<<4
+		}
+	}
+	h.data[h.cursor] = value
+	if h.changed != nil {
+		h.changed(h.cursor, value)
+	}
+
+	// Advance the cursor.
+	if !h.editASCII && !h.lowNibble {
+		h.lowNibble = true
+	} else if h.cursor < len(h.data)-1 {
+		h.moveCursor(h.cursor + 1)
+	} else {
+		h.lowNibble = false
+	}
+	return true
+}
+
+// applyTheme is called when the application's theme changes.
+func (h *HexView) applyTheme(from, to *Theme) {
+	h.Box.applyTheme(from, to)
+	themeColor(&h.offsetColor, from.TertiaryTextColor, to.TertiaryTextColor)
+	themeColor(&h.hexColor, from.PrimaryTextColor, to.PrimaryTextColor)
+	themeColor(&h.asciiColor, from.SecondaryTextColor, to.SecondaryTextColor)
+	themeColor(&h.statusColor, from.PrimaryTextColor, to.PrimaryTextColor)
+	themeColor(&h.cursorTextColor, from.PrimitiveBackgroundColor, to.PrimitiveBackgroundColor)
+	themeColor(&h.cursorBackgroundColor, from.PrimaryTextColor, to.PrimaryTextColor)
+}
+
+// Draw draws this primitive onto the screen.
+func (h *HexView) Draw(screen tcell.Screen) {
+	defer h.applyStyle(&h.hexColor)()
+	h.Box.Draw(screen)
+	x, y, width, height := h.GetInnerRect()
+	if width <= 0 || height <= 1 {
+		return
+	}
+	height--
+
+	// Determine the layout. A row consists of the offset, two spaces, three
+	// characters per byte plus one space per group of 8 bytes, one space, and
+	// one character per byte surrounded by bars.
+	offsetWidth := 8
+	for size := len(h.data) >> 32; size > 0; size >>= 4 {
+		offsetWidth++
+	}
+	rowWidth := func(bytes int) int {
+		return offsetWidth + 2 + bytes*3 + (bytes-1)/8 + 1 + bytes + 2
+	}
+	rowSize := h.bytesPerRow
+	if rowSize <= 0 {
+		rowSize = 8
+		for rowWidth(rowSize+8) <= width {
+			rowSize += 8
+		}
+	}
+	h.rowSize, h.pageSize = rowSize, height
+
+	// Make sure the cursor is visible.
+	rows := (len(h.data) + rowSize - 1) / rowSize
+	cursorRow := h.cursor / rowSize
+	if cursorRow < h.rowOffset {
+		h.rowOffset = cursorRow
+	} else if cursorRow >= h.rowOffset+height {
+		h.rowOffset = cursorRow - height + 1
+	}
+	if h.rowOffset > rows-height {
+		h.rowOffset = rows - height
+	}
+	if h.rowOffset < 0 {
+		h.rowOffset = 0
+	}
+
+	// Draw the rows.
+	style := func(color tcell.Color) tcell.Style {
+		return tcell.StyleDefault.Foreground(color).Background(h.backgroundColor)
+	}
+	cursorStyle := tcell.StyleDefault.Foreground(h.cursorTextColor).Background(h.cursorBackgroundColor)
+	dimCursorStyle := tcell.StyleDefault.Reverse(true)
+	for row := 0; row < height && h.rowOffset+row < rows; row++ {
+		start := (h.rowOffset + row) * rowSize
+		column := x
+		set := func(r rune, style tcell.Style) {
+			if column < x+width {
+				screen.SetContent(column, y+row, r, nil, style)
+			}
+			column++
+		}
+		for _, r := range fmt.Sprintf("%0*x  ", offsetWidth, start) {
+			set(r, style(h.offsetColor))
+		}
+
+		// Hex digits.
+		for index := 0; index < rowSize; index++ {
+			if index > 0 && index%8 == 0 {
+				set(' ', style(h.hexColor))
+			}
+			offset := start + index
+			if offset >= len(h.data) {
+				column += 3
+				continue
+			}
+			digits := fmt.Sprintf("%02x", h.data[offset])
+			for nibble, r := range digits {
+				digitStyle := style(h.hexColor)
+				if offset == h.cursor && h.hasFocus {
+					if h.editASCII {
+						digitStyle = dimCursorStyle
+					} else if !h.editable || h.lowNibble == (nibble == 1) {
+						digitStyle = cursorStyle
+					} else {
+						digitStyle = dimCursorStyle
+					}
+				}
+				set(r, digitStyle)
+			}
+			set(' ', style(h.hexColor))
+		}
+
+		// ASCII characters.
+		set(' ', style(h.asciiColor))
+		set('|', style(h.offsetColor))
+		for index := 0; index < rowSize && start+index < len(h.data); index++ {
+			offset := start + index
+			r := rune(h.data[offset])
+			if r < ' ' || r > '~' {
+				r = '.'
+			}
+			charStyle := style(h.asciiColor)
+			if offset == h.cursor && h.hasFocus {
+				if h.editASCII || !h.editable {
+					charStyle = cursorStyle
+				} else {
+					charStyle = dimCursorStyle
+				}
+			}
+			set(r, charStyle)
+		}
+		set('|', style(h.offsetColor))
+	}
+
+	// Draw the status line.
+	var status string
+	switch {
+	case h.prompt != 0:
+		status = string(h.prompt) + h.input + "_"
+	case h.message != "":
+		status = h.message
+	default:
+		status = fmt.Sprintf("Offset 0x%x (%d) of 0x%x bytes", h.cursor, h.cursor, len(h.data))
+		if h.editable && h.editASCII {
+			status += "  [ASCII]"
+		} else if h.editable {
+			status += "  [HEX]"
+		}
+	}
+	Print(screen, Escape(status), x, y+height, width, AlignLeft, h.statusColor)
+}
+
+// InputHandler returns the handler for this primitive.
+func (h *HexView) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return h.wrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		h.message = ""
+		key := event.Key()
+
+		// Typing a command.
+		if h.prompt != 0 {
+			switch key {
+			case tcell.KeyRune:
+				h.input += string(event.Rune())
+			case tcell.KeyBackspace, tcell.KeyBackspace2:
+				if h.input == "" {
+					h.prompt = 0
+				} else {
+					runes := []rune(h.input)
+					h.input = string(runes[:len(runes)-1])
+				}
+			case tcell.KeyEnter:
+				h.execute()
+				h.prompt = 0
+			case tcell.KeyEscape:
+				h.prompt = 0
+			}
+			return
+		}
+
+		rowSize := h.rowSize
+		if rowSize <= 0 {
+			rowSize = 16
+		}
+		switch key {
+		case tcell.KeyUp:
+			h.moveCursor(h.cursor - rowSize)
+		case tcell.KeyDown:
+			h.moveCursor(h.cursor + rowSize)
+		case tcell.KeyLeft:
+			h.moveCursor(h.cursor - 1)
+		case tcell.KeyRight:
+			h.moveCursor(h.cursor + 1)
+		case tcell.KeyPgUp:
+			h.moveCursor(h.cursor - rowSize*h.pageSize)
+		case tcell.KeyPgDn:
+			h.moveCursor(h.cursor + rowSize*h.pageSize)
+		case tcell.KeyHome:
+			h.moveCursor(h.cursor - h.cursor%rowSize)
+		case tcell.KeyEnd:
+			h.moveCursor(h.cursor - h.cursor%rowSize + rowSize - 1)
+		case tcell.KeyInsert:
+			if h.editable {
+				h.editASCII = !h.editASCII
+				h.lowNibble = false
+			}
+		case tcell.KeyRune:
+			r := event.Rune()
+			if h.edit(r) {
+				break
+			}
+			switch r {
+			case 'k':
+				h.moveCursor(h.cursor - rowSize)
+			case 'j':
+				h.moveCursor(h.cursor + rowSize)
+			case 'h':
+				h.moveCursor(h.cursor - 1)
+			case 'l':
+				h.moveCursor(h.cursor + 1)
+			case 'g':
+				h.moveCursor(0)
+			case 'G':
+				h.moveCursor(len(h.data) - 1)
+			case ':', '/', '#':
+				h.prompt, h.input = r, ""
+			case 'n', 'N':
+				if len(h.search) == 0 {
+					h.message = "No previous search pattern"
+				} else if !h.Find(h.search, r == 'n') {
+					h.message = "Pattern not found"
+				}
+			}
+		case tcell.KeyEscape, tcell.KeyTab, tcell.KeyBacktab:
+			if h.done != nil {
+				h.done(key)
+			}
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (h *HexView) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return h.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if !h.InRect(event.Position()) {
+			return false, nil
+		}
+		switch action {
+		case MouseLeftClick:
+			setFocus(h)
+		case MouseScrollUp:
+			h.moveCursor(h.cursor - 3*h.rowSize)
+		case MouseScrollDown:
+			h.moveCursor(h.cursor + 3*h.rowSize)
+		}
+		return true, nil
+	})
+}
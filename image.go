@@ -0,0 +1,458 @@
+package tview
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/gdamore/tcell"
+)
+
+// Image displays an image.Image using Unicode half blocks ("▀"). Each screen
+// cell shows two vertically stacked pixels, the upper one in the cell's
+// foreground color and the lower one in its background color. The image is
+// scaled to fit the primitive's inner rectangle, keeping its aspect ratio
+// unless stretching is switched on (see SetStretch()).
+//
+// On terminals which support true color, pixels are shown in their original
+// color. Otherwise, the image is reduced to the 256-color palette, with
+// Floyd-Steinberg dithering to reduce color banding (see SetDithering()).
+//
+// Terminals which support sixel graphics can show the image at their full
+// pixel resolution instead (see SetSixel()).
+type Image struct {
+	*Box
+
+	// The image to be displayed.
+	image image.Image
+
+	// The horizontal alignment of the image, one of AlignLeft, AlignCenter,
+	// or AlignRight.
+	align int
+
+	// If true, the image fills the entire inner rectangle, ignoring its
+	// aspect ratio.
+	stretch bool
+
+	// Whether or not to dither the image when reducing it to 256 colors.
+	dithering bool
+
+	// The number of colors to use. 0 means determine from the screen.
+	colors int
+
+	// The scaled and converted pixels of the last draw call, row by row.
+	cache []tcell.Color
+
+	// The dimensions (in pixels) and number of colors the cache was made
+	// for.
+	cacheWidth, cacheHeight, cacheColors int
+
+	// Whether or not the image is output as sixel graphics.
+	sixel bool
+
+	// The size of a screen cell in pixels, used for sixel output.
+	cellWidth, cellHeight int
+
+	// The sixel data of the last draw call and the dimensions (in pixels) it
+	// was made for.
+	sixelCache                        string
+	sixelCacheWidth, sixelCacheHeight int
+}
+
+// NewImage returns a new, empty image primitive.
+func NewImage() *Image {
+	return &Image{
+		Box:        NewBox(),
+		align:      AlignCenter,
+		dithering:  true,
+		cellWidth:  10,
+		cellHeight: 20,
+	}
+}
+
+// SetImage sets the image to be displayed. Provide nil to remove the image.
+func (i *Image) SetImage(img image.Image) *Image {
+	i.image = img
+	i.cache = nil
+	i.sixelCache = ""
+	return i
+}
+
+// GetImage returns the image being displayed.
+func (i *Image) GetImage() image.Image {
+	return i.image
+}
+
+// SetAlign sets the horizontal alignment of the image within the available
+// space. Must be one of AlignLeft, AlignCenter, or AlignRight.
+func (i *Image) SetAlign(align int) *Image {
+	i.align = align
+	return i
+}
+
+// SetStretch sets whether or not the image is stretched to fill the entire
+// available space, ignoring its aspect ratio.
+func (i *Image) SetStretch(stretch bool) *Image {
+	i.stretch = stretch
+	i.cache = nil
+	i.sixelCache = ""
+	return i
+}
+
+// SetDithering sets whether or not Floyd-Steinberg dithering is applied when
+// the image is reduced to the 256-color palette. This is on by default. It may
+// be helpful to switch it off for images with few colors, e.g. QR codes or
+// icons.
+func (i *Image) SetDithering(dithering bool) *Image {
+	i.dithering = dithering
+	i.cache = nil
+	i.sixelCache = ""
+	return i
+}
+
+// SetColors sets the number of colors used to display the image. Use 0
+// (the default) to determine this from the terminal's capabilities, 256 to
+// force the 256-color palette, or any larger number for true color.
+func (i *Image) SetColors(colors int) *Image {
+	i.colors = colors
+	i.cache = nil
+	return i
+}
+
+// SetSixel sets whether or not the image is output as sixel graphics. This is
+// off by default as only some terminals support sixels. When switched on, the
+// image's cells are cleared and the image is written to the terminal after
+// the application has shown the screen. It is reduced to the 256-color palette
+// (dithered unless switched off with SetDithering()). Images drawn directly
+// onto a screen, i.e. not by an Application, are always shown with half
+// blocks.
+//
+// Sixel graphics are drawn on top of the screen's cells, so they also cover
+// any primitives drawn on top of the image.
+func (i *Image) SetSixel(sixel bool) *Image {
+	i.sixel = sixel
+	return i
+}
+
+// SetCellSize sets the size of a screen cell in pixels. The terminal's actual
+// cell size is not known to the application so this is used to scale the
+// image to its rectangle when it is output as sixel graphics (see
+// SetSixel()). The default is 10x20 pixels.
+func (i *Image) SetCellSize(width, height int) *Image {
+	if width > 0 && height > 0 {
+		i.cellWidth, i.cellHeight = width, height
+		i.sixelCache = ""
+	}
+	return i
+}
+
+// imageSize returns the size (in pixels) at which the image is displayed in
+// an area of the given size (in pixels).
+func (i *Image) imageSize(width, height int) (int, int) {
+	bounds := i.image.Bounds()
+	if i.stretch || bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return width, height
+	}
+	if bounds.Dx()*height > bounds.Dy()*width {
+		// The image is wider than the available space.
+		return width, bounds.Dy() * width / bounds.Dx()
+	}
+	return bounds.Dx() * height / bounds.Dy(), height
+}
+
+// scale returns the image's pixels scaled to the given size, each pixel
+// being the average of the source pixels it covers. Transparent pixels are
+// blended with the given background color.
+func (i *Image) scale(width, height int, background [3]float64) [][3]float64 {
+	bounds := i.image.Bounds()
+	pixels := make([][3]float64, width*height)
+	for y := 0; y < height; y++ {
+		fromY, toY := bounds.Min.Y+y*bounds.Dy()/height, bounds.Min.Y+(y+1)*bounds.Dy()/height
+		if toY <= fromY {
+			toY = fromY + 1
+		}
+		for x := 0; x < width; x++ {
+			fromX, toX := bounds.Min.X+x*bounds.Dx()/width, bounds.Min.X+(x+1)*bounds.Dx()/width
+			if toX <= fromX {
+				toX = fromX + 1
+			}
+			var sum [3]float64
+			for sy := fromY; sy < toY; sy++ {
+				for sx := fromX; sx < toX; sx++ {
+					r, g, b, a := i.image.At(sx, sy).RGBA()
+					alpha := float64(a) / 0xffff
+					sum[0] += float64(r)/0x101 + background[0]*(1-alpha)
+					sum[1] += float64(g)/0x101 + background[1]*(1-alpha)
+					sum[2] += float64(b)/0x101 + background[2]*(1-alpha)
+				}
+			}
+			count := float64((toY - fromY) * (toX - fromX))
+			pixels[y*width+x] = [3]float64{sum[0] / count, sum[1] / count, sum[2] / count}
+		}
+	}
+	return pixels
+}
+
+// paletteLevels are the component values of the 6x6x6 color cube of the
+// 256-color palette.
+var paletteLevels = [6]float64{0, 95, 135, 175, 215, 255}
+
+// nearestPaletteColor returns the 256-color palette color closest to the
+// given RGB value and its actual RGB value. The first 16 colors are not used
+// as their values vary between terminals.
+func nearestPaletteColor(rgb [3]float64) (tcell.Color, [3]float64) {
+	// Closest color in the color cube.
+	var cube [3]int
+	for component, value := range rgb {
+		best := 0
+		for index, level := range paletteLevels {
+			if math.Abs(level-value) < math.Abs(paletteLevels[best]-value) {
+				best = index
+			}
+		}
+		cube[component] = best
+	}
+	cubeRGB := [3]float64{paletteLevels[cube[0]], paletteLevels[cube[1]], paletteLevels[cube[2]]}
+	color := tcell.Color(16 + cube[0]*36 + cube[1]*6 + cube[2])
+
+	// Closest gray.
+	gray := (rgb[0] + rgb[1] + rgb[2]) / 3
+	grayIndex := int((gray - 8 + 5) / 10)
+	if grayIndex < 0 {
+		grayIndex = 0
+	} else if grayIndex > 23 {
+		grayIndex = 23
+	}
+	grayValue := float64(8 + grayIndex*10)
+	grayRGB := [3]float64{grayValue, grayValue, grayValue}
+
+	if colorDistance(rgb, grayRGB) < colorDistance(rgb, cubeRGB) {
+		return tcell.Color(232 + grayIndex), grayRGB
+	}
+	return color, cubeRGB
+}
+
+// colorDistance returns the squared distance between two RGB colors.
+func colorDistance(a, b [3]float64) float64 {
+	dr, dg, db := a[0]-b[0], a[1]-b[1], a[2]-b[2]
+	return dr*dr + dg*dg + db*db
+}
+
+// convert turns the scaled pixels into terminal colors, using the 256-color
+// palette (with optional dithering) if "trueColor" is false.
+func (i *Image) convert(pixels [][3]float64, width, height int, trueColor bool) []tcell.Color {
+	colors := make([]tcell.Color, len(pixels))
+	for index, pixel := range pixels {
+		// Clamp values which may have been pushed out of range by dithering.
+		for component := range pixel {
+			if pixel[component] < 0 {
+				pixel[component] = 0
+			} else if pixel[component] > 255 {
+				pixel[component] = 255
+			}
+		}
+
+		if trueColor {
+			colors[index] = tcell.NewRGBColor(int32(pixel[0]+0.5), int32(pixel[1]+0.5), int32(pixel[2]+0.5))
+			continue
+		}
+
+		color, actual := nearestPaletteColor(pixel)
+		colors[index] = color
+		if !i.dithering {
+			continue
+		}
+
+		// Floyd-Steinberg: distribute the error to neighboring pixels.
+		x, y := index%width, index/width
+		for component := range pixel {
+			err := pixel[component] - actual[component]
+			if x+1 < width {
+				pixels[index+1][component] += err * 7 / 16
+			}
+			if y+1 < height {
+				if x > 0 {
+					pixels[index+width-1][component] += err * 3 / 16
+				}
+				pixels[index+width][component] += err * 5 / 16
+				if x+1 < width {
+					pixels[index+width+1][component] += err * 1 / 16
+				}
+			}
+		}
+	}
+	return colors
+}
+
+// Draw draws this primitive onto the screen.
+func (i *Image) Draw(screen tcell.Screen) {
+	defer i.applyStyle()()
+	i.Box.Draw(screen)
+	if i.image == nil {
+		return
+	}
+	x, y, width, height := i.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	// Sixel graphics are written after the screen was shown.
+	if state := appStateOf(screen); i.sixel && state != nil {
+		i.drawSixel(state, x, y, width, height)
+		return
+	}
+
+	// Determine the image's size and scale it.
+	imageWidth, imageHeight := i.imageSize(width, height*2)
+	if imageWidth <= 0 || imageHeight <= 0 {
+		return
+	}
+	colors := i.colors
+	if colors == 0 {
+		colors = screen.Colors()
+	}
+	if i.cache == nil || i.cacheWidth != imageWidth || i.cacheHeight != imageHeight || i.cacheColors != colors {
+		pixels := i.scale(imageWidth, imageHeight, i.backgroundRGB())
+		i.cache = i.convert(pixels, imageWidth, imageHeight, colors > 256)
+		i.cacheWidth, i.cacheHeight, i.cacheColors = imageWidth, imageHeight, colors
+	}
+
+	// Draw the pixels.
+	switch i.align {
+	case AlignCenter:
+		x += (width - imageWidth) / 2
+	case AlignRight:
+		x += width - imageWidth
+	}
+	for row := 0; row < (imageHeight+1)/2; row++ {
+		for column := 0; column < imageWidth; column++ {
+			style := tcell.StyleDefault.Foreground(i.cache[row*2*imageWidth+column])
+			if row*2+1 < imageHeight {
+				style = style.Background(i.cache[(row*2+1)*imageWidth+column])
+			} else {
+				style = style.Background(i.backgroundColor)
+			}
+			screen.SetContent(x+column, y+row, '▀', nil, style)
+		}
+	}
+}
+
+// backgroundRGB returns the RGB value of the background color which
+// transparent pixels are blended with.
+func (i *Image) backgroundRGB() (background [3]float64) {
+	if i.backgroundColor != tcell.ColorDefault {
+		r, g, b := i.backgroundColor.RGB()
+		background = [3]float64{float64(r), float64(g), float64(b)}
+	}
+	return
+}
+
+// drawSixel queues the image's sixel data to be written to the terminal at
+// the given position of its inner rectangle after the screen was shown. The
+// cells below the image were already cleared by the box.
+func (i *Image) drawSixel(state *appState, x, y, width, height int) {
+	imageWidth, imageHeight := i.imageSize(width*i.cellWidth, height*i.cellHeight)
+	if imageWidth <= 0 || imageHeight <= 0 {
+		return
+	}
+	if i.sixelCache == "" || i.sixelCacheWidth != imageWidth || i.sixelCacheHeight != imageHeight {
+		pixels := i.scale(imageWidth, imageHeight, i.backgroundRGB())
+		i.sixelCache = encodeSixel(i.convert(pixels, imageWidth, imageHeight, false), imageWidth, imageHeight)
+		i.sixelCacheWidth, i.sixelCacheHeight = imageWidth, imageHeight
+	}
+
+	// Position the image.
+	columns := (imageWidth + i.cellWidth - 1) / i.cellWidth
+	switch i.align {
+	case AlignCenter:
+		x += (width - columns) / 2
+	case AlignRight:
+		x += width - columns
+	}
+	state.pendingSixels = append(state.pendingSixels, sixelImage{x: x, y: y, data: i.sixelCache})
+}
+
+// sixelImage is sixel data to be written at a screen position.
+type sixelImage struct {
+	x, y int
+	data string
+}
+
+// writeSixels writes the sixel images queued since the last call to the
+// given terminal output and clears the queue. The cursor position is saved
+// and restored around each image.
+func (s *appState) writeSixels(w io.Writer) {
+	for _, sixel := range s.pendingSixels {
+		fmt.Fprintf(w, "\x1b7\x1b[%d;%dH%s\x1b8", sixel.y+1, sixel.x+1, sixel.data)
+	}
+	s.pendingSixels = s.pendingSixels[:0]
+}
+
+// encodeSixel returns the sixel escape sequence which displays the given
+// pixels, given row by row as 256-color palette colors.
+func encodeSixel(pixels []tcell.Color, width, height int) string {
+	var buffer strings.Builder
+	fmt.Fprintf(&buffer, "\x1bP0;1q\"1;1;%d;%d", width, height)
+
+	// Define the colors used, in the order in which they first appear.
+	defined := make(map[tcell.Color]bool)
+	for _, color := range pixels {
+		if !defined[color] {
+			defined[color] = true
+			r, g, b := color.RGB()
+			fmt.Fprintf(&buffer, "#%d;2;%d;%d;%d", color, (r*100+127)/255, (g*100+127)/255, (b*100+127)/255)
+		}
+	}
+
+	// Output bands of six rows, one pass per color.
+	for top := 0; top < height; top += 6 {
+		var colors []tcell.Color
+		used := make(map[tcell.Color]bool)
+		for y := top; y < top+6 && y < height; y++ {
+			for _, color := range pixels[y*width : (y+1)*width] {
+				if !used[color] {
+					used[color] = true
+					colors = append(colors, color)
+				}
+			}
+		}
+		for index, color := range colors {
+			if index > 0 {
+				buffer.WriteByte('$') // Back to the start of the band.
+			}
+			fmt.Fprintf(&buffer, "#%d", color)
+			var last byte
+			count := 0
+			flush := func() {
+				if count > 3 {
+					fmt.Fprintf(&buffer, "!%d%c", count, last)
+				} else {
+					for ; count > 0; count-- {
+						buffer.WriteByte(last)
+					}
+				}
+				count = 0
+			}
+			for x := 0; x < width; x++ {
+				var bits byte
+				for row := 0; row < 6 && top+row < height; row++ {
+					if pixels[(top+row)*width+x] == color {
+						bits |= 1 << uint(row)
+					}
+				}
+				if ch := '?' + bits; ch != last || count == 0 {
+					flush()
+					last = ch
+				}
+				count++
+			}
+			flush()
+		}
+		buffer.WriteByte('-') // Next band.
+	}
+
+	buffer.WriteString("\x1b\\")
+	return buffer.String()
+}
@@ -0,0 +1,79 @@
+package tview
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell"
+)
+
+func TestEncodeSixel(t *testing.T) {
+	black, white := tcell.Color(16), tcell.Color(231)
+	for _, test := range []struct {
+		pixels        []tcell.Color
+		width, height int
+		want          string
+	}{
+		{
+			pixels: []tcell.Color{black, white, white, black},
+			width:  2,
+			height: 2,
+			want:   "\x1bP0;1q\"1;1;2;2#16;2;0;0;0#231;2;100;100;100#16@A$#231A@-\x1b\\",
+		},
+		{
+			pixels: []tcell.Color{black, black, black, black, black, white},
+			width:  6,
+			height: 1,
+			want:   "\x1bP0;1q\"1;1;6;1#16;2;0;0;0#231;2;100;100;100#16!5@?$#231!5?@-\x1b\\",
+		},
+		{
+			pixels: []tcell.Color{black, black, black, black, black, black, black},
+			width:  1,
+			height: 7,
+			want:   "\x1bP0;1q\"1;1;1;7#16;2;0;0;0#16~-#16@-\x1b\\",
+		},
+	} {
+		if sixel := encodeSixel(test.pixels, test.width, test.height); sixel != test.want {
+			t.Errorf("encodeSixel(%v, %d, %d) = %q, want %q", test.pixels, test.width, test.height, sixel, test.want)
+		}
+	}
+}
+
+func TestImageSixel(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for index := range img.Pix {
+		img.Pix[index] = 0xff
+	}
+	img.Set(0, 0, color.Black)
+
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	screen.SetSize(10, 5)
+	state := newAppState()
+	i := NewImage().SetImage(img).SetSixel(true).SetCellSize(2, 2).SetAlign(AlignLeft)
+	i.SetRect(2, 1, 4, 2)
+	i.Draw(&appScreen{Screen: screen, state: state})
+
+	var output bytes.Buffer
+	state.writeSixels(&output)
+	if want := "\x1b7\x1b[2;3H\x1bP0;1q\"1;1;4;4"; !strings.HasPrefix(output.String(), want) {
+		t.Errorf("sixel output %q does not start with %q", output.String(), want)
+	}
+	if !strings.HasSuffix(output.String(), "\x1b\\\x1b8") {
+		t.Errorf("sixel output %q does not restore the cursor", output.String())
+	}
+	if len(state.pendingSixels) != 0 {
+		t.Error("sixel images are still queued after they were written")
+	}
+
+	// Without an application, the image is drawn with half blocks.
+	i.Draw(screen)
+	if ch, _, _, _ := screen.GetContent(2, 1); ch != '▀' {
+		t.Errorf("image drawn directly onto a screen shows %q, want half blocks", ch)
+	}
+}
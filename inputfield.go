@@ -7,7 +7,6 @@ import (
 	"unicode/utf8"
 
 	"github.com/gdamore/tcell"
-	runewidth "github.com/mattn/go-runewidth"
 )
 
 // InputField is a one-line box (three lines if there is a title) where the
@@ -49,6 +48,9 @@ type InputField struct {
 	// An optional function which is called when the input has changed.
 	changed func(text string)
 
+	// An optional function which is called when text was pasted.
+	pasted func(text string)
+
 	// An optional function which is called when the user indicated that they
 	// are done entering text. The key which was pressed is provided (tab,
 	// shift-tab, enter, or escape).
@@ -57,11 +59,12 @@ type InputField struct {
 
 // NewInputField returns a new input field.
 func NewInputField() *InputField {
+	styles := Styles.forWidget("InputField")
 	return &InputField{
 		Box:                  NewBox(),
-		labelColor:           Styles.SecondaryTextColor,
-		fieldBackgroundColor: Styles.ContrastBackgroundColor,
-		fieldTextColor:       Styles.PrimaryTextColor,
+		labelColor:           styles.SecondaryTextColor,
+		fieldBackgroundColor: styles.ContrastBackgroundColor,
+		fieldTextColor:       styles.PrimaryTextColor,
 	}
 }
 
@@ -154,6 +157,15 @@ func (i *InputField) SetChangedFunc(handler func(text string)) *InputField {
 	return i
 }
 
+// SetPastedFunc sets a handler which is called when the user pasted text into
+// the input field, after the text was inserted and the "changed" handler was
+// called. It receives the pasted text. Pasted text is only recognized in
+// bracketed paste mode (see Application.EnableBracketedPaste()).
+func (i *InputField) SetPastedFunc(handler func(text string)) *InputField {
+	i.pasted = handler
+	return i
+}
+
 // SetDoneFunc sets a handler which is called when the user is done entering
 // text. The callback function is provided with the key that was pressed, which
 // is one of the following:
@@ -172,8 +184,25 @@ func (i *InputField) SetFinishedFunc(handler func(key tcell.Key)) FormItem {
 	return i.SetDoneFunc(handler)
 }
 
+// SetDisabled sets whether or not the input field is disabled. Disabled input
+// fields do not accept input and are skipped by Form navigation. See
+// Box.SetDisabled() for details.
+func (i *InputField) SetDisabled(disabled bool) *InputField {
+	i.Box.SetDisabled(disabled)
+	return i
+}
+
+// applyTheme is called when the application's theme changes.
+func (i *InputField) applyTheme(from, to *Theme) {
+	i.Box.applyTheme(from, to)
+	themeColor(&i.labelColor, from.SecondaryTextColor, to.SecondaryTextColor)
+	themeColor(&i.fieldBackgroundColor, from.ContrastBackgroundColor, to.ContrastBackgroundColor)
+	themeColor(&i.fieldTextColor, from.PrimaryTextColor, to.PrimaryTextColor)
+}
+
 // Draw draws this primitive onto the screen.
 func (i *InputField) Draw(screen tcell.Screen) {
+	defer i.applyStyle(&i.fieldTextColor)()
 	i.Box.Draw(screen)
 
 	// Prepare
@@ -184,7 +213,7 @@ func (i *InputField) Draw(screen tcell.Screen) {
 	}
 
 	// Draw label.
-	_, drawnWidth := Print(screen, i.label, x, y, rightLimit-x, AlignLeft, i.labelColor)
+	_, drawnWidth := Print(screen, i.bidiText(i.label), x, y, rightLimit-x, AlignLeft, i.labelColor)
 	x += drawnWidth
 
 	// Draw input area.
@@ -205,27 +234,9 @@ func (i *InputField) Draw(screen tcell.Screen) {
 	if i.maskCharacter > 0 {
 		text = strings.Repeat(string(i.maskCharacter), utf8.RuneCountInString(i.text))
 	}
-	fieldWidth-- // We need one cell for the cursor.
-	if fieldWidth < runewidth.StringWidth(text) {
-		runes := []rune(text)
-		for pos := len(runes) - 1; pos >= 0; pos-- {
-			ch := runes[pos]
-			w := runewidth.RuneWidth(ch)
-			if fieldWidth-w < 0 {
-				break
-			}
-			_, _, style, _ := screen.GetContent(x+fieldWidth-w, y)
-			style = style.Foreground(i.fieldTextColor)
-			for w > 0 {
-				fieldWidth--
-				screen.SetContent(x+fieldWidth, y, ch, nil, style)
-				w--
-			}
-		}
-	} else {
-		pos := 0
+	drawText := func(text string, pos int) {
 		for _, ch := range text {
-			w := runewidth.RuneWidth(ch)
+			w := RuneWidth(ch)
 			_, _, style, _ := screen.GetContent(x+pos, y)
 			style = style.Foreground(i.fieldTextColor)
 			for w > 0 {
@@ -235,6 +246,29 @@ func (i *InputField) Draw(screen tcell.Screen) {
 			}
 		}
 	}
+	fieldWidth-- // We need one cell for the cursor.
+	if fieldWidth < stringWidth(text) {
+		// Show the end of the text, preceded by an ellipsis. Grapheme clusters
+		// are never split.
+		ellipsis := string(Styles.Ellipsis)
+		available := fieldWidth - stringWidth(ellipsis)
+		start, textWidth := len(text), 0
+		clusters := graphemes(text)
+		for index := len(clusters) - 1; index >= 0; index-- {
+			w := stringWidth(clusters[index])
+			if textWidth+w > available {
+				break
+			}
+			textWidth += w
+			start -= len(clusters[index])
+		}
+		if available >= 0 {
+			drawText(ellipsis, 0)
+		}
+		drawText(text[start:], fieldWidth-textWidth)
+	} else {
+		drawText(text, 0)
+	}
 
 	// Set cursor.
 	if i.focus.HasFocus() {
@@ -252,7 +286,7 @@ func (i *InputField) setCursor(screen tcell.Screen) {
 		y++
 		rightLimit -= 2
 	}
-	fieldWidth := runewidth.StringWidth(i.text)
+	fieldWidth := stringWidth(i.text)
 	if i.fieldWidth > 0 && fieldWidth > i.fieldWidth-1 {
 		fieldWidth = i.fieldWidth - 1
 	}
@@ -263,6 +297,44 @@ func (i *InputField) setCursor(screen tcell.Screen) {
 	screen.ShowCursor(x, y)
 }
 
+// PasteHandler returns the handler which inserts pasted text. The text is
+// appended in one step: line breaks and tabs are replaced with spaces, the
+// acceptance function (see SetAcceptanceFunc()) checks the entire new text
+// once, and the "changed" handler is called once.
+func (i *InputField) PasteHandler() func(text string, setFocus func(p Primitive)) {
+	return func(text string, setFocus func(p Primitive)) {
+		if i.disabled {
+			return
+		}
+		text = strings.Map(func(r rune) rune {
+			if r == '\n' || r == '\t' {
+				return ' '
+			}
+			if r < ' ' || r == 0x7f {
+				return -1
+			}
+			return r
+		}, text)
+		if text == "" {
+			return
+		}
+		newText := i.text + text
+		if i.accept != nil {
+			runes := []rune(text)
+			if !i.accept(newText, runes[len(runes)-1]) {
+				return
+			}
+		}
+		i.text = newText
+		if i.changed != nil {
+			i.changed(i.text)
+		}
+		if i.pasted != nil {
+			i.pasted(text)
+		}
+	}
+}
+
 // InputHandler returns the handler for this primitive.
 func (i *InputField) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
 	return i.wrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
@@ -0,0 +1,123 @@
+package tview
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell"
+)
+
+// keyLeader is the key of a KeyStroke which stands for the leader key (see
+// Application.SetLeaderKey()).
+const keyLeader tcell.Key = -1
+
+// KeyStroke is a single key press, e.g. one of the keys of a key sequence (see
+// Box.AddKeySequence()).
+type KeyStroke struct {
+	// The key. If it is tcell.KeyRune, the key is the rune below.
+	Key tcell.Key
+
+	// The rune, if the key is tcell.KeyRune.
+	Rune rune
+
+	// Whether or not the Alt key is held down.
+	Alt bool
+}
+
+// keyStrokeOf returns the key stroke of the given key event.
+func keyStrokeOf(event *tcell.EventKey) KeyStroke {
+	stroke := KeyStroke{Key: event.Key(), Alt: event.Modifiers()&tcell.ModAlt != 0}
+	if stroke.Key == tcell.KeyRune {
+		stroke.Rune = event.Rune()
+	}
+	return stroke
+}
+
+// String returns the name of the key stroke as shown to the user, e.g. "g",
+// "Space", "Ctrl-S", or "Alt-Enter".
+func (k KeyStroke) String() string {
+	var name string
+	switch {
+	case k.Key == keyLeader:
+		return "Leader"
+	case k.Key == tcell.KeyRune && k.Rune == ' ':
+		name = "Space"
+	case k.Key == tcell.KeyRune:
+		name = string(k.Rune)
+	default:
+		var ok bool
+		if name, ok = tcell.KeyNames[k.Key]; !ok {
+			name = "?"
+		}
+	}
+	if k.Alt {
+		name = "Alt-" + name
+	}
+	return name
+}
+
+// ParseKeySequence parses a sequence of key strokes separated by spaces, e.g.
+// "g g", "Ctrl-X Ctrl-S", or "Leader f". Each key stroke is either a single
+// rune, "Space", "Leader" (see Application.SetLeaderKey()), or the name of a
+// special key as found in tcell.KeyNames (e.g. "Enter", "F1", or "Ctrl-S",
+// case-insensitive). Key strokes may be prefixed with "Alt-".
+func ParseKeySequence(sequence string) ([]KeyStroke, error) {
+	var strokes []KeyStroke
+	for _, field := range strings.Fields(sequence) {
+		var stroke KeyStroke
+		if len(field) > 4 && strings.EqualFold(field[:4], "Alt-") {
+			stroke.Alt = true
+			field = field[4:]
+		}
+		switch {
+		case utf8.RuneCountInString(field) == 1:
+			stroke.Key = tcell.KeyRune
+			stroke.Rune, _ = utf8.DecodeRuneInString(field)
+		case strings.EqualFold(field, "Space"):
+			stroke.Key, stroke.Rune = tcell.KeyRune, ' '
+		case strings.EqualFold(field, "Leader"):
+			stroke.Key = keyLeader
+		default:
+			found := false
+			for key, name := range tcell.KeyNames {
+				if strings.EqualFold(field, name) {
+					stroke.Key, found = key, true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("unknown key %q", field)
+			}
+		}
+		strokes = append(strokes, stroke)
+	}
+	if len(strokes) == 0 {
+		return nil, errors.New("empty key sequence")
+	}
+	return strokes, nil
+}
+
+// keySequenceString returns the names of the given key strokes, separated by
+// spaces.
+func keySequenceString(strokes []KeyStroke) string {
+	names := make([]string, len(strokes))
+	for index, stroke := range strokes {
+		names[index] = stroke.String()
+	}
+	return strings.Join(names, " ")
+}
+
+// keySequenceTimeoutEvent is posted to the application's event queue when
+// the time to complete a key sequence (or a paste marker) has run out.
+type keySequenceTimeoutEvent struct {
+	tcell.EventTime
+
+	// The number of the pending key sequence which timed out.
+	generation int
+
+	// Whether the timeout refers to a paste marker instead of a shortcut's
+	// key sequence.
+	paste bool
+}
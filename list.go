@@ -1,6 +1,7 @@
 package tview
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/gdamore/tcell"
@@ -12,6 +13,9 @@ type listItem struct {
 	SecondaryText string // A secondary text to be shown underneath the main text.
 	Shortcut      rune   // The key to select the list item directly, 0 if there is no shortcut.
 	Selected      func() // The optional function which is called when the item is selected.
+	Disabled      bool   // Whether the item is skipped during navigation and cannot be selected.
+
+	mainTextWidth textWidth // The screen width of the main text.
 }
 
 // List displays rows of items, each of which can be selected.
@@ -26,6 +30,9 @@ type List struct {
 	// The index of the currently selected item.
 	currentItem int
 
+	// The index of the first item shown in the list.
+	itemOffset int
+
 	// Whether or not to show the secondary item texts.
 	showSecondaryText bool
 
@@ -44,6 +51,9 @@ type List struct {
 	// The background color for selected items.
 	selectedBackgroundColor tcell.Color
 
+	// The scroll bar settings.
+	scrollBar scrollBar
+
 	// An optional function which is called when the user has navigated to a list
 	// item.
 	changed func(index int, mainText, secondaryText string, shortcut rune)
@@ -56,16 +66,23 @@ type List struct {
 	done func()
 }
 
+// listState is the state of a List saved with SaveState().
+type listState struct {
+	Current int `json:"current"`
+}
+
 // NewList returns a new form.
 func NewList() *List {
+	styles := Styles.forWidget("List")
 	return &List{
 		Box:                     NewBox(),
 		showSecondaryText:       true,
-		mainTextColor:           Styles.PrimaryTextColor,
-		secondaryTextColor:      Styles.TertiaryTextColor,
-		shortcutColor:           Styles.SecondaryTextColor,
-		selectedTextColor:       Styles.PrimitiveBackgroundColor,
-		selectedBackgroundColor: Styles.PrimaryTextColor,
+		mainTextColor:           styles.PrimaryTextColor,
+		secondaryTextColor:      styles.TertiaryTextColor,
+		shortcutColor:           styles.SecondaryTextColor,
+		selectedTextColor:       styles.PrimitiveBackgroundColor,
+		selectedBackgroundColor: styles.PrimaryTextColor,
+		scrollBar:               newScrollBar(ScrollBarNever, styles),
 	}
 }
 
@@ -109,6 +126,19 @@ func (l *List) SetSelectedBackgroundColor(color tcell.Color) *List {
 	return l
 }
 
+// SetScrollBarVisibility sets when a vertical scroll bar is shown on the right
+// edge of the list. The default is ScrollBarNever.
+func (l *List) SetScrollBarVisibility(visibility ScrollBarVisibility) *List {
+	l.scrollBar.visibility = visibility
+	return l
+}
+
+// SetScrollBarColors sets the colors of the scroll bar's track and thumb.
+func (l *List) SetScrollBarColors(track, thumb tcell.Color) *List {
+	l.scrollBar.trackColor, l.scrollBar.thumbColor = track, thumb
+	return l
+}
+
 // ShowSecondaryText determines whether or not to show secondary item texts.
 func (l *List) ShowSecondaryText(show bool) *List {
 	l.showSecondaryText = show
@@ -154,34 +184,125 @@ func (l *List) SetDoneFunc(handler func()) *List {
 // may provide nil if no such item is needed or if all events are handled
 // through the selected callback set with SetSelectedFunc().
 func (l *List) AddItem(mainText, secondaryText string, shortcut rune, selected func()) *List {
-	l.items = append(l.items, &listItem{
+	item := &listItem{
 		MainText:      mainText,
 		SecondaryText: secondaryText,
 		Shortcut:      shortcut,
 		Selected:      selected,
-	})
+	}
+	item.mainTextWidth.get(mainText)
+	l.items = append(l.items, item)
 	if len(l.items) == 1 && l.changed != nil {
-		item := l.items[0]
 		l.changed(0, item.MainText, item.SecondaryText, item.Shortcut)
 	}
 	return l
 }
 
+// SetItemDisabled sets whether or not the item with the given index is
+// disabled. Disabled items are drawn in Styles.DisabledTextColor, are skipped
+// when navigating the list, and cannot be selected. Indices outside the range
+// of items are ignored.
+func (l *List) SetItemDisabled(index int, disabled bool) *List {
+	if index >= 0 && index < len(l.items) {
+		l.items[index].Disabled = disabled
+	}
+	return l
+}
+
+// IsItemDisabled returns whether or not the item with the given index is
+// disabled.
+func (l *List) IsItemDisabled(index int) bool {
+	return index >= 0 && index < len(l.items) && l.items[index].Disabled
+}
+
+// enabledItem returns the index of the first item which is not disabled,
+// starting at the given index and moving in the direction of "step" (1 or -1),
+// wrapping around at either end. If all items are disabled, -1 is returned.
+func (l *List) enabledItem(index, step int) int {
+	count := len(l.items)
+	for tries := 0; tries < count; tries++ {
+		index = (index%count + count) % count
+		if !l.items[index].Disabled {
+			return index
+		}
+		index += step
+	}
+	return -1
+}
+
 // Clear removes all items from the list.
 func (l *List) Clear() *List {
 	l.items = nil
 	l.currentItem = 0
+	l.itemOffset = 0
 	return l
 }
 
+// SaveState returns the index of the current item as JSON. See SaveLayout()
+// for details.
+func (l *List) SaveState() ([]byte, error) {
+	return json.Marshal(listState{Current: l.currentItem})
+}
+
+// RestoreState restores a state returned by SaveState().
+func (l *List) RestoreState(data []byte) error {
+	var state listState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	if state.Current >= 0 && state.Current < len(l.items) {
+		l.SetCurrentItem(state.Current)
+	}
+	return nil
+}
+
+// applyTheme is called when the application's theme changes.
+func (l *List) applyTheme(from, to *Theme) {
+	l.Box.applyTheme(from, to)
+	themeColor(&l.mainTextColor, from.PrimaryTextColor, to.PrimaryTextColor)
+	themeColor(&l.secondaryTextColor, from.TertiaryTextColor, to.TertiaryTextColor)
+	themeColor(&l.shortcutColor, from.SecondaryTextColor, to.SecondaryTextColor)
+	themeColor(&l.selectedTextColor, from.PrimitiveBackgroundColor, to.PrimitiveBackgroundColor)
+	themeColor(&l.selectedBackgroundColor, from.PrimaryTextColor, to.PrimaryTextColor)
+	l.scrollBar.applyTheme(from, to)
+}
+
 // Draw draws this primitive onto the screen.
 func (l *List) Draw(screen tcell.Screen) {
+	defer l.applyStyle(&l.mainTextColor)()
 	l.Box.Draw(screen)
 
 	// Determine the dimensions.
 	x, y, width, height := l.GetInnerRect()
 	bottomLimit := y + height
 
+	// Scroll such that the current item is visible.
+	itemHeight := 1
+	if l.showSecondaryText {
+		itemHeight = 2
+	}
+	visibleItems := height / itemHeight
+	if visibleItems < 1 {
+		visibleItems = 1
+	}
+	if l.currentItem < l.itemOffset {
+		l.itemOffset = l.currentItem
+	} else if l.currentItem >= l.itemOffset+visibleItems {
+		l.itemOffset = l.currentItem - visibleItems + 1
+	}
+	if l.itemOffset > len(l.items)-visibleItems {
+		l.itemOffset = len(l.items) - visibleItems
+	}
+	if l.itemOffset < 0 {
+		l.itemOffset = 0
+	}
+
+	// Draw the scroll bar.
+	if l.scrollBar.shown(visibleItems, len(l.items)) {
+		width--
+		l.scrollBar.draw(screen, x+width, y, height, l.itemOffset, visibleItems, len(l.items), true, l.backgroundColor)
+	}
+
 	// Do we show any shortcuts?
 	var showShortcuts bool
 	for _, item := range l.items {
@@ -195,25 +316,34 @@ func (l *List) Draw(screen tcell.Screen) {
 
 	// Draw the list items.
 	for index, item := range l.items {
+		if index < l.itemOffset {
+			continue
+		}
 		if y >= bottomLimit {
 			break
 		}
 
+		// Disabled items are drawn in a single color.
+		mainTextColor, secondaryTextColor, shortcutColor := l.mainTextColor, l.secondaryTextColor, l.shortcutColor
+		if item.Disabled {
+			mainTextColor, secondaryTextColor, shortcutColor = l.disabledTextColor, l.disabledTextColor, l.disabledTextColor
+		}
+
 		// Shortcuts.
 		if showShortcuts && item.Shortcut != 0 {
-			Print(screen, fmt.Sprintf("(%s)", string(item.Shortcut)), x-5, y, 4, AlignRight, l.shortcutColor)
+			Print(screen, fmt.Sprintf("(%s)", string(item.Shortcut)), x-5, y, 4, AlignRight, shortcutColor)
 		}
 
 		// Main text.
-		Print(screen, item.MainText, x, y, width, AlignLeft, l.mainTextColor)
+		Print(screen, TruncateString(item.MainText, width, string(Styles.Ellipsis)), x, y, width, AlignLeft, mainTextColor)
 
 		// Background color of selected text.
 		if index == l.currentItem {
-			textWidth := StringWidth(item.MainText)
+			textWidth := item.mainTextWidth.get(item.MainText)
 			for bx := 0; bx < textWidth && bx < width; bx++ {
 				m, c, style, _ := screen.GetContent(x+bx, y)
 				fg, _, _ := style.Decompose()
-				if fg == l.mainTextColor {
+				if fg == mainTextColor {
 					fg = l.selectedTextColor
 				}
 				style = style.Background(l.selectedBackgroundColor).Foreground(fg)
@@ -229,7 +359,7 @@ func (l *List) Draw(screen tcell.Screen) {
 
 		// Secondary text.
 		if l.showSecondaryText {
-			Print(screen, item.SecondaryText, x, y, width, AlignLeft, l.secondaryTextColor)
+			Print(screen, TruncateString(item.SecondaryText, width, string(Styles.Ellipsis)), x, y, width, AlignLeft, secondaryTextColor)
 			y++
 		}
 	}
@@ -239,22 +369,29 @@ func (l *List) Draw(screen tcell.Screen) {
 func (l *List) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
 	return l.wrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
 		previousItem := l.currentItem
+		step := 1 // The direction in which disabled items are skipped.
 
 		switch key := event.Key(); key {
 		case tcell.KeyTab, tcell.KeyDown, tcell.KeyRight:
 			l.currentItem++
 		case tcell.KeyBacktab, tcell.KeyUp, tcell.KeyLeft:
 			l.currentItem--
+			step = -1
 		case tcell.KeyHome:
 			l.currentItem = 0
 		case tcell.KeyEnd:
 			l.currentItem = len(l.items) - 1
+			step = -1
 		case tcell.KeyPgDn:
 			l.currentItem += 5
 		case tcell.KeyPgUp:
 			l.currentItem -= 5
+			step = -1
 		case tcell.KeyEnter:
 			item := l.items[l.currentItem]
+			if item.Disabled {
+				break
+			}
 			if item.Selected != nil {
 				item.Selected()
 			}
@@ -271,7 +408,7 @@ func (l *List) InputHandler() func(event *tcell.EventKey, setFocus func(p Primit
 				// It's not a space bar. Is it a shortcut?
 				var found bool
 				for index, item := range l.items {
-					if item.Shortcut == ch {
+					if item.Shortcut == ch && !item.Disabled {
 						// We have a shortcut.
 						found = true
 						l.currentItem = index
@@ -283,6 +420,9 @@ func (l *List) InputHandler() func(event *tcell.EventKey, setFocus func(p Primit
 				}
 			}
 			item := l.items[l.currentItem]
+			if item.Disabled {
+				break
+			}
 			if item.Selected != nil {
 				item.Selected()
 			}
@@ -296,6 +436,13 @@ func (l *List) InputHandler() func(event *tcell.EventKey, setFocus func(p Primit
 		} else if l.currentItem >= len(l.items) {
 			l.currentItem = 0
 		}
+		if l.currentItem != previousItem && l.currentItem < len(l.items) {
+			if index := l.enabledItem(l.currentItem, step); index >= 0 {
+				l.currentItem = index
+			} else {
+				l.currentItem = previousItem
+			}
+		}
 
 		if l.currentItem != previousItem && l.currentItem < len(l.items) && l.changed != nil {
 			item := l.items[l.currentItem]
@@ -0,0 +1,634 @@
+package tview
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell"
+)
+
+// Log levels for LogView, in increasing order of severity.
+const (
+	LogDebug = iota
+	LogInfo
+	LogWarning
+	LogError
+)
+
+// logLevelLabels are the labels shown for the log levels.
+var logLevelLabels = []string{"DBG", "INF", "WRN", "ERR"}
+
+// logEntry is one entry of a LogView.
+type logEntry struct {
+	Time    time.Time // The time the entry was logged.
+	Level   int       // The entry's log level.
+	Message string    // The log message, a single line.
+
+	sequence int // A running number identifying the entry.
+}
+
+// LogView is a primitive for displaying log messages. Each entry consists of
+// a timestamp, a log level, and a single line of text. Unlike TextView, it
+// keeps a bounded number of entries (see SetMaxEntries()), can filter entries
+// by their level, and has a search function. LogView implements io.Writer so
+// it can be used as the output of a log.Logger. Entries can be added from any
+// goroutine.
+//
+// The view follows new entries as long as it is scrolled to the bottom. It
+// can also be paused, in which case new entries are collected but not shown
+// until the view is resumed.
+//
+// The following keys are available when the log view has focus:
+//
+//   - Up arrow, k: Scroll up by one line.
+//   - Down arrow, j: Scroll down by one line.
+//   - Left arrow, h: Scroll left.
+//   - Right arrow, l: Scroll right.
+//   - Page Up, Ctrl-B: Scroll up by one page.
+//   - Page Down, Ctrl-F: Scroll down by one page.
+//   - Home, g: Scroll to the top.
+//   - End, G: Scroll to the bottom and follow new entries.
+//   - p, Space: Pause or resume.
+//   - +, -: Raise or lower the minimum level of entries shown.
+//   - /: Start a search. Typed characters are added to the search text, Enter
+//     finishes typing, Escape removes the search.
+//   - n, N: Scroll to the next or previous entry matching the search.
+//   - Escape, Tab, Backtab: Finish (see SetDoneFunc()).
+type LogView struct {
+	*Box
+	sync.Mutex
+
+	// The log entries, oldest first.
+	entries []logEntry
+
+	// The maximum number of entries kept.
+	maxEntries int
+
+	// The sequence number of the next entry.
+	nextSequence int
+
+	// Entries with a lower level are not shown.
+	minLevel int
+
+	// Whether or not the view is paused and, if so, the sequence number of
+	// the first entry which was not shown.
+	paused      bool
+	pausedUntil int
+
+	// The layout used for timestamps. If empty, no timestamps are shown.
+	timestampFormat string
+
+	// The search text (case-insensitive).
+	search string
+
+	// Whether or not the user is typing the search text.
+	searching bool
+
+	// The index of the first visible line and the number of columns scrolled
+	// to the right.
+	lineOffset, columnOffset int
+
+	// If true, the view scrolls to the bottom when new entries arrive.
+	follow bool
+
+	// The number of lines available for entries the last time the view was
+	// drawn.
+	pageSize int
+
+	// The colors of timestamps and messages.
+	timestampColor, textColor tcell.Color
+
+	// The colors of the level labels.
+	levelColors []tcell.Color
+
+	// The colors used to highlight search matches.
+	matchTextColor, matchBackgroundColor tcell.Color
+
+	// An optional function which is called when entries were added.
+	changed func()
+
+	// An optional function which is called when the user leaves the log view.
+	done func(tcell.Key)
+}
+
+// NewLogView returns a new, empty log view.
+func NewLogView() *LogView {
+	styles := Styles.forWidget("LogView")
+	return &LogView{
+		Box:                  NewBox(),
+		maxEntries:           1000,
+		timestampFormat:      "15:04:05",
+		follow:               true,
+		timestampColor:       styles.TertiaryTextColor,
+		textColor:            styles.PrimaryTextColor,
+		levelColors:          []tcell.Color{tcell.ColorGray, tcell.ColorGreen, tcell.ColorYellow, tcell.ColorRed},
+		matchTextColor:       styles.InverseTextColor,
+		matchBackgroundColor: styles.SecondaryTextColor,
+	}
+}
+
+// Log adds an entry with the given level and message, timestamped with the
+// current time. Messages containing newlines result in one entry per line.
+func (l *LogView) Log(level int, message string) *LogView {
+	return l.AddEntry(time.Now(), level, message)
+}
+
+// Logf is like Log() but formats the message according to a format specifier.
+func (l *LogView) Logf(level int, format string, a ...interface{}) *LogView {
+	return l.AddEntry(time.Now(), level, fmt.Sprintf(format, a...))
+}
+
+// AddEntry adds an entry with the given timestamp, level, and message.
+// Messages containing newlines result in one entry per line. If the maximum
+// number of entries is exceeded, the oldest entries are removed.
+func (l *LogView) AddEntry(timestamp time.Time, level int, message string) *LogView {
+	if level < LogDebug {
+		level = LogDebug
+	} else if level > LogError {
+		level = LogError
+	}
+
+	l.Lock()
+	for _, line := range strings.Split(strings.TrimRight(message, "\r\n"), "\n") {
+		l.entries = append(l.entries, logEntry{
+			Time:     timestamp,
+			Level:    level,
+			Message:  strings.TrimRight(line, "\r"),
+			sequence: l.nextSequence,
+		})
+		l.nextSequence++
+	}
+	if l.maxEntries > 0 && len(l.entries) > l.maxEntries {
+		l.entries = append(l.entries[:0], l.entries[len(l.entries)-l.maxEntries:]...)
+	}
+	changed := l.changed
+	l.Unlock()
+
+	if changed != nil {
+		changed()
+	}
+	return l
+}
+
+// Write adds the given text to the log view, one entry per line, at level
+// LogInfo. This lets LogView be used as an io.Writer, e.g. with
+// log.SetOutput(). It always returns len(p) and a nil error.
+func (l *LogView) Write(p []byte) (n int, err error) {
+	l.Log(LogInfo, string(p))
+	return len(p), nil
+}
+
+// Clear removes all entries.
+func (l *LogView) Clear() *LogView {
+	l.Lock()
+	defer l.Unlock()
+	l.entries = nil
+	l.lineOffset, l.columnOffset = 0, 0
+	return l
+}
+
+// GetEntryCount returns the number of entries held by the log view, including
+// those which are currently not shown.
+func (l *LogView) GetEntryCount() int {
+	l.Lock()
+	defer l.Unlock()
+	return len(l.entries)
+}
+
+// SetMaxEntries sets the maximum number of entries kept by the log view. When
+// this number is exceeded, the oldest entries are removed. A value of 0 means
+// there is no limit. The default is 1000.
+func (l *LogView) SetMaxEntries(maxEntries int) *LogView {
+	l.Lock()
+	defer l.Unlock()
+	l.maxEntries = maxEntries
+	if maxEntries > 0 && len(l.entries) > maxEntries {
+		l.entries = append(l.entries[:0], l.entries[len(l.entries)-maxEntries:]...)
+	}
+	return l
+}
+
+// SetMinLevel sets the minimum level of entries shown, e.g. LogWarning to hide
+// debug and info entries. The default is LogDebug.
+func (l *LogView) SetMinLevel(level int) *LogView {
+	l.Lock()
+	defer l.Unlock()
+	if level < LogDebug {
+		level = LogDebug
+	} else if level > LogError {
+		level = LogError
+	}
+	l.minLevel = level
+	return l
+}
+
+// GetMinLevel returns the minimum level of entries shown.
+func (l *LogView) GetMinLevel() int {
+	l.Lock()
+	defer l.Unlock()
+	return l.minLevel
+}
+
+// Pause stops showing new entries. They are still collected and shown when
+// Resume() is called.
+func (l *LogView) Pause() *LogView {
+	l.Lock()
+	defer l.Unlock()
+	if !l.paused {
+		l.paused = true
+		l.pausedUntil = l.nextSequence
+	}
+	return l
+}
+
+// Resume shows all entries again after Pause() was called.
+func (l *LogView) Resume() *LogView {
+	l.Lock()
+	defer l.Unlock()
+	l.paused = false
+	return l
+}
+
+// IsPaused returns whether or not the log view is paused.
+func (l *LogView) IsPaused() bool {
+	l.Lock()
+	defer l.Unlock()
+	return l.paused
+}
+
+// SetTimestampFormat sets the layout (see time.Time.Format()) used for the
+// entries' timestamps. An empty string hides the timestamps. The default is
+// "15:04:05".
+func (l *LogView) SetTimestampFormat(format string) *LogView {
+	l.Lock()
+	defer l.Unlock()
+	l.timestampFormat = format
+	return l
+}
+
+// SetSearch sets the search text. Occurrences of the text (compared
+// case-insensitively) are highlighted and the user can jump between the
+// entries containing it with "n" and "N". An empty string removes the search.
+func (l *LogView) SetSearch(text string) *LogView {
+	l.Lock()
+	defer l.Unlock()
+	l.search = text
+	return l
+}
+
+// GetSearch returns the current search text.
+func (l *LogView) GetSearch() string {
+	l.Lock()
+	defer l.Unlock()
+	return l.search
+}
+
+// SetLevelColor sets the color of the label of the given log level.
+func (l *LogView) SetLevelColor(level int, color tcell.Color) *LogView {
+	l.Lock()
+	defer l.Unlock()
+	if level >= LogDebug && level <= LogError {
+		l.levelColors[level] = color
+	}
+	return l
+}
+
+// SetTimestampColor sets the color of the timestamps.
+func (l *LogView) SetTimestampColor(color tcell.Color) *LogView {
+	l.Lock()
+	defer l.Unlock()
+	l.timestampColor = color
+	return l
+}
+
+// SetTextColor sets the color of the log messages.
+func (l *LogView) SetTextColor(color tcell.Color) *LogView {
+	l.Lock()
+	defer l.Unlock()
+	l.textColor = color
+	return l
+}
+
+// SetMatchTextColor sets the text color of search matches.
+func (l *LogView) SetMatchTextColor(color tcell.Color) *LogView {
+	l.Lock()
+	defer l.Unlock()
+	l.matchTextColor = color
+	return l
+}
+
+// SetMatchBackgroundColor sets the background color of search matches.
+func (l *LogView) SetMatchBackgroundColor(color tcell.Color) *LogView {
+	l.Lock()
+	defer l.Unlock()
+	l.matchBackgroundColor = color
+	return l
+}
+
+// SetChangedFunc sets a handler function which is called when entries were
+// added. This handler may be called from a different goroutine. It will
+// usually call Application.Draw().
+func (l *LogView) SetChangedFunc(handler func()) *LogView {
+	l.Lock()
+	defer l.Unlock()
+	l.changed = handler
+	return l
+}
+
+// SetDoneFunc sets a handler which is called when the user presses the
+// Escape, Tab, or Backtab key (unless a search is being typed).
+func (l *LogView) SetDoneFunc(handler func(key tcell.Key)) *LogView {
+	l.done = handler
+	return l
+}
+
+// ScrollToBeginning scrolls to the oldest entry.
+func (l *LogView) ScrollToBeginning() *LogView {
+	l.Lock()
+	defer l.Unlock()
+	l.lineOffset, l.follow = 0, false
+	return l
+}
+
+// ScrollToEnd scrolls to the newest entry and follows new entries.
+func (l *LogView) ScrollToEnd() *LogView {
+	l.Lock()
+	defer l.Unlock()
+	l.follow = true
+	return l
+}
+
+// visibleEntries returns the entries which pass the level filter and which
+// were added before the view was paused. The caller must hold the lock.
+func (l *LogView) visibleEntries() []*logEntry {
+	visible := make([]*logEntry, 0, len(l.entries))
+	for index := range l.entries {
+		entry := &l.entries[index]
+		if entry.Level < l.minLevel || l.paused && entry.sequence >= l.pausedUntil {
+			continue
+		}
+		visible = append(visible, entry)
+	}
+	return visible
+}
+
+// matches returns whether or not the given entry contains the search text.
+func (l *LogView) matches(entry *logEntry) bool {
+	return l.search != "" && strings.Contains(strings.ToLower(entry.Message), strings.ToLower(l.search))
+}
+
+// findMatch scrolls to the next (or previous if "forward" is false) entry
+// containing the search text. The caller must hold the lock.
+func (l *LogView) findMatch(forward bool) {
+	entries := l.visibleEntries()
+	for step := 1; step <= len(entries); step++ {
+		index := l.lineOffset - step
+		if forward {
+			index = l.lineOffset + step
+		}
+		if index < 0 || index >= len(entries) {
+			break
+		}
+		if l.matches(entries[index]) {
+			l.lineOffset, l.follow = index, false
+			return
+		}
+	}
+}
+
+// applyTheme is called when the application's theme changes.
+func (l *LogView) applyTheme(from, to *Theme) {
+	l.Box.applyTheme(from, to)
+	themeColor(&l.timestampColor, from.TertiaryTextColor, to.TertiaryTextColor)
+	themeColor(&l.textColor, from.PrimaryTextColor, to.PrimaryTextColor)
+	themeColor(&l.matchTextColor, from.InverseTextColor, to.InverseTextColor)
+	themeColor(&l.matchBackgroundColor, from.SecondaryTextColor, to.SecondaryTextColor)
+}
+
+// Draw draws this primitive onto the screen.
+func (l *LogView) Draw(screen tcell.Screen) {
+	defer l.applyStyle(&l.textColor)()
+	l.Box.Draw(screen)
+	l.Lock()
+	defer l.Unlock()
+
+	x, y, width, height := l.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	// The status line.
+	entries := l.visibleEntries()
+	var status string
+	if l.paused {
+		status = fmt.Sprintf("Paused (%d new)", l.nextSequence-l.pausedUntil)
+	}
+	if l.searching || l.search != "" {
+		if status != "" {
+			status += " "
+		}
+		status += "/" + Escape(l.search)
+		if l.searching {
+			status += "_"
+		}
+	}
+	if status != "" {
+		height--
+		Print(screen, status, x, y+height, width, AlignLeft, l.textColor)
+	}
+	l.pageSize = height
+
+	// Adjust the scroll position.
+	if l.follow || l.lineOffset > len(entries)-height {
+		l.lineOffset = len(entries) - height
+	}
+	if l.lineOffset < 0 {
+		l.lineOffset = 0
+	}
+	if l.columnOffset < 0 {
+		l.columnOffset = 0
+	}
+
+	// Draw the entries.
+	search := []rune(strings.ToLower(l.search))
+	for row := 0; row < height && l.lineOffset+row < len(entries); row++ {
+		entry := entries[l.lineOffset+row]
+
+		// Compose the line, with a color for each rune.
+		var (
+			line   []rune
+			colors []tcell.Color
+		)
+		add := func(text string, color tcell.Color) {
+			for _, r := range text {
+				line = append(line, r)
+				colors = append(colors, color)
+			}
+		}
+		if l.timestampFormat != "" {
+			add(entry.Time.Format(l.timestampFormat)+" ", l.timestampColor)
+		}
+		add(logLevelLabels[entry.Level]+" ", l.levelColors[entry.Level])
+		messageStart := len(line)
+		add(strings.Replace(entry.Message, "\t", strings.Repeat(" ", TabSize), -1), l.textColor)
+
+		matched := findMatches(line, messageStart, search)
+
+		// Print the line.
+		var column int
+		for index, r := range line {
+			runeWidth := RuneWidth(r)
+			if runeWidth == 0 {
+				continue
+			}
+			if column < l.columnOffset {
+				column += runeWidth
+				continue
+			}
+			screenX := x + column - l.columnOffset
+			if screenX+runeWidth > x+width {
+				break
+			}
+			style := tcell.StyleDefault.Foreground(colors[index]).Background(l.backgroundColor)
+			if matched[index] {
+				style = style.Foreground(l.matchTextColor).Background(l.matchBackgroundColor)
+			}
+			screen.SetContent(screenX, y+row, r, nil, style)
+			column += runeWidth
+		}
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (l *LogView) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return l.wrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		l.Lock()
+
+		// Typing a search.
+		if l.searching {
+			defer l.Unlock()
+			switch event.Key() {
+			case tcell.KeyRune:
+				l.search += string(event.Rune())
+			case tcell.KeyBackspace, tcell.KeyBackspace2:
+				if l.search != "" {
+					runes := []rune(l.search)
+					l.search = string(runes[:len(runes)-1])
+				}
+			case tcell.KeyEnter:
+				l.searching = false
+				if entries := l.visibleEntries(); l.lineOffset >= len(entries) || !l.matches(entries[l.lineOffset]) {
+					l.findMatch(true)
+				}
+			case tcell.KeyEscape:
+				l.searching = false
+				l.search = ""
+			}
+			return
+		}
+
+		up := func(lines int) {
+			l.lineOffset -= lines
+			l.follow = false
+		}
+		down := func(lines int) {
+			l.lineOffset += lines
+			if l.lineOffset >= len(l.visibleEntries())-l.pageSize {
+				l.follow = true
+			}
+		}
+		key := event.Key()
+		switch key {
+		case tcell.KeyUp:
+			up(1)
+		case tcell.KeyDown:
+			down(1)
+		case tcell.KeyLeft:
+			l.columnOffset--
+		case tcell.KeyRight:
+			l.columnOffset++
+		case tcell.KeyPgUp, tcell.KeyCtrlB:
+			up(l.pageSize)
+		case tcell.KeyPgDn, tcell.KeyCtrlF:
+			down(l.pageSize)
+		case tcell.KeyHome:
+			l.lineOffset, l.follow = 0, false
+		case tcell.KeyEnd:
+			l.follow = true
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'k':
+				up(1)
+			case 'j':
+				down(1)
+			case 'h':
+				l.columnOffset--
+			case 'l':
+				l.columnOffset++
+			case 'g':
+				l.lineOffset, l.follow = 0, false
+			case 'G':
+				l.follow = true
+			case 'p', ' ':
+				if l.paused {
+					l.paused = false
+				} else {
+					l.paused, l.pausedUntil = true, l.nextSequence
+				}
+			case '+':
+				if l.minLevel < LogError {
+					l.minLevel++
+				}
+			case '-':
+				if l.minLevel > LogDebug {
+					l.minLevel--
+				}
+			case '/':
+				l.searching = true
+				l.search = ""
+			case 'n':
+				l.findMatch(true)
+			case 'N':
+				l.findMatch(false)
+			}
+		}
+		l.Unlock()
+
+		switch key {
+		case tcell.KeyEscape, tcell.KeyTab, tcell.KeyBacktab:
+			if l.done != nil {
+				l.done(key)
+			}
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (l *LogView) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return l.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if !l.InRect(event.Position()) {
+			return false, nil
+		}
+		l.Lock()
+		defer l.Unlock()
+		switch action {
+		case MouseLeftClick:
+			setFocus(l)
+		case MouseScrollUp:
+			l.lineOffset -= 3
+			l.follow = false
+		case MouseScrollDown:
+			l.lineOffset += 3
+			if l.lineOffset >= len(l.visibleEntries())-l.pageSize {
+				l.follow = true
+			}
+		case MouseScrollLeft:
+			l.columnOffset -= 3
+		case MouseScrollRight:
+			l.columnOffset += 3
+		}
+		return true, nil
+	})
+}
@@ -0,0 +1,502 @@
+package tview
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/gdamore/tcell"
+)
+
+// menuItem represents one entry of a Menu.
+type menuItem struct {
+	Label     string // The item's text. May contain color tags.
+	Shortcut  string // A hint for the item's keyboard shortcut, shown right-aligned.
+	Disabled  bool   // If true, the item cannot be selected.
+	Separator bool   // If true, the item is a separator line.
+	Submenu   *Menu  // The menu opened by this item, nil if it has no submenu.
+	Selected  func() // The optional function which is called when the item is selected.
+}
+
+// Menu is a list of items which is shown as a pull-down menu by a MenuBar or
+// as a popup menu by other primitives. A menu item has a label, an optional
+// shortcut hint which is displayed right-aligned, and a function which is
+// called when the user selects the item. Note that the shortcut hint is only
+// text. The application itself is responsible for handling the shortcut key.
+//
+// Items may also be disabled or open another menu (a submenu). Groups of items
+// can be separated with separator lines.
+//
+// When a menu is open, it is navigated with the following keys:
+//
+//   - Up arrow, Down arrow: Select the previous/next item.
+//   - Home, End: Select the first/last item.
+//   - Enter, Space: Select the current item or open its submenu.
+//   - Right arrow: Open the current item's submenu.
+//   - Left arrow, Escape: Close the submenu.
+//   - Any other character: Select the next item whose label starts with that
+//     character.
+type Menu struct {
+	// The menu's title.
+	title string
+
+	// The key which, pressed together with the Alt key, opens this menu in a
+	// menu bar. 0 if there is no such key.
+	hotkey rune
+
+	// The menu's items.
+	items []*menuItem
+
+	// The index of the currently selected item.
+	currentItem int
+
+	// The position of the menu the last time it was drawn.
+	x, y, width, height int
+}
+
+// NewMenu returns a new menu with the given title (which may contain color
+// tags). The title is displayed in a menu bar. The first letter of the title
+// becomes the menu's hotkey (see SetHotkey()).
+func NewMenu(title string) *Menu {
+	m := &Menu{title: title}
+	for _, ch := range StripTags(title) {
+		if unicode.IsLetter(ch) || unicode.IsDigit(ch) {
+			m.hotkey = unicode.ToLower(ch)
+			break
+		}
+	}
+	return m
+}
+
+// SetTitle sets the menu's title.
+func (m *Menu) SetTitle(title string) *Menu {
+	m.title = title
+	return m
+}
+
+// GetTitle returns the menu's title.
+func (m *Menu) GetTitle() string {
+	return m.title
+}
+
+// SetHotkey sets the key which, pressed together with the Alt key, opens this
+// menu in a menu bar. This is the first letter of the title by default. Set to
+// 0 if the menu should have no hotkey.
+func (m *Menu) SetHotkey(hotkey rune) *Menu {
+	m.hotkey = unicode.ToLower(hotkey)
+	return m
+}
+
+// AddItem adds a new item to the menu. The label is the item's text. The
+// shortcut is a hint for the keyboard shortcut which triggers the same action
+// as the item. It is shown right-aligned and may be left empty. The "selected"
+// function is called when the user selects the item. It may be nil.
+func (m *Menu) AddItem(label, shortcut string, selected func()) *Menu {
+	m.items = append(m.items, &menuItem{
+		Label:    label,
+		Shortcut: shortcut,
+		Selected: selected,
+	})
+	return m
+}
+
+// AddSubmenu adds a new item to the menu which opens the given submenu when
+// selected.
+func (m *Menu) AddSubmenu(label string, submenu *Menu) *Menu {
+	m.items = append(m.items, &menuItem{
+		Label:   label,
+		Submenu: submenu,
+	})
+	return m
+}
+
+// AddSeparator adds a separator line to the menu.
+func (m *Menu) AddSeparator() *Menu {
+	m.items = append(m.items, &menuItem{Separator: true})
+	return m
+}
+
+// SetItemDisabled sets whether or not the item with the given index (starting
+// at 0, separators included) is disabled. Disabled items are shown in a
+// different color and cannot be selected.
+func (m *Menu) SetItemDisabled(index int, disabled bool) *Menu {
+	if index >= 0 && index < len(m.items) {
+		m.items[index].Disabled = disabled
+	}
+	return m
+}
+
+// GetItemCount returns the number of items in the menu, including separators.
+func (m *Menu) GetItemCount() int {
+	return len(m.items)
+}
+
+// Clear removes all items from the menu.
+func (m *Menu) Clear() *Menu {
+	m.items = nil
+	m.currentItem = 0
+	return m
+}
+
+// selectable returns whether or not the item with the given index can be
+// selected.
+func (m *Menu) selectable(index int) bool {
+	item := m.items[index]
+	return !item.Separator && !item.Disabled
+}
+
+// resetSelection selects the first selectable item.
+func (m *Menu) resetSelection() {
+	m.currentItem = -1
+	m.moveSelection(1)
+}
+
+// moveSelection moves the selection by "delta" (1 or -1) to the next
+// selectable item, wrapping around at the ends. If no item is selectable, the
+// current item will be -1.
+func (m *Menu) moveSelection(delta int) {
+	if len(m.items) == 0 {
+		m.currentItem = -1
+		return
+	}
+	index := m.currentItem
+	for range m.items {
+		index = (index + delta + len(m.items)) % len(m.items)
+		if m.selectable(index) {
+			m.currentItem = index
+			return
+		}
+	}
+	m.currentItem = -1
+}
+
+// itemAt returns the index of the item at the given screen position the last
+// time the menu was drawn. -1 is returned if there is no item at this
+// position.
+func (m *Menu) itemAt(x, y int) int {
+	if x <= m.x || x >= m.x+m.width-1 || y <= m.y || y >= m.y+m.height-1 {
+		return -1
+	}
+	index := y - m.y - 1
+	if index >= len(m.items) {
+		return -1
+	}
+	return index
+}
+
+// inRect returns true if the given screen position lies within the menu the
+// last time it was drawn.
+func (m *Menu) inRect(x, y int) bool {
+	return x >= m.x && x < m.x+m.width && y >= m.y && y < m.y+m.height
+}
+
+// menuColors holds the colors used to draw menus.
+type menuColors struct {
+	// The menu's background color.
+	backgroundColor tcell.Color
+
+	// The color of the menu's border and of separators.
+	borderColor tcell.Color
+
+	// The text color of item labels.
+	textColor tcell.Color
+
+	// The color of the shortcut hints.
+	shortcutColor tcell.Color
+
+	// The text color of disabled items.
+	disabledTextColor tcell.Color
+
+	// The text and background colors of the currently selected item.
+	selectedTextColor, selectedBackgroundColor tcell.Color
+}
+
+// defaultMenuColors returns the menu colors derived from the given theme.
+func defaultMenuColors(styles *Theme) menuColors {
+	return menuColors{
+		backgroundColor:         styles.ContrastBackgroundColor,
+		borderColor:             styles.BorderColor,
+		textColor:               styles.PrimaryTextColor,
+		shortcutColor:           styles.SecondaryTextColor,
+		disabledTextColor:       styles.TertiaryTextColor,
+		selectedTextColor:       styles.InverseTextColor,
+		selectedBackgroundColor: styles.PrimaryTextColor,
+	}
+}
+
+// applyTheme replaces the colors taken from the "from" theme with the
+// corresponding colors of the "to" theme.
+func (c *menuColors) applyTheme(from, to *Theme) {
+	themeColor(&c.backgroundColor, from.ContrastBackgroundColor, to.ContrastBackgroundColor)
+	themeColor(&c.borderColor, from.BorderColor, to.BorderColor)
+	themeColor(&c.textColor, from.PrimaryTextColor, to.PrimaryTextColor)
+	themeColor(&c.shortcutColor, from.SecondaryTextColor, to.SecondaryTextColor)
+	themeColor(&c.disabledTextColor, from.TertiaryTextColor, to.TertiaryTextColor)
+	themeColor(&c.selectedTextColor, from.InverseTextColor, to.InverseTextColor)
+	themeColor(&c.selectedBackgroundColor, from.PrimaryTextColor, to.PrimaryTextColor)
+}
+
+// draw draws the menu as a popup with its top-left corner at the given screen
+// position. If the menu does not fit on the screen at that position, it is
+// moved such that it does.
+func (m *Menu) draw(screen tcell.Screen, x, y int, colors *menuColors) {
+	// Determine the menu's size.
+	var labelWidth, shortcutWidth int
+	var hasSubmenus bool
+	for _, item := range m.items {
+		if w := StringWidth(item.Label); w > labelWidth {
+			labelWidth = w
+		}
+		if w := StringWidth(item.Shortcut); w > shortcutWidth {
+			shortcutWidth = w
+		}
+		if item.Submenu != nil {
+			hasSubmenus = true
+		}
+	}
+	width := labelWidth + 4
+	if shortcutWidth > 0 {
+		width += shortcutWidth + 2
+	}
+	if hasSubmenus {
+		width += 2
+	}
+	height := len(m.items) + 2
+
+	// Make it fit on the screen.
+	screenWidth, screenHeight := screen.Size()
+	if x+width > screenWidth {
+		x = screenWidth - width
+	}
+	if y+height > screenHeight {
+		y = screenHeight - height
+	}
+	if x < 0 {
+		x = 0
+	}
+	if y < 0 {
+		y = 0
+	}
+	m.x, m.y, m.width, m.height = x, y, width, height
+
+	// Draw the background and the border.
+	background := tcell.StyleDefault.Background(colors.backgroundColor)
+	border := background.Foreground(colors.borderColor)
+	for row := 0; row < height; row++ {
+		for column := 0; column < width; column++ {
+			ch := ' '
+			switch {
+			case row == 0 && column == 0:
+				ch = Styles.Borders.TopLeft
+			case row == 0 && column == width-1:
+				ch = Styles.Borders.TopRight
+			case row == height-1 && column == 0:
+				ch = Styles.Borders.BottomLeft
+			case row == height-1 && column == width-1:
+				ch = Styles.Borders.BottomRight
+			case row == 0 || row == height-1:
+				ch = Styles.Borders.Horizontal
+			case column == 0 || column == width-1:
+				ch = Styles.Borders.Vertical
+			}
+			screen.SetContent(x+column, y+row, ch, nil, border)
+		}
+	}
+
+	// Draw the items.
+	for index, item := range m.items {
+		itemY := y + 1 + index
+		if item.Separator {
+			screen.SetContent(x, itemY, GraphicsLeftT, nil, border)
+			for column := 1; column < width-1; column++ {
+				screen.SetContent(x+column, itemY, GraphicsHoriBar, nil, border)
+			}
+			screen.SetContent(x+width-1, itemY, GraphicsRightT, nil, border)
+			continue
+		}
+
+		textColor, shortcutColor := colors.textColor, colors.shortcutColor
+		if item.Disabled {
+			textColor, shortcutColor = colors.disabledTextColor, colors.disabledTextColor
+		} else if index == m.currentItem {
+			textColor, shortcutColor = colors.selectedTextColor, colors.selectedTextColor
+			selected := tcell.StyleDefault.Background(colors.selectedBackgroundColor)
+			for column := 1; column < width-1; column++ {
+				screen.SetContent(x+column, itemY, ' ', nil, selected)
+			}
+		}
+		Print(screen, item.Label, x+2, itemY, labelWidth, AlignLeft, textColor)
+		right := x + width - 2
+		if hasSubmenus {
+			right -= 2
+		}
+		if item.Shortcut != "" {
+			Print(screen, item.Shortcut, right-shortcutWidth, itemY, shortcutWidth, AlignRight, shortcutColor)
+		}
+		if item.Submenu != nil {
+			Print(screen, "▸", x+width-3, itemY, 1, AlignLeft, textColor)
+		}
+	}
+}
+
+// Possible results of processing events for open menus.
+const (
+	menuNone     = iota // Nothing to be done by the owner.
+	menuClose           // All menus are to be closed.
+	menuSelected        // An item was selected. All menus are to be closed.
+	menuLeft            // The user wants to move to the menu on the left.
+	menuRight           // The user wants to move to the menu on the right.
+)
+
+// menuPopups manages a stack of open menus. The first menu is the top-level
+// menu, every other menu is a submenu opened from the menu preceding it.
+type menuPopups struct {
+	// The open menus.
+	menus []*Menu
+}
+
+// open closes all open menus and opens the given menu.
+func (p *menuPopups) open(menu *Menu) {
+	menu.resetSelection()
+	p.menus = []*Menu{menu}
+}
+
+// close closes all menus.
+func (p *menuPopups) close() {
+	p.menus = nil
+}
+
+// isOpen returns true if at least one menu is open.
+func (p *menuPopups) isOpen() bool {
+	return len(p.menus) > 0
+}
+
+// draw draws all open menus, the top-level menu at the given position, each
+// submenu next to the item which opened it.
+func (p *menuPopups) draw(screen tcell.Screen, x, y int, colors *menuColors) {
+	for index, menu := range p.menus {
+		if index > 0 {
+			parent := p.menus[index-1]
+			x = parent.x + parent.width - 1
+			y = parent.y + parent.currentItem
+			screenWidth, _ := screen.Size()
+			if width := menu.width; width > 0 && x+width > screenWidth && parent.x-width+1 >= 0 {
+				x = parent.x - width + 1 // Open to the left if there's no space on the right.
+			}
+		}
+		menu.draw(screen, x, y, colors)
+	}
+}
+
+// activate selects the current item of the topmost menu. If it has a submenu,
+// the submenu is opened. Otherwise menuSelected and the item's callback are
+// returned.
+func (p *menuPopups) activate() (int, func()) {
+	menu := p.menus[len(p.menus)-1]
+	if menu.currentItem < 0 || menu.currentItem >= len(menu.items) || !menu.selectable(menu.currentItem) {
+		return menuNone, nil
+	}
+	item := menu.items[menu.currentItem]
+	if item.Submenu != nil {
+		item.Submenu.resetSelection()
+		p.menus = append(p.menus, item.Submenu)
+		return menuNone, nil
+	}
+	return menuSelected, item.Selected
+}
+
+// input processes a key event for the open menus. It returns one of the menu
+// result constants and, for menuSelected, the selected item's callback (which
+// may be nil).
+func (p *menuPopups) input(event *tcell.EventKey) (int, func()) {
+	if !p.isOpen() {
+		return menuNone, nil
+	}
+	menu := p.menus[len(p.menus)-1]
+	switch event.Key() {
+	case tcell.KeyUp, tcell.KeyBacktab:
+		menu.moveSelection(-1)
+	case tcell.KeyDown, tcell.KeyTab:
+		menu.moveSelection(1)
+	case tcell.KeyHome:
+		menu.currentItem = -1
+		menu.moveSelection(1)
+	case tcell.KeyEnd:
+		menu.currentItem = len(menu.items)
+		menu.moveSelection(-1)
+	case tcell.KeyEnter:
+		return p.activate()
+	case tcell.KeyRight:
+		if menu.currentItem >= 0 && menu.items[menu.currentItem].Submenu != nil {
+			return p.activate()
+		}
+		return menuRight, nil
+	case tcell.KeyLeft:
+		if len(p.menus) > 1 {
+			p.menus = p.menus[:len(p.menus)-1]
+			return menuNone, nil
+		}
+		return menuLeft, nil
+	case tcell.KeyEscape:
+		if len(p.menus) > 1 {
+			p.menus = p.menus[:len(p.menus)-1]
+			return menuNone, nil
+		}
+		return menuClose, nil
+	case tcell.KeyRune:
+		ch := event.Rune()
+		if ch == ' ' {
+			return p.activate()
+		}
+
+		// Jump to the next item starting with this character.
+		ch = unicode.ToLower(ch)
+		for offset := 1; offset <= len(menu.items); offset++ {
+			index := (menu.currentItem + offset) % len(menu.items)
+			if index < 0 || !menu.selectable(index) {
+				continue
+			}
+			label := strings.TrimSpace(StripTags(menu.items[index].Label))
+			if label != "" && unicode.ToLower([]rune(label)[0]) == ch {
+				menu.currentItem = index
+				break
+			}
+		}
+	}
+	return menuNone, nil
+}
+
+// mouse processes a mouse action for the open menus at the given screen
+// position. It returns one of the menu result constants, the selected item's
+// callback (for menuSelected), and whether or not the position was inside one
+// of the open menus.
+func (p *menuPopups) mouse(action MouseAction, x, y int) (result int, selected func(), inside bool) {
+	// Find the topmost menu at this position.
+	level := -1
+	for index := len(p.menus) - 1; index >= 0; index-- {
+		if p.menus[index].inRect(x, y) {
+			level = index
+			break
+		}
+	}
+	if level < 0 {
+		return menuNone, nil, false
+	}
+	menu := p.menus[level]
+	index := menu.itemAt(x, y)
+	if index < 0 || !menu.selectable(index) {
+		return menuNone, nil, true
+	}
+
+	switch action {
+	case MouseMove:
+		if menu.currentItem != index {
+			menu.currentItem = index
+			p.menus = p.menus[:level+1] // Close any submenus.
+		}
+	case MouseLeftClick:
+		menu.currentItem = index
+		p.menus = p.menus[:level+1]
+		result, selected = p.activate()
+	}
+	return result, selected, true
+}
@@ -0,0 +1,424 @@
+package tview
+
+import (
+	"unicode"
+
+	"github.com/gdamore/tcell"
+)
+
+// MenuBar is a row of menu titles (e.g. "File", "Edit", "Help"), each of which
+// opens a pull-down Menu.
+//
+// A menu is opened by clicking on its title or by pressing the Alt key
+// together with the menu's hotkey (see Menu.SetHotkey()). As the menu bar only
+// receives key events when it has focus, applications which want these
+// shortcuts to work everywhere should forward key events to HandleShortcut()
+// from their input capture function, moving the focus to the menu bar when it
+// returns true:
+//
+//   app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+//     if menuBar.HandleShortcut(event) {
+//       app.SetFocus(menuBar)
+//       return nil
+//     }
+//     return event
+//   })
+//
+// When the menu bar has focus, the following keys are available in addition
+// to the ones described for Menu:
+//
+//   - Left arrow, Right arrow: Move to the previous/next menu.
+//   - Enter, Down arrow: Open the highlighted menu.
+//   - Escape: Close the menus.
+//
+// Once the menus are closed, either because the user selected an item or
+// because they were dismissed, the "done" handler is called (see
+// SetDoneFunc()). It usually returns the focus to where it was before.
+type MenuBar struct {
+	*Box
+
+	// The menus.
+	menus []*Menu
+
+	// The index of the highlighted menu. -1 if none.
+	currentMenu int
+
+	// The currently open menus.
+	popups menuPopups
+
+	// The horizontal screen position and width of each menu title the last
+	// time the menu bar was drawn.
+	titleX, titleWidth []int
+
+	// The text color of the menu titles.
+	textColor tcell.Color
+
+	// The color of the menu titles' hotkeys.
+	hotkeyColor tcell.Color
+
+	// The text and background colors of the highlighted menu title.
+	selectedTextColor, selectedBackgroundColor tcell.Color
+
+	// The colors of the pull-down menus.
+	menuColors menuColors
+
+	// An optional function which is called when the menus were closed. The key
+	// is tcell.KeyEnter if an item was selected, tcell.KeyEscape otherwise.
+	done func(key tcell.Key)
+}
+
+// NewMenuBar returns a new, empty menu bar.
+func NewMenuBar() *MenuBar {
+	styles := Styles.forWidget("MenuBar")
+	m := &MenuBar{
+		Box:                     NewBox().SetBackgroundColor(styles.ContrastBackgroundColor),
+		currentMenu:             -1,
+		textColor:               styles.PrimaryTextColor,
+		hotkeyColor:             styles.SecondaryTextColor,
+		selectedTextColor:       styles.InverseTextColor,
+		selectedBackgroundColor: styles.PrimaryTextColor,
+		menuColors:              defaultMenuColors(styles),
+	}
+	m.focus = m
+	return m
+}
+
+// AddMenu adds a menu to the right end of the menu bar.
+func (m *MenuBar) AddMenu(menu *Menu) *MenuBar {
+	m.menus = append(m.menus, menu)
+	return m
+}
+
+// GetMenuCount returns the number of menus in the menu bar.
+func (m *MenuBar) GetMenuCount() int {
+	return len(m.menus)
+}
+
+// Clear removes all menus from the menu bar.
+func (m *MenuBar) Clear() *MenuBar {
+	m.menus = nil
+	m.currentMenu = -1
+	m.popups.close()
+	return m
+}
+
+// SetTextColor sets the text color of the menu titles.
+func (m *MenuBar) SetTextColor(color tcell.Color) *MenuBar {
+	m.textColor = color
+	return m
+}
+
+// SetHotkeyColor sets the color of the menu titles' hotkey characters.
+func (m *MenuBar) SetHotkeyColor(color tcell.Color) *MenuBar {
+	m.hotkeyColor = color
+	return m
+}
+
+// SetSelectedTextColor sets the text color of the highlighted menu title and
+// of the selected menu items.
+func (m *MenuBar) SetSelectedTextColor(color tcell.Color) *MenuBar {
+	m.selectedTextColor = color
+	m.menuColors.selectedTextColor = color
+	return m
+}
+
+// SetSelectedBackgroundColor sets the background color of the highlighted
+// menu title and of the selected menu items.
+func (m *MenuBar) SetSelectedBackgroundColor(color tcell.Color) *MenuBar {
+	m.selectedBackgroundColor = color
+	m.menuColors.selectedBackgroundColor = color
+	return m
+}
+
+// SetMenuBackgroundColor sets the background color of the pull-down menus.
+func (m *MenuBar) SetMenuBackgroundColor(color tcell.Color) *MenuBar {
+	m.menuColors.backgroundColor = color
+	return m
+}
+
+// SetMenuBorderColor sets the color of the pull-down menus' borders and
+// separators.
+func (m *MenuBar) SetMenuBorderColor(color tcell.Color) *MenuBar {
+	m.menuColors.borderColor = color
+	return m
+}
+
+// SetMenuTextColor sets the text color of the menu items.
+func (m *MenuBar) SetMenuTextColor(color tcell.Color) *MenuBar {
+	m.menuColors.textColor = color
+	return m
+}
+
+// SetShortcutColor sets the color of the menu items' shortcut hints.
+func (m *MenuBar) SetShortcutColor(color tcell.Color) *MenuBar {
+	m.menuColors.shortcutColor = color
+	return m
+}
+
+// SetDisabledTextColor sets the text color of disabled menu items.
+func (m *MenuBar) SetDisabledTextColor(color tcell.Color) *MenuBar {
+	m.menuColors.disabledTextColor = color
+	return m
+}
+
+// SetDoneFunc sets a handler which is called when the menus were closed. The
+// key is tcell.KeyEnter if the user selected an item (the handler is called
+// before the item's own callback) and tcell.KeyEscape if the menus were
+// dismissed.
+func (m *MenuBar) SetDoneFunc(handler func(key tcell.Key)) *MenuBar {
+	m.done = handler
+	return m
+}
+
+// OpenMenu opens the menu with the given index (starting at 0). Note that the
+// menu bar must have focus for the menu to be shown.
+func (m *MenuBar) OpenMenu(index int) *MenuBar {
+	if index < 0 || index >= len(m.menus) {
+		return m
+	}
+	m.currentMenu = index
+	m.popups.open(m.menus[index])
+	return m
+}
+
+// CloseMenus closes all open menus without calling the "done" handler.
+func (m *MenuBar) CloseMenus() *MenuBar {
+	m.popups.close()
+	return m
+}
+
+// IsOpen returns true if a menu is currently open.
+func (m *MenuBar) IsOpen() bool {
+	return m.popups.isOpen()
+}
+
+// HandleShortcut opens the menu whose hotkey corresponds to the given key
+// event, i.e. an Alt+letter combination. It returns true if a menu was opened.
+// The caller is then responsible for moving the focus to the menu bar.
+func (m *MenuBar) HandleShortcut(event *tcell.EventKey) bool {
+	if event.Key() != tcell.KeyRune || event.Modifiers()&tcell.ModAlt == 0 {
+		return false
+	}
+	ch := unicode.ToLower(event.Rune())
+	for index, menu := range m.menus {
+		if menu.hotkey != 0 && menu.hotkey == ch {
+			m.OpenMenu(index)
+			return true
+		}
+	}
+	return false
+}
+
+// finish closes all menus and calls the "done" handler, followed by the given
+// callback (which may be nil).
+func (m *MenuBar) finish(key tcell.Key, selected func()) {
+	m.popups.close()
+	if m.done != nil {
+		m.done(key)
+	}
+	if selected != nil {
+		selected()
+	}
+}
+
+// handleResult processes the result of an event handled by the open menus.
+func (m *MenuBar) handleResult(result int, selected func()) {
+	switch result {
+	case menuClose:
+		m.finish(tcell.KeyEscape, nil)
+	case menuSelected:
+		m.finish(tcell.KeyEnter, selected)
+	case menuLeft:
+		if len(m.menus) > 0 {
+			m.OpenMenu((m.currentMenu - 1 + len(m.menus)) % len(m.menus))
+		}
+	case menuRight:
+		if len(m.menus) > 0 {
+			m.OpenMenu((m.currentMenu + 1) % len(m.menus))
+		}
+	}
+}
+
+// titleAt returns the index of the menu title at the given screen position or
+// -1 if there is none.
+func (m *MenuBar) titleAt(x, y int) int {
+	_, rectY, _, _ := m.GetInnerRect()
+	if y != rectY {
+		return -1
+	}
+	for index := range m.titleX {
+		if x >= m.titleX[index] && x < m.titleX[index]+m.titleWidth[index] {
+			return index
+		}
+	}
+	return -1
+}
+
+// applyTheme is called when the application's theme changes.
+func (m *MenuBar) applyTheme(from, to *Theme) {
+	m.Box.applyTheme(from, to)
+	themeColor(&m.backgroundColor, from.ContrastBackgroundColor, to.ContrastBackgroundColor)
+	m.menuColors.applyTheme(from, to)
+	themeColor(&m.textColor, from.PrimaryTextColor, to.PrimaryTextColor)
+	themeColor(&m.hotkeyColor, from.SecondaryTextColor, to.SecondaryTextColor)
+	themeColor(&m.selectedTextColor, from.InverseTextColor, to.InverseTextColor)
+	themeColor(&m.selectedBackgroundColor, from.PrimaryTextColor, to.PrimaryTextColor)
+}
+
+// Draw draws this primitive onto the screen.
+func (m *MenuBar) Draw(screen tcell.Screen) {
+	defer m.applyStyle(&m.textColor)()
+	m.Box.Draw(screen)
+	x, y, width, height := m.GetInnerRect()
+	if height <= 0 {
+		return
+	}
+	hasFocus := m.HasFocus()
+	if !hasFocus {
+		m.popups.close()
+	}
+
+	// Draw the menu titles.
+	m.titleX, m.titleWidth = m.titleX[:0], m.titleWidth[:0]
+	right := x + width
+	for index, menu := range m.menus {
+		titleWidth := StringWidth(menu.title) + 2
+		if x+titleWidth > right {
+			titleWidth = right - x
+		}
+		m.titleX = append(m.titleX, x)
+		m.titleWidth = append(m.titleWidth, titleWidth)
+		if titleWidth <= 0 {
+			continue
+		}
+
+		textColor, backgroundColor := m.textColor, m.backgroundColor
+		if index == m.currentMenu && hasFocus {
+			textColor, backgroundColor = m.selectedTextColor, m.selectedBackgroundColor
+			style := tcell.StyleDefault.Background(backgroundColor)
+			for column := 0; column < titleWidth; column++ {
+				screen.SetContent(x+column, y, ' ', nil, style)
+			}
+		}
+		Print(screen, menu.title, x+1, y, titleWidth-1, AlignLeft, textColor)
+
+		// Highlight the hotkey.
+		if menu.hotkey != 0 {
+			hotkeyX := x + 1
+			for _, ch := range StripTags(menu.title) {
+				if unicode.ToLower(ch) == menu.hotkey {
+					if hotkeyX < x+titleWidth {
+						screen.SetContent(hotkeyX, y, ch, nil, tcell.StyleDefault.Foreground(m.hotkeyColor).Background(backgroundColor))
+					}
+					break
+				}
+				hotkeyX += RuneWidth(ch)
+			}
+		}
+
+		x += titleWidth
+	}
+
+	// Draw the open menus.
+	if m.popups.isOpen() && m.currentMenu >= 0 && m.currentMenu < len(m.titleX) {
+		m.popups.draw(screen, m.titleX[m.currentMenu], y+1, &m.menuColors)
+	}
+}
+
+// drawsOutside returns whether a menu, which extends below the menu bar, is
+// open.
+func (m *MenuBar) drawsOutside() bool {
+	return m.popups.isOpen()
+}
+
+// Focus is called when this primitive receives focus.
+func (m *MenuBar) Focus(delegate func(p Primitive)) {
+	if m.currentMenu < 0 && len(m.menus) > 0 {
+		m.currentMenu = 0
+	}
+	m.Box.Focus(delegate)
+}
+
+// InputHandler returns the handler for this primitive.
+func (m *MenuBar) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return m.wrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		if len(m.menus) == 0 {
+			if m.done != nil {
+				m.done(tcell.KeyEscape)
+			}
+			return
+		}
+
+		// Alt+letter opens a menu, even if another one is open.
+		if m.HandleShortcut(event) {
+			return
+		}
+
+		// Forward keys to the open menus.
+		if m.popups.isOpen() {
+			m.handleResult(m.popups.input(event))
+			return
+		}
+
+		// Navigate the menu titles.
+		switch event.Key() {
+		case tcell.KeyLeft:
+			m.currentMenu = (m.currentMenu - 1 + len(m.menus)) % len(m.menus)
+		case tcell.KeyRight:
+			m.currentMenu = (m.currentMenu + 1) % len(m.menus)
+		case tcell.KeyEnter, tcell.KeyDown:
+			m.OpenMenu(m.currentMenu)
+		case tcell.KeyEscape:
+			m.finish(tcell.KeyEscape, nil)
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (m *MenuBar) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return m.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+
+		// While menus are open, we capture all mouse events.
+		if m.popups.isOpen() {
+			result, selected, inside := m.popups.mouse(action, x, y)
+			if inside {
+				m.handleResult(result, selected)
+			} else if index := m.titleAt(x, y); index >= 0 {
+				switch action {
+				case MouseMove:
+					if index != m.currentMenu {
+						m.OpenMenu(index) // Sliding along the menu bar.
+					}
+				case MouseLeftClick:
+					if index == m.currentMenu {
+						m.finish(tcell.KeyEscape, nil)
+					} else {
+						m.OpenMenu(index)
+					}
+				}
+			} else {
+				switch action {
+				case MouseLeftClick, MouseMiddleClick, MouseRightClick:
+					m.finish(tcell.KeyEscape, nil)
+				}
+			}
+			if m.popups.isOpen() {
+				capture = m
+			}
+			return true, capture
+		}
+
+		if !m.InRect(x, y) {
+			return false, nil
+		}
+		if action == MouseLeftClick {
+			if index := m.titleAt(x, y); index >= 0 {
+				setFocus(m)
+				m.OpenMenu(index)
+				return true, m
+			}
+		}
+		return true, nil
+	})
+}
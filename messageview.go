@@ -0,0 +1,496 @@
+package tview
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell"
+)
+
+// message is one message of a MessageView.
+type message struct {
+	Author string    // The message's author.
+	Text   string    // The message text.
+	Time   time.Time // The time the message was sent.
+	Align  int       // AlignLeft or AlignRight.
+}
+
+// messageLine is one screen line of a MessageView.
+type messageLine struct {
+	text                   string      // The line's text, may contain color tags.
+	timestamp              string      // A timestamp shown after the text in header lines.
+	x, width               int         // The bubble's position and width relative to the view, 0 width for lines without a bubble.
+	textX                  int         // The text's position relative to the view.
+	color, backgroundColor tcell.Color // The text and bubble color.
+	align                  int         // The alignment of the text within the view if there is no bubble.
+}
+
+// MessageView is a primitive for chat-style user interfaces. It shows a list
+// of messages, each with an author and a timestamp, as bubbles whose text is
+// wrapped to fit. Messages are aligned to the left or right, e.g. to
+// distinguish the user's own messages from those of others. A separator line
+// with the date is shown between messages of different days. Messages can be
+// added from any goroutine.
+//
+// The view sticks to the bottom as long as it is scrolled to the bottom. If the
+// user scrolled up, new messages don't move the view and an indicator at the
+// bottom shows the number of new messages.
+//
+// The following keys are available when the message view has focus:
+//
+//   - Up arrow, k: Scroll up by one line.
+//   - Down arrow, j: Scroll down by one line.
+//   - Page Up, Ctrl-B: Scroll up by one page.
+//   - Page Down, Ctrl-F: Scroll down by one page.
+//   - Home, g: Scroll to the top.
+//   - End, G: Scroll to the bottom.
+//   - Escape, Tab, Backtab: Finish (see SetDoneFunc()).
+type MessageView struct {
+	*Box
+	sync.Mutex
+
+	// The messages, oldest first.
+	messages []message
+
+	// The index of the first visible line.
+	lineOffset int
+
+	// If true, the view sticks to the bottom.
+	follow bool
+
+	// The number of messages added since the user scrolled away from the
+	// bottom.
+	unread int
+
+	// The number of lines and the number of visible lines the last time the
+	// view was drawn.
+	lineCount, pageSize int
+
+	// The maximum bubble width. If 0, bubbles take up to two thirds of the
+	// available width.
+	maxBubbleWidth int
+
+	// The layouts used for timestamps and day separators.
+	timestampFormat, dateFormat string
+
+	// The colors of authors, message texts, and timestamps.
+	authorColor, textColor, timestampColor tcell.Color
+
+	// The background colors of left-aligned and right-aligned bubbles.
+	leftBubbleColor, rightBubbleColor tcell.Color
+
+	// The colors of day separators and of the "new messages" indicator.
+	separatorColor, indicatorTextColor, indicatorBackgroundColor tcell.Color
+
+	// An optional function which is called when messages were added.
+	changed func()
+
+	// An optional function which is called when the user leaves the view.
+	done func(tcell.Key)
+}
+
+// NewMessageView returns a new, empty message view.
+func NewMessageView() *MessageView {
+	styles := Styles.forWidget("MessageView")
+	return &MessageView{
+		Box:                      NewBox(),
+		follow:                   true,
+		timestampFormat:          "15:04",
+		dateFormat:               "Monday, January 2, 2006",
+		authorColor:              styles.SecondaryTextColor,
+		textColor:                styles.PrimaryTextColor,
+		timestampColor:           styles.TertiaryTextColor,
+		leftBubbleColor:          styles.ContrastBackgroundColor,
+		rightBubbleColor:         styles.MoreContrastBackgroundColor,
+		separatorColor:           styles.TertiaryTextColor,
+		indicatorTextColor:       styles.InverseTextColor,
+		indicatorBackgroundColor: styles.SecondaryTextColor,
+	}
+}
+
+// AddMessage adds a message with the given author, text, and timestamp. The
+// alignment is either AlignLeft or AlignRight. The text may contain line
+// breaks but color tags are not interpreted.
+func (m *MessageView) AddMessage(author, text string, timestamp time.Time, align int) *MessageView {
+	if align != AlignRight {
+		align = AlignLeft
+	}
+	m.Lock()
+	m.messages = append(m.messages, message{Author: author, Text: text, Time: timestamp, Align: align})
+	if !m.follow {
+		m.unread++
+	}
+	changed := m.changed
+	m.Unlock()
+
+	if changed != nil {
+		changed()
+	}
+	return m
+}
+
+// Clear removes all messages.
+func (m *MessageView) Clear() *MessageView {
+	m.Lock()
+	defer m.Unlock()
+	m.messages = nil
+	m.lineOffset, m.unread, m.follow = 0, 0, true
+	return m
+}
+
+// GetMessageCount returns the number of messages.
+func (m *MessageView) GetMessageCount() int {
+	m.Lock()
+	defer m.Unlock()
+	return len(m.messages)
+}
+
+// GetUnreadCount returns the number of messages which were added since the
+// user scrolled away from the bottom.
+func (m *MessageView) GetUnreadCount() int {
+	m.Lock()
+	defer m.Unlock()
+	return m.unread
+}
+
+// SetMaxBubbleWidth sets the maximum width of message bubbles, including their
+// padding. If 0 (the default), bubbles take up to two thirds of the available
+// width.
+func (m *MessageView) SetMaxBubbleWidth(width int) *MessageView {
+	m.Lock()
+	defer m.Unlock()
+	m.maxBubbleWidth = width
+	return m
+}
+
+// SetTimestampFormat sets the layout (see time.Time.Format()) of the
+// timestamps shown next to authors. If empty, no timestamps are shown. The
+// default is "15:04".
+func (m *MessageView) SetTimestampFormat(format string) *MessageView {
+	m.Lock()
+	defer m.Unlock()
+	m.timestampFormat = format
+	return m
+}
+
+// SetDateFormat sets the layout (see time.Time.Format()) of day separators. If
+// empty, no day separators are shown.
+func (m *MessageView) SetDateFormat(format string) *MessageView {
+	m.Lock()
+	defer m.Unlock()
+	m.dateFormat = format
+	return m
+}
+
+// SetAuthorColor sets the color of authors.
+func (m *MessageView) SetAuthorColor(color tcell.Color) *MessageView {
+	m.Lock()
+	defer m.Unlock()
+	m.authorColor = color
+	return m
+}
+
+// SetTextColor sets the color of message texts.
+func (m *MessageView) SetTextColor(color tcell.Color) *MessageView {
+	m.Lock()
+	defer m.Unlock()
+	m.textColor = color
+	return m
+}
+
+// SetTimestampColor sets the color of timestamps.
+func (m *MessageView) SetTimestampColor(color tcell.Color) *MessageView {
+	m.Lock()
+	defer m.Unlock()
+	m.timestampColor = color
+	return m
+}
+
+// SetBubbleColors sets the background colors of the bubbles of left-aligned
+// and right-aligned messages.
+func (m *MessageView) SetBubbleColors(left, right tcell.Color) *MessageView {
+	m.Lock()
+	defer m.Unlock()
+	m.leftBubbleColor, m.rightBubbleColor = left, right
+	return m
+}
+
+// SetSeparatorColor sets the color of day separators.
+func (m *MessageView) SetSeparatorColor(color tcell.Color) *MessageView {
+	m.Lock()
+	defer m.Unlock()
+	m.separatorColor = color
+	return m
+}
+
+// SetIndicatorColors sets the text and background color of the "new
+// messages" indicator.
+func (m *MessageView) SetIndicatorColors(textColor, backgroundColor tcell.Color) *MessageView {
+	m.Lock()
+	defer m.Unlock()
+	m.indicatorTextColor, m.indicatorBackgroundColor = textColor, backgroundColor
+	return m
+}
+
+// SetChangedFunc sets a handler function which is called when messages were
+// added. This handler may be called from a different goroutine. It will
+// usually call Application.Draw().
+func (m *MessageView) SetChangedFunc(handler func()) *MessageView {
+	m.Lock()
+	defer m.Unlock()
+	m.changed = handler
+	return m
+}
+
+// SetDoneFunc sets a handler which is called when the user leaves the message
+// view. The callback function is provided with the key that was pressed, which
+// is one of the following:
+//
+//   - KeyEscape: Leaving the message view with no specific direction.
+//   - KeyTab: Move to the next field.
+//   - KeyBacktab: Move to the previous field.
+func (m *MessageView) SetDoneFunc(handler func(key tcell.Key)) *MessageView {
+	m.done = handler
+	return m
+}
+
+// ScrollToBeginning scrolls to the first message.
+func (m *MessageView) ScrollToBeginning() *MessageView {
+	m.Lock()
+	defer m.Unlock()
+	m.lineOffset, m.follow = 0, false
+	return m
+}
+
+// ScrollToEnd scrolls to the last message and sticks to the bottom.
+func (m *MessageView) ScrollToEnd() *MessageView {
+	m.Lock()
+	defer m.Unlock()
+	m.follow, m.unread = true, 0
+	return m
+}
+
+// scroll scrolls by the given number of lines. Scrolling to the bottom makes
+// the view stick to it.
+func (m *MessageView) scroll(lines int) {
+	if m.follow {
+		m.lineOffset = m.lineCount - m.pageSize
+	}
+	m.lineOffset += lines
+	if m.lineOffset < 0 {
+		m.lineOffset = 0
+	}
+	m.follow = m.lineOffset >= m.lineCount-m.pageSize
+	if m.follow {
+		m.unread = 0
+	}
+}
+
+// layout returns the screen lines of all messages for the given width.
+func (m *MessageView) layout(width int) (lines []messageLine) {
+	bubbleWidth := m.maxBubbleWidth
+	if bubbleWidth <= 0 {
+		bubbleWidth = width * 2 / 3
+	}
+	if bubbleWidth > width {
+		bubbleWidth = width
+	}
+	if bubbleWidth < 3 {
+		return nil
+	}
+
+	var previous time.Time
+	for index, msg := range m.messages {
+		// Day separator.
+		if m.dateFormat != "" {
+			year, month, day := msg.Time.Date()
+			previousYear, previousMonth, previousDay := previous.Date()
+			if index == 0 || year != previousYear || month != previousMonth || day != previousDay {
+				if len(lines) > 0 {
+					lines = append(lines, messageLine{})
+				}
+				lines = append(lines, messageLine{
+					text:  fmt.Sprintf("── %s ──", Escape(msg.Time.Format(m.dateFormat))),
+					color: m.separatorColor,
+					align: AlignCenter,
+				})
+			}
+			previous = msg.Time
+		}
+		if len(lines) > 0 {
+			lines = append(lines, messageLine{})
+		}
+
+		// Compose the bubble's lines.
+		header := messageLine{text: Escape(msg.Author), color: m.authorColor}
+		textWidth := StringWidth(header.text)
+		if m.timestampFormat != "" {
+			header.timestamp = Escape(msg.Time.Format(m.timestampFormat))
+			textWidth += 2 + StringWidth(header.timestamp)
+		}
+		bubble := []messageLine{header}
+		for _, text := range WordWrap(Escape(msg.Text), bubbleWidth-2) {
+			bubble = append(bubble, messageLine{text: text, color: m.textColor})
+			if w := StringWidth(text); w > textWidth {
+				textWidth = w
+			}
+		}
+		if textWidth > bubbleWidth-2 {
+			textWidth = bubbleWidth - 2
+		}
+		x := 0
+		background := m.leftBubbleColor
+		if msg.Align == AlignRight {
+			x = width - textWidth - 2
+			background = m.rightBubbleColor
+		}
+		for _, line := range bubble {
+			line.x, line.width, line.textX = x, textWidth+2, x+1
+			line.backgroundColor = background
+			lines = append(lines, line)
+		}
+	}
+	return
+}
+
+// applyTheme is called when the application's theme changes.
+func (m *MessageView) applyTheme(from, to *Theme) {
+	m.Box.applyTheme(from, to)
+	themeColor(&m.authorColor, from.SecondaryTextColor, to.SecondaryTextColor)
+	themeColor(&m.textColor, from.PrimaryTextColor, to.PrimaryTextColor)
+	themeColor(&m.timestampColor, from.TertiaryTextColor, to.TertiaryTextColor)
+	themeColor(&m.leftBubbleColor, from.ContrastBackgroundColor, to.ContrastBackgroundColor)
+	themeColor(&m.rightBubbleColor, from.MoreContrastBackgroundColor, to.MoreContrastBackgroundColor)
+	themeColor(&m.separatorColor, from.TertiaryTextColor, to.TertiaryTextColor)
+	themeColor(&m.indicatorTextColor, from.InverseTextColor, to.InverseTextColor)
+	themeColor(&m.indicatorBackgroundColor, from.SecondaryTextColor, to.SecondaryTextColor)
+}
+
+// Draw draws this primitive onto the screen.
+func (m *MessageView) Draw(screen tcell.Screen) {
+	defer m.applyStyle(&m.textColor)()
+	m.Box.Draw(screen)
+	m.Lock()
+	defer m.Unlock()
+
+	x, y, width, height := m.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	// Adjust the scroll position.
+	lines := m.layout(width)
+	m.lineCount, m.pageSize = len(lines), height
+	if m.follow || m.lineOffset > len(lines)-height {
+		m.lineOffset = len(lines) - height
+	}
+	if m.lineOffset < 0 {
+		m.lineOffset = 0
+	}
+	if m.lineOffset >= len(lines)-height {
+		m.follow, m.unread = true, 0
+	}
+
+	// Draw the lines.
+	for row := 0; row < height && m.lineOffset+row < len(lines); row++ {
+		line := lines[m.lineOffset+row]
+		if line.width == 0 {
+			Print(screen, line.text, x, y+row, width, line.align, line.color)
+			continue
+		}
+		style := tcell.StyleDefault.Background(line.backgroundColor)
+		for column := 0; column < line.width; column++ {
+			screen.SetContent(x+line.x+column, y+row, ' ', nil, style)
+		}
+		_, printed := Print(screen, line.text, x+line.textX, y+row, line.width-2, AlignLeft, line.color)
+		if line.timestamp != "" && printed+2 < line.width-2 {
+			Print(screen, line.timestamp, x+line.textX+printed+2, y+row, line.width-4-printed, AlignLeft, m.timestampColor)
+		}
+	}
+
+	// Draw the "new messages" indicator.
+	if m.unread > 0 {
+		text := fmt.Sprintf(" ▼ %d new message", m.unread)
+		if m.unread > 1 {
+			text += "s"
+		}
+		text += " "
+		textWidth := StringWidth(text)
+		if textWidth > width {
+			textWidth = width
+		}
+		indicatorX := x + (width-textWidth)/2
+		style := tcell.StyleDefault.Background(m.indicatorBackgroundColor)
+		for column := 0; column < textWidth; column++ {
+			screen.SetContent(indicatorX+column, y+height-1, ' ', nil, style)
+		}
+		Print(screen, text, indicatorX, y+height-1, textWidth, AlignLeft, m.indicatorTextColor)
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (m *MessageView) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return m.wrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		m.Lock()
+		key := event.Key()
+		switch key {
+		case tcell.KeyUp:
+			m.scroll(-1)
+		case tcell.KeyDown:
+			m.scroll(1)
+		case tcell.KeyPgUp, tcell.KeyCtrlB:
+			m.scroll(-m.pageSize)
+		case tcell.KeyPgDn, tcell.KeyCtrlF:
+			m.scroll(m.pageSize)
+		case tcell.KeyHome:
+			m.lineOffset, m.follow = 0, false
+		case tcell.KeyEnd:
+			m.follow, m.unread = true, 0
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'k':
+				m.scroll(-1)
+			case 'j':
+				m.scroll(1)
+			case 'g':
+				m.lineOffset, m.follow = 0, false
+			case 'G':
+				m.follow, m.unread = true, 0
+			}
+		}
+		m.Unlock()
+
+		switch key {
+		case tcell.KeyEscape, tcell.KeyTab, tcell.KeyBacktab:
+			if m.done != nil {
+				m.done(key)
+			}
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (m *MessageView) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return m.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+		if !m.InRect(x, y) {
+			return false, nil
+		}
+		m.Lock()
+		defer m.Unlock()
+		switch action {
+		case MouseLeftClick:
+			setFocus(m)
+			_, rectY, _, height := m.GetInnerRect()
+			if m.unread > 0 && y == rectY+height-1 {
+				m.follow, m.unread = true, 0 // Clicked on the indicator.
+			}
+		case MouseScrollUp:
+			m.scroll(-3)
+		case MouseScrollDown:
+			m.scroll(3)
+		}
+		return true, nil
+	})
+}
@@ -1,12 +1,15 @@
 package tview
 
 import (
+	"unicode"
+
 	"github.com/gdamore/tcell"
 )
 
 // Modal is a centered message window used to inform the user or prompt them
 // for an immediate decision. It needs to have at least one button (added via
-// AddButtons()) or it will never disappear.
+// AddButtons()) or it will never disappear. Besides the message text, it may
+// show any other primitive above its buttons (see SetContent()).
 //
 // See https://github.com/rivo/tview/wiki/Modal for an example.
 type Modal struct {
@@ -24,6 +27,32 @@ type Modal struct {
 	// The text color.
 	textColor tcell.Color
 
+	// An optional primitive shown between the message text and the buttons,
+	// and its height.
+	content       Primitive
+	contentHeight int
+
+	// The modal's width and minimum height, including its border. A width of
+	// 0 means one third of the screen's width.
+	width, minHeight int
+
+	// If true, the buttons receive focus the next time the modal receives
+	// focus, even if there is content.
+	focusButtons bool
+
+	// We keep a reference to the function which allows us to set the focus.
+	setFocus func(p Primitive)
+
+	// The shortcut runes of the buttons, 0 for buttons without a shortcut.
+	shortcuts []rune
+
+	// The index of the button which receives focus first, -1 if there is no
+	// default button.
+	defaultButton int
+
+	// An optional function which is called when the user presses Escape.
+	cancel func()
+
 	// The optional callback for when the user clicked one of the buttons. It
 	// receives the index of the clicked button and the button's label.
 	done func(buttonIndex int, buttonLabel string)
@@ -31,18 +60,28 @@ type Modal struct {
 
 // NewModal returns a new modal message window.
 func NewModal() *Modal {
+	styles := Styles.forWidget("Modal")
 	m := &Modal{
-		Box:       NewBox(),
-		textColor: Styles.PrimaryTextColor,
+		Box:           NewBox(),
+		textColor:     styles.PrimaryTextColor,
+		defaultButton: -1,
 	}
 	m.form = NewForm().
 		SetButtonsAlign(AlignCenter).
-		SetButtonBackgroundColor(Styles.PrimitiveBackgroundColor).
-		SetButtonTextColor(Styles.PrimaryTextColor)
-	m.form.SetBackgroundColor(Styles.ContrastBackgroundColor).SetBorderPadding(0, 0, 0, 0)
+		SetButtonBackgroundColor(styles.PrimitiveBackgroundColor).
+		SetButtonTextColor(styles.PrimaryTextColor)
+	m.form.SetBackgroundColor(styles.ContrastBackgroundColor).SetBorderPadding(0, 0, 0, 0)
+	m.form.SetCancelFunc(func() {
+		if m.cancel != nil {
+			m.cancel()
+		}
+		if m.done != nil {
+			m.done(-1, "")
+		}
+	})
 	m.frame = NewFrame(m.form).SetBorders(0, 0, 1, 0, 0, 0)
 	m.frame.SetBorder(true).
-		SetBackgroundColor(Styles.ContrastBackgroundColor).
+		SetBackgroundColor(styles.ContrastBackgroundColor).
 		SetBorderPadding(1, 1, 1, 1)
 	m.focus = m
 	return m
@@ -56,13 +95,39 @@ func (m *Modal) SetTextColor(color tcell.Color) *Modal {
 
 // SetDoneFunc sets a handler which is called when one of the buttons was
 // pressed. It receives the index of the button as well as its label text. The
-// handler is also called when the user presses the Escape key. The index will
-// then be negative and the label text an emptry string.
+// handler is also called when the user presses the Escape key on a button. The
+// index will then be negative and the label text an empty string.
 func (m *Modal) SetDoneFunc(handler func(buttonIndex int, buttonLabel string)) *Modal {
 	m.done = handler
 	return m
 }
 
+// SetCancelFunc sets a handler which is called when the user presses the
+// Escape key on a button, before the "done" handler (see SetDoneFunc()).
+func (m *Modal) SetCancelFunc(handler func()) *Modal {
+	m.cancel = handler
+	return m
+}
+
+// SetDefaultButton sets the index of the button which receives focus when the
+// buttons receive focus, e.g. the button of the safe choice in a confirmation
+// dialog. A negative value (the default) selects the first button.
+func (m *Modal) SetDefaultButton(index int) *Modal {
+	m.defaultButton = index
+	return m
+}
+
+// SetButtonShortcut sets a shortcut rune for the button with the given index.
+// When one of the buttons has focus, typing the rune (case-insensitive)
+// presses the button, e.g. 'y' for a "Yes" button and 'n' for a "No" button.
+// A rune of 0 removes the shortcut.
+func (m *Modal) SetButtonShortcut(index int, shortcut rune) *Modal {
+	if index >= 0 && index < len(m.shortcuts) {
+		m.shortcuts[index] = shortcut
+	}
+	return m
+}
+
 // SetText sets the message text of the window. The text may contain line
 // breaks. Note that words are wrapped, too, based on the final size of the
 // window.
@@ -71,44 +136,172 @@ func (m *Modal) SetText(text string) *Modal {
 	return m
 }
 
+// SetContent sets a primitive (e.g. a Form, a List, or a TextView) which is
+// shown below the message text and above the buttons, with the given height.
+// The modal is still sized and centered automatically. When the modal receives
+// focus, it is passed on to the content. Call FocusButtons() (e.g. from the
+// content's "done" handler) to move the focus to the buttons. Tab on the last
+// button and Backtab on the first button move it back to the content. Pass nil
+// to remove the content.
+func (m *Modal) SetContent(content Primitive, height int) *Modal {
+	m.content, m.contentHeight = content, height
+	if content == nil {
+		m.frame.primitive = m.form
+		return m
+	}
+	layout := NewFlex().SetDirection(FlexRow).
+		AddItemWithLimits(content, 0, 1, height, 0, false).
+		AddItem(nil, 1, 0, false).
+		AddItem(m.form, 1, 0, false)
+	layout.SetBackgroundColor(m.frame.backgroundColor)
+	m.frame.primitive = layout
+	return m
+}
+
+// SetWidth sets the width of the modal, including its border. Long messages
+// are then wrapped at this width instead of being squeezed into the default
+// width, which is one third of the screen's width (0). The modal is always at
+// least wide enough for its buttons and never wider than the screen.
+func (m *Modal) SetWidth(width int) *Modal {
+	m.width = width
+	return m
+}
+
+// SetMinHeight sets the minimum height of the modal, including its border.
+// The modal grows beyond it if its text and content need more space. If it is
+// taller than needed, the additional space goes to the content (see
+// SetContent()) or, if there is none, is left between the text and the
+// buttons. The default is 0, i.e. the modal is only as tall as needed.
+func (m *Modal) SetMinHeight(height int) *Modal {
+	m.minHeight = height
+	return m
+}
+
+// GetContent returns the primitive shown between the message text and the
+// buttons, or nil if there is none.
+func (m *Modal) GetContent() Primitive {
+	return m.content
+}
+
+// FocusButtons moves the focus from the content to the buttons. If the modal
+// does not have focus, the buttons receive it the next time the modal receives
+// focus.
+func (m *Modal) FocusButtons() *Modal {
+	m.focusButtons = true
+	if m.setFocus != nil && m.HasFocus() {
+		m.setFocus(m)
+	}
+	return m
+}
+
 // AddButtons adds buttons to the window. There must be at least one button and
 // a "done" handler so the window can be closed again.
 func (m *Modal) AddButtons(labels []string) *Modal {
-	for index, label := range labels {
-		func(i int, l string) {
-			m.form.AddButton(label, func() {
-				if m.done != nil {
-					m.done(i, l)
+	for _, label := range labels {
+		buttonIndex := len(m.form.buttons)
+		m.form.AddButton(label, func() {
+			m.press(buttonIndex)
+		})
+		m.shortcuts = append(m.shortcuts, 0)
+
+		m.form.buttons[buttonIndex].SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			key := event.Key()
+
+			// Shortcuts press their buttons.
+			if key == tcell.KeyRune {
+				r := unicode.ToLower(event.Rune())
+				for index, shortcut := range m.shortcuts {
+					if shortcut != 0 && unicode.ToLower(shortcut) == r {
+						m.press(index)
+						return nil
+					}
 				}
-			})
-		}(index, label)
+			}
+
+			// Leaving the first or the last button moves the focus to the
+			// content.
+			if m.content != nil && m.setFocus != nil &&
+				(key == tcell.KeyBacktab && buttonIndex == 0 || key == tcell.KeyTab && buttonIndex == len(m.form.buttons)-1) {
+				m.setFocus(m.content)
+				return nil
+			}
+			return event
+		})
 	}
 	return m
 }
 
+// press presses the button with the given index.
+func (m *Modal) press(index int) {
+	if m.done != nil {
+		m.done(index, m.form.buttons[index].GetLabel())
+	}
+}
+
 // Focus is called when this primitive receives focus.
 func (m *Modal) Focus(delegate func(p Primitive)) {
-	delegate(m.form)
+	m.setFocus = delegate
+	if m.content != nil && !m.focusButtons {
+		delegate(m.content)
+	} else {
+		if !m.form.HasFocus() && m.defaultButton >= 0 && m.defaultButton < len(m.form.buttons) {
+			m.form.focusedElement = len(m.form.items) + m.defaultButton
+		}
+		delegate(m.form)
+	}
+	m.focusButtons = false
 }
 
 // HasFocus returns whether or not this primitive has focus.
 func (m *Modal) HasFocus() bool {
+	if m.content != nil && m.content.GetFocusable().HasFocus() {
+		return true
+	}
 	return m.form.HasFocus()
 }
 
+// GetChildren returns the frame which contains the modal's text, content, and
+// buttons.
+func (m *Modal) GetChildren() []Primitive {
+	return []Primitive{m.frame}
+}
+
+// applyTheme is called when the application's theme changes.
+func (m *Modal) applyTheme(from, to *Theme) {
+	m.Box.applyTheme(from, to)
+	themeColor(&m.textColor, from.PrimaryTextColor, to.PrimaryTextColor)
+
+	// The frame and the form are visited separately but their colors differ
+	// from the defaults.
+	themeColor(&m.frame.backgroundColor, from.ContrastBackgroundColor, to.ContrastBackgroundColor)
+	themeColor(&m.form.backgroundColor, from.ContrastBackgroundColor, to.ContrastBackgroundColor)
+	themeColor(&m.form.buttonBackgroundColor, from.PrimitiveBackgroundColor, to.PrimitiveBackgroundColor)
+	if layout, ok := m.frame.primitive.(*Flex); ok {
+		themeColor(&layout.backgroundColor, from.ContrastBackgroundColor, to.ContrastBackgroundColor)
+	}
+}
+
 // Draw draws this primitive onto the screen.
 func (m *Modal) Draw(screen tcell.Screen) {
+	defer m.applyStyle(&m.textColor)()
 	// Calculate the width of this modal.
 	buttonsWidth := 0
 	for _, button := range m.form.buttons {
-		buttonsWidth += StringWidth(button.label) + 4 + 2
+		buttonWidth, _ := button.GetPreferredSize()
+		buttonsWidth += buttonWidth + 2
 	}
 	buttonsWidth -= 2
 	screenWidth, screenHeight := screen.Size()
 	width := screenWidth / 3
+	if m.width > 0 {
+		width = m.width - 4
+	}
 	if width < buttonsWidth {
 		width = buttonsWidth
 	}
+	if width > screenWidth-4 {
+		width = screenWidth - 4
+	}
 	// width is now without the box border.
 
 	// Reset the text and find out how wide it is.
@@ -120,12 +313,42 @@ func (m *Modal) Draw(screen tcell.Screen) {
 
 	// Set the modal's position and size.
 	height := len(lines) + 6
+	if m.content != nil {
+		height = len(lines) + m.contentHeight + 6
+		if len(lines) > 0 {
+			height++ // The space between the text and the content.
+		}
+	}
+	if height < m.minHeight {
+		if m.content == nil {
+			for extra := height; extra < m.minHeight; extra++ {
+				m.frame.AddText("", true, AlignCenter, m.textColor)
+			}
+		}
+		height = m.minHeight
+	}
+	if height > screenHeight {
+		height = screenHeight
+	}
 	width += 4
 	x := (screenWidth - width) / 2
 	y := (screenHeight - height) / 2
 	m.SetRect(x, y, width, height)
+	m.markDrawn(screen)
 
 	// Draw the frame.
 	m.frame.SetRect(x, y, width, height)
 	m.frame.Draw(screen)
 }
+
+// MouseHandler returns the mouse handler for this primitive.
+func (m *Modal) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return m.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		// Pass mouse events on to the form.
+		consumed, capture = m.frame.MouseHandler()(action, event, setFocus)
+		if !consumed && m.InRect(event.Position()) {
+			consumed = true // Don't let clicks on the modal fall through.
+		}
+		return
+	})
+}
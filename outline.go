@@ -0,0 +1,402 @@
+package tview
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell"
+)
+
+// outlineEntry represents one entry of an Outline.
+type outlineEntry struct {
+	Title  string // The entry's title.
+	Level  int    // The entry's nesting level, 0 for top-level entries.
+	Region string // The ID of the region in the linked text view.
+	Line   int    // The line in the linked pager.
+}
+
+// Outline is a primitive which shows a hierarchical list of headings, like the
+// table of contents of a document. Entries are indented according to their
+// level.
+//
+// An outline can be linked to a TextView (see SetTextView()) and to a Pager
+// (see SetPager()). When an entry is selected, the text view highlights the
+// entry's region and scrolls to it, and the pager scrolls to the entry's line.
+// Entries for a Markdown-style text can be generated with
+// AddMarkdownHeadings().
+//
+// The following keys are available when the outline has focus:
+//
+//   - Up arrow, k: Move to the previous entry.
+//   - Down arrow, j: Move to the next entry.
+//   - Left arrow, h: Move to the parent entry.
+//   - Page Up, Page Down: Move up or down by one page.
+//   - Home, g: Move to the first entry.
+//   - End, G: Move to the last entry.
+//   - Enter, Space: Select the current entry.
+//   - Escape, Tab, Backtab: Finish (see SetDoneFunc()).
+type Outline struct {
+	*Box
+
+	// The entries.
+	entries []*outlineEntry
+
+	// The index of the current entry.
+	currentEntry int
+
+	// The index of the first visible entry.
+	entryOffset int
+
+	// The number of entries visible the last time the outline was drawn.
+	pageSize int
+
+	// The number of spaces by which each level is indented.
+	indent int
+
+	// The linked text view and pager, may be nil.
+	textView *TextView
+	pager    *Pager
+
+	// The color of entry titles.
+	textColor tcell.Color
+
+	// The colors of the current entry.
+	selectedTextColor, selectedBackgroundColor tcell.Color
+
+	// An optional function which is called when the user navigated to an
+	// entry.
+	changed func(index int, title string)
+
+	// An optional function which is called when an entry was selected.
+	selected func(index int, title string)
+
+	// An optional function which is called when the user leaves the outline.
+	done func(tcell.Key)
+}
+
+// NewOutline returns a new, empty outline.
+func NewOutline() *Outline {
+	styles := Styles.forWidget("Outline")
+	return &Outline{
+		Box:                     NewBox(),
+		indent:                  2,
+		textColor:               styles.PrimaryTextColor,
+		selectedTextColor:       styles.PrimitiveBackgroundColor,
+		selectedBackgroundColor: styles.PrimaryTextColor,
+	}
+}
+
+// AddEntry adds an entry with the given title and level at the end of the
+// outline. The title may contain color tags. Top-level entries have level 0.
+// "region" is the ID of the region in the linked text view which corresponds
+// to this entry (see TextView for details on regions), "line" is the index of
+// the corresponding line in the linked pager. Either is ignored if no such
+// primitive was linked.
+func (o *Outline) AddEntry(title string, level int, region string, line int) *Outline {
+	if level < 0 {
+		level = 0
+	}
+	o.entries = append(o.entries, &outlineEntry{Title: title, Level: level, Region: region, Line: line})
+	return o
+}
+
+// AddMarkdownHeadings adds an entry for each Markdown heading ("# Title",
+// "## Subtitle", and so on) found in the given text, skipping fenced code
+// blocks. The level is the number of "#" characters minus one, the line is the
+// index of the heading's line in the text. No regions are set. Titles are
+// escaped so they are not interpreted as color tags.
+func (o *Outline) AddMarkdownHeadings(text string) *Outline {
+	var fenced bool
+	for index, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.HasPrefix(line, "```") {
+			fenced = !fenced
+			continue
+		}
+		if fenced || !strings.HasPrefix(line, "#") {
+			continue
+		}
+		title := strings.TrimLeft(line, "#")
+		level := len(line) - len(title) - 1
+		if level > 5 || title != "" && title[0] != ' ' && title[0] != '\t' {
+			continue // Not a heading.
+		}
+		title = strings.TrimSpace(title)
+		if trimmed := strings.TrimRight(title, "#"); trimmed == "" || strings.HasSuffix(trimmed, " ") {
+			title = strings.TrimSpace(trimmed) // Remove the optional closing sequence.
+		}
+		if title == "" {
+			continue
+		}
+		o.AddEntry(Escape(title), level, "", index)
+	}
+	return o
+}
+
+// Clear removes all entries.
+func (o *Outline) Clear() *Outline {
+	o.entries = nil
+	o.currentEntry, o.entryOffset = 0, 0
+	return o
+}
+
+// GetEntryCount returns the number of entries.
+func (o *Outline) GetEntryCount() int {
+	return len(o.entries)
+}
+
+// SetCurrentEntry sets the current entry by its index. The linked primitives
+// are not scrolled. Call Select() for that.
+func (o *Outline) SetCurrentEntry(index int) *Outline {
+	o.setCurrentEntry(index)
+	return o
+}
+
+// GetCurrentEntry returns the index and the title of the current entry. If
+// there are no entries, -1 and an empty string are returned.
+func (o *Outline) GetCurrentEntry() (index int, title string) {
+	if len(o.entries) == 0 {
+		return -1, ""
+	}
+	return o.currentEntry, o.entries[o.currentEntry].Title
+}
+
+// Select selects the current entry, scrolling the linked text view and pager
+// to it and invoking the "selected" handler.
+func (o *Outline) Select() *Outline {
+	if len(o.entries) == 0 {
+		return o
+	}
+	entry := o.entries[o.currentEntry]
+	if o.textView != nil && entry.Region != "" {
+		o.textView.Highlight(entry.Region).ScrollToHighlight()
+	}
+	if o.pager != nil {
+		o.pager.ScrollTo(entry.Line)
+	}
+	if o.selected != nil {
+		o.selected(o.currentEntry, entry.Title)
+	}
+	return o
+}
+
+// SetTextView links the outline to a text view. When an entry is selected,
+// its region is highlighted in the text view and scrolled into view. The text
+// view needs to have regions enabled (see TextView.SetRegions()). Set to nil
+// to remove the link.
+func (o *Outline) SetTextView(textView *TextView) *Outline {
+	o.textView = textView
+	return o
+}
+
+// SetPager links the outline to a pager. When an entry is selected, the pager
+// scrolls to the entry's line. Set to nil to remove the link.
+func (o *Outline) SetPager(pager *Pager) *Outline {
+	o.pager = pager
+	return o
+}
+
+// SetIndent sets the number of spaces by which each level is indented.
+func (o *Outline) SetIndent(indent int) *Outline {
+	o.indent = indent
+	return o
+}
+
+// SetTextColor sets the color of the entry titles.
+func (o *Outline) SetTextColor(color tcell.Color) *Outline {
+	o.textColor = color
+	return o
+}
+
+// SetSelectedTextColor sets the text color of the current entry.
+func (o *Outline) SetSelectedTextColor(color tcell.Color) *Outline {
+	o.selectedTextColor = color
+	return o
+}
+
+// SetSelectedBackgroundColor sets the background color of the current entry.
+func (o *Outline) SetSelectedBackgroundColor(color tcell.Color) *Outline {
+	o.selectedBackgroundColor = color
+	return o
+}
+
+// SetChangedFunc sets a handler which is called when the user navigates to an
+// entry. It receives the entry's index and title.
+func (o *Outline) SetChangedFunc(handler func(index int, title string)) *Outline {
+	o.changed = handler
+	return o
+}
+
+// SetSelectedFunc sets a handler which is called when the user selects an
+// entry, after the linked primitives were scrolled. It receives the entry's
+// index and title.
+func (o *Outline) SetSelectedFunc(handler func(index int, title string)) *Outline {
+	o.selected = handler
+	return o
+}
+
+// SetDoneFunc sets a handler which is called when the user leaves the outline.
+// The callback function is provided with the key that was pressed, which is
+// one of the following:
+//
+//   - KeyEscape: Leaving the outline with no specific direction.
+//   - KeyTab: Move to the next field.
+//   - KeyBacktab: Move to the previous field.
+func (o *Outline) SetDoneFunc(handler func(key tcell.Key)) *Outline {
+	o.done = handler
+	return o
+}
+
+// setCurrentEntry sets the current entry's index, clamped to the available
+// entries, and invokes the "changed" callback if it changed.
+func (o *Outline) setCurrentEntry(index int) {
+	if index >= len(o.entries) {
+		index = len(o.entries) - 1
+	}
+	if index < 0 {
+		index = 0
+	}
+	if index == o.currentEntry {
+		return
+	}
+	o.currentEntry = index
+	if o.changed != nil {
+		o.changed(o.GetCurrentEntry())
+	}
+}
+
+// parent returns the index of the parent of the current entry or -1 if it is
+// a top-level entry.
+func (o *Outline) parent() int {
+	if len(o.entries) == 0 {
+		return -1
+	}
+	level := o.entries[o.currentEntry].Level
+	for index := o.currentEntry - 1; index >= 0; index-- {
+		if o.entries[index].Level < level {
+			return index
+		}
+	}
+	return -1
+}
+
+// applyTheme is called when the application's theme changes.
+func (o *Outline) applyTheme(from, to *Theme) {
+	o.Box.applyTheme(from, to)
+	themeColor(&o.textColor, from.PrimaryTextColor, to.PrimaryTextColor)
+	themeColor(&o.selectedTextColor, from.PrimitiveBackgroundColor, to.PrimitiveBackgroundColor)
+	themeColor(&o.selectedBackgroundColor, from.PrimaryTextColor, to.PrimaryTextColor)
+}
+
+// Draw draws this primitive onto the screen.
+func (o *Outline) Draw(screen tcell.Screen) {
+	defer o.applyStyle(&o.textColor)()
+	o.Box.Draw(screen)
+	x, y, width, height := o.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+	o.pageSize = height
+
+	// Make sure the current entry is visible.
+	if o.currentEntry < o.entryOffset {
+		o.entryOffset = o.currentEntry
+	} else if o.currentEntry >= o.entryOffset+height {
+		o.entryOffset = o.currentEntry - height + 1
+	}
+	if o.entryOffset > len(o.entries)-height {
+		o.entryOffset = len(o.entries) - height
+	}
+	if o.entryOffset < 0 {
+		o.entryOffset = 0
+	}
+
+	// Draw the entries.
+	for row := 0; row < height && o.entryOffset+row < len(o.entries); row++ {
+		index := o.entryOffset + row
+		entry := o.entries[index]
+		indent := entry.Level * o.indent
+		if indent >= width {
+			indent = width - 1
+		}
+		color := o.textColor
+		if index == o.currentEntry && o.hasFocus {
+			color = o.selectedTextColor
+			style := tcell.StyleDefault.Background(o.selectedBackgroundColor)
+			for column := indent; column < width; column++ {
+				screen.SetContent(x+column, y+row, ' ', nil, style)
+			}
+		}
+		Print(screen, entry.Title, x+indent, y+row, width-indent, AlignLeft, color)
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (o *Outline) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return o.wrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		switch key := event.Key(); key {
+		case tcell.KeyUp:
+			o.setCurrentEntry(o.currentEntry - 1)
+		case tcell.KeyDown:
+			o.setCurrentEntry(o.currentEntry + 1)
+		case tcell.KeyLeft:
+			if parent := o.parent(); parent >= 0 {
+				o.setCurrentEntry(parent)
+			}
+		case tcell.KeyPgUp:
+			o.setCurrentEntry(o.currentEntry - o.pageSize)
+		case tcell.KeyPgDn:
+			o.setCurrentEntry(o.currentEntry + o.pageSize)
+		case tcell.KeyHome:
+			o.setCurrentEntry(0)
+		case tcell.KeyEnd:
+			o.setCurrentEntry(len(o.entries) - 1)
+		case tcell.KeyEnter:
+			o.Select()
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'k':
+				o.setCurrentEntry(o.currentEntry - 1)
+			case 'j':
+				o.setCurrentEntry(o.currentEntry + 1)
+			case 'h':
+				if parent := o.parent(); parent >= 0 {
+					o.setCurrentEntry(parent)
+				}
+			case 'g':
+				o.setCurrentEntry(0)
+			case 'G':
+				o.setCurrentEntry(len(o.entries) - 1)
+			case ' ':
+				o.Select()
+			}
+		case tcell.KeyEscape, tcell.KeyTab, tcell.KeyBacktab:
+			if o.done != nil {
+				o.done(key)
+			}
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (o *Outline) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return o.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+		if !o.InRect(x, y) {
+			return false, nil
+		}
+		switch action {
+		case MouseLeftClick:
+			setFocus(o)
+			_, rectY, _, _ := o.GetInnerRect()
+			if index := o.entryOffset + y - rectY; y >= rectY && index < len(o.entries) {
+				o.setCurrentEntry(index)
+				o.Select()
+			}
+		case MouseScrollUp:
+			o.setCurrentEntry(o.currentEntry - 1)
+		case MouseScrollDown:
+			o.setCurrentEntry(o.currentEntry + 1)
+		}
+		return true, nil
+	})
+}
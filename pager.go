@@ -0,0 +1,466 @@
+package tview
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell"
+)
+
+// Pager is a primitive for viewing large amounts of text, using the key
+// bindings of the "less" program. It is suited for help texts, files, or
+// command output. Lines are not wrapped; long lines can be scrolled
+// horizontally. Unlike TextView, color tags and regions are not interpreted,
+// the text is shown as it is.
+//
+// A status line at the bottom shows the visible line range and the position
+// as a percentage of the text. It is also used to type search patterns and
+// marks.
+//
+// The following keys are available when the pager has focus:
+//
+//   - j, e, Down arrow, Enter, Ctrl-N, Ctrl-E: Scroll down by one line.
+//   - k, y, Up arrow, Ctrl-P, Ctrl-Y: Scroll up by one line.
+//   - Space, f, Page Down, Ctrl-F, Ctrl-V: Scroll down by one page.
+//   - b, Page Up, Ctrl-B: Scroll up by one page.
+//   - d, Ctrl-D: Scroll down by half a page.
+//   - u, Ctrl-U: Scroll up by half a page.
+//   - g, <, Home: Go to the first line.
+//   - G, >, End: Go to the last line.
+//   - Left arrow, Right arrow: Scroll left or right by half a screen width.
+//   - /pattern: Search forward for the (case-insensitive) pattern.
+//   - ?pattern: Search backward for the pattern.
+//   - n, N: Repeat the last search in the same or the opposite direction.
+//   - m followed by a letter: Mark the current position with that letter.
+//   - ' followed by a letter: Go to the position marked with that letter. A
+//     second ' returns to the position before the last jump.
+//   - q, Escape, Tab, Backtab: Finish (see SetDoneFunc()).
+type Pager struct {
+	*Box
+
+	// The text's lines, with tabs expanded.
+	lines [][]rune
+
+	// The index of the first visible line and the number of columns scrolled
+	// to the right.
+	lineOffset, columnOffset int
+
+	// The number of lines and columns available for text the last time the
+	// pager was drawn.
+	pageSize, pageWidth int
+
+	// Marked line offsets, indexed by the mark's letter.
+	marks map[rune]int
+
+	// The line offset before the last jump.
+	previousOffset int
+
+	// The last search pattern (lower case) and whether it searches backward.
+	search   string
+	backward bool
+
+	// The prompt currently shown in the status line ('/', '?', 'm', '\'') or
+	// 0 if there is none, and the text typed after it.
+	prompt rune
+	input  string
+
+	// A message shown in the status line until the next key is pressed.
+	message string
+
+	// The color of the text.
+	textColor tcell.Color
+
+	// The colors of the status line.
+	statusTextColor, statusBackgroundColor tcell.Color
+
+	// The colors used to highlight search matches.
+	matchTextColor, matchBackgroundColor tcell.Color
+
+	// An optional function which is called when the user leaves the pager.
+	done func(tcell.Key)
+}
+
+// pagerState is the state of a Pager saved with SaveState().
+type pagerState struct {
+	LineOffset   int `json:"lineOffset"`
+	ColumnOffset int `json:"columnOffset"`
+}
+
+// NewPager returns a new, empty pager.
+func NewPager() *Pager {
+	styles := Styles.forWidget("Pager")
+	return &Pager{
+		Box:                   NewBox(),
+		marks:                 make(map[rune]int),
+		textColor:             styles.PrimaryTextColor,
+		statusTextColor:       styles.PrimitiveBackgroundColor,
+		statusBackgroundColor: styles.PrimaryTextColor,
+		matchTextColor:        styles.PrimitiveBackgroundColor,
+		matchBackgroundColor:  styles.SecondaryTextColor,
+	}
+}
+
+// SetText sets the text shown by the pager. The scroll position and the marks
+// are reset.
+func (p *Pager) SetText(text string) *Pager {
+	text = strings.TrimSuffix(text, "\n")
+	tab := strings.Repeat(" ", TabSize)
+	p.lines = nil
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		p.lines = append(p.lines, []rune(strings.Replace(line, "\t", tab, -1)))
+	}
+	p.lineOffset, p.columnOffset, p.previousOffset = 0, 0, 0
+	p.marks = make(map[rune]int)
+	p.message = ""
+	return p
+}
+
+// GetText returns the text shown by the pager, with tabs expanded.
+func (p *Pager) GetText() string {
+	lines := make([]string, len(p.lines))
+	for index, line := range p.lines {
+		lines[index] = string(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// GetLineCount returns the number of lines of the text.
+func (p *Pager) GetLineCount() int {
+	return len(p.lines)
+}
+
+// ScrollTo scrolls to the given line, making it the first visible line (as
+// far as possible).
+func (p *Pager) ScrollTo(line int) *Pager {
+	p.lineOffset = line
+	return p
+}
+
+// GetScrollOffset returns the index of the first visible line and the number
+// of columns scrolled to the right.
+func (p *Pager) GetScrollOffset() (line, column int) {
+	return p.lineOffset, p.columnOffset
+}
+
+// SetSearch sets the search pattern used by the "n" and "N" keys and
+// highlights its matches. The pattern is matched case-insensitively. An empty
+// pattern removes the highlighting. The scroll position is not changed.
+func (p *Pager) SetSearch(pattern string) *Pager {
+	p.search = strings.ToLower(pattern)
+	p.backward = false
+	return p
+}
+
+// GetSearch returns the current search pattern (in lower case).
+func (p *Pager) GetSearch() string {
+	return p.search
+}
+
+// SetTextColor sets the color of the text.
+func (p *Pager) SetTextColor(color tcell.Color) *Pager {
+	p.textColor = color
+	return p
+}
+
+// SetStatusTextColor sets the text color of the status line.
+func (p *Pager) SetStatusTextColor(color tcell.Color) *Pager {
+	p.statusTextColor = color
+	return p
+}
+
+// SetStatusBackgroundColor sets the background color of the status line.
+func (p *Pager) SetStatusBackgroundColor(color tcell.Color) *Pager {
+	p.statusBackgroundColor = color
+	return p
+}
+
+// SetMatchTextColor sets the text color of search matches.
+func (p *Pager) SetMatchTextColor(color tcell.Color) *Pager {
+	p.matchTextColor = color
+	return p
+}
+
+// SetMatchBackgroundColor sets the background color of search matches.
+func (p *Pager) SetMatchBackgroundColor(color tcell.Color) *Pager {
+	p.matchBackgroundColor = color
+	return p
+}
+
+// SetDoneFunc sets a handler which is called when the user presses one of the
+// following keys:
+//
+//   - q: Quit the pager (passed as KeyRune).
+//   - KeyEscape: Quit the pager.
+//   - KeyTab: Move to the next field.
+//   - KeyBacktab: Move to the previous field.
+func (p *Pager) SetDoneFunc(handler func(key tcell.Key)) *Pager {
+	p.done = handler
+	return p
+}
+
+// jump scrolls to the given line and remembers the previous position.
+func (p *Pager) jump(line int) {
+	p.previousOffset = p.lineOffset
+	p.lineOffset = line
+}
+
+// find searches for the next line containing the search pattern, starting
+// after (or, when searching backward, before) the first visible line. If
+// "reverse" is true, the direction of the last search is reversed.
+func (p *Pager) find(reverse bool) {
+	if p.search == "" {
+		p.message = "No previous search pattern"
+		return
+	}
+	step := 1
+	if p.backward != reverse {
+		step = -1
+	}
+	for line := p.lineOffset + step; line >= 0 && line < len(p.lines); line += step {
+		if strings.Contains(strings.ToLower(string(p.lines[line])), p.search) {
+			p.jump(line)
+			return
+		}
+	}
+	p.message = "Pattern not found"
+}
+
+// SaveState returns the scroll position as JSON. See SaveLayout() for details.
+func (p *Pager) SaveState() ([]byte, error) {
+	return json.Marshal(pagerState{LineOffset: p.lineOffset, ColumnOffset: p.columnOffset})
+}
+
+// RestoreState restores a state returned by SaveState().
+func (p *Pager) RestoreState(data []byte) error {
+	var state pagerState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	p.lineOffset, p.columnOffset = state.LineOffset, state.ColumnOffset
+	return nil
+}
+
+// applyTheme is called when the application's theme changes.
+func (p *Pager) applyTheme(from, to *Theme) {
+	p.Box.applyTheme(from, to)
+	themeColor(&p.textColor, from.PrimaryTextColor, to.PrimaryTextColor)
+	themeColor(&p.statusTextColor, from.PrimitiveBackgroundColor, to.PrimitiveBackgroundColor)
+	themeColor(&p.statusBackgroundColor, from.PrimaryTextColor, to.PrimaryTextColor)
+	themeColor(&p.matchTextColor, from.PrimitiveBackgroundColor, to.PrimitiveBackgroundColor)
+	themeColor(&p.matchBackgroundColor, from.SecondaryTextColor, to.SecondaryTextColor)
+}
+
+// Draw draws this primitive onto the screen.
+func (p *Pager) Draw(screen tcell.Screen) {
+	defer p.applyStyle(&p.textColor)()
+	p.Box.Draw(screen)
+	x, y, width, height := p.GetInnerRect()
+	if width <= 0 || height <= 1 {
+		return
+	}
+	height--
+	p.pageSize, p.pageWidth = height, width
+
+	// Adjust the scroll position.
+	if p.lineOffset > len(p.lines)-height {
+		p.lineOffset = len(p.lines) - height
+	}
+	if p.lineOffset < 0 {
+		p.lineOffset = 0
+	}
+	if p.columnOffset < 0 {
+		p.columnOffset = 0
+	}
+
+	// Draw the lines.
+	search := []rune(p.search)
+	textStyle := tcell.StyleDefault.Foreground(p.textColor).Background(p.backgroundColor)
+	matchStyle := tcell.StyleDefault.Foreground(p.matchTextColor).Background(p.matchBackgroundColor)
+	for row := 0; row < height && p.lineOffset+row < len(p.lines); row++ {
+		line := p.lines[p.lineOffset+row]
+		matched := findMatches(line, 0, search)
+		var column int
+		for index, r := range line {
+			runeWidth := RuneWidth(r)
+			if runeWidth == 0 {
+				continue
+			}
+			if column < p.columnOffset {
+				column += runeWidth
+				continue
+			}
+			screenX := x + column - p.columnOffset
+			if screenX+runeWidth > x+width {
+				break
+			}
+			style := textStyle
+			if matched[index] {
+				style = matchStyle
+			}
+			screen.SetContent(screenX, y+row, r, nil, style)
+			column += runeWidth
+		}
+	}
+
+	// Draw the status line.
+	var status string
+	switch {
+	case p.prompt != 0:
+		status = string(p.prompt) + p.input + "_"
+	case p.message != "":
+		status = p.message
+	case len(p.lines) == 0:
+		status = "(END)"
+	case p.lineOffset+height >= len(p.lines):
+		status = fmt.Sprintf("Lines %d-%d of %d (END)", p.lineOffset+1, len(p.lines), len(p.lines))
+	default:
+		last := p.lineOffset + height
+		status = fmt.Sprintf("Lines %d-%d of %d  %d%%", p.lineOffset+1, last, len(p.lines), last*100/len(p.lines))
+	}
+	statusStyle := tcell.StyleDefault.Foreground(p.statusTextColor).Background(p.statusBackgroundColor)
+	for column := 0; column < width; column++ {
+		screen.SetContent(x+column, y+height, ' ', nil, statusStyle)
+	}
+	Print(screen, Escape(status), x, y+height, width, AlignLeft, p.statusTextColor)
+}
+
+// InputHandler returns the handler for this primitive.
+func (p *Pager) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return p.wrapInputHandler(func(event *tcell.EventKey, setFocus func(primitive Primitive)) {
+		p.message = ""
+		key := event.Key()
+
+		// Handle prompts.
+		switch p.prompt {
+		case '/', '?':
+			switch key {
+			case tcell.KeyRune:
+				p.input += string(event.Rune())
+			case tcell.KeyBackspace, tcell.KeyBackspace2:
+				if p.input == "" {
+					p.prompt = 0
+				} else {
+					runes := []rune(p.input)
+					p.input = string(runes[:len(runes)-1])
+				}
+			case tcell.KeyEnter:
+				if p.input != "" {
+					p.search = strings.ToLower(p.input)
+					p.backward = p.prompt == '?'
+				}
+				p.prompt = 0
+				p.find(false)
+			case tcell.KeyEscape:
+				p.prompt = 0
+			}
+			return
+		case 'm', '\'':
+			prompt := p.prompt
+			p.prompt = 0
+			if key != tcell.KeyRune {
+				return
+			}
+			mark := event.Rune()
+			if prompt == 'm' {
+				p.marks[mark] = p.lineOffset
+			} else if mark == '\'' {
+				p.jump(p.previousOffset)
+			} else if line, ok := p.marks[mark]; ok {
+				p.jump(line)
+			} else {
+				p.message = "Mark not set"
+			}
+			return
+		}
+
+		// Scroll.
+		halfPage := p.pageSize / 2
+		if halfPage < 1 {
+			halfPage = 1
+		}
+		halfWidth := p.pageWidth / 2
+		if halfWidth < 1 {
+			halfWidth = 1
+		}
+		switch key {
+		case tcell.KeyDown, tcell.KeyEnter, tcell.KeyCtrlN, tcell.KeyCtrlE:
+			p.lineOffset++
+		case tcell.KeyUp, tcell.KeyCtrlP, tcell.KeyCtrlY:
+			p.lineOffset--
+		case tcell.KeyPgDn, tcell.KeyCtrlF, tcell.KeyCtrlV:
+			p.lineOffset += p.pageSize
+		case tcell.KeyPgUp, tcell.KeyCtrlB:
+			p.lineOffset -= p.pageSize
+		case tcell.KeyCtrlD:
+			p.lineOffset += halfPage
+		case tcell.KeyCtrlU:
+			p.lineOffset -= halfPage
+		case tcell.KeyHome:
+			p.jump(0)
+		case tcell.KeyEnd:
+			p.jump(len(p.lines))
+		case tcell.KeyLeft:
+			p.columnOffset -= halfWidth
+		case tcell.KeyRight:
+			p.columnOffset += halfWidth
+		case tcell.KeyEscape, tcell.KeyTab, tcell.KeyBacktab:
+			if p.done != nil {
+				p.done(key)
+			}
+		case tcell.KeyRune:
+			switch r := event.Rune(); r {
+			case 'j', 'e':
+				p.lineOffset++
+			case 'k', 'y':
+				p.lineOffset--
+			case ' ', 'f':
+				p.lineOffset += p.pageSize
+			case 'b':
+				p.lineOffset -= p.pageSize
+			case 'd':
+				p.lineOffset += halfPage
+			case 'u':
+				p.lineOffset -= halfPage
+			case 'g', '<':
+				p.jump(0)
+			case 'G', '>':
+				p.jump(len(p.lines))
+			case '/', '?', 'm', '\'':
+				p.prompt = r
+				p.input = ""
+			case 'n':
+				p.find(false)
+			case 'N':
+				p.find(true)
+			case 'q':
+				if p.done != nil {
+					p.done(key)
+				}
+			}
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (p *Pager) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return p.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(primitive Primitive)) (consumed bool, capture Primitive) {
+		if !p.InRect(event.Position()) {
+			return false, nil
+		}
+		switch action {
+		case MouseLeftClick:
+			setFocus(p)
+		case MouseScrollUp:
+			p.lineOffset -= 3
+		case MouseScrollDown:
+			p.lineOffset += 3
+		case MouseScrollLeft:
+			p.columnOffset -= 3
+		case MouseScrollRight:
+			p.columnOffset += 3
+		}
+		return true, nil
+	})
+}
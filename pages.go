@@ -1,21 +1,57 @@
 package tview
 
 import (
+	"encoding/json"
+	"sort"
+	"time"
+
 	"github.com/gdamore/tcell"
 )
 
+// PageTransition is an animation shown when Pages switches from one page to
+// another (see Pages.SetTransition()).
+type PageTransition int
+
+// Page transitions.
+const (
+	TransitionNone       PageTransition = iota // Pages are switched immediately.
+	TransitionSlideLeft                        // The new page pushes the old page out to the left.
+	TransitionSlideRight                       // The new page pushes the old page out to the right.
+	TransitionFade                             // The new page fades in from black.
+)
+
 // page represents one page of a Pages object.
 type page struct {
 	Name    string    // The page's name.
 	Item    Primitive // The page's primitive.
 	Resize  bool      // Whether or not to resize the page when it is drawn.
 	Visible bool      // Whether or not this page is visible.
+	Modal   bool      // Whether or not the pages beneath this page are blocked while it is visible.
+
+	Shown  func()           // An optional handler called when the page becomes visible.
+	Hidden func()           // An optional handler called when the page stops being visible.
+	Build  func() Primitive // If not nil, builds the page's primitive when the page is first shown.
+}
+
+// pageStackEntry is a page on the navigation stack of Pages (see PushPage()).
+type pageStackEntry struct {
+	name  string    // The name of the page.
+	focus Primitive // The primitive which had focus when another page was pushed.
 }
 
 // Pages is a container for other primitives often used as the application's
 // root primitive. It allows to easily switch the visibility of the contained
 // primitives.
 //
+// Pages can be marked as modal (see SetModal()). While a modal page is visible,
+// the pages beneath it are dimmed (see SetModalDimming()), don't receive mouse
+// events, and cannot keep the focus. The order in which pages are stacked can
+// be changed with SendToFront() and SendToBack().
+//
+// For drill-down navigation, pages can be pushed onto a stack with PushPage()
+// and removed again with PopPage() or PopToPage(), which return the focus to
+// where it was on the revealed page.
+//
 // See https://github.com/rivo/tview/wiki/Pages for an example.
 type Pages struct {
 	*Box
@@ -30,12 +66,42 @@ type Pages struct {
 	// An optional handler which is called whenever the visibility or the order of
 	// pages changes.
 	changed func()
+
+	// The navigation stack maintained by PushPage() and PopPage(). The last
+	// entry is the page on top.
+	stack []*pageStackEntry
+
+	// An optional handler which is called with the name of each page whose
+	// visibility changes.
+	pageChanged func(name string, visible bool)
+
+	// The factor by which the colors of pages beneath a visible modal page are
+	// attenuated.
+	dimming float64
+
+	// The animation shown when switching pages, its duration, and the function
+	// which is called repeatedly to redraw the pages during an animation.
+	transition         PageTransition
+	transitionDuration time.Duration
+	redraw             func()
+
+	// The pages which were visible before the last switch and the time of the
+	// switch, for animations.
+	transitionFrom    []*page
+	transitionStarted time.Time
+}
+
+// pagesState is the state of Pages saved with SaveState().
+type pagesState struct {
+	Order   []string `json:"order"`
+	Visible []string `json:"visible"`
 }
 
 // NewPages returns a new Pages object.
 func NewPages() *Pages {
 	p := &Pages{
-		Box: NewBox(),
+		Box:     NewBox(),
+		dimming: 0.5,
 	}
 	p.focus = p
 	return p
@@ -48,6 +114,93 @@ func (p *Pages) SetChangedFunc(handler func()) *Pages {
 	return p
 }
 
+// SetPageChangedFunc sets a handler which is called whenever a page becomes
+// visible or stops being visible, with the page's name and its new visibility.
+// It is called after the page's own handlers (see SetPageShownFunc() and
+// SetPageHiddenFunc()).
+func (p *Pages) SetPageChangedFunc(handler func(name string, visible bool)) *Pages {
+	p.pageChanged = handler
+	return p
+}
+
+// SetPageShownFunc sets a handler which is called whenever the page with the
+// given name becomes visible, e.g. to start refreshing its content in the
+// background. It is not called for a page which is already visible.
+func (p *Pages) SetPageShownFunc(name string, handler func()) *Pages {
+	for _, page := range p.pages {
+		if page.Name == name {
+			page.Shown = handler
+			break
+		}
+	}
+	return p
+}
+
+// SetPageHiddenFunc sets a handler which is called whenever the page with the
+// given name stops being visible, including when it is removed. This can be
+// used to stop background activity started in the page's shown handler.
+func (p *Pages) SetPageHiddenFunc(name string, handler func()) *Pages {
+	for _, page := range p.pages {
+		if page.Name == name {
+			page.Hidden = handler
+			break
+		}
+	}
+	return p
+}
+
+// visiblePages returns the pages which are currently visible.
+func (p *Pages) visiblePages() (visible []*page) {
+	for _, page := range p.pages {
+		if page.Visible {
+			visible = append(visible, page)
+		}
+	}
+	return
+}
+
+// updateVisibility compares the currently visible pages with the given pages
+// which were visible before and calls the handlers of the pages whose
+// visibility changed. Pages which are shown for the first time are built if
+// they were added with AddLazyPage().
+func (p *Pages) updateVisibility(before []*page) {
+	p.MarkDirty()
+	isVisible := func(pages []*page, pg *page) bool {
+		for _, page := range pages {
+			if page == pg {
+				return page.Visible
+			}
+		}
+		return false
+	}
+	for _, page := range before {
+		if isVisible(p.pages, page) {
+			continue
+		}
+		if page.Hidden != nil {
+			page.Hidden()
+		}
+		if p.pageChanged != nil {
+			p.pageChanged(page.Name, false)
+		}
+	}
+	for _, page := range p.pages {
+		if !page.Visible || isVisible(before, page) {
+			continue
+		}
+		if page.Build != nil {
+			page.Item = page.Build()
+			page.Build = nil
+		}
+		if page.Shown != nil {
+			page.Shown()
+		}
+		if p.pageChanged != nil {
+			p.pageChanged(page.Name, true)
+		}
+	}
+}
+
 // AddPage adds a new page with the given name and primitive. If there was
 // previously a page with the same name, it is overwritten. Leaving the name
 // empty may cause conflicts in other functions.
@@ -57,13 +210,28 @@ func (p *Pages) SetChangedFunc(handler func()) *Pages {
 // primitive will be set to the size available to the Pages primitive whenever
 // the pages are drawn.
 func (p *Pages) AddPage(name string, item Primitive, resize, visible bool) *Pages {
+	return p.addPage(&page{Item: item, Name: name, Resize: resize, Visible: visible})
+}
+
+// AddLazyPage adds a new page like AddPage() but its primitive is only created
+// by calling the provided function when the page is shown for the first time.
+// This avoids building the content of pages which are never displayed. Until
+// then, the page is represented by an empty Box.
+func (p *Pages) AddLazyPage(name string, build func() Primitive, resize, visible bool) *Pages {
+	return p.addPage(&page{Item: NewBox(), Build: build, Name: name, Resize: resize, Visible: visible})
+}
+
+// addPage adds the given page, replacing any page with the same name.
+func (p *Pages) addPage(newPage *page) *Pages {
+	before := p.visiblePages()
 	for index, pg := range p.pages {
-		if pg.Name == name {
+		if pg.Name == newPage.Name {
 			p.pages = append(p.pages[:index], p.pages[index+1:]...)
 			break
 		}
 	}
-	p.pages = append(p.pages, &page{Item: item, Name: name, Resize: resize, Visible: visible})
+	p.pages = append(p.pages, newPage)
+	p.updateVisibility(before)
 	if p.changed != nil {
 		p.changed()
 	}
@@ -84,9 +252,12 @@ func (p *Pages) AddAndSwitchToPage(name string, item Primitive, resize bool) *Pa
 // RemovePage removes the page with the given name.
 func (p *Pages) RemovePage(name string) *Pages {
 	hasFocus := p.HasFocus()
+	before := p.visiblePages()
 	for index, page := range p.pages {
 		if page.Name == name {
 			p.pages = append(p.pages[:index], p.pages[index+1:]...)
+			p.removeFromStack(name)
+			p.updateVisibility(before)
 			if page.Visible && p.changed != nil {
 				p.changed()
 			}
@@ -99,6 +270,125 @@ func (p *Pages) RemovePage(name string) *Pages {
 	return p
 }
 
+// PushPage adds a page like AddPage() and switches to it (see SwitchToPage()),
+// putting it on top of a navigation stack. The page which was shown before
+// remains on the stack beneath it, together with the primitive within it which
+// had focus. PopPage() returns to that page and restores its focus.
+//
+// If the stack is empty, the current front page (see GetFrontPage()) becomes
+// its first entry.
+func (p *Pages) PushPage(name string, item Primitive, resize bool) *Pages {
+	if len(p.stack) == 0 {
+		if front, frontItem := p.GetFrontPage(); frontItem != nil && front != name {
+			p.stack = append(p.stack, &pageStackEntry{name: front})
+		}
+	}
+	p.removeFromStack(name)
+	if len(p.stack) > 0 && p.HasFocus() {
+		top := p.stack[len(p.stack)-1]
+		top.focus = p.focusedPrimitive(top.name)
+	}
+	p.stack = append(p.stack, &pageStackEntry{name: name})
+	return p.AddAndSwitchToPage(name, item, resize)
+}
+
+// PopPage removes the page on top of the navigation stack (see PushPage()) and
+// switches back to the page beneath it, restoring the focus to the primitive
+// which had it before. Nothing happens if there is no page beneath the top
+// page.
+func (p *Pages) PopPage() *Pages {
+	if len(p.stack) < 2 {
+		return p
+	}
+	return p.PopToPage(p.stack[len(p.stack)-2].name)
+}
+
+// PopToPage removes all pages above the page with the given name from the
+// navigation stack (see PushPage()) and switches back to that page, restoring
+// the focus to the primitive which had it before. Nothing happens if the page
+// is not on the stack.
+func (p *Pages) PopToPage(name string) *Pages {
+	index := -1
+	for i, entry := range p.stack {
+		if entry.name == name {
+			index = i
+		}
+	}
+	if index < 0 || index == len(p.stack)-1 {
+		return p
+	}
+	revealed, popped := p.stack[index], p.stack[index+1:]
+	p.stack = p.stack[:index+1]
+
+	hasFocus := p.HasFocus()
+	p.SwitchToPage(name)
+	for _, entry := range popped {
+		p.RemovePage(entry.name)
+	}
+	if hasFocus && revealed.focus != nil && p.setFocus != nil {
+		// Restore the focus if the primitive is still part of the page.
+		for _, page := range p.pages {
+			if page.Name == name && containsPrimitive(page.Item, revealed.focus) {
+				p.setFocus(revealed.focus)
+				break
+			}
+		}
+	}
+	revealed.focus = nil
+	return p
+}
+
+// GetPageStack returns the names of the pages on the navigation stack (see
+// PushPage()), from the bottom to the top.
+func (p *Pages) GetPageStack() []string {
+	names := make([]string, 0, len(p.stack))
+	for _, entry := range p.stack {
+		names = append(names, entry.name)
+	}
+	return names
+}
+
+// removeFromStack removes the page with the given name from the navigation
+// stack.
+func (p *Pages) removeFromStack(name string) {
+	for index, entry := range p.stack {
+		if entry.name == name {
+			p.stack = append(p.stack[:index], p.stack[index+1:]...)
+			return
+		}
+	}
+}
+
+// focusedPrimitive returns the innermost primitive of the page with the given
+// name which has focus, or nil if none has.
+func (p *Pages) focusedPrimitive(name string) (focused Primitive) {
+	for _, page := range p.pages {
+		if page.Name == name {
+			WalkTree(page.Item, func(primitive, parent Primitive) bool {
+				if !primitive.GetFocusable().HasFocus() {
+					return false
+				}
+				focused = primitive
+				return true
+			})
+			break
+		}
+	}
+	return
+}
+
+// containsPrimitive returns whether the given primitive is "root" or contained
+// in it.
+func containsPrimitive(root, primitive Primitive) (found bool) {
+	WalkTree(root, func(p, parent Primitive) bool {
+		if p == primitive {
+			found = true
+		}
+		return !found
+	})
+	return
+}
+
 // HasPage returns true if a page with the given name exists in this object.
 func (p *Pages) HasPage(name string) bool {
 	for _, page := range p.pages {
@@ -112,9 +402,11 @@ func (p *Pages) HasPage(name string) bool {
 // ShowPage sets a page's visibility to "true" (in addition to any other pages
 // which are already visible).
 func (p *Pages) ShowPage(name string) *Pages {
+	before := p.visiblePages()
 	for _, page := range p.pages {
 		if page.Name == name {
 			page.Visible = true
+			p.updateVisibility(before)
 			if p.changed != nil {
 				p.changed()
 			}
@@ -129,9 +421,11 @@ func (p *Pages) ShowPage(name string) *Pages {
 
 // HidePage sets a page's visibility to "false".
 func (p *Pages) HidePage(name string) *Pages {
+	before := p.visiblePages()
 	for _, page := range p.pages {
 		if page.Name == name {
 			page.Visible = false
+			p.updateVisibility(before)
 			if p.changed != nil {
 				p.changed()
 			}
@@ -147,13 +441,24 @@ func (p *Pages) HidePage(name string) *Pages {
 // SwitchToPage sets a page's visibility to "true" and all other pages'
 // visibility to "false".
 func (p *Pages) SwitchToPage(name string) *Pages {
-	for _, page := range p.pages {
-		if page.Name == name {
-			page.Visible = true
-		} else {
-			page.Visible = false
+	var (
+		from    []*page
+		changed bool
+	)
+	before := p.visiblePages()
+	for _, pg := range p.pages {
+		visible := pg.Name == name
+		if pg.Visible && !visible {
+			previous := *pg
+			from = append(from, &previous)
 		}
+		changed = changed || pg.Visible != visible
+		pg.Visible = visible
+	}
+	if changed && len(from) > 0 {
+		p.startTransition(from)
 	}
+	p.updateVisibility(before)
 	if p.changed != nil {
 		p.changed()
 	}
@@ -172,6 +477,7 @@ func (p *Pages) SendToFront(name string) *Pages {
 			if index < len(p.pages)-1 {
 				p.pages = append(append(p.pages[:index], p.pages[index+1:]...), page)
 			}
+			p.MarkDirty()
 			if page.Visible && p.changed != nil {
 				p.changed()
 			}
@@ -193,6 +499,7 @@ func (p *Pages) SendToBack(name string) *Pages {
 			if index > 0 {
 				p.pages = append(append([]*page{pg}, p.pages[:index]...), p.pages[index+1:]...)
 			}
+			p.MarkDirty()
 			if pg.Visible && p.changed != nil {
 				p.changed()
 			}
@@ -205,6 +512,109 @@ func (p *Pages) SendToBack(name string) *Pages {
 	return p
 }
 
+// SetModal sets whether or not the page with the given name is modal. While a
+// modal page is visible, the pages beneath it are dimmed, don't receive mouse
+// events, and cannot keep the focus.
+func (p *Pages) SetModal(name string, modal bool) *Pages {
+	for _, page := range p.pages {
+		if page.Name == name {
+			page.Modal = modal
+			p.MarkDirty()
+			if page.Visible && p.changed != nil {
+				p.changed()
+			}
+			break
+		}
+	}
+	if p.HasFocus() {
+		p.Focus(p.setFocus)
+	}
+	return p
+}
+
+// SetModalDimming sets the factor by which the colors of the pages beneath a
+// visible modal page are attenuated, a value between 0 (black) and 1 (no
+// change). The default is 0.5. Colors which are not RGB colors or palette
+// colors are shown with the terminal's "dim" attribute instead.
+func (p *Pages) SetModalDimming(factor float64) *Pages {
+	p.dimming = factor
+	return p
+}
+
+// SetTransition sets the animation shown by SwitchToPage() when switching
+// from one page to another, running over the given duration. As Pages cannot
+// redraw itself, the provided function is called repeatedly while an animation
+// is running. It will usually call Application.Draw(). It is called from a
+// different goroutine. The default is TransitionNone.
+func (p *Pages) SetTransition(transition PageTransition, duration time.Duration, redraw func()) *Pages {
+	p.transition = transition
+	p.transitionDuration = duration
+	p.redraw = redraw
+	return p
+}
+
+// startTransition starts the page transition animation from the given pages,
+// which were visible before the switch but are hidden now.
+func (p *Pages) startTransition(from []*page) {
+	if p.transition == TransitionNone || p.transitionDuration <= 0 {
+		return
+	}
+	p.transitionFrom = from
+	p.transitionStarted = time.Now()
+
+	// Keep redrawing until the animation is over.
+	if p.redraw != nil {
+		end, redraw := p.transitionStarted.Add(p.transitionDuration), p.redraw
+		var tick func()
+		tick = func() {
+			p.MarkDirty()
+			redraw()
+			if time.Now().Before(end) {
+				time.AfterFunc(25*time.Millisecond, tick)
+			}
+		}
+		time.AfterFunc(25*time.Millisecond, tick)
+	}
+}
+
+// transitionProgress returns how far the current page transition has
+// progressed, from 0 (just started) to 1 (finished or no transition).
+func (p *Pages) transitionProgress() float64 {
+	if p.transition == TransitionNone || p.transitionDuration <= 0 || p.transitionStarted.IsZero() {
+		return 1
+	}
+	progress := float64(time.Since(p.transitionStarted)) / float64(p.transitionDuration)
+	if progress >= 1 {
+		p.transitionFrom = nil
+		p.transitionStarted = time.Time{}
+		return 1
+	}
+	return progress
+}
+
+// GetFrontPage returns the name and primitive of the page which is drawn last,
+// i.e. the visible page on top of all others. If no page is visible, an empty
+// name and nil are returned.
+func (p *Pages) GetFrontPage() (name string, item Primitive) {
+	for index := len(p.pages) - 1; index >= 0; index-- {
+		if p.pages[index].Visible {
+			return p.pages[index].Name, p.pages[index].Item
+		}
+	}
+	return
+}
+
+// modalIndex returns the index of the topmost visible modal page, or -1 if
+// there is none.
+func (p *Pages) modalIndex() int {
+	for index := len(p.pages) - 1; index >= 0; index-- {
+		if p.pages[index].Visible && p.pages[index].Modal {
+			return index
+		}
+	}
+	return -1
+}
+
 // HasFocus returns whether or not this primitive has focus.
 func (p *Pages) HasFocus() bool {
 	for _, page := range p.pages {
@@ -229,16 +639,154 @@ func (p *Pages) Focus(delegate func(p Primitive)) {
 	}
 }
 
+// GetChildren returns the primitives of all pages, visible or not, in the order in which they
+// are drawn.
+func (p *Pages) GetChildren() []Primitive {
+	children := make([]Primitive, 0, len(p.pages))
+	for _, page := range p.pages {
+		children = append(children, page.Item)
+	}
+	return children
+}
+
+// SaveState returns the order of the pages and the names of the visible pages
+// as JSON. See SaveLayout() for details.
+func (p *Pages) SaveState() ([]byte, error) {
+	var state pagesState
+	for _, page := range p.pages {
+		state.Order = append(state.Order, page.Name)
+		if page.Visible {
+			state.Visible = append(state.Visible, page.Name)
+		}
+	}
+	return json.Marshal(state)
+}
+
+// RestoreState restores a state returned by SaveState(). Pages which did not
+// exist when the state was saved are placed behind all other pages and are
+// hidden.
+func (p *Pages) RestoreState(data []byte) error {
+	var state pagesState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	visible := make(map[string]bool)
+	for _, name := range state.Visible {
+		visible[name] = true
+	}
+	order := make(map[string]int)
+	for index, name := range state.Order {
+		order[name] = index + 1
+	}
+	before := p.visiblePages()
+	sort.SliceStable(p.pages, func(i, j int) bool {
+		return order[p.pages[i].Name] < order[p.pages[j].Name]
+	})
+	for _, page := range p.pages {
+		page.Visible = visible[page.Name]
+	}
+	p.updateVisibility(before)
+	if p.changed != nil {
+		p.changed()
+	}
+	if p.HasFocus() {
+		p.Focus(p.setFocus)
+	}
+	return nil
+}
+
 // Draw draws this primitive onto the screen.
 func (p *Pages) Draw(screen tcell.Screen) {
-	for _, page := range p.pages {
+	p.markDrawn(screen)
+	modal := p.modalIndex()
+
+	// Pages beneath a modal page cannot keep the focus.
+	if modal > 0 && p.setFocus != nil {
+		for _, page := range p.pages[:modal] {
+			if page.Item.GetFocusable().HasFocus() {
+				p.Focus(p.setFocus)
+				break
+			}
+		}
+	}
+
+	// While a transition is running, the pages change with every frame.
+	progress := p.transitionProgress()
+	if progress < 1 {
+		defer p.MarkDirty()
+	}
+
+	// Slide transitions draw the old and the new pages side by side.
+	if progress < 1 && (p.transition == TransitionSlideLeft || p.transition == TransitionSlideRight) {
+		x, y, width, height := p.GetInnerRect()
+		clipped := &clippedScreen{Screen: screen, x: x, y: y, width: width, height: height}
+		shift := int(progress * float64(width))
+		from, to := -shift, width-shift
+		if p.transition == TransitionSlideRight {
+			from, to = shift, shift-width
+		}
+		p.drawPages(clipped, p.transitionFrom, -1, from)
+		p.drawPages(clipped, p.pages, modal, to)
+		return
+	}
+
+	p.drawPages(screen, p.pages, modal, 0)
+	if progress < 1 && p.transition == TransitionFade {
+		x, y, width, height := p.GetInnerRect()
+		dimRect(screen, x, y, width, height, progress)
+	}
+}
+
+// drawPages draws the visible pages among the given pages, shifted
+// horizontally by "dx" cells. The pages' rectangles are restored afterwards.
+// The pages beneath the page with the index "modal" are dimmed.
+func (p *Pages) drawPages(screen tcell.Screen, pages []*page, modal, dx int) {
+	for index, page := range pages {
+		if index == modal && p.dimming < 1 {
+			x, y, width, height := p.GetRect()
+			dimRect(screen, x, y, width, height, p.dimming)
+		}
 		if !page.Visible {
 			continue
 		}
 		if page.Resize {
-			x, y, width, height := p.GetInnerRect()
-			page.Item.SetRect(x, y, width, height)
+			page.Item.SetRect(p.GetInnerRect())
+		}
+		if dx != 0 {
+			x, y, width, height := page.Item.GetRect()
+			page.Item.SetRect(x+dx, y, width, height)
+			defer page.Item.SetRect(x, y, width, height)
 		}
-		page.Item.Draw(screen)
+		drawPrimitive(screen, page.Item)
 	}
 }
+
+// MouseHandler returns the mouse handler for this primitive.
+func (p *Pages) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return p.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if !p.InRect(event.Position()) {
+			return false, nil
+		}
+
+		// Pass mouse events along to the last visible page item that takes it.
+		// Pages beneath a modal page don't receive any.
+		modal := p.modalIndex()
+		if modal < 0 {
+			modal = 0
+		}
+		for index := len(p.pages) - 1; index >= modal; index-- {
+			page := p.pages[index]
+			if !page.Visible {
+				continue
+			}
+			if handler := mouseHandler(page.Item); handler != nil {
+				consumed, capture = handler(action, event, setFocus)
+				if consumed {
+					return
+				}
+			}
+		}
+
+		return
+	})
+}
@@ -0,0 +1,112 @@
+package tview
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell"
+)
+
+func TestPagesTransitionDirtyTracking(t *testing.T) {
+	pages := NewPages().
+		AddPage("a", NewTextView().SetText(strings.Repeat("a", 20)), true, true).
+		AddPage("b", NewTextView().SetText(strings.Repeat("b", 20)), true, false)
+	app, screen := newTestApplication(t, pages, 20, 2)
+	app.EnableDirtyTracking(true)
+	pages.SetTransition(TransitionSlideLeft, 50*time.Millisecond, func() { app.Draw() })
+	app.Draw()
+
+	pages.SwitchToPage("b")
+	app.Draw()
+	time.Sleep(200 * time.Millisecond)
+	if line := screenLine(screen, 0); line != strings.Repeat("b", 20) {
+		t.Errorf("after the transition, the screen shows %q", line)
+	}
+}
+
+// newTransitionPages returns pages "a" and "b", filled with their names, and
+// an application drawing them on a screen of 20x1 cells.
+func newTransitionPages(t *testing.T, transition PageTransition) (*Pages, *Application, tcell.SimulationScreen) {
+	pages := NewPages().
+		AddPage("a", NewBox().SetBackgroundRune('a'), true, true).
+		AddPage("b", NewBox().SetBackgroundRune('b').SetBackgroundColor(tcell.ColorNavy), true, false).
+		SetTransition(transition, time.Second, nil)
+	app, screen := newTestApplication(t, pages, 20, 1)
+	app.Draw()
+	return pages, app, screen
+}
+
+func TestPagesSlideTransition(t *testing.T) {
+	for _, test := range []struct {
+		transition PageTransition
+		want       string
+	}{
+		{TransitionSlideLeft, strings.Repeat("a", 15) + strings.Repeat("b", 5)},
+		{TransitionSlideRight, strings.Repeat("b", 5) + strings.Repeat("a", 15)},
+	} {
+		pages, app, screen := newTransitionPages(t, test.transition)
+		pages.SwitchToPage("b")
+		pages.transitionStarted = time.Now().Add(-250 * time.Millisecond)
+		app.Draw()
+		if line := screenLine(screen, 0); line != test.want {
+			t.Errorf("transition %d at 25%%: screen shows %q, want %q", test.transition, line, test.want)
+		}
+
+		// The pages are back in place.
+		for _, page := range pages.pages {
+			if x, _, width, _ := page.Item.GetRect(); x != 0 || width != 20 {
+				t.Errorf("transition %d: page %s was left at x=%d, width %d", test.transition, page.Name, x, width)
+			}
+		}
+
+		pages.transitionStarted = time.Now().Add(-2 * time.Second)
+		app.Draw()
+		if line := screenLine(screen, 0); line != strings.Repeat("b", 20) {
+			t.Errorf("transition %d after the end: screen shows %q", test.transition, line)
+		}
+	}
+}
+
+func TestPagesSlideTransitionFromSharedPage(t *testing.T) {
+	// "b" is already visible on top of "a" and remains visible. Only "a"
+	// slides out.
+	pages, app, screen := newTransitionPages(t, TransitionSlideLeft)
+	pages.ShowPage("b")
+	app.Draw()
+	pages.SwitchToPage("b")
+	if len(pages.transitionFrom) != 1 || pages.transitionFrom[0].Name != "a" {
+		t.Fatalf("transition starts from %d pages, want only page a", len(pages.transitionFrom))
+	}
+	pages.transitionStarted = time.Now().Add(-500 * time.Millisecond)
+	app.Draw()
+	if line, want := screenLine(screen, 0), strings.Repeat("a", 10)+strings.Repeat("b", 10); line != want {
+		t.Errorf("screen shows %q, want %q", line, want)
+	}
+
+	// Switching to the only visible page starts no transition.
+	pages.transitionStarted = time.Time{}
+	pages.SwitchToPage("b")
+	if !pages.transitionStarted.IsZero() {
+		t.Error("a transition was started although no page was hidden")
+	}
+}
+
+func TestPagesFadeTransition(t *testing.T) {
+	pages, app, screen := newTransitionPages(t, TransitionFade)
+	pages.SwitchToPage("b")
+	pages.transitionStarted = time.Now().Add(-500 * time.Millisecond)
+	app.Draw()
+	ch, _, style, _ := screen.GetContent(0, 0)
+	_, background, _ := style.Decompose()
+	if r, g, b := background.RGB(); ch != 'b' || r != 0 || g != 0 || b != 64 {
+		t.Errorf("at 50%%, the new page shows %q with background %d,%d,%d, want 'b' with 0,0,64", ch, r, g, b)
+	}
+
+	pages.transitionStarted = time.Now().Add(-2 * time.Second)
+	app.Draw()
+	_, _, style, _ = screen.GetContent(0, 0)
+	if _, background, _ = style.Decompose(); background != tcell.ColorNavy {
+		t.Errorf("after the transition, the background is %v, want navy", background)
+	}
+}
@@ -44,3 +44,108 @@ type Primitive interface {
 	// GetFocusable returns the item's Focusable.
 	GetFocusable() Focusable
 }
+
+// MouseReceiver is implemented by primitives which process mouse events. All
+// primitives based on Box implement it. Primitives which don't are skipped
+// when mouse events are passed on.
+type MouseReceiver interface {
+	// MouseHandler returns a handler which receives mouse events. It is called
+	// by the Application class if mouse events were enabled (see
+	// Application.EnableMouse()).
+	//
+	// A value of nil may also be returned, in which case this primitive does not
+	// process any mouse events.
+	//
+	// The handler receives the mouse action (see the MouseAction constants), the
+	// original tcell mouse event, and a function that allows it to set the focus
+	// to a different primitive. It returns whether or not it has consumed the
+	// event. It may also return a primitive which will then receive all
+	// subsequent mouse events until it returns nil for this value (e.g. to
+	// implement dragging). Containers pass mouse events on to their contained
+	// primitives until one of them consumes the event.
+	//
+	// The Application's Draw() function will be called automatically after the
+	// handler returns if the event was consumed.
+	//
+	// If you subclass from Box, it is recommended that you wrap your handler
+	// using Box.wrapMouseHandler() so you inherit its functionality.
+	MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive)
+}
+
+// mouseHandler returns the mouse handler of the given primitive or nil if it
+// does not process mouse events (see MouseReceiver).
+func mouseHandler(p Primitive) func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	if receiver, ok := p.(MouseReceiver); ok {
+		return receiver.MouseHandler()
+	}
+	return nil
+}
+
+// Container is implemented by primitives which contain other primitives, such
+// as Flex, Grid, Pages, or Form. It allows to traverse the hierarchy of
+// primitives generically, see WalkTree().
+type Container interface {
+	Primitive
+
+	// GetChildren returns the primitives directly contained in this primitive.
+	// Hidden children (e.g. invisible pages) are included.
+	GetChildren() []Primitive
+}
+
+// Paster is implemented by primitives which accept pasted text as a whole
+// instead of as individual key events (see Application.EnableBracketedPaste()),
+// e.g. to insert it in one step.
+type Paster interface {
+	// PasteHandler returns a handler which receives pasted text when the
+	// primitive has focus, and a function that allows it to set the focus to a
+	// different primitive. It may return nil, in which case the text is sent as
+	// key events.
+	PasteHandler() func(text string, setFocus func(p Primitive))
+}
+
+// WalkTree calls "visit" for the given primitive and then, depth-first, for
+// all primitives contained in it (see Container). "visit" receives each
+// primitive and the container it was found in, which is nil for the root. If
+// it returns false, the primitives contained in that primitive are skipped. A
+// primitive which is contained in multiple containers is visited multiple
+// times.
+func WalkTree(root Primitive, visit func(p, parent Primitive) bool) {
+	var walk func(p, parent Primitive)
+	walk = func(p, parent Primitive) {
+		if !visit(p, parent) {
+			return
+		}
+		if container, ok := p.(Container); ok {
+			for _, child := range container.GetChildren() {
+				walk(child, p)
+			}
+		}
+	}
+	walk(root, nil)
+}
+
+// primitivePath returns the primitives from "root" down to "target",
+// including both, following the children of containers (see Container). It
+// returns nil if "target" is not found.
+func primitivePath(root, target Primitive) (path []Primitive) {
+	var find func(p Primitive) bool
+	find = func(p Primitive) bool {
+		path = append(path, p)
+		if p == target {
+			return true
+		}
+		if container, ok := p.(Container); ok {
+			for _, child := range container.GetChildren() {
+				if find(child) {
+					return true
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		return false
+	}
+	if !find(root) {
+		return nil
+	}
+	return
+}
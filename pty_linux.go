@@ -0,0 +1,64 @@
+package tview
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+	"unsafe"
+)
+
+// ioctl performs an ioctl system call on the given file.
+func ioctl(file *os.File, request uintptr, argument unsafe.Pointer) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, file.Fd(), request, uintptr(argument))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// openPTY opens a new pseudo terminal, returning its master and slave side.
+func openPTY() (pty, tty *os.File, err error) {
+	pty, err = os.OpenFile("/dev/ptmx", os.O_RDWR, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	var number uint32
+	if err = ioctl(pty, syscall.TIOCGPTN, unsafe.Pointer(&number)); err != nil {
+		pty.Close()
+		return nil, nil, err
+	}
+	var unlock int32
+	if err = ioctl(pty, syscall.TIOCSPTLCK, unsafe.Pointer(&unlock)); err != nil {
+		pty.Close()
+		return nil, nil, err
+	}
+	tty, err = os.OpenFile("/dev/pts/"+strconv.Itoa(int(number)), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		pty.Close()
+		return nil, nil, err
+	}
+	return pty, tty, nil
+}
+
+// setPTYSize informs the program running in the given pseudo terminal about
+// the terminal's size.
+func setPTYSize(pty *os.File, width, height int) error {
+	size := struct {
+		rows, columns, x, y uint16
+	}{
+		rows:    uint16(height),
+		columns: uint16(width),
+	}
+	return ioctl(pty, syscall.TIOCSWINSZ, unsafe.Pointer(&size))
+}
+
+// attachPTY prepares the given command to run in a new session with the
+// given pseudo terminal as its controlling terminal.
+func attachPTY(cmd *exec.Cmd, tty *os.File) {
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = tty, tty, tty
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setsid:  true,
+		Setctty: true,
+	}
+}
@@ -0,0 +1,25 @@
+// +build !linux
+
+package tview
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+// openPTY opens a new pseudo terminal. This is not supported on this platform.
+func openPTY() (pty, tty *os.File, err error) {
+	return nil, nil, errors.New("pseudo terminals are not supported on this platform")
+}
+
+// setPTYSize informs the program running in the given pseudo terminal about
+// the terminal's size.
+func setPTYSize(pty *os.File, width, height int) error {
+	return nil
+}
+
+// attachPTY prepares the given command to run with the given pseudo terminal.
+func attachPTY(cmd *exec.Cmd, tty *os.File) {
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = tty, tty, tty
+}
@@ -0,0 +1,187 @@
+package tview
+
+import (
+	"github.com/gdamore/tcell"
+)
+
+// responsiveLayout is one of the layouts of a Responsive container.
+type responsiveLayout struct {
+	Item                Primitive // The layout's primitive.
+	MinWidth, MinHeight int       // The minimum size of the container for this layout to be used.
+}
+
+// Responsive is a container which switches between alternative layouts
+// depending on the space available to it. Each layout is a primitive (e.g. a
+// Flex or a Grid) with a minimum width and height. Whenever the container is
+// drawn, the layout which fits best is chosen, so the arrangement adapts when
+// the terminal is resized. For example, three columns may be shown on wide
+// screens and the same primitives stacked in rows on narrow ones:
+//
+//   responsive := tview.NewResponsive().
+//     AddLayout(rows, 0, 0).
+//     AddLayout(columns, 120, 0)
+//
+// The layouts may share primitives. If a layout which contains the focused
+// primitive is replaced by one which doesn't, the new layout receives focus.
+type Responsive struct {
+	*Box
+
+	// The available layouts.
+	layouts []*responsiveLayout
+
+	// The layout used the last time the container was drawn, or nil.
+	current *responsiveLayout
+
+	// We keep a reference to the function which allows us to set the focus to
+	// a newly chosen layout.
+	setFocus func(p Primitive)
+
+	// An optional handler which is called when a different layout is chosen.
+	changed func(layout Primitive)
+}
+
+// NewResponsive returns a new responsive container without any layouts.
+func NewResponsive() *Responsive {
+	r := &Responsive{
+		Box: NewBox(),
+	}
+	r.focus = r
+	return r
+}
+
+// AddLayout adds a layout which is used when the container is at least
+// "minWidth" cells wide and "minHeight" cells high. If multiple layouts apply,
+// the one with the highest minimum width is used. Among those, the one with
+// the highest minimum height is used, or the one added last if those values
+// are the same. If no layout applies, nothing is drawn.
+func (r *Responsive) AddLayout(layout Primitive, minWidth, minHeight int) *Responsive {
+	r.layouts = append(r.layouts, &responsiveLayout{
+		Item:      layout,
+		MinWidth:  minWidth,
+		MinHeight: minHeight,
+	})
+	return r
+}
+
+// RemoveLayout removes all layouts with the given primitive.
+func (r *Responsive) RemoveLayout(layout Primitive) *Responsive {
+	for index := len(r.layouts) - 1; index >= 0; index-- {
+		if r.layouts[index].Item == layout {
+			if r.layouts[index] == r.current {
+				r.current = nil
+			}
+			r.layouts = append(r.layouts[:index], r.layouts[index+1:]...)
+		}
+	}
+	return r
+}
+
+// Clear removes all layouts from the container.
+func (r *Responsive) Clear() *Responsive {
+	r.layouts = nil
+	r.current = nil
+	return r
+}
+
+// GetCurrentLayout returns the primitive of the layout chosen the last time
+// the container was drawn, or nil if none was chosen.
+func (r *Responsive) GetCurrentLayout() Primitive {
+	if r.current == nil {
+		return nil
+	}
+	return r.current.Item
+}
+
+// SetChangedFunc sets a handler which is called when the container switches
+// to a different layout. It receives the new layout's primitive, which is nil
+// if no layout applies. The handler is called while the container is drawn.
+func (r *Responsive) SetChangedFunc(handler func(layout Primitive)) *Responsive {
+	r.changed = handler
+	return r
+}
+
+// choose returns the layout which fits best into the given size, or nil if
+// none does.
+func (r *Responsive) choose(width, height int) *responsiveLayout {
+	var chosen *responsiveLayout
+	for _, layout := range r.layouts {
+		if width < layout.MinWidth || height < layout.MinHeight {
+			continue
+		}
+		if chosen != nil && (layout.MinWidth < chosen.MinWidth ||
+			layout.MinWidth == chosen.MinWidth && layout.MinHeight < chosen.MinHeight) {
+			continue
+		}
+		chosen = layout
+	}
+	return chosen
+}
+
+// GetChildren returns the primitives of all layouts, not only the current one.
+func (r *Responsive) GetChildren() []Primitive {
+	children := make([]Primitive, 0, len(r.layouts))
+	for _, layout := range r.layouts {
+		children = append(children, layout.Item)
+	}
+	return children
+}
+
+// Draw draws this primitive onto the screen.
+func (r *Responsive) Draw(screen tcell.Screen) {
+	r.Box.Draw(screen)
+	x, y, width, height := r.GetInnerRect()
+
+	// Switch layouts if necessary.
+	layout := r.choose(width, height)
+	if layout != r.current {
+		hadFocus := r.current != nil && r.current.Item.GetFocusable().HasFocus()
+		r.current = layout
+		if layout != nil && hadFocus && !layout.Item.GetFocusable().HasFocus() && r.setFocus != nil {
+			r.setFocus(layout.Item)
+		}
+		if r.changed != nil {
+			r.changed(r.GetCurrentLayout())
+		}
+	}
+	if layout == nil {
+		return
+	}
+
+	layout.Item.SetRect(x, y, width, height)
+	layout.Item.Draw(screen)
+}
+
+// Focus is called when this primitive receives focus.
+func (r *Responsive) Focus(delegate func(p Primitive)) {
+	r.setFocus = delegate
+	if r.current == nil {
+		_, _, width, height := r.GetInnerRect()
+		r.current = r.choose(width, height)
+	}
+	if r.current != nil {
+		delegate(r.current.Item)
+		return
+	}
+	r.hasFocus = true
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (r *Responsive) HasFocus() bool {
+	if r.current != nil {
+		return r.current.Item.GetFocusable().HasFocus()
+	}
+	return r.hasFocus
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (r *Responsive) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return r.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if !r.InRect(event.Position()) || r.current == nil {
+			return false, nil
+		}
+		if handler := mouseHandler(r.current.Item); handler != nil {
+			return handler(action, event, setFocus)
+		}
+		return
+	})
+}
@@ -0,0 +1,90 @@
+package tview
+
+import "github.com/gdamore/tcell"
+
+// ScrollBarVisibility determines when a primitive shows a scroll bar.
+type ScrollBarVisibility int
+
+// Scroll bar visibility settings.
+const (
+	ScrollBarNever  ScrollBarVisibility = iota // Never show a scroll bar.
+	ScrollBarAuto                              // Show a scroll bar if the content does not fit.
+	ScrollBarAlways                            // Always show a scroll bar.
+)
+
+// scrollBar holds the scroll bar settings of a scrollable primitive and draws
+// its scroll bars. The runes are taken from Styles when drawing.
+type scrollBar struct {
+	// Determines when the scroll bar is shown.
+	visibility ScrollBarVisibility
+
+	// The color of the track and the color of the thumb.
+	trackColor, thumbColor tcell.Color
+}
+
+// newScrollBar returns scroll bar settings with the given visibility and the
+// colors of the given theme.
+func newScrollBar(visibility ScrollBarVisibility, styles *Theme) scrollBar {
+	return scrollBar{
+		visibility: visibility,
+		trackColor: styles.ScrollBarTrackColor,
+		thumbColor: styles.ScrollBarThumbColor,
+	}
+}
+
+// shown returns whether the scroll bar is shown for content of size "total" of
+// which "visible" units fit into the available space.
+func (s *scrollBar) shown(visible, total int) bool {
+	switch s.visibility {
+	case ScrollBarAlways:
+		return true
+	case ScrollBarAuto:
+		return total > visible
+	}
+	return false
+}
+
+// draw draws a scroll bar of the given length, starting at the given position,
+// for content of size "total" of which "visible" units are visible starting at
+// "offset". If all of the content is visible, the thumb fills the track.
+func (s *scrollBar) draw(screen tcell.Screen, x, y, length, offset, visible, total int, vertical bool, backgroundColor tcell.Color) {
+	if length <= 0 {
+		return
+	}
+
+	// Determine the size and position of the thumb.
+	thumb, start := length, 0
+	if total > visible {
+		thumb = length * visible / total
+		if thumb < 1 {
+			thumb = 1
+		}
+		start = (length - thumb) * offset / (total - visible)
+		if start > length-thumb {
+			start = length - thumb
+		}
+		if start < 0 {
+			start = 0
+		}
+	}
+
+	// Draw it.
+	background := tcell.StyleDefault.Background(backgroundColor)
+	for index := 0; index < length; index++ {
+		r, style := Styles.ScrollBarTrack, background.Foreground(s.trackColor)
+		if index >= start && index < start+thumb {
+			r, style = Styles.ScrollBarThumb, background.Foreground(s.thumbColor)
+		}
+		if vertical {
+			screen.SetContent(x, y+index, r, nil, style)
+		} else {
+			screen.SetContent(x+index, y, r, nil, style)
+		}
+	}
+}
+
+// applyTheme is called when the application's theme changes.
+func (s *scrollBar) applyTheme(from, to *Theme) {
+	themeColor(&s.trackColor, from.ScrollBarTrackColor, to.ScrollBarTrackColor)
+	themeColor(&s.thumbColor, from.ScrollBarThumbColor, to.ScrollBarThumbColor)
+}
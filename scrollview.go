@@ -0,0 +1,406 @@
+package tview
+
+import (
+	"encoding/json"
+
+	"github.com/gdamore/tcell"
+)
+
+// clippedScreen is a screen which forwards all output inside a rectangle to
+// another screen and discards everything outside of it. It remembers where
+// the cursor was last shown.
+type clippedScreen struct {
+	tcell.Screen
+
+	// The rectangle which may be drawn into.
+	x, y, width, height int
+
+	// The last cursor position and whether or not the cursor was shown.
+	cursorX, cursorY int
+	cursorShown      bool
+}
+
+// inside returns whether the given position is inside the clipping rectangle.
+func (c *clippedScreen) inside(x, y int) bool {
+	return x >= c.x && x < c.x+c.width && y >= c.y && y < c.y+c.height
+}
+
+// SetContent sets the contents of a cell if it is inside the clipping
+// rectangle.
+func (c *clippedScreen) SetContent(x, y int, mainc rune, combc []rune, style tcell.Style) {
+	if c.inside(x, y) {
+		c.Screen.SetContent(x, y, mainc, combc, style)
+	}
+}
+
+// SetCell sets the contents of a cell if it is inside the clipping rectangle.
+func (c *clippedScreen) SetCell(x, y int, style tcell.Style, ch ...rune) {
+	if c.inside(x, y) {
+		c.Screen.SetCell(x, y, style, ch...)
+	}
+}
+
+// Fill fills the clipping rectangle with the given rune and style.
+func (c *clippedScreen) Fill(r rune, style tcell.Style) {
+	for y := c.y; y < c.y+c.height; y++ {
+		for x := c.x; x < c.x+c.width; x++ {
+			c.Screen.SetContent(x, y, r, nil, style)
+		}
+	}
+}
+
+// Clear clears the clipping rectangle.
+func (c *clippedScreen) Clear() {
+	c.Fill(' ', tcell.StyleDefault)
+}
+
+// ShowCursor shows the cursor if it is inside the clipping rectangle and hides
+// it otherwise.
+func (c *clippedScreen) ShowCursor(x, y int) {
+	c.cursorX, c.cursorY, c.cursorShown = x, y, true
+	if c.inside(x, y) {
+		c.Screen.ShowCursor(x, y)
+	} else {
+		c.Screen.HideCursor()
+	}
+}
+
+// ScrollView is a container for a primitive which may be larger than the
+// space available to it. The primitive is given its full size (see
+// SetContentSize()) and only the part within the scroll view's viewport is
+// visible. Scroll bars are shown for the directions in which the content does
+// not fit.
+//
+// If the contained primitive shows a cursor (e.g. a focused input field of a
+// form), the view scrolls to keep the cursor visible.
+//
+// The following keys can be used to scroll while the scroll view has focus and
+// its content doesn't (see SetContent()):
+//
+//   - Arrow keys, h, j, k, l: Scroll by one row or column.
+//   - Page Up, Page Down: Scroll by one page.
+//   - Home, g: Scroll to the top-left corner.
+//   - End, G: Scroll to the bottom.
+//
+// The mouse wheel scrolls the view if the content does not handle it.
+type ScrollView struct {
+	*Box
+
+	// The contained primitive.
+	content Primitive
+
+	// Whether or not the content receives focus when the scroll view does.
+	contentFocus bool
+
+	// The size of the content. Values of 0 or less mean that the content has
+	// the viewport's size in that direction.
+	contentWidth, contentHeight int
+
+	// The number of rows and columns by which the content is scrolled.
+	rowOffset, columnOffset int
+
+	// The size of the viewport the last time the view was drawn.
+	pageWidth, pageHeight int
+
+	// The scroll bar settings.
+	scrollBar scrollBar
+}
+
+// scrollViewState is the state of a ScrollView saved with SaveState().
+type scrollViewState struct {
+	RowOffset    int `json:"rowOffset"`
+	ColumnOffset int `json:"columnOffset"`
+}
+
+// NewScrollView returns a new, empty scroll view.
+func NewScrollView() *ScrollView {
+	styles := Styles.forWidget("ScrollView")
+	s := &ScrollView{
+		Box:       NewBox(),
+		scrollBar: newScrollBar(ScrollBarAuto, styles),
+	}
+	s.focus = s
+	return s
+}
+
+// SetContent sets the primitive shown in the scroll view. If "focus" is true,
+// the primitive receives focus when the scroll view receives focus. Otherwise,
+// the scroll view keeps the focus and can be scrolled with the keyboard.
+func (s *ScrollView) SetContent(content Primitive, focus bool) *ScrollView {
+	s.content = content
+	s.contentFocus = focus
+	return s
+}
+
+// GetContent returns the primitive shown in the scroll view.
+func (s *ScrollView) GetContent() Primitive {
+	return s.content
+}
+
+// SetContentSize sets the size of the contained primitive. A value of 0 for
+// either dimension means that the content has the viewport's size in that
+// direction, i.e. it is not scrolled in that direction.
+func (s *ScrollView) SetContentSize(width, height int) *ScrollView {
+	s.contentWidth, s.contentHeight = width, height
+	return s
+}
+
+// SetScrollBars sets whether or not scroll bars are shown for the directions in
+// which the content does not fit. This is the same as calling
+// SetScrollBarVisibility() with ScrollBarAuto or ScrollBarNever.
+func (s *ScrollView) SetScrollBars(show bool) *ScrollView {
+	if show {
+		return s.SetScrollBarVisibility(ScrollBarAuto)
+	}
+	return s.SetScrollBarVisibility(ScrollBarNever)
+}
+
+// SetScrollBarVisibility sets when the scroll bars are shown. The default is
+// ScrollBarAuto.
+func (s *ScrollView) SetScrollBarVisibility(visibility ScrollBarVisibility) *ScrollView {
+	s.scrollBar.visibility = visibility
+	return s
+}
+
+// SetScrollBarColor sets the color of the scroll bars, both of their tracks and
+// of their thumbs.
+func (s *ScrollView) SetScrollBarColor(color tcell.Color) *ScrollView {
+	return s.SetScrollBarColors(color, color)
+}
+
+// SetScrollBarColors sets the colors of the scroll bars' tracks and thumbs.
+func (s *ScrollView) SetScrollBarColors(track, thumb tcell.Color) *ScrollView {
+	s.scrollBar.trackColor, s.scrollBar.thumbColor = track, thumb
+	return s
+}
+
+// ScrollTo scrolls the view such that the given row and column of the content
+// are shown in the top-left corner of the viewport, if possible.
+func (s *ScrollView) ScrollTo(row, column int) *ScrollView {
+	s.rowOffset, s.columnOffset = row, column
+	return s
+}
+
+// ScrollToBeginning scrolls to the top-left corner of the content.
+func (s *ScrollView) ScrollToBeginning() *ScrollView {
+	s.rowOffset, s.columnOffset = 0, 0
+	return s
+}
+
+// ScrollToEnd scrolls to the bottom of the content.
+func (s *ScrollView) ScrollToEnd() *ScrollView {
+	s.rowOffset = s.contentHeight
+	return s
+}
+
+// GetScrollOffset returns the number of rows and columns by which the content
+// is scrolled.
+func (s *ScrollView) GetScrollOffset() (row, column int) {
+	return s.rowOffset, s.columnOffset
+}
+
+// GetChildren returns the scroll view's content.
+func (s *ScrollView) GetChildren() []Primitive {
+	if s.content == nil {
+		return nil
+	}
+	return []Primitive{s.content}
+}
+
+// SaveState returns the scroll position as JSON. See SaveLayout() for details.
+func (s *ScrollView) SaveState() ([]byte, error) {
+	return json.Marshal(scrollViewState{RowOffset: s.rowOffset, ColumnOffset: s.columnOffset})
+}
+
+// RestoreState restores a state returned by SaveState().
+func (s *ScrollView) RestoreState(data []byte) error {
+	var state scrollViewState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	s.ScrollTo(state.RowOffset, state.ColumnOffset)
+	return nil
+}
+
+// applyTheme is called when the application's theme changes.
+func (s *ScrollView) applyTheme(from, to *Theme) {
+	s.Box.applyTheme(from, to)
+	s.scrollBar.applyTheme(from, to)
+}
+
+// Draw draws this primitive onto the screen.
+func (s *ScrollView) Draw(screen tcell.Screen) {
+	defer s.applyStyle()()
+	s.Box.Draw(screen)
+	x, y, width, height := s.GetInnerRect()
+	if width <= 0 || height <= 0 || s.content == nil {
+		return
+	}
+
+	// Determine the viewport and content sizes and which scroll bars we need.
+	pageWidth, pageHeight := width, height
+	contentWidth, contentHeight := s.contentWidth, s.contentHeight
+	vertical := s.scrollBar.shown(pageHeight, contentHeight)
+	if vertical {
+		pageWidth--
+	}
+	horizontal := s.scrollBar.shown(pageWidth, contentWidth)
+	if horizontal {
+		pageHeight--
+		if !vertical && s.scrollBar.shown(pageHeight, contentHeight) {
+			vertical = true
+			pageWidth--
+		}
+	}
+	if contentWidth <= 0 {
+		contentWidth = pageWidth
+	}
+	if contentHeight <= 0 {
+		contentHeight = pageHeight
+	}
+	s.pageWidth, s.pageHeight = pageWidth, pageHeight
+
+	// Draw the content. If it shows a cursor outside the viewport, scroll and
+	// draw it again.
+	background := tcell.StyleDefault.Background(s.backgroundColor)
+	for attempt := 0; attempt < 2; attempt++ {
+		if s.rowOffset > contentHeight-pageHeight {
+			s.rowOffset = contentHeight - pageHeight
+		}
+		if s.rowOffset < 0 {
+			s.rowOffset = 0
+		}
+		if s.columnOffset > contentWidth-pageWidth {
+			s.columnOffset = contentWidth - pageWidth
+		}
+		if s.columnOffset < 0 {
+			s.columnOffset = 0
+		}
+
+		clipped := &clippedScreen{
+			Screen: screen,
+			x:      x,
+			y:      y,
+			width:  pageWidth,
+			height: pageHeight,
+		}
+		if attempt > 0 {
+			clipped.Fill(' ', background)
+		}
+		s.content.SetRect(x-s.columnOffset, y-s.rowOffset, contentWidth, contentHeight)
+		s.content.Draw(clipped)
+		if !clipped.cursorShown || clipped.inside(clipped.cursorX, clipped.cursorY) {
+			break
+		}
+		if clipped.cursorY < y {
+			s.rowOffset -= y - clipped.cursorY
+		} else if clipped.cursorY >= y+pageHeight {
+			s.rowOffset += clipped.cursorY - y - pageHeight + 1
+		}
+		if clipped.cursorX < x {
+			s.columnOffset -= x - clipped.cursorX
+		} else if clipped.cursorX >= x+pageWidth {
+			s.columnOffset += clipped.cursorX - x - pageWidth + 1
+		}
+	}
+
+	// Draw the scroll bars.
+	if vertical {
+		s.scrollBar.draw(screen, x+pageWidth, y, pageHeight, s.rowOffset, pageHeight, contentHeight, true, s.backgroundColor)
+	}
+	if horizontal {
+		s.scrollBar.draw(screen, x, y+pageHeight, pageWidth, s.columnOffset, pageWidth, contentWidth, false, s.backgroundColor)
+	}
+}
+
+// Focus is called when this primitive receives focus.
+func (s *ScrollView) Focus(delegate func(p Primitive)) {
+	if s.content != nil && s.contentFocus {
+		delegate(s.content)
+		return
+	}
+	s.hasFocus = true
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (s *ScrollView) HasFocus() bool {
+	if s.content != nil && s.content.GetFocusable().HasFocus() {
+		return true
+	}
+	return s.hasFocus
+}
+
+// InputHandler returns the handler for this primitive.
+func (s *ScrollView) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return s.wrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		switch event.Key() {
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'g':
+				s.ScrollToBeginning()
+			case 'G':
+				s.ScrollToEnd()
+			case 'j':
+				s.rowOffset++
+			case 'k':
+				s.rowOffset--
+			case 'h':
+				s.columnOffset--
+			case 'l':
+				s.columnOffset++
+			}
+		case tcell.KeyHome:
+			s.ScrollToBeginning()
+		case tcell.KeyEnd:
+			s.ScrollToEnd()
+		case tcell.KeyUp:
+			s.rowOffset--
+		case tcell.KeyDown:
+			s.rowOffset++
+		case tcell.KeyLeft:
+			s.columnOffset--
+		case tcell.KeyRight:
+			s.columnOffset++
+		case tcell.KeyPgUp:
+			s.rowOffset -= s.pageHeight
+		case tcell.KeyPgDn:
+			s.rowOffset += s.pageHeight
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (s *ScrollView) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return s.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		mouseX, mouseY := event.Position()
+		if !s.InRect(mouseX, mouseY) {
+			return false, nil
+		}
+
+		// Only the visible part of the content receives mouse events.
+		x, y, _, _ := s.GetInnerRect()
+		if s.content != nil && mouseX >= x && mouseX < x+s.pageWidth && mouseY >= y && mouseY < y+s.pageHeight {
+			if handler := mouseHandler(s.content); handler != nil {
+				if consumed, capture = handler(action, event, setFocus); consumed {
+					return
+				}
+			}
+		}
+
+		switch action {
+		case MouseLeftClick:
+			setFocus(s)
+		case MouseScrollUp:
+			s.rowOffset -= 3
+		case MouseScrollDown:
+			s.rowOffset += 3
+		case MouseScrollLeft:
+			s.columnOffset -= 3
+		case MouseScrollRight:
+			s.columnOffset += 3
+		default:
+			return false, nil
+		}
+		return true, nil
+	})
+}
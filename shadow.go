@@ -0,0 +1,129 @@
+package tview
+
+import (
+	"github.com/gdamore/tcell"
+)
+
+// shadowCell is the content of a screen cell held by a shadowScreen.
+type shadowCell struct {
+	mainc rune
+	combc string
+	style tcell.Style
+
+	// Whether or not the cell was set.
+	set bool
+}
+
+// shadowScreen is a screen which collects all output in a buffer of its own
+// and, when it is shown, forwards only those cells to another screen which
+// differ from what was forwarded before (see Application.EnableCellDiffing()).
+// Cells which were drawn again with the same contents or which changed only
+// temporarily while drawing are therefore not passed on.
+type shadowScreen struct {
+	tcell.Screen
+
+	// The size of the buffers.
+	width, height int
+
+	// The cells as drawn and the cells as last forwarded to the screen.
+	cells, shown []shadowCell
+}
+
+// wrap makes the given screen the one which receives the output and returns
+// the shadow screen. If the screen was replaced or its size changed, the
+// buffers are reset.
+func (s *shadowScreen) wrap(screen tcell.Screen) *shadowScreen {
+	width, height := screen.Size()
+	if screen != s.Screen || width != s.width || height != s.height {
+		s.Screen, s.width, s.height = screen, width, height
+		s.cells = make([]shadowCell, width*height)
+		s.shown = make([]shadowCell, width*height)
+	}
+	return s
+}
+
+// reset forgets what was forwarded to the screen, e.g. after it was cleared,
+// so all cells are forwarded the next time.
+func (s *shadowScreen) reset() {
+	for index := range s.shown {
+		s.shown[index] = shadowCell{}
+	}
+}
+
+// cell returns the cell at the given position or nil if it is outside the
+// screen.
+func (s *shadowScreen) cell(x, y int) *shadowCell {
+	if x < 0 || y < 0 || x >= s.width || y >= s.height {
+		return nil
+	}
+	return &s.cells[y*s.width+x]
+}
+
+// SetContent sets the contents of a cell in the buffer.
+func (s *shadowScreen) SetContent(x, y int, mainc rune, combc []rune, style tcell.Style) {
+	if cell := s.cell(x, y); cell != nil {
+		*cell = shadowCell{mainc: mainc, style: style, set: true}
+		if len(combc) > 0 {
+			cell.combc = string(combc)
+		}
+	}
+}
+
+// SetCell sets the contents of a cell in the buffer.
+func (s *shadowScreen) SetCell(x, y int, style tcell.Style, ch ...rune) {
+	if len(ch) > 0 {
+		s.SetContent(x, y, ch[0], ch[1:], style)
+	}
+}
+
+// GetContent returns the contents of a cell. Cells which were not set yet are
+// taken from the screen.
+func (s *shadowScreen) GetContent(x, y int) (mainc rune, combc []rune, style tcell.Style, width int) {
+	cell := s.cell(x, y)
+	if cell == nil || !cell.set {
+		return s.Screen.GetContent(x, y)
+	}
+	if cell.combc != "" {
+		combc = []rune(cell.combc)
+	}
+	return cell.mainc, combc, cell.style, RuneWidth(cell.mainc)
+}
+
+// Fill fills the buffer with the given rune and style.
+func (s *shadowScreen) Fill(r rune, style tcell.Style) {
+	for index := range s.cells {
+		s.cells[index] = shadowCell{mainc: r, style: style, set: true}
+	}
+}
+
+// Clear clears the buffer.
+func (s *shadowScreen) Clear() {
+	s.Fill(' ', tcell.StyleDefault)
+}
+
+// flush forwards the cells which changed since they were last forwarded.
+func (s *shadowScreen) flush() {
+	for index, cell := range s.cells {
+		if !cell.set || cell == s.shown[index] {
+			continue
+		}
+		var combc []rune
+		if cell.combc != "" {
+			combc = []rune(cell.combc)
+		}
+		s.Screen.SetContent(index%s.width, index/s.width, cell.mainc, combc, cell.style)
+		s.shown[index] = cell
+	}
+}
+
+// Show forwards the changed cells and shows the screen.
+func (s *shadowScreen) Show() {
+	s.flush()
+	s.Screen.Show()
+}
+
+// Sync forwards the changed cells and syncs the screen.
+func (s *shadowScreen) Sync() {
+	s.flush()
+	s.Screen.Sync()
+}
@@ -0,0 +1,445 @@
+package tview
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell"
+)
+
+// Slider lets the user choose a numeric value from a range by moving a thumb
+// along a track. It can be horizontal (the default) or vertical and can be
+// used on its own, e.g. for volume or brightness controls, or as a form item.
+//
+// The following keys are available:
+//
+//   - Left arrow, down arrow: Decrease the value by one step.
+//   - Right arrow, up arrow: Increase the value by one step.
+//   - Page down, page up: Decrease/increase the value by ten steps.
+//   - Home, End: Set the value to the minimum/maximum.
+//   - Enter, Escape, Tab, Backtab: Finish (see SetDoneFunc()).
+//
+// With the mouse, the value can be set by clicking on the track or dragging
+// the thumb. The mouse wheel also changes the value.
+type Slider struct {
+	*Box
+
+	// The current value.
+	value float64
+
+	// The range of values and the step size.
+	min, max, step float64
+
+	// Whether the slider is vertical instead of horizontal.
+	vertical bool
+
+	// Whether or not to show the current value next to the track.
+	showValue bool
+
+	// The text to be displayed before the track.
+	label string
+
+	// The label color.
+	labelColor tcell.Color
+
+	// The color of the track.
+	fieldBackgroundColor tcell.Color
+
+	// The color of the thumb and the filled part of the track.
+	fieldTextColor tcell.Color
+
+	// The length of the track in a horizontal slider. A value of 0 means
+	// extend as much as possible.
+	fieldWidth int
+
+	// The screen position and length of the track the last time it was drawn.
+	trackX, trackY, trackLength int
+
+	// Set to true while the user is dragging the thumb.
+	dragging bool
+
+	// An optional function which is called when the user changes the value.
+	changed func(value float64)
+
+	// An optional function which is called when the user indicated that they
+	// are done with the slider. The key which was pressed is provided.
+	done func(tcell.Key)
+}
+
+// NewSlider returns a new horizontal slider with a range from 0 to 100 and a
+// step size of 1.
+func NewSlider() *Slider {
+	styles := Styles.forWidget("Slider")
+	return &Slider{
+		Box:                  NewBox(),
+		max:                  100,
+		step:                 1,
+		showValue:            true,
+		labelColor:           styles.SecondaryTextColor,
+		fieldBackgroundColor: styles.ContrastBackgroundColor,
+		fieldTextColor:       styles.PrimaryTextColor,
+	}
+}
+
+// SetRange sets the minimum and maximum values and the step size. A step size
+// of 0 or less means the value is continuous. The current value is adjusted
+// to the new range.
+func (s *Slider) SetRange(min, max, step float64) *Slider {
+	if max < min {
+		min, max = max, min
+	}
+	s.min, s.max, s.step = min, max, step
+	s.value = s.normalize(s.value)
+	return s
+}
+
+// SetValue sets the slider's value. It is adjusted to the slider's range and
+// step size.
+func (s *Slider) SetValue(value float64) *Slider {
+	s.value = s.normalize(value)
+	return s
+}
+
+// GetValue returns the slider's value.
+func (s *Slider) GetValue() float64 {
+	return s.value
+}
+
+// SetVertical sets whether the slider is vertical (the maximum at the top)
+// instead of horizontal.
+func (s *Slider) SetVertical(vertical bool) *Slider {
+	s.vertical = vertical
+	return s
+}
+
+// ShowValue sets whether or not the current value is shown next to the track
+// (below it for vertical sliders).
+func (s *Slider) ShowValue(show bool) *Slider {
+	s.showValue = show
+	return s
+}
+
+// SetLabel sets the text to be displayed before the track.
+func (s *Slider) SetLabel(label string) *Slider {
+	s.label = label
+	return s
+}
+
+// GetLabel returns the text to be displayed before the track.
+func (s *Slider) GetLabel() string {
+	return s.label
+}
+
+// SetLabelColor sets the color of the label.
+func (s *Slider) SetLabelColor(color tcell.Color) *Slider {
+	s.labelColor = color
+	return s
+}
+
+// SetFieldBackgroundColor sets the color of the track.
+func (s *Slider) SetFieldBackgroundColor(color tcell.Color) *Slider {
+	s.fieldBackgroundColor = color
+	return s
+}
+
+// SetFieldTextColor sets the color of the thumb and the filled part of the
+// track.
+func (s *Slider) SetFieldTextColor(color tcell.Color) *Slider {
+	s.fieldTextColor = color
+	return s
+}
+
+// SetFormAttributes sets attributes shared by all form items.
+func (s *Slider) SetFormAttributes(label string, labelColor, bgColor, fieldTextColor, fieldBgColor tcell.Color) FormItem {
+	s.label = label
+	s.labelColor = labelColor
+	s.backgroundColor = bgColor
+	s.fieldTextColor = fieldTextColor
+	s.fieldBackgroundColor = fieldBgColor
+	return s
+}
+
+// SetFieldWidth sets the length of a horizontal slider's track, including the
+// value display. A value of 0 means extend as much as possible.
+func (s *Slider) SetFieldWidth(width int) *Slider {
+	s.fieldWidth = width
+	return s
+}
+
+// GetFieldWidth returns this primitive's field width.
+func (s *Slider) GetFieldWidth() int {
+	return s.fieldWidth
+}
+
+// SetChangedFunc sets a handler which is called when the user changes the
+// slider's value. The handler receives the new value.
+func (s *Slider) SetChangedFunc(handler func(value float64)) *Slider {
+	s.changed = handler
+	return s
+}
+
+// SetDoneFunc sets a handler which is called when the user is done with the
+// slider. The callback function is provided with the key that was pressed,
+// which is one of the following:
+//
+//   - KeyEnter: Done with the slider.
+//   - KeyEscape: Abort.
+//   - KeyTab: Move to the next field.
+//   - KeyBacktab: Move to the previous field.
+func (s *Slider) SetDoneFunc(handler func(key tcell.Key)) *Slider {
+	s.done = handler
+	return s
+}
+
+// SetFinishedFunc calls SetDoneFunc().
+func (s *Slider) SetFinishedFunc(handler func(key tcell.Key)) FormItem {
+	return s.SetDoneFunc(handler)
+}
+
+// normalize adjusts the given value to the slider's range and step size.
+func (s *Slider) normalize(value float64) float64 {
+	if s.step > 0 {
+		value = s.min + math.Floor((value-s.min)/s.step+0.5)*s.step
+	}
+	if value < s.min {
+		value = s.min
+	} else if value > s.max {
+		value = s.max
+	}
+	return value
+}
+
+// change sets the slider's value and calls the "changed" handler if the value
+// has changed.
+func (s *Slider) change(value float64) {
+	value = s.normalize(value)
+	if value == s.value {
+		return
+	}
+	s.value = value
+	if s.changed != nil {
+		s.changed(value)
+	}
+}
+
+// increment returns the amount the value changes with one key press.
+func (s *Slider) increment() float64 {
+	if s.step > 0 {
+		return s.step
+	}
+	return (s.max - s.min) / 100
+}
+
+// formatValue returns the text displayed for the current value.
+func (s *Slider) formatValue(value float64) string {
+	if s.step <= 0 {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+
+	// Show as many decimals as the step size has.
+	precision := 0
+	step := strconv.FormatFloat(s.step, 'f', -1, 64)
+	if dot := strings.IndexRune(step, '.'); dot >= 0 {
+		precision = len(step) - dot - 1
+	}
+	return strconv.FormatFloat(value, 'f', precision, 64)
+}
+
+// valueWidth returns the width needed to display any of the slider's values.
+func (s *Slider) valueWidth() int {
+	width := len(s.formatValue(s.min))
+	if w := len(s.formatValue(s.max)); w > width {
+		width = w
+	}
+	if w := len(s.formatValue(s.value)); w > width {
+		width = w
+	}
+	return width
+}
+
+// thumbPosition returns the position of the thumb on a track of the given
+// length.
+func (s *Slider) thumbPosition(length int) int {
+	if s.max <= s.min || length <= 1 {
+		return 0
+	}
+	return int(math.Floor((s.value-s.min)/(s.max-s.min)*float64(length-1) + 0.5))
+}
+
+// applyTheme is called when the application's theme changes.
+func (s *Slider) applyTheme(from, to *Theme) {
+	s.Box.applyTheme(from, to)
+	themeColor(&s.labelColor, from.SecondaryTextColor, to.SecondaryTextColor)
+	themeColor(&s.fieldBackgroundColor, from.ContrastBackgroundColor, to.ContrastBackgroundColor)
+	themeColor(&s.fieldTextColor, from.PrimaryTextColor, to.PrimaryTextColor)
+}
+
+// Draw draws this primitive onto the screen.
+func (s *Slider) Draw(screen tcell.Screen) {
+	defer s.applyStyle(&s.fieldTextColor)()
+	s.Box.Draw(screen)
+	x, y, width, height := s.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+	trackStyle := tcell.StyleDefault.Background(s.backgroundColor).Foreground(s.fieldBackgroundColor)
+	fillStyle := trackStyle.Foreground(s.fieldTextColor)
+	thumb := '●'
+	if s.HasFocus() {
+		thumb = '◉'
+	}
+	value := s.formatValue(s.value)
+
+	if s.vertical {
+		// Label at the top, value at the bottom.
+		if s.label != "" {
+			Print(screen, s.bidiText(s.label), x, y, width, AlignCenter, s.labelColor)
+			y++
+			height--
+		}
+		if s.showValue && height > 1 {
+			Print(screen, value, x, y+height-1, width, AlignCenter, s.labelColor)
+			height--
+		}
+		if height <= 0 {
+			return
+		}
+		trackX := x + (width-1)/2
+		position := s.thumbPosition(height)
+		for row := 0; row < height; row++ {
+			ch, style := '│', trackStyle
+			fromBottom := height - 1 - row
+			if fromBottom == position {
+				ch, style = thumb, fillStyle
+			} else if fromBottom < position {
+				ch, style = '┃', fillStyle
+			}
+			screen.SetContent(trackX, y+row, ch, nil, style)
+		}
+		s.trackX, s.trackY, s.trackLength = trackX, y, height
+		return
+	}
+
+	// Label.
+	_, drawnWidth := Print(screen, s.bidiText(s.label), x, y, width, AlignLeft, s.labelColor)
+	x += drawnWidth
+	width -= drawnWidth
+	if s.fieldWidth > 0 && s.fieldWidth < width {
+		width = s.fieldWidth
+	}
+
+	// Value.
+	if s.showValue {
+		valueWidth := s.valueWidth()
+		if valueWidth+2 < width {
+			Print(screen, value, x+width-valueWidth, y, valueWidth, AlignRight, s.labelColor)
+			width -= valueWidth + 1
+		}
+	}
+	if width <= 0 {
+		return
+	}
+
+	// Track.
+	position := s.thumbPosition(width)
+	for column := 0; column < width; column++ {
+		ch, style := '─', trackStyle
+		if column == position {
+			ch, style = thumb, fillStyle
+		} else if column < position {
+			ch, style = '━', fillStyle
+		}
+		screen.SetContent(x+column, y, ch, nil, style)
+	}
+	s.trackX, s.trackY, s.trackLength = x, y, width
+}
+
+// InputHandler returns the handler for this primitive.
+func (s *Slider) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return s.wrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		switch key := event.Key(); key {
+		case tcell.KeyLeft, tcell.KeyDown:
+			s.change(s.value - s.increment())
+		case tcell.KeyRight, tcell.KeyUp:
+			s.change(s.value + s.increment())
+		case tcell.KeyPgDn:
+			s.change(s.value - 10*s.increment())
+		case tcell.KeyPgUp:
+			s.change(s.value + 10*s.increment())
+		case tcell.KeyHome:
+			s.change(s.min)
+		case tcell.KeyEnd:
+			s.change(s.max)
+		case tcell.KeyEnter, tcell.KeyEscape, tcell.KeyTab, tcell.KeyBacktab:
+			if s.done != nil {
+				s.done(key)
+			}
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'h', 'j', '-':
+				s.change(s.value - s.increment())
+			case 'l', 'k', '+':
+				s.change(s.value + s.increment())
+			}
+		}
+	})
+}
+
+// setFromPosition sets the value corresponding to the given screen position
+// on the track.
+func (s *Slider) setFromPosition(x, y int) {
+	if s.trackLength <= 1 {
+		return
+	}
+	offset := x - s.trackX
+	if s.vertical {
+		offset = s.trackLength - 1 - (y - s.trackY)
+	}
+	if offset < 0 {
+		offset = 0
+	} else if offset >= s.trackLength {
+		offset = s.trackLength - 1
+	}
+	s.change(s.min + float64(offset)/float64(s.trackLength-1)*(s.max-s.min))
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (s *Slider) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return s.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+
+		// Dragging the thumb. We capture the mouse until the button is released.
+		if s.dragging {
+			switch action {
+			case MouseMove:
+				s.setFromPosition(x, y)
+				return true, s
+			case MouseLeftUp:
+				s.dragging = false
+				return true, nil
+			}
+		}
+
+		if !s.InRect(x, y) {
+			return false, nil
+		}
+		switch action {
+		case MouseLeftDown:
+			setFocus(s)
+			s.setFromPosition(x, y)
+			s.dragging = true
+			return true, s
+		case MouseLeftUp, MouseLeftClick:
+			s.dragging = false
+			return true, nil
+		case MouseScrollUp, MouseScrollRight:
+			s.change(s.value + s.increment())
+		case MouseScrollDown, MouseScrollLeft:
+			s.change(s.value - s.increment())
+		default:
+			return false, nil
+		}
+		return true, nil
+	})
+}
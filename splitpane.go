@@ -0,0 +1,510 @@
+package tview
+
+import (
+	"encoding/json"
+
+	"github.com/gdamore/tcell"
+)
+
+// Values for SplitPane.SetCollapsed().
+const (
+	SplitPaneNone = iota
+	SplitPaneFirst
+	SplitPaneSecond
+)
+
+// SplitPane is a container which shows two primitives next to each other
+// (FlexColumn) or on top of each other (FlexRow), separated by a divider. The
+// user can move the divider with the mouse (by dragging it) or with the
+// keyboard. Each pane can be given a minimum size and each pane can be
+// collapsed, leaving the entire space to the other one.
+//
+// The position of the divider is stored as a ratio of the available space so
+// it stays proportional when the split pane is resized. Use GetRatio() and
+// SetRatio() to persist it, e.g. in combination with SetChangedFunc().
+//
+// The divider receives focus when the user clicks on it (if mouse events are
+// enabled) or when FocusDivider() is called. When the split pane receives
+// focus otherwise, it is passed on to the pane which had focus last. The
+// divider is controlled with the following keys when it has focus:
+//
+//   - Left arrow, Up arrow, h, k: Move the divider by one cell towards the
+//     first pane.
+//   - Right arrow, Down arrow, l, j: Move the divider by one cell towards the
+//     second pane.
+//   - Home, End: Move the divider as far as possible towards the first or
+//     second pane.
+//   - =: Split the available space evenly.
+//   - [: Collapse or expand the first pane.
+//   - ]: Collapse or expand the second pane.
+//   - Enter: Move the focus to the pane which had focus last.
+type SplitPane struct {
+	*Box
+
+	// The two panes.
+	first, second Primitive
+
+	// FlexColumn if the panes are shown side by side, FlexRow if they are
+	// stacked.
+	direction int
+
+	// The size of the first pane as a ratio of the space available to both
+	// panes.
+	ratio float64
+
+	// The minimum sizes of the two panes.
+	firstMin, secondMin int
+
+	// Which pane is collapsed, one of the SplitPane constants.
+	collapsed int
+
+	// The position of the divider (its column or row on screen) the last time
+	// the split pane was drawn.
+	dividerPos int
+
+	// Whether or not the divider should receive focus when this primitive
+	// receives focus.
+	focusDivider bool
+
+	// Whether or not the second pane had focus last.
+	secondFocused bool
+
+	// Whether or not the user is currently dragging the divider.
+	dragging bool
+
+	// We keep a reference to the function which allows us to set the focus to
+	// a pane.
+	setFocus func(p Primitive)
+
+	// The color of the divider when it does or does not have focus.
+	dividerColor, dividerFocusColor tcell.Color
+
+	// An optional function which is called when the user moved the divider.
+	changed func(ratio float64)
+
+	// An optional function which is called when the user leaves the divider.
+	done func(tcell.Key)
+}
+
+// splitPaneState is the state of a SplitPane saved with SaveState().
+type splitPaneState struct {
+	Ratio     float64 `json:"ratio"`
+	Collapsed int     `json:"collapsed"`
+}
+
+// NewSplitPane returns a new split pane showing the two given primitives side
+// by side with the divider in the middle. Either primitive may be nil.
+func NewSplitPane(first, second Primitive) *SplitPane {
+	styles := Styles.forWidget("SplitPane")
+	s := &SplitPane{
+		Box:               NewBox(),
+		first:             first,
+		second:            second,
+		direction:         FlexColumn,
+		ratio:             0.5,
+		dividerColor:      styles.BorderColor,
+		dividerFocusColor: styles.SecondaryTextColor,
+	}
+	s.focus = s
+	return s
+}
+
+// SetPanes replaces the two panes. Either primitive may be nil.
+func (s *SplitPane) SetPanes(first, second Primitive) *SplitPane {
+	s.first, s.second = first, second
+	return s
+}
+
+// GetPanes returns the two panes.
+func (s *SplitPane) GetPanes() (first, second Primitive) {
+	return s.first, s.second
+}
+
+// SetDirection sets the direction in which the panes are arranged. This must
+// be either FlexColumn (side by side, the default) or FlexRow (on top of each
+// other).
+func (s *SplitPane) SetDirection(direction int) *SplitPane {
+	s.direction = direction
+	return s
+}
+
+// SetRatio sets the size of the first pane as a ratio of the space available
+// to both panes, between 0 and 1. The default is 0.5. Minimum sizes set with
+// SetMinSizes() take precedence.
+func (s *SplitPane) SetRatio(ratio float64) *SplitPane {
+	if ratio < 0 {
+		ratio = 0
+	} else if ratio > 1 {
+		ratio = 1
+	}
+	s.ratio = ratio
+	return s
+}
+
+// GetRatio returns the size of the first pane as a ratio of the space
+// available to both panes.
+func (s *SplitPane) GetRatio() float64 {
+	return s.ratio
+}
+
+// SetMinSizes sets the minimum sizes of the two panes, in screen cells. The
+// divider cannot be moved such that a pane becomes smaller than its minimum
+// size, unless the split pane itself is too small. Collapsed panes are not
+// subject to these limits.
+func (s *SplitPane) SetMinSizes(first, second int) *SplitPane {
+	s.firstMin, s.secondMin = first, second
+	return s
+}
+
+// SetCollapsed collapses one of the panes, giving the entire space (except for
+// the divider) to the other pane. The argument must be one of SplitPaneFirst,
+// SplitPaneSecond, or SplitPaneNone (which expands a collapsed pane again,
+// restoring the previous ratio).
+func (s *SplitPane) SetCollapsed(pane int) *SplitPane {
+	s.collapsed = pane
+	if s.setFocus != nil && s.HasFocus() && !s.hasFocus {
+		// The focused pane may have been collapsed.
+		s.setFocus(s)
+	}
+	return s
+}
+
+// GetCollapsed returns which pane is collapsed, one of SplitPaneFirst,
+// SplitPaneSecond, or SplitPaneNone.
+func (s *SplitPane) GetCollapsed() int {
+	return s.collapsed
+}
+
+// SetDividerColor sets the color of the divider.
+func (s *SplitPane) SetDividerColor(color tcell.Color) *SplitPane {
+	s.dividerColor = color
+	return s
+}
+
+// SetDividerFocusColor sets the color of the divider when it has focus.
+func (s *SplitPane) SetDividerFocusColor(color tcell.Color) *SplitPane {
+	s.dividerFocusColor = color
+	return s
+}
+
+// SetChangedFunc sets a handler which is called when the user moved the
+// divider. It receives the new ratio (see GetRatio()).
+func (s *SplitPane) SetChangedFunc(handler func(ratio float64)) *SplitPane {
+	s.changed = handler
+	return s
+}
+
+// SetDoneFunc sets a handler which is called when the user leaves the divider.
+// The callback function is provided with the key that was pressed, which is
+// one of the following:
+//
+//   - KeyEscape: Leaving the divider with no specific direction.
+//   - KeyTab: Move to the next field.
+//   - KeyBacktab: Move to the previous field.
+func (s *SplitPane) SetDoneFunc(handler func(key tcell.Key)) *SplitPane {
+	s.done = handler
+	return s
+}
+
+// FocusDivider causes the divider to receive focus the next time this
+// primitive receives focus. If it already has focus, the divider receives
+// focus immediately.
+func (s *SplitPane) FocusDivider() *SplitPane {
+	s.focusDivider = true
+	if s.setFocus != nil && s.HasFocus() {
+		s.setFocus(s)
+	}
+	return s
+}
+
+// layout returns the screen position at which the panes start, the space
+// available to both panes, and the size of the first pane.
+func (s *SplitPane) layout() (start, available, firstSize int) {
+	x, y, width, height := s.GetInnerRect()
+	start, available = x, width-1
+	if s.direction == FlexRow {
+		start, available = y, height-1
+	}
+	if available < 0 {
+		available = 0
+	}
+
+	switch s.collapsed {
+	case SplitPaneFirst:
+		return start, available, 0
+	case SplitPaneSecond:
+		return start, available, available
+	}
+	return start, available, s.clamp(int(s.ratio*float64(available)+0.5), available)
+}
+
+// clamp returns the given size of the first pane, adjusted to the panes'
+// minimum sizes.
+func (s *SplitPane) clamp(firstSize, available int) int {
+	if firstSize > available-s.secondMin {
+		firstSize = available - s.secondMin
+	}
+	if firstSize < s.firstMin {
+		firstSize = s.firstMin
+	}
+	if firstSize > available {
+		firstSize = available
+	}
+	if firstSize < 0 {
+		firstSize = 0
+	}
+	return firstSize
+}
+
+// resize sets the size of the first pane to the given number of cells,
+// expanding a collapsed pane, and notifies the "changed" handler.
+func (s *SplitPane) resize(firstSize int) {
+	_, available, _ := s.layout()
+	if available <= 0 {
+		return
+	}
+	s.collapsed = SplitPaneNone
+	s.ratio = float64(s.clamp(firstSize, available)) / float64(available)
+	if s.changed != nil {
+		s.changed(s.ratio)
+	}
+}
+
+// toggleCollapsed collapses the given pane or, if it is already collapsed,
+// expands it again.
+func (s *SplitPane) toggleCollapsed(pane int) {
+	if s.collapsed == pane {
+		s.collapsed = SplitPaneNone
+	} else {
+		s.collapsed = pane
+	}
+}
+
+// focusedPane returns the pane which should receive focus, or nil if no pane
+// is visible.
+func (s *SplitPane) focusedPane() Primitive {
+	first, second := s.first, s.second
+	if s.collapsed == SplitPaneFirst {
+		first = nil
+	} else if s.collapsed == SplitPaneSecond {
+		second = nil
+	}
+	if second != nil && (s.secondFocused || first == nil) {
+		return second
+	}
+	return first
+}
+
+// GetChildren returns the two panes.
+func (s *SplitPane) GetChildren() []Primitive {
+	var children []Primitive
+	for _, pane := range []Primitive{s.first, s.second} {
+		if pane != nil {
+			children = append(children, pane)
+		}
+	}
+	return children
+}
+
+// SaveState returns the position of the divider and the collapsed pane as
+// JSON. See SaveLayout() for details.
+func (s *SplitPane) SaveState() ([]byte, error) {
+	return json.Marshal(splitPaneState{Ratio: s.ratio, Collapsed: s.collapsed})
+}
+
+// RestoreState restores a state returned by SaveState().
+func (s *SplitPane) RestoreState(data []byte) error {
+	var state splitPaneState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	s.SetRatio(state.Ratio)
+	s.collapsed = state.Collapsed
+	return nil
+}
+
+// applyTheme is called when the application's theme changes.
+func (s *SplitPane) applyTheme(from, to *Theme) {
+	s.Box.applyTheme(from, to)
+	themeColor(&s.dividerColor, from.BorderColor, to.BorderColor)
+	themeColor(&s.dividerFocusColor, from.SecondaryTextColor, to.SecondaryTextColor)
+}
+
+// Draw draws this primitive onto the screen.
+func (s *SplitPane) Draw(screen tcell.Screen) {
+	defer s.applyStyle()()
+	s.Box.Draw(screen)
+
+	x, y, width, height := s.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+	start, _, firstSize := s.layout()
+	s.dividerPos = start + firstSize
+
+	// Remember which pane had focus.
+	if s.first != nil && s.first.GetFocusable().HasFocus() {
+		s.secondFocused = false
+	} else if s.second != nil && s.second.GetFocusable().HasFocus() {
+		s.secondFocused = true
+	}
+
+	// Draw the divider.
+	color := s.dividerColor
+	if s.hasFocus {
+		color = s.dividerFocusColor
+	}
+	style := tcell.StyleDefault.Foreground(color).Background(s.backgroundColor)
+	if s.direction == FlexRow {
+		for column := x; column < x+width; column++ {
+			screen.SetContent(column, s.dividerPos, GraphicsHoriBar, nil, style)
+		}
+	} else {
+		for row := y; row < y+height; row++ {
+			screen.SetContent(s.dividerPos, row, GraphicsVertBar, nil, style)
+		}
+	}
+
+	// Position and draw the panes.
+	type pane struct {
+		item                Primitive
+		x, y, width, height int
+	}
+	panes := []pane{{item: s.first}, {item: s.second}}
+	if s.direction == FlexRow {
+		panes[0].x, panes[0].y, panes[0].width, panes[0].height = x, y, width, firstSize
+		panes[1].x, panes[1].y, panes[1].width, panes[1].height = x, s.dividerPos+1, width, y+height-s.dividerPos-1
+	} else {
+		panes[0].x, panes[0].y, panes[0].width, panes[0].height = x, y, firstSize, height
+		panes[1].x, panes[1].y, panes[1].width, panes[1].height = s.dividerPos+1, y, x+width-s.dividerPos-1, height
+	}
+	for _, p := range panes {
+		if p.item == nil {
+			continue
+		}
+		p.item.SetRect(p.x, p.y, p.width, p.height)
+		if p.width <= 0 || p.height <= 0 {
+			continue
+		}
+		if p.item.GetFocusable().HasFocus() {
+			defer drawPrimitive(screen, p.item)
+		} else {
+			drawPrimitive(screen, p.item)
+		}
+	}
+}
+
+// Focus is called when this primitive receives focus.
+func (s *SplitPane) Focus(delegate func(p Primitive)) {
+	s.setFocus = delegate
+	pane := s.focusedPane()
+	if pane == nil || s.focusDivider {
+		s.focusDivider = false
+		s.Box.Focus(delegate)
+		return
+	}
+	delegate(pane)
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (s *SplitPane) HasFocus() bool {
+	if s.hasFocus {
+		return true
+	}
+	if s.first != nil && s.first.GetFocusable().HasFocus() {
+		return true
+	}
+	return s.second != nil && s.second.GetFocusable().HasFocus()
+}
+
+// InputHandler returns the handler for this primitive.
+func (s *SplitPane) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return s.wrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		_, available, firstSize := s.layout()
+		switch key := event.Key(); key {
+		case tcell.KeyLeft, tcell.KeyUp:
+			s.resize(firstSize - 1)
+		case tcell.KeyRight, tcell.KeyDown:
+			s.resize(firstSize + 1)
+		case tcell.KeyHome:
+			s.resize(0)
+		case tcell.KeyEnd:
+			s.resize(available)
+		case tcell.KeyEnter:
+			if pane := s.focusedPane(); pane != nil {
+				setFocus(pane)
+			}
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'h', 'k':
+				s.resize(firstSize - 1)
+			case 'l', 'j':
+				s.resize(firstSize + 1)
+			case '=':
+				s.resize(available / 2)
+			case '[':
+				s.toggleCollapsed(SplitPaneFirst)
+			case ']':
+				s.toggleCollapsed(SplitPaneSecond)
+			}
+		case tcell.KeyEscape, tcell.KeyTab, tcell.KeyBacktab:
+			if s.done != nil {
+				s.done(key)
+			}
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (s *SplitPane) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return s.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+		pos := x
+		if s.direction == FlexRow {
+			pos = y
+		}
+
+		// Move the divider while it is being dragged.
+		if s.dragging {
+			start, _, _ := s.layout()
+			s.resize(pos - start)
+			if action == MouseLeftUp {
+				s.dragging = false
+				return true, nil
+			}
+			return true, s
+		}
+
+		if !s.InRect(x, y) {
+			return false, nil
+		}
+
+		// Clicks on the divider.
+		if pos == s.dividerPos {
+			switch action {
+			case MouseLeftDown:
+				s.dragging = true
+				s.focusDivider = true
+				setFocus(s)
+				return true, s
+			case MouseLeftDoubleClick:
+				_, available, _ := s.layout()
+				s.resize(available / 2)
+			}
+			return true, nil
+		}
+
+		// Other events go to the panes.
+		for _, pane := range []Primitive{s.first, s.second} {
+			if pane == nil {
+				continue
+			}
+			if handler := mouseHandler(pane); handler != nil {
+				if consumed, capture = handler(action, event, setFocus); consumed {
+					return
+				}
+			}
+		}
+		return false, nil
+	})
+}
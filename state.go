@@ -0,0 +1,82 @@
+package tview
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// StateSaver is implemented by primitives with layout-relevant state which
+// can be saved and restored, e.g. the ratio of a SplitPane, the visible pages
+// of Pages, or the scroll position of a TextView. See SaveLayout() and
+// RestoreLayout().
+type StateSaver interface {
+	// SaveState returns the primitive's state encoded as JSON.
+	SaveState() ([]byte, error)
+
+	// RestoreState restores a state previously returned by SaveState().
+	RestoreState(data []byte) error
+}
+
+// SaveLayout saves the state of the given primitive and of all primitives
+// contained in it (see Container) which implement StateSaver, e.g. to persist
+// an application's user interface state between sessions. The result is a
+// JSON object which maps the position of each primitive in the hierarchy to
+// its state. It can be restored with RestoreLayout().
+func SaveLayout(root Primitive) ([]byte, error) {
+	states := make(map[string]json.RawMessage)
+	var err error
+	walkLayout(root, "", func(p Primitive, path string) {
+		saver, ok := p.(StateSaver)
+		if !ok || err != nil {
+			return
+		}
+		var state []byte
+		if state, err = saver.SaveState(); err == nil {
+			states[path] = state
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(states)
+}
+
+// RestoreLayout restores the state saved with SaveLayout() into the given
+// primitive and the primitives contained in it. The hierarchy of primitives
+// must be the same as the one that was saved. States of primitives which no
+// longer exist are ignored.
+func RestoreLayout(root Primitive, data []byte) error {
+	var states map[string]json.RawMessage
+	if err := json.Unmarshal(data, &states); err != nil {
+		return err
+	}
+	var err error
+	walkLayout(root, "", func(p Primitive, path string) {
+		saver, ok := p.(StateSaver)
+		state, found := states[path]
+		if !ok || !found || err != nil {
+			return
+		}
+		err = saver.RestoreState(state)
+	})
+	return err
+}
+
+// walkLayout calls "visit" for the given primitive and all primitives
+// contained in it, depth-first, together with their path in the hierarchy.
+// The path is made up of the indices of the primitives in their containers,
+// separated by dots. The root's path is empty.
+func walkLayout(p Primitive, path string, visit func(p Primitive, path string)) {
+	visit(p, path)
+	container, ok := p.(Container)
+	if !ok {
+		return
+	}
+	for index, child := range container.GetChildren() {
+		childPath := strconv.Itoa(index)
+		if path != "" {
+			childPath = path + "." + childPath
+		}
+		walkLayout(child, childPath, visit)
+	}
+}
@@ -0,0 +1,286 @@
+package tview
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell"
+)
+
+// statusKeyHint describes a key and the action it triggers, e.g. "^X" and
+// "Exit".
+type statusKeyHint struct {
+	Key         string // The key, e.g. "F1" or "^X".
+	Description string // A short description of what the key does.
+}
+
+// StatusBar is a one-row primitive, usually placed at the bottom of the
+// screen, which shows status information. It has three text segments, one
+// aligned to the left, one centered, and one aligned to the right. All of them
+// may contain color tags.
+//
+// The status bar can also show a list of key hints, similar to the footers of
+// nano or htop:
+//
+//   ^G Help  ^O Write Out  ^X Exit
+//
+// Key hints are drawn on the left, followed by the left segment.
+//
+// Finally, ShowMessage() temporarily replaces the bar's content with a
+// message, e.g. "File saved". The message disappears after a given duration.
+// As the status bar is not redrawn automatically when this happens, you may
+// want to provide a "changed" handler which redraws the application (see
+// SetChangedFunc()).
+type StatusBar struct {
+	*Box
+	sync.Mutex
+
+	// The texts of the left, center, and right segments.
+	left, center, right string
+
+	// The key hints.
+	keyHints []*statusKeyHint
+
+	// The transient message, if any.
+	message string
+
+	// The time at which the message expires. The zero value means the message
+	// does not expire.
+	messageExpires time.Time
+
+	// The timer which clears the message.
+	messageTimer *time.Timer
+
+	// The color of the segment texts.
+	textColor tcell.Color
+
+	// The text and background colors of the key hints' keys.
+	keyColor, keyBackgroundColor tcell.Color
+
+	// The color of the key hints' descriptions.
+	keyDescriptionColor tcell.Color
+
+	// The text color of transient messages.
+	messageColor tcell.Color
+
+	// An optional function which is called when a transient message disappears.
+	changed func()
+}
+
+// NewStatusBar returns a new, empty status bar.
+func NewStatusBar() *StatusBar {
+	styles := Styles.forWidget("StatusBar")
+	return &StatusBar{
+		Box:                 NewBox().SetBackgroundColor(styles.ContrastBackgroundColor),
+		textColor:           styles.PrimaryTextColor,
+		keyColor:            styles.InverseTextColor,
+		keyBackgroundColor:  styles.PrimaryTextColor,
+		keyDescriptionColor: styles.PrimaryTextColor,
+		messageColor:        styles.SecondaryTextColor,
+	}
+}
+
+// SetText sets the text of one of the status bar's segments. "align" must be
+// one of AlignLeft, AlignCenter, or AlignRight.
+func (s *StatusBar) SetText(align int, text string) *StatusBar {
+	s.Lock()
+	defer s.Unlock()
+	switch align {
+	case AlignLeft:
+		s.left = text
+	case AlignCenter:
+		s.center = text
+	case AlignRight:
+		s.right = text
+	}
+	return s
+}
+
+// GetText returns the text of the segment with the given alignment which must
+// be one of AlignLeft, AlignCenter, or AlignRight.
+func (s *StatusBar) GetText(align int) string {
+	s.Lock()
+	defer s.Unlock()
+	switch align {
+	case AlignLeft:
+		return s.left
+	case AlignCenter:
+		return s.center
+	case AlignRight:
+		return s.right
+	}
+	return ""
+}
+
+// AddKeyHint adds a key hint to the status bar. The key is shown highlighted,
+// followed by its description, e.g. AddKeyHint("^X", "Exit").
+func (s *StatusBar) AddKeyHint(key, description string) *StatusBar {
+	s.Lock()
+	defer s.Unlock()
+	s.keyHints = append(s.keyHints, &statusKeyHint{
+		Key:         key,
+		Description: description,
+	})
+	return s
+}
+
+// ClearKeyHints removes all key hints from the status bar.
+func (s *StatusBar) ClearKeyHints() *StatusBar {
+	s.Lock()
+	defer s.Unlock()
+	s.keyHints = nil
+	return s
+}
+
+// ShowMessage replaces the status bar's content with the given message. The
+// message disappears after the given duration, after which the "changed"
+// handler is called (see SetChangedFunc()). If the duration is 0, the message
+// remains until ClearMessage() is called or another message is shown.
+//
+// This function may be called from any goroutine.
+func (s *StatusBar) ShowMessage(text string, duration time.Duration) *StatusBar {
+	s.Lock()
+	defer s.Unlock()
+	if s.messageTimer != nil {
+		s.messageTimer.Stop()
+		s.messageTimer = nil
+	}
+	s.message = text
+	s.messageExpires = time.Time{}
+	if duration > 0 {
+		s.messageExpires = time.Now().Add(duration)
+		var timer *time.Timer
+		timer = time.AfterFunc(duration, func() {
+			s.Lock()
+			if s.messageTimer != timer {
+				s.Unlock()
+				return // A newer message was shown in the meantime.
+			}
+			s.message = ""
+			s.messageTimer = nil
+			changed := s.changed
+			s.Unlock()
+			if changed != nil {
+				changed()
+			}
+		})
+		s.messageTimer = timer
+	}
+	return s
+}
+
+// ClearMessage removes the transient message, if any.
+func (s *StatusBar) ClearMessage() *StatusBar {
+	s.Lock()
+	defer s.Unlock()
+	if s.messageTimer != nil {
+		s.messageTimer.Stop()
+		s.messageTimer = nil
+	}
+	s.message = ""
+	return s
+}
+
+// SetTextColor sets the color of the segment texts.
+func (s *StatusBar) SetTextColor(color tcell.Color) *StatusBar {
+	s.textColor = color
+	return s
+}
+
+// SetKeyColor sets the text color of the key hints' keys.
+func (s *StatusBar) SetKeyColor(color tcell.Color) *StatusBar {
+	s.keyColor = color
+	return s
+}
+
+// SetKeyBackgroundColor sets the background color of the key hints' keys.
+func (s *StatusBar) SetKeyBackgroundColor(color tcell.Color) *StatusBar {
+	s.keyBackgroundColor = color
+	return s
+}
+
+// SetKeyDescriptionColor sets the color of the key hints' descriptions.
+func (s *StatusBar) SetKeyDescriptionColor(color tcell.Color) *StatusBar {
+	s.keyDescriptionColor = color
+	return s
+}
+
+// SetMessageColor sets the text color of transient messages.
+func (s *StatusBar) SetMessageColor(color tcell.Color) *StatusBar {
+	s.messageColor = color
+	return s
+}
+
+// SetChangedFunc sets a handler function which is called when a transient
+// message disappears. This is typically used to cause the application to
+// redraw the screen. Note that the handler is called from a different
+// goroutine.
+func (s *StatusBar) SetChangedFunc(handler func()) *StatusBar {
+	s.Lock()
+	defer s.Unlock()
+	s.changed = handler
+	return s
+}
+
+// applyTheme is called when the application's theme changes.
+func (s *StatusBar) applyTheme(from, to *Theme) {
+	s.Box.applyTheme(from, to)
+	themeColor(&s.backgroundColor, from.ContrastBackgroundColor, to.ContrastBackgroundColor)
+	themeColor(&s.textColor, from.PrimaryTextColor, to.PrimaryTextColor)
+	themeColor(&s.keyColor, from.InverseTextColor, to.InverseTextColor)
+	themeColor(&s.keyBackgroundColor, from.PrimaryTextColor, to.PrimaryTextColor)
+	themeColor(&s.keyDescriptionColor, from.PrimaryTextColor, to.PrimaryTextColor)
+	themeColor(&s.messageColor, from.SecondaryTextColor, to.SecondaryTextColor)
+}
+
+// Draw draws this primitive onto the screen.
+func (s *StatusBar) Draw(screen tcell.Screen) {
+	defer s.applyStyle(&s.textColor)()
+	s.Box.Draw(screen)
+	x, y, width, height := s.GetInnerRect()
+	if height <= 0 || width <= 0 {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	// A transient message replaces everything else.
+	if s.message != "" && (s.messageExpires.IsZero() || time.Now().Before(s.messageExpires)) {
+		Print(screen, s.message, x, y, width, AlignLeft, s.messageColor)
+		return
+	}
+
+	// Draw the right and the center segment first so the left side wins if
+	// they overlap.
+	Print(screen, s.right, x, y, width, AlignRight, s.textColor)
+	Print(screen, s.center, x, y, width, AlignCenter, s.textColor)
+
+	// Draw the key hints.
+	keyStyle := tcell.StyleDefault.Background(s.keyBackgroundColor)
+	right := x + width
+	for _, hint := range s.keyHints {
+		if x >= right {
+			return
+		}
+		keyWidth := StringWidth(hint.Key)
+		if x+keyWidth > right {
+			keyWidth = right - x
+		}
+		for column := 0; column < keyWidth; column++ {
+			screen.SetContent(x+column, y, ' ', nil, keyStyle)
+		}
+		Print(screen, hint.Key, x, y, keyWidth, AlignLeft, s.keyColor)
+		x += keyWidth
+		if x >= right {
+			return
+		}
+		_, descriptionWidth := Print(screen, " "+hint.Description, x, y, right-x, AlignLeft, s.keyDescriptionColor)
+		x += descriptionWidth + 2
+	}
+
+	// Draw the left segment.
+	if x < right {
+		Print(screen, s.left, x, y, right-x, AlignLeft, s.textColor)
+	}
+}
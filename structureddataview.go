@@ -0,0 +1,846 @@
+package tview
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell"
+)
+
+// Kinds of nodes in a StructuredDataView.
+const (
+	dataObject = iota
+	dataArray
+	dataString
+	dataNumber
+	dataBool
+	dataNull
+)
+
+// identifierPattern matches object keys which need no quotes in paths.
+var identifierPattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// dataNode is one node of the tree shown by a StructuredDataView.
+type dataNode struct {
+	kind     int         // One of the data node kinds.
+	key      string      // The object key or the array index.
+	isIndex  bool        // Whether "key" is an array index.
+	value    string      // The text of scalar values (unquoted for strings).
+	children []*dataNode // The children of objects and arrays.
+	parent   *dataNode   // The parent node, nil for the root.
+	expanded bool        // Whether the children of this node are shown.
+}
+
+// path returns the path of the node, e.g. ".items[2].name".
+func (n *dataNode) path() string {
+	if n.parent == nil {
+		return "."
+	}
+	var element string
+	if n.isIndex {
+		element = "[" + n.key + "]"
+	} else if identifierPattern.MatchString(n.key) {
+		element = "." + n.key
+	} else {
+		element = "[" + strconv.Quote(n.key) + "]"
+	}
+	if n.parent.parent == nil {
+		if strings.HasPrefix(element, "[") {
+			return "." + element
+		}
+		return element
+	}
+	return n.parent.path() + element
+}
+
+// json returns the node's value as compact JSON.
+func (n *dataNode) json() string {
+	switch n.kind {
+	case dataObject, dataArray:
+		elements := make([]string, len(n.children))
+		for index, child := range n.children {
+			elements[index] = child.json()
+			if n.kind == dataObject {
+				key, _ := json.Marshal(child.key)
+				elements[index] = string(key) + ":" + elements[index]
+			}
+		}
+		if n.kind == dataArray {
+			return "[" + strings.Join(elements, ",") + "]"
+		}
+		return "{" + strings.Join(elements, ",") + "}"
+	case dataString:
+		encoded, _ := json.Marshal(n.value)
+		return string(encoded)
+	default:
+		return n.value
+	}
+}
+
+// StructuredDataView is a primitive which shows structured data, e.g. parsed
+// JSON or YAML documents, as an expandable tree. Object keys, strings,
+// numbers, and other values are shown in different colors. A status line at
+// the bottom shows the path of the selected node, e.g. ".items[2].name".
+//
+// JSON text can be shown directly with SetJSON(), which keeps the order of
+// object keys. Data parsed by other packages (e.g. a YAML package) can be
+// shown with SetData().
+//
+// The following keys are available when the view has focus:
+//
+//   - Up arrow, k: Select the previous node.
+//   - Down arrow, j: Select the next node.
+//   - Right arrow, l: Expand the selected node or select its first child.
+//   - Left arrow, h: Collapse the selected node or select its parent.
+//   - Page Up, Page Down: Move up or down by one page.
+//   - Home, g: Select the first node.
+//   - End, G: Select the last node.
+//   - Enter, Space: Expand or collapse the selected node.
+//   - /: Start a search of keys and values. Typed characters are added to the
+//     search text, Enter finishes typing, Escape removes the search.
+//   - n, N: Select the next or previous node matching the search.
+//   - y: Copy the selected node's value (see SetCopyFunc()).
+//   - Escape, Tab, Backtab: Finish (see SetDoneFunc()).
+type StructuredDataView struct {
+	*Box
+
+	// The root node, nil if there is no data.
+	root *dataNode
+
+	// The selected node.
+	current *dataNode
+
+	// The index of the first visible row.
+	rowOffset int
+
+	// The number of rows visible the last time the view was drawn.
+	pageSize int
+
+	// The search text (case-insensitive) and whether or not the user is
+	// typing it.
+	search    string
+	searching bool
+
+	// The colors of keys, strings, numbers, and other values (booleans and
+	// null), and of the tree's structure.
+	keyColor, stringColor, numberColor, keywordColor, structureColor tcell.Color
+
+	// The colors of the selected row.
+	selectedTextColor, selectedBackgroundColor tcell.Color
+
+	// The color of the status line.
+	statusColor tcell.Color
+
+	// An optional function which is called when the user copies a value.
+	copy func(path, value string)
+
+	// An optional function which is called when the selected node changes.
+	changed func(path string)
+
+	// An optional function which is called when the user leaves the view.
+	done func(tcell.Key)
+}
+
+// structuredDataViewState is the state of a StructuredDataView saved with
+// SaveState().
+type structuredDataViewState struct {
+	Expanded []string `json:"expanded"`
+	Current  string   `json:"current"`
+}
+
+// NewStructuredDataView returns a new view without any data.
+func NewStructuredDataView() *StructuredDataView {
+	styles := Styles.forWidget("StructuredDataView")
+	return &StructuredDataView{
+		Box:                     NewBox(),
+		keyColor:                styles.SecondaryTextColor,
+		stringColor:             tcell.ColorGreen,
+		numberColor:             styles.TertiaryTextColor,
+		keywordColor:            tcell.ColorFuchsia,
+		structureColor:          styles.PrimaryTextColor,
+		selectedTextColor:       styles.PrimitiveBackgroundColor,
+		selectedBackgroundColor: styles.PrimaryTextColor,
+		statusColor:             styles.PrimaryTextColor,
+	}
+}
+
+// SetJSON parses the given JSON text and shows it. Object keys are shown in
+// the order in which they appear in the text. The top-level node is expanded.
+// An error is returned if the text is not valid JSON. The view is not changed
+// in this case.
+func (s *StructuredDataView) SetJSON(text string) error {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	root, err := parseJSONNode(decoder)
+	if err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return fmt.Errorf("unexpected data after JSON value")
+	}
+	s.setRoot(root)
+	return nil
+}
+
+// parseJSONNode reads the next JSON value from the decoder and returns it as a
+// tree of nodes.
+func parseJSONNode(decoder *json.Decoder) (*dataNode, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch value := token.(type) {
+	case json.Delim:
+		node := &dataNode{kind: dataObject}
+		if value == '[' {
+			node.kind = dataArray
+		}
+		for decoder.More() {
+			var key string
+			if node.kind == dataObject {
+				keyToken, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				key = keyToken.(string)
+			} else {
+				key = strconv.Itoa(len(node.children))
+			}
+			child, err := parseJSONNode(decoder)
+			if err != nil {
+				return nil, err
+			}
+			child.key, child.isIndex, child.parent = key, node.kind == dataArray, node
+			node.children = append(node.children, child)
+		}
+		if _, err := decoder.Token(); err != nil { // The closing delimiter.
+			return nil, err
+		}
+		return node, nil
+	case string:
+		return &dataNode{kind: dataString, value: value}, nil
+	case json.Number:
+		return &dataNode{kind: dataNumber, value: value.String()}, nil
+	case bool:
+		return &dataNode{kind: dataBool, value: strconv.FormatBool(value)}, nil
+	default:
+		return &dataNode{kind: dataNull, value: "null"}, nil
+	}
+}
+
+// SetData shows the given data. It may consist of maps with string keys
+// (map[string]interface{}) or arbitrary keys (map[interface{}]interface{}),
+// slices ([]interface{}), strings, numbers, booleans, and nil, as produced by
+// encoding/json and most YAML packages. Object keys are sorted. The top-level
+// node is expanded.
+func (s *StructuredDataView) SetData(data interface{}) *StructuredDataView {
+	s.setRoot(dataToNode(data))
+	return s
+}
+
+// dataToNode converts parsed data into a tree of nodes.
+func dataToNode(data interface{}) *dataNode {
+	node := &dataNode{}
+	addChild := func(key string, isIndex bool, value interface{}) {
+		child := dataToNode(value)
+		child.key, child.isIndex, child.parent = key, isIndex, node
+		node.children = append(node.children, child)
+	}
+	switch value := data.(type) {
+	case map[string]interface{}:
+		node.kind = dataObject
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			addChild(key, false, value[key])
+		}
+	case map[interface{}]interface{}:
+		node.kind = dataObject
+		keys := make([]string, 0, len(value))
+		values := make(map[string]interface{}, len(value))
+		for key, v := range value {
+			keyText := fmt.Sprint(key)
+			keys = append(keys, keyText)
+			values[keyText] = v
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			addChild(key, false, values[key])
+		}
+	case []interface{}:
+		node.kind = dataArray
+		for index, v := range value {
+			addChild(strconv.Itoa(index), true, v)
+		}
+	case string:
+		node.kind, node.value = dataString, value
+	case bool:
+		node.kind, node.value = dataBool, strconv.FormatBool(value)
+	case nil:
+		node.kind, node.value = dataNull, "null"
+	case json.Number, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		node.kind, node.value = dataNumber, fmt.Sprint(value)
+	default:
+		node.kind, node.value = dataString, fmt.Sprint(value)
+	}
+	return node
+}
+
+// setRoot replaces the shown data.
+func (s *StructuredDataView) setRoot(root *dataNode) {
+	root.expanded = true
+	s.root, s.current = root, root
+	s.rowOffset = 0
+	if s.changed != nil {
+		s.changed(root.path())
+	}
+}
+
+// Clear removes all data.
+func (s *StructuredDataView) Clear() *StructuredDataView {
+	s.root, s.current = nil, nil
+	return s
+}
+
+// GetCurrentPath returns the path of the selected node, e.g. ".items[2].name".
+// An empty string is returned if there is no data.
+func (s *StructuredDataView) GetCurrentPath() string {
+	if s.current == nil {
+		return ""
+	}
+	return s.current.path()
+}
+
+// GetCurrentValue returns the value of the selected node as compact JSON. An
+// empty string is returned if there is no data.
+func (s *StructuredDataView) GetCurrentValue() string {
+	if s.current == nil {
+		return ""
+	}
+	return s.current.json()
+}
+
+// ExpandAll expands all nodes.
+func (s *StructuredDataView) ExpandAll() *StructuredDataView {
+	s.walk(func(node *dataNode) {
+		node.expanded = true
+	})
+	return s
+}
+
+// CollapseAll collapses all nodes except the top-level node.
+func (s *StructuredDataView) CollapseAll() *StructuredDataView {
+	s.walk(func(node *dataNode) {
+		node.expanded = node == s.root
+	})
+	if s.current != nil {
+		for s.current.parent != nil && s.current.parent != s.root {
+			s.current = s.current.parent
+		}
+	}
+	return s
+}
+
+// SetSearch sets the search text (case-insensitive) and selects the first
+// matching node after the selected node.
+func (s *StructuredDataView) SetSearch(text string) *StructuredDataView {
+	s.search = text
+	if text != "" && (s.current == nil || !s.matches(s.current)) {
+		s.findMatch(true)
+	}
+	return s
+}
+
+// GetSearch returns the current search text.
+func (s *StructuredDataView) GetSearch() string {
+	return s.search
+}
+
+// SetKeyColor sets the color of object keys and array indices.
+func (s *StructuredDataView) SetKeyColor(color tcell.Color) *StructuredDataView {
+	s.keyColor = color
+	return s
+}
+
+// SetStringColor sets the color of string values.
+func (s *StructuredDataView) SetStringColor(color tcell.Color) *StructuredDataView {
+	s.stringColor = color
+	return s
+}
+
+// SetNumberColor sets the color of number values.
+func (s *StructuredDataView) SetNumberColor(color tcell.Color) *StructuredDataView {
+	s.numberColor = color
+	return s
+}
+
+// SetKeywordColor sets the color of booleans and null values.
+func (s *StructuredDataView) SetKeywordColor(color tcell.Color) *StructuredDataView {
+	s.keywordColor = color
+	return s
+}
+
+// SetStructureColor sets the color of the tree's structure, i.e. expansion
+// markers and object and array sizes.
+func (s *StructuredDataView) SetStructureColor(color tcell.Color) *StructuredDataView {
+	s.structureColor = color
+	return s
+}
+
+// SetSelectedTextColor sets the text color of the selected row.
+func (s *StructuredDataView) SetSelectedTextColor(color tcell.Color) *StructuredDataView {
+	s.selectedTextColor = color
+	return s
+}
+
+// SetSelectedBackgroundColor sets the background color of the selected row.
+func (s *StructuredDataView) SetSelectedBackgroundColor(color tcell.Color) *StructuredDataView {
+	s.selectedBackgroundColor = color
+	return s
+}
+
+// SetStatusColor sets the color of the status line.
+func (s *StructuredDataView) SetStatusColor(color tcell.Color) *StructuredDataView {
+	s.statusColor = color
+	return s
+}
+
+// SetCopyFunc sets a handler which is called when the user presses "y" to copy
+// the selected node's value. It receives the node's path and its value as
+// compact JSON (strings are quoted). The handler could, for example, write the
+// value to the system clipboard.
+func (s *StructuredDataView) SetCopyFunc(handler func(path, value string)) *StructuredDataView {
+	s.copy = handler
+	return s
+}
+
+// SetChangedFunc sets a handler which is called when the selected node
+// changes. It receives the node's path.
+func (s *StructuredDataView) SetChangedFunc(handler func(path string)) *StructuredDataView {
+	s.changed = handler
+	return s
+}
+
+// SetDoneFunc sets a handler which is called when the user leaves the view.
+// The callback function is provided with the key that was pressed, which is
+// one of the following:
+//
+//   - KeyEscape: Leaving the view with no specific direction.
+//   - KeyTab: Move to the next field.
+//   - KeyBacktab: Move to the previous field.
+func (s *StructuredDataView) SetDoneFunc(handler func(key tcell.Key)) *StructuredDataView {
+	s.done = handler
+	return s
+}
+
+// walk calls the given function for all nodes in pre-order.
+func (s *StructuredDataView) walk(callback func(node *dataNode)) {
+	var walk func(node *dataNode)
+	walk = func(node *dataNode) {
+		callback(node)
+		for _, child := range node.children {
+			walk(child)
+		}
+	}
+	if s.root != nil {
+		walk(s.root)
+	}
+}
+
+// visibleNodes returns the nodes which are currently shown, in order.
+func (s *StructuredDataView) visibleNodes() []*dataNode {
+	var (
+		nodes []*dataNode
+		add   func(node *dataNode)
+	)
+	add = func(node *dataNode) {
+		nodes = append(nodes, node)
+		if node.expanded {
+			for _, child := range node.children {
+				add(child)
+			}
+		}
+	}
+	if s.root != nil {
+		add(s.root)
+	}
+	return nodes
+}
+
+// selectNode selects the given node and invokes the "changed" callback.
+func (s *StructuredDataView) selectNode(node *dataNode) {
+	if node == s.current {
+		return
+	}
+	s.current = node
+	if s.changed != nil {
+		s.changed(node.path())
+	}
+}
+
+// move selects the visible node the given number of rows away from the
+// selected node.
+func (s *StructuredDataView) move(rows int) {
+	nodes := s.visibleNodes()
+	if len(nodes) == 0 {
+		return
+	}
+	index := rows
+	for i, node := range nodes {
+		if node == s.current {
+			index += i
+			break
+		}
+	}
+	if index < 0 {
+		index = 0
+	} else if index >= len(nodes) {
+		index = len(nodes) - 1
+	}
+	s.selectNode(nodes[index])
+}
+
+// matches returns whether the given node's key or value contains the search
+// text.
+func (s *StructuredDataView) matches(node *dataNode) bool {
+	search := strings.ToLower(s.search)
+	return strings.Contains(strings.ToLower(node.key), search) ||
+		node.kind != dataObject && node.kind != dataArray && strings.Contains(strings.ToLower(node.value), search)
+}
+
+// findMatch selects the next (or previous if "forward" is false) node which
+// matches the search text, wrapping around, and expands its ancestors.
+func (s *StructuredDataView) findMatch(forward bool) {
+	if s.search == "" || s.root == nil {
+		return
+	}
+	var nodes []*dataNode
+	s.walk(func(node *dataNode) {
+		nodes = append(nodes, node)
+	})
+	start := 0
+	for index, node := range nodes {
+		if node == s.current {
+			start = index
+			break
+		}
+	}
+	for offset := 1; offset <= len(nodes); offset++ {
+		index := start + offset
+		if !forward {
+			index = start - offset
+		}
+		node := nodes[(index%len(nodes)+len(nodes))%len(nodes)]
+		if s.matches(node) {
+			for parent := node.parent; parent != nil; parent = parent.parent {
+				parent.expanded = true
+			}
+			s.selectNode(node)
+			return
+		}
+	}
+}
+
+// SaveState returns the paths of the expanded nodes and of the selected node
+// as JSON. See SaveLayout() for details.
+func (s *StructuredDataView) SaveState() ([]byte, error) {
+	state := structuredDataViewState{Current: s.GetCurrentPath()}
+	s.walk(func(node *dataNode) {
+		if node.expanded && len(node.children) > 0 {
+			state.Expanded = append(state.Expanded, node.path())
+		}
+	})
+	return json.Marshal(state)
+}
+
+// RestoreState restores a state returned by SaveState(). Paths which don't
+// exist in the current data are ignored.
+func (s *StructuredDataView) RestoreState(data []byte) error {
+	var state structuredDataViewState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	expanded := make(map[string]bool)
+	for _, path := range state.Expanded {
+		expanded[path] = true
+	}
+	var current *dataNode
+	s.walk(func(node *dataNode) {
+		path := node.path()
+		if len(node.children) > 0 {
+			node.expanded = expanded[path]
+		}
+		if path == state.Current {
+			current = node
+		}
+	})
+	if current != nil {
+		s.selectNode(current)
+	}
+	return nil
+}
+
+// applyTheme is called when the application's theme changes.
+func (s *StructuredDataView) applyTheme(from, to *Theme) {
+	s.Box.applyTheme(from, to)
+	themeColor(&s.keyColor, from.SecondaryTextColor, to.SecondaryTextColor)
+	themeColor(&s.numberColor, from.TertiaryTextColor, to.TertiaryTextColor)
+	themeColor(&s.structureColor, from.PrimaryTextColor, to.PrimaryTextColor)
+	themeColor(&s.selectedTextColor, from.PrimitiveBackgroundColor, to.PrimitiveBackgroundColor)
+	themeColor(&s.selectedBackgroundColor, from.PrimaryTextColor, to.PrimaryTextColor)
+	themeColor(&s.statusColor, from.PrimaryTextColor, to.PrimaryTextColor)
+}
+
+// Draw draws this primitive onto the screen.
+func (s *StructuredDataView) Draw(screen tcell.Screen) {
+	defer s.applyStyle(&s.structureColor)()
+	s.Box.Draw(screen)
+	x, y, width, height := s.GetInnerRect()
+	if width <= 0 || height <= 0 || s.root == nil {
+		return
+	}
+
+	// Draw the status line.
+	status := s.current.path()
+	if s.searching || s.search != "" {
+		status += "  /" + s.search
+		if s.searching {
+			status += "_"
+		}
+	}
+	if height > 1 {
+		height--
+		Print(screen, Escape(status), x, y+height, width, AlignLeft, s.statusColor)
+	}
+	s.pageSize = height
+
+	// Make sure the selected node is visible.
+	nodes := s.visibleNodes()
+	currentRow := 0
+	for index, node := range nodes {
+		if node == s.current {
+			currentRow = index
+			break
+		}
+	}
+	if currentRow < s.rowOffset {
+		s.rowOffset = currentRow
+	} else if currentRow >= s.rowOffset+height {
+		s.rowOffset = currentRow - height + 1
+	}
+	if s.rowOffset > len(nodes)-height {
+		s.rowOffset = len(nodes) - height
+	}
+	if s.rowOffset < 0 {
+		s.rowOffset = 0
+	}
+
+	// Draw the nodes.
+	for row := 0; row < height && s.rowOffset+row < len(nodes); row++ {
+		node := nodes[s.rowOffset+row]
+		var depth int
+		for parent := node.parent; parent != nil; parent = parent.parent {
+			depth++
+		}
+		selected := node == s.current && s.hasFocus
+		if selected {
+			style := tcell.StyleDefault.Background(s.selectedBackgroundColor)
+			for column := 0; column < width; column++ {
+				screen.SetContent(x+column, y+row, ' ', nil, style)
+			}
+		}
+		color := func(color tcell.Color) tcell.Color {
+			if selected {
+				return s.selectedTextColor
+			}
+			return color
+		}
+
+		// Compose the row's parts.
+		marker := "  "
+		if node.kind == dataObject || node.kind == dataArray {
+			if node.expanded {
+				marker = "▼ "
+			} else {
+				marker = "▶ "
+			}
+		}
+		column := x + depth*2
+		add := func(text string, textColor tcell.Color) {
+			if column < x+width {
+				_, printed := Print(screen, Escape(text), column, y+row, x+width-column, AlignLeft, color(textColor))
+				column += printed
+			}
+		}
+		add(marker, s.structureColor)
+		if node.parent != nil {
+			key := strconv.Quote(node.key)
+			if node.isIndex {
+				key = "[" + node.key + "]"
+			}
+			add(key, s.keyColor)
+			add(": ", s.structureColor)
+		}
+		switch node.kind {
+		case dataObject:
+			add(fmt.Sprintf("{%d}", len(node.children)), s.structureColor)
+		case dataArray:
+			add(fmt.Sprintf("[%d]", len(node.children)), s.structureColor)
+		case dataString:
+			add(strconv.Quote(node.value), s.stringColor)
+		case dataNumber:
+			add(node.value, s.numberColor)
+		default:
+			add(node.value, s.keywordColor)
+		}
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (s *StructuredDataView) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return s.wrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		// Typing a search.
+		if s.searching {
+			switch event.Key() {
+			case tcell.KeyRune:
+				s.search += string(event.Rune())
+			case tcell.KeyBackspace, tcell.KeyBackspace2:
+				if s.search != "" {
+					runes := []rune(s.search)
+					s.search = string(runes[:len(runes)-1])
+				}
+			case tcell.KeyEnter:
+				s.searching = false
+				if s.current != nil && !s.matches(s.current) {
+					s.findMatch(true)
+				}
+			case tcell.KeyEscape:
+				s.searching = false
+				s.search = ""
+			}
+			return
+		}
+		if s.current == nil {
+			switch key := event.Key(); key {
+			case tcell.KeyEscape, tcell.KeyTab, tcell.KeyBacktab:
+				if s.done != nil {
+					s.done(key)
+				}
+			}
+			return
+		}
+
+		expand := func() {
+			if len(s.current.children) == 0 {
+				return
+			}
+			if !s.current.expanded {
+				s.current.expanded = true
+			} else {
+				s.selectNode(s.current.children[0])
+			}
+		}
+		collapse := func() {
+			if s.current.expanded && len(s.current.children) > 0 {
+				s.current.expanded = false
+			} else if s.current.parent != nil {
+				s.selectNode(s.current.parent)
+			}
+		}
+		toggle := func() {
+			if len(s.current.children) > 0 {
+				s.current.expanded = !s.current.expanded
+			}
+		}
+		switch key := event.Key(); key {
+		case tcell.KeyUp:
+			s.move(-1)
+		case tcell.KeyDown:
+			s.move(1)
+		case tcell.KeyRight:
+			expand()
+		case tcell.KeyLeft:
+			collapse()
+		case tcell.KeyPgUp:
+			s.move(-s.pageSize)
+		case tcell.KeyPgDn:
+			s.move(s.pageSize)
+		case tcell.KeyHome:
+			s.selectNode(s.root)
+		case tcell.KeyEnd:
+			nodes := s.visibleNodes()
+			s.selectNode(nodes[len(nodes)-1])
+		case tcell.KeyEnter:
+			toggle()
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'k':
+				s.move(-1)
+			case 'j':
+				s.move(1)
+			case 'l':
+				expand()
+			case 'h':
+				collapse()
+			case 'g':
+				s.selectNode(s.root)
+			case 'G':
+				nodes := s.visibleNodes()
+				s.selectNode(nodes[len(nodes)-1])
+			case ' ':
+				toggle()
+			case '/':
+				s.searching = true
+				s.search = ""
+			case 'n':
+				s.findMatch(true)
+			case 'N':
+				s.findMatch(false)
+			case 'y':
+				if s.copy != nil {
+					s.copy(s.current.path(), s.current.json())
+				}
+			}
+		case tcell.KeyEscape, tcell.KeyTab, tcell.KeyBacktab:
+			if s.done != nil {
+				s.done(key)
+			}
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (s *StructuredDataView) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return s.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+		if !s.InRect(x, y) {
+			return false, nil
+		}
+		switch action {
+		case MouseLeftClick:
+			setFocus(s)
+			_, rectY, _, _ := s.GetInnerRect()
+			nodes := s.visibleNodes()
+			if index := s.rowOffset + y - rectY; y >= rectY && y < rectY+s.pageSize && index < len(nodes) {
+				if nodes[index] == s.current && len(s.current.children) > 0 {
+					s.current.expanded = !s.current.expanded
+				} else {
+					s.selectNode(nodes[index])
+				}
+			}
+		case MouseScrollUp:
+			s.move(-1)
+		case MouseScrollDown:
+			s.move(1)
+		}
+		return true, nil
+	})
+}
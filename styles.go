@@ -1,13 +1,16 @@
 package tview
 
-import "github.com/gdamore/tcell"
+import (
+	"os"
+	"strconv"
+	"strings"
 
-// Styles defines various colors used when primitives are initialized. These
-// may be changed to accommodate a different look and feel.
-//
-// The default is for applications with a black background and basic colors:
-// black, white, yellow, green, and blue.
-var Styles = struct {
+	"github.com/gdamore/tcell"
+)
+
+// Theme defines the colors and border runes used by primitives. See Styles
+// and Application.SetTheme().
+type Theme struct {
 	PrimitiveBackgroundColor    tcell.Color // Main background color for primitives.
 	ContrastBackgroundColor     tcell.Color // Background color for contrasting elements.
 	MoreContrastBackgroundColor tcell.Color // Background color for even more contrasting elements.
@@ -18,7 +21,172 @@ var Styles = struct {
 	SecondaryTextColor          tcell.Color // Secondary text (e.g. labels).
 	TertiaryTextColor           tcell.Color // Tertiary text (e.g. subtitles, notes).
 	InverseTextColor            tcell.Color // Text on primary-colored backgrounds.
-}{
+	DisabledTextColor           tcell.Color // Text of disabled primitives and items.
+	ScrollBarTrackColor         tcell.Color // The tracks of scroll bars.
+	ScrollBarThumbColor         tcell.Color // The thumbs of scroll bars.
+
+	// The runes used to draw box borders.
+	Borders BorderRunes
+
+	// The runes used to draw the tracks and thumbs of scroll bars.
+	ScrollBarTrack, ScrollBarThumb rune
+
+	// The rune which marks truncated text, e.g. in table cells and list items.
+	Ellipsis rune
+
+	// Colors for individual primitive types which differ from the ones above.
+	// The keys of the outer map are type names, e.g. "Button". The keys of the
+	// inner maps are color names as used by LoadTheme(), e.g. "primaryText".
+	Widgets map[string]map[string]tcell.Color
+}
+
+// BorderRunes defines the runes used to draw the borders of boxes, one set for
+// boxes without focus and one set for boxes with focus.
+type BorderRunes struct {
+	Horizontal, Vertical                       rune
+	TopLeft, TopRight, BottomLeft, BottomRight rune
+	HorizontalFocus, VerticalFocus             rune
+	TopLeftFocus, TopRightFocus                rune
+	BottomLeftFocus, BottomRightFocus          rune
+}
+
+// Border styles which can be used with Box.SetBorderStyle() or as the borders
+// of a theme.
+var (
+	// BorderStyleSingle draws single lines, or double lines if the box has
+	// focus. This is the default.
+	BorderStyleSingle = BorderRunes{
+		Horizontal:       GraphicsHoriBar,
+		Vertical:         GraphicsVertBar,
+		TopLeft:          GraphicsTopLeftCorner,
+		TopRight:         GraphicsTopRightCorner,
+		BottomLeft:       GraphicsBottomLeftCorner,
+		BottomRight:      GraphicsBottomRightCorner,
+		HorizontalFocus:  GraphicsDbVertBar,
+		VerticalFocus:    GraphicsDbHorBar,
+		TopLeftFocus:     GraphicsDbTopLeftCorner,
+		TopRightFocus:    GraphicsDbTopRightCorner,
+		BottomLeftFocus:  GraphicsDbBottomLeftCorner,
+		BottomRightFocus: GraphicsDbBottomRightCorner,
+	}
+
+	// BorderStyleRounded draws single lines with rounded corners. Focus is
+	// indicated by thick lines with square corners.
+	BorderStyleRounded = BorderRunes{
+		Horizontal:       '\u2500',
+		Vertical:         '\u2502',
+		TopLeft:          '\u256d',
+		TopRight:         '\u256e',
+		BottomLeft:       '\u2570',
+		BottomRight:      '\u256f',
+		HorizontalFocus:  '\u2501',
+		VerticalFocus:    '\u2503',
+		TopLeftFocus:     '\u250f',
+		TopRightFocus:    '\u2513',
+		BottomLeftFocus:  '\u2517',
+		BottomRightFocus: '\u251b',
+	}
+
+	// BorderStyleDouble always draws double lines.
+	BorderStyleDouble = BorderRunes{
+		Horizontal:       GraphicsDbVertBar,
+		Vertical:         GraphicsDbHorBar,
+		TopLeft:          GraphicsDbTopLeftCorner,
+		TopRight:         GraphicsDbTopRightCorner,
+		BottomLeft:       GraphicsDbBottomLeftCorner,
+		BottomRight:      GraphicsDbBottomRightCorner,
+		HorizontalFocus:  GraphicsDbVertBar,
+		VerticalFocus:    GraphicsDbHorBar,
+		TopLeftFocus:     GraphicsDbTopLeftCorner,
+		TopRightFocus:    GraphicsDbTopRightCorner,
+		BottomLeftFocus:  GraphicsDbBottomLeftCorner,
+		BottomRightFocus: GraphicsDbBottomRightCorner,
+	}
+
+	// BorderStyleThick always draws thick lines.
+	BorderStyleThick = BorderRunes{
+		Horizontal:       '\u2501',
+		Vertical:         '\u2503',
+		TopLeft:          '\u250f',
+		TopRight:         '\u2513',
+		BottomLeft:       '\u2517',
+		BottomRight:      '\u251b',
+		HorizontalFocus:  '\u2501',
+		VerticalFocus:    '\u2503',
+		TopLeftFocus:     '\u250f',
+		TopRightFocus:    '\u2513',
+		BottomLeftFocus:  '\u2517',
+		BottomRightFocus: '\u251b',
+	}
+
+	// BorderStyleASCII uses ASCII characters only, for terminals or fonts
+	// without box drawing characters. Focus is indicated by '=' and '#'.
+	BorderStyleASCII = BorderRunes{
+		Horizontal:       '-',
+		Vertical:         '|',
+		TopLeft:          '+',
+		TopRight:         '+',
+		BottomLeft:       '+',
+		BottomRight:      '+',
+		HorizontalFocus:  '=',
+		VerticalFocus:    '#',
+		TopLeftFocus:     '#',
+		TopRightFocus:    '#',
+		BottomLeftFocus:  '#',
+		BottomRightFocus: '#',
+	}
+
+	// BorderStyleNone draws the border with spaces. The border still takes up
+	// space and the title is still shown.
+	BorderStyleNone = BorderRunes{
+		Horizontal:       ' ',
+		Vertical:         ' ',
+		TopLeft:          ' ',
+		TopRight:         ' ',
+		BottomLeft:       ' ',
+		BottomRight:      ' ',
+		HorizontalFocus:  ' ',
+		VerticalFocus:    ' ',
+		TopLeftFocus:     ' ',
+		TopRightFocus:    ' ',
+		BottomLeftFocus:  ' ',
+		BottomRightFocus: ' ',
+	}
+)
+
+// Style defines the appearance of a primitive: its foreground color, its
+// background color, and text attributes such as bold or underline. See
+// Box.SetStyle() for details.
+type Style struct {
+	Foreground tcell.Color    // The color of the primitive's main text.
+	Background tcell.Color    // The background color.
+	Attributes tcell.AttrMask // Text attributes, e.g. tcell.AttrBold.
+}
+
+// withAttributes returns the given tcell style with the style's attributes
+// added to it.
+func (s Style) withAttributes(style tcell.Style) tcell.Style {
+	if s.Attributes&tcell.AttrBold != 0 {
+		style = style.Bold(true)
+	}
+	if s.Attributes&tcell.AttrBlink != 0 {
+		style = style.Blink(true)
+	}
+	if s.Attributes&tcell.AttrReverse != 0 {
+		style = style.Reverse(true)
+	}
+	if s.Attributes&tcell.AttrUnderline != 0 {
+		style = style.Underline(true)
+	}
+	if s.Attributes&tcell.AttrDim != 0 {
+		style = style.Dim(true)
+	}
+	return style
+}
+
+// DarkTheme is the default theme for terminals with a dark background. It uses
+// basic colors only: black, white, yellow, green, and blue.
+var DarkTheme = Theme{
 	PrimitiveBackgroundColor:    tcell.ColorBlack,
 	ContrastBackgroundColor:     tcell.ColorBlue,
 	MoreContrastBackgroundColor: tcell.ColorGreen,
@@ -29,4 +197,149 @@ var Styles = struct {
 	SecondaryTextColor:          tcell.ColorYellow,
 	TertiaryTextColor:           tcell.ColorGreen,
 	InverseTextColor:            tcell.ColorBlue,
+	DisabledTextColor:           tcell.ColorGray,
+	ScrollBarTrackColor:         tcell.ColorWhite,
+	ScrollBarThumbColor:         tcell.ColorWhite,
+	Borders:                     BorderStyleSingle,
+	ScrollBarTrack:              '░',
+	ScrollBarThumb:              '█',
+	Ellipsis:                    GraphicsEllipsis,
+}
+
+// LightTheme is the default theme for terminals with a light background. Like
+// DarkTheme, it uses basic colors only.
+var LightTheme = Theme{
+	PrimitiveBackgroundColor:    tcell.ColorWhite,
+	ContrastBackgroundColor:     tcell.ColorSilver,
+	MoreContrastBackgroundColor: tcell.ColorNavy,
+	BorderColor:                 tcell.ColorBlack,
+	TitleColor:                  tcell.ColorBlack,
+	GraphicsColor:               tcell.ColorBlack,
+	PrimaryTextColor:            tcell.ColorBlack,
+	SecondaryTextColor:          tcell.ColorMaroon,
+	TertiaryTextColor:           tcell.ColorGreen,
+	InverseTextColor:            tcell.ColorAqua,
+	DisabledTextColor:           tcell.ColorGray,
+	ScrollBarTrackColor:         tcell.ColorBlack,
+	ScrollBarThumbColor:         tcell.ColorBlack,
+	Borders:                     BorderStyleSingle,
+	ScrollBarTrack:              '░',
+	ScrollBarThumb:              '█',
+	Ellipsis:                    GraphicsEllipsis,
+}
+
+// Styles defines various colors used when primitives are initialized. These
+// may be changed to accommodate a different look and feel. To change the colors
+// of primitives which already exist, use Application.SetTheme().
+//
+// The default is DarkTheme. To adapt to the terminal's background, assign
+// ThemeForBackground() before any primitives are created.
+var Styles = DarkTheme
+
+// ThemeForBackground returns LightTheme if the terminal appears to have a
+// light background (see HasLightBackground()) and DarkTheme otherwise.
+func ThemeForBackground() Theme {
+	if HasLightBackground() {
+		return LightTheme
+	}
+	return DarkTheme
+}
+
+// HasLightBackground returns whether the terminal appears to have a light
+// background. This is determined from environment variables:
+//
+//   - TVIEW_BACKGROUND may be set to "light" or "dark" by the user.
+//   - Otherwise, COLORFGBG is evaluated, which is set by some terminals (e.g.
+//     rxvt, Konsole, iTerm2) to the foreground and background palette colors,
+//     e.g. "0;15" for black text on a white background.
+//
+// If neither variable is set, the background is assumed to be dark.
+//
+// The terminal is not asked for its background color (with an OSC 11 query).
+// Its response would have to be read from the terminal's input, in raw mode
+// and with a timeout for terminals which don't respond, before tcell takes
+// over the terminal. This cannot be done portably without interfering with
+// tcell's own input handling.
+func HasLightBackground() bool {
+	switch strings.ToLower(os.Getenv("TVIEW_BACKGROUND")) {
+	case "light":
+		return true
+	case "dark":
+		return false
+	}
+
+	// COLORFGBG may also contain a middle field, e.g. "15;default;0".
+	fields := strings.Split(os.Getenv("COLORFGBG"), ";")
+	background, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil {
+		return false
+	}
+
+	// Palette colors 7 (silver) and 9 to 15 (bright colors) are light.
+	return background == 7 || background >= 9 && background <= 15
+}
+
+// color returns a pointer to the theme's color with the given name (see
+// LoadTheme()), or nil if there is no such color.
+func (t *Theme) color(name string) *tcell.Color {
+	switch name {
+	case "primitiveBackground":
+		return &t.PrimitiveBackgroundColor
+	case "contrastBackground":
+		return &t.ContrastBackgroundColor
+	case "moreContrastBackground":
+		return &t.MoreContrastBackgroundColor
+	case "border":
+		return &t.BorderColor
+	case "title":
+		return &t.TitleColor
+	case "graphics":
+		return &t.GraphicsColor
+	case "primaryText":
+		return &t.PrimaryTextColor
+	case "secondaryText":
+		return &t.SecondaryTextColor
+	case "tertiaryText":
+		return &t.TertiaryTextColor
+	case "inverseText":
+		return &t.InverseTextColor
+	case "disabledText":
+		return &t.DisabledTextColor
+	case "scrollBarTrack":
+		return &t.ScrollBarTrackColor
+	case "scrollBarThumb":
+		return &t.ScrollBarThumbColor
+	}
+	return nil
+}
+
+// forWidget returns the theme used for primitives of the given type, i.e. the
+// theme with the type's entry in Widgets applied to it.
+func (t *Theme) forWidget(name string) *Theme {
+	colors, ok := t.Widgets[name]
+	if !ok {
+		return t
+	}
+	theme := *t
+	for colorName, color := range colors {
+		if field := theme.color(colorName); field != nil {
+			*field = color
+		}
+	}
+	return &theme
+}
+
+// themeable is implemented by primitives which can switch to a different theme
+// after they were created.
+type themeable interface {
+	// applyTheme replaces the colors taken from the "from" theme with the
+	// corresponding colors of the "to" theme.
+	applyTheme(from, to *Theme)
+}
+
+// themeColor sets the given color to "to" if it is currently "from".
+func themeColor(color *tcell.Color, from, to tcell.Color) {
+	if *color == from {
+		*color = to
+	}
 }
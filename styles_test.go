@@ -0,0 +1,44 @@
+package tview
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestThemeForBackground(t *testing.T) {
+	defer os.Setenv("TVIEW_BACKGROUND", os.Getenv("TVIEW_BACKGROUND"))
+	defer os.Setenv("COLORFGBG", os.Getenv("COLORFGBG"))
+
+	for _, test := range []struct {
+		background, colorFgBg string
+		light                 bool
+	}{
+		{"", "", false},
+		{"light", "", true},
+		{"Dark", "0;15", false},
+		{"", "0;15", true},
+		{"", "15;0", false},
+		{"", "0;default;7", true},
+		{"", "0;8", false},
+		{"", "invalid", false},
+	} {
+		os.Setenv("TVIEW_BACKGROUND", test.background)
+		os.Setenv("COLORFGBG", test.colorFgBg)
+		if light := HasLightBackground(); light != test.light {
+			t.Errorf("HasLightBackground() with TVIEW_BACKGROUND=%q COLORFGBG=%q = %t, want %t", test.background, test.colorFgBg, light, test.light)
+		}
+		want := DarkTheme
+		if test.light {
+			want = LightTheme
+		}
+		if theme := ThemeForBackground(); !reflect.DeepEqual(theme, want) {
+			t.Errorf("ThemeForBackground() with TVIEW_BACKGROUND=%q COLORFGBG=%q returned the wrong theme", test.background, test.colorFgBg)
+		}
+	}
+
+	// Styles is not changed when the package is loaded.
+	if !reflect.DeepEqual(Styles, DarkTheme) {
+		t.Error("Styles is not DarkTheme by default")
+	}
+}
@@ -0,0 +1,12 @@
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package tview
+
+// stopProcess does nothing as job control is not supported on this platform.
+func stopProcess() {}
+
+// watchStopSignal does nothing as job control is not supported on this
+// platform.
+func watchStopSignal(handler func()) (cancel func()) {
+	return func() {}
+}
@@ -0,0 +1,65 @@
+// +build darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package tview
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// stopWatches holds the channels which receive SIGTSTP (see
+// watchStopSignal()).
+var stopWatches = struct {
+	sync.Mutex
+	channels map[chan os.Signal]bool
+}{channels: make(map[chan os.Signal]bool)}
+
+// stopProcess stops the process, like a shell's job control does when Ctrl-Z
+// is pressed, and returns when the process is continued (SIGCONT).
+func stopProcess() {
+	cont := make(chan os.Signal, 1)
+	signal.Notify(cont, syscall.SIGCONT)
+	defer signal.Stop(cont)
+	signal.Reset(syscall.SIGTSTP) // Let the signal stop the process.
+	syscall.Kill(syscall.Getpid(), syscall.SIGTSTP)
+	<-cont
+
+	// Resetting the signal removed the watches. Register them again.
+	stopWatches.Lock()
+	defer stopWatches.Unlock()
+	for signals := range stopWatches.channels {
+		signal.Notify(signals, syscall.SIGTSTP)
+	}
+}
+
+// watchStopSignal calls the given handler in a separate goroutine whenever the
+// process is asked to stop (SIGTSTP, e.g. sent by "kill -TSTP"), instead of
+// stopping it with the terminal still in raw mode. The returned function ends
+// the watch.
+func watchStopSignal(handler func()) (cancel func()) {
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	stopWatches.Lock()
+	stopWatches.channels[signals] = true
+	signal.Notify(signals, syscall.SIGTSTP)
+	stopWatches.Unlock()
+	go func() {
+		for {
+			select {
+			case <-signals:
+				handler()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		stopWatches.Lock()
+		delete(stopWatches.channels, signals)
+		signal.Stop(signals)
+		stopWatches.Unlock()
+		close(done)
+	}
+}
@@ -1,6 +1,7 @@
 package tview
 
 import (
+	"encoding/json"
 	"sort"
 
 	"github.com/gdamore/tcell"
@@ -38,23 +39,29 @@ type TableCell struct {
 
 	// The position and width of the cell the last time table was drawn.
 	x, y, width int
+
+	// The screen width of the text.
+	textWidth textWidth
 }
 
 // NewTableCell returns a new table cell with sensible defaults. That is, left
 // aligned text with the primary text color (see Styles) and a transparent
 // background (using the background of the Table).
 func NewTableCell(text string) *TableCell {
-	return &TableCell{
+	c := &TableCell{
 		Text:            text,
 		Align:           AlignLeft,
 		Color:           Styles.PrimaryTextColor,
 		BackgroundColor: tcell.ColorDefault,
 	}
+	c.textWidth.get(text)
+	return c
 }
 
 // SetText sets the cell's text.
 func (c *TableCell) SetText(text string) *TableCell {
 	c.Text = text
+	c.textWidth.get(text)
 	return c
 }
 
@@ -212,6 +219,9 @@ type Table struct {
 	// The number of visible rows the last time the table was drawn.
 	visibleRows int
 
+	// The scroll bar settings.
+	scrollBar scrollBar
+
 	// An optional function which gets called when the user presses Enter on a
 	// selected cell. If entire rows selected, the column value is undefined.
 	// Likewise for entire columns.
@@ -227,13 +237,23 @@ type Table struct {
 	done func(key tcell.Key)
 }
 
+// tableState is the state of a Table saved with SaveState().
+type tableState struct {
+	SelectedRow    int `json:"selectedRow"`
+	SelectedColumn int `json:"selectedColumn"`
+	RowOffset      int `json:"rowOffset"`
+	ColumnOffset   int `json:"columnOffset"`
+}
+
 // NewTable returns a new table.
 func NewTable() *Table {
+	styles := Styles.forWidget("Table")
 	return &Table{
 		Box:          NewBox(),
-		bordersColor: Styles.GraphicsColor,
+		bordersColor: styles.GraphicsColor,
 		separator:    ' ',
 		lastColumn:   -1,
+		scrollBar:    newScrollBar(ScrollBarNever, styles),
 	}
 }
 
@@ -269,6 +289,20 @@ func (t *Table) SetSeparator(separator rune) *Table {
 	return t
 }
 
+// SetScrollBarVisibility sets when a vertical scroll bar is shown on the right
+// edge of the table. The scroll bar refers to the rows below the fixed rows
+// (see SetFixed()). The default is ScrollBarNever.
+func (t *Table) SetScrollBarVisibility(visibility ScrollBarVisibility) *Table {
+	t.scrollBar.visibility = visibility
+	return t
+}
+
+// SetScrollBarColors sets the colors of the scroll bar's track and thumb.
+func (t *Table) SetScrollBarColors(track, thumb tcell.Color) *Table {
+	t.scrollBar.trackColor, t.scrollBar.thumbColor = track, thumb
+	return t
+}
+
 // SetFixed sets the number of fixed rows and columns which are always visible
 // even when the rest of the cells are scrolled out of view. Rows are always the
 // top-most ones. Columns are always the left-most ones.
@@ -430,8 +464,45 @@ func (t *Table) ScrollToEnd() *Table {
 	return t
 }
 
+// SaveState returns the selection and the scroll position as JSON. See
+// SaveLayout() for details.
+func (t *Table) SaveState() ([]byte, error) {
+	return json.Marshal(tableState{
+		SelectedRow:    t.selectedRow,
+		SelectedColumn: t.selectedColumn,
+		RowOffset:      t.rowOffset,
+		ColumnOffset:   t.columnOffset,
+	})
+}
+
+// RestoreState restores a state returned by SaveState().
+func (t *Table) RestoreState(data []byte) error {
+	var state tableState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	t.Select(state.SelectedRow, state.SelectedColumn)
+	t.SetOffset(state.RowOffset, state.ColumnOffset)
+	return nil
+}
+
+// applyTheme is called when the application's theme changes.
+func (t *Table) applyTheme(from, to *Theme) {
+	t.Box.applyTheme(from, to)
+	themeColor(&t.bordersColor, from.GraphicsColor, to.GraphicsColor)
+	t.scrollBar.applyTheme(from, to)
+	for _, row := range t.cells {
+		for _, cell := range row {
+			if cell != nil {
+				themeColor(&cell.Color, from.PrimaryTextColor, to.PrimaryTextColor)
+			}
+		}
+	}
+}
+
 // Draw draws this primitive onto the screen.
 func (t *Table) Draw(screen tcell.Screen) {
+	defer t.applyStyle()()
 	t.Box.Draw(screen)
 
 	// What's our available screen space?
@@ -442,6 +513,13 @@ func (t *Table) Draw(screen tcell.Screen) {
 		t.visibleRows = height
 	}
 
+	// Reserve space for the scroll bar. It is drawn last.
+	scrollRows, scrollVisible := len(t.cells)-t.fixedRows, t.visibleRows-t.fixedRows
+	scrollBar := t.scrollBar.shown(scrollVisible, scrollRows)
+	if scrollBar {
+		width--
+	}
+
 	// Return the cell at the specified position (nil if it doesn't exist).
 	getCell := func(row, column int) *TableCell {
 		if row < 0 || column < 0 || row >= len(t.cells) || column >= len(t.cells[row]) {
@@ -589,7 +667,7 @@ ColumnLoop:
 		expansion := 0
 		for _, row := range rows {
 			if cell := getCell(row, column); cell != nil {
-				cellWidth := StringWidth(cell.Text)
+				cellWidth := cell.textWidth.get(cell.Text)
 				if cell.MaxWidth > 0 && cell.MaxWidth < cellWidth {
 					cellWidth = cell.MaxWidth
 				}
@@ -683,12 +761,7 @@ ColumnLoop:
 				finalWidth = width - columnX - 1
 			}
 			cell.x, cell.y, cell.width = x+columnX+1, y+rowY, finalWidth
-			_, printed := Print(screen, cell.Text, x+columnX+1, y+rowY, finalWidth, cell.Align, cell.Color)
-			if StringWidth(cell.Text)-printed > 0 && printed > 0 {
-				_, _, style, _ := screen.GetContent(x+columnX+1+finalWidth-1, y+rowY)
-				fg, _, _ := style.Decompose()
-				Print(screen, string(GraphicsEllipsis), x+columnX+1+finalWidth-1, y+rowY, 1, AlignLeft, fg)
-			}
+			Print(screen, t.bidiText(TruncateString(cell.Text, finalWidth, string(Styles.Ellipsis))), x+columnX+1, y+rowY, finalWidth, cell.Align, cell.Color)
 		}
 
 		// Draw bottom border.
@@ -813,6 +886,11 @@ ColumnLoop:
 			}
 		}
 	}
+
+	// Draw the scroll bar.
+	if scrollBar {
+		t.scrollBar.draw(screen, x+width, y, height, t.rowOffset, scrollVisible, scrollRows, true, t.backgroundColor)
+	}
 }
 
 // InputHandler returns the handler for this primitive.
@@ -0,0 +1,647 @@
+package tview
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/gdamore/tcell"
+)
+
+// tabItem represents one tab of a Tabs primitive.
+type tabItem struct {
+	Name     string    // The tab's name, used to identify it.
+	Label    string    // The text shown in the tab bar.
+	Item     Primitive // The tab's primitive.
+	Closable bool      // Whether or not the tab shows a close button.
+
+	x, width int // The tab's horizontal position and width in the tab bar the last time it was drawn. The width is 0 if it was not visible.
+}
+
+// Tabs is a container which shows a tab bar in its first row and the primitive
+// of the currently selected tab underneath. Only one tab's primitive is visible
+// at a time. If the tabs don't fit into the available width, the tab bar
+// scrolls such that the current tab is always visible. Arrows on the left and
+// right side of the tab bar indicate that there are more tabs.
+//
+// Tabs can be given a close button (see AddTab()) which the user can click to
+// close the tab. The tab bar is navigated with the following keys when it has
+// focus:
+//
+//   - Left arrow, h: Select the previous tab.
+//   - Right arrow, l: Select the next tab.
+//   - Home, g: Select the first tab.
+//   - End, G: Select the last tab.
+//   - 1-9: Select the tab with that number.
+//   - Delete, x: Close the current tab (if it has a close button).
+//   - Enter, Down arrow: Move the focus to the current tab's primitive.
+//
+// The tab bar receives focus when there are no tabs, when the user clicks on it
+// (if mouse events are enabled), or when FocusTabBar() is called. When Tabs
+// receives focus otherwise, it is passed on to the current tab's primitive. To
+// switch tabs while a tab's primitive has focus, call NextTab() or
+// PreviousTab(), e.g. from a key handler installed with
+// Application.SetInputCapture().
+type Tabs struct {
+	*Box
+
+	// The tabs.
+	tabs []*tabItem
+
+	// The index of the currently selected tab, -1 if there are no tabs.
+	currentTab int
+
+	// The index of the first tab visible in the tab bar.
+	tabOffset int
+
+	// Whether or not the tab bar should receive focus when this primitive
+	// receives focus.
+	focusTabBar bool
+
+	// We keep a reference to the function which allows us to set the focus to
+	// a newly visible tab.
+	setFocus func(p Primitive)
+
+	// The separator rune drawn between two tabs.
+	separator rune
+
+	// The rune used as the close button.
+	closeRune rune
+
+	// The background color of the tab bar.
+	tabBarBackgroundColor tcell.Color
+
+	// The text and background colors of tabs which are not selected.
+	tabTextColor, tabBackgroundColor tcell.Color
+
+	// The text and background colors of the selected tab.
+	currentTabTextColor, currentTabBackgroundColor tcell.Color
+
+	// An optional function which is called when the current tab changes.
+	changed func(index int, name string)
+
+	// An optional function which is called when the user closed a tab.
+	closed func(name string, item Primitive)
+
+	// An optional function which is called when the user leaves the tab bar.
+	// The key which was pressed is provided (tab, shift-tab, or escape).
+	done func(tcell.Key)
+}
+
+// tabsState is the state of Tabs saved with SaveState().
+type tabsState struct {
+	Current string `json:"current"`
+}
+
+// NewTabs returns a new Tabs object without any tabs.
+func NewTabs() *Tabs {
+	styles := Styles.forWidget("Tabs")
+	t := &Tabs{
+		Box:                       NewBox(),
+		currentTab:                -1,
+		separator:                 ' ',
+		closeRune:                 '×',
+		tabBarBackgroundColor:     styles.PrimitiveBackgroundColor,
+		tabTextColor:              styles.PrimaryTextColor,
+		tabBackgroundColor:        styles.ContrastBackgroundColor,
+		currentTabTextColor:       styles.InverseTextColor,
+		currentTabBackgroundColor: styles.PrimaryTextColor,
+	}
+	t.focus = t
+	return t
+}
+
+// SetChangedFunc sets a handler which is called whenever the current tab
+// changes. It receives the index and the name of the new current tab (-1 and
+// an empty string if there are no more tabs).
+func (t *Tabs) SetChangedFunc(handler func(index int, name string)) *Tabs {
+	t.changed = handler
+	return t
+}
+
+// SetClosedFunc sets a handler which is called after the user closed a tab by
+// clicking on its close button or by pressing the Delete key on the tab bar.
+// The tab has already been removed when the handler is called. It receives the
+// tab's name and primitive. This handler is not called when tabs are removed
+// with RemoveTab().
+func (t *Tabs) SetClosedFunc(handler func(name string, item Primitive)) *Tabs {
+	t.closed = handler
+	return t
+}
+
+// SetDoneFunc sets a handler which is called when the user leaves the tab bar.
+// The callback function is provided with the key that was pressed, which is
+// one of the following:
+//
+//   - KeyEscape: Leaving the tab bar with no specific direction.
+//   - KeyTab: Move to the next field.
+//   - KeyBacktab: Move to the previous field.
+func (t *Tabs) SetDoneFunc(handler func(key tcell.Key)) *Tabs {
+	t.done = handler
+	return t
+}
+
+// SetSeparator sets the rune which is drawn between two neighboring tabs. This
+// is a space character by default.
+func (t *Tabs) SetSeparator(separator rune) *Tabs {
+	t.separator = separator
+	return t
+}
+
+// SetCloseRune sets the rune which is drawn as the close button of closable
+// tabs.
+func (t *Tabs) SetCloseRune(close rune) *Tabs {
+	t.closeRune = close
+	return t
+}
+
+// SetTabBarBackgroundColor sets the background color of the tab bar, i.e. the
+// color of the space not occupied by tabs.
+func (t *Tabs) SetTabBarBackgroundColor(color tcell.Color) *Tabs {
+	t.tabBarBackgroundColor = color
+	return t
+}
+
+// SetTabTextColor sets the text color of tabs which are not selected.
+func (t *Tabs) SetTabTextColor(color tcell.Color) *Tabs {
+	t.tabTextColor = color
+	return t
+}
+
+// SetTabBackgroundColor sets the background color of tabs which are not
+// selected.
+func (t *Tabs) SetTabBackgroundColor(color tcell.Color) *Tabs {
+	t.tabBackgroundColor = color
+	return t
+}
+
+// SetCurrentTabTextColor sets the text color of the selected tab.
+func (t *Tabs) SetCurrentTabTextColor(color tcell.Color) *Tabs {
+	t.currentTabTextColor = color
+	return t
+}
+
+// SetCurrentTabBackgroundColor sets the background color of the selected tab.
+func (t *Tabs) SetCurrentTabBackgroundColor(color tcell.Color) *Tabs {
+	t.currentTabBackgroundColor = color
+	return t
+}
+
+// AddTab adds a new tab with the given name, label, and primitive at the end of
+// the tab bar. If there was previously a tab with the same name, it is replaced
+// in place. The label is the text shown in the tab bar and may contain color
+// tags. If "closable" is true, the tab shows a close button. The primitive may
+// be nil, in which case the tab is empty and the tab bar keeps the focus.
+//
+// The first tab added becomes the current tab.
+func (t *Tabs) AddTab(name, label string, item Primitive, closable bool) *Tabs {
+	barFocused, hasFocus := t.hasFocus, t.HasFocus()
+	for index, tab := range t.tabs {
+		if tab.Name == name {
+			t.tabs[index] = &tabItem{Name: name, Label: label, Item: item, Closable: closable}
+			if hasFocus && index == t.currentTab {
+				t.refocus(barFocused)
+			}
+			return t
+		}
+	}
+	t.tabs = append(t.tabs, &tabItem{Name: name, Label: label, Item: item, Closable: closable})
+	if t.currentTab < 0 {
+		t.setCurrentTab(0)
+	}
+	return t
+}
+
+// RemoveTab removes the tab with the given name. If it was the current tab, the
+// next tab (or the previous tab if it was the last one) becomes the current
+// tab.
+func (t *Tabs) RemoveTab(name string) *Tabs {
+	for index, tab := range t.tabs {
+		if tab.Name == name {
+			t.removeTab(index)
+			break
+		}
+	}
+	return t
+}
+
+// removeTab removes the tab at the given index and updates the current tab.
+func (t *Tabs) removeTab(index int) {
+	barFocused, hasFocus := t.hasFocus, t.HasFocus()
+	t.tabs = append(t.tabs[:index], t.tabs[index+1:]...)
+	newTab := t.currentTab
+	if index < t.currentTab || newTab >= len(t.tabs) {
+		newTab--
+	}
+	if index == t.currentTab || newTab != t.currentTab {
+		t.currentTab = -2 // Force the "changed" callback.
+		t.setCurrentTab(newTab)
+	}
+	if hasFocus {
+		t.refocus(barFocused)
+	}
+}
+
+// HasTab returns true if a tab with the given name exists.
+func (t *Tabs) HasTab(name string) bool {
+	for _, tab := range t.tabs {
+		if tab.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// GetTabCount returns the number of tabs.
+func (t *Tabs) GetTabCount() int {
+	return len(t.tabs)
+}
+
+// SetTabLabel changes the label of the tab with the given name.
+func (t *Tabs) SetTabLabel(name, label string) *Tabs {
+	for _, tab := range t.tabs {
+		if tab.Name == name {
+			tab.Label = label
+			break
+		}
+	}
+	return t
+}
+
+// SwitchToTab makes the tab with the given name the current tab. If the Tabs
+// primitive has focus, the focus is moved to the new tab's primitive.
+func (t *Tabs) SwitchToTab(name string) *Tabs {
+	for index, tab := range t.tabs {
+		if tab.Name == name {
+			t.switchToTab(index)
+			break
+		}
+	}
+	return t
+}
+
+// NextTab makes the tab following the current tab the current tab, wrapping
+// around at the end.
+func (t *Tabs) NextTab() *Tabs {
+	if len(t.tabs) > 0 {
+		t.switchToTab((t.currentTab + 1) % len(t.tabs))
+	}
+	return t
+}
+
+// PreviousTab makes the tab preceding the current tab the current tab, wrapping
+// around at the beginning.
+func (t *Tabs) PreviousTab() *Tabs {
+	if len(t.tabs) > 0 {
+		t.switchToTab((t.currentTab - 1 + len(t.tabs)) % len(t.tabs))
+	}
+	return t
+}
+
+// GetCurrentTab returns the index and the name of the current tab. If there are
+// no tabs, -1 and an empty string are returned.
+func (t *Tabs) GetCurrentTab() (index int, name string) {
+	if t.currentTab < 0 {
+		return -1, ""
+	}
+	return t.currentTab, t.tabs[t.currentTab].Name
+}
+
+// FocusTabBar causes the tab bar to receive focus the next time this primitive
+// receives focus. If it already has focus, the tab bar receives focus
+// immediately.
+func (t *Tabs) FocusTabBar() *Tabs {
+	t.focusTabBar = true
+	if t.setFocus != nil && t.HasFocus() {
+		t.setFocus(t)
+	}
+	return t
+}
+
+// switchToTab makes the tab with the given index the current tab and moves the
+// focus along if we have it.
+func (t *Tabs) switchToTab(index int) {
+	if index == t.currentTab {
+		return
+	}
+	barFocused, hasFocus := t.hasFocus, t.HasFocus()
+	t.setCurrentTab(index)
+	if hasFocus {
+		t.refocus(barFocused)
+	}
+}
+
+// refocus passes the focus on to the tab bar (if "barFocused" is true) or to
+// the current tab's primitive. It is called when the current tab has changed
+// while this primitive had focus.
+func (t *Tabs) refocus(barFocused bool) {
+	if t.setFocus == nil {
+		return
+	}
+	t.focusTabBar = barFocused
+	t.setFocus(t)
+}
+
+// setCurrentTab sets the current tab's index and invokes the "changed"
+// callback.
+func (t *Tabs) setCurrentTab(index int) {
+	if index == t.currentTab {
+		return
+	}
+	t.currentTab = index
+	if t.changed != nil {
+		t.changed(t.GetCurrentTab())
+	}
+}
+
+// closeTab removes the tab at the given index after the user requested it and
+// notifies the "closed" handler.
+func (t *Tabs) closeTab(index int) {
+	tab := t.tabs[index]
+	t.removeTab(index)
+	if t.closed != nil {
+		t.closed(tab.Name, tab.Item)
+	}
+}
+
+// tabWidth returns the screen width of the given tab in the tab bar.
+func (t *Tabs) tabWidth(tab *tabItem) int {
+	width := StringWidth(tab.Label) + 2
+	if tab.Closable {
+		width += 2
+	}
+	return width
+}
+
+// GetChildren returns the primitives of all tabs.
+func (t *Tabs) GetChildren() []Primitive {
+	children := make([]Primitive, 0, len(t.tabs))
+	for _, tab := range t.tabs {
+		if tab.Item != nil {
+			children = append(children, tab.Item)
+		}
+	}
+	return children
+}
+
+// SaveState returns the name of the current tab as JSON. See SaveLayout() for
+// details.
+func (t *Tabs) SaveState() ([]byte, error) {
+	_, name := t.GetCurrentTab()
+	return json.Marshal(tabsState{Current: name})
+}
+
+// RestoreState restores a state returned by SaveState().
+func (t *Tabs) RestoreState(data []byte) error {
+	var state tabsState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	t.SwitchToTab(state.Current)
+	return nil
+}
+
+// applyTheme is called when the application's theme changes.
+func (t *Tabs) applyTheme(from, to *Theme) {
+	t.Box.applyTheme(from, to)
+	themeColor(&t.tabBarBackgroundColor, from.PrimitiveBackgroundColor, to.PrimitiveBackgroundColor)
+	themeColor(&t.tabTextColor, from.PrimaryTextColor, to.PrimaryTextColor)
+	themeColor(&t.tabBackgroundColor, from.ContrastBackgroundColor, to.ContrastBackgroundColor)
+	themeColor(&t.currentTabTextColor, from.InverseTextColor, to.InverseTextColor)
+	themeColor(&t.currentTabBackgroundColor, from.PrimaryTextColor, to.PrimaryTextColor)
+}
+
+// Draw draws this primitive onto the screen.
+func (t *Tabs) Draw(screen tcell.Screen) {
+	defer t.applyStyle(&t.tabTextColor)()
+	t.Box.Draw(screen)
+
+	x, y, width, height := t.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	// Draw the tab bar background.
+	barStyle := tcell.StyleDefault.Background(t.tabBarBackgroundColor)
+	for index := 0; index < width; index++ {
+		screen.SetContent(x+index, y, ' ', nil, barStyle)
+	}
+
+	// Make sure the current tab is visible.
+	if t.tabOffset > t.currentTab {
+		t.tabOffset = t.currentTab
+	}
+	if t.tabOffset < 0 {
+		t.tabOffset = 0
+	}
+	for t.tabOffset < t.currentTab {
+		needed := 0
+		if t.tabOffset > 0 {
+			needed++ // Left arrow.
+		}
+		for index := t.tabOffset; index <= t.currentTab; index++ {
+			needed += t.tabWidth(t.tabs[index]) + 1
+		}
+		needed-- // No separator after the last tab.
+		if t.currentTab < len(t.tabs)-1 {
+			needed += 2 // Separator and right arrow.
+		}
+		if needed <= width {
+			break
+		}
+		t.tabOffset++
+	}
+
+	// Draw the tabs.
+	for _, tab := range t.tabs {
+		tab.width = 0
+	}
+	tabX := x
+	if t.tabOffset > 0 {
+		screen.SetContent(tabX, y, '◀', nil, barStyle.Foreground(t.tabTextColor))
+		tabX++
+	}
+	rightLimit := x + width
+	for index := t.tabOffset; index < len(t.tabs); index++ {
+		tab := t.tabs[index]
+		tabWidth := t.tabWidth(tab)
+		limit := rightLimit
+		if index < len(t.tabs)-1 {
+			limit-- // Leave space for the right arrow.
+		}
+		if tabX+tabWidth > limit && index > t.tabOffset {
+			screen.SetContent(rightLimit-1, y, '▶', nil, barStyle.Foreground(t.tabTextColor))
+			break
+		}
+		if tabWidth > rightLimit-tabX {
+			tabWidth = rightLimit - tabX
+		}
+
+		// Draw the tab's background and label.
+		textColor, backgroundColor := t.tabTextColor, t.tabBackgroundColor
+		if index == t.currentTab {
+			textColor, backgroundColor = t.currentTabTextColor, t.currentTabBackgroundColor
+		}
+		tabStyle := tcell.StyleDefault.Background(backgroundColor).Foreground(textColor)
+		if index == t.currentTab && t.hasFocus {
+			tabStyle = tabStyle.Underline(true)
+		}
+		for offset := 0; offset < tabWidth; offset++ {
+			screen.SetContent(tabX+offset, y, ' ', nil, tabStyle)
+		}
+		labelWidth := tabWidth - 2
+		if tab.Closable {
+			labelWidth -= 2
+		}
+		Print(screen, tab.Label, tabX+1, y, labelWidth, AlignLeft, textColor)
+		if tab.Closable && tabWidth >= 4 {
+			screen.SetContent(tabX+tabWidth-2, y, t.closeRune, nil, tabStyle)
+		}
+		tab.x, tab.width = tabX, tabWidth
+		tabX += tabWidth
+
+		// Draw the separator.
+		if index < len(t.tabs)-1 && tabX < rightLimit {
+			screen.SetContent(tabX, y, t.separator, nil, barStyle.Foreground(t.tabTextColor))
+			tabX++
+		}
+	}
+
+	// Draw the current tab's primitive.
+	if t.currentTab >= 0 && height > 1 && t.tabs[t.currentTab].Item != nil {
+		item := t.tabs[t.currentTab].Item
+		item.SetRect(x, y+1, width, height-1)
+		item.Draw(screen)
+	}
+}
+
+// Focus is called when this primitive receives focus.
+func (t *Tabs) Focus(delegate func(p Primitive)) {
+	t.setFocus = delegate
+	if t.currentTab < 0 || t.focusTabBar || t.tabs[t.currentTab].Item == nil {
+		t.focusTabBar = false
+		t.Box.Focus(delegate)
+		return
+	}
+	delegate(t.tabs[t.currentTab].Item)
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (t *Tabs) HasFocus() bool {
+	if t.hasFocus {
+		return true
+	}
+	for _, tab := range t.tabs {
+		if tab.Item != nil && tab.Item.GetFocusable().HasFocus() {
+			return true
+		}
+	}
+	return false
+}
+
+// InputHandler returns the handler for this primitive.
+func (t *Tabs) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return t.wrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		switch key := event.Key(); key {
+		case tcell.KeyLeft:
+			t.PreviousTab()
+		case tcell.KeyRight:
+			t.NextTab()
+		case tcell.KeyHome:
+			if len(t.tabs) > 0 {
+				t.switchToTab(0)
+			}
+		case tcell.KeyEnd:
+			if len(t.tabs) > 0 {
+				t.switchToTab(len(t.tabs) - 1)
+			}
+		case tcell.KeyDelete:
+			if t.currentTab >= 0 && t.tabs[t.currentTab].Closable {
+				t.closeTab(t.currentTab)
+			}
+		case tcell.KeyEnter, tcell.KeyDown:
+			if t.currentTab >= 0 && t.tabs[t.currentTab].Item != nil {
+				setFocus(t.tabs[t.currentTab].Item)
+			}
+		case tcell.KeyRune:
+			switch ch := event.Rune(); ch {
+			case 'h':
+				t.PreviousTab()
+			case 'l':
+				t.NextTab()
+			case 'g':
+				if len(t.tabs) > 0 {
+					t.switchToTab(0)
+				}
+			case 'G':
+				if len(t.tabs) > 0 {
+					t.switchToTab(len(t.tabs) - 1)
+				}
+			case 'x':
+				if t.currentTab >= 0 && t.tabs[t.currentTab].Closable {
+					t.closeTab(t.currentTab)
+				}
+			default:
+				if index, err := strconv.Atoi(string(ch)); err == nil && index >= 1 && index <= len(t.tabs) {
+					t.switchToTab(index - 1)
+				}
+			}
+		case tcell.KeyEscape, tcell.KeyTab, tcell.KeyBacktab:
+			if t.done != nil {
+				t.done(key)
+			}
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (t *Tabs) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return t.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+		if !t.InRect(x, y) {
+			return false, nil
+		}
+
+		// Events outside the tab bar go to the current tab's primitive.
+		rectX, rectY, width, _ := t.GetInnerRect()
+		if y != rectY || x < rectX || x >= rectX+width {
+			if t.currentTab >= 0 {
+				if handler := mouseHandler(t.tabs[t.currentTab].Item); handler != nil {
+					return handler(action, event, setFocus)
+				}
+			}
+			return false, nil
+		}
+
+		switch action {
+		case MouseLeftClick:
+			if t.tabOffset > 0 && x == rectX {
+				t.tabOffset-- // Left arrow.
+				break
+			}
+			for index, tab := range t.tabs {
+				if tab.width == 0 || x < tab.x || x >= tab.x+tab.width {
+					continue
+				}
+				if tab.Closable && x == tab.x+tab.width-2 {
+					t.closeTab(index)
+				} else {
+					t.switchToTab(index)
+					t.focusTabBar = true
+					setFocus(t)
+				}
+				return true, nil
+			}
+			if x == rectX+width-1 && t.tabOffset < len(t.tabs)-1 {
+				t.tabOffset++ // Right arrow.
+				if t.tabOffset > t.currentTab {
+					t.switchToTab(t.tabOffset)
+				}
+			}
+		case MouseScrollUp, MouseScrollLeft:
+			t.PreviousTab()
+		case MouseScrollDown, MouseScrollRight:
+			t.NextTab()
+		}
+		return true, nil
+	})
+}
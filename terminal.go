@@ -0,0 +1,317 @@
+package tview
+
+import (
+	"os"
+	"os/exec"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell"
+)
+
+// terminalKeys maps special keys to the sequences sent to the program. The
+// second entry, if not empty, is sent instead when the program requested
+// application cursor keys.
+var terminalKeys = map[tcell.Key][2]string{
+	tcell.KeyUp:      {"\x1b[A", "\x1bOA"},
+	tcell.KeyDown:    {"\x1b[B", "\x1bOB"},
+	tcell.KeyRight:   {"\x1b[C", "\x1bOC"},
+	tcell.KeyLeft:    {"\x1b[D", "\x1bOD"},
+	tcell.KeyHome:    {"\x1b[H", "\x1bOH"},
+	tcell.KeyEnd:     {"\x1b[F", "\x1bOF"},
+	tcell.KeyPgUp:    {"\x1b[5~"},
+	tcell.KeyPgDn:    {"\x1b[6~"},
+	tcell.KeyInsert:  {"\x1b[2~"},
+	tcell.KeyDelete:  {"\x1b[3~"},
+	tcell.KeyBacktab: {"\x1b[Z"},
+	tcell.KeyF1:      {"\x1bOP"},
+	tcell.KeyF2:      {"\x1bOQ"},
+	tcell.KeyF3:      {"\x1bOR"},
+	tcell.KeyF4:      {"\x1bOS"},
+	tcell.KeyF5:      {"\x1b[15~"},
+	tcell.KeyF6:      {"\x1b[17~"},
+	tcell.KeyF7:      {"\x1b[18~"},
+	tcell.KeyF8:      {"\x1b[19~"},
+	tcell.KeyF9:      {"\x1b[20~"},
+	tcell.KeyF10:     {"\x1b[21~"},
+	tcell.KeyF11:     {"\x1b[23~"},
+	tcell.KeyF12:     {"\x1b[24~"},
+}
+
+// Terminal is a primitive which runs a program in a pseudo terminal and shows
+// its output, similar to a terminal emulator. This allows embedding shells or
+// other command line programs in a tview application.
+//
+// When the terminal has focus, all key events are forwarded to the program.
+// Use Application.SetInputCapture() to intercept keys which should move the
+// focus elsewhere. The size of the pseudo terminal follows the size of the
+// primitive's inner rectangle.
+//
+// The program's output is interpreted by a built-in emulator supporting the
+// escape sequences of an xterm-compatible terminal commonly used by full-screen
+// programs. The TERM environment variable is set to "xterm-256color" unless
+// the command specifies its own environment. Since output arrives
+// asynchronously, you will want to provide a "changed" handler which redraws
+// the application (see SetChangedFunc()).
+//
+// Pseudo terminals are currently only supported on Linux. On other platforms,
+// Start() returns an error.
+type Terminal struct {
+	*Box
+	sync.Mutex
+
+	// The emulated screen.
+	screen *vtScreen
+
+	// The master side of the pseudo terminal, nil if no program is running.
+	pty *os.File
+
+	// The running program.
+	cmd *exec.Cmd
+
+	// The color of text which has no color of its own.
+	textColor tcell.Color
+
+	// An optional function which is called when there is new output.
+	changed func()
+
+	// An optional function which is called when the program has exited.
+	exited func(err error)
+}
+
+// NewTerminal returns a new terminal which doesn't run a program yet.
+func NewTerminal() *Terminal {
+	styles := Styles.forWidget("Terminal")
+	return &Terminal{
+		Box:       NewBox(),
+		screen:    newVTScreen(80, 24),
+		textColor: styles.PrimaryTextColor,
+	}
+}
+
+// SetTextColor sets the color of text which has no color of its own.
+func (t *Terminal) SetTextColor(color tcell.Color) *Terminal {
+	t.textColor = color
+	return t
+}
+
+// SetChangedFunc sets a handler function which is called when the program
+// produced new output. This handler is called from a different goroutine. It
+// will usually call Application.Draw().
+func (t *Terminal) SetChangedFunc(handler func()) *Terminal {
+	t.Lock()
+	defer t.Unlock()
+	t.changed = handler
+	return t
+}
+
+// SetExitedFunc sets a handler function which is called when the program has
+// exited. It receives the error returned by exec.Cmd.Wait(). This handler is
+// called from a different goroutine.
+func (t *Terminal) SetExitedFunc(handler func(err error)) *Terminal {
+	t.Lock()
+	defer t.Unlock()
+	t.exited = handler
+	return t
+}
+
+// Start runs the given command in a new pseudo terminal. The command must not
+// have been started yet. Its standard input, output, and error are connected
+// to the pseudo terminal. If another program is still running, it is killed
+// first.
+func (t *Terminal) Start(cmd *exec.Cmd) error {
+	t.Close()
+
+	pty, tty, err := openPTY()
+	if err != nil {
+		return err
+	}
+	defer tty.Close()
+
+	t.Lock()
+	_, _, width, height := t.GetInnerRect()
+	if width > 0 && height > 0 {
+		t.screen = newVTScreen(width, height)
+	} else {
+		t.screen = newVTScreen(t.screen.width, t.screen.height)
+	}
+	setPTYSize(pty, t.screen.width, t.screen.height)
+	t.Unlock()
+
+	if cmd.Env == nil {
+		cmd.Env = append(os.Environ(), "TERM=xterm-256color")
+	}
+	attachPTY(cmd, tty)
+	if err := cmd.Start(); err != nil {
+		pty.Close()
+		return err
+	}
+
+	t.Lock()
+	t.pty, t.cmd = pty, cmd
+	t.Unlock()
+	go t.read(pty, cmd)
+	return nil
+}
+
+// read processes the program's output until it exits.
+func (t *Terminal) read(pty *os.File, cmd *exec.Cmd) {
+	buffer := make([]byte, 4096)
+	for {
+		n, err := pty.Read(buffer)
+		if n > 0 {
+			t.Lock()
+			if t.pty == pty {
+				t.screen.write(buffer[:n])
+				if len(t.screen.responses) > 0 {
+					pty.Write(t.screen.responses)
+					t.screen.responses = t.screen.responses[:0]
+				}
+			}
+			changed := t.changed
+			t.Unlock()
+			if changed != nil {
+				changed()
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	err := cmd.Wait()
+	t.Lock()
+	if t.pty == pty {
+		t.pty, t.cmd = nil, nil
+		pty.Close()
+	}
+	exited := t.exited
+	t.Unlock()
+	if exited != nil {
+		exited(err)
+	}
+}
+
+// Close kills the running program, if any. The "exited" handler is still
+// called.
+func (t *Terminal) Close() *Terminal {
+	t.Lock()
+	pty, cmd := t.pty, t.cmd
+	t.pty, t.cmd = nil, nil
+	t.Unlock()
+	if cmd != nil && cmd.Process != nil {
+		cmd.Process.Kill()
+	}
+	if pty != nil {
+		pty.Close()
+	}
+	return t
+}
+
+// IsRunning returns true if a program is currently running.
+func (t *Terminal) IsRunning() bool {
+	t.Lock()
+	defer t.Unlock()
+	return t.pty != nil
+}
+
+// Write sends the given data to the program as if it had been typed. It
+// returns an error if no program is running.
+func (t *Terminal) Write(data []byte) (int, error) {
+	t.Lock()
+	pty := t.pty
+	t.Unlock()
+	if pty == nil {
+		return 0, os.ErrClosed
+	}
+	return pty.Write(data)
+}
+
+// applyTheme is called when the application's theme changes.
+func (t *Terminal) applyTheme(from, to *Theme) {
+	t.Box.applyTheme(from, to)
+	themeColor(&t.textColor, from.PrimaryTextColor, to.PrimaryTextColor)
+}
+
+// Draw draws this primitive onto the screen.
+func (t *Terminal) Draw(screen tcell.Screen) {
+	defer t.applyStyle(&t.textColor)()
+	t.Box.Draw(screen)
+	t.Lock()
+	defer t.Unlock()
+
+	x, y, width, height := t.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	// Follow size changes.
+	if width != t.screen.width || height != t.screen.height {
+		t.screen.resize(width, height)
+		if t.pty != nil {
+			setPTYSize(t.pty, width, height)
+		}
+	}
+
+	// Draw the cells.
+	for row := 0; row < height; row++ {
+		for column, cell := range t.screen.cells[row] {
+			if cell.ch == 0 {
+				continue // Covered by a wide character.
+			}
+			fg, bg := cell.fg, cell.bg
+			if fg == tcell.ColorDefault {
+				fg = t.textColor
+			}
+			if bg == tcell.ColorDefault {
+				bg = t.backgroundColor
+			}
+			style := tcell.StyleDefault.Foreground(fg).Background(bg).
+				Bold(cell.attributes&tcell.AttrBold != 0).
+				Dim(cell.attributes&tcell.AttrDim != 0).
+				Underline(cell.attributes&tcell.AttrUnderline != 0).
+				Blink(cell.attributes&tcell.AttrBlink != 0).
+				Reverse(cell.attributes&tcell.AttrReverse != 0)
+			screen.SetContent(x+column, y+row, cell.ch, nil, style)
+		}
+	}
+
+	// Show the cursor.
+	if t.hasFocus && t.screen.cursorVisible {
+		screen.ShowCursor(x+t.screen.cursorX, y+t.screen.cursorY)
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (t *Terminal) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return t.wrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		t.Lock()
+		pty, applicationCursorKeys := t.pty, t.screen.applicationCursorKeys
+		t.Unlock()
+		if pty == nil {
+			return
+		}
+
+		// Determine what to send.
+		var data []byte
+		key := event.Key()
+		if key == tcell.KeyRune {
+			var buffer [utf8.UTFMax]byte
+			data = buffer[:utf8.EncodeRune(buffer[:], event.Rune())]
+		} else if sequences, ok := terminalKeys[key]; ok {
+			if applicationCursorKeys && sequences[1] != "" {
+				data = []byte(sequences[1])
+			} else {
+				data = []byte(sequences[0])
+			}
+		} else if key < 0x80 {
+			data = []byte{byte(key)} // Control characters, Enter, Tab, Backspace, Escape.
+		} else {
+			return
+		}
+		if event.Modifiers()&tcell.ModAlt != 0 {
+			data = append([]byte{0x1b}, data...)
+		}
+		pty.Write(data)
+	})
+}
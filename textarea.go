@@ -0,0 +1,782 @@
+package tview
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/gdamore/tcell"
+)
+
+// textAreaClipboard is the clipboard shared by all text areas which do not
+// have their own clipboard functions.
+var textAreaClipboard string
+
+// textAreaRow is one row of text as displayed on screen, given as rune offsets
+// into the text. The end offset excludes any trailing newline.
+type textAreaRow struct {
+	start, end int
+}
+
+// textAreaState is a snapshot of a text area's text and cursor, used for
+// undo and redo.
+type textAreaState struct {
+	text   []rune
+	cursor int
+}
+
+// Kinds of edits. Consecutive edits of the same kind are undone together.
+const (
+	textAreaEditOther = iota
+	textAreaEditInsert
+	textAreaEditDelete
+)
+
+// TextArea is a multi-line text editor. Long lines are wrapped at word
+// boundaries (this can be switched off, see SetWrap(), in which case the text
+// scrolls horizontally). Tab characters are replaced with TabSize spaces.
+//
+// The following keys are available:
+//
+//   - Arrow keys, Home, End, Page up, Page down: Move the cursor. Home and End
+//     move to the beginning/end of the row.
+//   - Ctrl-Left arrow, Ctrl-Right arrow: Move the cursor to the previous/next
+//     word.
+//   - Shift plus any of the above: Extend the selection.
+//   - Ctrl-L: Select all text.
+//   - Ctrl-X, Ctrl-Q, Ctrl-V: Cut, copy, paste.
+//   - Ctrl-Z, Ctrl-Y: Undo, redo.
+//   - Ctrl-K: Delete to the end of the line.
+//   - Ctrl-U: Delete to the beginning of the line.
+//   - Ctrl-W: Delete the word before the cursor.
+//   - Escape, Tab, Backtab: Finish (see SetDoneFunc()).
+//
+// Note that Ctrl-C is not used for copying as it stops the application.
+//
+// With the mouse, clicking moves the cursor, dragging selects text, and
+// double-clicking selects a word.
+//
+// Cut, copy, and paste use a clipboard shared by all text areas. Use
+// SetClipboard() to connect a text area to the system clipboard.
+type TextArea struct {
+	*Box
+
+	// The text.
+	text []rune
+
+	// The rune offset of the cursor.
+	cursor int
+
+	// The rune offset of the other end of the selection. If it equals the
+	// cursor or is negative, there is no selection.
+	anchor int
+
+	// Whether or not long lines are wrapped.
+	wrap bool
+
+	// The rows as displayed on screen. nil if they need to be recalculated.
+	rows []textAreaRow
+
+	// The width for which the rows were calculated.
+	rowsWidth int
+
+	// The index of the first visible row.
+	rowOffset int
+
+	// The horizontal scroll offset when wrapping is switched off.
+	columnOffset int
+
+	// The screen column the cursor moves to when moving up or down. -1 if the
+	// cursor's current column is to be used.
+	preferredColumn int
+
+	// Snapshots for undo and redo.
+	undoStack, redoStack []textAreaState
+
+	// The kind of the last edit.
+	lastEdit int
+
+	// The text shown when the text area is empty.
+	placeholder string
+
+	// The text color.
+	textColor tcell.Color
+
+	// The color of the placeholder text.
+	placeholderColor tcell.Color
+
+	// The text and background colors of selected text.
+	selectedTextColor, selectedBackgroundColor tcell.Color
+
+	// Set to true while the user is selecting text with the mouse.
+	dragging bool
+
+	// Optional functions to copy text to and paste text from a clipboard.
+	copyFunc  func(text string)
+	pasteFunc func() string
+
+	// An optional function which is called when the text has changed.
+	changed func()
+
+	// An optional function which is called when text was pasted.
+	pasted func(text string)
+
+	// An optional function which is called when the user presses Escape, Tab,
+	// or Backtab.
+	done func(key tcell.Key)
+}
+
+// NewTextArea returns a new, empty text area.
+func NewTextArea() *TextArea {
+	styles := Styles.forWidget("TextArea")
+	return &TextArea{
+		Box:                     NewBox(),
+		anchor:                  -1,
+		wrap:                    true,
+		preferredColumn:         -1,
+		textColor:               styles.PrimaryTextColor,
+		placeholderColor:        styles.TertiaryTextColor,
+		selectedTextColor:       styles.PrimitiveBackgroundColor,
+		selectedBackgroundColor: styles.PrimaryTextColor,
+	}
+}
+
+// SetText replaces the text area's text. The cursor is moved to the end of the
+// text. If "undoable" is true, the change can be undone by the user.
+func (t *TextArea) SetText(text string, undoable bool) *TextArea {
+	if undoable {
+		t.pushUndo(textAreaEditOther)
+	} else {
+		t.undoStack, t.redoStack = nil, nil
+	}
+	t.text = []rune(strings.Replace(text, "\t", strings.Repeat(" ", TabSize), -1))
+	t.cursor = len(t.text)
+	t.anchor = -1
+	t.rows = nil
+	return t
+}
+
+// GetText returns the text area's text.
+func (t *TextArea) GetText() string {
+	return string(t.text)
+}
+
+// GetSelection returns the selected text and its start and end rune offsets.
+// If there is no selection, an empty string is returned and start and end
+// are both the cursor position.
+func (t *TextArea) GetSelection() (text string, start, end int) {
+	start, end = t.selection()
+	return string(t.text[start:end]), start, end
+}
+
+// Select selects the text between the given rune offsets. The cursor is placed
+// at "end". If start and end are equal, the cursor is moved and nothing is
+// selected.
+func (t *TextArea) Select(start, end int) *TextArea {
+	t.anchor = t.clamp(start)
+	t.cursor = t.clamp(end)
+	t.preferredColumn = -1
+	return t
+}
+
+// GetCursor returns the rune offset of the cursor.
+func (t *TextArea) GetCursor() int {
+	return t.cursor
+}
+
+// SetWrap sets whether or not long lines are wrapped. If not, the text
+// scrolls horizontally.
+func (t *TextArea) SetWrap(wrap bool) *TextArea {
+	t.wrap = wrap
+	t.rows = nil
+	t.columnOffset = 0
+	return t
+}
+
+// SetPlaceholder sets the text shown when the text area is empty.
+func (t *TextArea) SetPlaceholder(placeholder string) *TextArea {
+	t.placeholder = placeholder
+	return t
+}
+
+// SetTextColor sets the color of the text.
+func (t *TextArea) SetTextColor(color tcell.Color) *TextArea {
+	t.textColor = color
+	return t
+}
+
+// SetPlaceholderColor sets the color of the placeholder text.
+func (t *TextArea) SetPlaceholderColor(color tcell.Color) *TextArea {
+	t.placeholderColor = color
+	return t
+}
+
+// SetSelectedTextColor sets the text color of selected text.
+func (t *TextArea) SetSelectedTextColor(color tcell.Color) *TextArea {
+	t.selectedTextColor = color
+	return t
+}
+
+// SetSelectedBackgroundColor sets the background color of selected text.
+func (t *TextArea) SetSelectedBackgroundColor(color tcell.Color) *TextArea {
+	t.selectedBackgroundColor = color
+	return t
+}
+
+// SetClipboard sets the functions used to copy text to and paste text from a
+// clipboard, e.g. the system clipboard. If either is nil, a clipboard shared
+// by all text areas is used instead.
+func (t *TextArea) SetClipboard(copyFunc func(text string), pasteFunc func() string) *TextArea {
+	t.copyFunc, t.pasteFunc = copyFunc, pasteFunc
+	return t
+}
+
+// SetChangedFunc sets a handler which is called whenever the text has been
+// changed by the user.
+func (t *TextArea) SetChangedFunc(handler func()) *TextArea {
+	t.changed = handler
+	return t
+}
+
+// SetPastedFunc sets a handler which is called when the user pasted text into
+// the text area from the terminal, after the text was inserted and the
+// "changed" handler was called. It receives the pasted text. Pasted text is
+// only recognized in bracketed paste mode (see
+// Application.EnableBracketedPaste()).
+func (t *TextArea) SetPastedFunc(handler func(text string)) *TextArea {
+	t.pasted = handler
+	return t
+}
+
+// SetDoneFunc sets a handler which is called when the user presses the
+// Escape, Tab, or Backtab key.
+func (t *TextArea) SetDoneFunc(handler func(key tcell.Key)) *TextArea {
+	t.done = handler
+	return t
+}
+
+// clamp restricts the given offset to the text.
+func (t *TextArea) clamp(offset int) int {
+	if offset < 0 {
+		return 0
+	}
+	if offset > len(t.text) {
+		return len(t.text)
+	}
+	return offset
+}
+
+// selection returns the start and end offsets of the selection. Both are the
+// cursor position if there is no selection.
+func (t *TextArea) selection() (start, end int) {
+	if t.anchor < 0 || t.anchor == t.cursor {
+		return t.cursor, t.cursor
+	}
+	if t.anchor < t.cursor {
+		return t.anchor, t.cursor
+	}
+	return t.cursor, t.anchor
+}
+
+// pushUndo saves the current state for undo unless the edit continues a
+// series of edits of the same kind.
+func (t *TextArea) pushUndo(kind int) {
+	if kind != textAreaEditOther && kind == t.lastEdit && len(t.undoStack) > 0 {
+		return
+	}
+	t.lastEdit = kind
+	text := make([]rune, len(t.text))
+	copy(text, t.text)
+	t.undoStack = append(t.undoStack, textAreaState{text: text, cursor: t.cursor})
+	if len(t.undoStack) > 100 {
+		t.undoStack = t.undoStack[1:]
+	}
+	t.redoStack = nil
+}
+
+// restore swaps the current state with the last state of "from", pushing the
+// current state onto "to".
+func (t *TextArea) restore(from, to *[]textAreaState) {
+	if len(*from) == 0 {
+		return
+	}
+	state := (*from)[len(*from)-1]
+	*from = (*from)[:len(*from)-1]
+	*to = append(*to, textAreaState{text: t.text, cursor: t.cursor})
+	t.text, t.cursor = state.text, state.cursor
+	t.anchor = -1
+	t.lastEdit = textAreaEditOther
+	t.rows = nil
+	if t.changed != nil {
+		t.changed()
+	}
+}
+
+// replace replaces the selected text (or inserts at the cursor if there is no
+// selection) with the given text.
+func (t *TextArea) replace(text string, kind int) {
+	start, end := t.selection()
+	if start == end && text == "" {
+		return
+	}
+	if start != end {
+		kind = textAreaEditOther
+	}
+	t.pushUndo(kind)
+	insert := []rune(strings.Replace(text, "\t", strings.Repeat(" ", TabSize), -1))
+	newText := make([]rune, 0, len(t.text)-(end-start)+len(insert))
+	newText = append(newText, t.text[:start]...)
+	newText = append(newText, insert...)
+	newText = append(newText, t.text[end:]...)
+	t.text = newText
+	t.cursor = start + len(insert)
+	t.anchor = -1
+	t.preferredColumn = -1
+	t.rows = nil
+	if t.changed != nil {
+		t.changed()
+	}
+}
+
+// deleteRange deletes the text between the given offsets.
+func (t *TextArea) deleteRange(start, end int) {
+	start, end = t.clamp(start), t.clamp(end)
+	if start > end {
+		start, end = end, start
+	}
+	t.anchor, t.cursor = start, end
+	t.replace("", textAreaEditDelete)
+}
+
+// copySelection copies the selected text to the clipboard.
+func (t *TextArea) copySelection() {
+	text, start, end := t.GetSelection()
+	if start == end {
+		return
+	}
+	if t.copyFunc != nil {
+		t.copyFunc(text)
+	} else {
+		textAreaClipboard = text
+	}
+}
+
+// paste inserts the clipboard's text at the cursor.
+func (t *TextArea) paste() {
+	text := textAreaClipboard
+	if t.pasteFunc != nil {
+		text = t.pasteFunc()
+	}
+	t.replace(text, textAreaEditOther)
+}
+
+// moveTo moves the cursor to the given offset, extending the selection if
+// "selecting" is true and removing it otherwise.
+func (t *TextArea) moveTo(offset int, selecting bool) {
+	if selecting {
+		if t.anchor < 0 {
+			t.anchor = t.cursor
+		}
+	} else {
+		t.anchor = -1
+	}
+	t.cursor = t.clamp(offset)
+	t.lastEdit = textAreaEditOther
+}
+
+// lineStart returns the offset of the beginning of the line containing the
+// given offset.
+func (t *TextArea) lineStart(offset int) int {
+	for offset > 0 && t.text[offset-1] != '\n' {
+		offset--
+	}
+	return offset
+}
+
+// lineEnd returns the offset of the end of the line (before the newline)
+// containing the given offset.
+func (t *TextArea) lineEnd(offset int) int {
+	for offset < len(t.text) && t.text[offset] != '\n' {
+		offset++
+	}
+	return offset
+}
+
+// isWordRune returns whether or not the given rune is part of a word.
+func isWordRune(ch rune) bool {
+	return unicode.IsLetter(ch) || unicode.IsDigit(ch) || ch == '_'
+}
+
+// wordLeft returns the offset of the beginning of the word before the given
+// offset.
+func (t *TextArea) wordLeft(offset int) int {
+	for offset > 0 && !isWordRune(t.text[offset-1]) {
+		offset--
+	}
+	for offset > 0 && isWordRune(t.text[offset-1]) {
+		offset--
+	}
+	return offset
+}
+
+// wordRight returns the offset of the end of the word after the given offset.
+func (t *TextArea) wordRight(offset int) int {
+	for offset < len(t.text) && !isWordRune(t.text[offset]) {
+		offset++
+	}
+	for offset < len(t.text) && isWordRune(t.text[offset]) {
+		offset++
+	}
+	return offset
+}
+
+// layout splits the text into rows for the given width.
+func (t *TextArea) layout(width int) {
+	if t.rows != nil && t.rowsWidth == width {
+		return
+	}
+	t.rows = t.rows[:0]
+	t.rowsWidth = width
+	start := 0
+	for {
+		end := t.lineEnd(start)
+		if !t.wrap || width <= 0 {
+			t.rows = append(t.rows, textAreaRow{start: start, end: end})
+		} else {
+			// Wrap this line.
+			rowStart, rowWidth, lastSpace := start, 0, -1
+			for offset := start; offset < end; offset++ {
+				ch := t.text[offset]
+				chWidth := RuneWidth(ch)
+				if rowWidth+chWidth > width && offset > rowStart {
+					breakAt := offset
+					if lastSpace >= rowStart {
+						breakAt = lastSpace + 1
+					}
+					t.rows = append(t.rows, textAreaRow{start: rowStart, end: breakAt})
+					rowStart, lastSpace = breakAt, -1
+					rowWidth = 0
+					for _, r := range t.text[rowStart:offset] {
+						rowWidth += RuneWidth(r)
+					}
+				}
+				if unicode.IsSpace(ch) {
+					lastSpace = offset
+				}
+				rowWidth += chWidth
+			}
+			t.rows = append(t.rows, textAreaRow{start: rowStart, end: end})
+		}
+		if end >= len(t.text) {
+			break
+		}
+		start = end + 1
+	}
+}
+
+// rowAt returns the index of the row containing the given offset.
+func (t *TextArea) rowAt(offset int) int {
+	row := 0
+	for index, r := range t.rows {
+		if r.start > offset {
+			break
+		}
+		row = index
+	}
+	return row
+}
+
+// columnAt returns the screen column of the given offset within its row.
+func (t *TextArea) columnAt(offset int) int {
+	row := t.rows[t.rowAt(offset)]
+	column := 0
+	for _, ch := range t.text[row.start:offset] {
+		column += RuneWidth(ch)
+	}
+	return column
+}
+
+// offsetAt returns the offset closest to the given screen column in the given
+// row.
+func (t *TextArea) offsetAt(row, column int) int {
+	if row < 0 {
+		return 0
+	}
+	if row >= len(t.rows) {
+		return len(t.text)
+	}
+	r := t.rows[row]
+	width := 0
+	for offset := r.start; offset < r.end; offset++ {
+		chWidth := RuneWidth(t.text[offset])
+		if width+chWidth > column {
+			return offset
+		}
+		width += chWidth
+	}
+	if r.end > r.start && row+1 < len(t.rows) && t.rows[row+1].start == r.end {
+		// Wrapped row. Don't place the cursor on the next row.
+		return r.end - 1
+	}
+	return r.end
+}
+
+// moveRows moves the cursor up or down by the given number of rows, keeping
+// the preferred column.
+func (t *TextArea) moveRows(rows int, selecting bool) {
+	if t.preferredColumn < 0 {
+		t.preferredColumn = t.columnAt(t.cursor)
+	}
+	column := t.preferredColumn
+	t.moveTo(t.offsetAt(t.rowAt(t.cursor)+rows, column), selecting)
+	t.preferredColumn = column
+}
+
+// applyTheme is called when the application's theme changes.
+func (t *TextArea) applyTheme(from, to *Theme) {
+	t.Box.applyTheme(from, to)
+	themeColor(&t.textColor, from.PrimaryTextColor, to.PrimaryTextColor)
+	themeColor(&t.placeholderColor, from.TertiaryTextColor, to.TertiaryTextColor)
+	themeColor(&t.selectedTextColor, from.PrimitiveBackgroundColor, to.PrimitiveBackgroundColor)
+	themeColor(&t.selectedBackgroundColor, from.PrimaryTextColor, to.PrimaryTextColor)
+}
+
+// Draw draws this primitive onto the screen.
+func (t *TextArea) Draw(screen tcell.Screen) {
+	defer t.applyStyle(&t.textColor)()
+	t.Box.Draw(screen)
+	x, y, width, height := t.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+	t.layout(width)
+
+	// Show the placeholder.
+	if len(t.text) == 0 && t.placeholder != "" {
+		for index, line := range WordWrap(t.placeholder, width) {
+			if index >= height {
+				break
+			}
+			Print(screen, line, x, y+index, width, AlignLeft, t.placeholderColor)
+		}
+	}
+
+	// Scroll to the cursor.
+	cursorRow, cursorColumn := t.rowAt(t.cursor), t.columnAt(t.cursor)
+	if cursorRow < t.rowOffset {
+		t.rowOffset = cursorRow
+	} else if cursorRow >= t.rowOffset+height {
+		t.rowOffset = cursorRow - height + 1
+	}
+	if t.rowOffset > len(t.rows)-height {
+		t.rowOffset = len(t.rows) - height
+	}
+	if t.rowOffset < 0 {
+		t.rowOffset = 0
+	}
+	if t.wrap {
+		t.columnOffset = 0
+	} else if cursorColumn < t.columnOffset {
+		t.columnOffset = cursorColumn
+	} else if cursorColumn >= t.columnOffset+width {
+		t.columnOffset = cursorColumn - width + 1
+	}
+
+	// Draw the text.
+	selectionStart, selectionEnd := t.selection()
+	textStyle := tcell.StyleDefault.Background(t.backgroundColor).Foreground(t.textColor)
+	selectedStyle := tcell.StyleDefault.Background(t.selectedBackgroundColor).Foreground(t.selectedTextColor)
+	for line := 0; line < height && t.rowOffset+line < len(t.rows); line++ {
+		row := t.rows[t.rowOffset+line]
+		column := -t.columnOffset
+		for offset := row.start; offset < row.end && column < width; offset++ {
+			ch := t.text[offset]
+			chWidth := RuneWidth(ch)
+			if column >= 0 && column+chWidth <= width {
+				style := textStyle
+				if offset >= selectionStart && offset < selectionEnd {
+					style = selectedStyle
+				}
+				for w := 0; w < chWidth; w++ {
+					screen.SetContent(x+column+w, y+line, ch, nil, style)
+				}
+			}
+			column += chWidth
+		}
+
+		// Mark selected line breaks.
+		if row.end < len(t.text) && t.text[row.end] == '\n' && row.end >= selectionStart && row.end < selectionEnd && column >= 0 && column < width {
+			screen.SetContent(x+column, y+line, ' ', nil, selectedStyle)
+		}
+	}
+
+	// Set the cursor.
+	if t.HasFocus() {
+		screen.ShowCursor(x+cursorColumn-t.columnOffset, y+cursorRow-t.rowOffset)
+	}
+}
+
+// PasteHandler returns the handler which inserts pasted text at the cursor,
+// replacing the selection, as a single edit which can be undone in one step.
+func (t *TextArea) PasteHandler() func(text string, setFocus func(p Primitive)) {
+	return func(text string, setFocus func(p Primitive)) {
+		if t.disabled {
+			return
+		}
+		text = strings.Replace(strings.Replace(text, "\r\n", "\n", -1), "\r", "\n", -1)
+		if text == "" {
+			return
+		}
+		t.replace(text, textAreaEditOther)
+		if t.pasted != nil {
+			t.pasted(text)
+		}
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (t *TextArea) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return t.wrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		_, _, width, height := t.GetInnerRect()
+		t.layout(width)
+		selecting := event.Modifiers()&tcell.ModShift != 0
+		ctrl := event.Modifiers()&tcell.ModCtrl != 0
+		key := event.Key()
+		if key != tcell.KeyUp && key != tcell.KeyDown && key != tcell.KeyPgUp && key != tcell.KeyPgDn {
+			t.preferredColumn = -1
+		}
+
+		switch key {
+		case tcell.KeyRune:
+			t.replace(string(event.Rune()), textAreaEditInsert)
+		case tcell.KeyEnter:
+			t.replace("\n", textAreaEditOther)
+		case tcell.KeyBackspace, tcell.KeyBackspace2:
+			if start, end := t.selection(); start != end {
+				t.replace("", textAreaEditOther)
+			} else if t.cursor > 0 {
+				t.deleteRange(t.cursor-1, t.cursor)
+			}
+		case tcell.KeyDelete:
+			if start, end := t.selection(); start != end {
+				t.replace("", textAreaEditOther)
+			} else if t.cursor < len(t.text) {
+				t.deleteRange(t.cursor, t.cursor+1)
+			}
+		case tcell.KeyLeft:
+			if ctrl {
+				t.moveTo(t.wordLeft(t.cursor), selecting)
+			} else if start, end := t.selection(); start != end && !selecting {
+				t.moveTo(start, false)
+			} else {
+				t.moveTo(t.cursor-1, selecting)
+			}
+		case tcell.KeyRight:
+			if ctrl {
+				t.moveTo(t.wordRight(t.cursor), selecting)
+			} else if start, end := t.selection(); start != end && !selecting {
+				t.moveTo(end, false)
+			} else {
+				t.moveTo(t.cursor+1, selecting)
+			}
+		case tcell.KeyUp:
+			t.moveRows(-1, selecting)
+		case tcell.KeyDown:
+			t.moveRows(1, selecting)
+		case tcell.KeyPgUp:
+			t.moveRows(-height, selecting)
+		case tcell.KeyPgDn:
+			t.moveRows(height, selecting)
+		case tcell.KeyHome:
+			if ctrl {
+				t.moveTo(0, selecting)
+			} else {
+				t.moveTo(t.rows[t.rowAt(t.cursor)].start, selecting)
+			}
+		case tcell.KeyEnd:
+			if ctrl {
+				t.moveTo(len(t.text), selecting)
+			} else {
+				t.moveTo(t.offsetAt(t.rowAt(t.cursor), width+1), selecting)
+			}
+		case tcell.KeyCtrlL:
+			t.Select(0, len(t.text))
+		case tcell.KeyCtrlQ:
+			t.copySelection()
+		case tcell.KeyCtrlX:
+			t.copySelection()
+			t.replace("", textAreaEditOther)
+		case tcell.KeyCtrlV:
+			t.paste()
+		case tcell.KeyCtrlZ:
+			t.restore(&t.undoStack, &t.redoStack)
+		case tcell.KeyCtrlY:
+			t.restore(&t.redoStack, &t.undoStack)
+		case tcell.KeyCtrlK:
+			end := t.lineEnd(t.cursor)
+			if end == t.cursor && end < len(t.text) {
+				end++ // Join with the next line.
+			}
+			t.deleteRange(t.cursor, end)
+		case tcell.KeyCtrlU:
+			t.deleteRange(t.lineStart(t.cursor), t.cursor)
+		case tcell.KeyCtrlW:
+			t.deleteRange(t.wordLeft(t.cursor), t.cursor)
+		case tcell.KeyEscape, tcell.KeyTab, tcell.KeyBacktab:
+			if t.done != nil {
+				t.done(key)
+			}
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (t *TextArea) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return t.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+		rectX, rectY, width, _ := t.GetInnerRect()
+		t.layout(width)
+		offset := t.offsetAt(t.rowOffset+y-rectY, t.columnOffset+x-rectX)
+
+		// Selecting text by dragging.
+		if t.dragging {
+			switch action {
+			case MouseMove:
+				t.moveTo(offset, true)
+				return true, t
+			case MouseLeftUp:
+				t.dragging = false
+				return true, nil
+			}
+		}
+
+		if !t.InRect(x, y) {
+			return false, nil
+		}
+		switch action {
+		case MouseLeftDown:
+			setFocus(t)
+			t.moveTo(offset, event.Modifiers()&tcell.ModShift != 0)
+			t.preferredColumn = -1
+			t.dragging = true
+			return true, t
+		case MouseLeftUp, MouseLeftClick:
+			t.dragging = false
+		case MouseLeftDoubleClick:
+			t.Select(t.wordLeft(t.clamp(offset+1)), t.wordRight(offset))
+		case MouseScrollUp:
+			if t.rowOffset > 0 {
+				t.rowOffset--
+				t.moveRows(-1, false)
+			}
+		case MouseScrollDown:
+			_, _, _, height := t.GetInnerRect()
+			if t.rowOffset+height < len(t.rows) {
+				t.rowOffset++
+				t.moveRows(1, false)
+			}
+		default:
+			return false, nil
+		}
+		return true, nil
+	})
+}
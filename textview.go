@@ -2,13 +2,13 @@ package tview
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"sync"
 	"unicode/utf8"
 
 	"github.com/gdamore/tcell"
-	runewidth "github.com/mattn/go-runewidth"
 )
 
 // TabSize is the number of spaces with which a tab character will be replaced.
@@ -17,12 +17,12 @@ var TabSize = 4
 // textViewIndex contains information about each line displayed in the text
 // view.
 type textViewIndex struct {
-	Line    int         // The index into the "buffer" variable.
-	Pos     int         // The index into the "buffer" string (byte position).
-	NextPos int         // The (byte) index of the next character in this buffer line.
-	Width   int         // The screen width of this line.
-	Color   tcell.Color // The starting color.
-	Region  string      // The starting region ID.
+	Line    int       // The index into the "buffer" variable.
+	Pos     int       // The index into the "buffer" string (byte position).
+	NextPos int       // The (byte) index of the next character in this buffer line.
+	Width   int       // The screen width of this line.
+	Style   textStyle // The starting style.
+	Region  string    // The starting region ID.
 }
 
 // TextView is a box which displays text. It implements the io.Writer interface
@@ -137,6 +137,13 @@ type TextView struct {
 	// The (starting) color of the text.
 	textColor tcell.Color
 
+	// The scroll bar settings.
+	scrollBar scrollBar
+
+	// Whether or not a vertical scroll bar was shown the last time the text
+	// view was drawn.
+	verticalScrollBar bool
+
 	// If set to true, the text color can be changed dynamically by piping color
 	// strings in square brackets to the text view.
 	dynamicColors bool
@@ -157,8 +164,16 @@ type TextView struct {
 	done func(tcell.Key)
 }
 
+// textViewState is the state of a TextView saved with SaveState().
+type textViewState struct {
+	LineOffset   int  `json:"lineOffset"`
+	ColumnOffset int  `json:"columnOffset"`
+	TrackEnd     bool `json:"trackEnd"`
+}
+
 // NewTextView returns a new text view.
 func NewTextView() *TextView {
+	styles := Styles.forWidget("TextView")
 	return &TextView{
 		Box:           NewBox(),
 		highlights:    make(map[string]struct{}),
@@ -166,8 +181,9 @@ func NewTextView() *TextView {
 		scrollable:    true,
 		align:         AlignLeft,
 		wrap:          true,
-		textColor:     Styles.PrimaryTextColor,
+		textColor:     styles.PrimaryTextColor,
 		dynamicColors: false,
+		scrollBar:     newScrollBar(ScrollBarNever, styles),
 	}
 }
 
@@ -223,6 +239,21 @@ func (t *TextView) SetTextColor(color tcell.Color) *TextView {
 	return t
 }
 
+// SetScrollBarVisibility sets when a vertical scroll bar is shown on the right
+// edge of the text view. If wrapping is turned off (see SetWrap()), a
+// horizontal scroll bar is also shown at the bottom edge. The default is
+// ScrollBarNever.
+func (t *TextView) SetScrollBarVisibility(visibility ScrollBarVisibility) *TextView {
+	t.scrollBar.visibility = visibility
+	return t
+}
+
+// SetScrollBarColors sets the colors of the scroll bars' tracks and thumbs.
+func (t *TextView) SetScrollBarColors(track, thumb tcell.Color) *TextView {
+	t.scrollBar.trackColor, t.scrollBar.thumbColor = track, thumb
+	return t
+}
+
 // SetText sets the text of this text view to the provided string. Previously
 // contained text will be removed.
 func (t *TextView) SetText(text string) *TextView {
@@ -499,7 +530,7 @@ func (t *TextView) reindexBuffer(width int) {
 	// Initial states.
 	regionID := ""
 	var highlighted bool
-	color := t.textColor
+	style := newTextStyle(t.textColor, 0)
 
 	// Go through each line in the buffer.
 	for bufferIndex, str := range t.buffer {
@@ -536,7 +567,7 @@ func (t *TextView) reindexBuffer(width int) {
 		var splitLines []string
 		if t.wrap && len(str) > 0 {
 			for len(str) > 0 {
-				extract := runewidth.Truncate(str, width, "")
+				extract := truncateWidth(str, width)
 				if t.wordWrap && len(extract) < len(str) {
 					// Add any spaces from the next line.
 					if spaces := spacePattern.FindStringIndex(str[len(extract):]); spaces != nil && spaces[0] == 0 {
@@ -564,7 +595,7 @@ func (t *TextView) reindexBuffer(width int) {
 			line := &textViewIndex{
 				Line:   bufferIndex,
 				Pos:    originalPos,
-				Color:  color,
+				Style:  style,
 				Region: regionID,
 			}
 
@@ -574,7 +605,7 @@ func (t *TextView) reindexBuffer(width int) {
 				if colorPos < len(colorTagIndices) && colorTagIndices[colorPos][0] <= originalPos+lineLength {
 					// Process color tags.
 					originalPos += colorTagIndices[colorPos][1] - colorTagIndices[colorPos][0]
-					color = tcell.GetColor(colorTags[colorPos][1])
+					style = style.applyTag(colorTags[colorPos])
 					colorPos++
 				} else if regionPos < len(regionIndices) && regionIndices[regionPos][0] <= originalPos+lineLength {
 					// Process region tags.
@@ -607,7 +638,7 @@ func (t *TextView) reindexBuffer(width int) {
 
 			// Append this line.
 			line.NextPos = originalPos
-			line.Width = runewidth.StringWidth(splitLine)
+			line.Width = stringWidth(splitLine)
 			t.index = append(t.index, line)
 		}
 
@@ -619,7 +650,7 @@ func (t *TextView) reindexBuffer(width int) {
 				if spaces != nil && spaces[len(spaces)-1][1] == len(str) {
 					oldNextPos := line.NextPos
 					line.NextPos -= spaces[len(spaces)-1][1] - spaces[len(spaces)-1][0]
-					line.Width -= runewidth.StringWidth(t.buffer[line.Line][line.NextPos:oldNextPos])
+					line.Width -= stringWidth(t.buffer[line.Line][line.NextPos:oldNextPos])
 				}
 			}
 		}
@@ -634,24 +665,80 @@ func (t *TextView) reindexBuffer(width int) {
 	}
 }
 
+// SaveState returns the scroll position as JSON. See SaveLayout() for details.
+func (t *TextView) SaveState() ([]byte, error) {
+	t.Lock()
+	defer t.Unlock()
+	return json.Marshal(textViewState{
+		LineOffset:   t.lineOffset,
+		ColumnOffset: t.columnOffset,
+		TrackEnd:     t.trackEnd,
+	})
+}
+
+// RestoreState restores a state returned by SaveState().
+func (t *TextView) RestoreState(data []byte) error {
+	var state textViewState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	t.Lock()
+	defer t.Unlock()
+	t.lineOffset, t.columnOffset, t.trackEnd = state.LineOffset, state.ColumnOffset, state.TrackEnd
+	return nil
+}
+
+// applyTheme is called when the application's theme changes.
+func (t *TextView) applyTheme(from, to *Theme) {
+	t.Box.applyTheme(from, to)
+	themeColor(&t.textColor, from.PrimaryTextColor, to.PrimaryTextColor)
+	t.scrollBar.applyTheme(from, to)
+}
+
 // Draw draws this primitive onto the screen.
 func (t *TextView) Draw(screen tcell.Screen) {
 	t.Lock()
 	defer t.Unlock()
+	textColor := t.textColor
+	defer t.applyStyle(&t.textColor)()
 	t.Box.Draw(screen)
 
 	// Get the available size.
 	x, y, width, height := t.GetInnerRect()
-	t.pageSize = height
 
-	// If the width has changed, we need to reindex.
-	if width != t.lastWidth {
-		t.index = nil
+	// Re-index. If the width has changed, we need to start over.
+	reindex := func(width int) {
+		if width != t.lastWidth {
+			t.index = nil
+		}
+		t.lastWidth = width
+		t.reindexBuffer(width)
 	}
-	t.lastWidth = width
 
-	// Re-index.
-	t.reindexBuffer(width)
+	// Reserve space for the scroll bars. Because a vertical scroll bar makes
+	// the text narrower, which may change the number of lines, we start with
+	// the previous decision so we don't re-index on every draw.
+	vertical := t.scrollBar.visibility == ScrollBarAlways ||
+		t.scrollBar.visibility == ScrollBarAuto && t.verticalScrollBar
+	if vertical {
+		width--
+	}
+	reindex(width)
+	horizontal := !t.wrap && t.scrollBar.shown(width, t.longestLine)
+	if horizontal {
+		height--
+	}
+	if t.scrollBar.visibility == ScrollBarAuto && vertical != (len(t.index) > height) {
+		vertical = !vertical
+		if vertical {
+			width--
+		} else {
+			width++
+		}
+		reindex(width)
+	}
+	t.verticalScrollBar = vertical
+	t.pageSize = height
 
 	// If we don't have an index, there's nothing to draw.
 	if t.index == nil {
@@ -711,7 +798,30 @@ func (t *TextView) Draw(screen tcell.Screen) {
 		}
 	}
 
-	// Draw the buffer.
+	// Draw the scroll bars.
+	if vertical {
+		t.scrollBar.draw(screen, x+width, y, height, t.lineOffset, height, len(t.index), true, t.backgroundColor)
+	}
+	if horizontal {
+		offset := t.columnOffset
+		if t.align == AlignRight {
+			offset += t.longestLine - width
+		} else if t.align == AlignCenter {
+			offset += (t.longestLine - width) / 2
+		}
+		t.scrollBar.draw(screen, x, y+height, width, offset, width, t.longestLine, false, t.backgroundColor)
+	}
+
+	// Draw the buffer. The cells of each line are collected in a slice which
+	// is reused for all lines.
+	type textViewCell struct {
+		ch    rune
+		style tcell.Style
+	}
+	var (
+		cells      []textViewCell
+		submatches [7]string
+	)
 	for line := t.lineOffset; line < len(t.index); line++ {
 		// Are we done?
 		if line-t.lineOffset >= height {
@@ -721,33 +831,26 @@ func (t *TextView) Draw(screen tcell.Screen) {
 		// Get the text for this line.
 		index := t.index[line]
 		text := t.buffer[index.Line][index.Pos:index.NextPos]
-		color := index.Color
-		regionID := index.Region
-
-		// Get color tags.
-		var (
-			colorTagIndices [][]int
-			colorTags       [][]string
-		)
-		if t.dynamicColors {
-			colorTagIndices = colorPattern.FindAllStringIndex(text, -1)
-			colorTags = colorPattern.FindAllStringSubmatch(text, -1)
-		}
-
-		// Get regions.
-		var (
-			regionIndices [][]int
-			regions       [][]string
-		)
-		if t.regions {
-			regionIndices = regionPattern.FindAllStringIndex(text, -1)
-			regions = regionPattern.FindAllStringSubmatch(text, -1)
+		style := index.Style
+		if style.foreground == textColor {
+			style.foreground = t.textColor // The box style may have changed the text color.
 		}
+		style.defaultForeground = t.textColor
+		regionID := index.Region
 
-		// Get escape tags.
-		var escapeIndices [][]int
-		if t.dynamicColors || t.regions {
-			escapeIndices = escapePattern.FindAllStringIndex(text, -1)
+		// Get color tags, regions, and escape tags. Lines without square
+		// brackets have none.
+		var colorTagIndices, regionIndices, escapeIndices [][]int
+		if hasTags(text) {
+			if t.dynamicColors {
+				colorTagIndices = colorPattern.FindAllStringSubmatchIndex(text, -1)
+			}
+			if t.regions {
+				regionIndices = regionPattern.FindAllStringSubmatchIndex(text, -1)
+			}
+			if t.dynamicColors || t.regions {
+				escapeIndices = escapePattern.FindAllStringIndex(text, -1)
+			}
 		}
 
 		// Calculate the position of the line.
@@ -764,13 +867,14 @@ func (t *TextView) Draw(screen tcell.Screen) {
 			posX = 0
 		}
 
-		// Print the line.
-		var currentTag, currentRegion, currentEscapeTag, skipped int
+		// Collect the characters of the line and their styles.
+		var currentTag, currentRegion, currentEscapeTag int
+		cells = cells[:0]
 		for pos, ch := range text {
 			// Get the color.
-			if currentTag < len(colorTags) && pos >= colorTagIndices[currentTag][0] && pos < colorTagIndices[currentTag][1] {
+			if currentTag < len(colorTagIndices) && pos >= colorTagIndices[currentTag][0] && pos < colorTagIndices[currentTag][1] {
 				if pos == colorTagIndices[currentTag][1]-1 {
-					color = tcell.GetColor(colorTags[currentTag][1])
+					style = style.applyTag(tagSubmatches(text, colorTagIndices[currentTag], submatches[:]))
 					currentTag++
 				}
 				continue
@@ -779,7 +883,7 @@ func (t *TextView) Draw(screen tcell.Screen) {
 			// Get the region.
 			if currentRegion < len(regionIndices) && pos >= regionIndices[currentRegion][0] && pos < regionIndices[currentRegion][1] {
 				if pos == regionIndices[currentRegion][1]-1 {
-					regionID = regions[currentRegion][1]
+					regionID = text[regionIndices[currentRegion][2]:regionIndices[currentRegion][3]]
 					currentRegion++
 				}
 				continue
@@ -794,8 +898,41 @@ func (t *TextView) Draw(screen tcell.Screen) {
 				}
 			}
 
+			// Do we highlight this character?
+			cellStyle := style.apply(tcell.StyleDefault.Background(t.backgroundColor))
+			if len(regionID) > 0 {
+				if _, ok := t.highlights[regionID]; ok {
+					foreground, background, _ := cellStyle.Decompose()
+					cellStyle = cellStyle.Background(foreground).Foreground(background)
+				}
+			}
+			cells = append(cells, textViewCell{ch: ch, style: cellStyle})
+		}
+
+		// Bring right-to-left text into visual order.
+		if t.bidi {
+			runes := make([]rune, len(cells))
+			for index, cell := range cells {
+				runes[index] = cell.ch
+			}
+			if order, rtl := bidiReorder(runes); order != nil {
+				reordered := make([]textViewCell, 0, len(cells))
+				for _, index := range order {
+					cell := cells[index]
+					if rtl[index] {
+						cell.ch = bidiMirror(cell.ch)
+					}
+					reordered = append(reordered, cell)
+				}
+				cells = reordered
+			}
+		}
+
+		// Print the line.
+		var skipped int
+		for _, cell := range cells {
 			// Determine the width of this rune.
-			chWidth := runewidth.RuneWidth(ch)
+			chWidth := RuneWidth(cell.ch)
 			if chWidth == 0 {
 				continue
 			}
@@ -811,17 +948,9 @@ func (t *TextView) Draw(screen tcell.Screen) {
 				break
 			}
 
-			// Do we highlight this character?
-			style := tcell.StyleDefault.Background(t.backgroundColor).Foreground(color)
-			if len(regionID) > 0 {
-				if _, ok := t.highlights[regionID]; ok {
-					style = tcell.StyleDefault.Background(color).Foreground(t.backgroundColor)
-				}
-			}
-
 			// Draw the character.
 			for offset := 0; offset < chWidth; offset++ {
-				screen.SetContent(x+posX+offset, y+line-t.lineOffset, ch, nil, style)
+				screen.SetContent(x+posX+offset, y+line-t.lineOffset, cell.ch, nil, cell.style)
 			}
 
 			// Advance.
@@ -0,0 +1,394 @@
+package tview
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell"
+)
+
+// LoadTheme reads a theme from the given reader, allowing users to change the
+// look of an application without recompiling it. The result may be assigned to
+// Styles before any primitives are created or passed to Application.SetTheme().
+// Anything not specified in the theme is taken from the current Styles.
+//
+// The theme may be given in JSON or in YAML. Only a subset of YAML is
+// supported: nested mappings whose values are plain or quoted strings, and
+// comments. Lists, anchors, and multi-line strings are not. As in YAML, a "#"
+// after a space starts a comment, so hexadecimal colors must be quoted, e.g.
+// "#ff8700". The theme consists of the following sections, all of which are
+// optional:
+//
+//   colors:      # Named colors which may be used in the other sections.
+//     accent: "#ff8700"
+//   styles:      # The theme's colors.
+//     primitiveBackground: black
+//     primaryText: accent
+//   widgets:     # Colors for individual primitive types.
+//     Button:
+//       contrastBackground: darkslategray
+//   borders:     # The runes used to draw box borders.
+//     horizontal: "-"
+//     vertical: "|"
+//   scrollBar:   # The runes used to draw scroll bars.
+//     track: " "
+//     thumb: "#"
+//
+// The color names in the "styles" and "widgets" sections are the names of the
+// Theme fields without the "Color" suffix, starting with a lowercase letter,
+// e.g. "moreContrastBackground". Colors are W3C color names, "default", or
+// hexadecimal values in the format "#rrggbb". The rune names in the "borders"
+// section are the names of the BorderRunes fields, starting with a lowercase
+// letter, e.g. "topLeftFocus". Instead of a mapping, the "borders" section may
+// also be one of the names "single", "rounded", "double", "thick", "ascii", or
+// "none", selecting the corresponding BorderStyle preset. The "scrollBar"
+// section may contain the runes "track" and "thumb".
+func LoadTheme(r io.Reader) (Theme, error) {
+	theme := Styles
+
+	// Parse the file.
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return theme, err
+	}
+	var document map[string]interface{}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		err = json.Unmarshal(trimmed, &document)
+	} else {
+		document, err = parseYAMLMapping(data)
+	}
+	if err != nil {
+		return theme, err
+	}
+
+	// Check for unknown sections.
+	for name := range document {
+		switch name {
+		case "colors", "styles", "widgets", "borders", "scrollBar":
+		default:
+			return theme, fmt.Errorf("unknown theme section %q", name)
+		}
+	}
+
+	// Named colors come first as the other sections may refer to them.
+	palette := make(map[string]tcell.Color)
+	colors, err := themeSection(document, "colors")
+	if err != nil {
+		return theme, err
+	}
+	for name, value := range colors {
+		color, err := parseThemeColor(value, nil)
+		if err != nil {
+			return theme, fmt.Errorf("colors: %s: %v", name, err)
+		}
+		palette[name] = color
+	}
+
+	// The theme's main colors.
+	styles, err := themeSection(document, "styles")
+	if err != nil {
+		return theme, err
+	}
+	for name, value := range styles {
+		field := theme.color(name)
+		if field == nil {
+			return theme, fmt.Errorf("styles: unknown color %q", name)
+		}
+		if *field, err = parseThemeColor(value, palette); err != nil {
+			return theme, fmt.Errorf("styles: %s: %v", name, err)
+		}
+	}
+
+	// Per-widget colors. We copy the existing ones so Styles is not modified.
+	widgets, err := themeSection(document, "widgets")
+	if err != nil {
+		return theme, err
+	}
+	overrides := make(map[string]map[string]tcell.Color)
+	for widget, colors := range theme.Widgets {
+		overrides[widget] = make(map[string]tcell.Color)
+		for name, color := range colors {
+			overrides[widget][name] = color
+		}
+	}
+	for widget := range widgets {
+		colors, err := themeSection(widgets, widget)
+		if err != nil {
+			return theme, fmt.Errorf("widgets: %v", err)
+		}
+		if overrides[widget] == nil {
+			overrides[widget] = make(map[string]tcell.Color)
+		}
+		for name, value := range colors {
+			if theme.color(name) == nil {
+				return theme, fmt.Errorf("widgets: %s: unknown color %q", widget, name)
+			}
+			if overrides[widget][name], err = parseThemeColor(value, palette); err != nil {
+				return theme, fmt.Errorf("widgets: %s: %s: %v", widget, name, err)
+			}
+		}
+	}
+	if len(overrides) > 0 {
+		theme.Widgets = overrides
+	}
+
+	// Border runes. They may also be given as the name of a preset.
+	if name, ok := document["borders"].(string); ok {
+		preset, ok := borderStyles[name]
+		if !ok {
+			return theme, fmt.Errorf("borders: unknown border style %q", name)
+		}
+		theme.Borders = preset
+		delete(document, "borders")
+	}
+	borders, err := themeSection(document, "borders")
+	if err != nil {
+		return theme, err
+	}
+	for name, value := range borders {
+		field := theme.Borders.rune(name)
+		if field == nil {
+			return theme, fmt.Errorf("borders: unknown rune %q", name)
+		}
+		if *field, err = parseThemeRune(value); err != nil {
+			return theme, fmt.Errorf("borders: %s: %v", name, err)
+		}
+	}
+
+	// Scroll bar runes.
+	scrollBar, err := themeSection(document, "scrollBar")
+	if err != nil {
+		return theme, err
+	}
+	for name, value := range scrollBar {
+		var field *rune
+		switch name {
+		case "track":
+			field = &theme.ScrollBarTrack
+		case "thumb":
+			field = &theme.ScrollBarThumb
+		default:
+			return theme, fmt.Errorf("scrollBar: unknown rune %q", name)
+		}
+		if *field, err = parseThemeRune(value); err != nil {
+			return theme, fmt.Errorf("scrollBar: %s: %v", name, err)
+		}
+	}
+
+	return theme, nil
+}
+
+// borderStyles maps the names of border style presets as used in theme files
+// to the presets.
+var borderStyles = map[string]BorderRunes{
+	"single":  BorderStyleSingle,
+	"rounded": BorderStyleRounded,
+	"double":  BorderStyleDouble,
+	"thick":   BorderStyleThick,
+	"ascii":   BorderStyleASCII,
+	"none":    BorderStyleNone,
+}
+
+// rune returns a pointer to the border rune with the given name (see
+// LoadTheme()), or nil if there is no such rune.
+func (b *BorderRunes) rune(name string) *rune {
+	switch name {
+	case "horizontal":
+		return &b.Horizontal
+	case "vertical":
+		return &b.Vertical
+	case "topLeft":
+		return &b.TopLeft
+	case "topRight":
+		return &b.TopRight
+	case "bottomLeft":
+		return &b.BottomLeft
+	case "bottomRight":
+		return &b.BottomRight
+	case "horizontalFocus":
+		return &b.HorizontalFocus
+	case "verticalFocus":
+		return &b.VerticalFocus
+	case "topLeftFocus":
+		return &b.TopLeftFocus
+	case "topRightFocus":
+		return &b.TopRightFocus
+	case "bottomLeftFocus":
+		return &b.BottomLeftFocus
+	case "bottomRightFocus":
+		return &b.BottomRightFocus
+	}
+	return nil
+}
+
+// themeSection returns the mapping with the given name from the given parsed
+// theme file. If there is no such mapping, nil is returned.
+func themeSection(document map[string]interface{}, name string) (map[string]interface{}, error) {
+	value, ok := document[name]
+	if !ok || value == nil {
+		return nil, nil
+	}
+	section, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: expected a mapping", name)
+	}
+	return section, nil
+}
+
+// parseThemeColor converts a color value from a theme file into a color. If a
+// palette is provided, its color names take precedence.
+func parseThemeColor(value interface{}, palette map[string]tcell.Color) (tcell.Color, error) {
+	name, ok := value.(string)
+	if !ok {
+		return tcell.ColorDefault, fmt.Errorf("expected a color name, got %v", value)
+	}
+	if color, ok := palette[name]; ok {
+		return color, nil
+	}
+	name = strings.ToLower(name)
+	if name == "default" {
+		return tcell.ColorDefault, nil
+	}
+	color := tcell.GetColor(name)
+	if color == tcell.ColorDefault {
+		return color, fmt.Errorf("unknown color %q", name)
+	}
+	return color, nil
+}
+
+// parseThemeRune converts a rune value from a theme file into a rune.
+func parseThemeRune(value interface{}) (rune, error) {
+	str, ok := value.(string)
+	if !ok || utf8.RuneCountInString(str) != 1 {
+		return 0, errors.New("expected a single character")
+	}
+	r, _ := utf8.DecodeRuneInString(str)
+	return r, nil
+}
+
+// unquotedColorPattern matches YAML comments which are probably unquoted
+// hexadecimal colors or "#" runes.
+var unquotedColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})?$`)
+
+// parseYAMLMapping parses a small subset of YAML: nested mappings whose values
+// are plain, single-quoted, or double-quoted scalars. Comments and empty lines
+// are ignored. This is all that is needed for theme files.
+func parseYAMLMapping(data []byte) (map[string]interface{}, error) {
+	type level struct {
+		indent  int
+		mapping map[string]interface{}
+	}
+	root := make(map[string]interface{})
+	stack := []level{{indent: -1, mapping: root}}
+	var (
+		pendingKey    string
+		pendingParent map[string]interface{}
+		pendingIndent int
+	)
+
+	for number, line := range strings.Split(string(data), "\n") {
+		line, comment := stripYAMLComment(strings.TrimRight(line, " \t\r"))
+		line = strings.TrimRight(line, " \t")
+		if strings.TrimSpace(line) == "" || line == "---" {
+			continue
+		}
+		content := strings.TrimLeft(line, " ")
+		indent := len(line) - len(content)
+		if strings.HasPrefix(content, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", number+1)
+		}
+
+		// A key without a value opens a nested mapping if the following line
+		// is indented further.
+		if pendingParent != nil {
+			if indent > pendingIndent {
+				mapping := make(map[string]interface{})
+				pendingParent[pendingKey] = mapping
+				stack = append(stack, level{indent: indent, mapping: mapping})
+			}
+			pendingParent = nil
+		}
+		if len(stack) == 1 && stack[0].indent < 0 {
+			stack[0].indent = indent
+		}
+		for len(stack) > 1 && indent < stack[len(stack)-1].indent {
+			stack = stack[:len(stack)-1]
+		}
+		if indent != stack[len(stack)-1].indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", number+1)
+		}
+		parent := stack[len(stack)-1].mapping
+
+		// Split into key and value.
+		colon := strings.Index(content, ":")
+		if colon < 0 {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", number+1)
+		}
+		key, err := unquoteYAML(strings.TrimSpace(content[:colon]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", number+1, err)
+		}
+		value := strings.TrimSpace(content[colon+1:])
+		if value == "" {
+			if unquotedColorPattern.MatchString(comment) {
+				return nil, fmt.Errorf("line %d: %s starts a comment, it must be quoted (e.g. %q)", number+1, comment, comment)
+			}
+			parent[key] = nil
+			pendingKey, pendingParent, pendingIndent = key, parent, indent
+			continue
+		}
+		if parent[key], err = unquoteYAML(value); err != nil {
+			return nil, fmt.Errorf("line %d: %v", number+1, err)
+		}
+	}
+
+	return root, nil
+}
+
+// stripYAMLComment removes a comment from a YAML line and returns the line and
+// the comment, including the '#'. Comments start with a '#' at the beginning
+// of the line or after whitespace, outside of quotes.
+func stripYAMLComment(line string) (string, string) {
+	var (
+		quote   rune
+		escaped bool
+	)
+	for index, ch := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case quote == '"' && ch == '\\':
+			escaped = true
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '\'':
+			quote = ch
+		case ch == '#' && (index == 0 || line[index-1] == ' ' || line[index-1] == '\t'):
+			return line[:index], line[index:]
+		}
+	}
+	return line, ""
+}
+
+// unquoteYAML returns the value of a YAML scalar, removing quotes if present.
+func unquoteYAML(value string) (string, error) {
+	if len(value) >= 2 {
+		switch {
+		case value[0] == '"' && value[len(value)-1] == '"':
+			return strconv.Unquote(value)
+		case value[0] == '\'' && value[len(value)-1] == '\'':
+			return strings.Replace(value[1:len(value)-1], "''", "'", -1), nil
+		}
+	}
+	return value, nil
+}
@@ -0,0 +1,106 @@
+package tview
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell"
+)
+
+func TestLoadTheme(t *testing.T) {
+	for _, test := range []struct {
+		format, source string
+	}{
+		{"JSON", `{
+			"colors": {"accent": "#ff8700"},
+			"styles": {"primitiveBackground": "#1e1e2e", "primaryText": "accent"},
+			"widgets": {"Button": {"contrastBackground": "darkslategray"}},
+			"borders": {"horizontal": "-", "vertical": "|"},
+			"scrollBar": {"track": " ", "thumb": "#"}
+		}`},
+		{"YAML", `
+# A theme.
+colors:      # Named colors.
+  accent: '#ff8700'
+styles:
+  primitiveBackground: "#1e1e2e"   # Quoted.
+  primaryText: accent
+widgets:
+  Button:
+    contrastBackground: darkslategray
+borders:
+  horizontal: "-"
+  vertical: "|"
+scrollBar:
+  track: " "
+  thumb: "#"
+`},
+	} {
+		theme, err := LoadTheme(strings.NewReader(test.source))
+		if err != nil {
+			t.Errorf("%s: %v", test.format, err)
+			continue
+		}
+		if want := tcell.NewHexColor(0x1e1e2e); theme.PrimitiveBackgroundColor != want {
+			t.Errorf("%s: primitiveBackground = %v, want %v", test.format, theme.PrimitiveBackgroundColor, want)
+		}
+		if want := tcell.NewHexColor(0xff8700); theme.PrimaryTextColor != want {
+			t.Errorf("%s: primaryText = %v, want %v", test.format, theme.PrimaryTextColor, want)
+		}
+		if color, want := theme.Widgets["Button"]["contrastBackground"], tcell.GetColor("darkslategray"); color != want {
+			t.Errorf("%s: Button contrastBackground = %v, want %v", test.format, color, want)
+		}
+		if theme.Borders.Horizontal != '-' || theme.Borders.Vertical != '|' {
+			t.Errorf("%s: borders = %q, %q", test.format, theme.Borders.Horizontal, theme.Borders.Vertical)
+		}
+		if theme.ScrollBarTrack != ' ' || theme.ScrollBarThumb != '#' {
+			t.Errorf("%s: scroll bar = %q, %q", test.format, theme.ScrollBarTrack, theme.ScrollBarThumb)
+		}
+		if theme.SecondaryTextColor != Styles.SecondaryTextColor {
+			t.Errorf("%s: secondaryText = %v, want it taken from Styles", test.format, theme.SecondaryTextColor)
+		}
+	}
+}
+
+func TestLoadThemeErrors(t *testing.T) {
+	for _, test := range []struct {
+		source, err string
+	}{
+		{"styles:\n  primitiveBackground: #1e1e2e\n", `line 2: #1e1e2e starts a comment, it must be quoted (e.g. "#1e1e2e")`},
+		{"scrollBar:\n  thumb: #\n", `line 2: # starts a comment, it must be quoted (e.g. "#")`},
+		{"styles:\n  primaryText: nocolor\n", `styles: primaryText: unknown color "nocolor"`},
+		{"styles:\n  unknown: red\n", `styles: unknown color "unknown"`},
+		{"sounds:\n  beep: loud\n", `unknown theme section "sounds"`},
+		{"styles:\n  primaryText: red\n   secondaryText: red\n", "line 3: unexpected indentation"},
+		{`{"styles": {"primaryText": "nocolor"}}`, `styles: primaryText: unknown color "nocolor"`},
+	} {
+		if _, err := LoadTheme(strings.NewReader(test.source)); err == nil || err.Error() != test.err {
+			t.Errorf("LoadTheme(%q) returned error %v, want %q", test.source, err, test.err)
+		}
+	}
+}
+
+func TestParseYAMLMapping(t *testing.T) {
+	document, err := parseYAMLMapping([]byte(`---
+a:
+  b: plain text # A comment.
+  c: 'it''s # quoted'
+  d: "tab\t"
+e: x#y
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"a": map[string]interface{}{
+			"b": "plain text",
+			"c": "it's # quoted",
+			"d": "tab\t",
+		},
+		"e": "x#y",
+	}
+	if !reflect.DeepEqual(document, want) {
+		t.Errorf("parseYAMLMapping() = %v, want %v", document, want)
+	}
+}
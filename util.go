@@ -1,14 +1,15 @@
 package tview
 
 import (
+	"fmt"
 	"math"
 	"regexp"
 	"strconv"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/gdamore/tcell"
-	runewidth "github.com/mattn/go-runewidth"
 )
 
 // Text alignment within a box.
@@ -18,6 +19,12 @@ const (
 	AlignRight
 )
 
+// Vertical alignment within a box.
+const (
+	AlignTop    = AlignLeft
+	AlignBottom = AlignRight
+)
+
 // Semigraphical runes.
 const (
 	GraphicsHoriBar             = '\u2500'
@@ -38,77 +45,74 @@ const (
 	GraphicsDbBottomRightCorner = '\u255d'
 	GraphicsDbBottomLeftCorner  = '\u255a'
 	GraphicsEllipsis            = '\u2026'
+	GraphicsCheckMark           = '\u2713'
 )
 
-// joints maps combinations of two graphical runes to the rune that results
-// when joining the two in the same screen cell. The keys of this map are
-// two-rune strings where the value of the first rune is lower than the value
-// of the second rune. Identical runes are not contained.
-var joints = map[string]rune{
-	"\u2500\u2502": GraphicsCross,
-	"\u2500\u250c": GraphicsTopT,
-	"\u2500\u2510": GraphicsTopT,
-	"\u2500\u2514": GraphicsBottomT,
-	"\u2500\u2518": GraphicsBottomT,
-	"\u2500\u251c": GraphicsCross,
-	"\u2500\u2524": GraphicsCross,
-	"\u2500\u252c": GraphicsTopT,
-	"\u2500\u2534": GraphicsBottomT,
-	"\u2500\u253c": GraphicsCross,
-	"\u2502\u250c": GraphicsLeftT,
-	"\u2502\u2510": GraphicsRightT,
-	"\u2502\u2514": GraphicsLeftT,
-	"\u2502\u2518": GraphicsRightT,
-	"\u2502\u251c": GraphicsLeftT,
-	"\u2502\u2524": GraphicsRightT,
-	"\u2502\u252c": GraphicsCross,
-	"\u2502\u2534": GraphicsCross,
-	"\u2502\u253c": GraphicsCross,
-	"\u250c\u2510": GraphicsTopT,
-	"\u250c\u2514": GraphicsLeftT,
-	"\u250c\u2518": GraphicsCross,
-	"\u250c\u251c": GraphicsLeftT,
-	"\u250c\u2524": GraphicsCross,
-	"\u250c\u252c": GraphicsTopT,
-	"\u250c\u2534": GraphicsCross,
-	"\u250c\u253c": GraphicsCross,
-	"\u2510\u2514": GraphicsCross,
-	"\u2510\u2518": GraphicsRightT,
-	"\u2510\u251c": GraphicsCross,
-	"\u2510\u2524": GraphicsRightT,
-	"\u2510\u252c": GraphicsTopT,
-	"\u2510\u2534": GraphicsCross,
-	"\u2510\u253c": GraphicsCross,
-	"\u2514\u2518": GraphicsBottomT,
-	"\u2514\u251c": GraphicsLeftT,
-	"\u2514\u2524": GraphicsCross,
-	"\u2514\u252c": GraphicsCross,
-	"\u2514\u2534": GraphicsBottomT,
-	"\u2514\u253c": GraphicsCross,
-	"\u2518\u251c": GraphicsCross,
-	"\u2518\u2524": GraphicsRightT,
-	"\u2518\u252c": GraphicsCross,
-	"\u2518\u2534": GraphicsBottomT,
-	"\u2518\u253c": GraphicsCross,
-	"\u251c\u2524": GraphicsCross,
-	"\u251c\u252c": GraphicsCross,
-	"\u251c\u2534": GraphicsCross,
-	"\u251c\u253c": GraphicsCross,
-	"\u2524\u252c": GraphicsCross,
-	"\u2524\u2534": GraphicsCross,
-	"\u2524\u253c": GraphicsCross,
-	"\u252c\u2534": GraphicsCross,
-	"\u252c\u253c": GraphicsCross,
-	"\u2534\u253c": GraphicsCross,
+// graphicsArms describes box drawing runes by the lines extending from the
+// center of the cell to the top, right, bottom, and left edge. Each line is
+// either absent (0), light (1), heavy (2), or double (3). Rounded corners join
+// like their light counterparts.
+var graphicsArms = map[rune][4]byte{
+	// Light lines.
+	'\u2500': {0, 1, 0, 1}, '\u2502': {1, 0, 1, 0},
+	'\u250c': {0, 1, 1, 0}, '\u2510': {0, 0, 1, 1}, '\u2514': {1, 1, 0, 0}, '\u2518': {1, 0, 0, 1},
+	'\u251c': {1, 1, 1, 0}, '\u2524': {1, 0, 1, 1}, '\u252c': {0, 1, 1, 1}, '\u2534': {1, 1, 0, 1},
+	'\u253c': {1, 1, 1, 1},
+	'\u256d': {0, 1, 1, 0}, '\u256e': {0, 0, 1, 1}, '\u2570': {1, 1, 0, 0}, '\u256f': {1, 0, 0, 1},
+
+	// Heavy lines.
+	'\u2501': {0, 2, 0, 2}, '\u2503': {2, 0, 2, 0},
+	'\u250f': {0, 2, 2, 0}, '\u2513': {0, 0, 2, 2}, '\u2517': {2, 2, 0, 0}, '\u251b': {2, 0, 0, 2},
+	'\u2523': {2, 2, 2, 0}, '\u252b': {2, 0, 2, 2}, '\u2533': {0, 2, 2, 2}, '\u253b': {2, 2, 0, 2},
+	'\u254b': {2, 2, 2, 2},
+
+	// Double lines.
+	'\u2550': {0, 3, 0, 3}, '\u2551': {3, 0, 3, 0},
+	'\u2554': {0, 3, 3, 0}, '\u2557': {0, 0, 3, 3}, '\u255a': {3, 3, 0, 0}, '\u255d': {3, 0, 0, 3},
+	'\u2560': {3, 3, 3, 0}, '\u2563': {3, 0, 3, 3}, '\u2566': {0, 3, 3, 3}, '\u2569': {3, 3, 0, 3},
+	'\u256c': {3, 3, 3, 3},
+
+	// Light vertical and double horizontal lines.
+	'\u2552': {0, 3, 1, 0}, '\u2555': {0, 0, 1, 3}, '\u2558': {1, 3, 0, 0}, '\u255b': {1, 0, 0, 3},
+	'\u255e': {1, 3, 1, 0}, '\u2561': {1, 0, 1, 3}, '\u2564': {0, 3, 1, 3}, '\u2567': {1, 3, 0, 3},
+	'\u256a': {1, 3, 1, 3},
+
+	// Double vertical and light horizontal lines.
+	'\u2553': {0, 1, 3, 0}, '\u2556': {0, 0, 3, 1}, '\u2559': {3, 1, 0, 0}, '\u255c': {3, 0, 0, 1},
+	'\u255f': {3, 1, 3, 0}, '\u2562': {3, 0, 3, 1}, '\u2565': {0, 1, 3, 1}, '\u2568': {3, 1, 0, 1},
+	'\u256b': {3, 1, 3, 1},
 }
 
+// graphicsRunes is the reverse of graphicsArms. Corners are always square.
+var graphicsRunes = make(map[[4]byte]rune)
+
+func init() {
+	for r, arms := range graphicsArms {
+		if r < '\u256d' || r > '\u2570' {
+			graphicsRunes[arms] = r
+		}
+	}
+}
+
+// colorTagContent matches the contents of a color tag: up to three fields
+// (text color, background color, and attributes) separated by colons, at least
+// one of which is not empty. A field may also be "-" to reset it. Depending on
+// which fields are given, the text color is in submatch 1, the background
+// color in submatch 2 or 4, and the attributes in submatch 3, 5, or 6.
+const colorTagContent = `([a-zA-Z]+|#[0-9a-zA-Z]{6}|-)(?::([a-zA-Z]+|#[0-9a-zA-Z]{6}|-)?(?::([blrud]+|-)?)?)?|:([a-zA-Z]+|#[0-9a-zA-Z]{6}|-)(?::([blrud]+|-)?)?|::([blrud]+|-)`
+
+// tagContent matches the contents of a region tag or a color tag, without
+// submatches.
+var tagContent = `"[a-zA-Z0-9_,;: \-\.]*"|` + strings.Replace(colorTagContent, "([", "(?:[", -1)
+
 // Common regular expressions.
 var (
-	colorPattern    = regexp.MustCompile(`\[([a-zA-Z]+|#[0-9a-zA-Z]{6})\]`)
+	colorPattern    = regexp.MustCompile(`\[(?:` + colorTagContent + `)\]`)
 	regionPattern   = regexp.MustCompile(`\["([a-zA-Z0-9_,;: \-\.]*)"\]`)
-	escapePattern   = regexp.MustCompile(`\[("[a-zA-Z0-9_,;: \-\.]*"|[a-zA-Z]+|#[0-9a-zA-Z]{6})\[(\[*)\]`)
+	escapePattern   = regexp.MustCompile(`\[(` + tagContent + `)\[(\[*)\]`)
 	boundaryPattern = regexp.MustCompile("([[:punct:]]\\s*|\\s+)")
 	spacePattern    = regexp.MustCompile(`\s+`)
+	tagPattern      = regexp.MustCompile(`(\[(?:` + tagContent + `)\[*)\]`)
 )
 
 // Predefined InputField acceptance functions.
@@ -152,38 +156,73 @@ func init() {
 
 // Print prints text onto the screen into the given box at (x,y,maxWidth,1),
 // not exceeding that box. "align" is one of AlignLeft, AlignCenter, or
-// AlignRight. The screen's background color will not be changed.
+// AlignRight. The screen's background color will not be changed unless a color
+// tag specifies a background color.
 //
-// You can change the text color mid-text by inserting a color tag. See the
-// package description for details.
+// You can change the text and background color mid-text by inserting a color
+// tag. See the package description for details.
 //
 // Returns the number of actual runes printed (not including color tags) and the
 // actual width used for the printed runes.
 func Print(screen tcell.Screen, text string, x, y, maxWidth, align int, color tcell.Color) (int, int) {
+	return printWithStyle(screen, text, x, y, maxWidth, align, newTextStyle(color, 0))
+}
+
+// PrintStyled works like Print() but prints the text in the given style. The
+// style's background color is only applied if it is not tcell.ColorDefault.
+// Color tags in the text may still change the style, with "-" fields restoring
+// the style's text color and attributes. Returns the width of the printed text.
+func PrintStyled(screen tcell.Screen, text string, x, y, maxWidth, align int, style Style) int {
+	textStyle := newTextStyle(style.Foreground, style.Attributes)
+	if style.Background != tcell.ColorDefault {
+		textStyle.background = style.Background
+		textStyle.hasBackground = true
+	}
+	_, width := printWithStyle(screen, text, x, y, maxWidth, align, textStyle)
+	return width
+}
+
+// PrintTruncated works like PrintStyled() but if the text does not fit into
+// "maxWidth", it is shortened and the given ellipsis (e.g. "…") is appended to
+// it (see TruncateString()). With an empty ellipsis, the text is simply cut
+// off. Returns the width of the printed text.
+func PrintTruncated(screen tcell.Screen, text string, x, y, maxWidth, align int, style Style, ellipsis string) int {
+	if maxWidth <= 0 {
+		return 0
+	}
+	return PrintStyled(screen, TruncateString(text, maxWidth, ellipsis), x, y, maxWidth, align, style)
+}
+
+// printWithStyle works like Print() but starts with the given text style
+// instead of a text color.
+func printWithStyle(screen tcell.Screen, text string, x, y, maxWidth, align int, style textStyle) (int, int) {
 	if maxWidth < 0 {
 		return 0, 0
 	}
 
-	// Get positions of color and escape tags. Remove them from original string.
-	colorIndices := colorPattern.FindAllStringIndex(text, -1)
-	colors := colorPattern.FindAllStringSubmatch(text, -1)
-	escapeIndices := escapePattern.FindAllStringIndex(text, -1)
-	strippedText := escapePattern.ReplaceAllString(colorPattern.ReplaceAllString(text, ""), "[$1$2]")
-
-	// We deal with runes, not with bytes.
-	runes := []rune(strippedText)
-
-	// This helper function takes positions for a substring of "runes" and a start
-	// color and returns the substring with the original tags and the new start
-	// color.
-	substring := func(from, to int, color tcell.Color) (string, tcell.Color) {
-		var colorPos, escapePos, runePos, startPos int
+	// Get positions of color and escape tags. Text without square brackets
+	// has none, so we don't need to search it.
+	var colorIndices, escapeIndices [][]int
+	if hasTags(text) {
+		colorIndices = colorPattern.FindAllStringSubmatchIndex(text, -1)
+		escapeIndices = escapePattern.FindAllStringIndex(text, -1)
+	}
+
+	// This helper function takes positions for a substring of "runes" and
+	// returns the substring with the original tags. All color tags which
+	// precede the substring are prepended to it so the substring starts with
+	// the same style.
+	substring := func(from, to int) string {
+		var (
+			colorPos, escapePos, runePos, startPos int
+			prefix                                 string
+		)
 		for pos := range text {
 			// Handle color tags.
 			if colorPos < len(colorIndices) && pos >= colorIndices[colorPos][0] && pos < colorIndices[colorPos][1] {
 				if pos == colorIndices[colorPos][1]-1 {
 					if runePos <= from {
-						color = tcell.GetColor(colors[colorPos][1])
+						prefix += text[colorIndices[colorPos][0]:colorIndices[colorPos][1]]
 					}
 					colorPos++
 				}
@@ -203,45 +242,56 @@ func Print(screen tcell.Screen, text string, x, y, maxWidth, align int, color tc
 			if runePos == from {
 				startPos = pos
 			} else if runePos >= to {
-				return text[startPos:pos], color
+				return prefix + text[startPos:pos]
 			}
 
 			runePos++
 		}
 
-		return text[startPos:], color
+		return prefix + text[startPos:]
 	}
 
-	// We want to reduce everything to AlignLeft.
+	// We want to reduce everything to AlignLeft. Only then do we need the text
+	// without tags, as runes.
+	var (
+		strippedText string
+		runes        []rune
+	)
+	if align == AlignRight || align == AlignCenter {
+		strippedText = text
+		if len(colorIndices) > 0 || len(escapeIndices) > 0 {
+			strippedText = StripTags(text)
+		}
+		runes = []rune(strippedText)
+	}
 	if align == AlignRight {
 		width := 0
 		start := len(runes)
 		for index := start - 1; index >= 0; index-- {
-			w := runewidth.RuneWidth(runes[index])
+			w := RuneWidth(runes[index])
 			if width+w > maxWidth {
 				break
 			}
 			width += w
 			start = index
 		}
-		text, color = substring(start, len(runes), color)
-		return Print(screen, text, x+maxWidth-width, y, width, AlignLeft, color)
+		return printWithStyle(screen, substring(start, len(runes)), x+maxWidth-width, y, width, AlignLeft, style)
 	} else if align == AlignCenter {
-		width := runewidth.StringWidth(strippedText)
+		width := stringWidth(strippedText)
 		if width == maxWidth {
 			// Use the exact space.
-			return Print(screen, text, x, y, maxWidth, AlignLeft, color)
+			return printWithStyle(screen, text, x, y, maxWidth, AlignLeft, style)
 		} else if width < maxWidth {
 			// We have more space than we need.
 			half := (maxWidth - width) / 2
-			return Print(screen, text, x+half, y, maxWidth-half, AlignLeft, color)
+			return printWithStyle(screen, text, x+half, y, maxWidth-half, AlignLeft, style)
 		} else {
 			// Chop off runes until we have a perfect fit.
 			var choppedLeft, choppedRight, leftIndex, rightIndex int
 			rightIndex = len(runes) - 1
 			for rightIndex > leftIndex && width-choppedLeft-choppedRight > maxWidth {
-				leftWidth := runewidth.RuneWidth(runes[leftIndex])
-				rightWidth := runewidth.RuneWidth(runes[rightIndex])
+				leftWidth := RuneWidth(runes[leftIndex])
+				rightWidth := RuneWidth(runes[rightIndex])
 				if choppedLeft < choppedRight {
 					choppedLeft += leftWidth
 					leftIndex++
@@ -250,20 +300,22 @@ func Print(screen tcell.Screen, text string, x, y, maxWidth, align int, color tc
 					rightIndex--
 				}
 			}
-			text, color = substring(leftIndex, rightIndex, color)
-			return Print(screen, text, x, y, maxWidth, AlignLeft, color)
+			return printWithStyle(screen, substring(leftIndex, rightIndex), x, y, maxWidth, AlignLeft, style)
 		}
 	}
 
 	// Draw text.
 	drawn := 0
 	drawnWidth := 0
-	var colorPos, escapePos int
+	var (
+		colorPos, escapePos int
+		submatches          [7]string
+	)
 	for pos, ch := range text {
 		// Handle color tags.
 		if colorPos < len(colorIndices) && pos >= colorIndices[colorPos][0] && pos < colorIndices[colorPos][1] {
 			if pos == colorIndices[colorPos][1]-1 {
-				color = tcell.GetColor(colors[colorPos][1])
+				style = style.applyTag(tagSubmatches(text, colorIndices[colorPos], submatches[:]))
 				colorPos++
 			}
 			continue
@@ -279,18 +331,18 @@ func Print(screen tcell.Screen, text string, x, y, maxWidth, align int, color tc
 		}
 
 		// Check if we have enough space for this rune.
-		chWidth := runewidth.RuneWidth(ch)
+		chWidth := RuneWidth(ch)
 		if drawnWidth+chWidth > maxWidth {
 			break
 		}
 		finalX := x + drawnWidth
 
 		// Print the rune.
-		_, _, style, _ := screen.GetContent(finalX, y)
-		style = style.Foreground(color)
+		_, _, cellStyle, _ := screen.GetContent(finalX, y)
+		cellStyle = style.apply(cellStyle)
 		for offset := 0; offset < chWidth; offset++ {
 			// To avoid undesired effects, we place the same character in all cells.
-			screen.SetContent(finalX+offset, y, ch, nil, style)
+			screen.SetContent(finalX+offset, y, ch, nil, cellStyle)
 		}
 
 		drawn++
@@ -300,6 +352,148 @@ func Print(screen tcell.Screen, text string, x, y, maxWidth, align int, color tc
 	return drawn, drawnWidth
 }
 
+// hasTags returns whether the given text may contain color, region, or escape
+// tags. Text without square brackets can skip the regular expressions.
+func hasTags(text string) bool {
+	return strings.IndexByte(text, '[') >= 0
+}
+
+// tagSubmatches fills "submatches" with the submatches of the color tag whose
+// indices in "text" were returned by colorPattern.FindAllStringSubmatchIndex()
+// and returns it. Submatches which did not participate in the match are empty.
+func tagSubmatches(text string, indices []int, submatches []string) []string {
+	for index := range submatches {
+		submatches[index] = ""
+		if 2*index+1 < len(indices) && indices[2*index] >= 0 {
+			submatches[index] = text[indices[2*index]:indices[2*index+1]]
+		}
+	}
+	return submatches
+}
+
+// textStyle is the style of tagged text at a specific position, resulting
+// from the color tags preceding it.
+type textStyle struct {
+	// The text color.
+	foreground tcell.Color
+
+	// The background color. Only used if "hasBackground" is true. Otherwise,
+	// the background is left unchanged.
+	background    tcell.Color
+	hasBackground bool
+
+	// The text attributes. Only used if "hasAttributes" is true. Otherwise,
+	// the attributes are left unchanged.
+	attributes    tcell.AttrMask
+	hasAttributes bool
+
+	// The primitive's text color and attributes, restored by "-" fields.
+	defaultForeground tcell.Color
+	defaultAttributes tcell.AttrMask
+}
+
+// newTextStyle returns the text style of a primitive's text before any tags
+// are applied. Attributes of 0 leave the attributes on screen unchanged.
+func newTextStyle(foreground tcell.Color, attributes tcell.AttrMask) textStyle {
+	return textStyle{
+		foreground:        foreground,
+		attributes:        attributes,
+		hasAttributes:     attributes != 0,
+		defaultForeground: foreground,
+		defaultAttributes: attributes,
+	}
+}
+
+// applyTag returns the style after applying the color tag with the given
+// submatches (see colorPattern) to it. Empty fields leave the corresponding
+// part of the style unchanged. Fields with a "-" reset it to the primitive's
+// default: its text color, its background (i.e. the background on screen), or
+// its attributes.
+func (s textStyle) applyTag(tag []string) textStyle {
+	// Only one of the alternative submatches is not empty.
+	foreground, background, attributes := tag[1], tag[2]+tag[4], tag[3]+tag[5]+tag[6]
+	switch foreground {
+	case "":
+	case "-":
+		s.foreground = s.defaultForeground
+	default:
+		s.foreground = tcell.GetColor(foreground)
+	}
+	switch background {
+	case "":
+	case "-":
+		s.hasBackground = false
+	default:
+		s.background = tcell.GetColor(background)
+		s.hasBackground = true
+	}
+	switch attributes {
+	case "":
+	case "-":
+		s.attributes = s.defaultAttributes
+		s.hasAttributes = s.defaultAttributes != 0
+	default:
+		s.attributes = 0
+		for _, flag := range attributes {
+			switch flag {
+			case 'b':
+				s.attributes |= tcell.AttrBold
+			case 'l':
+				s.attributes |= tcell.AttrBlink
+			case 'r':
+				s.attributes |= tcell.AttrReverse
+			case 'u':
+				s.attributes |= tcell.AttrUnderline
+			case 'd':
+				s.attributes |= tcell.AttrDim
+			}
+		}
+		s.hasAttributes = true
+	}
+	return s
+}
+
+// styleTag returns a color tag which sets the given text color and attributes.
+// The text color is left unchanged if it is tcell.ColorDefault.
+func styleTag(foreground tcell.Color, attributes tcell.AttrMask) string {
+	var color string
+	if foreground != tcell.ColorDefault {
+		color = fmt.Sprintf("#%06x", foreground.Hex())
+		for name, c := range tcell.ColorNames {
+			if c == foreground {
+				color = name
+				break
+			}
+		}
+	}
+	flags := "-"
+	if attributes != 0 {
+		flags = ""
+		for index, mask := range []tcell.AttrMask{tcell.AttrBold, tcell.AttrBlink, tcell.AttrReverse, tcell.AttrUnderline, tcell.AttrDim} {
+			if attributes&mask != 0 {
+				flags += string("blrud"[index])
+			}
+		}
+	}
+	return "[" + color + "::" + flags + "]"
+}
+
+// apply returns the given cell style changed according to this text style.
+func (s textStyle) apply(style tcell.Style) tcell.Style {
+	style = style.Foreground(s.foreground)
+	if s.hasBackground {
+		style = style.Background(s.background)
+	}
+	if s.hasAttributes {
+		style = style.Bold(s.attributes&tcell.AttrBold != 0).
+			Blink(s.attributes&tcell.AttrBlink != 0).
+			Reverse(s.attributes&tcell.AttrReverse != 0).
+			Underline(s.attributes&tcell.AttrUnderline != 0).
+			Dim(s.attributes&tcell.AttrDim != 0)
+	}
+	return style
+}
+
 // PrintSimple prints white text to the screen at the given position.
 func PrintSimple(screen tcell.Screen, text string, x, y int) {
 	Print(screen, text, x, y, math.MaxInt32, AlignLeft, Styles.PrimaryTextColor)
@@ -308,149 +502,579 @@ func PrintSimple(screen tcell.Screen, text string, x, y int) {
 // StringWidth returns the width of the given string needed to print it on
 // screen. The text may contain color tags which are not counted.
 func StringWidth(text string) int {
-	return runewidth.StringWidth(escapePattern.ReplaceAllString(colorPattern.ReplaceAllString(text, ""), "[$1$2]"))
+	return taggedWidth(text, false)
+}
+
+// Escape escapes the given text such that color and region tags are not
+// recognized and substituted by the print functions of this package. Only
+// text which would be taken for a tag (or an escaped tag) is changed, other
+// square brackets are left alone. Use it for user-supplied strings which may
+// contain square brackets, for example:
+//
+//   box.SetTitle(tview.Escape(fileName))
+func Escape(text string) string {
+	return tagPattern.ReplaceAllString(text, "$1[]")
+}
+
+// TaggedStringWidth returns the width of the given text on screen when it is
+// printed by a primitive which interprets color and region tags (e.g. a text
+// view with dynamic colors and regions). Unlike StringWidth(), which only
+// skips color tags, it skips region tags, too. Escaped tags count as printed,
+// i.e. without the escape character.
+func TaggedStringWidth(text string) int {
+	return taggedWidth(text, true)
+}
+
+// StripTags removes all color tags from the given string and unescapes escaped
+// tags, returning the text as it appears on screen.
+func StripTags(text string) string {
+	return escapePattern.ReplaceAllString(colorPattern.ReplaceAllString(text, ""), "[$1$2]")
+}
+
+// StyledSpan is a piece of text with a uniform style, as returned by
+// ParseTags().
+type StyledSpan struct {
+	// The text, without tags. Escaped tags are unescaped.
+	Text string
+
+	// The text and background colors. tcell.ColorDefault if no tag has set
+	// the color or if it was reset with "-", meaning that the primitive's
+	// default color is to be used.
+	Foreground, Background tcell.Color
+
+	// The text attributes. 0 if no tag has set them or if they were reset
+	// with "-".
+	Attributes tcell.AttrMask
+}
+
+// Style returns the given style with the span's colors and attributes applied
+// to it. Colors and attributes which are not set by the span are taken from
+// the given style.
+func (s StyledSpan) Style(style tcell.Style) tcell.Style {
+	if s.Foreground != tcell.ColorDefault {
+		style = style.Foreground(s.Foreground)
+	}
+	if s.Background != tcell.ColorDefault {
+		style = style.Background(s.Background)
+	}
+	if s.Attributes != 0 {
+		style = Style{Attributes: s.Attributes}.withAttributes(style)
+	}
+	return style
+}
+
+// ParseTags splits the given text into spans of uniformly styled text according
+// to its color tags (see the package description for the tag syntax). This
+// allows custom primitives to support the same tags as the primitives of this
+// package. Spans without text are omitted. For example:
+//
+//   for _, span := range tview.ParseTags(text) {
+//     style := span.Style(defaultStyle)
+//     for _, ch := range span.Text {
+//       screen.SetContent(x, y, ch, nil, style)
+//       x += tview.RuneWidth(ch)
+//     }
+//   }
+func ParseTags(text string) []StyledSpan {
+	var spans []StyledSpan
+	style := newTextStyle(tcell.ColorDefault, 0)
+	add := func(text string) {
+		if text == "" {
+			return
+		}
+		span := StyledSpan{
+			Text:       escapePattern.ReplaceAllString(text, "[$1$2]"),
+			Foreground: style.foreground,
+			Background: tcell.ColorDefault,
+		}
+		if style.hasBackground {
+			span.Background = style.background
+		}
+		if style.hasAttributes {
+			span.Attributes = style.attributes
+		}
+		spans = append(spans, span)
+	}
+
+	var start int
+	for _, tag := range colorPattern.FindAllStringSubmatchIndex(text, -1) {
+		add(text[start:tag[0]])
+		style = style.applyTag(tagSubmatches(text, tag, make([]string, len(tag)/2)))
+		start = tag[1]
+	}
+	add(text[start:])
+
+	return spans
+}
+
+// findMatches returns, for each rune of "line", whether or not it is part of
+// an occurrence of "search" starting at or after index "from". Runes are
+// compared case-insensitively. "search" must be in lower case.
+func findMatches(line []rune, from int, search []rune) []bool {
+	matched := make([]bool, len(line))
+	if len(search) == 0 {
+		return matched
+	}
+	for start := from; start+len(search) <= len(line); start++ {
+		found := true
+		for offset, r := range search {
+			if unicode.ToLower(line[start+offset]) != r {
+				found = false
+				break
+			}
+		}
+		if found {
+			for offset := range search {
+				matched[start+offset] = true
+			}
+		}
+	}
+	return matched
+}
+
+// visibleRune is a rune of tagged text which is shown on screen, together with
+// its screen width and the position of its bytes in the original text.
+type visibleRune struct {
+	ch       rune
+	width    int
+	from, to int
+}
+
+// visibleRunes returns the runes of the given text which are shown on screen,
+// i.e. without color tags and without the characters which escape tags.
+func visibleRunes(text string) []visibleRune {
+	var colorIndices, escapeIndices [][]int
+	if hasTags(text) {
+		colorIndices = colorPattern.FindAllStringIndex(text, -1)
+		escapeIndices = escapePattern.FindAllStringIndex(text, -1)
+	}
+	var (
+		runes               = make([]visibleRune, 0, utf8.RuneCountInString(text))
+		colorPos, escapePos int
+	)
+	for pos, ch := range text {
+		// Skip color tags.
+		if colorPos < len(colorIndices) && pos >= colorIndices[colorPos][0] && pos < colorIndices[colorPos][1] {
+			if pos == colorIndices[colorPos][1]-1 {
+				colorPos++
+			}
+			continue
+		}
+
+		// Skip the second-to-last character of escape tags.
+		if escapePos < len(escapeIndices) && pos >= escapeIndices[escapePos][0] && pos < escapeIndices[escapePos][1] {
+			if pos == escapeIndices[escapePos][1]-1 {
+				escapePos++
+			} else if pos == escapeIndices[escapePos][1]-2 {
+				continue
+			}
+		}
+
+		runes = append(runes, visibleRune{
+			ch:    ch,
+			width: RuneWidth(ch),
+			from:  pos,
+			to:    pos + utf8.RuneLen(ch),
+		})
+	}
+	return runes
+}
+
+// graphemeBoundary returns whether a grapheme cluster (a user-perceived
+// character) may end between the runes "previous" and "next". This is a
+// simplified version of the rules of Unicode Standard Annex #29 which keeps
+// combining marks, variation selectors, emoji modifiers, and emoji ZWJ
+// sequences with their base character and pairs of regional indicators (flags)
+// together. "indicators" is the number of consecutive regional indicators up to
+// and including "previous".
+func graphemeBoundary(previous, next rune, indicators int) bool {
+	switch {
+	case previous == '\r' && next == '\n':
+		return false
+	case previous == '\u200d' || next == '\u200d': // Zero-width joiner.
+		return false
+	case unicode.In(next, unicode.Mn, unicode.Me, unicode.Mc):
+		return false
+	case next >= 0xfe00 && next <= 0xfe0f: // Variation selectors.
+		return false
+	case next >= 0x1f3fb && next <= 0x1f3ff: // Emoji skin tone modifiers.
+		return false
+	case next >= 0xe0020 && next <= 0xe007f: // Emoji tag sequences.
+		return false
+	case isRegionalIndicator(next) && indicators%2 == 1:
+		return false
+	}
+	return true
+}
+
+// isRegionalIndicator returns whether the given rune is a regional indicator
+// symbol. Pairs of them form flags.
+func isRegionalIndicator(ch rune) bool {
+	return ch >= 0x1f1e6 && ch <= 0x1f1ff
+}
+
+// graphemeStarts returns, for each of the given runes, whether it starts a new
+// grapheme cluster.
+func graphemeStarts(runes []rune) []bool {
+	starts := make([]bool, len(runes))
+	var indicators int
+	for index, ch := range runes {
+		starts[index] = index == 0 || graphemeBoundary(runes[index-1], ch, indicators)
+		if isRegionalIndicator(ch) {
+			indicators++
+		} else {
+			indicators = 0
+		}
+	}
+	return starts
+}
+
+// graphemes splits the given text (without color tags) into grapheme clusters.
+func graphemes(text string) (clusters []string) {
+	runes := []rune(text)
+	var start int
+	for index, isStart := range graphemeStarts(runes) {
+		if isStart && index > 0 {
+			clusters = append(clusters, string(runes[start:index]))
+			start = index
+		}
+	}
+	if start < len(runes) {
+		clusters = append(clusters, string(runes[start:]))
+	}
+	return
+}
+
+// Hyphenate enables hyphenation in WordWrap() if it is not nil. It is called
+// with words which do not fit into the remainder of a line and returns the
+// positions (byte offsets into the word, in ascending order) at which the word
+// may be hyphenated, e.g. syllable boundaries found with language-specific
+// patterns. WordWrap() then splits the word at the last position which fits
+// and appends a hyphen to the line. For a simple approach which hyphenates
+// long words anywhere, set it to HyphenateAnywhere.
+var Hyphenate func(word string) []int
+
+// HyphenateAnywhere is a function for Hyphenate which allows words to be split
+// between any two characters, keeping at least three characters on each side.
+// Only words consisting of letters and digits are hyphenated.
+func HyphenateAnywhere(word string) (positions []int) {
+	runes := []rune(word)
+	for _, ch := range runes {
+		if !unicode.IsLetter(ch) && !unicode.IsDigit(ch) && !unicode.Is(unicode.Mn, ch) {
+			return nil
+		}
+	}
+	var pos int
+	for index, ch := range runes {
+		if index >= 3 && index <= len(runes)-3 {
+			positions = append(positions, pos)
+		}
+		pos += utf8.RuneLen(ch)
+	}
+	return
 }
 
 // WordWrap splits a text such that each resulting line does not exceed the
 // given screen width. Possible split points are after any punctuation or
-// whitespace. Whitespace after split points will be dropped.
+// whitespace and before and after wide characters (e.g. Chinese, Japanese, or
+// Korean characters). Lines are never split within grapheme clusters, e.g.
+// before combining marks. Whitespace at split points will be dropped. If a word
+// does not fit into a line, it is split at the last possible character.
+//
+// If Hyphenate is set, words which do not fit into a line may be hyphenated.
 //
-// This function considers color tags to have no width.
+// This function considers color tags to have no width. Each line starts with
+// all color tags preceding it so it can be printed with the colors it has in
+// the original text.
 //
 // Text is always split at newline characters ('\n').
 func WordWrap(text string, width int) (lines []string) {
-	// Strip color tags.
-	strippedText := escapePattern.ReplaceAllString(colorPattern.ReplaceAllString(text, ""), "[$1$2]")
-
-	// Keep track of color tags and escape patterns so we can restore the original
-	// indices.
-	colorTagIndices := colorPattern.FindAllStringIndex(text, -1)
-	escapeIndices := escapePattern.FindAllStringIndex(text, -1)
-
-	// Find candidate breakpoints.
-	breakPoints := boundaryPattern.FindAllStringIndex(strippedText, -1)
-
-	// This helper function adds a new line to the result slice. The provided
-	// positions are in stripped index space.
-	addLine := func(from, to int) {
-		// Shift indices back to original index space.
-		var colorTagIndex, escapeIndex int
-		for colorTagIndex < len(colorTagIndices) && to >= colorTagIndices[colorTagIndex][0] ||
-			escapeIndex < len(escapeIndices) && to >= escapeIndices[escapeIndex][0] {
-			past := 0
-			if colorTagIndex < len(colorTagIndices) {
-				tagWidth := colorTagIndices[colorTagIndex][1] - colorTagIndices[colorTagIndex][0]
-				if colorTagIndices[colorTagIndex][0] < from {
-					from += tagWidth
-					to += tagWidth
-					colorTagIndex++
-				} else if colorTagIndices[colorTagIndex][0] < to {
-					to += tagWidth
-					colorTagIndex++
-				} else {
-					past++
-				}
-			} else {
-				past++
+	if text == "" || width <= 0 {
+		return nil
+	}
+	colorIndices := colorPattern.FindAllStringIndex(text, -1)
+
+	// prefix returns the color tags preceding the given position.
+	prefix := func(pos int) string {
+		var tags string
+		for _, tag := range colorIndices {
+			if tag[0] >= pos {
+				break
 			}
-			if escapeIndex < len(escapeIndices) {
-				tagWidth := escapeIndices[escapeIndex][1] - escapeIndices[escapeIndex][0]
-				if escapeIndices[escapeIndex][0] < from {
-					from += tagWidth
-					to += tagWidth
-					escapeIndex++
-				} else if escapeIndices[escapeIndex][0] < to {
-					to += tagWidth
-					escapeIndex++
-				} else {
-					past++
-				}
-			} else {
-				past++
+			tags += text[tag[0]:tag[1]]
+		}
+		return tags
+	}
+
+	isSpace := func(r visibleRune) bool {
+		return unicode.IsSpace(r.ch)
+	}
+
+	// canBreak returns whether a line may end before the given rune.
+	var starts []bool
+	canBreak := func(runes []visibleRune, index int) bool {
+		previous, next := runes[index-1], runes[index]
+		if next.width == 0 || !starts[index] || isSpace(next) {
+			return false
+		}
+		if unicode.Is(unicode.Ps, previous.ch) {
+			return false // Don't break after opening brackets.
+		}
+		return isSpace(previous) || unicode.IsPunct(previous.ch) || previous.width > 1 || next.width > 1
+	}
+
+	// hyphenation returns the position at which the line starting at "start"
+	// may end with a hyphen, or -1 if there is none. The word to be hyphenated
+	// starts at "end", the current end of the line, or at "start" if the line
+	// consists of a single word.
+	hyphenation := func(runes []visibleRune, start, end int) int {
+		if end >= len(runes) {
+			return -1
+		}
+		wordStart := end
+		if !canBreak(runes, wordStart) {
+			wordStart = start
+		}
+		wordEnd := wordStart + 1
+		for wordEnd < len(runes) && !isSpace(runes[wordEnd]) && !canBreak(runes, wordEnd) {
+			wordEnd++
+		}
+		var (
+			word    []rune
+			lengths []int
+		)
+		for _, r := range runes[wordStart:wordEnd] {
+			word = append(word, r.ch)
+			lengths = append(lengths, utf8.RuneLen(r.ch))
+		}
+
+		// Find the last position which fits.
+		var lineWidth int
+		for _, r := range runes[start:wordStart] {
+			lineWidth += r.width
+		}
+		at, pos, index := -1, 0, wordStart
+		for _, position := range Hyphenate(string(word)) {
+			for index < wordEnd && pos < position {
+				lineWidth += runes[index].width
+				pos += lengths[index-wordStart]
+				index++
 			}
-			if past == 2 {
-				break // All other indices are beyond the requested string.
+			if pos != position || index <= wordStart || index >= wordEnd || !starts[index] {
+				continue
 			}
+			if lineWidth+1 > width {
+				break
+			}
+			at = index
 		}
-		lines = append(lines, text[from:to])
+		return at
 	}
 
-	// Determine final breakpoints.
-	var start, lastEnd, newStart, breakPoint int
-	for {
-		// What's our candidate string?
-		var candidate string
-		if breakPoint < len(breakPoints) {
-			candidate = text[start:breakPoints[breakPoint][1]]
-		} else {
-			candidate = text[start:]
-		}
-		candidate = strings.TrimRightFunc(candidate, unicode.IsSpace)
-
-		if runewidth.StringWidth(candidate) >= width {
-			// We're past the available width.
-			if lastEnd > start {
-				// Use the previous candidate.
-				addLine(start, lastEnd)
-				start = newStart
-			} else {
-				// We have no previous candidate. Make a hard break.
-				var lineWidth int
-				for index, ch := range text {
-					if index < start {
-						continue
-					}
-					chWidth := runewidth.RuneWidth(ch)
-					if lineWidth > 0 && lineWidth+chWidth >= width {
-						addLine(start, index)
-						start = index
-						break
+	// Process each paragraph separately.
+	var offset int
+	for _, paragraph := range strings.Split(text, "\n") {
+		runes := visibleRunes(paragraph)
+		chs := make([]rune, len(runes))
+		for index := range runes {
+			runes[index].from += offset
+			runes[index].to += offset
+			chs[index] = runes[index].ch
+		}
+		starts = graphemeStarts(chs)
+		if len(runes) == 0 {
+			lines = append(lines, prefix(offset))
+		}
+
+		for start := 0; start < len(runes); {
+			// Find the end of this line.
+			var lineWidth int
+			lastBreak := -1
+			index := start
+			for ; index < len(runes); index++ {
+				if index > start && canBreak(runes, index) {
+					lastBreak = index
+				}
+				if index > start && runes[index].width > 0 && lineWidth+runes[index].width > width {
+					break
+				}
+				lineWidth += runes[index].width
+			}
+			end := index
+			if index < len(runes) && lastBreak > start {
+				end = lastBreak
+			}
+			if end < len(runes) && !starts[end] {
+				// Don't split grapheme clusters. If the cluster is wider than
+				// the line, it gets a line of its own.
+				clusterStart := end
+				for clusterStart > start && !starts[clusterStart] {
+					clusterStart--
+				}
+				if clusterStart > start {
+					end = clusterStart
+				} else {
+					for end < len(runes) && !starts[end] {
+						end++
 					}
-					lineWidth += chWidth
 				}
 			}
-		} else {
-			// We haven't hit the right border yet.
-			if breakPoint >= len(breakPoints) {
-				// It's the last line. We're done.
-				if len(candidate) > 0 {
-					addLine(start, len(strippedText))
+
+			// Hyphenate the word which does not fit into the line.
+			var hyphen bool
+			if Hyphenate != nil && index < len(runes) {
+				if at := hyphenation(runes, start, end); at > 0 {
+					end, hyphen = at, true
 				}
-				break
-			} else {
-				// We have a new candidate.
-				lastEnd = start + len(candidate)
-				newStart = breakPoints[breakPoint][1]
-				breakPoint++
+			}
+
+			// Add the line without trailing whitespace.
+			lineEnd := end
+			for lineEnd > start && isSpace(runes[lineEnd-1]) {
+				lineEnd--
+			}
+			line := prefix(runes[start].from)
+			if lineEnd > start {
+				line += text[runes[start].from:runes[lineEnd-1].to]
+			}
+			if hyphen {
+				line += "-"
+			}
+			lines = append(lines, line)
+
+			// Skip whitespace at the beginning of the next line.
+			start = end
+			for start < len(runes) && isSpace(runes[start]) {
+				start++
 			}
 		}
+
+		offset += len(paragraph) + 1
 	}
 
 	return
 }
 
+// TruncateString shortens the given text such that it does not exceed the given
+// screen width. If text needs to be removed, the ellipsis (e.g. "…") is
+// appended and counted towards the width. Color tags are preserved and have no
+// width. Wide characters are never cut in half and grapheme clusters (e.g.
+// characters with combining marks, emoji ZWJ sequences, or flags) are never
+// split. Text which fits is returned unchanged.
+func TruncateString(text string, width int, ellipsis string) string {
+	if StringWidth(text) <= width {
+		return text
+	}
+	runes := visibleRunes(text)
+
+	// Make room for the ellipsis.
+	width -= StringWidth(ellipsis)
+	if width < 0 {
+		return ""
+	}
+
+	// Find the end of the grapheme clusters that fit.
+	chs := make([]rune, len(runes))
+	for index, r := range runes {
+		chs[index] = r.ch
+	}
+	starts := graphemeStarts(chs)
+	var end, lineWidth int
+	for index := 0; index < len(runes); {
+		next, clusterWidth := index+1, runes[index].width
+		for next < len(runes) && !starts[next] {
+			clusterWidth += runes[next].width
+			next++
+		}
+		if lineWidth+clusterWidth > width {
+			break
+		}
+		lineWidth += clusterWidth
+		end = runes[next-1].to
+		index = next
+	}
+	return text[:end] + ellipsis
+}
+
+// JoinGraphics returns the box drawing rune which results from drawing "ch"
+// into a screen cell which already contains "previous", joining the lines of
+// both runes. For example, joining a vertical bar with a horizontal bar results
+// in a cross. Single, double, and thick lines as well as rounded corners are
+// supported. Where the two runes' lines differ in weight, the lines of "ch"
+// win. If either rune is not a box drawing rune, "ch" is returned.
+func JoinGraphics(previous, ch rune) rune {
+	below, ok := graphicsArms[previous]
+	if !ok {
+		return ch
+	}
+	above, ok := graphicsArms[ch]
+	if !ok {
+		return ch
+	}
+	var (
+		joined [4]byte
+		weight byte
+	)
+	for index := range joined {
+		joined[index] = above[index]
+		if above[index] > weight {
+			weight = above[index]
+		}
+		if joined[index] == 0 {
+			joined[index] = below[index]
+		}
+	}
+	if result, ok := graphicsRunes[joined]; ok {
+		return result
+	}
+
+	// There is no rune for this combination of weights. Use the weight of "ch"
+	// for all lines.
+	for index := range joined {
+		if joined[index] != 0 {
+			joined[index] = weight
+		}
+	}
+	if result, ok := graphicsRunes[joined]; ok {
+		return result
+	}
+	return ch
+}
+
 // PrintJoinedBorder prints a border graphics rune into the screen at the given
 // position with the given color, joining it with any existing border graphics
-// rune. Background colors are preserved. At this point, only regular single
-// line borders are supported.
+// rune (see JoinGraphics()). Background colors are preserved.
 func PrintJoinedBorder(screen tcell.Screen, x, y int, ch rune, color tcell.Color) {
 	previous, _, style, _ := screen.GetContent(x, y)
-	style = style.Foreground(color)
+	screen.SetContent(x, y, JoinGraphics(previous, ch), nil, style.Foreground(color))
+}
 
-	// What's the resulting rune?
-	var result rune
-	if ch == previous {
-		result = ch
-	} else {
-		if ch < previous {
-			previous, ch = ch, previous
+// dimRect attenuates the colors of all cells in the given rectangle by
+// multiplying their red, green, and blue components with "factor" (a value
+// between 0 and 1). Cells with default colors are set to the "dim" attribute.
+func dimRect(screen tcell.Screen, x, y, width, height int, factor float64) {
+	if state := appStateOf(screen); state != nil {
+		state.drawSequence++
+		state.dimmedRects = append(state.dimmedRects, dimmedRect{order: state.drawSequence, x: x, y: y, width: width, height: height})
+	}
+	dim := func(color tcell.Color) (tcell.Color, bool) {
+		r, g, b := color.RGB()
+		if color == tcell.ColorDefault || r < 0 || g < 0 || b < 0 {
+			return color, false
 		}
-		result = joints[string(previous)+string(ch)]
+		return tcell.NewRGBColor(int32(float64(r)*factor), int32(float64(g)*factor), int32(float64(b)*factor)), true
 	}
-	if result == 0 {
-		result = ch
+	for cy := y; cy < y+height; cy++ {
+		for cx := x; cx < x+width; cx++ {
+			ch, combining, style, _ := screen.GetContent(cx, cy)
+			fg, bg, _ := style.Decompose()
+			fg, fgDimmed := dim(fg)
+			bg, _ = dim(bg)
+			style = style.Foreground(fg).Background(bg)
+			if !fgDimmed {
+				style = style.Dim(true)
+			}
+			screen.SetContent(cx, cy, ch, combining, style)
+		}
 	}
-
-	// We only print something if we have something.
-	screen.SetContent(x, y, result, nil, style)
 }
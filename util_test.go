@@ -0,0 +1,39 @@
+package tview
+
+import (
+	"testing"
+)
+
+func TestEscape(t *testing.T) {
+	for _, text := range []string{
+		"plain text",
+		"a[1]",
+		"arr[1][2]",
+		"[foo bar]",
+		"[]",
+		"[[x]]",
+		"[red]",
+		"[red]text[-]",
+		"[::b]bold",
+		"[#00ff1a]",
+		`["region"]`,
+		"[red[]",
+		"[red[[]",
+		"[red]]",
+	} {
+		escaped := Escape(text)
+		if stripped := StripTags(escaped); stripped != text {
+			t.Errorf("StripTags(Escape(%q)) = %q (escaped %q)", text, stripped, escaped)
+		}
+		if width := StringWidth(escaped); width != stringWidth(text) {
+			t.Errorf("StringWidth(Escape(%q)) = %d, want %d", text, width, stringWidth(text))
+		}
+	}
+
+	// Plain bracketed text is not changed.
+	for _, text := range []string{"a[1]", "[foo bar]", "file [2].txt", "[x y]"} {
+		if escaped := Escape(text); escaped != text {
+			t.Errorf("Escape(%q) = %q, want it unchanged", text, escaped)
+		}
+	}
+}
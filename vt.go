@@ -0,0 +1,620 @@
+package tview
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell"
+)
+
+// States of the escape sequence parser of vtScreen.
+const (
+	vtGround = iota
+	vtEscape
+	vtCSI
+	vtString
+	vtStringEscape
+	vtCharset
+)
+
+// vtCell is one cell of a vtScreen.
+type vtCell struct {
+	ch         rune // 0 if this cell is covered by a wide character to its left.
+	fg, bg     tcell.Color
+	attributes tcell.AttrMask
+}
+
+// vtScreen emulates the screen of a VT100-like (xterm-compatible) terminal.
+// Output from a program is fed into it with write() and the resulting grid of
+// cells is read from "cells". It supports the escape sequences commonly used
+// by full-screen programs: cursor movement, erasing, scroll regions, inserting
+// and deleting lines and characters, colors (16, 256, and true color), text
+// attributes, and the alternate screen.
+type vtScreen struct {
+	// The screen size.
+	width, height int
+
+	// The visible cells, row by row.
+	cells [][]vtCell
+
+	// The cells of the main screen while the alternate screen is active, nil
+	// otherwise.
+	mainCells [][]vtCell
+
+	// The cursor position.
+	cursorX, cursorY int
+
+	// Whether or not the cursor is visible.
+	cursorVisible bool
+
+	// If true, the cursor is in the last column and the next character
+	// causes a line wrap.
+	wrapPending bool
+
+	// Whether or not lines wrap automatically.
+	autoWrap bool
+
+	// Whether or not the cursor keys send application sequences.
+	applicationCursorKeys bool
+
+	// The scroll region (top and bottom row, both inclusive).
+	scrollTop, scrollBottom int
+
+	// The current colors and attributes for new characters.
+	fg, bg     tcell.Color
+	attributes tcell.AttrMask
+
+	// The cursor position, colors, and attributes saved with ESC 7.
+	savedX, savedY   int
+	savedFg, savedBg tcell.Color
+	savedAttributes  tcell.AttrMask
+
+	// The escape sequence parser's state.
+	state int
+
+	// The parameters of the current CSI sequence.
+	params []int
+
+	// The private marker of the current CSI sequence (e.g. '?'), 0 if none.
+	private byte
+
+	// Incomplete UTF-8 sequences from the last write() call.
+	pending []byte
+
+	// Data to be sent back to the program, e.g. responses to status queries.
+	responses []byte
+}
+
+// newVTScreen returns a new emulated screen of the given size.
+func newVTScreen(width, height int) *vtScreen {
+	s := &vtScreen{
+		cursorVisible: true,
+		autoWrap:      true,
+		fg:            tcell.ColorDefault,
+		bg:            tcell.ColorDefault,
+	}
+	s.resize(width, height)
+	return s
+}
+
+// blank returns an empty cell with the current background color.
+func (s *vtScreen) blank() vtCell {
+	return vtCell{ch: ' ', fg: tcell.ColorDefault, bg: s.bg}
+}
+
+// blankRow returns a new row of empty cells.
+func (s *vtScreen) blankRow() []vtCell {
+	row := make([]vtCell, s.width)
+	for index := range row {
+		row[index] = s.blank()
+	}
+	return row
+}
+
+// resize changes the screen size, keeping as much content as possible.
+func (s *vtScreen) resize(width, height int) {
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+
+	// Drop rows at the top if the cursor would end up below the screen.
+	var shift int
+	if s.cursorY >= height {
+		shift = s.cursorY - height + 1
+	}
+	s.width = width
+	s.cells = s.resizeCells(s.cells, shift, height)
+	if s.mainCells != nil {
+		s.mainCells = s.resizeCells(s.mainCells, 0, height)
+	}
+	s.cursorY -= shift
+	s.height = height
+	s.scrollTop, s.scrollBottom = 0, height-1
+	s.clampCursor()
+}
+
+// resizeCells returns a copy of the given cells with the current width and
+// the given height, starting at row "shift".
+func (s *vtScreen) resizeCells(cells [][]vtCell, shift, height int) [][]vtCell {
+	resized := make([][]vtCell, height)
+	for y := range resized {
+		resized[y] = s.blankRow()
+		if y+shift < len(cells) {
+			copy(resized[y], cells[y+shift])
+		}
+	}
+	return resized
+}
+
+// clampCursor moves the cursor back onto the screen.
+func (s *vtScreen) clampCursor() {
+	if s.cursorX >= s.width {
+		s.cursorX = s.width - 1
+	}
+	if s.cursorX < 0 {
+		s.cursorX = 0
+	}
+	if s.cursorY >= s.height {
+		s.cursorY = s.height - 1
+	}
+	if s.cursorY < 0 {
+		s.cursorY = 0
+	}
+	s.wrapPending = false
+}
+
+// write processes output from the program.
+func (s *vtScreen) write(data []byte) {
+	if len(s.pending) > 0 {
+		data = append(s.pending, data...)
+		s.pending = nil
+	}
+	for index := 0; index < len(data); {
+		b := data[index]
+		if s.state == vtGround && b >= utf8.RuneSelf {
+			if !utf8.FullRune(data[index:]) {
+				s.pending = append([]byte(nil), data[index:]...)
+				return
+			}
+			r, size := utf8.DecodeRune(data[index:])
+			s.put(r)
+			index += size
+			continue
+		}
+		s.process(b)
+		index++
+	}
+}
+
+// process handles a single (ASCII) byte of output.
+func (s *vtScreen) process(b byte) {
+	switch s.state {
+	case vtGround:
+		s.control(b)
+	case vtEscape:
+		s.state = vtGround
+		switch b {
+		case '[':
+			s.state = vtCSI
+			s.params = s.params[:0]
+			s.private = 0
+		case ']', 'P', 'X', '^', '_':
+			s.state = vtString // OSC, DCS, etc. are ignored.
+		case '(', ')', '*', '+':
+			s.state = vtCharset
+		case '7':
+			s.saveCursor()
+		case '8':
+			s.restoreCursor()
+		case 'D':
+			s.lineFeed()
+		case 'E':
+			s.cursorX = 0
+			s.lineFeed()
+		case 'M':
+			s.reverseIndex()
+		case 'c':
+			width, height := s.width, s.height
+			*s = *newVTScreen(width, height)
+		}
+	case vtCSI:
+		switch {
+		case b >= '0' && b <= '9':
+			if len(s.params) == 0 {
+				s.params = append(s.params, 0)
+			}
+			s.params[len(s.params)-1] = s.params[len(s.params)-1]*10 + int(b-'0')
+		case b == ';':
+			if len(s.params) == 0 {
+				s.params = append(s.params, 0)
+			}
+			s.params = append(s.params, 0)
+		case b >= '<' && b <= '?':
+			s.private = b
+		case b >= 0x40 && b <= 0x7e:
+			s.state = vtGround
+			s.csi(b)
+		case b == 0x1b:
+			s.state = vtEscape
+		case b < 0x20:
+			s.control(b)
+		}
+	case vtString:
+		switch b {
+		case 0x07:
+			s.state = vtGround
+		case 0x1b:
+			s.state = vtStringEscape
+		}
+	case vtStringEscape:
+		s.state = vtString
+		if b == '\\' {
+			s.state = vtGround
+		}
+	case vtCharset:
+		s.state = vtGround
+	}
+}
+
+// control handles a byte outside of escape sequences.
+func (s *vtScreen) control(b byte) {
+	switch b {
+	case 0x1b:
+		s.state = vtEscape
+	case '\r':
+		s.cursorX = 0
+		s.wrapPending = false
+	case '\n', '\v', '\f':
+		s.lineFeed()
+	case '\b':
+		if s.cursorX > 0 {
+			s.cursorX--
+		}
+		s.wrapPending = false
+	case '\t':
+		s.cursorX = (s.cursorX/8 + 1) * 8
+		if s.cursorX >= s.width {
+			s.cursorX = s.width - 1
+		}
+	default:
+		if b >= 0x20 && b < 0x7f {
+			s.put(rune(b))
+		}
+	}
+}
+
+// put prints a character at the cursor position and advances the cursor.
+func (s *vtScreen) put(r rune) {
+	width := RuneWidth(r)
+	if width == 0 {
+		return // Combining characters are not supported.
+	}
+	if s.wrapPending || width > 1 && s.cursorX+width > s.width {
+		if s.autoWrap {
+			s.cursorX = 0
+			s.lineFeed()
+		}
+		s.wrapPending = false
+	}
+	if s.cursorX+width > s.width {
+		return // A wide character which doesn't fit.
+	}
+	row := s.cells[s.cursorY]
+	row[s.cursorX] = vtCell{ch: r, fg: s.fg, bg: s.bg, attributes: s.attributes}
+	for offset := 1; offset < width; offset++ {
+		row[s.cursorX+offset] = vtCell{fg: s.fg, bg: s.bg, attributes: s.attributes}
+	}
+	s.cursorX += width
+	if s.cursorX >= s.width {
+		s.cursorX = s.width - 1
+		s.wrapPending = true
+	}
+}
+
+// lineFeed moves the cursor down by one line, scrolling if necessary.
+func (s *vtScreen) lineFeed() {
+	s.wrapPending = false
+	if s.cursorY == s.scrollBottom {
+		s.scrollUp(s.scrollTop, 1)
+	} else if s.cursorY < s.height-1 {
+		s.cursorY++
+	}
+}
+
+// reverseIndex moves the cursor up by one line, scrolling if necessary.
+func (s *vtScreen) reverseIndex() {
+	s.wrapPending = false
+	if s.cursorY == s.scrollTop {
+		s.scrollDown(s.scrollTop, 1)
+	} else if s.cursorY > 0 {
+		s.cursorY--
+	}
+}
+
+// scrollUp moves the rows from "top" to the bottom of the scroll region up by
+// "count" rows, inserting blank rows at the bottom.
+func (s *vtScreen) scrollUp(top, count int) {
+	bottom := s.scrollBottom
+	if top > bottom {
+		return
+	}
+	if count > bottom-top+1 {
+		count = bottom - top + 1
+	}
+	copy(s.cells[top:bottom+1], s.cells[top+count:bottom+1])
+	for y := bottom - count + 1; y <= bottom; y++ {
+		s.cells[y] = s.blankRow()
+	}
+}
+
+// scrollDown moves the rows from "top" to the bottom of the scroll region down
+// by "count" rows, inserting blank rows at the top.
+func (s *vtScreen) scrollDown(top, count int) {
+	bottom := s.scrollBottom
+	if top > bottom {
+		return
+	}
+	if count > bottom-top+1 {
+		count = bottom - top + 1
+	}
+	copy(s.cells[top+count:bottom+1], s.cells[top:bottom+1-count])
+	for y := top; y < top+count; y++ {
+		s.cells[y] = s.blankRow()
+	}
+}
+
+// erase clears the cells of the given row from "from" (inclusive) to "to"
+// (exclusive).
+func (s *vtScreen) erase(y, from, to int) {
+	if from < 0 {
+		from = 0
+	}
+	if to > s.width {
+		to = s.width
+	}
+	for x := from; x < to; x++ {
+		s.cells[y][x] = s.blank()
+	}
+}
+
+// saveCursor saves the cursor position, colors, and attributes.
+func (s *vtScreen) saveCursor() {
+	s.savedX, s.savedY = s.cursorX, s.cursorY
+	s.savedFg, s.savedBg, s.savedAttributes = s.fg, s.bg, s.attributes
+}
+
+// restoreCursor restores the state saved with saveCursor().
+func (s *vtScreen) restoreCursor() {
+	s.cursorX, s.cursorY = s.savedX, s.savedY
+	s.fg, s.bg, s.attributes = s.savedFg, s.savedBg, s.savedAttributes
+	s.clampCursor()
+}
+
+// param returns the CSI parameter with the given index or the default value
+// if it is missing or 0.
+func (s *vtScreen) param(index, def int) int {
+	if index >= len(s.params) || s.params[index] == 0 {
+		return def
+	}
+	return s.params[index]
+}
+
+// csi executes a CSI sequence with the given final byte.
+func (s *vtScreen) csi(final byte) {
+	if s.private != 0 && s.private != '?' {
+		return // Not supported.
+	}
+	if s.private == '?' {
+		if final == 'h' || final == 'l' {
+			for _, mode := range s.params {
+				s.setMode(mode, final == 'h')
+			}
+		}
+		return
+	}
+
+	n := s.param(0, 1)
+	switch final {
+	case 'A':
+		s.cursorY -= n
+		s.clampCursor()
+	case 'B', 'e':
+		s.cursorY += n
+		s.clampCursor()
+	case 'C', 'a':
+		s.cursorX += n
+		s.clampCursor()
+	case 'D':
+		s.cursorX -= n
+		s.clampCursor()
+	case 'E':
+		s.cursorX, s.cursorY = 0, s.cursorY+n
+		s.clampCursor()
+	case 'F':
+		s.cursorX, s.cursorY = 0, s.cursorY-n
+		s.clampCursor()
+	case 'G', '`':
+		s.cursorX = n - 1
+		s.clampCursor()
+	case 'd':
+		s.cursorY = n - 1
+		s.clampCursor()
+	case 'H', 'f':
+		s.cursorY, s.cursorX = n-1, s.param(1, 1)-1
+		s.clampCursor()
+	case 'J':
+		switch s.param(0, 0) {
+		case 0:
+			s.erase(s.cursorY, s.cursorX, s.width)
+			for y := s.cursorY + 1; y < s.height; y++ {
+				s.erase(y, 0, s.width)
+			}
+		case 1:
+			s.erase(s.cursorY, 0, s.cursorX+1)
+			for y := 0; y < s.cursorY; y++ {
+				s.erase(y, 0, s.width)
+			}
+		case 2, 3:
+			for y := 0; y < s.height; y++ {
+				s.erase(y, 0, s.width)
+			}
+		}
+	case 'K':
+		switch s.param(0, 0) {
+		case 0:
+			s.erase(s.cursorY, s.cursorX, s.width)
+		case 1:
+			s.erase(s.cursorY, 0, s.cursorX+1)
+		case 2:
+			s.erase(s.cursorY, 0, s.width)
+		}
+	case 'L':
+		if s.cursorY >= s.scrollTop && s.cursorY <= s.scrollBottom {
+			s.scrollDown(s.cursorY, n)
+			s.cursorX = 0
+		}
+	case 'M':
+		if s.cursorY >= s.scrollTop && s.cursorY <= s.scrollBottom {
+			s.scrollUp(s.cursorY, n)
+			s.cursorX = 0
+		}
+	case '@':
+		row := s.cells[s.cursorY]
+		if n > s.width-s.cursorX {
+			n = s.width - s.cursorX
+		}
+		copy(row[s.cursorX+n:], row[s.cursorX:])
+		s.erase(s.cursorY, s.cursorX, s.cursorX+n)
+	case 'P':
+		row := s.cells[s.cursorY]
+		if n > s.width-s.cursorX {
+			n = s.width - s.cursorX
+		}
+		copy(row[s.cursorX:], row[s.cursorX+n:])
+		s.erase(s.cursorY, s.width-n, s.width)
+	case 'X':
+		s.erase(s.cursorY, s.cursorX, s.cursorX+n)
+	case 'S':
+		s.scrollUp(s.scrollTop, n)
+	case 'T':
+		s.scrollDown(s.scrollTop, n)
+	case 'm':
+		s.sgr()
+	case 'r':
+		top, bottom := s.param(0, 1)-1, s.param(1, s.height)-1
+		if top < bottom && bottom < s.height {
+			s.scrollTop, s.scrollBottom = top, bottom
+			s.cursorX, s.cursorY = 0, 0
+			s.wrapPending = false
+		}
+	case 's':
+		s.saveCursor()
+	case 'u':
+		s.restoreCursor()
+	case 'n':
+		switch s.param(0, 0) {
+		case 5:
+			s.responses = append(s.responses, "\x1b[0n"...)
+		case 6:
+			s.responses = append(s.responses, fmt.Sprintf("\x1b[%d;%dR", s.cursorY+1, s.cursorX+1)...)
+		}
+	case 'c':
+		s.responses = append(s.responses, "\x1b[?1;2c"...)
+	}
+}
+
+// setMode sets or resets a DEC private mode.
+func (s *vtScreen) setMode(mode int, set bool) {
+	switch mode {
+	case 1:
+		s.applicationCursorKeys = set
+	case 7:
+		s.autoWrap = set
+	case 25:
+		s.cursorVisible = set
+	case 47, 1047, 1049:
+		if set == (s.mainCells != nil) {
+			return // Already in the requested mode.
+		}
+		if set {
+			if mode == 1049 {
+				s.saveCursor()
+			}
+			s.mainCells = s.cells
+			s.cells = make([][]vtCell, s.height)
+			for y := range s.cells {
+				s.cells[y] = s.blankRow()
+			}
+		} else {
+			s.cells, s.mainCells = s.mainCells, nil
+			if mode == 1049 {
+				s.restoreCursor()
+			}
+		}
+	}
+}
+
+// sgr processes a "select graphic rendition" sequence, i.e. changes colors
+// and attributes.
+func (s *vtScreen) sgr() {
+	params := s.params
+	if len(params) == 0 {
+		params = []int{0}
+	}
+	for index := 0; index < len(params); index++ {
+		switch p := params[index]; {
+		case p == 0:
+			s.fg, s.bg, s.attributes = tcell.ColorDefault, tcell.ColorDefault, 0
+		case p == 1:
+			s.attributes |= tcell.AttrBold
+		case p == 2:
+			s.attributes |= tcell.AttrDim
+		case p == 4:
+			s.attributes |= tcell.AttrUnderline
+		case p == 5:
+			s.attributes |= tcell.AttrBlink
+		case p == 7:
+			s.attributes |= tcell.AttrReverse
+		case p == 22:
+			s.attributes &^= tcell.AttrBold | tcell.AttrDim
+		case p == 24:
+			s.attributes &^= tcell.AttrUnderline
+		case p == 25:
+			s.attributes &^= tcell.AttrBlink
+		case p == 27:
+			s.attributes &^= tcell.AttrReverse
+		case p >= 30 && p <= 37:
+			s.fg = tcell.Color(p - 30)
+		case p == 39:
+			s.fg = tcell.ColorDefault
+		case p >= 40 && p <= 47:
+			s.bg = tcell.Color(p - 40)
+		case p == 49:
+			s.bg = tcell.ColorDefault
+		case p >= 90 && p <= 97:
+			s.fg = tcell.Color(p - 90 + 8)
+		case p >= 100 && p <= 107:
+			s.bg = tcell.Color(p - 100 + 8)
+		case p == 38 || p == 48:
+			// Extended colors.
+			var color tcell.Color
+			if index+2 < len(params) && params[index+1] == 5 {
+				color = tcell.Color(params[index+2] & 0xff)
+				index += 2
+			} else if index+4 < len(params) && params[index+1] == 2 {
+				color = tcell.NewRGBColor(int32(params[index+2]), int32(params[index+3]), int32(params[index+4]))
+				index += 4
+			} else {
+				return
+			}
+			if p == 38 {
+				s.fg = color
+			} else {
+				s.bg = color
+			}
+		}
+	}
+}
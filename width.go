@@ -0,0 +1,163 @@
+package tview
+
+import (
+	"sync"
+
+	runewidth "github.com/mattn/go-runewidth"
+)
+
+// WidthPolicy determines the screen width of characters whose width depends on
+// the terminal and its font. If the widths calculated by this package differ
+// from the ones used by the terminal, text is shifted and columns drift apart.
+// See SetWidthPolicy().
+type WidthPolicy struct {
+	// If true, East Asian characters of ambiguous width (e.g. "±", "①", or
+	// Greek and Cyrillic letters) are two cells wide. Otherwise they are one
+	// cell wide.
+	AmbiguousWide bool
+
+	// If true, symbols which may be shown as text or as emoji (e.g. "☀", "❤",
+	// or "✔") are two cells wide, as in terminals which draw them as emoji.
+	// Otherwise their width is determined by their East Asian width. Emoji
+	// which are always shown as emoji (e.g. "😀") are two cells wide regardless
+	// of this setting.
+	EmojiWide bool
+}
+
+// widthPolicy is the current width policy. By default, the width of ambiguous
+// characters follows the locale.
+var widthPolicy = WidthPolicy{AmbiguousWide: runewidth.EastAsianWidth}
+
+// widthGeneration is incremented each time the width policy changes. It
+// invalidates widths calculated before.
+var widthGeneration = 1
+
+// widthCacheSize is the maximum number of entries in the width cache.
+const widthCacheSize = 4096
+
+// widthKey identifies a text in the width cache. If "regions" is true, region
+// tags are skipped, too (see TaggedStringWidth()).
+type widthKey struct {
+	text    string
+	regions bool
+}
+
+// widthCache contains the widths of texts with tags, which are expensive to
+// calculate. It is emptied when it is full and when the width policy changes.
+var widthCache = struct {
+	sync.Mutex
+	widths map[widthKey]int
+}{widths: make(map[widthKey]int)}
+
+// SetWidthPolicy sets the policy for characters whose width depends on the
+// terminal. All width calculations of this package respect it. The width of
+// ambiguous characters is also passed on to the screen. The policy should be
+// set before the application is started, otherwise call Application.Draw()
+// afterwards.
+func SetWidthPolicy(policy WidthPolicy) {
+	widthPolicy = policy
+	widthGeneration++
+	widthCache.Lock()
+	widthCache.widths = make(map[widthKey]int)
+	widthCache.Unlock()
+	runewidth.EastAsianWidth = policy.AmbiguousWide
+	runewidth.DefaultCondition.EastAsianWidth = policy.AmbiguousWide
+}
+
+// GetWidthPolicy returns the current width policy.
+func GetWidthPolicy() WidthPolicy {
+	return widthPolicy
+}
+
+// RuneWidth returns the number of screen cells occupied by the given
+// character, according to the current width policy (see SetWidthPolicy()).
+// Custom primitives should use this function to calculate widths.
+func RuneWidth(ch rune) int {
+	width := runewidth.RuneWidth(ch)
+	switch {
+	case width == 0:
+	case widthPolicy.EmojiWide && isEmojiSymbol(ch):
+		width = 2
+	case runewidth.IsAmbiguousWidth(ch):
+		width = 1
+		if widthPolicy.AmbiguousWide {
+			width = 2
+		}
+	}
+	return width
+}
+
+// isEmojiSymbol returns whether the given character is a symbol which may be
+// shown as text or as an emoji.
+func isEmojiSymbol(ch rune) bool {
+	return ch == 0x00a9 || ch == 0x00ae || // Copyright and registered signs.
+		ch >= 0x2190 && ch <= 0x21ff || // Arrows.
+		ch >= 0x2300 && ch <= 0x23ff || // Miscellaneous technical.
+		ch >= 0x25a0 && ch <= 0x27bf || // Geometric shapes, miscellaneous symbols, dingbats.
+		ch >= 0x2b00 && ch <= 0x2bff || // Miscellaneous symbols and arrows.
+		ch >= 0x1f000 && ch <= 0x1f2ff // Game symbols and enclosed characters.
+}
+
+// stringWidth returns the screen width of the given text. Unlike
+// StringWidth(), color tags are not removed.
+func stringWidth(text string) (width int) {
+	for _, ch := range text {
+		width += RuneWidth(ch)
+	}
+	return
+}
+
+// taggedWidth returns the screen width of the given text, skipping color tags
+// and, if "regions" is true, region tags. Widths of texts which may contain
+// tags are cached.
+func taggedWidth(text string, regions bool) int {
+	if !hasTags(text) {
+		return stringWidth(text) // No tags.
+	}
+	key := widthKey{text: text, regions: regions}
+	widthCache.Lock()
+	defer widthCache.Unlock()
+	if width, ok := widthCache.widths[key]; ok {
+		return width
+	}
+	if regions {
+		text = regionPattern.ReplaceAllString(text, "")
+	}
+	width := stringWidth(StripTags(text))
+	if len(widthCache.widths) >= widthCacheSize {
+		widthCache.widths = make(map[widthKey]int)
+	}
+	widthCache.widths[key] = width
+	return width
+}
+
+// textWidth holds the screen width of a text with color tags (see
+// StringWidth()). It is calculated when the text is set and recalculated
+// only when the text or the width policy changes.
+type textWidth struct {
+	text       string
+	width      int
+	generation int
+}
+
+// get returns the width of the given text.
+func (w *textWidth) get(text string) int {
+	if w.generation != widthGeneration || w.text != text {
+		w.text, w.width, w.generation = text, StringWidth(text), widthGeneration
+	}
+	return w.width
+}
+
+// truncateWidth returns the longest prefix of the given text (without color
+// tags) which does not exceed the given screen width.
+func truncateWidth(text string, width int) string {
+	var total int
+	for pos, ch := range text {
+		w := RuneWidth(ch)
+		if total+w > width {
+			return text[:pos]
+		}
+		total += w
+	}
+	return text
+}
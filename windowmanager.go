@@ -0,0 +1,691 @@
+package tview
+
+import (
+	"github.com/gdamore/tcell"
+)
+
+// Window is a titled, bordered frame around a primitive which is shown inside
+// a WindowManager. Its position and size are relative to the window manager's
+// inner rectangle.
+type Window struct {
+	*Box
+
+	// The primitive shown inside the window.
+	content Primitive
+
+	// The window's position and size relative to the window manager.
+	left, top, columns, rows int
+
+	// Whether or not the window is minimized or maximized.
+	minimized, maximized bool
+}
+
+// NewWindow returns a new window with the given content. Use SetTitle() to
+// give it a title.
+func NewWindow(content Primitive) *Window {
+	w := &Window{
+		Box:     NewBox().SetBorder(true),
+		content: content,
+		columns: 40,
+		rows:    10,
+	}
+	w.focus = w
+	return w
+}
+
+// SetContent sets the primitive shown inside the window.
+func (w *Window) SetContent(content Primitive) *Window {
+	w.content = content
+	return w
+}
+
+// GetContent returns the primitive shown inside the window.
+func (w *Window) GetContent() Primitive {
+	return w.content
+}
+
+// SetPosition sets the position of the window's top-left corner relative to
+// the window manager.
+func (w *Window) SetPosition(x, y int) *Window {
+	w.left, w.top = x, y
+	return w
+}
+
+// GetPosition returns the position of the window's top-left corner relative to
+// the window manager.
+func (w *Window) GetPosition() (x, y int) {
+	return w.left, w.top
+}
+
+// SetSize sets the window's width and height, including its border.
+func (w *Window) SetSize(width, height int) *Window {
+	w.columns, w.rows = width, height
+	return w
+}
+
+// GetSize returns the window's width and height, including its border.
+func (w *Window) GetSize() (width, height int) {
+	return w.columns, w.rows
+}
+
+// IsMinimized returns whether or not the window is minimized.
+func (w *Window) IsMinimized() bool {
+	return w.minimized
+}
+
+// IsMaximized returns whether or not the window is maximized.
+func (w *Window) IsMaximized() bool {
+	return w.maximized
+}
+
+// GetChildren returns the window's content.
+func (w *Window) GetChildren() []Primitive {
+	if w.content == nil {
+		return nil
+	}
+	return []Primitive{w.content}
+}
+
+// Draw draws this primitive onto the screen.
+func (w *Window) Draw(screen tcell.Screen) {
+	defer w.applyStyle()()
+	w.Box.Draw(screen)
+
+	// Draw the minimize and maximize buttons into the title bar.
+	x, y, width, _ := w.getBorderRect()
+	if width >= 8 {
+		style := tcell.StyleDefault.Background(w.backgroundColor).Foreground(w.titleColor)
+		screen.SetContent(x+width-4, y, '_', nil, style)
+		screen.SetContent(x+width-2, y, '□', nil, style)
+	}
+
+	if w.content != nil {
+		x, y, width, height := w.GetInnerRect()
+		w.content.SetRect(x, y, width, height)
+		w.content.Draw(screen)
+	}
+}
+
+// Focus is called when this primitive receives focus.
+func (w *Window) Focus(delegate func(p Primitive)) {
+	if w.content != nil {
+		delegate(w.content)
+		return
+	}
+	w.hasFocus = true
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (w *Window) HasFocus() bool {
+	if w.content != nil {
+		return w.content.GetFocusable().HasFocus()
+	}
+	return w.hasFocus
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (w *Window) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return w.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if !w.InRect(event.Position()) {
+			return false, nil
+		}
+		if w.content != nil {
+			if handler := mouseHandler(w.content); handler != nil {
+				if consumed, capture = handler(action, event, setFocus); consumed {
+					return
+				}
+			}
+		}
+		if action == MouseLeftClick {
+			setFocus(w)
+		}
+		return true, nil // The window hides whatever is below it.
+	})
+}
+
+// Window manager drag modes.
+const (
+	windowDragNone = iota
+	windowDragMove
+	windowDragResize
+)
+
+// WindowManager is a container for floating windows (see NewWindow()) which
+// can be moved, resized, minimized, and maximized. Windows are stacked on top
+// of each other, the last window being the topmost one. The window which
+// receives focus is brought to the front.
+//
+// With the mouse, windows are moved by dragging their title bar and resized by
+// dragging their bottom-right corner. The "_" and "□" buttons in the title bar
+// minimize and maximize a window. Double-clicking the title bar also toggles
+// the maximized state. Minimized windows are listed in a task bar at the
+// bottom where they can be clicked to restore them.
+//
+// For keyboard interaction, pass key events to HandleKey(), e.g. from
+// Application.SetInputCapture(). When the window mode key (see
+// SetWindowModeKey(), Ctrl-W by default) is pressed, the window manager enters
+// a window mode in which it receives all key events and the following keys are
+// available:
+//
+//   - Arrow keys, h, j, k, l: Move the topmost window.
+//   - Shift-arrow keys, H, J, K, L: Resize the topmost window.
+//   - Tab, Backtab: Focus the next or previous window, restoring it if it was
+//     minimized.
+//   - m: Minimize the topmost window.
+//   - z: Maximize the topmost window or restore it if it is maximized.
+//   - Escape, Enter, window mode key: Leave the window mode.
+type WindowManager struct {
+	*Box
+
+	// The windows, from bottom to top.
+	windows []*Window
+
+	// Whether or not the window mode is active.
+	windowMode bool
+
+	// The key and rune which toggle the window mode in HandleKey().
+	modeKey  tcell.Key
+	modeRune rune
+
+	// The window currently being dragged with the mouse, and what the drag
+	// does.
+	dragWindow *Window
+	dragMode   int
+
+	// The mouse position where the current drag started and the window's
+	// position or size at that time.
+	dragX, dragY, dragLeft, dragTop int
+
+	// The colors of the task bar.
+	taskBarTextColor, taskBarBackgroundColor, modeColor tcell.Color
+
+	// A function which sets the focus to a primitive.
+	setFocus func(p Primitive)
+}
+
+// NewWindowManager returns a new window manager without any windows.
+func NewWindowManager() *WindowManager {
+	styles := Styles.forWidget("WindowManager")
+	m := &WindowManager{
+		Box:                    NewBox(),
+		modeKey:                tcell.KeyCtrlW,
+		taskBarTextColor:       styles.PrimaryTextColor,
+		taskBarBackgroundColor: styles.ContrastBackgroundColor,
+		modeColor:              styles.SecondaryTextColor,
+	}
+	m.focus = m
+	return m
+}
+
+// AddWindow adds a window on top of all other windows. If the window manager
+// has focus, the new window receives it.
+func (m *WindowManager) AddWindow(window *Window) *WindowManager {
+	m.windows = append(m.windows, window)
+	if m.HasFocus() && m.setFocus != nil {
+		m.setFocus(window)
+	}
+	return m
+}
+
+// RemoveWindow removes the given window. If it had focus, the focus moves to
+// the topmost remaining window.
+func (m *WindowManager) RemoveWindow(window *Window) *WindowManager {
+	hasFocus := window.HasFocus()
+	for index, w := range m.windows {
+		if w == window {
+			m.windows = append(m.windows[:index], m.windows[index+1:]...)
+			break
+		}
+	}
+	if m.dragWindow == window {
+		m.dragWindow, m.dragMode = nil, windowDragNone
+	}
+	if hasFocus {
+		m.focusTop()
+	}
+	return m
+}
+
+// GetWindows returns the windows in the order in which they are stacked, the
+// topmost window being the last one.
+func (m *WindowManager) GetWindows() []*Window {
+	return m.windows
+}
+
+// FocusWindow brings the given window to the front, restores it if it is
+// minimized, and gives it focus.
+func (m *WindowManager) FocusWindow(window *Window) *WindowManager {
+	window.minimized = false
+	m.toFront(window)
+	if m.setFocus != nil {
+		m.setFocus(window)
+	}
+	return m
+}
+
+// MinimizeWindow minimizes the given window, hiding it and adding it to the
+// task bar. If it had focus, the focus moves to the topmost remaining window.
+func (m *WindowManager) MinimizeWindow(window *Window) *WindowManager {
+	hasFocus := window.HasFocus()
+	window.minimized = true
+	if hasFocus {
+		m.focusTop()
+	}
+	return m
+}
+
+// MaximizeWindow maximizes the given window so that it fills the entire
+// window manager, brings it to the front, and gives it focus.
+func (m *WindowManager) MaximizeWindow(window *Window) *WindowManager {
+	window.maximized = true
+	return m.FocusWindow(window)
+}
+
+// RestoreWindow restores the given window from its minimized or maximized
+// state, brings it to the front, and gives it focus.
+func (m *WindowManager) RestoreWindow(window *Window) *WindowManager {
+	window.maximized = false
+	return m.FocusWindow(window)
+}
+
+// SetWindowModeKey sets the key which toggles the window mode in HandleKey().
+// If "key" is tcell.KeyRune, the window mode is toggled with the rune "ch".
+// The default is tcell.KeyCtrlW.
+func (m *WindowManager) SetWindowModeKey(key tcell.Key, ch rune) *WindowManager {
+	m.modeKey, m.modeRune = key, ch
+	return m
+}
+
+// SetTaskBarColors sets the text and background color of the task bar.
+func (m *WindowManager) SetTaskBarColors(textColor, backgroundColor tcell.Color) *WindowManager {
+	m.taskBarTextColor, m.taskBarBackgroundColor = textColor, backgroundColor
+	return m
+}
+
+// SetWindowModeColor sets the color of the window mode indicator shown in the
+// task bar.
+func (m *WindowManager) SetWindowModeColor(color tcell.Color) *WindowManager {
+	m.modeColor = color
+	return m
+}
+
+// IsWindowMode returns whether or not the window mode is active.
+func (m *WindowManager) IsWindowMode() bool {
+	return m.windowMode
+}
+
+// toFront moves the given window to the top of the stack.
+func (m *WindowManager) toFront(window *Window) {
+	for index, w := range m.windows {
+		if w == window {
+			m.windows = append(append(m.windows[:index], m.windows[index+1:]...), window)
+			return
+		}
+	}
+}
+
+// topWindow returns the topmost window which is not minimized, or nil if there
+// is none.
+func (m *WindowManager) topWindow() *Window {
+	for index := len(m.windows) - 1; index >= 0; index-- {
+		if !m.windows[index].minimized {
+			return m.windows[index]
+		}
+	}
+	return nil
+}
+
+// focusTop gives focus to the topmost window which is not minimized, or to
+// the window manager itself if there is none.
+func (m *WindowManager) focusTop() {
+	if m.setFocus == nil {
+		return
+	}
+	if top := m.topWindow(); top != nil {
+		m.setFocus(top)
+	} else {
+		m.setFocus(m)
+	}
+}
+
+// cycle focuses the next (or previous, if "forward" is false) window.
+func (m *WindowManager) cycle(forward bool) {
+	if len(m.windows) == 0 {
+		return
+	}
+	var window *Window
+	if forward {
+		// The bottommost window comes to the top.
+		window = m.windows[0]
+	} else {
+		// The topmost window goes to the bottom.
+		top := m.topWindow()
+		if top == nil {
+			window = m.windows[len(m.windows)-1]
+		} else {
+			for index, w := range m.windows {
+				if w == top {
+					m.windows = append(m.windows[:index], m.windows[index+1:]...)
+					break
+				}
+			}
+			m.windows = append([]*Window{top}, m.windows...)
+			window = m.topWindow()
+			if window == nil {
+				window = top
+			}
+		}
+	}
+	m.FocusWindow(window)
+}
+
+// HandleKey toggles the window mode when the given key event corresponds to
+// the window mode key (see SetWindowModeKey()) and handles all key events
+// while the window mode is active. It returns true if the event was handled.
+// This is meant to be called from Application.SetInputCapture().
+func (m *WindowManager) HandleKey(event *tcell.EventKey) bool {
+	key := event.Key()
+	isModeKey := key == m.modeKey && (key != tcell.KeyRune || event.Rune() == m.modeRune)
+	if !m.windowMode {
+		if isModeKey {
+			m.windowMode = true
+			return true
+		}
+		return false
+	}
+	if isModeKey {
+		m.windowMode = false
+		return true
+	}
+
+	window := m.topWindow()
+	move := func(dx, dy int) {
+		if window == nil {
+			return
+		}
+		if event.Modifiers()&tcell.ModShift != 0 || key == tcell.KeyRune && event.Rune() >= 'A' && event.Rune() <= 'Z' {
+			window.columns += dx
+			window.rows += dy
+		} else {
+			window.left += dx
+			window.top += dy
+		}
+		window.maximized = false
+	}
+	switch key {
+	case tcell.KeyEscape, tcell.KeyEnter:
+		m.windowMode = false
+	case tcell.KeyLeft:
+		move(-1, 0)
+	case tcell.KeyRight:
+		move(1, 0)
+	case tcell.KeyUp:
+		move(0, -1)
+	case tcell.KeyDown:
+		move(0, 1)
+	case tcell.KeyTab:
+		m.cycle(true)
+	case tcell.KeyBacktab:
+		m.cycle(false)
+	case tcell.KeyRune:
+		switch event.Rune() {
+		case 'h', 'H':
+			move(-1, 0)
+		case 'l', 'L':
+			move(1, 0)
+		case 'k', 'K':
+			move(0, -1)
+		case 'j', 'J':
+			move(0, 1)
+		case 'm':
+			if window != nil {
+				m.MinimizeWindow(window)
+			}
+		case 'z':
+			if window != nil {
+				if window.maximized {
+					m.RestoreWindow(window)
+				} else {
+					m.MaximizeWindow(window)
+				}
+			}
+		}
+	}
+	return true
+}
+
+// taskBarVisible returns whether or not the task bar needs to be shown.
+func (m *WindowManager) taskBarVisible() bool {
+	if m.windowMode {
+		return true
+	}
+	for _, window := range m.windows {
+		if window.minimized {
+			return true
+		}
+	}
+	return false
+}
+
+// GetChildren returns the windows, from bottom to top.
+func (m *WindowManager) GetChildren() []Primitive {
+	children := make([]Primitive, 0, len(m.windows))
+	for _, window := range m.windows {
+		children = append(children, window)
+	}
+	return children
+}
+
+// applyTheme is called when the application's theme changes.
+func (m *WindowManager) applyTheme(from, to *Theme) {
+	m.Box.applyTheme(from, to)
+	themeColor(&m.taskBarTextColor, from.PrimaryTextColor, to.PrimaryTextColor)
+	themeColor(&m.taskBarBackgroundColor, from.ContrastBackgroundColor, to.ContrastBackgroundColor)
+	themeColor(&m.modeColor, from.SecondaryTextColor, to.SecondaryTextColor)
+}
+
+// Draw draws this primitive onto the screen.
+func (m *WindowManager) Draw(screen tcell.Screen) {
+	m.Box.Draw(screen)
+	x, y, width, height := m.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+	taskBar := m.taskBarVisible()
+	if taskBar {
+		height--
+	}
+
+	// The focused window is always the topmost one.
+	for _, window := range m.windows {
+		if !window.minimized && window.HasFocus() {
+			m.toFront(window)
+			break
+		}
+	}
+
+	// Draw the windows.
+	for _, window := range m.windows {
+		if window.minimized {
+			continue
+		}
+		if window.maximized {
+			window.SetRect(x, y, width, height)
+			window.Draw(screen)
+			continue
+		}
+
+		// Keep the window within the available space.
+		if window.columns < 8 {
+			window.columns = 8
+		}
+		if window.rows < 3 {
+			window.rows = 3
+		}
+		if window.left > width-window.columns {
+			window.left = width - window.columns
+		}
+		if window.top > height-window.rows {
+			window.top = height - window.rows
+		}
+		if window.left < 0 {
+			window.left = 0
+		}
+		if window.top < 0 {
+			window.top = 0
+		}
+		windowWidth, windowHeight := window.columns, window.rows
+		if windowWidth > width {
+			windowWidth = width
+		}
+		if windowHeight > height {
+			windowHeight = height
+		}
+		window.SetRect(x+window.left, y+window.top, windowWidth, windowHeight)
+		window.Draw(screen)
+	}
+
+	// Draw the task bar.
+	if !taskBar {
+		return
+	}
+	style := tcell.StyleDefault.Background(m.taskBarBackgroundColor).Foreground(m.taskBarTextColor)
+	for column := x; column < x+width; column++ {
+		screen.SetContent(column, y+height, ' ', nil, style)
+	}
+	column := x
+	if m.windowMode {
+		modeStyle := tcell.StyleDefault.Background(m.modeColor).Foreground(m.taskBarBackgroundColor)
+		for _, r := range " WINDOW " {
+			if column < x+width {
+				screen.SetContent(column, y+height, r, nil, modeStyle)
+			}
+			column++
+		}
+		column++
+	}
+	for _, window := range m.windows {
+		if !window.minimized || column >= x+width {
+			continue
+		}
+		_, printed := Print(screen, "<"+Escape(window.title)+"> ", column, y+height, x+width-column, AlignLeft, m.taskBarTextColor)
+		column += printed
+	}
+}
+
+// Focus is called when this primitive receives focus.
+func (m *WindowManager) Focus(delegate func(p Primitive)) {
+	m.setFocus = delegate
+	if top := m.topWindow(); top != nil {
+		delegate(top)
+		return
+	}
+	m.hasFocus = true
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (m *WindowManager) HasFocus() bool {
+	for _, window := range m.windows {
+		if !window.minimized && window.HasFocus() {
+			return true
+		}
+	}
+	return m.hasFocus
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (m *WindowManager) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return m.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		mouseX, mouseY := event.Position()
+
+		// Moving or resizing a window.
+		if m.dragWindow != nil {
+			switch action {
+			case MouseMove:
+				if m.dragMode == windowDragMove {
+					m.dragWindow.left = m.dragLeft + mouseX - m.dragX
+					m.dragWindow.top = m.dragTop + mouseY - m.dragY
+				} else {
+					m.dragWindow.columns = m.dragLeft + mouseX - m.dragX
+					m.dragWindow.rows = m.dragTop + mouseY - m.dragY
+				}
+				return true, m
+			case MouseLeftUp:
+				m.dragWindow, m.dragMode = nil, windowDragNone
+				return true, nil
+			}
+		}
+
+		if !m.InRect(mouseX, mouseY) {
+			return false, nil
+		}
+
+		// Clicks on the task bar restore minimized windows.
+		x, y, _, height := m.GetInnerRect()
+		if m.taskBarVisible() && mouseY == y+height-1 {
+			if action == MouseLeftClick {
+				column := x
+				if m.windowMode {
+					column += 9
+				}
+				for _, window := range m.windows {
+					if !window.minimized {
+						continue
+					}
+					column += StringWidth(window.title) + 3
+					if mouseX < column {
+						m.RestoreWindow(window)
+						break
+					}
+				}
+			}
+			return true, nil
+		}
+
+		// Find the topmost window under the mouse.
+		for index := len(m.windows) - 1; index >= 0; index-- {
+			window := m.windows[index]
+			if window.minimized || !window.InRect(mouseX, mouseY) {
+				continue
+			}
+			wx, wy, ww, wh := window.getBorderRect()
+			switch action {
+			case MouseLeftDown:
+				m.toFront(window)
+				if mouseY == wy && ww >= 8 && mouseX == wx+ww-4 {
+					m.MinimizeWindow(window)
+					return true, nil
+				} else if mouseY == wy && ww >= 8 && mouseX == wx+ww-2 {
+					if window.maximized {
+						m.RestoreWindow(window)
+					} else {
+						m.MaximizeWindow(window)
+					}
+					return true, nil
+				} else if mouseY == wy && !window.maximized {
+					m.dragWindow, m.dragMode = window, windowDragMove
+					m.dragX, m.dragY, m.dragLeft, m.dragTop = mouseX, mouseY, window.left, window.top
+					setFocus(window)
+					return true, m
+				} else if mouseX == wx+ww-1 && mouseY == wy+wh-1 && !window.maximized {
+					m.dragWindow, m.dragMode = window, windowDragResize
+					m.dragX, m.dragY, m.dragLeft, m.dragTop = mouseX, mouseY, window.columns, window.rows
+					setFocus(window)
+					return true, m
+				}
+			case MouseLeftDoubleClick:
+				if mouseY == wy {
+					if window.maximized {
+						m.RestoreWindow(window)
+					} else {
+						m.MaximizeWindow(window)
+					}
+					return true, nil
+				}
+			}
+			return window.MouseHandler()(action, event, setFocus)
+		}
+
+		return
+	})
+}
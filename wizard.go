@@ -0,0 +1,434 @@
+package tview
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell"
+)
+
+// wizardStep represents one step of a Wizard.
+type wizardStep struct {
+	Title    string      // The step's title, shown in the header.
+	Item     Primitive   // The step's primitive.
+	Validate func() bool // An optional function which decides whether the user may leave the step forward.
+}
+
+// Wizard is a container which guides the user through a sequence of steps, for
+// example in installers or setup flows. Only the current step's primitive is
+// visible at a time. A header shows the step's title and the progress ("Step
+// 2 of 5") and a button row at the bottom lets the user go back, go to the next
+// step, or cancel. On the last step, the "Next" button becomes a "Finish"
+// button.
+//
+// Each step may have a validation function (see AddStep()). The user can only
+// proceed to the next step (or finish) if it returns true. Going back is always
+// possible.
+//
+// When the wizard receives focus, it is passed on to the current step's
+// primitive. Call FocusButtons() (e.g. from the "done" handler of a form on
+// the step) to move the focus to the button row. Tab and Backtab move between
+// the buttons, leaving the button row returns the focus to the step. Escape on
+// the button row invokes the "done" handler (see SetDoneFunc()).
+type Wizard struct {
+	*Box
+
+	// The steps.
+	steps []*wizardStep
+
+	// The index of the current step, -1 if there are no steps.
+	currentStep int
+
+	// The "Back", "Next", and "Cancel" buttons.
+	backButton, nextButton, cancelButton *Button
+
+	// The labels of the "Next" button on the last step and on other steps.
+	finishLabel, nextLabel string
+
+	// The index of the button which should receive focus when this primitive
+	// receives focus, -1 for the current step's primitive.
+	focusButton int
+
+	// We keep a reference to the function which allows us to set the focus to
+	// a newly visible step.
+	setFocus func(p Primitive)
+
+	// The color of the header's title and progress text.
+	titleColor, progressColor tcell.Color
+
+	// An optional function which is called when the current step changes.
+	changed func(index int, title string)
+
+	// An optional function which is called when the user finished the last
+	// step.
+	finished func()
+
+	// An optional function which is called when the user cancelled the wizard.
+	cancelled func()
+
+	// An optional function which is called when the user leaves the button
+	// row with the Escape key.
+	done func(tcell.Key)
+}
+
+// NewWizard returns a new wizard without any steps.
+func NewWizard() *Wizard {
+	styles := Styles.forWidget("Wizard")
+	w := &Wizard{
+		Box:           NewBox(),
+		currentStep:   -1,
+		focusButton:   -1,
+		nextLabel:     "Next",
+		finishLabel:   "Finish",
+		titleColor:    styles.TitleColor,
+		progressColor: styles.SecondaryTextColor,
+	}
+	w.backButton = NewButton("Back").SetSelectedFunc(func() {
+		w.Back()
+	})
+	w.nextButton = NewButton("Next").SetSelectedFunc(func() {
+		w.Next()
+	})
+	w.cancelButton = NewButton("Cancel").SetSelectedFunc(func() {
+		if w.cancelled != nil {
+			w.cancelled()
+		}
+	})
+	for index, button := range w.buttons() {
+		func(index int) {
+			button.SetBlurFunc(func(key tcell.Key) {
+				w.blurButton(index, key)
+			})
+		}(index)
+	}
+	w.focus = w
+	return w
+}
+
+// buttons returns the wizard's buttons in the order in which they appear.
+func (w *Wizard) buttons() []*Button {
+	return []*Button{w.backButton, w.nextButton, w.cancelButton}
+}
+
+// SetButtonLabels sets the labels of the wizard's buttons. The "next" label
+// is used for the "Next" button on all but the last step, the "finish" label
+// on the last step.
+func (w *Wizard) SetButtonLabels(back, next, finish, cancel string) *Wizard {
+	w.backButton.SetLabel(back)
+	w.cancelButton.SetLabel(cancel)
+	w.nextLabel, w.finishLabel = next, finish
+	return w
+}
+
+// SetTitleColor sets the color of the step title in the header.
+func (w *Wizard) SetTitleColor(color tcell.Color) *Wizard {
+	w.titleColor = color
+	return w
+}
+
+// SetProgressColor sets the color of the progress text in the header.
+func (w *Wizard) SetProgressColor(color tcell.Color) *Wizard {
+	w.progressColor = color
+	return w
+}
+
+// SetChangedFunc sets a handler which is called whenever the current step
+// changes. It receives the index and the title of the new current step.
+func (w *Wizard) SetChangedFunc(handler func(index int, title string)) *Wizard {
+	w.changed = handler
+	return w
+}
+
+// SetFinishedFunc sets a handler which is called when the user pressed the
+// "Finish" button on the last step and the step's validation succeeded.
+func (w *Wizard) SetFinishedFunc(handler func()) *Wizard {
+	w.finished = handler
+	return w
+}
+
+// SetCancelFunc sets a handler which is called when the user pressed the
+// "Cancel" button.
+func (w *Wizard) SetCancelFunc(handler func()) *Wizard {
+	w.cancelled = handler
+	return w
+}
+
+// SetDoneFunc sets a handler which is called when the user leaves the button
+// row with the Escape key. The callback function is provided with the key that
+// was pressed (KeyEscape).
+func (w *Wizard) SetDoneFunc(handler func(key tcell.Key)) *Wizard {
+	w.done = handler
+	return w
+}
+
+// AddStep adds a new step with the given title and primitive at the end of the
+// wizard. The title may contain color tags. If "validate" is not nil, it is
+// called when the user wants to proceed from this step. The user may only
+// proceed if it returns true. The validation function is also a good place to
+// show error messages to the user.
+//
+// The first step added becomes the current step.
+func (w *Wizard) AddStep(title string, item Primitive, validate func() bool) *Wizard {
+	w.steps = append(w.steps, &wizardStep{Title: title, Item: item, Validate: validate})
+	if w.currentStep < 0 {
+		w.setCurrentStep(0)
+	}
+	return w
+}
+
+// GetStepCount returns the number of steps.
+func (w *Wizard) GetStepCount() int {
+	return len(w.steps)
+}
+
+// GetCurrentStep returns the index and the title of the current step. If there
+// are no steps, -1 and an empty string are returned.
+func (w *Wizard) GetCurrentStep() (index int, title string) {
+	if w.currentStep < 0 {
+		return -1, ""
+	}
+	return w.currentStep, w.steps[w.currentStep].Title
+}
+
+// SetCurrentStep makes the step with the given index the current step,
+// without calling any validation functions. If the wizard has focus, the focus
+// is moved to the new step's primitive.
+func (w *Wizard) SetCurrentStep(index int) *Wizard {
+	if index >= 0 && index < len(w.steps) {
+		w.switchToStep(index)
+	}
+	return w
+}
+
+// Next validates the current step and, if successful, proceeds to the next
+// step. On the last step, the "finished" handler is called instead. Returns
+// false if the validation failed.
+func (w *Wizard) Next() bool {
+	if w.currentStep < 0 {
+		return false
+	}
+	if validate := w.steps[w.currentStep].Validate; validate != nil && !validate() {
+		return false
+	}
+	if w.currentStep == len(w.steps)-1 {
+		if w.finished != nil {
+			w.finished()
+		}
+		return true
+	}
+	w.switchToStep(w.currentStep + 1)
+	return true
+}
+
+// Back returns to the previous step, if there is one.
+func (w *Wizard) Back() *Wizard {
+	if w.currentStep > 0 {
+		w.switchToStep(w.currentStep - 1)
+	}
+	return w
+}
+
+// FocusButtons causes the button row to receive focus the next time this
+// primitive receives focus. If it already has focus, the button row receives
+// focus immediately. The "Next" button is focused first.
+func (w *Wizard) FocusButtons() *Wizard {
+	w.focusButton = 1
+	if w.setFocus != nil && w.HasFocus() {
+		w.setFocus(w)
+	}
+	return w
+}
+
+// switchToStep makes the step with the given index the current step and moves
+// the focus to its primitive if we have focus.
+func (w *Wizard) switchToStep(index int) {
+	if index == w.currentStep {
+		return
+	}
+	hasFocus := w.HasFocus()
+	w.setCurrentStep(index)
+	if hasFocus && w.setFocus != nil {
+		w.focusButton = -1
+		w.setFocus(w)
+	}
+}
+
+// setCurrentStep sets the current step's index and invokes the "changed"
+// callback.
+func (w *Wizard) setCurrentStep(index int) {
+	w.currentStep = index
+	if w.changed != nil {
+		w.changed(w.GetCurrentStep())
+	}
+}
+
+// blurButton is called when the user leaves the button with the given index
+// (0 = "Back", 1 = "Next", 2 = "Cancel") with the given key.
+func (w *Wizard) blurButton(index int, key tcell.Key) {
+	if w.setFocus == nil {
+		return
+	}
+	switch key {
+	case tcell.KeyTab:
+		index++
+		if index == 0 && w.currentStep <= 0 {
+			index++ // Skip the invisible "Back" button.
+		}
+	case tcell.KeyBacktab:
+		index--
+		if index == 0 && w.currentStep <= 0 {
+			index--
+		}
+	case tcell.KeyEscape:
+		if w.done != nil {
+			w.done(key)
+		}
+		return
+	}
+	if index < 0 || index > 2 {
+		index = -1 // Back to the step's primitive.
+	}
+	w.focusButton = index
+	w.setFocus(w)
+}
+
+// GetChildren returns the primitives of all steps followed by the wizard's buttons.
+func (w *Wizard) GetChildren() []Primitive {
+	var children []Primitive
+	for _, step := range w.steps {
+		if step.Item != nil {
+			children = append(children, step.Item)
+		}
+	}
+	for _, button := range w.buttons() {
+		children = append(children, button)
+	}
+	return children
+}
+
+// applyTheme is called when the application's theme changes.
+func (w *Wizard) applyTheme(from, to *Theme) {
+	w.Box.applyTheme(from, to)
+	themeColor(&w.titleColor, from.TitleColor, to.TitleColor)
+	themeColor(&w.progressColor, from.SecondaryTextColor, to.SecondaryTextColor)
+}
+
+// Draw draws this primitive onto the screen.
+func (w *Wizard) Draw(screen tcell.Screen) {
+	w.Box.Draw(screen)
+
+	x, y, width, height := w.GetInnerRect()
+	if width <= 0 || height <= 0 || w.currentStep < 0 {
+		return
+	}
+	step := w.steps[w.currentStep]
+
+	// Draw the header.
+	progress := fmt.Sprintf("Step %d of %d", w.currentStep+1, len(w.steps))
+	_, progressWidth := Print(screen, progress, x, y, width, AlignLeft, w.progressColor)
+	if progressWidth+2 < width {
+		Print(screen, step.Title, x+progressWidth+2, y, width-progressWidth-2, AlignLeft, w.titleColor)
+	}
+	if height < 3 {
+		return
+	}
+
+	// Draw the buttons, aligned to the right.
+	if w.currentStep == len(w.steps)-1 {
+		w.nextButton.SetLabel(w.finishLabel)
+	} else {
+		w.nextButton.SetLabel(w.nextLabel)
+	}
+	buttons := w.buttons()
+	if w.currentStep == 0 {
+		buttons = buttons[1:]
+	}
+	buttonX := x + width
+	for index := len(buttons) - 1; index >= 0; index-- {
+		button := buttons[index]
+		buttonWidth, _ := button.GetPreferredSize()
+		buttonX -= buttonWidth
+		if buttonX < x {
+			break
+		}
+		button.SetRect(buttonX, y+height-1, buttonWidth, 1)
+		button.Draw(screen)
+		buttonX--
+	}
+
+	// Draw the current step's primitive.
+	if height > 3 {
+		step.Item.SetRect(x, y+2, width, height-4)
+		step.Item.Draw(screen)
+	}
+}
+
+// Focus is called when this primitive receives focus.
+func (w *Wizard) Focus(delegate func(p Primitive)) {
+	w.setFocus = delegate
+	if w.currentStep < 0 {
+		w.Box.Focus(delegate)
+		return
+	}
+	if w.focusButton == 0 && w.currentStep == 0 {
+		w.focusButton = 1 // The "Back" button is not visible.
+	}
+	if w.focusButton >= 0 {
+		button := w.buttons()[w.focusButton]
+		w.focusButton = -1
+		delegate(button)
+		return
+	}
+	delegate(w.steps[w.currentStep].Item)
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (w *Wizard) HasFocus() bool {
+	if w.hasFocus {
+		return true
+	}
+	for _, button := range w.buttons() {
+		if button.GetFocusable().HasFocus() {
+			return true
+		}
+	}
+	for _, step := range w.steps {
+		if step.Item.GetFocusable().HasFocus() {
+			return true
+		}
+	}
+	return false
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (w *Wizard) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return w.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if !w.InRect(event.Position()) || w.currentStep < 0 {
+			return false, nil
+		}
+
+		// Clicks on the buttons press them.
+		buttons := w.buttons()
+		if w.currentStep == 0 {
+			buttons = buttons[1:]
+		}
+		for _, button := range buttons {
+			if button.InRect(event.Position()) {
+				if action == MouseLeftClick {
+					setFocus(button)
+					if button.selected != nil {
+						button.selected()
+					}
+				}
+				return true, nil
+			}
+		}
+
+		// Other events go to the current step's primitive.
+		if handler := mouseHandler(w.steps[w.currentStep].Item); handler != nil {
+			if consumed, capture = handler(action, event, setFocus); consumed {
+				return
+			}
+		}
+		return true, nil
+	})
+}